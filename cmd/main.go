@@ -2,20 +2,64 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"net/http"
 	"os"
+	"time"
 
 	"github.com/financial_tracer/internal/config"
+	"github.com/financial_tracer/internal/domain"
 	"github.com/financial_tracer/internal/handlers"
+	accessTokenHandlers "github.com/financial_tracer/internal/handlers/accesstoken"
+	adminHandlers "github.com/financial_tracer/internal/handlers/admin"
+	"github.com/financial_tracer/internal/handlers/api"
 	categoryHandlers "github.com/financial_tracer/internal/handlers/categories"
+	comparisonHandlers "github.com/financial_tracer/internal/handlers/comparison"
+	exportHandlers "github.com/financial_tracer/internal/handlers/export"
+	forecastHandlers "github.com/financial_tracer/internal/handlers/forecast"
+	householdHandlers "github.com/financial_tracer/internal/handlers/household"
+	inviteHandlers "github.com/financial_tracer/internal/handlers/invite"
+	jwksHandlers "github.com/financial_tracer/internal/handlers/jwks"
+	ledgerHandlers "github.com/financial_tracer/internal/handlers/ledger"
+	"github.com/financial_tracer/internal/handlers/middlewares"
+	passwordHandlers "github.com/financial_tracer/internal/handlers/password"
+	profileHandlers "github.com/financial_tracer/internal/handlers/profile"
+	searchHandlers "github.com/financial_tracer/internal/handlers/search"
+	securityHandlers "github.com/financial_tracer/internal/handlers/security"
+	ssoHandlers "github.com/financial_tracer/internal/handlers/sso"
 	transactionHandlers "github.com/financial_tracer/internal/handlers/transaction"
+	twofactorHandlers "github.com/financial_tracer/internal/handlers/twofactor"
 	userHandlers "github.com/financial_tracer/internal/handlers/user"
+	verificationHandlers "github.com/financial_tracer/internal/handlers/verification"
 	"github.com/financial_tracer/internal/infastructure/cash"
 	"github.com/financial_tracer/internal/infastructure/db/postgresql"
+	"github.com/financial_tracer/internal/infastructure/files"
+	"github.com/financial_tracer/internal/infastructure/mail"
+	"github.com/financial_tracer/internal/lib/hashPassword"
+	jwttoken "github.com/financial_tracer/internal/lib/jwtToken"
+	"github.com/financial_tracer/internal/lib/oidc"
+	"github.com/financial_tracer/internal/lib/passwordPolicy"
+	"github.com/financial_tracer/internal/servic/accesstoken"
+	"github.com/financial_tracer/internal/servic/admin"
 	"github.com/financial_tracer/internal/servic/category"
+	"github.com/financial_tracer/internal/servic/comparison"
+	"github.com/financial_tracer/internal/servic/deletion"
+	"github.com/financial_tracer/internal/servic/export"
+	"github.com/financial_tracer/internal/servic/forecast"
+	"github.com/financial_tracer/internal/servic/household"
+	"github.com/financial_tracer/internal/servic/invite"
+	"github.com/financial_tracer/internal/servic/ledger"
+	"github.com/financial_tracer/internal/servic/lockout"
+	"github.com/financial_tracer/internal/servic/password"
+	"github.com/financial_tracer/internal/servic/profile"
+	"github.com/financial_tracer/internal/servic/search"
+	"github.com/financial_tracer/internal/servic/security"
+	"github.com/financial_tracer/internal/servic/sso"
 	"github.com/financial_tracer/internal/servic/transaction"
+	"github.com/financial_tracer/internal/servic/twofactor"
 	"github.com/financial_tracer/internal/servic/user"
+	"github.com/financial_tracer/internal/servic/verification"
 	"github.com/sirupsen/logrus"
 )
 
@@ -28,16 +72,118 @@ func main() {
 		log.Fatal(err)
 	}
 
+	promoted, err := db.PromoteAdmins(context.Background(), cfg.Admin.Emails)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if promoted > 0 {
+		log.WithField("admins", promoted).Info("admin role given to configured users")
+	}
+
 	red := cash.CreateRealRedis(*cfg)
 	ctx := context.Background()
 
-	users := user.CreateUserServer(db, db, db, cfg.App.SercretKey, log)
-	handlersUser := userHandlers.CreateHandlersUser(cfg.App.SercretKey, users, users, users, log, ctx)
+	keys, err := NewKeySet(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	mailer, err := NewMailer(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	cookies, err := NewCookies(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := hashPassword.SetParams(hashPassword.Params{
+		Memory:      cfg.Password.Argon2.Memory,
+		Iterations:  cfg.Password.Argon2.Iterations,
+		Parallelism: cfg.Password.Argon2.Parallelism,
+	}); err != nil {
+		log.Fatal(err)
+	}
+
+	policy, err := NewRegistration(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	passwordRules, err := NewPasswordPolicy(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	verifications := verification.CreateVerificationServer(db, mailer, verification.Options{TTL: cfg.Verify.TTL, URL: cfg.Verify.URL}, log)
+	handlersVerification := verificationHandlers.CreateVerificationHandlers(verifications, verifications, log, ctx)
+	securities := security.CreateSecurityServer(db, db, mailer, log, time.Now)
+	handlersSecurity := securityHandlers.CreateSecurityHandlers(securities, log, ctx)
+	twoFactors := twofactor.CreateTwoFactorServer(db, db, securities, cfg.TwoFactor.Issuer, log, time.Now)
+	attempts := cash.CreateFallbackAttempts(&red, cash.CreateMemoryAttempts(time.Now), log)
+	lockouts := lockout.CreateLockout(attempts, lockout.Options{
+		MaxAttempts:   cfg.Lockout.MaxAttempts,
+		MaxAttemptsIP: cfg.Lockout.MaxAttemptsIP,
+		BaseLock:      cfg.Lockout.BaseLock,
+		MaxLock:       cfg.Lockout.MaxLock,
+		Window:        cfg.Lockout.Window,
+	}, log)
+	exportDir := cfg.Export.Dir
+	if exportDir == "" {
+		exportDir = "exports"
+	}
+	archives, err := files.CreateDir(exportDir)
+	if err != nil {
+		log.Fatal(err)
+	}
+	deletions := deletion.CreateDeletionServer(db, mailer, &red, archives, deletion.Options{Grace: cfg.Deletion.Grace, Interval: cfg.Deletion.Interval}, log, time.Now)
+	go deletions.Run(ctx)
+	users := user.CreateUserServer(db, db, db, verifications, db, twoFactors, lockouts, securities, policy, passwordRules, keys, log)
+	handlersUser := userHandlers.CreateHandlersUser(users, users, deletions, users, users, users, log, ctx)
 	categories := category.CreateCategoryServer(db, db, db, db, db, log, &red)
 	handlersCategory := categoryHandlers.CreateHandlersCategory(categories, categories, categories, categories, categories, log, ctx)
-	transactions := transaction.CreateTransactionServer(db, db, db, db, log, &red)
-	handlersTransaction := transactionHandlers.CreateTransactionHandlers(transactions, transactions, transactions, transactions, log, ctx)
-	r := handlers.Router(handlersUser, handlersCategory, log, handlersTransaction, cfg.App.SercretKey)
+	transactions := transaction.CreateTransactionServer(db, db, db, db, db, log, &red)
+	handlersTransaction := transactionHandlers.CreateTransactionHandlers(transactions, transactions, transactions, transactions, transactions, log, ctx)
+	ledgers := ledger.CreateLedgerServer(db, db, log)
+	handlersLedger := ledgerHandlers.CreateLedgerHandlers(ledgers, ledgers, log, ctx)
+	forecasts := forecast.CreateForecastServer(db, db, log, time.Now)
+	handlersForecast := forecastHandlers.CreateForecastHandlers(forecasts, log, ctx)
+	searches := search.CreateSearchServer(db, log)
+	handlersSearch := searchHandlers.CreateSearchHandlers(searches, log, ctx)
+	comparisons := comparison.CreateComparisonServer(db, log)
+	handlersComparison := comparisonHandlers.CreateComparisonHandlers(comparisons, log, ctx)
+	passwords := password.CreatePasswordServer(db, db, mailer, securities, password.Options{ResetTTL: cfg.Password.ResetTTL, ResetURL: cfg.Password.ResetURL, Policy: passwordRules}, log)
+	handlersPassword := passwordHandlers.CreatePasswordHandlers(passwords, passwords, passwords, log, ctx)
+	handlersTwoFactor := twofactorHandlers.CreateTwoFactorHandlers(twoFactors, twoFactors, users, log, ctx)
+	profiles := profile.CreateProfileServer(db, db, verifications, log)
+	handlersProfile := profileHandlers.CreateProfileHandlers(profiles, profiles, log, ctx)
+	exportSecret := cfg.Export.Secret
+	if exportSecret == "" {
+		exportSecret = cfg.App.SercretKey
+	}
+	exports, err := export.CreateExportServer(db, db, archives, export.Options{TTL: cfg.Export.TTL, URL: cfg.Export.URL, Secret: exportSecret}, log, time.Now)
+	if err != nil {
+		log.Fatal(err)
+	}
+	handlersExport := exportHandlers.CreateExportHandlers(exports, exports, log, ctx)
+	accessTokens := accesstoken.CreateAccessTokenServer(db, log, time.Now)
+	handlersAccessToken := accessTokenHandlers.CreateAccessTokenHandlers(accessTokens, log, ctx)
+	admins := admin.CreateAdminServer(db, log)
+	handlersAdmin := adminHandlers.CreateAdminHandlers(admins, log, ctx)
+	households := household.CreateHouseholdServer(db, db, mailer, household.Options{TTL: cfg.Household.InviteTTL, URL: cfg.Household.InviteURL}, log, time.Now)
+	handlersHousehold := householdHandlers.CreateHouseholdHandlers(households, log, ctx)
+	invites := invite.CreateInviteServer(db, log, time.Now)
+	handlersInvite := inviteHandlers.CreateInviteHandlers(invites, log, ctx)
+	ssos := sso.CreateSSOServer(NewProviders(cfg), db, db, users, policy, log, time.Now)
+	handlersSSO := ssoHandlers.CreateSSOHandlers(ssos, log, ctx)
+	handlersJWKS := jwksHandlers.CreateJWKSHandlers(keys, log)
+	r := handlers.Router(handlersUser, handlersCategory, log, handlersTransaction, handlersLedger, handlersForecast, handlersSearch, handlersComparison, handlersPassword, handlersVerification, handlersTwoFactor, handlersProfile, handlersExport, handlersAccessToken, handlersAdmin, handlersHousehold, handlersSSO, handlersSecurity, handlersInvite, middlewares.CORS{Origins: cfg.CORS.Origins, Credentials: cfg.CORS.Credentials}, middlewares.Cookies(cfg.Session.Cookies, cookies), db, accessTokens, db, middlewares.Verified(db, cfg.Verify.Access, log), middlewares.Preferences(db, log), middlewares.Household(households, log), handlersJWKS, keys)
+
+	r.ForwardedByClientIP = len(cfg.Server.TrustedProxies) > 0
+	if err := r.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		log.Fatalf("invalid trusted proxies: %s\n", err)
+	}
 
 	srv := &http.Server{
 		Addr:         ":8080",
@@ -79,3 +225,142 @@ func NewLogger(cfg string) *logrus.Logger {
 
 	return log
 }
+
+func NewKeySet(cfg *config.Config) (*jwttoken.KeySet, error) {
+	var signing jwttoken.Key
+	var err error
+
+	switch cfg.JWT.Algorithm {
+	case "", jwttoken.AlgHS256:
+		signing, err = jwttoken.HMACKey(cfg.JWT.KeyID, cfg.App.SercretKey)
+	default:
+		var data []byte
+		data, err = os.ReadFile(cfg.JWT.PrivateKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("error read private key: %w", err)
+		}
+		signing, err = jwttoken.PrivateKeyFromPEM(cfg.JWT.KeyID, cfg.JWT.Algorithm, data)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error signing key: %w", err)
+	}
+
+	previous, err := jwttoken.HMACKeys(cfg.JWT.PreviousKeys)
+	if err != nil {
+		return nil, fmt.Errorf("error previous keys: %w", err)
+	}
+
+	for kid, file := range cfg.JWT.PreviousPublicKeyFiles {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("error read public key: %w", err)
+		}
+		key, err := jwttoken.PublicKeyFromPEM(kid, data)
+		if err != nil {
+			return nil, fmt.Errorf("error previous keys: %w", err)
+		}
+		previous = append(previous, key)
+	}
+
+	return jwttoken.NewKeySet(cfg.JWT.Issuer, cfg.JWT.Audience, signing, previous...)
+}
+
+// NewProviders returns the configured OpenID Connect providers by name.
+func NewProviders(cfg *config.Config) map[string]sso.Provider {
+	providers := make(map[string]sso.Provider, len(cfg.OIDC.Providers))
+	for name, p := range cfg.OIDC.Providers {
+		providers[name] = oidc.NewProvider(oidc.Config{
+			Issuer:       p.Issuer,
+			ClientID:     p.ClientID,
+			ClientSecret: p.ClientSecret,
+			RedirectURL:  p.RedirectURL,
+			Scopes:       p.Scopes,
+		}, nil, time.Now)
+	}
+
+	return providers
+}
+
+// NewCookies returns the settings of the session cookies of the cookie session mode.
+func NewCookies(cfg *config.Config) (api.Cookies, error) {
+	cookies := api.Cookies{
+		Domain:      cfg.Session.Domain,
+		Path:        handlers.BasePath,
+		RefreshPath: handlers.BasePath + "/registration",
+		Secure:      !cfg.Session.Insecure,
+	}
+
+	switch cfg.Session.SameSite {
+	case "", "strict":
+		cookies.SameSite = http.SameSiteStrictMode
+	case "lax":
+		cookies.SameSite = http.SameSiteLaxMode
+	case "none":
+		if !cookies.Secure {
+			return api.Cookies{}, fmt.Errorf("session sameSite none requires secure cookies")
+		}
+		cookies.SameSite = http.SameSiteNoneMode
+	default:
+		return api.Cookies{}, fmt.Errorf("unknown session sameSite %q", cfg.Session.SameSite)
+	}
+
+	return cookies, nil
+}
+
+// NewRegistration returns who can sign up, the configured admins always can.
+func NewRegistration(cfg *config.Config) (domain.RegistrationPolicy, error) {
+	switch cfg.Registration.Mode {
+	case "", domain.RegistrationOpen, domain.RegistrationInvite, domain.RegistrationClosed:
+	default:
+		return domain.RegistrationPolicy{}, fmt.Errorf("unknown registration mode %q", cfg.Registration.Mode)
+	}
+
+	return domain.RegistrationPolicy{
+		Mode:    cfg.Registration.Mode,
+		Domains: cfg.Registration.Domains,
+		Admins:  cfg.Admin.Emails,
+	}, nil
+}
+
+// NewPasswordPolicy returns the rules for new passwords.
+func NewPasswordPolicy(cfg *config.Config) (passwordPolicy.Policy, error) {
+	p := cfg.Password.Policy
+	minLength := p.MinLength
+	if minLength == 0 {
+		minLength = passwordPolicy.DefaultPolicy.MinLength
+	}
+	if p.MinLength < 0 || p.MaxLength < 0 || (p.MaxLength != 0 && p.MaxLength < minLength) {
+		return passwordPolicy.Policy{}, fmt.Errorf("invalid password length %d-%d", minLength, p.MaxLength)
+	}
+	if p.MinClasses < 0 || p.MinClasses > 4 {
+		return passwordPolicy.Policy{}, fmt.Errorf("password minClasses must be from 0 to 4")
+	}
+
+	policy := passwordPolicy.Policy{
+		MinLength:     p.MinLength,
+		MaxLength:     p.MaxLength,
+		MinClasses:    p.MinClasses,
+		AllowPersonal: p.AllowPersonal,
+	}
+
+	if p.BreachedDir != "" {
+		list, err := passwordPolicy.OpenBreachedList(p.BreachedDir)
+		if err != nil {
+			return passwordPolicy.Policy{}, err
+		}
+		policy.Breached = list
+	}
+
+	return policy, nil
+}
+
+func NewMailer(cfg *config.Config) (password.Mailer, error) {
+	switch cfg.Mail.Driver {
+	case "smtp":
+		return mail.CreateSMTPMailer(*cfg), nil
+	case "", "file":
+		return mail.CreateFileMailer(*cfg)
+	default:
+		return nil, fmt.Errorf("unknown mail driver %q", cfg.Mail.Driver)
+	}
+}
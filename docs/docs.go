@@ -22,56 +22,36 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/category/": {
-            "put": {
+        "/admin/invites": {
+            "get": {
                 "security": [
                     {
                         "jwtAuth": []
                     }
                 ],
-                "description": "Обновление всех характеристики категории",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Неотозванные коды приглашения от новых к старым с количеством использований, включая истекшие и исчерпанные",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "categories"
-                ],
-                "summary": "обновление пользователя",
-                "parameters": [
-                    {
-                        "description": "данные для обновление категории",
-                        "name": "req",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/categoryHandlers.RequestUpdateCategory"
-                        }
-                    }
+                    "Admin"
                 ],
+                "summary": "Коды приглашения",
                 "responses": {
                     "200": {
-                        "description": "success",
+                        "description": "Коды приглашения",
                         "schema": {
                             "$ref": "#/definitions/api.SuccessResponse"
                         }
                     },
-                    "400": {
-                        "description": "Некорректный данные",
-                        "schema": {
-                            "$ref": "#/definitions/api.ErrorResponse"
-                        }
-                    },
                     "401": {
                         "description": "Ошибка авторизации",
                         "schema": {
                             "$ref": "#/definitions/api.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Категория не найдена",
+                    "403": {
+                        "description": "Нужна роль admin",
                         "schema": {
                             "$ref": "#/definitions/api.ErrorResponse"
                         }
@@ -90,7 +70,7 @@ const docTemplate = `{
                         "jwtAuth": []
                     }
                 ],
-                "description": "Создание новой категории для зарегистрировшегося пользователя",
+                "description": "Код приглашения для регистрации в режиме invite. Код возвращается один раз, хранится только его хеш",
                 "consumes": [
                     "application/json"
                 ],
@@ -98,29 +78,29 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "categories"
+                    "Admin"
                 ],
-                "summary": "Создание Категории",
+                "summary": "Создание кода приглашения",
                 "parameters": [
                     {
-                        "description": "данные для создание новой категории",
+                        "description": "параметры кода",
                         "name": "req",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/categoryHandlers.RequestCreateCategory"
+                            "$ref": "#/definitions/inviteHandlers.RequestInvite"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Успешное создание категории",
+                        "description": "Код приглашения",
                         "schema": {
                             "$ref": "#/definitions/api.SuccessResponse"
                         }
                     },
                     "400": {
-                        "description": "Некорректный данные",
+                        "description": "Некорректные входные данные",
                         "schema": {
                             "$ref": "#/definitions/api.ErrorResponse"
                         }
@@ -131,7 +111,13 @@ const docTemplate = `{
                             "$ref": "#/definitions/api.ErrorResponse"
                         }
                     },
-                    "501": {
+                    "403": {
+                        "description": "Нужна роль admin",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
                         "description": "Ошибка сервера",
                         "schema": {
                             "$ref": "#/definitions/api.ErrorResponse"
@@ -140,45 +126,57 @@ const docTemplate = `{
                 }
             }
         },
-        "/category/type/{type}": {
-            "get": {
+        "/admin/invites/{id}": {
+            "delete": {
                 "security": [
                     {
                         "jwtAuth": []
                     }
                 ],
-                "description": "получение категории или категорий по определенномк типу, который передается в URL пути",
+                "description": "Отозванный код больше нельзя использовать для регистрации",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "categories"
+                    "Admin"
                 ],
-                "summary": "получение категории или категорий по типу",
+                "summary": "Отзыв кода приглашения",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "тип, по которому будут выбиратся категории или категория",
-                        "name": "type",
+                        "type": "integer",
+                        "description": "id кода приглашения",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "success",
+                        "description": "Код отозван",
                         "schema": {
                             "$ref": "#/definitions/api.SuccessResponse"
                         }
                     },
                     "400": {
-                        "description": "Некорректные данные",
+                        "description": "Некорректные входные данные",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Нужна роль admin",
                         "schema": {
                             "$ref": "#/definitions/api.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "Категория не найдена",
+                        "description": "Код не найден",
                         "schema": {
                             "$ref": "#/definitions/api.ErrorResponse"
                         }
@@ -192,39 +190,76 @@ const docTemplate = `{
                 }
             }
         },
-        "/category/{id}": {
+        "/admin/security-events": {
             "get": {
                 "security": [
                     {
                         "jwtAuth": []
                     }
                 ],
-                "description": "Получение категории для конкретного пользователя",
+                "description": "События безопасности всех пользователей или одного от новых к старым. Неудачные входы с неизвестным email имеют user_id 0 и email, с которым был вход",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "categories"
+                    "Admin"
                 ],
-                "summary": "Получение категории",
+                "summary": "События безопасности пользователей",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID категории",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "id пользователя",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "тип: login, refresh, password_change, password_reset, two_factor_enable, two_factor_disable",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "успешно ли действие",
+                        "name": "success",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "с даты (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "по дату включительно (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "количество событий",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "смещение",
+                        "name": "offset",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "success",
+                        "description": "События",
                         "schema": {
                             "$ref": "#/definitions/api.SuccessResponse"
                         }
                     },
                     "400": {
-                        "description": "Некорректный данные",
+                        "description": "Некорректные входные данные",
                         "schema": {
                             "$ref": "#/definitions/api.ErrorResponse"
                         }
@@ -235,8 +270,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/api.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Категория не найдена",
+                    "403": {
+                        "description": "Нужна роль admin",
                         "schema": {
                             "$ref": "#/definitions/api.ErrorResponse"
                         }
@@ -248,39 +283,66 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/admin/users": {
+            "get": {
                 "security": [
                     {
                         "jwtAuth": []
                     }
                 ],
-                "description": "Удаление категории конкретного пользователя",
+                "description": "Пользователи от новых к старым с поиском по части имени или email, фильтрами по роли и блокировке и общим количеством найденных",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "categories"
+                    "Admin"
                 ],
-                "summary": "Удаление категории",
+                "summary": "Поиск пользователей",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "часть имени или email",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "роль: user, admin",
+                        "name": "role",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "заблокирован ли аккаунт",
+                        "name": "disabled",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
-                        "description": "ID категории",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "default": 20,
+                        "description": "количество пользователей",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "смещение",
+                        "name": "offset",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "success",
+                        "description": "Пользователи",
                         "schema": {
                             "$ref": "#/definitions/api.SuccessResponse"
                         }
                     },
                     "400": {
-                        "description": "Некорректные данные",
+                        "description": "Некорректные входные данные",
                         "schema": {
                             "$ref": "#/definitions/api.ErrorResponse"
                         }
@@ -291,8 +353,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/api.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Категория не найдена",
+                    "403": {
+                        "description": "Нужна роль admin",
                         "schema": {
                             "$ref": "#/definitions/api.ErrorResponse"
                         }
@@ -306,33 +368,33 @@ const docTemplate = `{
                 }
             }
         },
-        "/registration/get_access_token": {
-            "post": {
-                "description": "Получение токена",
-                "consumes": [
-                    "application/json"
+        "/admin/users/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "jwtAuth": []
+                    }
                 ],
+                "description": "Пользователь с количеством его категорий, транзакций, активных сессий, токенов доступа и экспортов",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "registration"
+                    "Admin"
                 ],
-                "summary": "Получение токена",
+                "summary": "Пользователь",
                 "parameters": [
                     {
-                        "description": "для получение access токена",
-                        "name": "req",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/userHandlers.RefreshToken"
-                        }
+                        "type": "integer",
+                        "description": "id пользователя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Получение access токена",
+                        "description": "Пользователь",
                         "schema": {
                             "$ref": "#/definitions/api.SuccessResponse"
                         }
@@ -343,48 +405,14 @@ const docTemplate = `{
                             "$ref": "#/definitions/api.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Ошибка сервера",
+                    "401": {
+                        "description": "Ошибка авторизации",
                         "schema": {
                             "$ref": "#/definitions/api.ErrorResponse"
                         }
-                    }
-                }
-            }
-        },
-        "/registration/login": {
-            "post": {
-                "description": "Вход пользователя в систему",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "registration"
-                ],
-                "summary": "Аутентификация пользователя",
-                "parameters": [
-                    {
-                        "description": "Данные для авторизации пользователя",
-                        "name": "credentials",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/userHandlers.UserRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "Авторизация пользователя",
-                        "schema": {
-                            "$ref": "#/definitions/api.SuccessResponse"
-                        }
                     },
-                    "400": {
-                        "description": "Некорректные данные",
+                    "403": {
+                        "description": "Нужна роль admin",
                         "schema": {
                             "$ref": "#/definitions/api.ErrorResponse"
                         }
@@ -404,39 +432,57 @@ const docTemplate = `{
                 }
             }
         },
-        "/registration/register": {
+        "/admin/users/{id}/disable": {
             "post": {
-                "description": "Создание нового пользователя",
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "jwtAuth": []
+                    }
                 ],
+                "description": "Блокировка аккаунта: пользователь не может войти, его сессии завершаются, токены доступа перестают действовать",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "registration"
+                    "Admin"
                 ],
-                "summary": "Регистрация пользователя",
+                "summary": "Блокировка пользователя",
                 "parameters": [
                     {
-                        "description": "Данные для регистрации пользователя",
-                        "name": "user",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/userHandlers.UserRegistration"
-                        }
+                        "type": "integer",
+                        "description": "id пользователя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Регистрация пользователя",
+                        "description": "Пользователь заблокирован",
                         "schema": {
                             "$ref": "#/definitions/api.SuccessResponse"
                         }
                     },
                     "400": {
-                        "description": "Некорректные данные",
+                        "description": "Некорректные входные данные",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Нужна роль admin",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь не найден",
                         "schema": {
                             "$ref": "#/definitions/api.ErrorResponse"
                         }
@@ -450,44 +496,39 @@ const docTemplate = `{
                 }
             }
         },
-        "/transaction/": {
-            "put": {
+        "/admin/users/{id}/enable": {
+            "post": {
                 "security": [
                     {
                         "jwtAuth": []
                     }
                 ],
-                "description": "Обновление 1 транзакции для 1 пользователя",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Разблокировка аккаунта, завершенные при блокировке сессии не восстанавливаются",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "transaction"
+                    "Admin"
                 ],
-                "summary": "Обновление транзакции",
+                "summary": "Разблокировка пользователя",
                 "parameters": [
                     {
-                        "description": "Данные для обновление пользователя",
-                        "name": "req",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/transactionHandlers.RequestUpdateTransaction"
-                        }
+                        "type": "integer",
+                        "description": "id пользователя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Транзакция обновлена",
+                        "description": "Пользователь разблокирован",
                         "schema": {
                             "$ref": "#/definitions/api.SuccessResponse"
                         }
                     },
                     "400": {
-                        "description": "Некорректные данные",
+                        "description": "Некорректные входные данные",
                         "schema": {
                             "$ref": "#/definitions/api.ErrorResponse"
                         }
@@ -498,8 +539,14 @@ const docTemplate = `{
                             "$ref": "#/definitions/api.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Нужна роль admin",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
                     "404": {
-                        "description": "Транзакция не найдена",
+                        "description": "Пользователь не найден",
                         "schema": {
                             "$ref": "#/definitions/api.ErrorResponse"
                         }
@@ -511,7 +558,2015 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/admin/users/{id}/logout": {
+            "post": {
+                "security": [
+                    {
+                        "jwtAuth": []
+                    }
+                ],
+                "description": "Завершение всех сессий пользователя, возвращает количество завершенных. Токены доступа не отзываются",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Завершение сессий пользователя",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "id пользователя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Количество завершенных сессий",
+                        "schema": {
+                            "$ref": "#/definitions/api.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные входные данные",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Нужна роль admin",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/users/{id}/role": {
+            "put": {
+                "security": [
+                    {
+                        "jwtAuth": []
+                    }
+                ],
+                "description": "Назначение пользователю роли user или admin. Администратор не может снять роль с себя",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Изменение роли",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "id пользователя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "роль",
+                        "name": "req",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/adminHandlers.RequestRole"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Роль изменена",
+                        "schema": {
+                            "$ref": "#/definitions/api.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные входные данные",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Нужна роль admin",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/category/": {
+            "put": {
+                "security": [
+                    {
+                        "jwtAuth": []
+                    }
+                ],
+                "description": "Обновление всех характеристики категории",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "categories"
+                ],
+                "summary": "обновление пользователя",
+                "parameters": [
+                    {
+                        "description": "данные для обновление категории",
+                        "name": "req",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/categoryHandlers.RequestUpdateCategory"
+                        }
+                    },
+                    {
+                        "type": "integer",
+                        "description": "id общего бюджета, по умолчанию личный",
+                        "name": "X-Household-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "success",
+                        "schema": {
+                            "$ref": "#/definitions/api.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный данные",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Нет доступа к бюджету",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Категория не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "jwtAuth": []
+                    }
+                ],
+                "description": "Создание новой категории для зарегистрировшегося пользователя",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "categories"
+                ],
+                "summary": "Создание Категории",
+                "parameters": [
+                    {
+                        "description": "данные для создание новой категории",
+                        "name": "req",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/categoryHandlers.RequestCreateCategory"
+                        }
+                    },
+                    {
+                        "type": "integer",
+                        "description": "id общего бюджета, по умолчанию личный",
+                        "name": "X-Household-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Успешное создание категории",
+                        "schema": {
+                            "$ref": "#/definitions/api.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный данные",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Нет доступа к бюджету",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "501": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/category/type/{type}": {
+            "get": {
+                "security": [
+                    {
+                        "jwtAuth": []
+                    }
+                ],
+                "description": "получение категории или категорий по определенномк типу, который передается в URL пути",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "categories"
+                ],
+                "summary": "получение категории или категорий по типу",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "тип, по которому будут выбиратся категории или категория",
+                        "name": "type",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "id общего бюджета, по умолчанию личный",
+                        "name": "X-Household-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "success",
+                        "schema": {
+                            "$ref": "#/definitions/api.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные данные",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Нет доступа к бюджету",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Категория не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/category/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "jwtAuth": []
+                    }
+                ],
+                "description": "Получение категории для конкретного пользователя",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "categories"
+                ],
+                "summary": "Получение категории",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID категории",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "id общего бюджета, по умолчанию личный",
+                        "name": "X-Household-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "success",
+                        "schema": {
+                            "$ref": "#/definitions/api.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный данные",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Нет доступа к бюджету",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Категория не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "jwtAuth": []
+                    }
+                ],
+                "description": "Удаление категории конкретного пользователя",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "categories"
+                ],
+                "summary": "Удаление категории",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID категории",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "id общего бюджета, по умолчанию личный",
+                        "name": "X-Household-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "success",
+                        "schema": {
+                            "$ref": "#/definitions/api.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные данные",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Нет доступа к бюджету",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Категория не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/export/download": {
+            "get": {
+                "description": "Скачивание ZIP архива по подписанной ссылке из экспорта. Ссылка не требует авторизации и перестает действовать вместе с архивом",
+                "produces": [
+                    "application/zip"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Скачивание экспорта",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "id экспорта",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "время окончания действия ссылки, unix",
+                        "name": "expires",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "подпись ссылки",
+                        "name": "signature",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Архив",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные входные данные",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Неверная или просроченная ссылка",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Экспорт не найден",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/household": {
+            "get": {
+                "security": [
+                    {
+                        "jwtAuth": []
+                    }
+                ],
+                "description": "Личный и общие бюджеты, в которых состоит пользователь, с его ролью в каждом",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Household"
+                ],
+                "summary": "Бюджеты пользователя",
+                "responses": {
+                    "200": {
+                        "description": "Бюджеты",
+                        "schema": {
+                            "$ref": "#/definitions/api.SuccessResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "jwtAuth": []
+                    }
+                ],
+                "description": "Создание общего бюджета, пользователь становится его владельцем",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Household"
+                ],
+                "summary": "Создание общего бюджета",
+                "parameters": [
+                    {
+                        "description": "название бюджета",
+                        "name": "req",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/householdHandlers.RequestHousehold"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Бюджет создан",
+                        "schema": {
+                            "$ref": "#/definitions/api.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные входные данные",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/household/invitations/accept": {
+            "post": {
+                "security": [
+                    {
+                        "jwtAuth": []
+                    }
+                ],
+                "description": "Принятие приглашения по токену из письма, приглашение должно быть отправлено на email пользователя",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Household"
+                ],
+                "summary": "Принятие приглашения",
+                "parameters": [
+                    {
+                        "description": "токен приглашения",
+                        "name": "req",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/householdHandlers.RequestAccept"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Пользователь добавлен в бюджет",
+                        "schema": {
+                            "$ref": "#/definitions/api.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректное или просроченное приглашение",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/household/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "jwtAuth": []
+                    }
+                ],
+                "description": "Бюджет с участниками и их ролями, доступен только участникам",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Household"
+                ],
+                "summary": "Бюджет",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "id бюджета",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Бюджет",
+                        "schema": {
+                            "$ref": "#/definitions/api.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные входные данные",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Нет доступа к бюджету",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Бюджет не найден",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/household/{id}/invitations": {
+            "post": {
+                "security": [
+                    {
+                        "jwtAuth": []
+                    }
+                ],
+                "description": "Отправка приглашения на email с ролью editor или viewer. Приглашает только владелец, личный бюджет не делится",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Household"
+                ],
+                "summary": "Приглашение в бюджет",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "id бюджета",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "email и роль",
+                        "name": "req",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/householdHandlers.RequestInvite"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Приглашение отправлено",
+                        "schema": {
+                            "$ref": "#/definitions/api.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные входные данные",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Нужна роль owner",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Бюджет не найден",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/household/{id}/members/{user}": {
+            "put": {
+                "security": [
+                    {
+                        "jwtAuth": []
+                    }
+                ],
+                "description": "Назначение участнику роли editor или viewer, роли меняет только владелец",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Household"
+                ],
+                "summary": "Изменение роли участника",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "id бюджета",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "id участника",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "роль",
+                        "name": "req",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/householdHandlers.RequestMember"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Роль изменена",
+                        "schema": {
+                            "$ref": "#/definitions/api.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные входные данные",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Нужна роль owner",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Участник не найден",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "jwtAuth": []
+                    }
+                ],
+                "description": "Владелец удаляет участников, участник может выйти из бюджета, удалив себя",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Household"
+                ],
+                "summary": "Удаление участника",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "id бюджета",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "id участника",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Участник удален",
+                        "schema": {
+                            "$ref": "#/definitions/api.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные входные данные",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Нужна роль owner",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Участник не найден",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/journal/export": {
+            "get": {
+                "security": [
+                    {
+                        "jwtAuth": []
+                    }
+                ],
+                "description": "Экспорт категорий и транзакций пользователя в формате ledger, hledger или beancount. Категории становятся счетами Expenses",
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "journal"
+                ],
+                "summary": "Экспорт в журнал",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "ledger",
+                        "description": "формат журнала: ledger, hledger, beancount",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "id общего бюджета, по умолчанию личный",
+                        "name": "X-Household-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Журнал",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Неизвестный формат",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Нет доступа к бюджету",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/journal/import": {
+            "post": {
+                "security": [
+                    {
+                        "jwtAuth": []
+                    }
+                ],
+                "description": "Импорт категорий и транзакций из журнала ledger, hledger или beancount. Уже существующие транзакции пропускаются",
+                "consumes": [
+                    "text/plain"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "journal"
+                ],
+                "summary": "Импорт из журнала",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "ledger",
+                        "description": "формат журнала: ledger, hledger, beancount",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "description": "текст журнала",
+                        "name": "journal",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "type": "integer",
+                        "description": "id общего бюджета, по умолчанию личный",
+                        "name": "X-Household-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Результат импорта",
+                        "schema": {
+                            "$ref": "#/definitions/api.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный журнал",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Нет доступа к бюджету",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/registration/2fa": {
+            "post": {
+                "description": "Обмен challenge токена, полученного при входе, и кода из приложения на пару токенов. Challenge токен одноразовый: после неверного кода нужно войти заново",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "registration"
+                ],
+                "summary": "Вход с 2FA",
+                "parameters": [
+                    {
+                        "description": "challenge токен и код",
+                        "name": "req",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/twofactorHandlers.RequestTwoFactorLogin"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "cookie: токены выдаются в HttpOnly cookie, в ответе только csrf_token",
+                        "name": "X-Session-Mode",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Пара токенов",
+                        "schema": {
+                            "$ref": "#/definitions/api.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные входные данные",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Неверный код или challenge токен",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Слишком много неудачных попыток, см. Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        },
+                        "headers": {
+                            "Retry-After": {
+                                "type": "string",
+                                "description": "Через сколько секунд можно повторить"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/registration/2fa/recover": {
+            "post": {
+                "description": "Обмен challenge токена и кода восстановления на пару токенов. Двухфакторная аутентификация при этом отключается, ее можно подключить заново",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "registration"
+                ],
+                "summary": "Вход по коду восстановления",
+                "parameters": [
+                    {
+                        "description": "challenge токен и код восстановления",
+                        "name": "req",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/twofactorHandlers.RequestRecover"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "cookie: токены выдаются в HttpOnly cookie, в ответе только csrf_token",
+                        "name": "X-Session-Mode",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Пара токенов",
+                        "schema": {
+                            "$ref": "#/definitions/api.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные входные данные",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Неверный код или challenge токен",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Слишком много неудачных попыток, см. Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        },
+                        "headers": {
+                            "Retry-After": {
+                                "type": "string",
+                                "description": "Через сколько секунд можно повторить"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/registration/access_token": {
+            "post": {
+                "description": "Обмен refresh токена на новую пару токенов. Refresh токен одноразовый: повторное использование уже обменянного токена завершает сессию. В cookie режиме токен читается из cookie ft_refresh",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "registration"
+                ],
+                "summary": "Обновление токенов",
+                "parameters": [
+                    {
+                        "description": "refresh токен, без cookie режима",
+                        "name": "req",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/userHandlers.RefreshToken"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "cookie: refresh токен берется из cookie, токены выдаются в cookie",
+                        "name": "X-Session-Mode",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "CSRF токен, обязателен в cookie режиме",
+                        "name": "X-CSRF-Token",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Новая пара токенов",
+                        "schema": {
+                            "$ref": "#/definitions/api.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные входные данные",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Недействительный refresh токен",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Неверный CSRF токен",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/registration/login": {
+            "post": {
+                "description": "Вход пользователя в систему. При включенной 2FA вместо пары токенов возвращается challenge_token для /registration/2fa",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "registration"
+                ],
+                "summary": "Аутентификация пользователя",
+                "parameters": [
+                    {
+                        "description": "Данные для авторизации пользователя",
+                        "name": "credentials",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/userHandlers.UserRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "cookie: токены выдаются в HttpOnly cookie, в ответе только csrf_token",
+                        "name": "X-Session-Mode",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Авторизация пользователя",
+                        "schema": {
+                            "$ref": "#/definitions/api.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные данные",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Неверный email или пароль",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Слишком много неудачных попыток, см. Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        },
+                        "headers": {
+                            "Retry-After": {
+                                "type": "string",
+                                "description": "Через сколько секунд можно повторить"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/registration/oidc": {
+            "get": {
+                "description": "Имена настроенных провайдеров OpenID Connect для входа через корпоративный SSO",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "registration"
+                ],
+                "summary": "Провайдеры SSO",
+                "responses": {
+                    "200": {
+                        "description": "Провайдеры",
+                        "schema": {
+                            "$ref": "#/definitions/api.SuccessResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/registration/oidc/{provider}": {
+            "get": {
+                "description": "Начало входа через провайдера OpenID Connect (authorization code + PKCE). Возвращает url, на который нужно перенаправить пользователя",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "registration"
+                ],
+                "summary": "Вход через SSO",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "имя провайдера",
+                        "name": "provider",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "url провайдера",
+                        "schema": {
+                            "$ref": "#/definitions/api.SuccessResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Провайдер не найден",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Провайдер недоступен",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/registration/oidc/{provider}/callback": {
+            "get": {
+                "description": "Обработка возврата от провайдера: обмен кода на ID token, проверка подписи по JWKS провайдера и привязка к пользователю по подтвержденному email. Возвращает пару токенов, при включенной 2FA challenge_token для /registration/2fa",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "registration"
+                ],
+                "summary": "Завершение входа через SSO",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "имя провайдера",
+                        "name": "provider",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "код авторизации",
+                        "name": "code",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "state входа",
+                        "name": "state",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ошибка провайдера",
+                        "name": "error",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "описание ошибки провайдера",
+                        "name": "error_description",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "cookie: токены выдаются в HttpOnly cookie, в ответе только csrf_token",
+                        "name": "X-Session-Mode",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Авторизация пользователя",
+                        "schema": {
+                            "$ref": "#/definitions/api.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный или просроченный вход",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Провайдер отклонил вход",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Email не подтвержден провайдером, пользователь заблокирован или регистрация новых пользователей запрещена",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Провайдер не найден",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Email существующего пользователя не подтвержден, нужно войти по паролю и подтвердить его",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/registration/password/forgot": {
+            "post": {
+                "description": "Отправка письма со ссылкой для сброса пароля. Ответ не зависит от того, зарегистрирован ли email",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "registration"
+                ],
+                "summary": "Восстановление пароля",
+                "parameters": [
+                    {
+                        "description": "email пользователя",
+                        "name": "req",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/passwordHandlers.RequestForgotPassword"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Письмо отправлено",
+                        "schema": {
+                            "$ref": "#/definitions/api.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные входные данные",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/registration/password/reset": {
+            "post": {
+                "description": "Установка нового пароля по токену из письма. Токен одноразовый и ограничен по времени, после сброса все сессии пользователя завершаются, токены доступа отзываются",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "registration"
+                ],
+                "summary": "Сброс пароля",
+                "parameters": [
+                    {
+                        "description": "токен и новый пароль",
+                        "name": "req",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/passwordHandlers.RequestResetPassword"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Пароль изменен",
+                        "schema": {
+                            "$ref": "#/definitions/api.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный или просроченный токен",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/registration/register": {
+            "post": {
+                "description": "Создание нового пользователя. В режиме invite нужен код приглашения, в режиме closed регистрация закрыта",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "registration"
+                ],
+                "summary": "Регистрация пользователя",
+                "parameters": [
+                    {
+                        "description": "Данные для регистрации пользователя",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/userHandlers.UserRegistration"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "cookie: токены выдаются в HttpOnly cookie, в ответе только csrf_token",
+                        "name": "X-Session-Mode",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Регистрация пользователя",
+                        "schema": {
+                            "$ref": "#/definitions/api.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные данные",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Регистрация закрыта или домен email не разрешен",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/registration/verify": {
+            "post": {
+                "description": "Подтверждение email по токену из письма, отправленного при регистрации. Токен ограничен по времени",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "registration"
+                ],
+                "summary": "Подтверждение email",
+                "parameters": [
+                    {
+                        "description": "токен из письма",
+                        "name": "req",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/verificationHandlers.RequestVerifyEmail"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Email подтвержден",
+                        "schema": {
+                            "$ref": "#/definitions/api.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный или просроченный токен",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/report/compare": {
+            "get": {
+                "security": [
+                    {
+                        "jwtAuth": []
+                    }
+                ],
+                "description": "Сравнение трат по категориям за два периода: абсолютная и процентная разница, новые (new) и исчезнувшие (disappeared) категории. Даты включительно в часовом поясе пользователя, если предыдущий период не указан, берется тот же период год назад. period=week|month сравнивает текущую неделю или отчетный месяц с предыдущими по настройкам пользователя",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "report"
+                ],
+                "summary": "Сравнение периодов",
+                "parameters": [
+                    {
+                        "enum": [
+                            "week",
+                            "month"
+                        ],
+                        "type": "string",
+                        "description": "текущая неделя или месяц",
+                        "name": "period",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "2025-07-01",
+                        "description": "начало периода, без period обязательно",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "2025-09-30",
+                        "description": "конец периода, без period обязательно",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "2025-04-01",
+                        "description": "начало предыдущего периода",
+                        "name": "previous_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "2025-06-30",
+                        "description": "конец предыдущего периода",
+                        "name": "previous_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "id общего бюджета, по умолчанию личный",
+                        "name": "X-Household-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Сравнение",
+                        "schema": {
+                            "$ref": "#/definitions/api.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные входные данные",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Нет доступа к бюджету",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/report/forecast": {
+            "get": {
+                "security": [
+                    {
+                        "jwtAuth": []
+                    }
+                ],
+                "description": "Прогноз трат по категориям на конец текущего отчетного месяца (день начала месяца и часовой пояс из настроек пользователя): темп трат, среднее за прошлые месяцы и регулярные платежи. Категории, которые вероятно превысят лимит, отмечаются over_limit",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "report"
+                ],
+                "summary": "Прогноз трат",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "id общего бюджета, по умолчанию личный",
+                        "name": "X-Household-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Прогноз",
+                        "schema": {
+                            "$ref": "#/definitions/api.SuccessResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Нет доступа к бюджету",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transaction/": {
+            "put": {
+                "security": [
+                    {
+                        "jwtAuth": []
+                    }
+                ],
+                "description": "Обновление 1 транзакции для 1 пользователя",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transaction"
+                ],
+                "summary": "Обновление транзакции",
+                "parameters": [
+                    {
+                        "description": "Данные для обновление пользователя",
+                        "name": "req",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/transactionHandlers.RequestUpdateTransaction"
+                        }
+                    },
+                    {
+                        "type": "integer",
+                        "description": "id общего бюджета, по умолчанию личный",
+                        "name": "X-Household-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Транзакция обновлена",
+                        "schema": {
+                            "$ref": "#/definitions/api.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные данные",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Нет доступа к бюджету",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Транзакция не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            },
             "post": {
                 "security": [
                     {
@@ -526,31 +2581,1288 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "transaction"
+                    "transaction"
+                ],
+                "summary": "Создание транзакции",
+                "parameters": [
+                    {
+                        "description": "данные для создание пользователя",
+                        "name": "req",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/transactionHandlers.RequestCreateTransaction"
+                        }
+                    },
+                    {
+                        "type": "integer",
+                        "description": "id общего бюджета, по умолчанию личный",
+                        "name": "X-Household-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Транзакция создана успешно",
+                        "schema": {
+                            "$ref": "#/definitions/api.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные данные",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Нет доступа к бюджету",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transaction/anomalies": {
+            "get": {
+                "security": [
+                    {
+                        "jwtAuth": []
+                    }
+                ],
+                "description": "Список транзакций, сумма которых сильно отличается от истории категории (медиана / MAD)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transaction"
+                ],
+                "summary": "Подозрительные транзакции",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "flagged",
+                        "description": "статус: flagged, confirmed, dismissed",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "id общего бюджета, по умолчанию личный",
+                        "name": "X-Household-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Список транзакций",
+                        "schema": {
+                            "$ref": "#/definitions/api.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный статус",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Нет доступа к бюджету",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transaction/anomalies/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "jwtAuth": []
+                    }
+                ],
+                "description": "Подтверждение (confirmed) или снятие (dismissed) отметки о подозрительной транзакции",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transaction"
+                ],
+                "summary": "Проверка подозрительной транзакции",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "id транзакции",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "решение пользователя",
+                        "name": "req",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/transactionHandlers.RequestReviewAnomaly"
+                        }
+                    },
+                    {
+                        "type": "integer",
+                        "description": "id общего бюджета, по умолчанию личный",
+                        "name": "X-Household-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Решение сохранено",
+                        "schema": {
+                            "$ref": "#/definitions/api.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные входные данные",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Нет доступа к бюджету",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Транзакция не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transaction/search": {
+            "get": {
+                "security": [
+                    {
+                        "jwtAuth": []
+                    }
+                ],
+                "description": "Полнотекстовый поиск по названию и описанию транзакций пользователя (русский и английский). Результаты отсортированы по релевантности, найденные слова выделены \u003cb\u003e\u003c/b\u003e",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transaction"
+                ],
+                "summary": "Поиск транзакций",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "поисковый запрос",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "количество результатов",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "смещение",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "id общего бюджета, по умолчанию личный",
+                        "name": "X-Household-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Найденные транзакции",
+                        "schema": {
+                            "$ref": "#/definitions/api.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные входные данные",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Нет доступа к бюджету",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transaction/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "jwtAuth": []
+                    }
+                ],
+                "description": "Получение 1 транзакции для 1 пользователя",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transaction"
+                ],
+                "summary": "Получение транзакции",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "id транзакции",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "id общего бюджета, по умолчанию личный",
+                        "name": "X-Household-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "good",
+                        "schema": {
+                            "$ref": "#/definitions/api.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные данные",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Нет доступа к бюджету",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Транзакция не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "jwtAuth": []
+                    }
+                ],
+                "description": "Удаление 1 транзакции для 1 пользователя",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transaction"
+                ],
+                "summary": "Удаление транзакции",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "id транзакции",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "id общего бюджета, по умолчанию личный",
+                        "name": "X-Household-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Транзакция удалена",
+                        "schema": {
+                            "$ref": "#/definitions/api.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные данные",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Нет доступа к бюджету",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Транзакция не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/": {
+            "get": {
+                "security": [
+                    {
+                        "jwtAuth": []
+                    }
+                ],
+                "description": "Имя, email, подтвержден ли email, включена ли 2FA и настройки пользователя",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Профиль пользователя",
+                "responses": {
+                    "200": {
+                        "description": "Профиль",
+                        "schema": {
+                            "$ref": "#/definitions/api.SuccessResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "jwtAuth": []
+                    }
+                ],
+                "description": "Изменение имени и/или email. Для смены email нужен текущий пароль, новый email нужно подтвердить заново",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Изменение профиля",
+                "parameters": [
+                    {
+                        "description": "новые имя и/или email",
+                        "name": "req",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/profileHandlers.RequestUpdateProfile"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Профиль",
+                        "schema": {
+                            "$ref": "#/definitions/api.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные входные данные или email занят",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Неверный пароль",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "jwtAuth": []
+                    }
+                ],
+                "description": "Планирование удаления пользователя: аккаунт блокируется, на email приходит письмо, после льготного периода аккаунт удаляется вместе с данными. Вход в аккаунт до этого отменяет удаление",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Удаление пользователя",
+                "parameters": [
+                    {
+                        "description": "данные для удаление пользователя",
+                        "name": "req",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/userHandlers.UserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Время удаления пользователя",
+                        "schema": {
+                            "$ref": "#/definitions/api.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные данные",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Пользователь заблокирован",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/2fa": {
+            "delete": {
+                "security": [
+                    {
+                        "jwtAuth": []
+                    }
+                ],
+                "description": "Отключение двухфакторной аутентификации кодом из приложения или кодом восстановления",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "2FA"
+                ],
+                "summary": "Отключение 2FA",
+                "parameters": [
+                    {
+                        "description": "код из приложения или код восстановления",
+                        "name": "req",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/twofactorHandlers.RequestCode"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "2FA отключена",
+                        "schema": {
+                            "$ref": "#/definitions/api.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "2FA не включена",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Неверный код",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/2fa/confirm": {
+            "post": {
+                "security": [
+                    {
+                        "jwtAuth": []
+                    }
+                ],
+                "description": "Включение двухфакторной аутентификации кодом из приложения. Возвращает коды восстановления, они показываются один раз",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "2FA"
+                ],
+                "summary": "Подтверждение 2FA",
+                "parameters": [
+                    {
+                        "description": "код из приложения",
+                        "name": "req",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/twofactorHandlers.RequestCode"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Коды восстановления",
+                        "schema": {
+                            "$ref": "#/definitions/api.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные входные данные",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Неверный код",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/2fa/enroll": {
+            "post": {
+                "security": [
+                    {
+                        "jwtAuth": []
+                    }
+                ],
+                "description": "Создание секрета TOTP и otpauth URI для приложения-аутентификатора. Двухфакторная аутентификация включается после подтверждения кодом",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "2FA"
+                ],
+                "summary": "Подключение 2FA",
+                "responses": {
+                    "200": {
+                        "description": "Секрет и otpauth URI",
+                        "schema": {
+                            "$ref": "#/definitions/api.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "2FA уже включена",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/export": {
+            "get": {
+                "security": [
+                    {
+                        "jwtAuth": []
+                    }
+                ],
+                "description": "Экспорты пользователя от новых к старым. У готовых экспортов есть подписанная ссылка на скачивание, она действует пока архив не удален",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Экспорты пользователя",
+                "responses": {
+                    "200": {
+                        "description": "Экспорты",
+                        "schema": {
+                            "$ref": "#/definitions/api.SuccessResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "jwtAuth": []
+                    }
+                ],
+                "description": "Запуск сборки ZIP архива с профилем, категориями, транзакциями (JSON и CSV), сессиями и журналом ledger. Архив собирается в фоне, пока сборка не закончилась возвращается тот же экспорт. Журнал из архива можно загрузить обратно через /journal/import",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Экспорт всех данных пользователя",
+                "responses": {
+                    "202": {
+                        "description": "Экспорт",
+                        "schema": {
+                            "$ref": "#/definitions/api.SuccessResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/export/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "jwtAuth": []
+                    }
+                ],
+                "description": "Статус экспорта: pending, ready или failed. У готового экспорта есть подписанная ссылка на скачивание",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Статус экспорта",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "id экспорта",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Экспорт",
+                        "schema": {
+                            "$ref": "#/definitions/api.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные входные данные",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Экспорт не найден",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/logout": {
+            "post": {
+                "security": [
+                    {
+                        "jwtAuth": []
+                    }
+                ],
+                "description": "Завершение текущей сессии: refresh токен сессии перестает действовать",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Выход",
+                "responses": {
+                    "200": {
+                        "description": "Сессия завершена",
+                        "schema": {
+                            "$ref": "#/definitions/api.SuccessResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Сессия не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/password": {
+            "put": {
+                "security": [
+                    {
+                        "jwtAuth": []
+                    }
+                ],
+                "description": "Смена пароля по текущему паролю. Все сессии пользователя, кроме текущей, завершаются, токены доступа отзываются",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Смена пароля",
+                "parameters": [
+                    {
+                        "description": "текущий и новый пароль",
+                        "name": "req",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/passwordHandlers.RequestChangePassword"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Пароль изменен",
+                        "schema": {
+                            "$ref": "#/definitions/api.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные входные данные",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Неверный текущий пароль",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/preferences": {
+            "get": {
+                "security": [
+                    {
+                        "jwtAuth": []
+                    }
+                ],
+                "description": "Валюта по умолчанию, часовой пояс, язык, первый день недели и день начала отчетного месяца",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Настройки пользователя",
+                "responses": {
+                    "200": {
+                        "description": "Настройки",
+                        "schema": {
+                            "$ref": "#/definitions/api.SuccessResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "jwtAuth": []
+                    }
+                ],
+                "description": "Валюта (ISO 4217), часовой пояс (IANA), язык (en, ru), первый день недели (0 - воскресенье, 1 - понедельник) и день начала отчетного месяца (1-28). Отчеты и сообщения об ошибках учитывают настройки",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
                 ],
-                "summary": "Создание транзакции",
+                "summary": "Изменение настроек",
                 "parameters": [
                     {
-                        "description": "данные для создание пользователя",
+                        "description": "настройки",
                         "name": "req",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/transactionHandlers.RequestCreateTransaction"
+                            "$ref": "#/definitions/profileHandlers.RequestPreferences"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Транзакция создана успешно",
+                        "description": "Настройки",
                         "schema": {
                             "$ref": "#/definitions/api.SuccessResponse"
                         }
                     },
                     "400": {
-                        "description": "Некорректные данные",
+                        "description": "Некорректные входные данные",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/security-events": {
+            "get": {
+                "security": [
+                    {
+                        "jwtAuth": []
+                    }
+                ],
+                "description": "События безопасности пользователя от новых к старым: входы (успешные и нет), обновления токенов, смена и сброс пароля, включение и отключение 2FA с IP и User-Agent. new_device отмечает вход с нового устройства, о нем приходит письмо",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "История входов",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "тип: login, refresh, password_change, password_reset, two_factor_enable, two_factor_disable",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "успешно ли действие",
+                        "name": "success",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "с даты (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "по дату включительно (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "количество событий",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "смещение",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "События",
+                        "schema": {
+                            "$ref": "#/definitions/api.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные входные данные",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/sessions": {
+            "get": {
+                "security": [
+                    {
+                        "jwtAuth": []
+                    }
+                ],
+                "description": "Список активных сессий пользователя: устройство (user agent), IP и время последнего использования. Текущая сессия отмечена current",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Активные сессии",
+                "responses": {
+                    "200": {
+                        "description": "Сессии",
+                        "schema": {
+                            "$ref": "#/definitions/api.SuccessResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/sessions/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "jwtAuth": []
+                    }
+                ],
+                "description": "Завершение сессии пользователя, например на потерянном устройстве. Токены сессии перестают действовать",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Завершение сессии",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "id сессии",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Сессия завершена",
+                        "schema": {
+                            "$ref": "#/definitions/api.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные входные данные",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Сессия не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/tokens": {
+            "get": {
+                "security": [
+                    {
+                        "jwtAuth": []
+                    }
+                ],
+                "description": "Токены доступа пользователя: название, начало токена, права, срок действия и время последнего использования",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Токены доступа",
+                "responses": {
+                    "200": {
+                        "description": "Токены",
                         "schema": {
-                            "$ref": "#/definitions/api.ErrorResponse"
+                            "$ref": "#/definitions/api.SuccessResponse"
                         }
                     },
                     "401": {
@@ -566,41 +3878,44 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/transaction/{id}": {
-            "get": {
+            },
+            "post": {
                 "security": [
                     {
                         "jwtAuth": []
                     }
                 ],
-                "description": "Получение 1 транзакции для 1 пользователя",
+                "description": "Создание именного токена доступа для скриптов с ограниченными правами. Токен передается как Bearer в заголовке Authorization и показывается только в этом ответе. Токеном нельзя управлять аккаунтом",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "transaction"
+                    "User"
                 ],
-                "summary": "Получение транзакции",
+                "summary": "Создание токена доступа",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "id транзакции",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "название, права и срок действия токена",
+                        "name": "req",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/accessTokenHandlers.RequestCreateAccessToken"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "good",
+                        "description": "Токен",
                         "schema": {
                             "$ref": "#/definitions/api.SuccessResponse"
                         }
                     },
                     "400": {
-                        "description": "Некорректные данные",
+                        "description": "Некорректные входные данные или слишком много токенов",
                         "schema": {
                             "$ref": "#/definitions/api.ErrorResponse"
                         }
@@ -611,12 +3926,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/api.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Транзакция не найдена",
-                        "schema": {
-                            "$ref": "#/definitions/api.ErrorResponse"
-                        }
-                    },
                     "500": {
                         "description": "Ошибка сервера",
                         "schema": {
@@ -624,25 +3933,27 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/user/tokens/{id}": {
             "delete": {
                 "security": [
                     {
                         "jwtAuth": []
                     }
                 ],
-                "description": "Удаление 1 транзакции для 1 пользователя",
+                "description": "Отзыв токена доступа, запросы с ним перестают проходить сразу",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "transaction"
+                    "User"
                 ],
-                "summary": "Удаление транзакции",
+                "summary": "Отзыв токена доступа",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "id транзакции",
+                        "description": "id токена",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -650,13 +3961,13 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Транзакция удалена",
+                        "description": "Токен отозван",
                         "schema": {
                             "$ref": "#/definitions/api.SuccessResponse"
                         }
                     },
                     "400": {
-                        "description": "Некорректные данные",
+                        "description": "Некорректные входные данные",
                         "schema": {
                             "$ref": "#/definitions/api.ErrorResponse"
                         }
@@ -668,7 +3979,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Транзакция не найдена",
+                        "description": "Токен не найден",
                         "schema": {
                             "$ref": "#/definitions/api.ErrorResponse"
                         }
@@ -682,44 +3993,36 @@ const docTemplate = `{
                 }
             }
         },
-        "/user/": {
-            "delete": {
+        "/user/verify/resend": {
+            "post": {
                 "security": [
                     {
                         "jwtAuth": []
                     }
                 ],
-                "description": "Удаление пользователя",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Отправка нового письма для подтверждения email. Доступно пользователю с неподтвержденным email",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "User"
                 ],
-                "summary": "Удаление пользователя",
-                "parameters": [
-                    {
-                        "description": "данные для удаление пользователя",
-                        "name": "req",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/userHandlers.UserRequest"
-                        }
-                    }
-                ],
+                "summary": "Повторное письмо подтверждения",
                 "responses": {
                     "200": {
-                        "description": "Удаление пользователя",
+                        "description": "Письмо отправлено",
                         "schema": {
                             "$ref": "#/definitions/api.SuccessResponse"
                         }
                     },
                     "400": {
-                        "description": "Некорректные данные",
+                        "description": "Email уже подтвержден",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Ошибка авторизации",
                         "schema": {
                             "$ref": "#/definitions/api.ErrorResponse"
                         }
@@ -741,6 +4044,45 @@ const docTemplate = `{
         }
     },
     "definitions": {
+        "accessTokenHandlers.RequestCreateAccessToken": {
+            "type": "object",
+            "required": [
+                "name",
+                "scopes"
+            ],
+            "properties": {
+                "expires_in": {
+                    "type": "integer",
+                    "example": 90
+                },
+                "name": {
+                    "type": "string",
+                    "example": "bank scraper"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "transactions:write",
+                        "categories:read"
+                    ]
+                }
+            }
+        },
+        "adminHandlers.RequestRole": {
+            "type": "object",
+            "required": [
+                "role"
+            ],
+            "properties": {
+                "role": {
+                    "type": "string",
+                    "example": "admin"
+                }
+            }
+        },
         "api.ErrorResponse": {
             "type": "object",
             "properties": {
@@ -807,6 +4149,173 @@ const docTemplate = `{
                 }
             }
         },
+        "householdHandlers.RequestAccept": {
+            "type": "object",
+            "required": [
+                "token"
+            ],
+            "properties": {
+                "token": {
+                    "type": "string",
+                    "example": "Xy3kQ9..."
+                }
+            }
+        },
+        "householdHandlers.RequestHousehold": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "example": "семья"
+                }
+            }
+        },
+        "householdHandlers.RequestInvite": {
+            "type": "object",
+            "required": [
+                "email",
+                "role"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "anna@gmail.com"
+                },
+                "role": {
+                    "type": "string",
+                    "example": "editor"
+                }
+            }
+        },
+        "householdHandlers.RequestMember": {
+            "type": "object",
+            "required": [
+                "role"
+            ],
+            "properties": {
+                "role": {
+                    "type": "string",
+                    "example": "viewer"
+                }
+            }
+        },
+        "inviteHandlers.RequestInvite": {
+            "type": "object",
+            "required": [
+                "max_uses"
+            ],
+            "properties": {
+                "expires_in": {
+                    "type": "integer",
+                    "example": 7
+                },
+                "max_uses": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "note": {
+                    "type": "string",
+                    "example": "finance team"
+                }
+            }
+        },
+        "passwordHandlers.RequestChangePassword": {
+            "type": "object",
+            "required": [
+                "new_password",
+                "old_password"
+            ],
+            "properties": {
+                "new_password": {
+                    "type": "string",
+                    "example": "securitycod456"
+                },
+                "old_password": {
+                    "type": "string",
+                    "example": "securitycod123"
+                }
+            }
+        },
+        "passwordHandlers.RequestForgotPassword": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "jonn@gmail.com"
+                }
+            }
+        },
+        "passwordHandlers.RequestResetPassword": {
+            "type": "object",
+            "required": [
+                "new_password",
+                "token"
+            ],
+            "properties": {
+                "new_password": {
+                    "type": "string",
+                    "example": "securitycod456"
+                },
+                "token": {
+                    "type": "string",
+                    "example": "Zx9kQ2LmVbT0yA4sWc1NrE7uHj3PfG6dKo8iB5qXtYw"
+                }
+            }
+        },
+        "profileHandlers.RequestPreferences": {
+            "type": "object",
+            "required": [
+                "currency",
+                "locale",
+                "month_start",
+                "timezone"
+            ],
+            "properties": {
+                "currency": {
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "locale": {
+                    "type": "string",
+                    "example": "ru"
+                },
+                "month_start": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "timezone": {
+                    "type": "string",
+                    "example": "Europe/Moscow"
+                },
+                "week_start": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "profileHandlers.RequestUpdateProfile": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "jonn@gmail.com"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "jonn"
+                },
+                "password": {
+                    "type": "string",
+                    "example": "securitycod123"
+                }
+            }
+        },
         "transactionHandlers.RequestCreateTransaction": {
             "type": "object",
             "required": [
@@ -833,6 +4342,18 @@ const docTemplate = `{
                 }
             }
         },
+        "transactionHandlers.RequestReviewAnomaly": {
+            "type": "object",
+            "required": [
+                "status"
+            ],
+            "properties": {
+                "status": {
+                    "type": "string",
+                    "example": "dismissed"
+                }
+            }
+        },
         "transactionHandlers.RequestUpdateTransaction": {
             "type": "object",
             "required": [
@@ -859,6 +4380,50 @@ const docTemplate = `{
                 }
             }
         },
+        "twofactorHandlers.RequestCode": {
+            "type": "object",
+            "required": [
+                "code"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "287082"
+                }
+            }
+        },
+        "twofactorHandlers.RequestRecover": {
+            "type": "object",
+            "required": [
+                "challenge_token",
+                "recovery_code"
+            ],
+            "properties": {
+                "challenge_token": {
+                    "type": "string"
+                },
+                "recovery_code": {
+                    "type": "string",
+                    "example": "k3j9d-x82mf"
+                }
+            }
+        },
+        "twofactorHandlers.RequestTwoFactorLogin": {
+            "type": "object",
+            "required": [
+                "challenge_token",
+                "code"
+            ],
+            "properties": {
+                "challenge_token": {
+                    "type": "string"
+                },
+                "code": {
+                    "type": "string",
+                    "example": "287082"
+                }
+            }
+        },
         "userHandlers.RefreshToken": {
             "type": "object",
             "required": [
@@ -882,6 +4447,10 @@ const docTemplate = `{
                     "type": "string",
                     "example": "jonn@gmail.com"
                 },
+                "invite_code": {
+                    "type": "string",
+                    "example": "q3Zt8LkP..."
+                },
                 "name": {
                     "type": "string",
                     "example": "jonn"
@@ -908,11 +4477,23 @@ const docTemplate = `{
                     "example": "securitycod123"
                 }
             }
+        },
+        "verificationHandlers.RequestVerifyEmail": {
+            "type": "object",
+            "required": [
+                "token"
+            ],
+            "properties": {
+                "token": {
+                    "type": "string",
+                    "example": "Zx9kQ2LmVbT0yA4sWc1NrE7uHj3PfG6dKo8iB5qXtYw"
+                }
+            }
         }
     },
     "securityDefinitions": {
         "jwtAuth": {
-            "description": "type \"Bearer\" после пробел и jwt token, пример: \"Bearer zpdgjeawzgp0398tuP29R0J20THVTP9235BHRNr312r346as2...\"",
+            "description": "type \"Bearer\" после пробел и jwt token или токен доступа ft_pat_..., пример: \"Bearer zpdgjeawzgp0398tuP29R0J20THVTP9235BHRNr312r346as2...\"",
             "type": "apiKey",
             "name": "Authorization",
             "in": "header"
@@ -7,10 +7,24 @@ import (
 )
 
 type Config struct {
-	App    AppB        `mapstructure:"app"`
-	Server HTTPServer  `mapstructure:"server"`
-	DB     DataBase    `mapstructure:"database"`
-	Redis  RedisConfig `mapstructure:"Redis"`
+	App          AppB               `mapstructure:"app"`
+	Server       HTTPServer         `mapstructure:"server"`
+	DB           DataBase           `mapstructure:"database"`
+	Redis        RedisConfig        `mapstructure:"Redis"`
+	JWT          JWTConfig          `mapstructure:"jwt"`
+	Mail         MailConfig         `mapstructure:"mail"`
+	Password     PasswordConfig     `mapstructure:"password"`
+	Verify       VerifyConfig       `mapstructure:"verify"`
+	TwoFactor    TwoFactorConfig    `mapstructure:"twoFactor"`
+	Lockout      LockoutConfig      `mapstructure:"lockout"`
+	Export       ExportConfig       `mapstructure:"export"`
+	Admin        AdminConfig        `mapstructure:"admin"`
+	Household    HouseholdConfig    `mapstructure:"household"`
+	OIDC         OIDCConfig         `mapstructure:"oidc"`
+	Session      SessionConfig      `mapstructure:"session"`
+	CORS         CORSConfig         `mapstructure:"cors"`
+	Registration RegistrationConfig `mapstructure:"registration"`
+	Deletion     DeletionConfig     `mapstructure:"deletion"`
 }
 
 type AppB struct {
@@ -19,11 +33,168 @@ type AppB struct {
 	Host       string `mapstructure:"Host"`
 }
 
+// JWTConfig describes token signing. Algorithm is HS256 (default), RS256 or EdDSA: HS256
+// signs with App.SercretKey, the others with the PEM key in PrivateKeyFile. The current key
+// is identified by KeyID; PreviousKeys (kid: secret) and PreviousPublicKeyFiles (kid: PEM
+// file) only verify tokens signed before a rotation.
+type JWTConfig struct {
+	Algorithm              string            `mapstructure:"algorithm"`
+	KeyID                  string            `mapstructure:"keyId"`
+	PrivateKeyFile         string            `mapstructure:"privateKeyFile"`
+	Issuer                 string            `mapstructure:"issuer"`
+	Audience               string            `mapstructure:"audience"`
+	PreviousKeys           map[string]string `mapstructure:"previousKeys"`
+	PreviousPublicKeyFiles map[string]string `mapstructure:"previousPublicKeyFiles"`
+}
+
+// MailConfig selects how mails are sent: Driver "smtp" uses the SMTP server, "file"
+// appends the mails to File or writes them to stdout when File is empty.
+type MailConfig struct {
+	Driver   string `mapstructure:"driver"`
+	Host     string `mapstructure:"host"`
+	Port     string `mapstructure:"port"`
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+	From     string `mapstructure:"from"`
+	File     string `mapstructure:"file"`
+}
+
+type PasswordConfig struct {
+	ResetTTL time.Duration `mapstructure:"resetTTL"`
+	ResetURL string        `mapstructure:"resetURL"`
+	Argon2   Argon2Config  `mapstructure:"argon2"`
+	Policy   PolicyConfig  `mapstructure:"policy"`
+}
+
+// PolicyConfig describes the new passwords: the length limits, how many of lower case,
+// upper case, digits and other characters are required and whether the name or the email
+// may be part of the password. BreachedDir is a local copy of the Pwned Passwords ranges,
+// one PREFIX.txt file per SHA-1 prefix; without it the check is off.
+type PolicyConfig struct {
+	MinLength     int    `mapstructure:"minLength"`
+	MaxLength     int    `mapstructure:"maxLength"`
+	MinClasses    int    `mapstructure:"minClasses"`
+	AllowPersonal bool   `mapstructure:"allowPersonal"`
+	BreachedDir   string `mapstructure:"breachedDir"`
+}
+
+// Argon2Config is the cost of the password hashes: Memory in KiB, Iterations and
+// Parallelism threads. Zero values keep the defaults, stored hashes made with other
+// values are upgraded on the next login.
+type Argon2Config struct {
+	Memory      uint32 `mapstructure:"memory"`
+	Iterations  uint32 `mapstructure:"iterations"`
+	Parallelism uint8  `mapstructure:"parallelism"`
+}
+
+// VerifyConfig describes email verification. Access is what users with an unverified
+// email may do after login: "limited" (default) only manages the account, "full" allows
+// everything. The mailed link is URL with the token query parameter and lives TTL.
+type VerifyConfig struct {
+	Access string        `mapstructure:"access"`
+	TTL    time.Duration `mapstructure:"TTL"`
+	URL    string        `mapstructure:"URL"`
+}
+
+// TwoFactorConfig describes TOTP two-factor authentication, Issuer is the name
+// authenticator apps show next to the codes.
+type TwoFactorConfig struct {
+	Issuer string `mapstructure:"issuer"`
+}
+
+// LockoutConfig describes the lockout of repeated failed logins: after MaxAttempts
+// failures of an email (MaxAttemptsIP of an IP address) it is locked for BaseLock,
+// doubled with every further failure up to MaxLock. Failures are forgotten Window after
+// the last one. Zero values take the defaults of the lockout package.
+type LockoutConfig struct {
+	MaxAttempts   int           `mapstructure:"maxAttempts"`
+	MaxAttemptsIP int           `mapstructure:"maxAttemptsIP"`
+	BaseLock      time.Duration `mapstructure:"baseLock"`
+	MaxLock       time.Duration `mapstructure:"maxLock"`
+	Window        time.Duration `mapstructure:"window"`
+}
+
+// ExportConfig describes personal data exports: the archives are kept in Dir (exports
+// when empty) for TTL,
+// URL is the download endpoint of the signed links and Secret signs them (App.SercretKey
+// when empty).
+type ExportConfig struct {
+	Dir    string        `mapstructure:"dir"`
+	TTL    time.Duration `mapstructure:"TTL"`
+	URL    string        `mapstructure:"URL"`
+	Secret string        `mapstructure:"secret"`
+}
+
+// AdminConfig lists the emails of the users that get the admin role at start, the
+// first administrator is created this way.
+type AdminConfig struct {
+	Emails []string `mapstructure:"emails"`
+}
+
+// HouseholdConfig describes household invitations: the mailed link is InviteURL with the
+// token query parameter and lives InviteTTL.
+type HouseholdConfig struct {
+	InviteTTL time.Duration `mapstructure:"inviteTTL"`
+	InviteURL string        `mapstructure:"inviteURL"`
+}
+
+// OIDCConfig lists the OpenID Connect providers users may log in with, the key is the
+// name of the provider in the login URLs.
+type OIDCConfig struct {
+	Providers map[string]OIDCProvider `mapstructure:"providers"`
+}
+
+// OIDCProvider describes the client registered at the provider: Issuer is where the
+// discovery document is, RedirectURL the callback of the provider in this API. Scopes
+// default to openid, email and profile.
+type OIDCProvider struct {
+	Issuer       string   `mapstructure:"issuer"`
+	ClientID     string   `mapstructure:"clientId"`
+	ClientSecret string   `mapstructure:"clientSecret"`
+	RedirectURL  string   `mapstructure:"redirectURL"`
+	Scopes       []string `mapstructure:"scopes"`
+}
+
+// SessionConfig describes the cookie session mode for browser clients: with Cookies on a
+// login sent with X-Session-Mode: cookie sets the tokens as HttpOnly cookies. SameSite is
+// strict (default), lax or none; Insecure drops the Secure flag for local development.
+type SessionConfig struct {
+	Cookies  bool   `mapstructure:"cookies"`
+	Domain   string `mapstructure:"domain"`
+	SameSite string `mapstructure:"sameSite"`
+	Insecure bool   `mapstructure:"insecure"`
+}
+
+// CORSConfig lists the origins of the browser clients, Credentials lets them send the
+// session cookies. Without both any origin is allowed.
+type CORSConfig struct {
+	Origins     []string `mapstructure:"origins"`
+	Credentials bool     `mapstructure:"credentials"`
+}
+
+// RegistrationConfig describes who can sign up: Mode is open (default), invite or closed,
+// Domains limits the emails to the listed domains. The admin emails can always sign up.
+type RegistrationConfig struct {
+	Mode    string   `mapstructure:"mode"`
+	Domains []string `mapstructure:"domains"`
+}
+
+// DeletionConfig describes the account deletion: Grace is how long a deleted account can
+// be restored by logging in (30 days by default), Interval how often the accounts past it
+// are purged (hourly by default).
+type DeletionConfig struct {
+	Grace    time.Duration `mapstructure:"grace"`
+	Interval time.Duration `mapstructure:"interval"`
+}
+
+// HTTPServer describes the listener. TrustedProxies are the addresses or CIDRs of the reverse
+// proxies whose X-Forwarded-For is believed; without them the client is the remote address.
 type HTTPServer struct {
-	Port         string        `mapstructure:"port"`
-	ReadTimeout  time.Duration `mapstructure:"readTimeout"`
-	WriteTimeout time.Duration `mapstructure:"writeTimeout"`
-	IdleTimeout  time.Duration `mapstructure:"idleTimeout"`
+	Port           string        `mapstructure:"port"`
+	ReadTimeout    time.Duration `mapstructure:"readTimeout"`
+	WriteTimeout   time.Duration `mapstructure:"writeTimeout"`
+	IdleTimeout    time.Duration `mapstructure:"idleTimeout"`
+	TrustedProxies []string      `mapstructure:"trustedProxies"`
 }
 
 type DataBase struct {
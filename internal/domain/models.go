@@ -1,14 +1,37 @@
 package domain
 
+import (
+	"strings"
+	"time"
+)
+
 type AuthenticationUser struct {
 	Email    string `json:"email" validate:"required,email"`
 	Password string `json:"password" validate:"required,min=5"`
+	Device   Device `json:"-"`
 }
 
 type RegisterUser struct {
-	Name     string `json:"name" validate:"required,min=3,max=50"`
-	Email    string `json:"email" validate:"required,email"`
-	Password string `json:"password" validate:"required,min=5"`
+	Name       string `json:"name" validate:"required,min=3,max=50"`
+	Email      string `json:"email" validate:"required,email"`
+	Password   string `json:"password" validate:"required,password_length,password_classes,password_personal,password_breached"`
+	InviteCode string `json:"invite_code" validate:"max=100"`
+	Device     Device `json:"-"`
+}
+
+// Device describes the client a session was started or last used from.
+type Device struct {
+	UserAgent string `json:"user_agent"`
+	IP        string `json:"ip"`
+}
+
+type Session struct {
+	ID uint `json:"id"`
+	Device
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	Current    bool      `json:"current"`
 }
 
 type DeleteUser struct {
@@ -16,12 +39,116 @@ type DeleteUser struct {
 	Password string `json:"password" validate:"required,min=5"`
 }
 
+// AccountDeletion is when the account is purged, logging in before that cancels it.
+type AccountDeletion struct {
+	DeleteAt time.Time `json:"delete_at"`
+}
+
+// PurgedUser lists what was removed with the user or handed over to another member, so the
+// caches and the stored files can follow.
+type PurgedUser struct {
+	UserID         uint
+	CategoryIDs    []uint
+	TransactionIDs []uint
+	Files          []string
+}
+
+type ChangePassword struct {
+	OldPassword string `json:"old_password" validate:"required"`
+	NewPassword string `json:"new_password" validate:"required,nefield=OldPassword,password_length,password_classes,password_breached"`
+	Device      Device `json:"-"`
+}
+
+type ForgotPassword struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type ResetPassword struct {
+	Token       string `json:"token" validate:"required"`
+	NewPassword string `json:"new_password" validate:"required,password_length,password_classes,password_breached"`
+	Device      Device `json:"-"`
+}
+
+type VerifyEmail struct {
+	Token string `json:"token" validate:"required"`
+}
+
+// TwoFactor is the confirmed TOTP secret of a user, LastStep the time step of the last
+// accepted code.
+type TwoFactor struct {
+	Secret   string
+	LastStep int64
+}
+
+type TwoFactorEnrollment struct {
+	Secret string `json:"secret"`
+	URI    string `json:"otpauth_uri"`
+}
+
+type TwoFactorCode struct {
+	Code   string `json:"code" validate:"required"`
+	Device Device `json:"-"`
+}
+
+type RecoveryCodes struct {
+	Codes []string `json:"recovery_codes"`
+}
+
+type TwoFactorLogin struct {
+	ChallengeToken string `json:"challenge_token" validate:"required"`
+	Code           string `json:"code" validate:"required"`
+	Device         Device `json:"-"`
+}
+
+type Mail struct {
+	To      string
+	Subject string
+	Body    string
+}
+
 type User struct {
 	Name         string `json:"name"`
 	Email        string `json:"email"`
 	PasswordHash []byte `json:"password_hasy"`
 }
 
+// Preferences of the user. WeekStart is the first day of the week as time.Weekday
+// (0 is Sunday), MonthStart is the day the reporting month starts on.
+type Preferences struct {
+	Currency   string `json:"currency" validate:"required,iso4217" example:"RUB"`
+	Timezone   string `json:"timezone" validate:"required,timezone" example:"Europe/Moscow"`
+	Locale     string `json:"locale" validate:"required,oneof=en ru" example:"ru"`
+	WeekStart  int    `json:"week_start" validate:"min=0,max=6" example:"1"`
+	MonthStart int    `json:"month_start" validate:"required,min=1,max=28" example:"1"`
+}
+
+const (
+	DefaultCurrency   = "RUB"
+	DefaultTimezone   = "UTC"
+	DefaultLocale     = "en"
+	DefaultWeekStart  = 1
+	DefaultMonthStart = 1
+)
+
+type Profile struct {
+	ID          uint        `json:"id"`
+	Name        string      `json:"name"`
+	Email       string      `json:"email"`
+	Role        string      `json:"role"`
+	Verified    bool        `json:"verified"`
+	TwoFactor   bool        `json:"two_factor"`
+	CreatedAt   time.Time   `json:"created_at"`
+	Preferences Preferences `json:"preferences"`
+}
+
+// UpdateProfile changes the name and/or the email, changing the email takes the
+// current password.
+type UpdateProfile struct {
+	Name     string `json:"name" validate:"omitempty,min=3,max=50"`
+	Email    string `json:"email" validate:"omitempty,email"`
+	Password string `json:"password"`
+}
+
 // @Name	Category
 type CategoryInput struct {
 	Name        string `json:"name" validate:"required,max=60,min=3"`
@@ -32,6 +159,7 @@ type CategoryInput struct {
 
 type CategoryOutput struct {
 	UserID      uint
+	HouseholdID uint
 	Name        string `json:"name"`
 	Limit       int    `json:"limit"`
 	Type        string `json:"type"`
@@ -46,8 +174,502 @@ type TransactionInput struct {
 
 type TransactionOutput struct {
 	UserID      uint
+	HouseholdID uint
 	CategoryID  uint
 	Name        string `json:"name" validate:"required,max=60,min=3"`
 	Count       int    `json:"count" validate:"required"`
 	Description string `json:"description" validate:"max=100"`
 }
+
+type JournalTransaction struct {
+	Date        time.Time `json:"date"`
+	Category    string    `json:"category" validate:"required,max=60,min=3"`
+	Name        string    `json:"name" validate:"required,max=60,min=3"`
+	Count       int       `json:"count" validate:"required"`
+	Description string    `json:"description" validate:"max=100"`
+}
+
+type Journal struct {
+	UserName     string               `json:"user_name"`
+	Email        string               `json:"email"`
+	Currency     string               `json:"currency"`
+	Categories   []CategoryInput      `json:"categories" validate:"dive"`
+	Transactions []JournalTransaction `json:"transactions" validate:"dive"`
+}
+
+type JournalImport struct {
+	Categories   int `json:"categories"`
+	Transactions int `json:"transactions"`
+	Skipped      int `json:"skipped"`
+}
+
+type CategoryRecord struct {
+	ID uint `json:"id"`
+	CategoryOutput
+}
+
+type TransactionRecord struct {
+	ID   uint      `json:"id"`
+	Date time.Time `json:"date"`
+	TransactionOutput
+}
+
+const (
+	AnomalyFlagged   = "flagged"
+	AnomalyConfirmed = "confirmed"
+	AnomalyDismissed = "dismissed"
+)
+
+type TransactionAnomaly struct {
+	TransactionRecord
+	Reason string `json:"reason"`
+	Status string `json:"status"`
+}
+
+type SearchQuery struct {
+	Query  string `json:"query" validate:"required,max=200"`
+	Limit  int    `json:"limit" validate:"min=1,max=100"`
+	Offset int    `json:"offset" validate:"min=0"`
+}
+
+type TransactionSearchResult struct {
+	TransactionRecord
+	Rank                 float64 `json:"rank"`
+	NameHighlight        string  `json:"name_highlight"`
+	DescriptionHighlight string  `json:"description_highlight"`
+}
+
+type CategoryForecast struct {
+	CategoryID       uint   `json:"category_id"`
+	Name             string `json:"name"`
+	Limit            int    `json:"limit"`
+	Spent            int    `json:"spent"`
+	RunRate          int    `json:"run_rate"`
+	TrailingAverage  int    `json:"trailing_average"`
+	RecurringPending int    `json:"recurring_pending"`
+	Projected        int    `json:"projected"`
+	OverLimit        bool   `json:"over_limit"`
+}
+
+type Forecast struct {
+	From         time.Time          `json:"from"`
+	To           time.Time          `json:"to"`
+	Currency     string             `json:"currency"`
+	DaysElapsed  int                `json:"days_elapsed"`
+	DaysInPeriod int                `json:"days_in_period"`
+	Categories   []CategoryForecast `json:"categories"`
+}
+
+// Period is the half-open time range [From, To).
+type Period struct {
+	From time.Time `json:"from" validate:"required"`
+	To   time.Time `json:"to" validate:"required,gtfield=From"`
+}
+
+type CategoryTotal struct {
+	CategoryID   uint   `json:"category_id"`
+	Name         string `json:"name"`
+	Total        int    `json:"total"`
+	Transactions int    `json:"transactions"`
+}
+
+const (
+	ComparisonNew         = "new"
+	ComparisonDisappeared = "disappeared"
+)
+
+// Comparison is the spending of the current period against the previous one, Percent
+// is nil when nothing was spent in the previous period.
+type Comparison struct {
+	Current    int      `json:"current"`
+	Previous   int      `json:"previous"`
+	Difference int      `json:"difference"`
+	Percent    *float64 `json:"percent"`
+}
+
+type CategoryComparison struct {
+	CategoryID uint   `json:"category_id"`
+	Name       string `json:"name"`
+	Comparison
+	Status string `json:"status,omitempty"`
+}
+
+type PeriodComparison struct {
+	Current    Period               `json:"current"`
+	Previous   Period               `json:"previous"`
+	Currency   string               `json:"currency"`
+	Total      Comparison           `json:"total"`
+	Categories []CategoryComparison `json:"categories"`
+}
+
+const (
+	ExportPending = "pending"
+	ExportReady   = "ready"
+	ExportFailed  = "failed"
+)
+
+// DataExport is an archive with all the data of the user. DownloadURL is a signed link
+// valid until ExpiresAt, it is set on ready exports only.
+type DataExport struct {
+	ID          uint       `json:"id"`
+	Status      string     `json:"status"`
+	Size        int64      `json:"size,omitempty"`
+	Error       string     `json:"error,omitempty"`
+	FileName    string     `json:"-"`
+	UserID      uint       `json:"-"`
+	CreatedAt   time.Time  `json:"created_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	DownloadURL string     `json:"download_url,omitempty"`
+}
+
+// Archive is everything the user owns, as it is written to the data export.
+type Archive struct {
+	Profile        Profile              `json:"profile"`
+	Categories     []ArchiveCategory    `json:"categories"`
+	Transactions   []ArchiveTransaction `json:"transactions"`
+	Sessions       []Session            `json:"sessions"`
+	AccessTokens   []AccessToken        `json:"access_tokens"`
+	SecurityEvents []SecurityEvent      `json:"security_events"`
+	Households     []Household          `json:"households"`
+	Identities     []ArchiveIdentity    `json:"identities"`
+}
+
+// ArchiveIdentity is an identity provider account linked to the user.
+type ArchiveIdentity struct {
+	Provider string    `json:"provider"`
+	Subject  string    `json:"subject"`
+	Email    string    `json:"email"`
+	LinkedAt time.Time `json:"linked_at"`
+}
+
+type ArchiveCategory struct {
+	ID          uint      `json:"id"`
+	Name        string    `json:"name"`
+	Limit       int       `json:"limit"`
+	Type        string    `json:"type"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type ArchiveTransaction struct {
+	ID            uint      `json:"id"`
+	CategoryID    uint      `json:"category_id"`
+	Category      string    `json:"category"`
+	Name          string    `json:"name"`
+	Count         int       `json:"count"`
+	Description   string    `json:"description"`
+	AnomalyStatus string    `json:"anomaly_status,omitempty"`
+	AnomalyReason string    `json:"anomaly_reason,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// AccessTokenPrefix starts every personal access token, it tells them apart from JWTs.
+const AccessTokenPrefix = "ft_pat_"
+
+// Scopes of personal access tokens, a write scope also allows reading.
+const (
+	ScopeCategoriesRead    = "categories:read"
+	ScopeCategoriesWrite   = "categories:write"
+	ScopeTransactionsRead  = "transactions:read"
+	ScopeTransactionsWrite = "transactions:write"
+	ScopeJournalRead       = "journal:read"
+	ScopeJournalWrite      = "journal:write"
+	ScopeReportsRead       = "reports:read"
+)
+
+// AccessToken is a personal access token of the user, the token itself is shown only once
+// when it is created, Prefix identifies it afterwards.
+type AccessToken struct {
+	ID         uint       `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	UserID     uint       `json:"-"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+}
+
+// CreateAccessToken describes a new personal access token, ExpiresIn is its lifetime in
+// days, 0 is a token that never expires.
+type CreateAccessToken struct {
+	Name      string   `json:"name" validate:"required,min=3,max=60"`
+	Scopes    []string `json:"scopes" validate:"required,min=1,dive,oneof=categories:read categories:write transactions:read transactions:write journal:read journal:write reports:read"`
+	ExpiresIn int      `json:"expires_in" validate:"min=0,max=365"`
+}
+
+type NewAccessToken struct {
+	AccessToken
+	Token string `json:"token"`
+}
+
+// Roles of users, only admins may use the admin API.
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
+// AdminUser is a user as administrators see it, Counts is filled for a single user.
+type AdminUser struct {
+	ID         uint        `json:"id"`
+	Name       string      `json:"name"`
+	Email      string      `json:"email"`
+	Role       string      `json:"role"`
+	Verified   bool        `json:"verified"`
+	TwoFactor  bool        `json:"two_factor"`
+	CreatedAt  time.Time   `json:"created_at"`
+	DisabledAt *time.Time  `json:"disabled_at,omitempty"`
+	Counts     *UserCounts `json:"counts,omitempty"`
+}
+
+// UserCounts is how many records the user owns, Sessions are the active ones.
+type UserCounts struct {
+	Categories   int64 `json:"categories"`
+	Transactions int64 `json:"transactions"`
+	Sessions     int64 `json:"sessions"`
+	AccessTokens int64 `json:"access_tokens"`
+	DataExports  int64 `json:"data_exports"`
+}
+
+// AdminUserFilter selects users by a part of the name or email, the role and whether
+// the account is disabled.
+type AdminUserFilter struct {
+	Query    string `json:"query" validate:"max=100"`
+	Role     string `json:"role" validate:"omitempty,oneof=user admin"`
+	Disabled *bool  `json:"disabled"`
+	Limit    int    `json:"limit" validate:"min=1,max=100"`
+	Offset   int    `json:"offset" validate:"min=0"`
+}
+
+type AdminUserList struct {
+	Users []AdminUser `json:"users"`
+	Total int64       `json:"total"`
+}
+
+type UpdateRole struct {
+	Role string `json:"role" validate:"required,oneof=user admin"`
+}
+
+// Roles of household members: the owner manages the members, editors change categories
+// and transactions, viewers only read them.
+const (
+	HouseholdOwner  = "owner"
+	HouseholdEditor = "editor"
+	HouseholdViewer = "viewer"
+)
+
+// Member is the user acting in the active household of the request. Repositories check
+// the membership themselves, Role is what the user had when the request started.
+type Member struct {
+	UserID      uint
+	HouseholdID uint
+	Role        string
+}
+
+// CanWrite reports whether the member may change categories and transactions.
+func (m Member) CanWrite() bool {
+	return m.Role == HouseholdOwner || m.Role == HouseholdEditor
+}
+
+// Household is a shared budget, Role is the role of the user looking at it. The
+// personal household of a user is created with the account and can't be shared.
+type Household struct {
+	ID        uint              `json:"id"`
+	Name      string            `json:"name"`
+	Personal  bool              `json:"personal"`
+	Role      string            `json:"role"`
+	CreatedAt time.Time         `json:"created_at"`
+	Members   []HouseholdMember `json:"members,omitempty"`
+}
+
+type HouseholdMember struct {
+	UserID   uint      `json:"user_id"`
+	Name     string    `json:"name"`
+	Email    string    `json:"email"`
+	Role     string    `json:"role"`
+	JoinedAt time.Time `json:"joined_at"`
+}
+
+type CreateHousehold struct {
+	Name string `json:"name" validate:"required,min=3,max=60"`
+}
+
+type InviteMember struct {
+	Email string `json:"email" validate:"required,email"`
+	Role  string `json:"role" validate:"required,oneof=editor viewer"`
+}
+
+type UpdateMember struct {
+	Role string `json:"role" validate:"required,oneof=editor viewer"`
+}
+
+type AcceptInvitation struct {
+	Token string `json:"token" validate:"required"`
+}
+
+// HouseholdInvitation is an invitation mailed to join a household, it is accepted by the
+// user with the invited email.
+type HouseholdInvitation struct {
+	ID          uint      `json:"id"`
+	HouseholdID uint      `json:"household_id"`
+	Household   string    `json:"household"`
+	Email       string    `json:"email"`
+	Role        string    `json:"role"`
+	InvitedBy   string    `json:"invited_by"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// OIDCStart is the authorization URL of the identity provider the user is sent to.
+type OIDCStart struct {
+	URL string `json:"url"`
+}
+
+// OIDCCallback is the redirect back from the identity provider.
+type OIDCCallback struct {
+	Provider string `validate:"required"`
+	Code     string `validate:"required"`
+	State    string `validate:"required"`
+	Device   Device
+}
+
+// OIDCState is a login started with the identity provider, it is kept on the server
+// until the callback: Nonce is bound to the ID token, Verifier is the PKCE code verifier.
+type OIDCState struct {
+	Provider string
+	Nonce    string
+	Verifier string
+}
+
+// OIDCIdentity is the user as an identity provider has authenticated it, Subject is
+// the id of the user at the provider.
+type OIDCIdentity struct {
+	Provider      string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+// Types of security events.
+const (
+	EventLogin            = "login"
+	EventRefresh          = "refresh"
+	EventPasswordChange   = "password_change"
+	EventPasswordReset    = "password_reset"
+	EventTwoFactorEnable  = "two_factor_enable"
+	EventTwoFactorDisable = "two_factor_disable"
+)
+
+// SecurityEvent is a record of the login history of a user: a login, a token refresh or
+// a change of the password or of two-factor authentication. Email is the one a failed
+// login was made with, UserID is 0 when no user has it. NewDevice marks a successful
+// login from a user agent and IP the user never logged in from before.
+type SecurityEvent struct {
+	ID        uint   `json:"id"`
+	UserID    uint   `json:"user_id"`
+	Email     string `json:"email,omitempty"`
+	Type      string `json:"type"`
+	Success   bool   `json:"success"`
+	Reason    string `json:"reason,omitempty"`
+	NewDevice bool   `json:"new_device"`
+	Device
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// SecurityEventFilter selects security events from the newest, a zero UserID means the
+// events of all users.
+type SecurityEventFilter struct {
+	UserID  uint      `json:"user_id"`
+	Type    string    `json:"type" validate:"omitempty,oneof=login refresh password_change password_reset two_factor_enable two_factor_disable"`
+	Success *bool     `json:"success"`
+	From    time.Time `json:"from"`
+	To      time.Time `json:"to"`
+	Limit   int       `json:"limit" validate:"min=1,max=100"`
+	Offset  int       `json:"offset" validate:"min=0"`
+}
+
+type SecurityEventList struct {
+	Events []SecurityEvent `json:"events"`
+	Total  int64           `json:"total"`
+}
+
+// Registration modes: open lets anyone sign up, invite needs an invite code created by an
+// administrator, closed turns sign-up off.
+const (
+	RegistrationOpen   = "open"
+	RegistrationInvite = "invite"
+	RegistrationClosed = "closed"
+)
+
+// RegistrationPolicy decides who may sign up. With Domains set only emails of the domains
+// may. Admins are the emails of the administrators, they sign up in any mode, so the
+// first administrator can be created in a closed deployment.
+type RegistrationPolicy struct {
+	Mode    string
+	Domains []string
+	Admins  []string
+}
+
+// Admin reports whether the email is one of the administrators.
+func (p RegistrationPolicy) Admin(email string) bool {
+	for _, value := range p.Admins {
+		if strings.EqualFold(strings.TrimSpace(value), strings.TrimSpace(email)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Open reports whether the email may sign up without an invite code.
+func (p RegistrationPolicy) Open(email string) bool {
+	if p.Admin(email) {
+		return true
+	}
+	return (p.Mode == "" || p.Mode == RegistrationOpen) && p.DomainAllowed(email)
+}
+
+// DomainAllowed reports whether the domain of the email is allowed to sign up.
+func (p RegistrationPolicy) DomainAllowed(email string) bool {
+	if len(p.Domains) == 0 {
+		return true
+	}
+
+	_, domain, ok := strings.Cut(strings.TrimSpace(email), "@")
+	if !ok {
+		return false
+	}
+	for _, value := range p.Domains {
+		if strings.EqualFold(strings.TrimPrefix(strings.TrimSpace(value), "@"), domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// InviteCode lets users sign up in the invite registration mode, it can be used MaxUses
+// times until ExpiresAt. The code itself is shown once, Prefix identifies it later.
+type InviteCode struct {
+	ID        uint       `json:"id"`
+	Prefix    string     `json:"prefix"`
+	Note      string     `json:"note,omitempty"`
+	MaxUses   int        `json:"max_uses"`
+	Uses      int        `json:"uses"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	CreatedBy uint       `json:"created_by"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+type NewInviteCode struct {
+	InviteCode
+	Code string `json:"code"`
+}
+
+// CreateInviteCode describes a new invite code, ExpiresIn is in days, 0 is without expiry.
+type CreateInviteCode struct {
+	Note      string `json:"note" validate:"max=100"`
+	MaxUses   int    `json:"max_uses" validate:"min=1,max=1000"`
+	ExpiresIn int    `json:"expires_in" validate:"min=0,max=365"`
+}
@@ -0,0 +1,182 @@
+package accessTokenHandlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/financial_tracer/internal/handlers/api"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type AccessTokenServic interface {
+	CreateAccessToken(ctx context.Context, userID uint, req domain.CreateAccessToken) (domain.NewAccessToken, error)
+	AccessTokens(ctx context.Context, userID uint) ([]domain.AccessToken, error)
+	RevokeAccessToken(ctx context.Context, userID uint, id uint) error
+}
+
+type AccessTokenHandlers struct {
+	s   AccessTokenServic
+	log *logrus.Logger
+	ctx context.Context
+}
+
+func CreateAccessTokenHandlers(s AccessTokenServic, log *logrus.Logger, ctx context.Context) *AccessTokenHandlers {
+	return &AccessTokenHandlers{
+		s:   s,
+		log: log,
+		ctx: ctx,
+	}
+}
+
+// CreateAccessToken godoc
+//
+//	@Summary		Создание токена доступа
+//	@Description	Создание именного токена доступа для скриптов с ограниченными правами. Токен передается как Bearer в заголовке Authorization и показывается только в этом ответе. Токеном нельзя управлять аккаунтом
+//
+//	@Tags			User
+//
+//	@Accept			json
+//	@Produce		json
+//	@Param			req	body		RequestCreateAccessToken	true	"название, права и срок действия токена"
+//	@Success		200	{object}	api.SuccessResponse			"Токен"
+//
+//	@Failure		400	{object}	api.ErrorResponse			"Некорректные входные данные или слишком много токенов"
+//	@Failure		401	{object}	api.ErrorResponse			"Ошибка авторизации"
+//	@Failure		500	{object}	api.ErrorResponse			"Ошибка сервера"
+//
+//	@Router			/user/tokens [post]
+//
+//	@Security		jwtAuth
+func (h *AccessTokenHandlers) CreateAccessToken(c *gin.Context) {
+	const op = "handlers.CreateAccessToken"
+
+	log := h.log.WithField("op", op)
+
+	log.Info("start create access token")
+
+	var req RequestCreateAccessToken
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.WithField("err", err).Error("error valid JSON")
+		api.ResponseError(c, http.StatusBadRequest, "error valid JSON")
+		return
+	}
+
+	idUser, ok := c.Get("userID")
+	if !ok {
+		log.Error("error get userID")
+		api.ResponseError(c, http.StatusInternalServerError, "error server")
+		return
+	}
+
+	token, err := h.s.CreateAccessToken(c.Request.Context(), idUser.(uint), domain.CreateAccessToken{
+		Name:      req.Name,
+		Scopes:    req.Scopes,
+		ExpiresIn: req.ExpiresIn,
+	})
+	if err != nil {
+		log.WithField("err", err).Error("error create access token")
+		api.RegistrationError(c, err)
+		return
+	}
+
+	log.Info("success create access token")
+
+	api.ResponseOK(c, token)
+}
+
+// ListAccessTokens godoc
+//
+//	@Summary		Токены доступа
+//	@Description	Токены доступа пользователя: название, начало токена, права, срок действия и время последнего использования
+//
+//	@Tags			User
+//
+//	@Produce		json
+//	@Success		200	{object}	api.SuccessResponse	"Токены"
+//
+//	@Failure		401	{object}	api.ErrorResponse	"Ошибка авторизации"
+//	@Failure		500	{object}	api.ErrorResponse	"Ошибка сервера"
+//
+//	@Router			/user/tokens [get]
+//
+//	@Security		jwtAuth
+func (h *AccessTokenHandlers) ListAccessTokens(c *gin.Context) {
+	const op = "handlers.ListAccessTokens"
+
+	log := h.log.WithField("op", op)
+
+	log.Info("start list access tokens")
+
+	idUser, ok := c.Get("userID")
+	if !ok {
+		log.Error("error get userID")
+		api.ResponseError(c, http.StatusInternalServerError, "error server")
+		return
+	}
+
+	tokens, err := h.s.AccessTokens(c.Request.Context(), idUser.(uint))
+	if err != nil {
+		log.WithField("err", err).Error("error list access tokens")
+		api.RegistrationError(c, err)
+		return
+	}
+
+	log.Info("success list access tokens")
+
+	api.ResponseOK(c, tokens)
+}
+
+// RevokeAccessToken godoc
+//
+//	@Summary		Отзыв токена доступа
+//	@Description	Отзыв токена доступа, запросы с ним перестают проходить сразу
+//
+//	@Tags			User
+//
+//	@Produce		json
+//	@Param			id	path		int					true	"id токена"
+//	@Success		200	{object}	api.SuccessResponse	"Токен отозван"
+//
+//	@Failure		400	{object}	api.ErrorResponse	"Некорректные входные данные"
+//	@Failure		401	{object}	api.ErrorResponse	"Ошибка авторизации"
+//	@Failure		404	{object}	api.ErrorResponse	"Токен не найден"
+//	@Failure		500	{object}	api.ErrorResponse	"Ошибка сервера"
+//
+//	@Router			/user/tokens/{id} [delete]
+//
+//	@Security		jwtAuth
+func (h *AccessTokenHandlers) RevokeAccessToken(c *gin.Context) {
+	const op = "handlers.RevokeAccessToken"
+
+	log := h.log.WithField("op", op)
+
+	log.Info("start revoke access token")
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		log.WithField("err", err).Error("error get id")
+		api.ResponseError(c, http.StatusBadRequest, "invalid token id")
+		return
+	}
+
+	idUser, ok := c.Get("userID")
+	if !ok {
+		log.Error("error get userID")
+		api.ResponseError(c, http.StatusInternalServerError, "error server")
+		return
+	}
+
+	err = h.s.RevokeAccessToken(c.Request.Context(), idUser.(uint), uint(id))
+	if err != nil {
+		log.WithField("err", err).Error("error revoke access token")
+		api.RegistrationError(c, err)
+		return
+	}
+
+	log.Info("success revoke access token")
+
+	api.ResponseOK(c, "token revoked")
+}
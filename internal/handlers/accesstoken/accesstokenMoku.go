@@ -0,0 +1,27 @@
+package accessTokenHandlers
+
+import (
+	"context"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type accessTokenServicMock struct {
+	mock.Mock
+}
+
+func (m *accessTokenServicMock) CreateAccessToken(ctx context.Context, userID uint, req domain.CreateAccessToken) (domain.NewAccessToken, error) {
+	args := m.Called(ctx, userID, req)
+	return args.Get(0).(domain.NewAccessToken), args.Error(1)
+}
+
+func (m *accessTokenServicMock) AccessTokens(ctx context.Context, userID uint) ([]domain.AccessToken, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]domain.AccessToken), args.Error(1)
+}
+
+func (m *accessTokenServicMock) RevokeAccessToken(ctx context.Context, userID uint, id uint) error {
+	args := m.Called(ctx, userID, id)
+	return args.Error(0)
+}
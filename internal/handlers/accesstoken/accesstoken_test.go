@@ -0,0 +1,153 @@
+package accessTokenHandlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/financial_tracer/internal/servic/accesstoken"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/assert/v2"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreateAccessToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	valid := RequestCreateAccessToken{Name: "bank scraper", Scopes: []string{domain.ScopeTransactionsWrite}, ExpiresIn: 90}
+
+	tests := []struct {
+		name         string
+		body         RequestCreateAccessToken
+		invalidJSON  bool
+		mockErr      error
+		status       int
+		shouldCallDB bool
+	}{
+		{
+			name:         "success",
+			body:         valid,
+			status:       http.StatusOK,
+			shouldCallDB: true,
+		},
+		{
+			name:         "error too many",
+			body:         valid,
+			mockErr:      accesstoken.ErrTooMany,
+			status:       http.StatusBadRequest,
+			shouldCallDB: true,
+		},
+		{
+			name:        "error json",
+			invalidJSON: true,
+			status:      http.StatusBadRequest,
+		},
+		{
+			name:   "error no scopes",
+			body:   RequestCreateAccessToken{Name: "bank scraper"},
+			status: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Set("userID", uint(1))
+
+			svc := new(accessTokenServicMock)
+			ctx := context.Background()
+			svc.On("CreateAccessToken", mock.Anything, uint(1), domain.CreateAccessToken{
+				Name:      tc.body.Name,
+				Scopes:    tc.body.Scopes,
+				ExpiresIn: tc.body.ExpiresIn,
+			}).Return(domain.NewAccessToken{Token: domain.AccessTokenPrefix + "secret"}, tc.mockErr)
+
+			h := CreateAccessTokenHandlers(svc, logrus.New(), ctx)
+
+			req := http.Request{Header: make(http.Header), URL: &url.URL{}}
+			if tc.invalidJSON {
+				req.Body = io.NopCloser(bytes.NewBufferString("{"))
+			} else {
+				b, _ := json.Marshal(tc.body)
+				req.Body = io.NopCloser(bytes.NewBuffer(b))
+			}
+			req.Header.Set("content-type", "application/json")
+			c.Request = req.WithContext(ctx)
+
+			h.CreateAccessToken(c)
+
+			assert.Equal(t, tc.status, w.Code)
+			if tc.shouldCallDB {
+				svc.AssertExpectations(t)
+			} else {
+				svc.AssertNotCalled(t, "CreateAccessToken", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestRevokeAccessToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name         string
+		id           string
+		mockErr      error
+		status       int
+		shouldCallDB bool
+	}{
+		{
+			name:         "success",
+			id:           "9",
+			status:       http.StatusOK,
+			shouldCallDB: true,
+		},
+		{
+			name:         "error not found",
+			id:           "9",
+			mockErr:      accesstoken.ErrNoFound,
+			status:       http.StatusNotFound,
+			shouldCallDB: true,
+		},
+		{
+			name:   "error id",
+			id:     "nine",
+			status: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Set("userID", uint(1))
+			c.Params = gin.Params{{Key: "id", Value: tc.id}}
+
+			svc := new(accessTokenServicMock)
+			ctx := context.Background()
+			svc.On("RevokeAccessToken", mock.Anything, uint(1), uint(9)).Return(tc.mockErr)
+
+			h := CreateAccessTokenHandlers(svc, logrus.New(), ctx)
+
+			req := http.Request{Header: make(http.Header), URL: &url.URL{}}
+			c.Request = req.WithContext(ctx)
+
+			h.RevokeAccessToken(c)
+
+			assert.Equal(t, tc.status, w.Code)
+			if tc.shouldCallDB {
+				svc.AssertExpectations(t)
+			} else {
+				svc.AssertNotCalled(t, "RevokeAccessToken", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
@@ -0,0 +1,10 @@
+package accessTokenHandlers
+
+// RequestCreateAccessToken represents create personal access token request, scopes are
+// categories, transactions, journal with :read or :write and reports:read, expires_in is
+// the lifetime in days (0 - never expires)
+type RequestCreateAccessToken struct {
+	Name      string   `json:"name" binding:"required" example:"bank scraper"`
+	Scopes    []string `json:"scopes" binding:"required" example:"transactions:write,categories:read"`
+	ExpiresIn int      `json:"expires_in" example:"90"`
+}
@@ -0,0 +1,327 @@
+package adminHandlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/financial_tracer/internal/handlers/api"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type AdminServic interface {
+	Users(ctx context.Context, filter domain.AdminUserFilter) (domain.AdminUserList, error)
+	User(ctx context.Context, userID uint) (domain.AdminUser, error)
+	SetDisabled(ctx context.Context, adminID uint, userID uint, disabled bool) error
+	Logout(ctx context.Context, adminID uint, userID uint) (int64, error)
+	SetRole(ctx context.Context, adminID uint, userID uint, req domain.UpdateRole) error
+}
+
+type AdminHandlers struct {
+	s   AdminServic
+	log *logrus.Logger
+	ctx context.Context
+}
+
+func CreateAdminHandlers(s AdminServic, log *logrus.Logger, ctx context.Context) *AdminHandlers {
+	return &AdminHandlers{
+		s:   s,
+		log: log,
+		ctx: ctx,
+	}
+}
+
+// ListUsers godoc
+//
+//	@Summary		Поиск пользователей
+//	@Description	Пользователи от новых к старым с поиском по части имени или email, фильтрами по роли и блокировке и общим количеством найденных
+//
+//	@Tags			Admin
+//
+//	@Produce		json
+//	@Param			q			query		string				false	"часть имени или email"
+//	@Param			role		query		string				false	"роль: user, admin"
+//	@Param			disabled	query		bool				false	"заблокирован ли аккаунт"
+//	@Param			limit		query		int					false	"количество пользователей"	default(20)
+//	@Param			offset		query		int					false	"смещение"					default(0)
+//	@Success		200			{object}	api.SuccessResponse	"Пользователи"
+//
+//	@Failure		400			{object}	api.ErrorResponse	"Некорректные входные данные"
+//	@Failure		401			{object}	api.ErrorResponse	"Ошибка авторизации"
+//	@Failure		403			{object}	api.ErrorResponse	"Нужна роль admin"
+//	@Failure		500			{object}	api.ErrorResponse	"Ошибка сервера"
+//
+//	@Router			/admin/users [get]
+//
+//	@Security		jwtAuth
+func (h *AdminHandlers) ListUsers(c *gin.Context) {
+	const op = "handlers.ListUsers"
+
+	log := h.log.WithField("op", op)
+
+	log.Info("start list users")
+
+	var req RequestUsers
+	if err := c.ShouldBindQuery(&req); err != nil {
+		log.WithField("err", err).Error("error valid query")
+		api.ResponseError(c, http.StatusBadRequest, "error valid query")
+		return
+	}
+
+	list, err := h.s.Users(c.Request.Context(), domain.AdminUserFilter{
+		Query:    req.Query,
+		Role:     req.Role,
+		Disabled: req.Disabled,
+		Limit:    req.Limit,
+		Offset:   req.Offset,
+	})
+	if err != nil {
+		log.WithField("err", err).Error("error list users")
+		api.RegistrationError(c, err)
+		return
+	}
+
+	log.Info("success list users")
+
+	api.ResponseOK(c, list)
+}
+
+// GetUser godoc
+//
+//	@Summary		Пользователь
+//	@Description	Пользователь с количеством его категорий, транзакций, активных сессий, токенов доступа и экспортов
+//
+//	@Tags			Admin
+//
+//	@Produce		json
+//	@Param			id	path		int					true	"id пользователя"
+//	@Success		200	{object}	api.SuccessResponse	"Пользователь"
+//
+//	@Failure		400	{object}	api.ErrorResponse	"Некорректные входные данные"
+//	@Failure		401	{object}	api.ErrorResponse	"Ошибка авторизации"
+//	@Failure		403	{object}	api.ErrorResponse	"Нужна роль admin"
+//	@Failure		404	{object}	api.ErrorResponse	"Пользователь не найден"
+//	@Failure		500	{object}	api.ErrorResponse	"Ошибка сервера"
+//
+//	@Router			/admin/users/{id} [get]
+//
+//	@Security		jwtAuth
+func (h *AdminHandlers) GetUser(c *gin.Context) {
+	const op = "handlers.GetUser"
+
+	log := h.log.WithField("op", op)
+
+	log.Info("start get user")
+
+	_, userID, ok := ids(c, log)
+	if !ok {
+		return
+	}
+
+	user, err := h.s.User(c.Request.Context(), userID)
+	if err != nil {
+		log.WithField("err", err).Error("error get user")
+		api.RegistrationError(c, err)
+		return
+	}
+
+	log.Info("success get user")
+
+	api.ResponseOK(c, user)
+}
+
+// DisableUser godoc
+//
+//	@Summary		Блокировка пользователя
+//	@Description	Блокировка аккаунта: пользователь не может войти, его сессии завершаются, токены доступа перестают действовать
+//
+//	@Tags			Admin
+//
+//	@Produce		json
+//	@Param			id	path		int					true	"id пользователя"
+//	@Success		200	{object}	api.SuccessResponse	"Пользователь заблокирован"
+//
+//	@Failure		400	{object}	api.ErrorResponse	"Некорректные входные данные"
+//	@Failure		401	{object}	api.ErrorResponse	"Ошибка авторизации"
+//	@Failure		403	{object}	api.ErrorResponse	"Нужна роль admin"
+//	@Failure		404	{object}	api.ErrorResponse	"Пользователь не найден"
+//	@Failure		500	{object}	api.ErrorResponse	"Ошибка сервера"
+//
+//	@Router			/admin/users/{id}/disable [post]
+//
+//	@Security		jwtAuth
+func (h *AdminHandlers) DisableUser(c *gin.Context) {
+	h.setDisabled(c, true)
+}
+
+// EnableUser godoc
+//
+//	@Summary		Разблокировка пользователя
+//	@Description	Разблокировка аккаунта, завершенные при блокировке сессии не восстанавливаются
+//
+//	@Tags			Admin
+//
+//	@Produce		json
+//	@Param			id	path		int					true	"id пользователя"
+//	@Success		200	{object}	api.SuccessResponse	"Пользователь разблокирован"
+//
+//	@Failure		400	{object}	api.ErrorResponse	"Некорректные входные данные"
+//	@Failure		401	{object}	api.ErrorResponse	"Ошибка авторизации"
+//	@Failure		403	{object}	api.ErrorResponse	"Нужна роль admin"
+//	@Failure		404	{object}	api.ErrorResponse	"Пользователь не найден"
+//	@Failure		500	{object}	api.ErrorResponse	"Ошибка сервера"
+//
+//	@Router			/admin/users/{id}/enable [post]
+//
+//	@Security		jwtAuth
+func (h *AdminHandlers) EnableUser(c *gin.Context) {
+	h.setDisabled(c, false)
+}
+
+func (h *AdminHandlers) setDisabled(c *gin.Context, disabled bool) {
+	const op = "handlers.SetDisabled"
+
+	log := h.log.WithFields(logrus.Fields{
+		"op":       op,
+		"disabled": disabled,
+	})
+
+	log.Info("start set disabled")
+
+	adminID, userID, ok := ids(c, log)
+	if !ok {
+		return
+	}
+
+	if err := h.s.SetDisabled(c.Request.Context(), adminID, userID, disabled); err != nil {
+		log.WithField("err", err).Error("error set disabled")
+		api.RegistrationError(c, err)
+		return
+	}
+
+	log.Info("success set disabled")
+
+	if disabled {
+		api.ResponseOK(c, "user disabled")
+		return
+	}
+	api.ResponseOK(c, "user enabled")
+}
+
+// LogoutUser godoc
+//
+//	@Summary		Завершение сессий пользователя
+//	@Description	Завершение всех сессий пользователя, возвращает количество завершенных. Токены доступа не отзываются
+//
+//	@Tags			Admin
+//
+//	@Produce		json
+//	@Param			id	path		int					true	"id пользователя"
+//	@Success		200	{object}	api.SuccessResponse	"Количество завершенных сессий"
+//
+//	@Failure		400	{object}	api.ErrorResponse	"Некорректные входные данные"
+//	@Failure		401	{object}	api.ErrorResponse	"Ошибка авторизации"
+//	@Failure		403	{object}	api.ErrorResponse	"Нужна роль admin"
+//	@Failure		404	{object}	api.ErrorResponse	"Пользователь не найден"
+//	@Failure		500	{object}	api.ErrorResponse	"Ошибка сервера"
+//
+//	@Router			/admin/users/{id}/logout [post]
+//
+//	@Security		jwtAuth
+func (h *AdminHandlers) LogoutUser(c *gin.Context) {
+	const op = "handlers.LogoutUser"
+
+	log := h.log.WithField("op", op)
+
+	log.Info("start logout user")
+
+	adminID, userID, ok := ids(c, log)
+	if !ok {
+		return
+	}
+
+	revoked, err := h.s.Logout(c.Request.Context(), adminID, userID)
+	if err != nil {
+		log.WithField("err", err).Error("error logout user")
+		api.RegistrationError(c, err)
+		return
+	}
+
+	log.Info("success logout user")
+
+	api.ResponseOK(c, revoked)
+}
+
+// SetRole godoc
+//
+//	@Summary		Изменение роли
+//	@Description	Назначение пользователю роли user или admin. Администратор не может снять роль с себя
+//
+//	@Tags			Admin
+//
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		int					true	"id пользователя"
+//	@Param			req	body		RequestRole			true	"роль"
+//	@Success		200	{object}	api.SuccessResponse	"Роль изменена"
+//
+//	@Failure		400	{object}	api.ErrorResponse	"Некорректные входные данные"
+//	@Failure		401	{object}	api.ErrorResponse	"Ошибка авторизации"
+//	@Failure		403	{object}	api.ErrorResponse	"Нужна роль admin"
+//	@Failure		404	{object}	api.ErrorResponse	"Пользователь не найден"
+//	@Failure		500	{object}	api.ErrorResponse	"Ошибка сервера"
+//
+//	@Router			/admin/users/{id}/role [put]
+//
+//	@Security		jwtAuth
+func (h *AdminHandlers) SetRole(c *gin.Context) {
+	const op = "handlers.SetRole"
+
+	log := h.log.WithField("op", op)
+
+	log.Info("start set role")
+
+	var req RequestRole
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.WithField("err", err).Error("error valid JSON")
+		api.ResponseError(c, http.StatusBadRequest, "error valid JSON")
+		return
+	}
+
+	adminID, userID, ok := ids(c, log)
+	if !ok {
+		return
+	}
+
+	if err := h.s.SetRole(c.Request.Context(), adminID, userID, domain.UpdateRole{Role: req.Role}); err != nil {
+		log.WithField("err", err).Error("error set role")
+		api.RegistrationError(c, err)
+		return
+	}
+
+	log.Info("success set role")
+
+	api.ResponseOK(c, "role changed")
+}
+
+// ids returns the id of the admin making the request and the id of the user in the path,
+// on error the response is already written.
+func ids(c *gin.Context, log *logrus.Entry) (uint, uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		log.WithField("err", err).Error("error get id")
+		api.ResponseError(c, http.StatusBadRequest, "invalid user id")
+		return 0, 0, false
+	}
+
+	adminID, ok := c.Get("userID")
+	if !ok {
+		log.Error("error get userID")
+		api.ResponseError(c, http.StatusInternalServerError, "error server")
+		return 0, 0, false
+	}
+
+	return adminID.(uint), uint(id), true
+}
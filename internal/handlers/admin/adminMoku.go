@@ -0,0 +1,37 @@
+package adminHandlers
+
+import (
+	"context"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type adminServicMock struct {
+	mock.Mock
+}
+
+func (m *adminServicMock) Users(ctx context.Context, filter domain.AdminUserFilter) (domain.AdminUserList, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).(domain.AdminUserList), args.Error(1)
+}
+
+func (m *adminServicMock) User(ctx context.Context, userID uint) (domain.AdminUser, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(domain.AdminUser), args.Error(1)
+}
+
+func (m *adminServicMock) SetDisabled(ctx context.Context, adminID uint, userID uint, disabled bool) error {
+	args := m.Called(ctx, adminID, userID, disabled)
+	return args.Error(0)
+}
+
+func (m *adminServicMock) Logout(ctx context.Context, adminID uint, userID uint) (int64, error) {
+	args := m.Called(ctx, adminID, userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *adminServicMock) SetRole(ctx context.Context, adminID uint, userID uint, req domain.UpdateRole) error {
+	args := m.Called(ctx, adminID, userID, req)
+	return args.Error(0)
+}
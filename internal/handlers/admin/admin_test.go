@@ -0,0 +1,211 @@
+package adminHandlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/financial_tracer/internal/servic/admin"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/assert/v2"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestListUsers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	disabled := true
+
+	tests := []struct {
+		name         string
+		query        string
+		filter       domain.AdminUserFilter
+		mockErr      error
+		status       int
+		shouldCallDB bool
+	}{
+		{
+			name:         "success",
+			query:        "q=jonn&disabled=true&limit=10",
+			filter:       domain.AdminUserFilter{Query: "jonn", Disabled: &disabled, Limit: 10},
+			status:       http.StatusOK,
+			shouldCallDB: true,
+		},
+		{
+			name:         "error database",
+			filter:       domain.AdminUserFilter{},
+			mockErr:      admin.ErrDatabase,
+			status:       http.StatusInternalServerError,
+			shouldCallDB: true,
+		},
+		{
+			name:   "error query",
+			query:  "limit=ten",
+			status: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Set("userID", uint(1))
+
+			svc := new(adminServicMock)
+			ctx := context.Background()
+			svc.On("Users", mock.Anything, tc.filter).Return(domain.AdminUserList{}, tc.mockErr)
+
+			h := CreateAdminHandlers(svc, logrus.New(), ctx)
+
+			req := http.Request{Header: make(http.Header), URL: &url.URL{RawQuery: tc.query}}
+			c.Request = req.WithContext(ctx)
+
+			h.ListUsers(c)
+
+			assert.Equal(t, tc.status, w.Code)
+			if tc.shouldCallDB {
+				svc.AssertExpectations(t)
+			} else {
+				svc.AssertNotCalled(t, "Users", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestDisableUser(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name         string
+		id           string
+		mockErr      error
+		status       int
+		shouldCallDB bool
+	}{
+		{
+			name:         "success",
+			id:           "3",
+			status:       http.StatusOK,
+			shouldCallDB: true,
+		},
+		{
+			name:         "error self",
+			id:           "3",
+			mockErr:      admin.ErrSelf,
+			status:       http.StatusBadRequest,
+			shouldCallDB: true,
+		},
+		{
+			name:         "error not found",
+			id:           "3",
+			mockErr:      admin.ErrNoFound,
+			status:       http.StatusNotFound,
+			shouldCallDB: true,
+		},
+		{
+			name:   "error id",
+			id:     "three",
+			status: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Set("userID", uint(1))
+			c.Params = gin.Params{{Key: "id", Value: tc.id}}
+
+			svc := new(adminServicMock)
+			ctx := context.Background()
+			svc.On("SetDisabled", mock.Anything, uint(1), uint(3), true).Return(tc.mockErr)
+
+			h := CreateAdminHandlers(svc, logrus.New(), ctx)
+
+			req := http.Request{Header: make(http.Header), URL: &url.URL{}}
+			c.Request = req.WithContext(ctx)
+
+			h.DisableUser(c)
+
+			assert.Equal(t, tc.status, w.Code)
+			if tc.shouldCallDB {
+				svc.AssertExpectations(t)
+			} else {
+				svc.AssertNotCalled(t, "SetDisabled", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestSetRole(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name         string
+		body         RequestRole
+		invalidJSON  bool
+		mockErr      error
+		status       int
+		shouldCallDB bool
+	}{
+		{
+			name:         "success",
+			body:         RequestRole{Role: domain.RoleAdmin},
+			status:       http.StatusOK,
+			shouldCallDB: true,
+		},
+		{
+			name:         "error demote self",
+			body:         RequestRole{Role: domain.RoleUser},
+			mockErr:      admin.ErrSelf,
+			status:       http.StatusBadRequest,
+			shouldCallDB: true,
+		},
+		{
+			name:        "error json",
+			invalidJSON: true,
+			status:      http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Set("userID", uint(1))
+			c.Params = gin.Params{{Key: "id", Value: "3"}}
+
+			svc := new(adminServicMock)
+			ctx := context.Background()
+			svc.On("SetRole", mock.Anything, uint(1), uint(3), domain.UpdateRole{Role: tc.body.Role}).Return(tc.mockErr)
+
+			h := CreateAdminHandlers(svc, logrus.New(), ctx)
+
+			req := http.Request{Header: make(http.Header), URL: &url.URL{}}
+			if tc.invalidJSON {
+				req.Body = io.NopCloser(bytes.NewBufferString("{"))
+			} else {
+				b, _ := json.Marshal(tc.body)
+				req.Body = io.NopCloser(bytes.NewBuffer(b))
+			}
+			req.Header.Set("content-type", "application/json")
+			c.Request = req.WithContext(ctx)
+
+			h.SetRole(c)
+
+			assert.Equal(t, tc.status, w.Code)
+			if tc.shouldCallDB {
+				svc.AssertExpectations(t)
+			} else {
+				svc.AssertNotCalled(t, "SetRole", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
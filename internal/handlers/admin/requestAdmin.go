@@ -0,0 +1,15 @@
+package adminHandlers
+
+// RequestUsers represents search users request, q is a part of the name or email
+type RequestUsers struct {
+	Query    string `form:"q" example:"jonn"`
+	Role     string `form:"role" example:"admin"`
+	Disabled *bool  `form:"disabled" example:"true"`
+	Limit    int    `form:"limit" example:"20"`
+	Offset   int    `form:"offset" example:"0"`
+}
+
+// RequestRole represents change role request, role is user or admin
+type RequestRole struct {
+	Role string `json:"role" binding:"required" example:"admin"`
+}
@@ -0,0 +1,26 @@
+package api
+
+import (
+	"strings"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+// maxUserAgent is the size of the user agent column of a session.
+const maxUserAgent = 255
+
+// Device returns the client of the request as recorded in its session. The address comes from
+// X-Forwarded-For only when the request passed a trusted proxy of the engine (see
+// HTTPServer.TrustedProxies), otherwise it is the remote address, so clients can not forge it.
+func Device(c *gin.Context) domain.Device {
+	ua := c.Request.UserAgent()
+	if len(ua) > maxUserAgent {
+		ua = strings.ToValidUTF8(ua[:maxUserAgent], "")
+	}
+
+	return domain.Device{
+		UserAgent: ua,
+		IP:        c.ClientIP(),
+	}
+}
@@ -0,0 +1,18 @@
+package api
+
+import (
+	"github.com/financial_tracer/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+// Member returns the member of the active household the Household middleware put into
+// the context.
+func Member(c *gin.Context) (domain.Member, bool) {
+	value, ok := c.Get("member")
+	if !ok {
+		return domain.Member{}, false
+	}
+
+	m, ok := value.(domain.Member)
+	return m, ok
+}
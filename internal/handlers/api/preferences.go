@@ -0,0 +1,56 @@
+package api
+
+import (
+	"strings"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+// Locales the messages are translated to.
+const (
+	LocaleEn = "en"
+	LocaleRu = "ru"
+)
+
+// Preferences returns the preferences of the user the Preferences middleware put into the
+// context, the defaults with the locale of the Accept-Language header otherwise.
+func Preferences(c *gin.Context) domain.Preferences {
+	if value, ok := c.Get("preferences"); ok {
+		if pref, ok := value.(domain.Preferences); ok {
+			return pref
+		}
+	}
+
+	return domain.Preferences{
+		Currency:   domain.DefaultCurrency,
+		Timezone:   domain.DefaultTimezone,
+		Locale:     acceptLanguage(c),
+		WeekStart:  domain.DefaultWeekStart,
+		MonthStart: domain.DefaultMonthStart,
+	}
+}
+
+// Locale returns the locale the response messages are written in.
+func Locale(c *gin.Context) string {
+	return Preferences(c).Locale
+}
+
+// acceptLanguage returns the first supported language of the Accept-Language header,
+// the preference weights are not looked at.
+func acceptLanguage(c *gin.Context) string {
+	if c.Request == nil {
+		return domain.DefaultLocale
+	}
+
+	for _, tag := range strings.Split(c.GetHeader("Accept-Language"), ",") {
+		tag, _, _ = strings.Cut(strings.TrimSpace(tag), ";")
+		lang, _, _ := strings.Cut(strings.ToLower(tag), "-")
+		switch lang {
+		case LocaleEn, LocaleRu:
+			return lang
+		}
+	}
+
+	return domain.DefaultLocale
+}
@@ -0,0 +1,116 @@
+package api
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"net/http"
+	"time"
+
+	jwttoken "github.com/financial_tracer/internal/lib/jwtToken"
+	"github.com/gin-gonic/gin"
+)
+
+// Cookie session mode: a browser client sends SessionModeHeader with the login, the
+// tokens are then set as HttpOnly cookies instead of being returned, and the CSRF token
+// of CSRFCookie must come back in CSRFHeader with every state-changing request.
+const (
+	SessionModeHeader = "X-Session-Mode"
+	SessionModeCookie = "cookie"
+	CSRFHeader        = "X-CSRF-Token"
+
+	AccessCookie  = "ft_access"
+	RefreshCookie = "ft_refresh"
+	CSRFCookie    = "ft_csrf"
+)
+
+// Cookies describe the session cookies. Path is where the access and CSRF cookies are
+// sent, RefreshPath where the refresh cookie is, so it only travels to the token refresh.
+type Cookies struct {
+	Domain      string
+	Path        string
+	RefreshPath string
+	Secure      bool
+	SameSite    http.SameSite
+}
+
+// CookieSession is the response of a login in cookie mode.
+type CookieSession struct {
+	CSRFToken string `json:"csrf_token"`
+}
+
+// SessionCookies returns the cookies the Cookies middleware put into the context, ok is
+// false when the cookie mode is off.
+func SessionCookies(c *gin.Context) (Cookies, bool) {
+	value, ok := c.Get("cookies")
+	if !ok {
+		return Cookies{}, false
+	}
+
+	cookies, ok := value.(Cookies)
+	return cookies, ok
+}
+
+// CookieMode reports whether the client asked for the cookie mode and it is on.
+func CookieMode(c *gin.Context) bool {
+	_, ok := SessionCookies(c)
+	return ok && c.GetHeader(SessionModeHeader) == SessionModeCookie
+}
+
+// ResponseTokens writes the tokens of a login. In cookie mode they are set as cookies and
+// only the CSRF token is returned; a two-factor challenge is returned as it is.
+func ResponseTokens(c *gin.Context, tokens jwttoken.ResponseJWTUser) {
+	cookies, ok := SessionCookies(c)
+	if !ok || !CookieMode(c) || tokens.ChallengeToken != "" {
+		ResponseOK(c, tokens)
+		return
+	}
+
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		ResponseError(c, http.StatusInternalServerError, "server error")
+		return
+	}
+	csrf := base64.RawURLEncoding.EncodeToString(b)
+
+	c.SetSameSite(cookies.SameSite)
+	c.SetCookie(AccessCookie, tokens.AccessToken, int(jwttoken.AccessTTL/time.Second), cookies.Path, cookies.Domain, cookies.Secure, true)
+	c.SetCookie(RefreshCookie, tokens.RefreshToken, int(jwttoken.RefreshTTL/time.Second), cookies.RefreshPath, cookies.Domain, cookies.Secure, true)
+	// readable by the frontend, it sends the value back in CSRFHeader
+	c.SetCookie(CSRFCookie, csrf, int(jwttoken.RefreshTTL/time.Second), cookies.Path, cookies.Domain, cookies.Secure, false)
+
+	ResponseOK(c, CookieSession{CSRFToken: csrf})
+}
+
+// ClearSession deletes the session cookies.
+func ClearSession(c *gin.Context) {
+	cookies, ok := SessionCookies(c)
+	if !ok {
+		return
+	}
+
+	c.SetSameSite(cookies.SameSite)
+	c.SetCookie(AccessCookie, "", -1, cookies.Path, cookies.Domain, cookies.Secure, true)
+	c.SetCookie(RefreshCookie, "", -1, cookies.RefreshPath, cookies.Domain, cookies.Secure, true)
+	c.SetCookie(CSRFCookie, "", -1, cookies.Path, cookies.Domain, cookies.Secure, false)
+}
+
+// CheckCSRF reports whether CSRFHeader matches the CSRF cookie (double-submit): another
+// site can make the browser send the cookies, but can't read them to set the header.
+func CheckCSRF(c *gin.Context) bool {
+	cookie, err := c.Cookie(CSRFCookie)
+	if err != nil || cookie == "" {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare([]byte(cookie), []byte(c.GetHeader(CSRFHeader))) == 1
+}
+
+// SafeMethod reports whether the request does not change state and needs no CSRF token.
+func SafeMethod(c *gin.Context) bool {
+	switch c.Request.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
+}
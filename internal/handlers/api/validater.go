@@ -4,10 +4,30 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 
+	"github.com/financial_tracer/internal/lib/passwordPolicy"
+	"github.com/financial_tracer/internal/servic/accesstoken"
+	"github.com/financial_tracer/internal/servic/admin"
 	"github.com/financial_tracer/internal/servic/category"
+	"github.com/financial_tracer/internal/servic/comparison"
+	"github.com/financial_tracer/internal/servic/deletion"
+	"github.com/financial_tracer/internal/servic/export"
+	"github.com/financial_tracer/internal/servic/forecast"
+	"github.com/financial_tracer/internal/servic/household"
+	"github.com/financial_tracer/internal/servic/invite"
+	"github.com/financial_tracer/internal/servic/ledger"
+	"github.com/financial_tracer/internal/servic/lockout"
+	"github.com/financial_tracer/internal/servic/password"
+	"github.com/financial_tracer/internal/servic/profile"
+	"github.com/financial_tracer/internal/servic/search"
+	"github.com/financial_tracer/internal/servic/security"
+	"github.com/financial_tracer/internal/servic/sso"
 	"github.com/financial_tracer/internal/servic/transaction"
+	"github.com/financial_tracer/internal/servic/twofactor"
 	"github.com/financial_tracer/internal/servic/user"
+	"github.com/financial_tracer/internal/servic/verification"
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
 )
@@ -18,12 +38,18 @@ type errInfo struct {
 }
 
 func RegistrationError(c *gin.Context, err error) {
-	errMessage := ValidationError(err)
+	errMessage := LocalizedValidationError(err, Locale(c))
 	if len(errMessage) != 0 {
 		ResponseError(c, http.StatusBadRequest, errMessage)
 		return
 	}
 
+	var locked *lockout.LockedError
+	if errors.As(err, &locked) {
+		c.Header("Retry-After", strconv.Itoa(locked.Seconds()))
+		err = lockout.ErrLocked
+	}
+
 	value := validateClientsErrors(err)
 	fmt.Println(value)
 	prov := errInfo{}
@@ -37,13 +63,37 @@ func RegistrationError(c *gin.Context, err error) {
 }
 
 func ValidationError(err error) []map[string]string {
+	return LocalizedValidationError(err, LocaleEn)
+}
+
+// ruMessages are the validation messages in russian by tag, the parameter of the tag is
+// the second argument.
+var ruMessages = map[string]string{
+	"required": "поле %s обязательно",
+	"email":    "поле %s должно быть email адресом",
+	"min":      "поле %s должно быть не меньше %s",
+	"max":      "поле %s должно быть не больше %s",
+	"oneof":    "поле %s должно быть одним из: %s",
+	"gtfield":  "поле %s должно быть больше поля %s",
+	"iso4217":  "поле %s должно быть кодом валюты ISO 4217",
+	"timezone": "поле %s должно быть часовым поясом IANA, например Europe/Moscow",
+	"nefield":  "поле %s должно отличаться от поля %s",
+
+	passwordPolicy.TagLength:   "длина пароля в поле %s не соответствует политике паролей",
+	passwordPolicy.TagClasses:  "пароль в поле %s должен содержать больше видов символов: строчные и заглавные буквы, цифры, другие символы",
+	passwordPolicy.TagPersonal: "пароль в поле %s не должен содержать имя или email",
+	passwordPolicy.TagBreached: "пароль в поле %s найден в утечках, выберите другой",
+}
+
+// LocalizedValidationError is ValidationError with the messages in the locale.
+func LocalizedValidationError(err error, locale string) []map[string]string {
 	var validError validator.ValidationErrors
 	if errors.As(err, &validError) {
 		var errMessage []map[string]string
 		for _, errs := range validError {
 			detail := map[string]string{
 				"field":   errs.Field(),
-				"message": fmt.Sprintf(" Field validation for %s, field on the tag: %s", errs.Field(), errs.Tag()),
+				"message": validationMessage(errs, locale),
 			}
 
 			errMessage = append(errMessage, detail)
@@ -55,6 +105,21 @@ func ValidationError(err error) []map[string]string {
 	return []map[string]string{}
 }
 
+func validationMessage(err validator.FieldError, locale string) string {
+	if locale != LocaleRu {
+		return fmt.Sprintf(" Field validation for %s, field on the tag: %s", err.Field(), err.Tag())
+	}
+
+	format, ok := ruMessages[err.Tag()]
+	if !ok {
+		return fmt.Sprintf("поле %s не прошло проверку %s", err.Field(), err.Tag())
+	}
+	if strings.Count(format, "%s") == 1 {
+		return fmt.Sprintf(format, err.Field())
+	}
+	return fmt.Sprintf(format, err.Field(), err.Param())
+}
+
 func validateClientsErrors(err error) errInfo {
 
 	arr := map[error]errInfo{
@@ -68,6 +133,11 @@ func validateClientsErrors(err error) errInfo {
 			message: "transaction is not found",
 		},
 
+		transaction.ErrAnomalyStatus: {
+			code:    http.StatusBadRequest,
+			message: "invalid anomaly status",
+		},
+
 		category.ErrValidateType: {
 			code:    http.StatusBadRequest,
 			message: "param is not valid",
@@ -103,6 +173,56 @@ func validateClientsErrors(err error) errInfo {
 			message: "server error",
 		},
 
+		user.ErrPassword: {
+			code:    http.StatusUnauthorized,
+			message: "wrong email or password",
+		},
+
+		user.ErrDisabled: {
+			code:    http.StatusForbidden,
+			message: "account is disabled",
+		},
+
+		lockout.ErrLocked: {
+			code:    http.StatusTooManyRequests,
+			message: "too many failed attempts, try again later",
+		},
+
+		user.ErrToken: {
+			code:    http.StatusUnauthorized,
+			message: "invalid refresh token",
+		},
+
+		user.ErrTokenReused: {
+			code:    http.StatusUnauthorized,
+			message: "refresh token already used, session revoked",
+		},
+
+		user.ErrSessionNotFound: {
+			code:    http.StatusNotFound,
+			message: "session is not found",
+		},
+
+		user.ErrChallenge: {
+			code:    http.StatusUnauthorized,
+			message: "invalid or expired two-factor challenge",
+		},
+
+		user.ErrRegistrationClosed: {
+			code:    http.StatusForbidden,
+			message: "registration is closed",
+		},
+
+		user.ErrEmailDomain: {
+			code:    http.StatusForbidden,
+			message: "registration is not allowed for the email domain",
+		},
+
+		user.ErrInvite: {
+			code:    http.StatusBadRequest,
+			message: "invalid or expired invite code",
+		},
+
 		category.ErrDatabase: {
 			code:    http.StatusInternalServerError,
 			message: "server error",
@@ -112,6 +232,386 @@ func validateClientsErrors(err error) errInfo {
 			code:    http.StatusInternalServerError,
 			message: "server error",
 		},
+
+		ledger.ErrFormat: {
+			code:    http.StatusBadRequest,
+			message: "unknown journal format",
+		},
+
+		ledger.ErrParse: {
+			code:    http.StatusBadRequest,
+			message: "invalid journal",
+		},
+
+		ledger.ErrDuplicated: {
+			code:    http.StatusBadRequest,
+			message: "category belongs to another household",
+		},
+
+		ledger.ErrNoFound: {
+			code:    http.StatusNotFound,
+			message: "user is not found",
+		},
+
+		ledger.ErrDatabase: {
+			code:    http.StatusInternalServerError,
+			message: "server error",
+		},
+
+		forecast.ErrDatabase: {
+			code:    http.StatusInternalServerError,
+			message: "server error",
+		},
+
+		search.ErrDatabase: {
+			code:    http.StatusInternalServerError,
+			message: "server error",
+		},
+
+		comparison.ErrDatabase: {
+			code:    http.StatusInternalServerError,
+			message: "server error",
+		},
+
+		password.ErrPassword: {
+			code:    http.StatusUnauthorized,
+			message: "wrong password",
+		},
+
+		password.ErrResetToken: {
+			code:    http.StatusBadRequest,
+			message: "invalid or expired reset token",
+		},
+
+		password.ErrNoFound: {
+			code:    http.StatusNotFound,
+			message: "user is not found",
+		},
+
+		password.ErrServic: {
+			code:    http.StatusInternalServerError,
+			message: "server error",
+		},
+
+		password.ErrDatabase: {
+			code:    http.StatusInternalServerError,
+			message: "server error",
+		},
+
+		verification.ErrVerified: {
+			code:    http.StatusBadRequest,
+			message: "email already verified",
+		},
+
+		verification.ErrVerifyToken: {
+			code:    http.StatusBadRequest,
+			message: "invalid or expired verification token",
+		},
+
+		verification.ErrNoFound: {
+			code:    http.StatusNotFound,
+			message: "user is not found",
+		},
+
+		verification.ErrMail: {
+			code:    http.StatusInternalServerError,
+			message: "error send mail",
+		},
+
+		verification.ErrServic: {
+			code:    http.StatusInternalServerError,
+			message: "server error",
+		},
+
+		verification.ErrDatabase: {
+			code:    http.StatusInternalServerError,
+			message: "server error",
+		},
+
+		profile.ErrNoFound: {
+			code:    http.StatusNotFound,
+			message: "user is not found",
+		},
+
+		profile.ErrPassword: {
+			code:    http.StatusUnauthorized,
+			message: "wrong password",
+		},
+
+		profile.ErrDuplicated: {
+			code:    http.StatusBadRequest,
+			message: "this email is already registered",
+		},
+
+		profile.ErrPasswordRequired: {
+			code:    http.StatusBadRequest,
+			message: "password is required to change the email",
+		},
+
+		profile.ErrNothingToUpdate: {
+			code:    http.StatusBadRequest,
+			message: "nothing to update",
+		},
+
+		profile.ErrDatabase: {
+			code:    http.StatusInternalServerError,
+			message: "server error",
+		},
+
+		twofactor.ErrEnabled: {
+			code:    http.StatusBadRequest,
+			message: "two-factor authentication already enabled",
+		},
+
+		twofactor.ErrDisabled: {
+			code:    http.StatusBadRequest,
+			message: "two-factor authentication is not enabled",
+		},
+
+		twofactor.ErrCode: {
+			code:    http.StatusUnauthorized,
+			message: "invalid two-factor code",
+		},
+
+		twofactor.ErrRecoveryCode: {
+			code:    http.StatusUnauthorized,
+			message: "invalid recovery code",
+		},
+
+		twofactor.ErrNoFound: {
+			code:    http.StatusNotFound,
+			message: "user is not found",
+		},
+
+		twofactor.ErrServic: {
+			code:    http.StatusInternalServerError,
+			message: "server error",
+		},
+
+		twofactor.ErrDatabase: {
+			code:    http.StatusInternalServerError,
+			message: "server error",
+		},
+
+		export.ErrNoFound: {
+			code:    http.StatusNotFound,
+			message: "export is not found",
+		},
+
+		export.ErrLink: {
+			code:    http.StatusForbidden,
+			message: "invalid or expired download link",
+		},
+
+		export.ErrServic: {
+			code:    http.StatusInternalServerError,
+			message: "server error",
+		},
+
+		export.ErrDatabase: {
+			code:    http.StatusInternalServerError,
+			message: "server error",
+		},
+
+		accesstoken.ErrNoFound: {
+			code:    http.StatusNotFound,
+			message: "access token is not found",
+		},
+
+		accesstoken.ErrTooMany: {
+			code:    http.StatusBadRequest,
+			message: "too many access tokens, revoke unused ones",
+		},
+
+		accesstoken.ErrToken: {
+			code:    http.StatusUnauthorized,
+			message: "invalid token",
+		},
+
+		accesstoken.ErrServic: {
+			code:    http.StatusInternalServerError,
+			message: "server error",
+		},
+
+		accesstoken.ErrDatabase: {
+			code:    http.StatusInternalServerError,
+			message: "server error",
+		},
+
+		admin.ErrNoFound: {
+			code:    http.StatusNotFound,
+			message: "user is not found",
+		},
+
+		admin.ErrSelf: {
+			code:    http.StatusBadRequest,
+			message: "admins can't disable or demote themselves",
+		},
+
+		admin.ErrDatabase: {
+			code:    http.StatusInternalServerError,
+			message: "server error",
+		},
+
+		category.ErrForbidden: {
+			code:    http.StatusForbidden,
+			message: "no access to the household",
+		},
+
+		transaction.ErrForbidden: {
+			code:    http.StatusForbidden,
+			message: "no access to the household",
+		},
+
+		ledger.ErrForbidden: {
+			code:    http.StatusForbidden,
+			message: "no access to the household",
+		},
+
+		forecast.ErrForbidden: {
+			code:    http.StatusForbidden,
+			message: "no access to the household",
+		},
+
+		search.ErrForbidden: {
+			code:    http.StatusForbidden,
+			message: "no access to the household",
+		},
+
+		comparison.ErrForbidden: {
+			code:    http.StatusForbidden,
+			message: "no access to the household",
+		},
+
+		household.ErrNoFound: {
+			code:    http.StatusNotFound,
+			message: "household is not found",
+		},
+
+		household.ErrForbidden: {
+			code:    http.StatusForbidden,
+			message: "no access to the household",
+		},
+
+		household.ErrPersonal: {
+			code:    http.StatusBadRequest,
+			message: "personal household can't be shared",
+		},
+
+		household.ErrDuplicated: {
+			code:    http.StatusBadRequest,
+			message: "user is already a member of the household",
+		},
+
+		household.ErrInvitation: {
+			code:    http.StatusBadRequest,
+			message: "invalid or expired invitation",
+		},
+
+		household.ErrMail: {
+			code:    http.StatusInternalServerError,
+			message: "server error",
+		},
+
+		household.ErrServic: {
+			code:    http.StatusInternalServerError,
+			message: "server error",
+		},
+
+		household.ErrDatabase: {
+			code:    http.StatusInternalServerError,
+			message: "server error",
+		},
+
+		sso.ErrProvider: {
+			code:    http.StatusNotFound,
+			message: "unknown identity provider",
+		},
+
+		sso.ErrState: {
+			code:    http.StatusBadRequest,
+			message: "invalid or expired login state",
+		},
+
+		sso.ErrLogin: {
+			code:    http.StatusUnauthorized,
+			message: "identity provider rejected the login",
+		},
+
+		sso.ErrUnavailable: {
+			code:    http.StatusBadGateway,
+			message: "identity provider is unavailable",
+		},
+
+		sso.ErrUnverified: {
+			code:    http.StatusForbidden,
+			message: "email is not verified by the identity provider",
+		},
+
+		sso.ErrDisabled: {
+			code:    http.StatusForbidden,
+			message: "account is disabled",
+		},
+
+		sso.ErrRegistration: {
+			code:    http.StatusForbidden,
+			message: "registration of new users is not allowed",
+		},
+
+		sso.ErrUnlinked: {
+			code:    http.StatusConflict,
+			message: "sign in with the password and verify the email of the account first",
+		},
+
+		sso.ErrServic: {
+			code:    http.StatusInternalServerError,
+			message: "server error",
+		},
+
+		sso.ErrDatabase: {
+			code:    http.StatusInternalServerError,
+			message: "server error",
+		},
+
+		security.ErrDatabase: {
+			code:    http.StatusInternalServerError,
+			message: "server error",
+		},
+
+		invite.ErrNoFound: {
+			code:    http.StatusNotFound,
+			message: "invite code is not found",
+		},
+
+		invite.ErrServic: {
+			code:    http.StatusInternalServerError,
+			message: "server error",
+		},
+
+		invite.ErrDatabase: {
+			code:    http.StatusInternalServerError,
+			message: "server error",
+		},
+
+		deletion.ErrNoFound: {
+			code:    http.StatusNotFound,
+			message: "user is not found",
+		},
+
+		deletion.ErrPassword: {
+			code:    http.StatusBadRequest,
+			message: "wrong email or password",
+		},
+
+		deletion.ErrDisabled: {
+			code:    http.StatusForbidden,
+			message: "account is disabled",
+		},
+
+		deletion.ErrDatabase: {
+			code:    http.StatusInternalServerError,
+			message: "server error",
+		},
 	}
 
 	value, ok := arr[err]
@@ -12,30 +12,30 @@ import (
 )
 
 type ServicCategoryer interface {
-	CreateCategory(ctx context.Context, idUser uint, category domain.CategoryInput) (uint, error)
-	ReadCategory(ctx context.Context, idCategory uint) (domain.CategoryOutput, error)
-	UpdateCategory(ctx context.Context, idCategory uint, newCategory domain.CategoryInput) (domain.CategoryOutput, error)
-	DeleteCategory(ctx context.Context, idCategory uint) error
+	CreateCategory(ctx context.Context, member domain.Member, category domain.CategoryInput) (uint, error)
+	ReadCategory(ctx context.Context, member domain.Member, idCategory uint) (domain.CategoryOutput, error)
+	UpdateCategory(ctx context.Context, member domain.Member, idCategory uint, newCategory domain.CategoryInput) (domain.CategoryOutput, error)
+	DeleteCategory(ctx context.Context, member domain.Member, idCategory uint) error
 }
 
 type CreateCategoryServic interface {
-	CreateCategory(ctx context.Context, idUser uint, category domain.CategoryInput) (uint, error)
+	CreateCategory(ctx context.Context, member domain.Member, category domain.CategoryInput) (uint, error)
 }
 
 type GetCategoryServic interface {
-	GetCategory(ctx context.Context, idCategory uint) (domain.CategoryOutput, error)
+	GetCategory(ctx context.Context, member domain.Member, idCategory uint) (domain.CategoryOutput, error)
 }
 
 type UpdateCategoryServic interface {
-	UpdateCategory(ctx context.Context, idCategory uint, newCategory domain.CategoryInput) (domain.CategoryOutput, error)
+	UpdateCategory(ctx context.Context, member domain.Member, idCategory uint, newCategory domain.CategoryInput) (domain.CategoryOutput, error)
 }
 
 type DeleteCategoryServic interface {
-	DeleteCategory(ctx context.Context, idCategory uint) error
+	DeleteCategory(ctx context.Context, member domain.Member, idCategory uint) error
 }
 
 type CategoryTypeServic interface {
-	CategoryType(ctx context.Context, typeFound string) ([]domain.CategoryOutput, error)
+	CategoryType(ctx context.Context, member domain.Member, typeFound string) ([]domain.CategoryOutput, error)
 }
 
 type CategoryHandlers struct {
@@ -74,15 +74,18 @@ func CreateHandlersCategory(c CreateCategoryServic,
 //	@Accept			json
 //	@Produce		json
 //
-//	@Param			req	body		RequestCreateCategory	true	"данные для создание новой категории"
+//	@Param			req				body		RequestCreateCategory	true	"данные для создание новой категории"
 //
-//	@Success		200	{object}	api.SuccessResponse		"Успешное создание категории"
+//	@Param			X-Household-ID	header		int						false	"id общего бюджета, по умолчанию личный"
+//	@Success		200				{object}	api.SuccessResponse		"Успешное создание категории"
 //
-//	@Failure		401	{object}	api.ErrorResponse		"Ошибка авторизации"
-//	@Failure		400	{object}	api.ErrorResponse		"Некорректные входные данные"
-//	@Failure		400	{object}	api.ErrorResponse		"Некорректный данные"
+//	@Failure		401				{object}	api.ErrorResponse		"Ошибка авторизации"
+//	@Failure		400				{object}	api.ErrorResponse		"Некорректные входные данные"
+//	@Failure		400				{object}	api.ErrorResponse		"Некорректный данные"
 //
-//	@Failure		501	{object}	api.ErrorResponse		"Ошибка сервера"
+//	@Failure		501				{object}	api.ErrorResponse		"Ошибка сервера"
+//
+//	@Failure		403				{object}	api.ErrorResponse		"Нет доступа к бюджету"
 //
 //	@Router			/category/ [post]
 //
@@ -101,10 +104,9 @@ func (h *CategoryHandlers) PostCategory(c *gin.Context) {
 		return
 	}
 
-	idUser, ok := c.Get("userID")
-	h.log.Info(idUser)
+	member, ok := api.Member(c)
 	if !ok {
-		log.Error("error get userID")
+		log.Error("error get member")
 		api.ResponseError(c, http.StatusInternalServerError, "error server")
 		return
 	}
@@ -116,7 +118,7 @@ func (h *CategoryHandlers) PostCategory(c *gin.Context) {
 		Description: newCategory.Description,
 	}
 
-	idCategory, err := h.c.CreateCategory(h.ctx, idUser.(uint), cat)
+	idCategory, err := h.c.CreateCategory(h.ctx, member, cat)
 	if err != nil {
 		log.Error("error create category")
 		api.RegistrationError(c, err)
@@ -134,14 +136,17 @@ func (h *CategoryHandlers) PostCategory(c *gin.Context) {
 //	@Description	Получение категории для конкретного пользователя
 //	@Tags			categories
 //	@Produce		json
-//	@Param			id	path		int					true	"ID категории"
-//	@Success		200	{object}	api.SuccessResponse	"success"
+//	@Param			id				path		int					true	"ID категории"
+//	@Param			X-Household-ID	header		int					false	"id общего бюджета, по умолчанию личный"
+//	@Success		200				{object}	api.SuccessResponse	"success"
+//
+//	@Failure		401				{object}	api.ErrorResponse	"Ошибка авторизации"
+//	@Failure		400				{object}	api.ErrorResponse	"Некорректные входные данные"
+//	@Failure		500				{object}	api.ErrorResponse	"Ошибка сервера"
+//	@Failure		400				{object}	api.ErrorResponse	"Некорректный данные"
+//	@Failure		404				{object}	api.ErrorResponse	"Категория не найдена"
 //
-//	@Failure		401	{object}	api.ErrorResponse	"Ошибка авторизации"
-//	@Failure		400	{object}	api.ErrorResponse	"Некорректные входные данные"
-//	@Failure		500	{object}	api.ErrorResponse	"Ошибка сервера"
-//	@Failure		400	{object}	api.ErrorResponse	"Некорректный данные"
-//	@Failure		404	{object}	api.ErrorResponse	"Категория не найдена"
+//	@Failure		403				{object}	api.ErrorResponse	"Нет доступа к бюджету"
 //
 //	@Router			/category/{id} [get]
 //
@@ -161,7 +166,14 @@ func (h *CategoryHandlers) GetCategory(c *gin.Context) {
 		return
 	}
 
-	category, err := h.g.GetCategory(h.ctx, uint(id))
+	member, ok := api.Member(c)
+	if !ok {
+		log.Error("error get member")
+		api.ResponseError(c, http.StatusInternalServerError, "error server")
+		return
+	}
+
+	category, err := h.g.GetCategory(h.ctx, member, uint(id))
 	if err != nil {
 		log.Error("error get category")
 		api.RegistrationError(c, err)
@@ -177,14 +189,17 @@ func (h *CategoryHandlers) GetCategory(c *gin.Context) {
 //	@Tags			categories
 //	@Accept			json
 //	@Produce		json
-//	@Param			req	body		RequestUpdateCategory	true	"данные для обновление категории"
-//	@Success		200	{object}	api.SuccessResponse		"success"
+//	@Param			req				body		RequestUpdateCategory	true	"данные для обновление категории"
+//	@Param			X-Household-ID	header		int						false	"id общего бюджета, по умолчанию личный"
+//	@Success		200				{object}	api.SuccessResponse		"success"
+//
+//	@Failure		401				{object}	api.ErrorResponse		"Ошибка авторизации"
+//	@Failure		400				{object}	api.ErrorResponse		"Некоректные входные данные"
+//	@Failure		500				{object}	api.ErrorResponse		"Ошибка сервера"
+//	@Failure		400				{object}	api.ErrorResponse		"Некорректный данные"
+//	@Failure		404				{object}	api.ErrorResponse		"Категория не найдена"
 //
-//	@Failure		401	{object}	api.ErrorResponse		"Ошибка авторизации"
-//	@Failure		400	{object}	api.ErrorResponse		"Некоректные входные данные"
-//	@Failure		500	{object}	api.ErrorResponse		"Ошибка сервера"
-//	@Failure		400	{object}	api.ErrorResponse		"Некорректный данные"
-//	@Failure		404	{object}	api.ErrorResponse		"Категория не найдена"
+//	@Failure		403				{object}	api.ErrorResponse		"Нет доступа к бюджету"
 //
 //	@Router			/category/ [put]
 //
@@ -211,7 +226,14 @@ func (h *CategoryHandlers) UpdateCategory(c *gin.Context) {
 		Description: updateCategory.Description,
 	}
 
-	category, err := h.u.UpdateCategory(h.ctx, updateCategory.CategoryId, newCategory)
+	member, ok := api.Member(c)
+	if !ok {
+		log.Error("error get member")
+		api.ResponseError(c, http.StatusInternalServerError, "error server")
+		return
+	}
+
+	category, err := h.u.UpdateCategory(h.ctx, member, updateCategory.CategoryId, newCategory)
 	if err != nil {
 		log.Error("error update category")
 		api.RegistrationError(c, err)
@@ -229,15 +251,18 @@ func (h *CategoryHandlers) UpdateCategory(c *gin.Context) {
 //	@Description	Удаление категории конкретного пользователя
 //	@Tags			categories
 //	@Produce		json
-//	@Param			id	path		int					true	"ID категории"
-//	@Success		200	{object}	api.SuccessResponse	"success"
+//	@Param			id				path		int					true	"ID категории"
+//	@Param			X-Household-ID	header		int					false	"id общего бюджета, по умолчанию личный"
+//	@Success		200				{object}	api.SuccessResponse	"success"
+//
+//	@Failure		401				{object}	api.ErrorResponse	"Ошибка авторизации"
 //
-//	@Failure		401	{object}	api.ErrorResponse	"Ошибка авторизации"
+//	@Failure		500				{object}	api.ErrorResponse	"Ошибка сервера"
+//	@Failure		400				{object}	api.ErrorResponse	"Некоректные входные данные"
+//	@Failure		400				{object}	api.ErrorResponse	"Некорректные данные"
+//	@Failure		404				{object}	api.ErrorResponse	"Категория не найдена"
 //
-//	@Failure		500	{object}	api.ErrorResponse	"Ошибка сервера"
-//	@Failure		400	{object}	api.ErrorResponse	"Некоректные входные данные"
-//	@Failure		400	{object}	api.ErrorResponse	"Некорректные данные"
-//	@Failure		404	{object}	api.ErrorResponse	"Категория не найдена"
+//	@Failure		403				{object}	api.ErrorResponse	"Нет доступа к бюджету"
 //
 //	@Router			/category/{id} [delete]
 //
@@ -258,7 +283,14 @@ func (h *CategoryHandlers) DeleteCategory(c *gin.Context) {
 		return
 	}
 
-	err = h.d.DeleteCategory(h.ctx, uint(id))
+	member, ok := api.Member(c)
+	if !ok {
+		log.Error("error get member")
+		api.ResponseError(c, http.StatusInternalServerError, "error server")
+		return
+	}
+
+	err = h.d.DeleteCategory(h.ctx, member, uint(id))
 	if err != nil {
 		log.Error("error delete category")
 		api.RegistrationError(c, err)
@@ -271,19 +303,23 @@ func (h *CategoryHandlers) DeleteCategory(c *gin.Context) {
 }
 
 // CategoryType godoc
-// @Summary получение категории или категорий по типу
-// @Description получение категории или категорий по определенномк типу, который передается в URL пути
-// @Tags categories
-// @Produce json
-// @Param type path string true "тип, по которому будут выбиратся категории или категория"
-// @Success 200 {object}  api.SuccessResponse "success"
 //
-//	@Failure	500	{object}	api.ErrorResponse	"Ошибка сервера"
-//	@Failure	400	{object}	api.ErrorResponse	"Некоректные входные данные"
-//	@Failure	400	{object}	api.ErrorResponse	"Некорректные данные"
-//	@Failure	404	{object}	api.ErrorResponse	"Категория не найдена"
-//	@Router		/category/type/{type} [get]
-//	@Security	jwtAuth
+//	@Summary		получение категории или категорий по типу
+//	@Description	получение категории или категорий по определенномк типу, который передается в URL пути
+//	@Tags			categories
+//	@Produce		json
+//	@Param			type			path		string				true	"тип, по которому будут выбиратся категории или категория"
+//	@Param			X-Household-ID	header		int					false	"id общего бюджета, по умолчанию личный"
+//	@Success		200				{object}	api.SuccessResponse	"success"
+//
+//	@Failure		500				{object}	api.ErrorResponse	"Ошибка сервера"
+//	@Failure		400				{object}	api.ErrorResponse	"Некоректные входные данные"
+//	@Failure		400				{object}	api.ErrorResponse	"Некорректные данные"
+//	@Failure		404				{object}	api.ErrorResponse	"Категория не найдена"
+//	@Failure		403				{object}	api.ErrorResponse	"Нет доступа к бюджету"
+//
+//	@Router			/category/type/{type} [get]
+//	@Security		jwtAuth
 func (h *CategoryHandlers) CategoryType(c *gin.Context) {
 	const op = "handlers.CategoryType"
 
@@ -298,7 +334,14 @@ func (h *CategoryHandlers) CategoryType(c *gin.Context) {
 		return
 	}
 
-	result, err := h.t.CategoryType(h.ctx, typeFound)
+	member, ok := api.Member(c)
+	if !ok {
+		log.Error("error get member")
+		api.ResponseError(c, http.StatusInternalServerError, "error server")
+		return
+	}
+
+	result, err := h.t.CategoryType(h.ctx, member, typeFound)
 	if err != nil {
 		log.WithField("err", err).Error("error in get category type")
 		api.RegistrationError(c, err)
@@ -11,24 +11,24 @@ type categoryServiceMock struct {
 	mock.Mock
 }
 
-func (m *categoryServiceMock) CreateCategory(ctx context.Context, idUser uint, category domain.CategoryInput) (uint, error) {
-	args := m.Called(ctx, idUser, category)
+func (m *categoryServiceMock) CreateCategory(ctx context.Context, member domain.Member, category domain.CategoryInput) (uint, error) {
+	args := m.Called(ctx, member, category)
 	return args.Get(0).(uint), args.Error(1)
 }
-func (m *categoryServiceMock) GetCategory(ctx context.Context, idCategory uint) (domain.CategoryOutput, error) {
-	args := m.Called(ctx, idCategory)
+func (m *categoryServiceMock) GetCategory(ctx context.Context, member domain.Member, idCategory uint) (domain.CategoryOutput, error) {
+	args := m.Called(ctx, member, idCategory)
 	return args.Get(0).(domain.CategoryOutput), args.Error(1)
 }
-func (m *categoryServiceMock) UpdateCategory(ctx context.Context, idCategory uint, newCategory domain.CategoryInput) (domain.CategoryOutput, error) {
-	args := m.Called(ctx, idCategory, newCategory)
+func (m *categoryServiceMock) UpdateCategory(ctx context.Context, member domain.Member, idCategory uint, newCategory domain.CategoryInput) (domain.CategoryOutput, error) {
+	args := m.Called(ctx, member, idCategory, newCategory)
 	return args.Get(0).(domain.CategoryOutput), args.Error(1)
 }
-func (m *categoryServiceMock) DeleteCategory(ctx context.Context, idCategory uint) error {
-	args := m.Called(ctx, idCategory)
+func (m *categoryServiceMock) DeleteCategory(ctx context.Context, member domain.Member, idCategory uint) error {
+	args := m.Called(ctx, member, idCategory)
 	return args.Error(0)
 }
 
-func (m *categoryServiceMock) CategoryType(ctx context.Context, typeFound string) ([]domain.CategoryOutput, error) {
-	args := m.Called(ctx, typeFound)
+func (m *categoryServiceMock) CategoryType(ctx context.Context, member domain.Member, typeFound string) ([]domain.CategoryOutput, error) {
+	args := m.Called(ctx, member, typeFound)
 	return args.Get(0).([]domain.CategoryOutput), args.Error(1)
 }
@@ -19,6 +19,8 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+var member = domain.Member{UserID: 1, HouseholdID: 3, Role: domain.HouseholdOwner}
+
 func TestPostCategory(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -78,14 +80,15 @@ func TestPostCategory(t *testing.T) {
 			w := httptest.NewRecorder()
 			c, _ := gin.CreateTestContext(w)
 			if !tc.missUserID {
-				c.Set("userID", tc.userID)
+				c.Set("member", domain.Member{UserID: tc.userID, HouseholdID: member.HouseholdID, Role: member.Role})
 			}
 
 			svc := new(categoryServiceMock)
 			log := logrus.New()
 			ctx := context.Background()
 
-			svc.On("CreateCategory", ctx, tc.userID, tc.body).Return(tc.categoryID, tc.mockErr)
+			m := domain.Member{UserID: tc.userID, HouseholdID: member.HouseholdID, Role: member.Role}
+			svc.On("CreateCategory", ctx, m, tc.body).Return(tc.categoryID, tc.mockErr)
 			h := CreateHandlersCategory(svc, svc, svc, svc, svc, log, ctx)
 
 			req := http.Request{Header: make(http.Header), URL: &url.URL{}}
@@ -101,7 +104,7 @@ func TestPostCategory(t *testing.T) {
 			h.PostCategory(c)
 			assert.Equal(t, tc.status, w.Code)
 			if tc.shouldCallDB {
-				svc.AssertCalled(t, "CreateCategory", ctx, tc.userID, tc.body)
+				svc.AssertCalled(t, "CreateCategory", ctx, m, tc.body)
 			}
 		})
 	}
@@ -143,13 +146,14 @@ func TestGetCategory(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			w := httptest.NewRecorder()
 			c, _ := gin.CreateTestContext(w)
+			c.Set("member", member)
 
 			svc := new(categoryServiceMock)
 			log := logrus.New()
 			ctx := context.Background()
 
 			if tc.shouldCallDB {
-				svc.On("GetCategory", ctx, tc.req).Return(tc.output, tc.mockErr)
+				svc.On("GetCategory", ctx, member, tc.req).Return(tc.output, tc.mockErr)
 			}
 
 			h := CreateHandlersCategory(svc, svc, svc, svc, svc, log, ctx)
@@ -178,7 +182,7 @@ func TestGetCategory(t *testing.T) {
 			h.GetCategory(c)
 			assert.Equal(t, tc.status, w.Code)
 			if tc.shouldCallDB {
-				svc.AssertCalled(t, "GetCategory", ctx, tc.req)
+				svc.AssertCalled(t, "GetCategory", ctx, member, tc.req)
 			}
 		})
 	}
@@ -222,6 +226,7 @@ func TestUpdateCategory(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			w := httptest.NewRecorder()
 			c, _ := gin.CreateTestContext(w)
+			c.Set("member", member)
 
 			svc := new(categoryServiceMock)
 			log := logrus.New()
@@ -229,7 +234,7 @@ func TestUpdateCategory(t *testing.T) {
 			input := domain.CategoryInput{Name: tc.req.Name, Limit: tc.req.Limit, Description: tc.req.Description}
 
 			if tc.shouldCallDB {
-				svc.On("UpdateCategory", ctx, tc.req.CategoryId, input).Return(tc.output, tc.mockErr)
+				svc.On("UpdateCategory", ctx, member, tc.req.CategoryId, input).Return(tc.output, tc.mockErr)
 			}
 			h := CreateHandlersCategory(svc, svc, svc, svc, svc, log, ctx)
 
@@ -246,7 +251,7 @@ func TestUpdateCategory(t *testing.T) {
 			h.UpdateCategory(c)
 			assert.Equal(t, tc.status, w.Code)
 			if tc.shouldCallDB {
-				svc.AssertCalled(t, "UpdateCategory", ctx, tc.req.CategoryId, input)
+				svc.AssertCalled(t, "UpdateCategory", ctx, member, tc.req.CategoryId, input)
 			}
 		})
 	}
@@ -287,13 +292,14 @@ func TestDeleteCategory(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			w := httptest.NewRecorder()
 			c, _ := gin.CreateTestContext(w)
+			c.Set("member", member)
 
 			svc := new(categoryServiceMock)
 			log := logrus.New()
 			ctx := context.Background()
 
 			if tc.shouldCallDB {
-				svc.On("DeleteCategory", ctx, tc.req).Return(tc.mockErr)
+				svc.On("DeleteCategory", ctx, member, tc.req).Return(tc.mockErr)
 			}
 
 			h := CreateHandlersCategory(svc, svc, svc, svc, svc, log, ctx)
@@ -322,7 +328,7 @@ func TestDeleteCategory(t *testing.T) {
 			h.DeleteCategory(c)
 			assert.Equal(t, tc.status, w.Code)
 			if tc.shouldCallDB {
-				svc.AssertCalled(t, "DeleteCategory", ctx, tc.req)
+				svc.AssertCalled(t, "DeleteCategory", ctx, member, tc.req)
 			}
 		})
 	}
@@ -382,13 +388,14 @@ func TestCategoryType(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			w := httptest.NewRecorder()
 			c, _ := gin.CreateTestContext(w)
+			c.Set("member", member)
 
 			svc := new(categoryServiceMock)
 			log := logrus.New()
 			ctx := context.Background()
 
 			if tc.shouldCallDB {
-				svc.On("CategoryType", ctx, tc.param).Return(tc.output, tc.mockErr)
+				svc.On("CategoryType", ctx, member, tc.param).Return(tc.output, tc.mockErr)
 			}
 
 			h := CreateHandlersCategory(svc, svc, svc, svc, svc, log, ctx)
@@ -409,7 +416,7 @@ func TestCategoryType(t *testing.T) {
 			h.CategoryType(c)
 			assert.Equal(t, tc.status, w.Code)
 			if tc.shouldCallDB {
-				svc.AssertCalled(t, "CategoryType", ctx, tc.param)
+				svc.AssertCalled(t, "CategoryType", ctx, member, tc.param)
 			}
 		})
 	}
@@ -0,0 +1,111 @@
+package comparisonHandlers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/financial_tracer/internal/handlers/api"
+	"github.com/financial_tracer/internal/lib/period"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type CompareServic interface {
+	Compare(ctx context.Context, member domain.Member, current domain.Period, previous domain.Period, pref domain.Preferences) (domain.PeriodComparison, error)
+}
+
+type ComparisonHandlers struct {
+	c   CompareServic
+	log *logrus.Logger
+	ctx context.Context
+}
+
+func CreateComparisonHandlers(c CompareServic, log *logrus.Logger, ctx context.Context) *ComparisonHandlers {
+	return &ComparisonHandlers{
+		c:   c,
+		log: log,
+		ctx: ctx,
+	}
+}
+
+// Compare godoc
+//
+//	@Summary		Сравнение периодов
+//	@Description	Сравнение трат по категориям за два периода: абсолютная и процентная разница, новые (new) и исчезнувшие (disappeared) категории. Даты включительно в часовом поясе пользователя, если предыдущий период не указан, берется тот же период год назад. period=week|month сравнивает текущую неделю или отчетный месяц с предыдущими по настройкам пользователя
+//	@Tags			report
+//	@Produce		json
+//	@Param			period			query		string				false	"текущая неделя или месяц"					Enums(week, month)
+//	@Param			from			query		string				false	"начало периода, без period обязательно"	example(2025-07-01)
+//	@Param			to				query		string				false	"конец периода, без period обязательно"		example(2025-09-30)
+//	@Param			previous_from	query		string				false	"начало предыдущего периода"				example(2025-04-01)
+//	@Param			previous_to		query		string				false	"конец предыдущего периода"					example(2025-06-30)
+//	@Param			X-Household-ID	header		int					false	"id общего бюджета, по умолчанию личный"
+//	@Success		200				{object}	api.SuccessResponse	"Сравнение"
+//
+//	@Failure		401				{object}	api.ErrorResponse	"Ошибка авторизации"
+//	@Failure		400				{object}	api.ErrorResponse	"Некорректные входные данные"
+//	@Failure		500				{object}	api.ErrorResponse	"Ошибка сервера"
+//	@Failure		403				{object}	api.ErrorResponse	"Нет доступа к бюджету"
+//
+//	@Router			/report/compare [get]
+//
+//	@Security		jwtAuth
+func (h *ComparisonHandlers) Compare(c *gin.Context) {
+	const op = "handlers.Compare"
+
+	log := h.log.WithField("op", op)
+
+	log.Info("start compare periods")
+
+	var req RequestCompare
+	if err := c.ShouldBindQuery(&req); err != nil {
+		log.WithField("err", err).Error("error valid query")
+		api.ResponseError(c, http.StatusBadRequest, "error valid query")
+		return
+	}
+
+	if req.PreviousFrom.IsZero() != req.PreviousTo.IsZero() {
+		log.Error("incomplete previous period")
+		api.ResponseError(c, http.StatusBadRequest, "previous_from and previous_to must be set together")
+		return
+	}
+
+	member, ok := api.Member(c)
+	if !ok {
+		log.Error("error get member")
+		api.ResponseError(c, http.StatusInternalServerError, "error server")
+		return
+	}
+
+	pref := api.Preferences(c)
+	loc := period.Location(pref)
+
+	var current, previous domain.Period
+	switch req.Period {
+	case "week":
+		current = period.Week(time.Now().In(loc), time.Weekday(pref.WeekStart))
+		previous = period.Previous(current, true)
+	case "month":
+		current = period.Month(time.Now().In(loc), pref.MonthStart)
+		previous = period.Previous(current, false)
+	default:
+		current = domain.Period{From: period.Date(req.From, loc), To: period.Date(req.To, loc).AddDate(0, 0, 1)}
+	}
+
+	if !req.PreviousFrom.IsZero() {
+		previous = domain.Period{From: period.Date(req.PreviousFrom, loc), To: period.Date(req.PreviousTo, loc).AddDate(0, 0, 1)}
+	}
+
+	res, err := h.c.Compare(c.Request.Context(), member, current, previous, pref)
+	if err != nil {
+		log.WithField("err", err).Error("error compare periods")
+		api.RegistrationError(c, err)
+		return
+	}
+
+	log.Info("success compare periods")
+
+	api.ResponseOK(c, res)
+}
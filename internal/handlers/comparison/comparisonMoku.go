@@ -0,0 +1,17 @@
+package comparisonHandlers
+
+import (
+	"context"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type comparisonServicMock struct {
+	mock.Mock
+}
+
+func (m *comparisonServicMock) Compare(ctx context.Context, member domain.Member, current domain.Period, previous domain.Period, pref domain.Preferences) (domain.PeriodComparison, error) {
+	args := m.Called(ctx, member, current, previous, pref)
+	return args.Get(0).(domain.PeriodComparison), args.Error(1)
+}
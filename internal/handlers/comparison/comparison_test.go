@@ -0,0 +1,182 @@
+package comparisonHandlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/financial_tracer/internal/servic/comparison"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/assert/v2"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/mock"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+var member = domain.Member{UserID: 1, HouseholdID: 3, Role: domain.HouseholdViewer}
+
+func TestCompare(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	q3 := domain.Period{From: date(2025, time.July, 1), To: date(2025, time.October, 1)}
+	q2 := domain.Period{From: date(2025, time.April, 1), To: date(2025, time.July, 1)}
+
+	tests := []struct {
+		name         string
+		rawQuery     string
+		previous     domain.Period
+		mockErr      error
+		status       int
+		shouldCallDB bool
+	}{
+		{
+			name:         "success",
+			rawQuery:     "from=2025-07-01&to=2025-09-30&previous_from=2025-04-01&previous_to=2025-06-30",
+			previous:     q2,
+			status:       http.StatusOK,
+			shouldCallDB: true,
+		},
+		{
+			name:         "success year over year",
+			rawQuery:     "from=2025-07-01&to=2025-09-30",
+			status:       http.StatusOK,
+			shouldCallDB: true,
+		},
+		{
+			name:         "missing to",
+			rawQuery:     "from=2025-07-01",
+			status:       http.StatusBadRequest,
+			shouldCallDB: false,
+		},
+		{
+			name:         "invalid date",
+			rawQuery:     "from=01.07.2025&to=2025-09-30",
+			status:       http.StatusBadRequest,
+			shouldCallDB: false,
+		},
+		{
+			name:         "invalid period",
+			rawQuery:     "period=year",
+			status:       http.StatusBadRequest,
+			shouldCallDB: false,
+		},
+		{
+			name:         "incomplete previous period",
+			rawQuery:     "from=2025-07-01&to=2025-09-30&previous_from=2025-04-01",
+			status:       http.StatusBadRequest,
+			shouldCallDB: false,
+		},
+		{
+			name:         "error database",
+			rawQuery:     "from=2025-07-01&to=2025-09-30",
+			mockErr:      comparison.ErrDatabase,
+			status:       http.StatusInternalServerError,
+			shouldCallDB: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Set("member", member)
+
+			svc := new(comparisonServicMock)
+			ctx := context.Background()
+			svc.On("Compare", mock.Anything, member, q3, tc.previous, mock.Anything).Return(domain.PeriodComparison{}, tc.mockErr)
+
+			h := CreateComparisonHandlers(svc, logrus.New(), ctx)
+
+			req := http.Request{Header: make(http.Header), URL: &url.URL{RawQuery: tc.rawQuery}}
+			c.Request = req.WithContext(ctx)
+
+			h.Compare(c)
+
+			assert.Equal(t, tc.status, w.Code)
+			if tc.shouldCallDB {
+				svc.AssertCalled(t, "Compare", mock.Anything, member, q3, tc.previous, mock.Anything)
+			} else {
+				svc.AssertNotCalled(t, "Compare", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestComparePreferences(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pref := domain.Preferences{Currency: "EUR", Timezone: "Europe/Moscow", Locale: "ru", WeekStart: 0, MonthStart: 10}
+
+	tests := []struct {
+		name     string
+		rawQuery string
+		check    func(t *testing.T, current domain.Period, previous domain.Period)
+	}{
+		{
+			name:     "dates in the user timezone",
+			rawQuery: "from=2025-07-01&to=2025-09-30",
+			check: func(t *testing.T, current domain.Period, previous domain.Period) {
+				assert.Equal(t, time.Date(2025, time.July, 1, 0, 0, 0, 0, moscow), current.From)
+				assert.Equal(t, time.Date(2025, time.October, 1, 0, 0, 0, 0, moscow), current.To)
+				assert.Equal(t, domain.Period{}, previous)
+			},
+		},
+		{
+			name:     "current week",
+			rawQuery: "period=week",
+			check: func(t *testing.T, current domain.Period, previous domain.Period) {
+				assert.Equal(t, time.Sunday, current.From.Weekday())
+				assert.Equal(t, current.From, previous.To)
+				assert.Equal(t, current.From.AddDate(0, 0, -7), previous.From)
+				assert.Equal(t, moscow, current.From.Location())
+			},
+		},
+		{
+			name:     "current month",
+			rawQuery: "period=month",
+			check: func(t *testing.T, current domain.Period, previous domain.Period) {
+				assert.Equal(t, 10, current.From.Day())
+				assert.Equal(t, current.From.AddDate(0, 1, 0), current.To)
+				assert.Equal(t, current.From.AddDate(0, -1, 0), previous.From)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Set("member", member)
+			c.Set("preferences", pref)
+
+			svc := new(comparisonServicMock)
+			ctx := context.Background()
+			svc.On("Compare", mock.Anything, member, mock.Anything, mock.Anything, pref).Return(domain.PeriodComparison{}, nil)
+
+			h := CreateComparisonHandlers(svc, logrus.New(), ctx)
+
+			req := http.Request{Header: make(http.Header), URL: &url.URL{RawQuery: tc.rawQuery}}
+			c.Request = req.WithContext(ctx)
+
+			h.Compare(c)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			if len(svc.Calls) != 1 {
+				t.Fatalf("Compare called %d times", len(svc.Calls))
+			}
+			args := svc.Calls[0].Arguments
+			tc.check(t, args.Get(2).(domain.Period), args.Get(3).(domain.Period))
+		})
+	}
+}
@@ -0,0 +1,13 @@
+package comparisonHandlers
+
+import "time"
+
+// RequestCompare represents compare periods request, the dates are inclusive. Period
+// "week" or "month" compares the current week or reporting month with the one before.
+type RequestCompare struct {
+	Period       string    `form:"period" binding:"omitempty,oneof=week month" example:"month"`
+	From         time.Time `form:"from" binding:"required_without=Period" time_format:"2006-01-02" example:"2025-07-01"`
+	To           time.Time `form:"to" binding:"required_without=Period" time_format:"2006-01-02" example:"2025-09-30"`
+	PreviousFrom time.Time `form:"previous_from" time_format:"2006-01-02" example:"2025-04-01"`
+	PreviousTo   time.Time `form:"previous_to" time_format:"2006-01-02" example:"2025-06-30"`
+}
@@ -0,0 +1,227 @@
+package exportHandlers
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/financial_tracer/internal/handlers/api"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type ExportServic interface {
+	RequestExport(ctx context.Context, userID uint) (domain.DataExport, error)
+	Exports(ctx context.Context, userID uint) ([]domain.DataExport, error)
+	Export(ctx context.Context, userID uint, id uint) (domain.DataExport, error)
+}
+
+type DownloadServic interface {
+	Download(ctx context.Context, id uint, expires int64, signature string) (domain.DataExport, io.ReadCloser, error)
+}
+
+type ExportHandlers struct {
+	e   ExportServic
+	d   DownloadServic
+	log *logrus.Logger
+	ctx context.Context
+}
+
+func CreateExportHandlers(e ExportServic, d DownloadServic, log *logrus.Logger, ctx context.Context) *ExportHandlers {
+	return &ExportHandlers{
+		e:   e,
+		d:   d,
+		log: log,
+		ctx: ctx,
+	}
+}
+
+// RequestExport godoc
+//
+//	@Summary		Экспорт всех данных пользователя
+//	@Description	Запуск сборки ZIP архива с профилем, категориями, транзакциями (JSON и CSV), сессиями и журналом ledger. Архив собирается в фоне, пока сборка не закончилась возвращается тот же экспорт. Журнал из архива можно загрузить обратно через /journal/import
+//
+//	@Tags			User
+//
+//	@Produce		json
+//	@Success		202	{object}	api.SuccessResponse	"Экспорт"
+//
+//	@Failure		401	{object}	api.ErrorResponse	"Ошибка авторизации"
+//	@Failure		500	{object}	api.ErrorResponse	"Ошибка сервера"
+//
+//	@Router			/user/export [post]
+//
+//	@Security		jwtAuth
+func (h *ExportHandlers) RequestExport(c *gin.Context) {
+	const op = "handlers.RequestExport"
+
+	log := h.log.WithField("op", op)
+
+	log.Info("start request export")
+
+	idUser, ok := c.Get("userID")
+	if !ok {
+		log.Error("error get userID")
+		api.ResponseError(c, http.StatusInternalServerError, "error server")
+		return
+	}
+
+	export, err := h.e.RequestExport(c.Request.Context(), idUser.(uint))
+	if err != nil {
+		log.WithField("err", err).Error("error request export")
+		api.RegistrationError(c, err)
+		return
+	}
+
+	log.Info("success request export")
+
+	c.Writer.Header().Set("content-type", "application/json")
+	c.JSON(http.StatusAccepted, api.SuccessResponse{
+		Value: export,
+	})
+}
+
+// ListExports godoc
+//
+//	@Summary		Экспорты пользователя
+//	@Description	Экспорты пользователя от новых к старым. У готовых экспортов есть подписанная ссылка на скачивание, она действует пока архив не удален
+//
+//	@Tags			User
+//
+//	@Produce		json
+//	@Success		200	{object}	api.SuccessResponse	"Экспорты"
+//
+//	@Failure		401	{object}	api.ErrorResponse	"Ошибка авторизации"
+//	@Failure		500	{object}	api.ErrorResponse	"Ошибка сервера"
+//
+//	@Router			/user/export [get]
+//
+//	@Security		jwtAuth
+func (h *ExportHandlers) ListExports(c *gin.Context) {
+	const op = "handlers.ListExports"
+
+	log := h.log.WithField("op", op)
+
+	log.Info("start list exports")
+
+	idUser, ok := c.Get("userID")
+	if !ok {
+		log.Error("error get userID")
+		api.ResponseError(c, http.StatusInternalServerError, "error server")
+		return
+	}
+
+	exports, err := h.e.Exports(c.Request.Context(), idUser.(uint))
+	if err != nil {
+		log.WithField("err", err).Error("error list exports")
+		api.RegistrationError(c, err)
+		return
+	}
+
+	log.Info("success list exports")
+
+	api.ResponseOK(c, exports)
+}
+
+// GetExport godoc
+//
+//	@Summary		Статус экспорта
+//	@Description	Статус экспорта: pending, ready или failed. У готового экспорта есть подписанная ссылка на скачивание
+//
+//	@Tags			User
+//
+//	@Produce		json
+//	@Param			id	path		int					true	"id экспорта"
+//	@Success		200	{object}	api.SuccessResponse	"Экспорт"
+//
+//	@Failure		400	{object}	api.ErrorResponse	"Некорректные входные данные"
+//	@Failure		401	{object}	api.ErrorResponse	"Ошибка авторизации"
+//	@Failure		404	{object}	api.ErrorResponse	"Экспорт не найден"
+//	@Failure		500	{object}	api.ErrorResponse	"Ошибка сервера"
+//
+//	@Router			/user/export/{id} [get]
+//
+//	@Security		jwtAuth
+func (h *ExportHandlers) GetExport(c *gin.Context) {
+	const op = "handlers.GetExport"
+
+	log := h.log.WithField("op", op)
+
+	log.Info("start get export")
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		log.WithField("err", err).Error("error get id")
+		api.ResponseError(c, http.StatusBadRequest, "invalid export id")
+		return
+	}
+
+	idUser, ok := c.Get("userID")
+	if !ok {
+		log.Error("error get userID")
+		api.ResponseError(c, http.StatusInternalServerError, "error server")
+		return
+	}
+
+	export, err := h.e.Export(c.Request.Context(), idUser.(uint), uint(id))
+	if err != nil {
+		log.WithField("err", err).Error("error get export")
+		api.RegistrationError(c, err)
+		return
+	}
+
+	log.Info("success get export")
+
+	api.ResponseOK(c, export)
+}
+
+// Download godoc
+//
+//	@Summary		Скачивание экспорта
+//	@Description	Скачивание ZIP архива по подписанной ссылке из экспорта. Ссылка не требует авторизации и перестает действовать вместе с архивом
+//
+//	@Tags			User
+//
+//	@Produce		application/zip
+//	@Param			id			query		int					true	"id экспорта"
+//	@Param			expires		query		int					true	"время окончания действия ссылки, unix"
+//	@Param			signature	query		string				true	"подпись ссылки"
+//	@Success		200			{file}		file				"Архив"
+//
+//	@Failure		400			{object}	api.ErrorResponse	"Некорректные входные данные"
+//	@Failure		403			{object}	api.ErrorResponse	"Неверная или просроченная ссылка"
+//	@Failure		404			{object}	api.ErrorResponse	"Экспорт не найден"
+//	@Failure		500			{object}	api.ErrorResponse	"Ошибка сервера"
+//
+//	@Router			/export/download [get]
+func (h *ExportHandlers) Download(c *gin.Context) {
+	const op = "handlers.Download"
+
+	log := h.log.WithField("op", op)
+
+	log.Info("start download export")
+
+	var req RequestDownload
+	if err := c.ShouldBindQuery(&req); err != nil {
+		log.WithField("err", err).Error("error valid query")
+		api.ResponseError(c, http.StatusBadRequest, "invalid download link")
+		return
+	}
+
+	export, file, err := h.d.Download(c.Request.Context(), req.ID, req.Expires, req.Signature)
+	if err != nil {
+		log.WithField("err", err).Error("error download export")
+		api.RegistrationError(c, err)
+		return
+	}
+	defer file.Close()
+
+	log.Info("success download export")
+
+	c.DataFromReader(http.StatusOK, export.Size, "application/zip", file, map[string]string{
+		"Content-Disposition": `attachment; filename="financial_tracer-` + export.CreatedAt.Format("2006-01-02") + `.zip"`,
+		"Cache-Control":       "no-store",
+	})
+}
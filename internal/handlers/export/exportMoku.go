@@ -0,0 +1,34 @@
+package exportHandlers
+
+import (
+	"context"
+	"io"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type exportServicMock struct {
+	mock.Mock
+}
+
+func (m *exportServicMock) RequestExport(ctx context.Context, userID uint) (domain.DataExport, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(domain.DataExport), args.Error(1)
+}
+
+func (m *exportServicMock) Exports(ctx context.Context, userID uint) ([]domain.DataExport, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]domain.DataExport), args.Error(1)
+}
+
+func (m *exportServicMock) Export(ctx context.Context, userID uint, id uint) (domain.DataExport, error) {
+	args := m.Called(ctx, userID, id)
+	return args.Get(0).(domain.DataExport), args.Error(1)
+}
+
+func (m *exportServicMock) Download(ctx context.Context, id uint, expires int64, signature string) (domain.DataExport, io.ReadCloser, error) {
+	args := m.Called(ctx, id, expires, signature)
+	file, _ := args.Get(1).(io.ReadCloser)
+	return args.Get(0).(domain.DataExport), file, args.Error(2)
+}
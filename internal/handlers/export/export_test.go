@@ -0,0 +1,206 @@
+package exportHandlers
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/financial_tracer/internal/servic/export"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/assert/v2"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRequestExport(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name         string
+		mockErr      error
+		status       int
+		missUserID   bool
+		shouldCallDB bool
+	}{
+		{
+			name:         "success",
+			status:       http.StatusAccepted,
+			shouldCallDB: true,
+		},
+		{
+			name:         "error database",
+			mockErr:      export.ErrDatabase,
+			status:       http.StatusInternalServerError,
+			shouldCallDB: true,
+		},
+		{
+			name:       "no user id",
+			missUserID: true,
+			status:     http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			if !tc.missUserID {
+				c.Set("userID", uint(1))
+			}
+
+			svc := new(exportServicMock)
+			ctx := context.Background()
+			svc.On("RequestExport", mock.Anything, uint(1)).Return(domain.DataExport{ID: 7, Status: domain.ExportPending}, tc.mockErr)
+
+			h := CreateExportHandlers(svc, svc, logrus.New(), ctx)
+
+			req := http.Request{Header: make(http.Header), URL: &url.URL{}}
+			c.Request = req.WithContext(ctx)
+
+			h.RequestExport(c)
+
+			assert.Equal(t, tc.status, w.Code)
+			if tc.shouldCallDB {
+				svc.AssertExpectations(t)
+			} else {
+				svc.AssertNotCalled(t, "RequestExport", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestGetExport(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name         string
+		id           string
+		mockErr      error
+		status       int
+		shouldCallDB bool
+	}{
+		{
+			name:         "success",
+			id:           "7",
+			status:       http.StatusOK,
+			shouldCallDB: true,
+		},
+		{
+			name:         "error not found",
+			id:           "7",
+			mockErr:      export.ErrNoFound,
+			status:       http.StatusNotFound,
+			shouldCallDB: true,
+		},
+		{
+			name:   "error id",
+			id:     "seven",
+			status: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Set("userID", uint(1))
+			c.Params = gin.Params{{Key: "id", Value: tc.id}}
+
+			svc := new(exportServicMock)
+			ctx := context.Background()
+			svc.On("Export", mock.Anything, uint(1), uint(7)).Return(domain.DataExport{ID: 7}, tc.mockErr)
+
+			h := CreateExportHandlers(svc, svc, logrus.New(), ctx)
+
+			req := http.Request{Header: make(http.Header), URL: &url.URL{}}
+			c.Request = req.WithContext(ctx)
+
+			h.GetExport(c)
+
+			assert.Equal(t, tc.status, w.Code)
+			if tc.shouldCallDB {
+				svc.AssertExpectations(t)
+			} else {
+				svc.AssertNotCalled(t, "Export", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestDownload(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	ready := domain.DataExport{ID: 7, Status: domain.ExportReady, Size: 7, CreatedAt: time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)}
+
+	tests := []struct {
+		name         string
+		query        string
+		mockErr      error
+		status       int
+		shouldCallDB bool
+	}{
+		{
+			name:         "success",
+			query:        "id=7&expires=1773230400&signature=abc",
+			status:       http.StatusOK,
+			shouldCallDB: true,
+		},
+		{
+			name:         "error link",
+			query:        "id=7&expires=1773230400&signature=abc",
+			mockErr:      export.ErrLink,
+			status:       http.StatusForbidden,
+			shouldCallDB: true,
+		},
+		{
+			name:   "error no signature",
+			query:  "id=7&expires=1773230400",
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "error expires",
+			query:  "id=7&expires=tomorrow&signature=abc",
+			status: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+
+			svc := new(exportServicMock)
+			ctx := context.Background()
+			if tc.mockErr != nil {
+				svc.On("Download", mock.Anything, uint(7), int64(1773230400), "abc").Return(domain.DataExport{}, nil, tc.mockErr)
+			} else {
+				svc.On("Download", mock.Anything, uint(7), int64(1773230400), "abc").Return(ready, io.NopCloser(strings.NewReader("archive")), nil)
+			}
+
+			h := CreateExportHandlers(svc, svc, logrus.New(), ctx)
+
+			req := http.Request{Header: make(http.Header), URL: &url.URL{RawQuery: tc.query}}
+			c.Request = req.WithContext(ctx)
+
+			h.Download(c)
+
+			assert.Equal(t, tc.status, w.Code)
+			if tc.status == http.StatusOK {
+				assert.Equal(t, "archive", w.Body.String())
+				assert.Equal(t, "application/zip", w.Header().Get("Content-Type"))
+				assert.Equal(t, `attachment; filename="financial_tracer-2026-03-10.zip"`, w.Header().Get("Content-Disposition"))
+			}
+			if tc.shouldCallDB {
+				svc.AssertExpectations(t)
+			} else {
+				svc.AssertNotCalled(t, "Download", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
@@ -0,0 +1,8 @@
+package exportHandlers
+
+// RequestDownload represents the signed download link of an export
+type RequestDownload struct {
+	ID        uint   `form:"id" binding:"required" example:"7"`
+	Expires   int64  `form:"expires" binding:"required" example:"1773230400"`
+	Signature string `form:"signature" binding:"required" example:"Zp2c8kq1..."`
+}
@@ -0,0 +1,71 @@
+package forecastHandlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/financial_tracer/internal/handlers/api"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type ForecastServic interface {
+	Forecast(ctx context.Context, member domain.Member, pref domain.Preferences) (domain.Forecast, error)
+}
+
+type ForecastHandlers struct {
+	f   ForecastServic
+	log *logrus.Logger
+	ctx context.Context
+}
+
+func CreateForecastHandlers(f ForecastServic, log *logrus.Logger, ctx context.Context) *ForecastHandlers {
+	return &ForecastHandlers{
+		f:   f,
+		log: log,
+		ctx: ctx,
+	}
+}
+
+// Forecast godoc
+//
+//	@Summary		Прогноз трат
+//	@Description	Прогноз трат по категориям на конец текущего отчетного месяца (день начала месяца и часовой пояс из настроек пользователя): темп трат, среднее за прошлые месяцы и регулярные платежи. Категории, которые вероятно превысят лимит, отмечаются over_limit
+//	@Tags			report
+//	@Produce		json
+//	@Param			X-Household-ID	header		int					false	"id общего бюджета, по умолчанию личный"
+//	@Success		200				{object}	api.SuccessResponse	"Прогноз"
+//
+//	@Failure		401				{object}	api.ErrorResponse	"Ошибка авторизации"
+//	@Failure		500				{object}	api.ErrorResponse	"Ошибка сервера"
+//	@Failure		403				{object}	api.ErrorResponse	"Нет доступа к бюджету"
+//
+//	@Router			/report/forecast [get]
+//
+//	@Security		jwtAuth
+func (h *ForecastHandlers) Forecast(c *gin.Context) {
+	const op = "handlers.Forecast"
+
+	log := h.log.WithField("op", op)
+
+	log.Info("start forecast")
+
+	member, ok := api.Member(c)
+	if !ok {
+		log.Error("error get member")
+		api.ResponseError(c, http.StatusInternalServerError, "error server")
+		return
+	}
+
+	forecast, err := h.f.Forecast(c.Request.Context(), member, api.Preferences(c))
+	if err != nil {
+		log.WithField("err", err).Error("error forecast")
+		api.RegistrationError(c, err)
+		return
+	}
+
+	log.Info("success forecast")
+
+	api.ResponseOK(c, forecast)
+}
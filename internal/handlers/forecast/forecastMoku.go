@@ -0,0 +1,17 @@
+package forecastHandlers
+
+import (
+	"context"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type forecastServicMock struct {
+	mock.Mock
+}
+
+func (m *forecastServicMock) Forecast(ctx context.Context, member domain.Member, pref domain.Preferences) (domain.Forecast, error) {
+	args := m.Called(ctx, member, pref)
+	return args.Get(0).(domain.Forecast), args.Error(1)
+}
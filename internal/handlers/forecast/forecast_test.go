@@ -0,0 +1,80 @@
+package forecastHandlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/financial_tracer/internal/servic/forecast"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/assert/v2"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/mock"
+)
+
+var member = domain.Member{UserID: 1, HouseholdID: 3, Role: domain.HouseholdViewer}
+
+func TestForecast(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name         string
+		forecast     domain.Forecast
+		mockErr      error
+		missUserID   bool
+		status       int
+		shouldCallDB bool
+	}{
+		{
+			name: "success",
+			forecast: domain.Forecast{DaysElapsed: 10, DaysInPeriod: 30, Categories: []domain.CategoryForecast{
+				{CategoryID: 1, Name: "food", Limit: 1000, Spent: 500, RunRate: 1500, Projected: 1500, OverLimit: true},
+			}},
+			status:       http.StatusOK,
+			shouldCallDB: true,
+		},
+		{
+			name:         "error database",
+			mockErr:      forecast.ErrDatabase,
+			status:       http.StatusInternalServerError,
+			shouldCallDB: true,
+		},
+		{
+			name:         "no user id",
+			missUserID:   true,
+			status:       http.StatusInternalServerError,
+			shouldCallDB: false,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			if !tc.missUserID {
+				c.Set("member", member)
+			}
+
+			svc := new(forecastServicMock)
+			ctx := context.Background()
+			svc.On("Forecast", mock.Anything, member, mock.Anything).Return(tc.forecast, tc.mockErr)
+
+			h := CreateForecastHandlers(svc, logrus.New(), ctx)
+
+			req := http.Request{Header: make(http.Header), URL: &url.URL{}}
+			c.Request = req.WithContext(ctx)
+
+			h.Forecast(c)
+
+			assert.Equal(t, tc.status, w.Code)
+			if tc.shouldCallDB {
+				svc.AssertCalled(t, "Forecast", mock.Anything, member, mock.Anything)
+			} else {
+				svc.AssertNotCalled(t, "Forecast", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
@@ -0,0 +1,417 @@
+package householdHandlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/financial_tracer/internal/handlers/api"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type HouseholdServic interface {
+	Households(ctx context.Context, userID uint) ([]domain.Household, error)
+	Household(ctx context.Context, member domain.Member) (domain.Household, error)
+	CreateHousehold(ctx context.Context, userID uint, req domain.CreateHousehold) (domain.Household, error)
+	Invite(ctx context.Context, member domain.Member, req domain.InviteMember) (domain.HouseholdInvitation, error)
+	Accept(ctx context.Context, userID uint, req domain.AcceptInvitation) (domain.Household, error)
+	UpdateMember(ctx context.Context, member domain.Member, userID uint, req domain.UpdateMember) error
+	RemoveMember(ctx context.Context, member domain.Member, userID uint) error
+}
+
+type HouseholdHandlers struct {
+	s   HouseholdServic
+	log *logrus.Logger
+	ctx context.Context
+}
+
+func CreateHouseholdHandlers(s HouseholdServic, log *logrus.Logger, ctx context.Context) *HouseholdHandlers {
+	return &HouseholdHandlers{
+		s:   s,
+		log: log,
+		ctx: ctx,
+	}
+}
+
+// ListHouseholds godoc
+//
+//	@Summary		Бюджеты пользователя
+//	@Description	Личный и общие бюджеты, в которых состоит пользователь, с его ролью в каждом
+//
+//	@Tags			Household
+//
+//	@Produce		json
+//	@Success		200	{object}	api.SuccessResponse	"Бюджеты"
+//
+//	@Failure		401	{object}	api.ErrorResponse	"Ошибка авторизации"
+//	@Failure		500	{object}	api.ErrorResponse	"Ошибка сервера"
+//
+//	@Router			/household [get]
+//
+//	@Security		jwtAuth
+func (h *HouseholdHandlers) ListHouseholds(c *gin.Context) {
+	const op = "handlers.ListHouseholds"
+
+	log := h.log.WithField("op", op)
+
+	log.Info("start list households")
+
+	userID, ok := user(c, log)
+	if !ok {
+		return
+	}
+
+	households, err := h.s.Households(c.Request.Context(), userID)
+	if err != nil {
+		log.WithField("err", err).Error("error list households")
+		api.RegistrationError(c, err)
+		return
+	}
+
+	log.Info("success list households")
+
+	api.ResponseOK(c, households)
+}
+
+// CreateHousehold godoc
+//
+//	@Summary		Создание общего бюджета
+//	@Description	Создание общего бюджета, пользователь становится его владельцем
+//
+//	@Tags			Household
+//
+//	@Accept			json
+//	@Produce		json
+//	@Param			req	body		RequestHousehold	true	"название бюджета"
+//	@Success		200	{object}	api.SuccessResponse	"Бюджет создан"
+//
+//	@Failure		400	{object}	api.ErrorResponse	"Некорректные входные данные"
+//	@Failure		401	{object}	api.ErrorResponse	"Ошибка авторизации"
+//	@Failure		500	{object}	api.ErrorResponse	"Ошибка сервера"
+//
+//	@Router			/household [post]
+//
+//	@Security		jwtAuth
+func (h *HouseholdHandlers) CreateHousehold(c *gin.Context) {
+	const op = "handlers.CreateHousehold"
+
+	log := h.log.WithField("op", op)
+
+	log.Info("start create household")
+
+	var req RequestHousehold
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.WithField("err", err).Error("error valid JSON")
+		api.ResponseError(c, http.StatusBadRequest, "error valid JSON")
+		return
+	}
+
+	userID, ok := user(c, log)
+	if !ok {
+		return
+	}
+
+	household, err := h.s.CreateHousehold(c.Request.Context(), userID, domain.CreateHousehold{Name: req.Name})
+	if err != nil {
+		log.WithField("err", err).Error("error create household")
+		api.RegistrationError(c, err)
+		return
+	}
+
+	log.Info("success create household")
+
+	api.ResponseOK(c, household)
+}
+
+// GetHousehold godoc
+//
+//	@Summary		Бюджет
+//	@Description	Бюджет с участниками и их ролями, доступен только участникам
+//
+//	@Tags			Household
+//
+//	@Produce		json
+//	@Param			id	path		int					true	"id бюджета"
+//	@Success		200	{object}	api.SuccessResponse	"Бюджет"
+//
+//	@Failure		400	{object}	api.ErrorResponse	"Некорректные входные данные"
+//	@Failure		401	{object}	api.ErrorResponse	"Ошибка авторизации"
+//	@Failure		403	{object}	api.ErrorResponse	"Нет доступа к бюджету"
+//	@Failure		404	{object}	api.ErrorResponse	"Бюджет не найден"
+//	@Failure		500	{object}	api.ErrorResponse	"Ошибка сервера"
+//
+//	@Router			/household/{id} [get]
+//
+//	@Security		jwtAuth
+func (h *HouseholdHandlers) GetHousehold(c *gin.Context) {
+	const op = "handlers.GetHousehold"
+
+	log := h.log.WithField("op", op)
+
+	log.Info("start get household")
+
+	member, ok := householdMember(c, log)
+	if !ok {
+		return
+	}
+
+	household, err := h.s.Household(c.Request.Context(), member)
+	if err != nil {
+		log.WithField("err", err).Error("error get household")
+		api.RegistrationError(c, err)
+		return
+	}
+
+	log.Info("success get household")
+
+	api.ResponseOK(c, household)
+}
+
+// Invite godoc
+//
+//	@Summary		Приглашение в бюджет
+//	@Description	Отправка приглашения на email с ролью editor или viewer. Приглашает только владелец, личный бюджет не делится
+//
+//	@Tags			Household
+//
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		int					true	"id бюджета"
+//	@Param			req	body		RequestInvite		true	"email и роль"
+//	@Success		200	{object}	api.SuccessResponse	"Приглашение отправлено"
+//
+//	@Failure		400	{object}	api.ErrorResponse	"Некорректные входные данные"
+//	@Failure		401	{object}	api.ErrorResponse	"Ошибка авторизации"
+//	@Failure		403	{object}	api.ErrorResponse	"Нужна роль owner"
+//	@Failure		404	{object}	api.ErrorResponse	"Бюджет не найден"
+//	@Failure		500	{object}	api.ErrorResponse	"Ошибка сервера"
+//
+//	@Router			/household/{id}/invitations [post]
+//
+//	@Security		jwtAuth
+func (h *HouseholdHandlers) Invite(c *gin.Context) {
+	const op = "handlers.Invite"
+
+	log := h.log.WithField("op", op)
+
+	log.Info("start invite member")
+
+	var req RequestInvite
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.WithField("err", err).Error("error valid JSON")
+		api.ResponseError(c, http.StatusBadRequest, "error valid JSON")
+		return
+	}
+
+	member, ok := householdMember(c, log)
+	if !ok {
+		return
+	}
+
+	invitation, err := h.s.Invite(c.Request.Context(), member, domain.InviteMember{Email: req.Email, Role: req.Role})
+	if err != nil {
+		log.WithField("err", err).Error("error invite member")
+		api.RegistrationError(c, err)
+		return
+	}
+
+	log.Info("success invite member")
+
+	api.ResponseOK(c, invitation)
+}
+
+// Accept godoc
+//
+//	@Summary		Принятие приглашения
+//	@Description	Принятие приглашения по токену из письма, приглашение должно быть отправлено на email пользователя
+//
+//	@Tags			Household
+//
+//	@Accept			json
+//	@Produce		json
+//	@Param			req	body		RequestAccept		true	"токен приглашения"
+//	@Success		200	{object}	api.SuccessResponse	"Пользователь добавлен в бюджет"
+//
+//	@Failure		400	{object}	api.ErrorResponse	"Некорректное или просроченное приглашение"
+//	@Failure		401	{object}	api.ErrorResponse	"Ошибка авторизации"
+//	@Failure		500	{object}	api.ErrorResponse	"Ошибка сервера"
+//
+//	@Router			/household/invitations/accept [post]
+//
+//	@Security		jwtAuth
+func (h *HouseholdHandlers) Accept(c *gin.Context) {
+	const op = "handlers.Accept"
+
+	log := h.log.WithField("op", op)
+
+	log.Info("start accept invitation")
+
+	var req RequestAccept
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.WithField("err", err).Error("error valid JSON")
+		api.ResponseError(c, http.StatusBadRequest, "error valid JSON")
+		return
+	}
+
+	userID, ok := user(c, log)
+	if !ok {
+		return
+	}
+
+	household, err := h.s.Accept(c.Request.Context(), userID, domain.AcceptInvitation{Token: req.Token})
+	if err != nil {
+		log.WithField("err", err).Error("error accept invitation")
+		api.RegistrationError(c, err)
+		return
+	}
+
+	log.Info("success accept invitation")
+
+	api.ResponseOK(c, household)
+}
+
+// UpdateMember godoc
+//
+//	@Summary		Изменение роли участника
+//	@Description	Назначение участнику роли editor или viewer, роли меняет только владелец
+//
+//	@Tags			Household
+//
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int					true	"id бюджета"
+//	@Param			user	path		int					true	"id участника"
+//	@Param			req		body		RequestMember		true	"роль"
+//	@Success		200		{object}	api.SuccessResponse	"Роль изменена"
+//
+//	@Failure		400		{object}	api.ErrorResponse	"Некорректные входные данные"
+//	@Failure		401		{object}	api.ErrorResponse	"Ошибка авторизации"
+//	@Failure		403		{object}	api.ErrorResponse	"Нужна роль owner"
+//	@Failure		404		{object}	api.ErrorResponse	"Участник не найден"
+//	@Failure		500		{object}	api.ErrorResponse	"Ошибка сервера"
+//
+//	@Router			/household/{id}/members/{user} [put]
+//
+//	@Security		jwtAuth
+func (h *HouseholdHandlers) UpdateMember(c *gin.Context) {
+	const op = "handlers.UpdateMember"
+
+	log := h.log.WithField("op", op)
+
+	log.Info("start update member")
+
+	var req RequestMember
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.WithField("err", err).Error("error valid JSON")
+		api.ResponseError(c, http.StatusBadRequest, "error valid JSON")
+		return
+	}
+
+	member, userID, ok := members(c, log)
+	if !ok {
+		return
+	}
+
+	if err := h.s.UpdateMember(c.Request.Context(), member, userID, domain.UpdateMember{Role: req.Role}); err != nil {
+		log.WithField("err", err).Error("error update member")
+		api.RegistrationError(c, err)
+		return
+	}
+
+	log.Info("success update member")
+
+	api.ResponseOK(c, "role changed")
+}
+
+// RemoveMember godoc
+//
+//	@Summary		Удаление участника
+//	@Description	Владелец удаляет участников, участник может выйти из бюджета, удалив себя
+//
+//	@Tags			Household
+//
+//	@Produce		json
+//	@Param			id		path		int					true	"id бюджета"
+//	@Param			user	path		int					true	"id участника"
+//	@Success		200		{object}	api.SuccessResponse	"Участник удален"
+//
+//	@Failure		400		{object}	api.ErrorResponse	"Некорректные входные данные"
+//	@Failure		401		{object}	api.ErrorResponse	"Ошибка авторизации"
+//	@Failure		403		{object}	api.ErrorResponse	"Нужна роль owner"
+//	@Failure		404		{object}	api.ErrorResponse	"Участник не найден"
+//	@Failure		500		{object}	api.ErrorResponse	"Ошибка сервера"
+//
+//	@Router			/household/{id}/members/{user} [delete]
+//
+//	@Security		jwtAuth
+func (h *HouseholdHandlers) RemoveMember(c *gin.Context) {
+	const op = "handlers.RemoveMember"
+
+	log := h.log.WithField("op", op)
+
+	log.Info("start remove member")
+
+	member, userID, ok := members(c, log)
+	if !ok {
+		return
+	}
+
+	if err := h.s.RemoveMember(c.Request.Context(), member, userID); err != nil {
+		log.WithField("err", err).Error("error remove member")
+		api.RegistrationError(c, err)
+		return
+	}
+
+	log.Info("success remove member")
+
+	api.ResponseOK(c, "member removed")
+}
+
+// user returns the id of the user making the request, on error the response is already written.
+func user(c *gin.Context, log *logrus.Entry) (uint, bool) {
+	userID, ok := c.Get("userID")
+	if !ok {
+		log.Error("error get userID")
+		api.ResponseError(c, http.StatusInternalServerError, "error server")
+		return 0, false
+	}
+
+	return userID.(uint), true
+}
+
+// householdMember returns the user making the request as a member of the household in the
+// path, the role is checked by the repository.
+func householdMember(c *gin.Context, log *logrus.Entry) (domain.Member, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		log.WithField("err", err).Error("error get id")
+		api.ResponseError(c, http.StatusBadRequest, "invalid household id")
+		return domain.Member{}, false
+	}
+
+	userID, ok := user(c, log)
+	if !ok {
+		return domain.Member{}, false
+	}
+
+	return domain.Member{UserID: userID, HouseholdID: uint(id)}, true
+}
+
+// members returns the user making the request and the id of the member in the path.
+func members(c *gin.Context, log *logrus.Entry) (domain.Member, uint, bool) {
+	id, err := strconv.ParseUint(c.Param("user"), 10, 64)
+	if err != nil {
+		log.WithField("err", err).Error("error get user id")
+		api.ResponseError(c, http.StatusBadRequest, "invalid user id")
+		return domain.Member{}, 0, false
+	}
+
+	member, ok := householdMember(c, log)
+	if !ok {
+		return domain.Member{}, 0, false
+	}
+
+	return member, uint(id), true
+}
@@ -0,0 +1,47 @@
+package householdHandlers
+
+import (
+	"context"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type householdServicMock struct {
+	mock.Mock
+}
+
+func (m *householdServicMock) Households(ctx context.Context, userID uint) ([]domain.Household, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]domain.Household), args.Error(1)
+}
+
+func (m *householdServicMock) Household(ctx context.Context, member domain.Member) (domain.Household, error) {
+	args := m.Called(ctx, member)
+	return args.Get(0).(domain.Household), args.Error(1)
+}
+
+func (m *householdServicMock) CreateHousehold(ctx context.Context, userID uint, req domain.CreateHousehold) (domain.Household, error) {
+	args := m.Called(ctx, userID, req)
+	return args.Get(0).(domain.Household), args.Error(1)
+}
+
+func (m *householdServicMock) Invite(ctx context.Context, member domain.Member, req domain.InviteMember) (domain.HouseholdInvitation, error) {
+	args := m.Called(ctx, member, req)
+	return args.Get(0).(domain.HouseholdInvitation), args.Error(1)
+}
+
+func (m *householdServicMock) Accept(ctx context.Context, userID uint, req domain.AcceptInvitation) (domain.Household, error) {
+	args := m.Called(ctx, userID, req)
+	return args.Get(0).(domain.Household), args.Error(1)
+}
+
+func (m *householdServicMock) UpdateMember(ctx context.Context, member domain.Member, userID uint, req domain.UpdateMember) error {
+	args := m.Called(ctx, member, userID, req)
+	return args.Error(0)
+}
+
+func (m *householdServicMock) RemoveMember(ctx context.Context, member domain.Member, userID uint) error {
+	args := m.Called(ctx, member, userID)
+	return args.Error(0)
+}
@@ -0,0 +1,352 @@
+package householdHandlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/financial_tracer/internal/servic/household"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/assert/v2"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/mock"
+)
+
+var member = domain.Member{UserID: 1, HouseholdID: 7}
+
+func TestListHouseholds(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name    string
+		noUser  bool
+		mockErr error
+		status  int
+	}{
+		{name: "success", status: http.StatusOK},
+		{name: "error database", mockErr: household.ErrDatabase, status: http.StatusInternalServerError},
+		{name: "error no user", noUser: true, status: http.StatusInternalServerError},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			if !tc.noUser {
+				c.Set("userID", uint(1))
+			}
+
+			svc := new(householdServicMock)
+			ctx := context.Background()
+			svc.On("Households", mock.Anything, uint(1)).Return([]domain.Household{}, tc.mockErr)
+
+			h := CreateHouseholdHandlers(svc, logrus.New(), ctx)
+
+			req := http.Request{Header: make(http.Header), URL: &url.URL{}}
+			c.Request = req.WithContext(ctx)
+
+			h.ListHouseholds(c)
+
+			assert.Equal(t, tc.status, w.Code)
+			if tc.noUser {
+				svc.AssertNotCalled(t, "Households", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestCreateHousehold(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name         string
+		body         RequestHousehold
+		invalidJSON  bool
+		mockErr      error
+		status       int
+		shouldCallDB bool
+	}{
+		{name: "success", body: RequestHousehold{Name: "семья"}, status: http.StatusOK, shouldCallDB: true},
+		{name: "error database", body: RequestHousehold{Name: "семья"}, mockErr: household.ErrDatabase, status: http.StatusInternalServerError, shouldCallDB: true},
+		{name: "error json", invalidJSON: true, status: http.StatusBadRequest},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Set("userID", uint(1))
+
+			svc := new(householdServicMock)
+			ctx := context.Background()
+			svc.On("CreateHousehold", mock.Anything, uint(1), domain.CreateHousehold{Name: tc.body.Name}).Return(domain.Household{}, tc.mockErr)
+
+			h := CreateHouseholdHandlers(svc, logrus.New(), ctx)
+
+			req := http.Request{Header: make(http.Header), URL: &url.URL{}}
+			if tc.invalidJSON {
+				req.Body = io.NopCloser(bytes.NewBufferString("{"))
+			} else {
+				b, _ := json.Marshal(tc.body)
+				req.Body = io.NopCloser(bytes.NewBuffer(b))
+			}
+			req.Header.Set("content-type", "application/json")
+			c.Request = req.WithContext(ctx)
+
+			h.CreateHousehold(c)
+
+			assert.Equal(t, tc.status, w.Code)
+			if tc.shouldCallDB {
+				svc.AssertExpectations(t)
+			} else {
+				svc.AssertNotCalled(t, "CreateHousehold", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestGetHousehold(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name         string
+		id           string
+		mockErr      error
+		status       int
+		shouldCallDB bool
+	}{
+		{name: "success", id: "7", status: http.StatusOK, shouldCallDB: true},
+		{name: "error forbidden", id: "7", mockErr: household.ErrForbidden, status: http.StatusForbidden, shouldCallDB: true},
+		{name: "error not found", id: "7", mockErr: household.ErrNoFound, status: http.StatusNotFound, shouldCallDB: true},
+		{name: "error id", id: "seven", status: http.StatusBadRequest},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Set("userID", uint(1))
+			c.Params = gin.Params{{Key: "id", Value: tc.id}}
+
+			svc := new(householdServicMock)
+			ctx := context.Background()
+			svc.On("Household", mock.Anything, member).Return(domain.Household{}, tc.mockErr)
+
+			h := CreateHouseholdHandlers(svc, logrus.New(), ctx)
+
+			req := http.Request{Header: make(http.Header), URL: &url.URL{}}
+			c.Request = req.WithContext(ctx)
+
+			h.GetHousehold(c)
+
+			assert.Equal(t, tc.status, w.Code)
+			if tc.shouldCallDB {
+				svc.AssertExpectations(t)
+			} else {
+				svc.AssertNotCalled(t, "Household", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestInvite(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	body := RequestInvite{Email: "anna@gmail.com", Role: domain.HouseholdEditor}
+
+	tests := []struct {
+		name         string
+		body         RequestInvite
+		invalidJSON  bool
+		mockErr      error
+		status       int
+		shouldCallDB bool
+	}{
+		{name: "success", body: body, status: http.StatusOK, shouldCallDB: true},
+		{name: "error not owner", body: body, mockErr: household.ErrForbidden, status: http.StatusForbidden, shouldCallDB: true},
+		{name: "error personal", body: body, mockErr: household.ErrPersonal, status: http.StatusBadRequest, shouldCallDB: true},
+		{name: "error mail", body: body, mockErr: household.ErrMail, status: http.StatusInternalServerError, shouldCallDB: true},
+		{name: "error json", invalidJSON: true, status: http.StatusBadRequest},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Set("userID", uint(1))
+			c.Params = gin.Params{{Key: "id", Value: "7"}}
+
+			svc := new(householdServicMock)
+			ctx := context.Background()
+			svc.On("Invite", mock.Anything, member, domain.InviteMember{Email: tc.body.Email, Role: tc.body.Role}).Return(domain.HouseholdInvitation{}, tc.mockErr)
+
+			h := CreateHouseholdHandlers(svc, logrus.New(), ctx)
+
+			req := http.Request{Header: make(http.Header), URL: &url.URL{}}
+			if tc.invalidJSON {
+				req.Body = io.NopCloser(bytes.NewBufferString("{"))
+			} else {
+				b, _ := json.Marshal(tc.body)
+				req.Body = io.NopCloser(bytes.NewBuffer(b))
+			}
+			req.Header.Set("content-type", "application/json")
+			c.Request = req.WithContext(ctx)
+
+			h.Invite(c)
+
+			assert.Equal(t, tc.status, w.Code)
+			if tc.shouldCallDB {
+				svc.AssertExpectations(t)
+			} else {
+				svc.AssertNotCalled(t, "Invite", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestAccept(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name         string
+		body         RequestAccept
+		invalidJSON  bool
+		mockErr      error
+		status       int
+		shouldCallDB bool
+	}{
+		{name: "success", body: RequestAccept{Token: "token"}, status: http.StatusOK, shouldCallDB: true},
+		{name: "error invitation", body: RequestAccept{Token: "token"}, mockErr: household.ErrInvitation, status: http.StatusBadRequest, shouldCallDB: true},
+		{name: "error already member", body: RequestAccept{Token: "token"}, mockErr: household.ErrDuplicated, status: http.StatusBadRequest, shouldCallDB: true},
+		{name: "error json", invalidJSON: true, status: http.StatusBadRequest},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Set("userID", uint(2))
+
+			svc := new(householdServicMock)
+			ctx := context.Background()
+			svc.On("Accept", mock.Anything, uint(2), domain.AcceptInvitation{Token: tc.body.Token}).Return(domain.Household{}, tc.mockErr)
+
+			h := CreateHouseholdHandlers(svc, logrus.New(), ctx)
+
+			req := http.Request{Header: make(http.Header), URL: &url.URL{}}
+			if tc.invalidJSON {
+				req.Body = io.NopCloser(bytes.NewBufferString("{"))
+			} else {
+				b, _ := json.Marshal(tc.body)
+				req.Body = io.NopCloser(bytes.NewBuffer(b))
+			}
+			req.Header.Set("content-type", "application/json")
+			c.Request = req.WithContext(ctx)
+
+			h.Accept(c)
+
+			assert.Equal(t, tc.status, w.Code)
+			if tc.shouldCallDB {
+				svc.AssertExpectations(t)
+			} else {
+				svc.AssertNotCalled(t, "Accept", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestUpdateMember(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name         string
+		user         string
+		body         RequestMember
+		invalidJSON  bool
+		mockErr      error
+		status       int
+		shouldCallDB bool
+	}{
+		{name: "success", user: "2", body: RequestMember{Role: domain.HouseholdViewer}, status: http.StatusOK, shouldCallDB: true},
+		{name: "error not owner", user: "2", body: RequestMember{Role: domain.HouseholdViewer}, mockErr: household.ErrForbidden, status: http.StatusForbidden, shouldCallDB: true},
+		{name: "error user id", user: "anna", body: RequestMember{Role: domain.HouseholdViewer}, status: http.StatusBadRequest},
+		{name: "error json", user: "2", invalidJSON: true, status: http.StatusBadRequest},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Set("userID", uint(1))
+			c.Params = gin.Params{{Key: "id", Value: "7"}, {Key: "user", Value: tc.user}}
+
+			svc := new(householdServicMock)
+			ctx := context.Background()
+			svc.On("UpdateMember", mock.Anything, member, uint(2), domain.UpdateMember{Role: tc.body.Role}).Return(tc.mockErr)
+
+			h := CreateHouseholdHandlers(svc, logrus.New(), ctx)
+
+			req := http.Request{Header: make(http.Header), URL: &url.URL{}}
+			if tc.invalidJSON {
+				req.Body = io.NopCloser(bytes.NewBufferString("{"))
+			} else {
+				b, _ := json.Marshal(tc.body)
+				req.Body = io.NopCloser(bytes.NewBuffer(b))
+			}
+			req.Header.Set("content-type", "application/json")
+			c.Request = req.WithContext(ctx)
+
+			h.UpdateMember(c)
+
+			assert.Equal(t, tc.status, w.Code)
+			if tc.shouldCallDB {
+				svc.AssertExpectations(t)
+			} else {
+				svc.AssertNotCalled(t, "UpdateMember", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestRemoveMember(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name    string
+		mockErr error
+		status  int
+	}{
+		{name: "success", status: http.StatusOK},
+		{name: "error not owner", mockErr: household.ErrForbidden, status: http.StatusForbidden},
+		{name: "error not found", mockErr: household.ErrNoFound, status: http.StatusNotFound},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Set("userID", uint(1))
+			c.Params = gin.Params{{Key: "id", Value: "7"}, {Key: "user", Value: "2"}}
+
+			svc := new(householdServicMock)
+			ctx := context.Background()
+			svc.On("RemoveMember", mock.Anything, member, uint(2)).Return(tc.mockErr)
+
+			h := CreateHouseholdHandlers(svc, logrus.New(), ctx)
+
+			req := http.Request{Header: make(http.Header), URL: &url.URL{}}
+			c.Request = req.WithContext(ctx)
+
+			h.RemoveMember(c)
+
+			assert.Equal(t, tc.status, w.Code)
+			svc.AssertExpectations(t)
+		})
+	}
+}
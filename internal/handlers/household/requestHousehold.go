@@ -0,0 +1,22 @@
+package householdHandlers
+
+// RequestHousehold represents create household request
+type RequestHousehold struct {
+	Name string `json:"name" binding:"required" example:"семья"`
+}
+
+// RequestInvite represents invite member request, role is editor or viewer
+type RequestInvite struct {
+	Email string `json:"email" binding:"required" example:"anna@gmail.com"`
+	Role  string `json:"role" binding:"required" example:"editor"`
+}
+
+// RequestAccept represents accept invitation request, token is taken from the invitation mail
+type RequestAccept struct {
+	Token string `json:"token" binding:"required" example:"Xy3kQ9..."`
+}
+
+// RequestMember represents change member role request, role is editor or viewer
+type RequestMember struct {
+	Role string `json:"role" binding:"required" example:"viewer"`
+}
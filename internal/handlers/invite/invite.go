@@ -0,0 +1,177 @@
+package inviteHandlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/financial_tracer/internal/handlers/api"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type InviteServic interface {
+	Create(ctx context.Context, adminID uint, req domain.CreateInviteCode) (domain.NewInviteCode, error)
+	List(ctx context.Context) ([]domain.InviteCode, error)
+	Revoke(ctx context.Context, adminID uint, id uint) error
+}
+
+type InviteHandlers struct {
+	s   InviteServic
+	log *logrus.Logger
+	ctx context.Context
+}
+
+func CreateInviteHandlers(s InviteServic, log *logrus.Logger, ctx context.Context) *InviteHandlers {
+	return &InviteHandlers{
+		s:   s,
+		log: log,
+		ctx: ctx,
+	}
+}
+
+// CreateInvite godoc
+//
+//	@Summary		Создание кода приглашения
+//	@Description	Код приглашения для регистрации в режиме invite. Код возвращается один раз, хранится только его хеш
+//
+//	@Tags			Admin
+//
+//	@Accept			json
+//	@Produce		json
+//	@Param			req	body		RequestInvite		true	"параметры кода"
+//	@Success		200	{object}	api.SuccessResponse	"Код приглашения"
+//
+//	@Failure		400	{object}	api.ErrorResponse	"Некорректные входные данные"
+//	@Failure		401	{object}	api.ErrorResponse	"Ошибка авторизации"
+//	@Failure		403	{object}	api.ErrorResponse	"Нужна роль admin"
+//	@Failure		500	{object}	api.ErrorResponse	"Ошибка сервера"
+//
+//	@Router			/admin/invites [post]
+//
+//	@Security		jwtAuth
+func (h *InviteHandlers) CreateInvite(c *gin.Context) {
+	const op = "handlers.CreateInvite"
+
+	log := h.log.WithField("op", op)
+
+	log.Info("start create invite code")
+
+	var req RequestInvite
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.WithField("err", err).Error("error valid JSON")
+		api.ResponseError(c, http.StatusBadRequest, "error valid JSON")
+		return
+	}
+
+	adminID, ok := c.Get("userID")
+	if !ok {
+		log.Error("error get userID")
+		api.ResponseError(c, http.StatusInternalServerError, "error server")
+		return
+	}
+
+	code, err := h.s.Create(c.Request.Context(), adminID.(uint), domain.CreateInviteCode{
+		Note:      req.Note,
+		MaxUses:   req.MaxUses,
+		ExpiresIn: req.ExpiresIn,
+	})
+	if err != nil {
+		log.WithField("err", err).Error("error create invite code")
+		api.RegistrationError(c, err)
+		return
+	}
+
+	log.Info("success create invite code")
+
+	api.ResponseOK(c, code)
+}
+
+// ListInvites godoc
+//
+//	@Summary		Коды приглашения
+//	@Description	Неотозванные коды приглашения от новых к старым с количеством использований, включая истекшие и исчерпанные
+//
+//	@Tags			Admin
+//
+//	@Produce		json
+//	@Success		200	{object}	api.SuccessResponse	"Коды приглашения"
+//
+//	@Failure		401	{object}	api.ErrorResponse	"Ошибка авторизации"
+//	@Failure		403	{object}	api.ErrorResponse	"Нужна роль admin"
+//	@Failure		500	{object}	api.ErrorResponse	"Ошибка сервера"
+//
+//	@Router			/admin/invites [get]
+//
+//	@Security		jwtAuth
+func (h *InviteHandlers) ListInvites(c *gin.Context) {
+	const op = "handlers.ListInvites"
+
+	log := h.log.WithField("op", op)
+
+	log.Info("start list invite codes")
+
+	codes, err := h.s.List(c.Request.Context())
+	if err != nil {
+		log.WithField("err", err).Error("error list invite codes")
+		api.RegistrationError(c, err)
+		return
+	}
+
+	log.Info("success list invite codes")
+
+	api.ResponseOK(c, codes)
+}
+
+// RevokeInvite godoc
+//
+//	@Summary		Отзыв кода приглашения
+//	@Description	Отозванный код больше нельзя использовать для регистрации
+//
+//	@Tags			Admin
+//
+//	@Produce		json
+//	@Param			id	path		int					true	"id кода приглашения"
+//	@Success		200	{object}	api.SuccessResponse	"Код отозван"
+//
+//	@Failure		400	{object}	api.ErrorResponse	"Некорректные входные данные"
+//	@Failure		401	{object}	api.ErrorResponse	"Ошибка авторизации"
+//	@Failure		403	{object}	api.ErrorResponse	"Нужна роль admin"
+//	@Failure		404	{object}	api.ErrorResponse	"Код не найден"
+//	@Failure		500	{object}	api.ErrorResponse	"Ошибка сервера"
+//
+//	@Router			/admin/invites/{id} [delete]
+//
+//	@Security		jwtAuth
+func (h *InviteHandlers) RevokeInvite(c *gin.Context) {
+	const op = "handlers.RevokeInvite"
+
+	log := h.log.WithField("op", op)
+
+	log.Info("start revoke invite code")
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		log.WithField("err", err).Error("error get id")
+		api.ResponseError(c, http.StatusBadRequest, "invalid invite id")
+		return
+	}
+
+	adminID, ok := c.Get("userID")
+	if !ok {
+		log.Error("error get userID")
+		api.ResponseError(c, http.StatusInternalServerError, "error server")
+		return
+	}
+
+	if err := h.s.Revoke(c.Request.Context(), adminID.(uint), uint(id)); err != nil {
+		log.WithField("err", err).Error("error revoke invite code")
+		api.RegistrationError(c, err)
+		return
+	}
+
+	log.Info("success revoke invite code")
+
+	api.ResponseOK(c, "invite code revoked")
+}
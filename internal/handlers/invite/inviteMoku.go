@@ -0,0 +1,27 @@
+package inviteHandlers
+
+import (
+	"context"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type inviteServicMock struct {
+	mock.Mock
+}
+
+func (m *inviteServicMock) Create(ctx context.Context, adminID uint, req domain.CreateInviteCode) (domain.NewInviteCode, error) {
+	args := m.Called(ctx, adminID, req)
+	return args.Get(0).(domain.NewInviteCode), args.Error(1)
+}
+
+func (m *inviteServicMock) List(ctx context.Context) ([]domain.InviteCode, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]domain.InviteCode), args.Error(1)
+}
+
+func (m *inviteServicMock) Revoke(ctx context.Context, adminID uint, id uint) error {
+	args := m.Called(ctx, adminID, id)
+	return args.Error(0)
+}
@@ -0,0 +1,136 @@
+package inviteHandlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/financial_tracer/internal/servic/invite"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/assert/v2"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreateInvite(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name         string
+		body         RequestInvite
+		invalidJSON  bool
+		mockErr      error
+		status       int
+		shouldCallDB bool
+	}{
+		{
+			name:         "success",
+			body:         RequestInvite{Note: "team", MaxUses: 5, ExpiresIn: 7},
+			status:       http.StatusOK,
+			shouldCallDB: true,
+		},
+		{
+			name:         "error database",
+			body:         RequestInvite{MaxUses: 1},
+			mockErr:      invite.ErrDatabase,
+			status:       http.StatusInternalServerError,
+			shouldCallDB: true,
+		},
+		{
+			name:   "error max uses",
+			body:   RequestInvite{Note: "team"},
+			status: http.StatusBadRequest,
+		},
+		{
+			name:        "error json",
+			invalidJSON: true,
+			status:      http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Set("userID", uint(1))
+
+			svc := new(inviteServicMock)
+			ctx := context.Background()
+			svc.On("Create", mock.Anything, uint(1), domain.CreateInviteCode{
+				Note:      tc.body.Note,
+				MaxUses:   tc.body.MaxUses,
+				ExpiresIn: tc.body.ExpiresIn,
+			}).Return(domain.NewInviteCode{Code: "code"}, tc.mockErr)
+
+			h := CreateInviteHandlers(svc, logrus.New(), ctx)
+
+			req := http.Request{Header: make(http.Header), URL: &url.URL{}}
+			if tc.invalidJSON {
+				req.Body = io.NopCloser(bytes.NewBufferString("{"))
+			} else {
+				b, _ := json.Marshal(tc.body)
+				req.Body = io.NopCloser(bytes.NewBuffer(b))
+			}
+			req.Header.Set("content-type", "application/json")
+			c.Request = req.WithContext(ctx)
+
+			h.CreateInvite(c)
+
+			assert.Equal(t, tc.status, w.Code)
+			if tc.shouldCallDB {
+				svc.AssertExpectations(t)
+			} else {
+				svc.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestRevokeInvite(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name         string
+		id           string
+		mockErr      error
+		status       int
+		shouldCallDB bool
+	}{
+		{name: "success", id: "4", status: http.StatusOK, shouldCallDB: true},
+		{name: "error not found", id: "4", mockErr: invite.ErrNoFound, status: http.StatusNotFound, shouldCallDB: true},
+		{name: "error id", id: "four", status: http.StatusBadRequest},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Set("userID", uint(1))
+			c.Params = gin.Params{{Key: "id", Value: tc.id}}
+
+			svc := new(inviteServicMock)
+			ctx := context.Background()
+			svc.On("Revoke", mock.Anything, uint(1), uint(4)).Return(tc.mockErr)
+
+			h := CreateInviteHandlers(svc, logrus.New(), ctx)
+
+			req := http.Request{Header: make(http.Header), URL: &url.URL{}}
+			c.Request = req.WithContext(ctx)
+
+			h.RevokeInvite(c)
+
+			assert.Equal(t, tc.status, w.Code)
+			if tc.shouldCallDB {
+				svc.AssertExpectations(t)
+			} else {
+				svc.AssertNotCalled(t, "Revoke", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
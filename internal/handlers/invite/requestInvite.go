@@ -0,0 +1,9 @@
+package inviteHandlers
+
+// RequestInvite represents create invite code request, expires_in is the lifetime in days,
+// 0 means the code does not expire
+type RequestInvite struct {
+	Note      string `json:"note" example:"finance team"`
+	MaxUses   int    `json:"max_uses" binding:"required" example:"5"`
+	ExpiresIn int    `json:"expires_in" example:"7"`
+}
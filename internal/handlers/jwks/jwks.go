@@ -0,0 +1,40 @@
+package jwksHandlers
+
+import (
+	"net/http"
+
+	jwttoken "github.com/financial_tracer/internal/lib/jwtToken"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// cacheMaxAge lets verifiers cache the key set, a new key should be published for at
+// least this long before it starts signing.
+const cacheMaxAge = "public, max-age=3600"
+
+type JWKSServic interface {
+	JWKS() jwttoken.JWKS
+}
+
+type JWKSHandlers struct {
+	k   JWKSServic
+	log *logrus.Logger
+}
+
+func CreateJWKSHandlers(k JWKSServic, log *logrus.Logger) *JWKSHandlers {
+	return &JWKSHandlers{
+		k:   k,
+		log: log,
+	}
+}
+
+// JWKS publishes the public keys that verify financial_tracer tokens at
+// /.well-known/jwks.json (RFC 7517). With HS256 signing the set is empty.
+func (h *JWKSHandlers) JWKS(c *gin.Context) {
+	const op = "handlers.JWKS"
+
+	h.log.WithField("op", op).Debug("get jwks")
+
+	c.Header("Cache-Control", cacheMaxAge)
+	c.JSON(http.StatusOK, h.k.JWKS())
+}
@@ -0,0 +1,40 @@
+package jwksHandlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	jwttoken "github.com/financial_tracer/internal/lib/jwtToken"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/assert/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type jwksServicStub jwttoken.JWKS
+
+func (s jwksServicStub) JWKS() jwttoken.JWKS {
+	return jwttoken.JWKS(s)
+}
+
+func TestJWKS(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	keys := jwttoken.JWKS{Keys: []jwttoken.JWK{{Kty: "OKP", Kid: "k1", Use: "sig", Alg: "EdDSA", Crv: "Ed25519", X: "11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"}}}
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil)
+
+	h := CreateJWKSHandlers(jwksServicStub(keys), logrus.New())
+	h.JWKS(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, cacheMaxAge, w.Header().Get("Cache-Control"))
+
+	var res jwttoken.JWKS
+	err := json.Unmarshal(w.Body.Bytes(), &res)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, keys, res)
+}
@@ -0,0 +1,137 @@
+package ledgerHandlers
+
+import (
+	"context"
+	"io"
+	"net/http"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/financial_tracer/internal/handlers/api"
+	"github.com/financial_tracer/internal/lib/journal"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+const maxJournalSize = 10 << 20
+
+type ExportJournalServic interface {
+	ExportJournal(ctx context.Context, member domain.Member, format string) ([]byte, journal.Format, error)
+}
+
+type ImportJournalServic interface {
+	ImportJournal(ctx context.Context, member domain.Member, format string, r io.Reader) (domain.JournalImport, error)
+}
+
+type LedgerHandlers struct {
+	e   ExportJournalServic
+	i   ImportJournalServic
+	log *logrus.Logger
+	ctx context.Context
+}
+
+func CreateLedgerHandlers(e ExportJournalServic,
+	i ImportJournalServic,
+	log *logrus.Logger,
+	ctx context.Context) *LedgerHandlers {
+	return &LedgerHandlers{
+		e:   e,
+		i:   i,
+		log: log,
+		ctx: ctx,
+	}
+}
+
+// ExportJournal godoc
+//
+//	@Summary		Экспорт в журнал
+//	@Description	Экспорт категорий и транзакций пользователя в формате ledger, hledger или beancount. Категории становятся счетами Expenses
+//	@Tags			journal
+//	@Produce		plain
+//	@Param			format			query		string				false	"формат журнала: ledger, hledger, beancount"	default(ledger)
+//	@Param			X-Household-ID	header		int					false	"id общего бюджета, по умолчанию личный"
+//	@Success		200				{string}	string				"Журнал"
+//
+//	@Failure		401				{object}	api.ErrorResponse	"Ошибка авторизации"
+//	@Failure		400				{object}	api.ErrorResponse	"Неизвестный формат"
+//	@Failure		404				{object}	api.ErrorResponse	"Пользователь не найден"
+//	@Failure		500				{object}	api.ErrorResponse	"Ошибка сервера"
+//	@Failure		403				{object}	api.ErrorResponse	"Нет доступа к бюджету"
+//
+//	@Router			/journal/export [get]
+//
+//	@Security		jwtAuth
+func (h *LedgerHandlers) ExportJournal(c *gin.Context) {
+	const op = "handlers.ExportJournal"
+
+	log := h.log.WithField("op", op)
+
+	log.Info("start export journal")
+
+	member, ok := api.Member(c)
+	if !ok {
+		log.Error("error get member")
+		api.ResponseError(c, http.StatusInternalServerError, "error server")
+		return
+	}
+
+	data, format, err := h.e.ExportJournal(c.Request.Context(), member, c.Query("format"))
+	if err != nil {
+		log.WithField("err", err).Error("error export journal")
+		api.RegistrationError(c, err)
+		return
+	}
+
+	log.Info("success export journal")
+
+	c.Header("Content-Disposition", `attachment; filename="financial_tracer`+format.Extension()+`"`)
+	c.Data(http.StatusOK, "text/plain; charset=utf-8", data)
+}
+
+// ImportJournal godoc
+//
+//	@Summary		Импорт из журнала
+//	@Description	Импорт категорий и транзакций из журнала ledger, hledger или beancount. Уже существующие транзакции пропускаются
+//	@Tags			journal
+//	@Accept			plain
+//	@Produce		json
+//	@Param			format			query		string				false	"формат журнала: ledger, hledger, beancount"	default(ledger)
+//	@Param			journal			body		string				true	"текст журнала"
+//	@Param			X-Household-ID	header		int					false	"id общего бюджета, по умолчанию личный"
+//	@Success		200				{object}	api.SuccessResponse	"Результат импорта"
+//
+//	@Failure		401				{object}	api.ErrorResponse	"Ошибка авторизации"
+//	@Failure		400				{object}	api.ErrorResponse	"Некорректный журнал"
+//	@Failure		404				{object}	api.ErrorResponse	"Пользователь не найден"
+//	@Failure		500				{object}	api.ErrorResponse	"Ошибка сервера"
+//	@Failure		403				{object}	api.ErrorResponse	"Нет доступа к бюджету"
+//
+//	@Router			/journal/import [post]
+//
+//	@Security		jwtAuth
+func (h *LedgerHandlers) ImportJournal(c *gin.Context) {
+	const op = "handlers.ImportJournal"
+
+	log := h.log.WithField("op", op)
+
+	log.Info("start import journal")
+
+	member, ok := api.Member(c)
+	if !ok {
+		log.Error("error get member")
+		api.ResponseError(c, http.StatusInternalServerError, "error server")
+		return
+	}
+
+	body := http.MaxBytesReader(c.Writer, c.Request.Body, maxJournalSize)
+
+	res, err := h.i.ImportJournal(c.Request.Context(), member, c.Query("format"), body)
+	if err != nil {
+		log.WithField("err", err).Error("error import journal")
+		api.RegistrationError(c, err)
+		return
+	}
+
+	log.Info("success import journal")
+
+	api.ResponseOK(c, res)
+}
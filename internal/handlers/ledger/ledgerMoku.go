@@ -0,0 +1,24 @@
+package ledgerHandlers
+
+import (
+	"context"
+	"io"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/financial_tracer/internal/lib/journal"
+	"github.com/stretchr/testify/mock"
+)
+
+type ledgerServicMock struct {
+	mock.Mock
+}
+
+func (m *ledgerServicMock) ExportJournal(ctx context.Context, member domain.Member, format string) ([]byte, journal.Format, error) {
+	args := m.Called(ctx, member, format)
+	return args.Get(0).([]byte), args.Get(1).(journal.Format), args.Error(2)
+}
+
+func (m *ledgerServicMock) ImportJournal(ctx context.Context, member domain.Member, format string, r io.Reader) (domain.JournalImport, error) {
+	args := m.Called(ctx, member, format, r)
+	return args.Get(0).(domain.JournalImport), args.Error(1)
+}
@@ -0,0 +1,158 @@
+package ledgerHandlers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/financial_tracer/internal/lib/journal"
+	"github.com/financial_tracer/internal/servic/ledger"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/assert/v2"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/mock"
+)
+
+var member = domain.Member{UserID: 1, HouseholdID: 3, Role: domain.HouseholdViewer}
+
+func TestExportJournal(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name         string
+		format       string
+		data         []byte
+		mockErr      error
+		status       int
+		missUserID   bool
+		shouldCallDB bool
+	}{
+		{
+			name:         "success",
+			format:       "beancount",
+			data:         []byte("option \"title\" \"jonn\"\n"),
+			status:       http.StatusOK,
+			shouldCallDB: true,
+		},
+		{
+			name:         "error format",
+			format:       "qif",
+			data:         []byte{},
+			mockErr:      ledger.ErrFormat,
+			status:       http.StatusBadRequest,
+			shouldCallDB: true,
+		},
+		{
+			name:         "error database",
+			format:       "ledger",
+			data:         []byte{},
+			mockErr:      errors.New("error database"),
+			status:       http.StatusInternalServerError,
+			shouldCallDB: true,
+		},
+		{
+			name:         "no user id",
+			missUserID:   true,
+			status:       http.StatusInternalServerError,
+			shouldCallDB: false,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			if !tc.missUserID {
+				c.Set("member", member)
+			}
+
+			svc := new(ledgerServicMock)
+			ctx := context.Background()
+			svc.On("ExportJournal", mock.Anything, member, tc.format).Return(tc.data, journal.Format(tc.format), tc.mockErr)
+
+			h := CreateLedgerHandlers(svc, svc, logrus.New(), ctx)
+
+			req := http.Request{Header: make(http.Header), URL: &url.URL{RawQuery: "format=" + tc.format}}
+			c.Request = req.WithContext(ctx)
+
+			h.ExportJournal(c)
+
+			assert.Equal(t, tc.status, w.Code)
+			if tc.mockErr == nil && tc.shouldCallDB {
+				assert.Equal(t, string(tc.data), w.Body.String())
+				assert.Equal(t, `attachment; filename="financial_tracer.beancount"`, w.Header().Get("Content-Disposition"))
+			}
+			if tc.shouldCallDB {
+				svc.AssertCalled(t, "ExportJournal", mock.Anything, member, tc.format)
+			} else {
+				svc.AssertNotCalled(t, "ExportJournal", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestImportJournal(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name         string
+		format       string
+		result       domain.JournalImport
+		mockErr      error
+		status       int
+		shouldCallDB bool
+	}{
+		{
+			name:         "success",
+			format:       "hledger",
+			result:       domain.JournalImport{Categories: 1, Transactions: 3},
+			status:       http.StatusOK,
+			shouldCallDB: true,
+		},
+		{
+			name:         "error parse",
+			format:       "ledger",
+			mockErr:      ledger.ErrParse,
+			status:       http.StatusBadRequest,
+			shouldCallDB: true,
+		},
+		{
+			name:         "error duplicated",
+			format:       "beancount",
+			mockErr:      ledger.ErrDuplicated,
+			status:       http.StatusBadRequest,
+			shouldCallDB: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Set("member", member)
+
+			svc := new(ledgerServicMock)
+			ctx := context.Background()
+			svc.On("ImportJournal", mock.Anything, member, tc.format, mock.Anything).Return(tc.result, tc.mockErr)
+
+			h := CreateLedgerHandlers(svc, svc, logrus.New(), ctx)
+
+			req := http.Request{Header: make(http.Header), URL: &url.URL{RawQuery: "format=" + tc.format}}
+			req.Body = ioutil.NopCloser(bytes.NewBufferString("2025-01-01 shop\n  Expenses:Food  10\n  Assets:Cash\n"))
+			c.Request = req.WithContext(ctx)
+
+			h.ImportJournal(c)
+
+			assert.Equal(t, tc.status, w.Code)
+			if tc.shouldCallDB {
+				svc.AssertCalled(t, "ImportJournal", mock.Anything, member, tc.format, mock.Anything)
+			}
+		})
+	}
+}
@@ -1,13 +1,19 @@
 package middlewares
 
 import (
-	"fmt"
+	"context"
+	"errors"
 	"net/http"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/financial_tracer/internal/domain"
 	"github.com/financial_tracer/internal/handlers/api"
+	jwttoken "github.com/financial_tracer/internal/lib/jwtToken"
+	"github.com/financial_tracer/internal/servic/accesstoken"
+	"github.com/financial_tracer/internal/servic/household"
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/sirupsen/logrus"
@@ -32,90 +38,318 @@ func Logging(log *logrus.Logger) gin.HandlerFunc {
 	}
 }
 
-func JWToken(secretKey string, log *logrus.Logger) gin.HandlerFunc {
+type SessionChecker interface {
+	SessionActive(ctx context.Context, userID uint, sessionID uint) (bool, error)
+}
+
+type AccessTokenChecker interface {
+	Authenticate(ctx context.Context, token string) (domain.AccessToken, error)
+}
+
+// JWToken authenticates the request by the Bearer token: an access JWT of an active
+// session, or a personal access token, then the scopes of the token are put into the
+// context for Scope. Without the header, in the cookie mode the access JWT is read from
+// the session cookie and state-changing requests need the CSRF token.
+func JWToken(keys *jwttoken.KeySet, sessions SessionChecker, tokens AccessTokenChecker, log *logrus.Logger) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		const authPrefix = "Bearer "
+		var tokenStr string
 		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" || !strings.HasPrefix(authHeader, authPrefix) {
-			log.Error("error Authorization header")
-			c.AbortWithStatusJSON(http.StatusUnauthorized, api.ResponseUnauthorizedError("invalid Authorization header"))
-			return
+		switch {
+		case authHeader != "":
+			if !strings.HasPrefix(authHeader, authPrefix) {
+				log.Error("error Authorization header")
+				c.AbortWithStatusJSON(http.StatusUnauthorized, api.ResponseUnauthorizedError("invalid Authorization header"))
+				return
+			}
+			tokenStr = strings.TrimSpace(strings.TrimPrefix(authHeader, authPrefix))
+		default:
+			if _, ok := api.SessionCookies(c); ok {
+				tokenStr, _ = c.Cookie(api.AccessCookie)
+			}
+			if tokenStr == "" {
+				log.Error("error Authorization header")
+				c.AbortWithStatusJSON(http.StatusUnauthorized, api.ResponseUnauthorizedError("invalid Authorization header"))
+				return
+			}
+			if !api.SafeMethod(c) && !api.CheckCSRF(c) {
+				log.Error("invalid CSRF token")
+				c.AbortWithStatusJSON(http.StatusForbidden, api.ResponseUnauthorizedError("invalid CSRF token"))
+				return
+			}
 		}
 
-		tokenStr := strings.TrimPrefix(authHeader, authPrefix)
-		tokenStr = strings.TrimSpace(tokenStr)
-
-		token, err := jwt.Parse(tokenStr, func(t *jwt.Token) (any, error) {
-			if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
-				log.Error("invalid signing method")
-				return nil, fmt.Errorf("invalid signing method: %s", t.Header["alg"])
+		if strings.HasPrefix(tokenStr, domain.AccessTokenPrefix) {
+			token, err := tokens.Authenticate(c.Request.Context(), tokenStr)
+			if err != nil {
+				if errors.Is(err, accesstoken.ErrToken) {
+					log.WithField("prefix", accesstoken.Prefix(tokenStr)).Error("invalid access token")
+					c.AbortWithStatusJSON(http.StatusUnauthorized, api.ResponseUnauthorizedError("invalid token"))
+					return
+				}
+				log.WithField("err", err).Error("error check access token")
+				c.AbortWithStatusJSON(http.StatusInternalServerError, api.ResponseUnauthorizedError("error check access token"))
+				return
 			}
 
-			return []byte(secretKey), nil
-		})
+			c.Set("userID", token.UserID)
+			c.Set("tokenID", token.ID)
+			c.Set("scopes", token.Scopes)
+			c.Next()
+			return
+		}
 
-		if err != nil || !token.Valid {
-			log.Error("invalid token")
+		claims, err := keys.Parse(tokenStr, jwttoken.TypeAccess)
+		if err != nil {
+			if errors.Is(err, jwt.ErrTokenExpired) {
+				log.Error("invalid term token exp")
+				c.AbortWithStatusJSON(http.StatusBadRequest, api.ResponseUnauthorizedError("invalid term token exp"))
+				return
+			}
+			log.WithField("err", err).Error("invalid token")
 			c.AbortWithStatusJSON(http.StatusUnauthorized, api.ResponseUnauthorizedError("invalid token"))
 			return
 		}
 
-		claims, ok := token.Claims.(jwt.MapClaims)
-		if !ok {
-			log.Error("error convert token in claims")
-			c.AbortWithStatusJSON(http.StatusUnauthorized, api.ResponseUnauthorizedError("invalid token claims"))
+		if claims.Id == 0 {
+			log.Error("error get userID")
+			c.AbortWithStatusJSON(http.StatusUnauthorized, api.ResponseUnauthorizedError("invalid get userID"))
 			return
 		}
 
-		if exp, ok := claims["exp"]; ok {
-			if expTime, ok := exp.(float64); ok {
-				if time.Now().After(time.Unix(int64(expTime), 0)) {
-					log.Error("invalid term token exp")
-					c.AbortWithStatusJSON(http.StatusBadRequest, api.ResponseUnauthorizedError("invalid term token exp"))
+		if claims.SessionID == 0 {
+			log.Error("error get sessionID")
+			c.AbortWithStatusJSON(http.StatusUnauthorized, api.ResponseUnauthorizedError("invalid get sessionID"))
+			return
+		}
 
-					return
-				}
-			} else {
-				log.Error("error exp format")
-				c.AbortWithStatusJSON(http.StatusUnauthorized, api.ResponseUnauthorizedError("invalid exp format"))
-			}
-		} else {
-			log.Error("error get exp")
-			c.AbortWithStatusJSON(http.StatusUnauthorized, api.ResponseUnauthorizedError("invalid get exp"))
+		active, err := sessions.SessionActive(c.Request.Context(), claims.Id, claims.SessionID)
+		if err != nil {
+			log.WithField("err", err).Error("error check session")
+			c.AbortWithStatusJSON(http.StatusInternalServerError, api.ResponseUnauthorizedError("error check session"))
 			return
 		}
-		idClaim, ok := claims["id"]
-		if !ok {
-			log.Error("error get userID")
-			c.AbortWithStatusJSON(http.StatusBadRequest, api.ResponseUnauthorizedError("invalid get userID"))
+		if !active {
+			log.WithField("session_id", claims.SessionID).Error("session revoked")
+			c.AbortWithStatusJSON(http.StatusUnauthorized, api.ResponseUnauthorizedError("session revoked"))
 			return
 		}
-		switch v := idClaim.(type) {
-		case float64:
-			userId := uint(v)
-			c.Set("userID", userId)
+
+		c.Set("userID", claims.Id)
+		c.Set("sessionID", claims.SessionID)
+		c.Next()
+	}
+}
+
+// Scope lets a personal access token through only with a scope of the resource:
+// resource:read for GET requests, resource:write (that also allows reading) for the
+// others. It runs after JWToken; requests with a session JWT have every scope, with
+// an empty resource access tokens are rejected, for the endpoints managing the account.
+func Scope(resource string, log *logrus.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		value, ok := c.Get("scopes")
+		if !ok {
 			c.Next()
-		case string:
-			id, err := strconv.ParseUint(v, 10, 32)
-			if err != nil {
-				log.Error("error convert userID (string in uint)")
-				c.AbortWithStatusJSON(http.StatusUnauthorized, api.ResponseUnauthorizedError("invalid userID format"))
+			return
+		}
+
+		if resource == "" {
+			log.WithField("token_id", c.GetUint("tokenID")).Error("access token used for the account")
+			c.AbortWithStatusJSON(http.StatusForbidden, api.ResponseUnauthorizedError("access tokens can't manage the account"))
+			return
+		}
+
+		write := resource + ":write"
+		need := write
+		if c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead {
+			need = resource + ":read"
+		}
+
+		scopes, _ := value.([]string)
+		if !slices.Contains(scopes, need) && !slices.Contains(scopes, write) {
+			log.WithFields(logrus.Fields{
+				"token_id": c.GetUint("tokenID"),
+				"scope":    need,
+			}).Error("access token lacks scope")
+			c.AbortWithStatusJSON(http.StatusForbidden, api.ResponseUnauthorizedError("access token lacks scope "+need))
+			return
+		}
+
+		c.Next()
+	}
+}
+
+type RoleChecker interface {
+	UserRole(ctx context.Context, userID uint) (string, error)
+}
+
+// Role lets through only users with the role. It runs after JWToken, the role is read on
+// every request so a demoted admin loses access at once.
+func Role(checker RoleChecker, role string, log *logrus.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.GetUint("userID")
+
+		value, err := checker.UserRole(c.Request.Context(), userID)
+		if err != nil {
+			log.WithField("err", err).Error("error check role")
+			c.AbortWithStatusJSON(http.StatusInternalServerError, api.ResponseUnauthorizedError("error check role"))
+			return
+		}
+		if value != role {
+			log.WithFields(logrus.Fields{
+				"user_id": userID,
+				"role":    value,
+			}).Error("role required: ", role)
+			c.AbortWithStatusJSON(http.StatusForbidden, api.ResponseUnauthorizedError("role "+role+" required"))
+			return
+		}
+
+		c.Next()
+	}
+}
+
+const (
+	AccessFull    = "full"
+	AccessLimited = "limited"
+)
+
+// HouseholdHeader selects the active household of the request, without it the personal
+// household of the user is used.
+const HouseholdHeader = "X-Household-ID"
+
+type HouseholdResolver interface {
+	Member(ctx context.Context, userID uint, householdID uint) (domain.Member, error)
+}
+
+// Household sets the member of the active household the request works in. It runs after
+// JWToken, repositories check the role again on every query.
+func Household(resolver HouseholdResolver, log *logrus.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.GetUint("userID")
+
+		var householdID uint64
+		if header := c.GetHeader(HouseholdHeader); header != "" {
+			id, err := strconv.ParseUint(header, 10, 64)
+			if err != nil || id == 0 {
+				log.WithField("header", header).Error("invalid household id")
+				c.AbortWithStatusJSON(http.StatusBadRequest, api.ResponseUnauthorizedError("invalid "+HouseholdHeader))
+				return
+			}
+			householdID = id
+		}
+
+		m, err := resolver.Member(c.Request.Context(), userID, uint(householdID))
+		if err != nil {
+			if errors.Is(err, household.ErrForbidden) {
+				log.WithFields(logrus.Fields{
+					"user_id":      userID,
+					"household_id": householdID,
+				}).Error("not a member of the household")
+				c.AbortWithStatusJSON(http.StatusForbidden, api.ResponseUnauthorizedError("not a member of the household"))
+				return
 			}
-			userId := uint(id)
-			c.Set("userID", userId)
+			log.WithField("err", err).Error("error resolve household")
+			c.AbortWithStatusJSON(http.StatusInternalServerError, api.ResponseUnauthorizedError("error resolve household"))
+			return
+		}
+
+		c.Set("member", m)
+		c.Next()
+	}
+}
+
+type VerificationChecker interface {
+	UserVerified(ctx context.Context, userID uint) (bool, error)
+}
+
+// Verified rejects requests of users with an unverified email. It runs after JWToken; with
+// access AccessFull every user passes, any other value limits unverified users.
+func Verified(checker VerificationChecker, access string, log *logrus.Logger) gin.HandlerFunc {
+	if access == AccessFull {
+		return func(c *gin.Context) {
 			c.Next()
-		default:
-			log.Error("error convert userID")
-			c.AbortWithStatusJSON(http.StatusUnauthorized, api.ResponseUnauthorizedError("the userID is not meet the requirements any format"))
 		}
 	}
+
+	return func(c *gin.Context) {
+		userID := c.GetUint("userID")
+
+		verified, err := checker.UserVerified(c.Request.Context(), userID)
+		if err != nil {
+			log.WithField("err", err).Error("error check verification")
+			c.AbortWithStatusJSON(http.StatusInternalServerError, api.ResponseUnauthorizedError("error check verification"))
+			return
+		}
+		if !verified {
+			log.WithField("user_id", userID).Error("email is not verified")
+			c.AbortWithStatusJSON(http.StatusForbidden, api.ResponseUnauthorizedError("email is not verified"))
+			return
+		}
+
+		c.Next()
+	}
+}
+
+type PreferencesLoader interface {
+	UserPreferences(ctx context.Context, userID uint) (domain.Preferences, error)
 }
 
-func CORSMiddleware() gin.HandlerFunc {
+// Preferences puts the preferences of the user into the context for api.Preferences. It
+// runs after JWToken; when they can't be loaded the request goes on with the defaults.
+func Preferences(loader PreferencesLoader, log *logrus.Logger) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE")
+		userID := c.GetUint("userID")
+
+		pref, err := loader.UserPreferences(c.Request.Context(), userID)
+		if err != nil {
+			log.WithFields(logrus.Fields{
+				"user_id": userID,
+				"err":     err,
+			}).Warn("error load preferences, using defaults")
+			c.Next()
+			return
+		}
+
+		c.Set("preferences", pref)
+		c.Next()
+	}
+}
+
+// Cookies turns the cookie session mode on, the token handlers and JWToken read the
+// cookie settings from the context. When it is disabled the requests pass as they are.
+func Cookies(enabled bool, cookies api.Cookies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if enabled {
+			c.Set("cookies", cookies)
+		}
+		c.Next()
+	}
+}
+
+// CORS configures CORSMiddleware. Without origins and credentials any origin is allowed;
+// with credentials browsers send the session cookies, so only the listed origins are
+// answered (and "*" is never used).
+type CORS struct {
+	Origins     []string
+	Credentials bool
+}
+
+func CORSMiddleware(cfg CORS) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.Writer.Header()
+		if len(cfg.Origins) == 0 && !cfg.Credentials {
+			header.Set("Access-Control-Allow-Origin", "*")
+		} else {
+			header.Add("Vary", "Origin")
+			if origin := c.GetHeader("Origin"); origin != "" && slices.Contains(cfg.Origins, origin) {
+				header.Set("Access-Control-Allow-Origin", origin)
+				if cfg.Credentials {
+					header.Set("Access-Control-Allow-Credentials", "true")
+				}
+			}
+		}
+		header.Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, X-Household-ID, X-Session-Mode, Authorization, accept, origin, Cache-Control, X-Requested-With")
+		header.Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE")
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
 			return
@@ -0,0 +1,197 @@
+package passwordHandlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/financial_tracer/internal/handlers/api"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type ChangePasswordServic interface {
+	ChangePassword(ctx context.Context, userID uint, sessionID uint, req domain.ChangePassword) error
+}
+
+type ForgotPasswordServic interface {
+	ForgotPassword(ctx context.Context, req domain.ForgotPassword) error
+}
+
+type ResetPasswordServic interface {
+	ResetPassword(ctx context.Context, req domain.ResetPassword) error
+}
+
+type PasswordHandlers struct {
+	c   ChangePasswordServic
+	f   ForgotPasswordServic
+	r   ResetPasswordServic
+	log *logrus.Logger
+	ctx context.Context
+}
+
+func CreatePasswordHandlers(c ChangePasswordServic,
+	f ForgotPasswordServic,
+	r ResetPasswordServic,
+	log *logrus.Logger,
+	ctx context.Context) *PasswordHandlers {
+	return &PasswordHandlers{
+		c:   c,
+		f:   f,
+		r:   r,
+		log: log,
+		ctx: ctx,
+	}
+}
+
+// ChangePassword godoc
+//
+//	@Summary		Смена пароля
+//	@Description	Смена пароля по текущему паролю. Все сессии пользователя, кроме текущей, завершаются, токены доступа отзываются
+//
+//	@Tags			User
+//
+//	@Accept			json
+//	@Produce		json
+//	@Param			req	body		RequestChangePassword	true	"текущий и новый пароль"
+//	@Success		200	{object}	api.SuccessResponse		"Пароль изменен"
+//
+//	@Failure		400	{object}	api.ErrorResponse		"Некорректные входные данные"
+//	@Failure		401	{object}	api.ErrorResponse		"Неверный текущий пароль"
+//	@Failure		404	{object}	api.ErrorResponse		"Пользователь не найден"
+//	@Failure		500	{object}	api.ErrorResponse		"Ошибка сервера"
+//
+//	@Router			/user/password [put]
+//
+//	@Security		jwtAuth
+func (h *PasswordHandlers) ChangePassword(c *gin.Context) {
+	const op = "handlers.ChangePassword"
+
+	log := h.log.WithField("op", op)
+
+	log.Info("start change password")
+
+	var req RequestChangePassword
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.WithField("err", err).Error("error valid JSON")
+		api.ResponseError(c, http.StatusBadRequest, "error valid JSON")
+		return
+	}
+
+	idUser, ok := c.Get("userID")
+	if !ok {
+		log.Error("error get userID")
+		api.ResponseError(c, http.StatusInternalServerError, "error server")
+		return
+	}
+
+	idSession, ok := c.Get("sessionID")
+	if !ok {
+		log.Error("error get sessionID")
+		api.ResponseError(c, http.StatusUnauthorized, "token without session")
+		return
+	}
+
+	err := h.c.ChangePassword(c.Request.Context(), idUser.(uint), idSession.(uint), domain.ChangePassword{
+		OldPassword: req.OldPassword,
+		NewPassword: req.NewPassword,
+		Device:      api.Device(c),
+	})
+	if err != nil {
+		log.WithField("err", err).Error("error change password")
+		api.RegistrationError(c, err)
+		return
+	}
+
+	log.Info("success change password")
+
+	api.ResponseOK(c, "password changed")
+}
+
+// ForgotPassword godoc
+//
+//	@Summary		Восстановление пароля
+//	@Description	Отправка письма со ссылкой для сброса пароля. Ответ не зависит от того, зарегистрирован ли email
+//
+//	@Tags			registration
+//
+//	@Accept			json
+//	@Produce		json
+//	@Param			req	body		RequestForgotPassword	true	"email пользователя"
+//	@Success		200	{object}	api.SuccessResponse		"Письмо отправлено"
+//
+//	@Failure		400	{object}	api.ErrorResponse		"Некорректные входные данные"
+//	@Failure		500	{object}	api.ErrorResponse		"Ошибка сервера"
+//
+//	@Router			/registration/password/forgot [post]
+func (h *PasswordHandlers) ForgotPassword(c *gin.Context) {
+	const op = "handlers.ForgotPassword"
+
+	log := h.log.WithField("op", op)
+
+	log.Info("start forgot password")
+
+	var req RequestForgotPassword
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.WithField("err", err).Error("error valid JSON")
+		api.ResponseError(c, http.StatusBadRequest, "error valid JSON")
+		return
+	}
+
+	err := h.f.ForgotPassword(c.Request.Context(), domain.ForgotPassword{Email: req.Email})
+	if err != nil {
+		log.WithField("err", err).Error("error forgot password")
+		api.RegistrationError(c, err)
+		return
+	}
+
+	log.Info("success forgot password")
+
+	api.ResponseOK(c, "if the email is registered, a reset link has been sent")
+}
+
+// ResetPassword godoc
+//
+//	@Summary		Сброс пароля
+//	@Description	Установка нового пароля по токену из письма. Токен одноразовый и ограничен по времени, после сброса все сессии пользователя завершаются, токены доступа отзываются
+//
+//	@Tags			registration
+//
+//	@Accept			json
+//	@Produce		json
+//	@Param			req	body		RequestResetPassword	true	"токен и новый пароль"
+//	@Success		200	{object}	api.SuccessResponse		"Пароль изменен"
+//
+//	@Failure		400	{object}	api.ErrorResponse		"Некорректный или просроченный токен"
+//	@Failure		500	{object}	api.ErrorResponse		"Ошибка сервера"
+//
+//	@Router			/registration/password/reset [post]
+func (h *PasswordHandlers) ResetPassword(c *gin.Context) {
+	const op = "handlers.ResetPassword"
+
+	log := h.log.WithField("op", op)
+
+	log.Info("start reset password")
+
+	var req RequestResetPassword
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.WithField("err", err).Error("error valid JSON")
+		api.ResponseError(c, http.StatusBadRequest, "error valid JSON")
+		return
+	}
+
+	err := h.r.ResetPassword(c.Request.Context(), domain.ResetPassword{
+		Token:       req.Token,
+		NewPassword: req.NewPassword,
+		Device:      api.Device(c),
+	})
+	if err != nil {
+		log.WithField("err", err).Error("error reset password")
+		api.RegistrationError(c, err)
+		return
+	}
+
+	log.Info("success reset password")
+
+	api.ResponseOK(c, "password changed")
+}
@@ -0,0 +1,27 @@
+package passwordHandlers
+
+import (
+	"context"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type passwordServicMock struct {
+	mock.Mock
+}
+
+func (m *passwordServicMock) ChangePassword(ctx context.Context, userID uint, sessionID uint, req domain.ChangePassword) error {
+	args := m.Called(ctx, userID, sessionID, req)
+	return args.Error(0)
+}
+
+func (m *passwordServicMock) ForgotPassword(ctx context.Context, req domain.ForgotPassword) error {
+	args := m.Called(ctx, req)
+	return args.Error(0)
+}
+
+func (m *passwordServicMock) ResetPassword(ctx context.Context, req domain.ResetPassword) error {
+	args := m.Called(ctx, req)
+	return args.Error(0)
+}
@@ -0,0 +1,221 @@
+package passwordHandlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/financial_tracer/internal/servic/password"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/assert/v2"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestChangePassword(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name         string
+		body         any
+		req          domain.ChangePassword
+		invalidJSON  bool
+		mockErr      error
+		status       int
+		shouldCallDB bool
+	}{
+		{
+			name:         "success",
+			body:         RequestChangePassword{OldPassword: "secret", NewPassword: "secret2"},
+			req:          domain.ChangePassword{OldPassword: "secret", NewPassword: "secret2"},
+			status:       http.StatusOK,
+			shouldCallDB: true,
+		},
+		{
+			name:         "wrong password",
+			body:         RequestChangePassword{OldPassword: "secret", NewPassword: "secret2"},
+			req:          domain.ChangePassword{OldPassword: "secret", NewPassword: "secret2"},
+			mockErr:      password.ErrPassword,
+			status:       http.StatusUnauthorized,
+			shouldCallDB: true,
+		},
+		{
+			name:         "missing new password",
+			body:         RequestChangePassword{OldPassword: "secret"},
+			status:       http.StatusBadRequest,
+			shouldCallDB: false,
+		},
+		{
+			name:         "invalid json",
+			invalidJSON:  true,
+			status:       http.StatusBadRequest,
+			shouldCallDB: false,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Set("userID", uint(1))
+			c.Set("sessionID", uint(4))
+
+			svc := new(passwordServicMock)
+			ctx := context.Background()
+			svc.On("ChangePassword", mock.Anything, uint(1), uint(4), tc.req).Return(tc.mockErr)
+
+			h := CreatePasswordHandlers(svc, svc, svc, logrus.New(), ctx)
+
+			req := http.Request{Header: make(http.Header), URL: &url.URL{}}
+			if tc.invalidJSON {
+				req.Body = io.NopCloser(bytes.NewBufferString("{"))
+			} else {
+				b, _ := json.Marshal(tc.body)
+				req.Body = io.NopCloser(bytes.NewBuffer(b))
+			}
+			req.Header.Set("content-type", "application/json")
+			c.Request = req.WithContext(ctx)
+
+			h.ChangePassword(c)
+
+			assert.Equal(t, tc.status, w.Code)
+			if tc.shouldCallDB {
+				svc.AssertCalled(t, "ChangePassword", mock.Anything, uint(1), uint(4), tc.req)
+			} else {
+				svc.AssertNotCalled(t, "ChangePassword", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestForgotPassword(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name         string
+		body         any
+		req          domain.ForgotPassword
+		mockErr      error
+		status       int
+		shouldCallDB bool
+	}{
+		{
+			name:         "success",
+			body:         RequestForgotPassword{Email: "jonn@gmail.com"},
+			req:          domain.ForgotPassword{Email: "jonn@gmail.com"},
+			status:       http.StatusOK,
+			shouldCallDB: true,
+		},
+		{
+			name:         "missing email",
+			body:         RequestForgotPassword{},
+			status:       http.StatusBadRequest,
+			shouldCallDB: false,
+		},
+		{
+			name:         "error database",
+			body:         RequestForgotPassword{Email: "jonn@gmail.com"},
+			req:          domain.ForgotPassword{Email: "jonn@gmail.com"},
+			mockErr:      password.ErrDatabase,
+			status:       http.StatusInternalServerError,
+			shouldCallDB: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+
+			svc := new(passwordServicMock)
+			ctx := context.Background()
+			svc.On("ForgotPassword", mock.Anything, tc.req).Return(tc.mockErr)
+
+			h := CreatePasswordHandlers(svc, svc, svc, logrus.New(), ctx)
+
+			req := http.Request{Header: make(http.Header), URL: &url.URL{}}
+			b, _ := json.Marshal(tc.body)
+			req.Body = io.NopCloser(bytes.NewBuffer(b))
+			req.Header.Set("content-type", "application/json")
+			c.Request = req.WithContext(ctx)
+
+			h.ForgotPassword(c)
+
+			assert.Equal(t, tc.status, w.Code)
+			if tc.shouldCallDB {
+				svc.AssertCalled(t, "ForgotPassword", mock.Anything, tc.req)
+			} else {
+				svc.AssertNotCalled(t, "ForgotPassword", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestResetPassword(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name         string
+		body         any
+		req          domain.ResetPassword
+		mockErr      error
+		status       int
+		shouldCallDB bool
+	}{
+		{
+			name:         "success",
+			body:         RequestResetPassword{Token: "token", NewPassword: "secret2"},
+			req:          domain.ResetPassword{Token: "token", NewPassword: "secret2"},
+			status:       http.StatusOK,
+			shouldCallDB: true,
+		},
+		{
+			name:         "expired token",
+			body:         RequestResetPassword{Token: "token", NewPassword: "secret2"},
+			req:          domain.ResetPassword{Token: "token", NewPassword: "secret2"},
+			mockErr:      password.ErrResetToken,
+			status:       http.StatusBadRequest,
+			shouldCallDB: true,
+		},
+		{
+			name:         "missing token",
+			body:         RequestResetPassword{NewPassword: "secret2"},
+			status:       http.StatusBadRequest,
+			shouldCallDB: false,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+
+			svc := new(passwordServicMock)
+			ctx := context.Background()
+			svc.On("ResetPassword", mock.Anything, tc.req).Return(tc.mockErr)
+
+			h := CreatePasswordHandlers(svc, svc, svc, logrus.New(), ctx)
+
+			req := http.Request{Header: make(http.Header), URL: &url.URL{}}
+			b, _ := json.Marshal(tc.body)
+			req.Body = io.NopCloser(bytes.NewBuffer(b))
+			req.Header.Set("content-type", "application/json")
+			c.Request = req.WithContext(ctx)
+
+			h.ResetPassword(c)
+
+			assert.Equal(t, tc.status, w.Code)
+			if tc.shouldCallDB {
+				svc.AssertCalled(t, "ResetPassword", mock.Anything, tc.req)
+			} else {
+				svc.AssertNotCalled(t, "ResetPassword", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
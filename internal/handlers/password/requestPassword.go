@@ -0,0 +1,18 @@
+package passwordHandlers
+
+// RequestChangePassword represents change password request
+type RequestChangePassword struct {
+	OldPassword string `json:"old_password" binding:"required" example:"securitycod123"`
+	NewPassword string `json:"new_password" binding:"required" example:"securitycod456"`
+}
+
+// RequestForgotPassword represents forgot password request
+type RequestForgotPassword struct {
+	Email string `json:"email" binding:"required" example:"jonn@gmail.com"`
+}
+
+// RequestResetPassword represents reset password request
+type RequestResetPassword struct {
+	Token       string `json:"token" binding:"required" example:"Zx9kQ2LmVbT0yA4sWc1NrE7uHj3PfG6dKo8iB5qXtYw"`
+	NewPassword string `json:"new_password" binding:"required" example:"securitycod456"`
+}
@@ -0,0 +1,239 @@
+package profileHandlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/financial_tracer/internal/handlers/api"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type ProfileServic interface {
+	Profile(ctx context.Context, userID uint) (domain.Profile, error)
+	UpdateProfile(ctx context.Context, userID uint, req domain.UpdateProfile) (domain.Profile, error)
+}
+
+type PreferencesServic interface {
+	Preferences(ctx context.Context, userID uint) (domain.Preferences, error)
+	UpdatePreferences(ctx context.Context, userID uint, pref domain.Preferences) (domain.Preferences, error)
+}
+
+type ProfileHandlers struct {
+	p   ProfileServic
+	pr  PreferencesServic
+	log *logrus.Logger
+	ctx context.Context
+}
+
+func CreateProfileHandlers(p ProfileServic, pr PreferencesServic, log *logrus.Logger, ctx context.Context) *ProfileHandlers {
+	return &ProfileHandlers{
+		p:   p,
+		pr:  pr,
+		log: log,
+		ctx: ctx,
+	}
+}
+
+// GetProfile godoc
+//
+//	@Summary		Профиль пользователя
+//	@Description	Имя, email, подтвержден ли email, включена ли 2FA и настройки пользователя
+//
+//	@Tags			User
+//
+//	@Produce		json
+//	@Success		200	{object}	api.SuccessResponse	"Профиль"
+//
+//	@Failure		401	{object}	api.ErrorResponse	"Ошибка авторизации"
+//	@Failure		404	{object}	api.ErrorResponse	"Пользователь не найден"
+//	@Failure		500	{object}	api.ErrorResponse	"Ошибка сервера"
+//
+//	@Router			/user/ [get]
+//
+//	@Security		jwtAuth
+func (h *ProfileHandlers) GetProfile(c *gin.Context) {
+	const op = "handlers.GetProfile"
+
+	log := h.log.WithField("op", op)
+
+	log.Info("start get profile")
+
+	idUser, ok := c.Get("userID")
+	if !ok {
+		log.Error("error get userID")
+		api.ResponseError(c, http.StatusInternalServerError, "error server")
+		return
+	}
+
+	profile, err := h.p.Profile(c.Request.Context(), idUser.(uint))
+	if err != nil {
+		log.WithField("err", err).Error("error get profile")
+		api.RegistrationError(c, err)
+		return
+	}
+
+	log.Info("success get profile")
+
+	api.ResponseOK(c, profile)
+}
+
+// UpdateProfile godoc
+//
+//	@Summary		Изменение профиля
+//	@Description	Изменение имени и/или email. Для смены email нужен текущий пароль, новый email нужно подтвердить заново
+//
+//	@Tags			User
+//
+//	@Accept			json
+//	@Produce		json
+//	@Param			req	body		RequestUpdateProfile	true	"новые имя и/или email"
+//	@Success		200	{object}	api.SuccessResponse		"Профиль"
+//
+//	@Failure		400	{object}	api.ErrorResponse		"Некорректные входные данные или email занят"
+//	@Failure		401	{object}	api.ErrorResponse		"Неверный пароль"
+//	@Failure		404	{object}	api.ErrorResponse		"Пользователь не найден"
+//	@Failure		500	{object}	api.ErrorResponse		"Ошибка сервера"
+//
+//	@Router			/user/ [put]
+//
+//	@Security		jwtAuth
+func (h *ProfileHandlers) UpdateProfile(c *gin.Context) {
+	const op = "handlers.UpdateProfile"
+
+	log := h.log.WithField("op", op)
+
+	log.Info("start update profile")
+
+	var req RequestUpdateProfile
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.WithField("err", err).Error("error valid JSON")
+		api.ResponseError(c, http.StatusBadRequest, "error valid JSON")
+		return
+	}
+
+	idUser, ok := c.Get("userID")
+	if !ok {
+		log.Error("error get userID")
+		api.ResponseError(c, http.StatusInternalServerError, "error server")
+		return
+	}
+
+	profile, err := h.p.UpdateProfile(c.Request.Context(), idUser.(uint), domain.UpdateProfile{
+		Name:     req.Name,
+		Email:    req.Email,
+		Password: req.Password,
+	})
+	if err != nil {
+		log.WithField("err", err).Error("error update profile")
+		api.RegistrationError(c, err)
+		return
+	}
+
+	log.Info("success update profile")
+
+	api.ResponseOK(c, profile)
+}
+
+// GetPreferences godoc
+//
+//	@Summary		Настройки пользователя
+//	@Description	Валюта по умолчанию, часовой пояс, язык, первый день недели и день начала отчетного месяца
+//
+//	@Tags			User
+//
+//	@Produce		json
+//	@Success		200	{object}	api.SuccessResponse	"Настройки"
+//
+//	@Failure		401	{object}	api.ErrorResponse	"Ошибка авторизации"
+//	@Failure		404	{object}	api.ErrorResponse	"Пользователь не найден"
+//	@Failure		500	{object}	api.ErrorResponse	"Ошибка сервера"
+//
+//	@Router			/user/preferences [get]
+//
+//	@Security		jwtAuth
+func (h *ProfileHandlers) GetPreferences(c *gin.Context) {
+	const op = "handlers.GetPreferences"
+
+	log := h.log.WithField("op", op)
+
+	log.Info("start get preferences")
+
+	idUser, ok := c.Get("userID")
+	if !ok {
+		log.Error("error get userID")
+		api.ResponseError(c, http.StatusInternalServerError, "error server")
+		return
+	}
+
+	pref, err := h.pr.Preferences(c.Request.Context(), idUser.(uint))
+	if err != nil {
+		log.WithField("err", err).Error("error get preferences")
+		api.RegistrationError(c, err)
+		return
+	}
+
+	log.Info("success get preferences")
+
+	api.ResponseOK(c, pref)
+}
+
+// UpdatePreferences godoc
+//
+//	@Summary		Изменение настроек
+//	@Description	Валюта (ISO 4217), часовой пояс (IANA), язык (en, ru), первый день недели (0 - воскресенье, 1 - понедельник) и день начала отчетного месяца (1-28). Отчеты и сообщения об ошибках учитывают настройки
+//
+//	@Tags			User
+//
+//	@Accept			json
+//	@Produce		json
+//	@Param			req	body		RequestPreferences	true	"настройки"
+//	@Success		200	{object}	api.SuccessResponse	"Настройки"
+//
+//	@Failure		400	{object}	api.ErrorResponse	"Некорректные входные данные"
+//	@Failure		401	{object}	api.ErrorResponse	"Ошибка авторизации"
+//	@Failure		404	{object}	api.ErrorResponse	"Пользователь не найден"
+//	@Failure		500	{object}	api.ErrorResponse	"Ошибка сервера"
+//
+//	@Router			/user/preferences [put]
+//
+//	@Security		jwtAuth
+func (h *ProfileHandlers) UpdatePreferences(c *gin.Context) {
+	const op = "handlers.UpdatePreferences"
+
+	log := h.log.WithField("op", op)
+
+	log.Info("start update preferences")
+
+	var req RequestPreferences
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.WithField("err", err).Error("error valid JSON")
+		api.ResponseError(c, http.StatusBadRequest, "error valid JSON")
+		return
+	}
+
+	idUser, ok := c.Get("userID")
+	if !ok {
+		log.Error("error get userID")
+		api.ResponseError(c, http.StatusInternalServerError, "error server")
+		return
+	}
+
+	pref, err := h.pr.UpdatePreferences(c.Request.Context(), idUser.(uint), domain.Preferences{
+		Currency:   req.Currency,
+		Timezone:   req.Timezone,
+		Locale:     req.Locale,
+		WeekStart:  req.WeekStart,
+		MonthStart: req.MonthStart,
+	})
+	if err != nil {
+		log.WithField("err", err).Error("error update preferences")
+		api.RegistrationError(c, err)
+		return
+	}
+
+	log.Info("success update preferences")
+
+	api.ResponseOK(c, pref)
+}
@@ -0,0 +1,32 @@
+package profileHandlers
+
+import (
+	"context"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type profileServicMock struct {
+	mock.Mock
+}
+
+func (m *profileServicMock) Profile(ctx context.Context, userID uint) (domain.Profile, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(domain.Profile), args.Error(1)
+}
+
+func (m *profileServicMock) UpdateProfile(ctx context.Context, userID uint, req domain.UpdateProfile) (domain.Profile, error) {
+	args := m.Called(ctx, userID, req)
+	return args.Get(0).(domain.Profile), args.Error(1)
+}
+
+func (m *profileServicMock) Preferences(ctx context.Context, userID uint) (domain.Preferences, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(domain.Preferences), args.Error(1)
+}
+
+func (m *profileServicMock) UpdatePreferences(ctx context.Context, userID uint, pref domain.Preferences) (domain.Preferences, error) {
+	args := m.Called(ctx, userID, pref)
+	return args.Get(0).(domain.Preferences), args.Error(1)
+}
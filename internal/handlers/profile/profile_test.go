@@ -0,0 +1,235 @@
+package profileHandlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/financial_tracer/internal/servic/profile"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/assert/v2"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetProfile(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name    string
+		mockErr error
+		status  int
+	}{
+		{name: "success", status: http.StatusOK},
+		{name: "not found", mockErr: profile.ErrNoFound, status: http.StatusNotFound},
+		{name: "error database", mockErr: profile.ErrDatabase, status: http.StatusInternalServerError},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Set("userID", uint(1))
+
+			svc := new(profileServicMock)
+			ctx := context.Background()
+			svc.On("Profile", mock.Anything, uint(1)).Return(domain.Profile{ID: 1, Name: "jonn"}, tc.mockErr)
+
+			h := CreateProfileHandlers(svc, svc, logrus.New(), ctx)
+
+			req := http.Request{Header: make(http.Header), URL: &url.URL{}}
+			c.Request = req.WithContext(ctx)
+
+			h.GetProfile(c)
+
+			assert.Equal(t, tc.status, w.Code)
+		})
+	}
+}
+
+func TestUpdateProfile(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name         string
+		body         any
+		req          domain.UpdateProfile
+		invalidJSON  bool
+		mockErr      error
+		status       int
+		shouldCallDB bool
+	}{
+		{
+			name:         "success",
+			body:         RequestUpdateProfile{Email: "jonny@gmail.com", Password: "secret"},
+			req:          domain.UpdateProfile{Email: "jonny@gmail.com", Password: "secret"},
+			status:       http.StatusOK,
+			shouldCallDB: true,
+		},
+		{
+			name:         "wrong password",
+			body:         RequestUpdateProfile{Email: "jonny@gmail.com", Password: "wrong"},
+			req:          domain.UpdateProfile{Email: "jonny@gmail.com", Password: "wrong"},
+			mockErr:      profile.ErrPassword,
+			status:       http.StatusUnauthorized,
+			shouldCallDB: true,
+		},
+		{
+			name:         "email taken",
+			body:         RequestUpdateProfile{Email: "sasha@gmail.com", Password: "secret"},
+			req:          domain.UpdateProfile{Email: "sasha@gmail.com", Password: "secret"},
+			mockErr:      profile.ErrDuplicated,
+			status:       http.StatusBadRequest,
+			shouldCallDB: true,
+		},
+		{
+			name:         "invalid json",
+			invalidJSON:  true,
+			status:       http.StatusBadRequest,
+			shouldCallDB: false,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Set("userID", uint(1))
+
+			svc := new(profileServicMock)
+			ctx := context.Background()
+			svc.On("UpdateProfile", mock.Anything, uint(1), tc.req).Return(domain.Profile{}, tc.mockErr)
+
+			h := CreateProfileHandlers(svc, svc, logrus.New(), ctx)
+
+			req := http.Request{Header: make(http.Header), URL: &url.URL{}}
+			if tc.invalidJSON {
+				req.Body = io.NopCloser(bytes.NewBufferString("{"))
+			} else {
+				b, _ := json.Marshal(tc.body)
+				req.Body = io.NopCloser(bytes.NewBuffer(b))
+			}
+			req.Header.Set("content-type", "application/json")
+			c.Request = req.WithContext(ctx)
+
+			h.UpdateProfile(c)
+
+			assert.Equal(t, tc.status, w.Code)
+			if tc.shouldCallDB {
+				svc.AssertCalled(t, "UpdateProfile", mock.Anything, uint(1), tc.req)
+			} else {
+				svc.AssertNotCalled(t, "UpdateProfile", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestUpdatePreferences(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	pref := domain.Preferences{Currency: "EUR", Timezone: "Europe/Berlin", Locale: "ru", WeekStart: 0, MonthStart: 25}
+	validErr := validator.New().Struct(domain.Preferences{Currency: "EUR", Timezone: "Europe/Berlin", Locale: "de", MonthStart: 25})
+
+	tests := []struct {
+		name         string
+		body         any
+		invalidJSON  bool
+		header       string
+		preferences  *domain.Preferences
+		mockErr      error
+		status       int
+		message      string
+		shouldCallDB bool
+	}{
+		{
+			name:         "success",
+			body:         RequestPreferences{Currency: "EUR", Timezone: "Europe/Berlin", Locale: "ru", WeekStart: 0, MonthStart: 25},
+			status:       http.StatusOK,
+			shouldCallDB: true,
+		},
+		{
+			name:         "validation message in english",
+			body:         RequestPreferences{Currency: "EUR", Timezone: "Europe/Berlin", Locale: "ru", WeekStart: 0, MonthStart: 25},
+			mockErr:      validErr,
+			status:       http.StatusBadRequest,
+			message:      "Field validation for Locale",
+			shouldCallDB: true,
+		},
+		{
+			name:         "validation message in the user locale",
+			body:         RequestPreferences{Currency: "EUR", Timezone: "Europe/Berlin", Locale: "ru", WeekStart: 0, MonthStart: 25},
+			preferences:  &pref,
+			mockErr:      validErr,
+			status:       http.StatusBadRequest,
+			message:      "поле Locale должно быть одним из: en ru",
+			shouldCallDB: true,
+		},
+		{
+			name:         "validation message in the Accept-Language",
+			body:         RequestPreferences{Currency: "EUR", Timezone: "Europe/Berlin", Locale: "ru", WeekStart: 0, MonthStart: 25},
+			header:       "ru-RU,ru;q=0.9,en;q=0.8",
+			mockErr:      validErr,
+			status:       http.StatusBadRequest,
+			message:      "поле Locale должно быть одним из: en ru",
+			shouldCallDB: true,
+		},
+		{
+			name:         "missing month start",
+			body:         RequestPreferences{Currency: "EUR", Timezone: "Europe/Berlin", Locale: "ru"},
+			status:       http.StatusBadRequest,
+			shouldCallDB: false,
+		},
+		{
+			name:         "invalid json",
+			invalidJSON:  true,
+			status:       http.StatusBadRequest,
+			shouldCallDB: false,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Set("userID", uint(1))
+			if tc.preferences != nil {
+				c.Set("preferences", *tc.preferences)
+			}
+
+			svc := new(profileServicMock)
+			ctx := context.Background()
+			svc.On("UpdatePreferences", mock.Anything, uint(1), pref).Return(pref, tc.mockErr)
+
+			h := CreateProfileHandlers(svc, svc, logrus.New(), ctx)
+
+			req := http.Request{Header: make(http.Header), URL: &url.URL{}}
+			if tc.invalidJSON {
+				req.Body = io.NopCloser(bytes.NewBufferString("{"))
+			} else {
+				b, _ := json.Marshal(tc.body)
+				req.Body = io.NopCloser(bytes.NewBuffer(b))
+			}
+			req.Header.Set("content-type", "application/json")
+			req.Header.Set("Accept-Language", tc.header)
+			c.Request = req.WithContext(ctx)
+
+			h.UpdatePreferences(c)
+
+			assert.Equal(t, tc.status, w.Code)
+			assert.Equal(t, true, strings.Contains(w.Body.String(), tc.message))
+			if tc.shouldCallDB {
+				svc.AssertCalled(t, "UpdatePreferences", mock.Anything, uint(1), pref)
+			} else {
+				svc.AssertNotCalled(t, "UpdatePreferences", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
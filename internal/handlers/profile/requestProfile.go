@@ -0,0 +1,19 @@
+package profileHandlers
+
+// RequestUpdateProfile represents update profile request, the password is required to
+// change the email
+type RequestUpdateProfile struct {
+	Name     string `json:"name" example:"jonn"`
+	Email    string `json:"email" example:"jonn@gmail.com"`
+	Password string `json:"password" example:"securitycod123"`
+}
+
+// RequestPreferences represents update preferences request, week_start is the first day
+// of the week (0 - sunday, 1 - monday), month_start is the day the reporting month starts on
+type RequestPreferences struct {
+	Currency   string `json:"currency" binding:"required" example:"RUB"`
+	Timezone   string `json:"timezone" binding:"required" example:"Europe/Moscow"`
+	Locale     string `json:"locale" binding:"required" example:"ru"`
+	WeekStart  int    `json:"week_start" example:"1"`
+	MonthStart int    `json:"month_start" binding:"required" example:"1"`
+}
@@ -2,10 +2,28 @@ package handlers
 
 import (
 	"github.com/financial_tracer/docs"
+	"github.com/financial_tracer/internal/domain"
+	accessTokenHandlers "github.com/financial_tracer/internal/handlers/accesstoken"
+	adminHandlers "github.com/financial_tracer/internal/handlers/admin"
 	categoryHandlers "github.com/financial_tracer/internal/handlers/categories"
+	comparisonHandlers "github.com/financial_tracer/internal/handlers/comparison"
+	exportHandlers "github.com/financial_tracer/internal/handlers/export"
+	forecastHandlers "github.com/financial_tracer/internal/handlers/forecast"
+	householdHandlers "github.com/financial_tracer/internal/handlers/household"
+	inviteHandlers "github.com/financial_tracer/internal/handlers/invite"
+	jwksHandlers "github.com/financial_tracer/internal/handlers/jwks"
+	ledgerHandlers "github.com/financial_tracer/internal/handlers/ledger"
 	"github.com/financial_tracer/internal/handlers/middlewares"
+	passwordHandlers "github.com/financial_tracer/internal/handlers/password"
+	profileHandlers "github.com/financial_tracer/internal/handlers/profile"
+	searchHandlers "github.com/financial_tracer/internal/handlers/search"
+	securityHandlers "github.com/financial_tracer/internal/handlers/security"
+	ssoHandlers "github.com/financial_tracer/internal/handlers/sso"
 	transactionHandlers "github.com/financial_tracer/internal/handlers/transaction"
+	twofactorHandlers "github.com/financial_tracer/internal/handlers/twofactor"
 	userHandlers "github.com/financial_tracer/internal/handlers/user"
+	verificationHandlers "github.com/financial_tracer/internal/handlers/verification"
+	jwttoken "github.com/financial_tracer/internal/lib/jwtToken"
 	"github.com/gin-contrib/pprof"
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
@@ -13,6 +31,9 @@ import (
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
+// BasePath is the prefix of the API routes, the session cookies are scoped to it.
+const BasePath = "/financial_tracker"
+
 // @title						Финансовый Трекер
 // @version					1.0
 // @description				API для работы с финансовым трекером
@@ -25,13 +46,17 @@ import (
 // @securityDefinitions.apiKey	jwtAuth
 // @in							header
 // @name						Authorization
-// @description				type "Bearer" после пробел и jwt token, пример: "Bearer zpdgjeawzgp0398tuP29R0J20THVTP9235BHRNr312r346as2..."
-func Router(users *userHandlers.HandlersUser, category *categoryHandlers.CategoryHandlers, log *logrus.Logger, tran *transactionHandlers.TransactionHandlers, secretKey string) *gin.Engine {
+// @description				type "Bearer" после пробел и jwt token или токен доступа ft_pat_..., пример: "Bearer zpdgjeawzgp0398tuP29R0J20THVTP9235BHRNr312r346as2..."
+func Router(users *userHandlers.HandlersUser, category *categoryHandlers.CategoryHandlers, log *logrus.Logger, tran *transactionHandlers.TransactionHandlers, ledger *ledgerHandlers.LedgerHandlers, forecast *forecastHandlers.ForecastHandlers, search *searchHandlers.SearchHandlers, comparison *comparisonHandlers.ComparisonHandlers, passwords *passwordHandlers.PasswordHandlers, verifications *verificationHandlers.VerificationHandlers, twoFactor *twofactorHandlers.TwoFactorHandlers, profile *profileHandlers.ProfileHandlers, exports *exportHandlers.ExportHandlers, tokens *accessTokenHandlers.AccessTokenHandlers, admins *adminHandlers.AdminHandlers, households *householdHandlers.HouseholdHandlers, ssos *ssoHandlers.SSOHandlers, securities *securityHandlers.SecurityHandlers, invites *inviteHandlers.InviteHandlers, cors middlewares.CORS, cookies gin.HandlerFunc, sessions middlewares.SessionChecker, accessTokens middlewares.AccessTokenChecker, roles middlewares.RoleChecker, verified gin.HandlerFunc, preferences gin.HandlerFunc, member gin.HandlerFunc, jwks *jwksHandlers.JWKSHandlers, keys *jwttoken.KeySet) *gin.Engine {
 	r := gin.Default()
 
-	api := r.Group("/financial_tracker")
+	auth := middlewares.JWToken(keys, sessions, accessTokens, log)
+
+	r.GET("/.well-known/jwks.json", jwks.JWKS)
+
+	api := r.Group(BasePath)
 	api.Use(middlewares.Logging(log))
-	api.Use(middlewares.CORSMiddleware())
+	api.Use(middlewares.CORSMiddleware(cors), cookies)
 
 	registration := api.Group("/registration")
 	{
@@ -40,17 +65,46 @@ func Router(users *userHandlers.HandlersUser, category *categoryHandlers.Categor
 		registration.POST("/login", users.Authentication)
 
 		registration.POST("/access_token", users.GetAccessToken)
+		registration.POST("/password/forgot", passwords.ForgotPassword)
+		registration.POST("/password/reset", passwords.ResetPassword)
+		registration.POST("/verify", verifications.VerifyEmail)
+		registration.POST("/2fa", twoFactor.Login)
+		registration.POST("/2fa/recover", twoFactor.Recover)
+		registration.GET("/oidc", ssos.ListProviders)
+		registration.GET("/oidc/:provider", ssos.Start)
+		registration.GET("/oidc/:provider/callback", ssos.Callback)
 	}
 
+	api.GET("/export/download", exports.Download)
+
 	user := api.Group("/user")
 	user.Use(middlewares.Logging(log))
-	user.Use(middlewares.JWToken(secretKey, log))
+	user.Use(auth, middlewares.Scope("", log), preferences)
 	{
+		user.GET("/", profile.GetProfile)
+		user.PUT("/", profile.UpdateProfile)
 		user.DELETE("/", users.DeleteUser)
+		user.GET("/preferences", profile.GetPreferences)
+		user.PUT("/preferences", profile.UpdatePreferences)
+		user.POST("/logout", users.Logout)
+		user.GET("/sessions", users.ListSessions)
+		user.DELETE("/sessions/:id", users.DeleteSession)
+		user.GET("/security-events", securities.ListEvents)
+		user.PUT("/password", passwords.ChangePassword)
+		user.POST("/verify/resend", verifications.ResendVerification)
+		user.POST("/2fa/enroll", twoFactor.Enroll)
+		user.POST("/2fa/confirm", twoFactor.Confirm)
+		user.DELETE("/2fa", twoFactor.Disable)
+		user.POST("/export", exports.RequestExport)
+		user.GET("/export", exports.ListExports)
+		user.GET("/export/:id", exports.GetExport)
+		user.POST("/tokens", tokens.CreateAccessToken)
+		user.GET("/tokens", tokens.ListAccessTokens)
+		user.DELETE("/tokens/:id", tokens.RevokeAccessToken)
 	}
 
 	categories := api.Group("/category")
-	categories.Use(middlewares.JWToken(secretKey, log))
+	categories.Use(auth, middlewares.Scope("categories", log), verified, member, preferences)
 	{
 		categories.GET("/:id", category.GetCategory)
 		categories.GET("/type/:type", category.CategoryType)
@@ -60,15 +114,59 @@ func Router(users *userHandlers.HandlersUser, category *categoryHandlers.Categor
 	}
 
 	transaction := api.Group("/transaction")
-	transaction.Use(middlewares.JWToken(secretKey, log))
+	transaction.Use(auth, middlewares.Scope("transactions", log), verified, member, preferences)
 	{
 		transaction.POST("/", tran.PostTransaction)
 		transaction.GET("/:id", tran.GetTransaction)
 		transaction.PUT("/", tran.UpdateTransaction)
 		transaction.DELETE("/:id", tran.DeleteTransaction)
+		transaction.GET("/anomalies", tran.ListAnomalies)
+		transaction.PUT("/anomalies/:id", tran.ReviewAnomaly)
+		transaction.GET("/search", search.SearchTransactions)
+	}
+
+	journal := api.Group("/journal")
+	journal.Use(auth, middlewares.Scope("journal", log), verified, member, preferences)
+	{
+		journal.GET("/export", ledger.ExportJournal)
+		journal.POST("/import", ledger.ImportJournal)
+	}
+
+	report := api.Group("/report")
+	report.Use(auth, middlewares.Scope("reports", log), verified, member, preferences)
+	{
+		report.GET("/forecast", forecast.Forecast)
+		report.GET("/compare", comparison.Compare)
+	}
+
+	household := api.Group("/household")
+	household.Use(auth, middlewares.Scope("", log), verified, preferences)
+	{
+		household.GET("/", households.ListHouseholds)
+		household.POST("/", households.CreateHousehold)
+		household.POST("/invitations/accept", households.Accept)
+		household.GET("/:id", households.GetHousehold)
+		household.POST("/:id/invitations", households.Invite)
+		household.PUT("/:id/members/:user", households.UpdateMember)
+		household.DELETE("/:id/members/:user", households.RemoveMember)
+	}
+
+	admin := api.Group("/admin")
+	admin.Use(auth, middlewares.Scope("", log), middlewares.Role(roles, domain.RoleAdmin, log))
+	{
+		admin.GET("/users", admins.ListUsers)
+		admin.GET("/users/:id", admins.GetUser)
+		admin.POST("/users/:id/disable", admins.DisableUser)
+		admin.POST("/users/:id/enable", admins.EnableUser)
+		admin.POST("/users/:id/logout", admins.LogoutUser)
+		admin.PUT("/users/:id/role", admins.SetRole)
+		admin.GET("/security-events", securities.ListAllEvents)
+		admin.POST("/invites", invites.CreateInvite)
+		admin.GET("/invites", invites.ListInvites)
+		admin.DELETE("/invites/:id", invites.RevokeInvite)
 	}
 
-	docs.SwaggerInfo.BasePath = "/financial_tracker"
+	docs.SwaggerInfo.BasePath = BasePath
 	api.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 	pprof.Register(api, "/debug/pprof")
 
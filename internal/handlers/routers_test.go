@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/financial_tracer/internal/domain"
+	categoryHandlers "github.com/financial_tracer/internal/handlers/categories"
+	forecastHandlers "github.com/financial_tracer/internal/handlers/forecast"
+	householdHandlers "github.com/financial_tracer/internal/handlers/household"
+	ledgerHandlers "github.com/financial_tracer/internal/handlers/ledger"
+	"github.com/financial_tracer/internal/handlers/middlewares"
+	profileHandlers "github.com/financial_tracer/internal/handlers/profile"
+	transactionHandlers "github.com/financial_tracer/internal/handlers/transaction"
+	"github.com/financial_tracer/internal/lib/journal"
+	jwttoken "github.com/financial_tracer/internal/lib/jwtToken"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// routerStub answers the middlewares and the services of the checked routes.
+type routerStub struct {
+	householdHandlers.HouseholdServic
+}
+
+func (routerStub) SessionActive(ctx context.Context, userID uint, sessionID uint) (bool, error) {
+	return true, nil
+}
+
+func (routerStub) Authenticate(ctx context.Context, token string) (domain.AccessToken, error) {
+	return domain.AccessToken{}, nil
+}
+
+func (routerStub) UserRole(ctx context.Context, userID uint) (string, error) {
+	return domain.RoleUser, nil
+}
+
+func (routerStub) UserVerified(ctx context.Context, userID uint) (bool, error) {
+	return true, nil
+}
+
+func (routerStub) UserPreferences(ctx context.Context, userID uint) (domain.Preferences, error) {
+	return domain.Preferences{Locale: "ru"}, nil
+}
+
+func (routerStub) Member(ctx context.Context, userID uint, householdID uint) (domain.Member, error) {
+	return domain.Member{UserID: userID, HouseholdID: 1, Role: domain.HouseholdOwner}, nil
+}
+
+func (routerStub) Preferences(ctx context.Context, userID uint) (domain.Preferences, error) {
+	return domain.Preferences{}, nil
+}
+
+func (routerStub) UpdatePreferences(ctx context.Context, userID uint, pref domain.Preferences) (domain.Preferences, error) {
+	return pref, nil
+}
+
+func (routerStub) GetCategory(ctx context.Context, member domain.Member, idCategory uint) (domain.CategoryOutput, error) {
+	return domain.CategoryOutput{}, nil
+}
+
+func (routerStub) GetTransaction(ctx context.Context, member domain.Member, idTransaction uint) (domain.TransactionOutput, error) {
+	return domain.TransactionOutput{}, nil
+}
+
+func (routerStub) ExportJournal(ctx context.Context, member domain.Member, format string) ([]byte, journal.Format, error) {
+	return nil, journal.Ledger, nil
+}
+
+func (routerStub) Forecast(ctx context.Context, member domain.Member, pref domain.Preferences) (domain.Forecast, error) {
+	return domain.Forecast{}, nil
+}
+
+func (routerStub) Households(ctx context.Context, userID uint) ([]domain.Household, error) {
+	return nil, nil
+}
+
+func TestRouterMiddlewares(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	log := logrus.New()
+	ctx := context.Background()
+	stub := routerStub{}
+
+	keys, err := jwttoken.NewHMACKeySet("tracker", "tracker", "test", "secret", nil)
+	require.NoError(t, err)
+	token, err := keys.JWTAccessToken(1, "jonn", 1)
+	require.NoError(t, err)
+
+	// the outermost middleware of the API sees the keys the inner ones have set
+	var seen map[string]any
+	record := func(c *gin.Context) {
+		c.Next()
+		seen = c.Keys
+	}
+
+	r := Router(nil,
+		categoryHandlers.CreateHandlersCategory(nil, stub, nil, nil, nil, log, ctx),
+		log,
+		transactionHandlers.CreateTransactionHandlers(nil, stub, nil, nil, nil, log, ctx),
+		ledgerHandlers.CreateLedgerHandlers(stub, nil, log, ctx),
+		forecastHandlers.CreateForecastHandlers(stub, log, ctx),
+		nil, nil, nil, nil, nil,
+		profileHandlers.CreateProfileHandlers(nil, stub, log, ctx),
+		nil, nil, nil,
+		householdHandlers.CreateHouseholdHandlers(stub, log, ctx),
+		nil, nil, nil,
+		middlewares.CORS{}, record, stub, stub, stub,
+		middlewares.Verified(stub, middlewares.AccessFull, log),
+		middlewares.Preferences(stub, log),
+		middlewares.Household(stub, log),
+		nil, keys)
+
+	tests := []struct {
+		name   string
+		path   string
+		member bool
+	}{
+		{name: "user", path: "/user/preferences"},
+		{name: "category", path: "/category/1", member: true},
+		{name: "transaction", path: "/transaction/1", member: true},
+		{name: "journal", path: "/journal/export?format=ledger", member: true},
+		{name: "report", path: "/report/forecast", member: true},
+		{name: "household", path: "/household/"},
+	}
+
+	for _, ts := range tests {
+		t.Run(ts.name, func(t *testing.T) {
+			seen = nil
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, BasePath+ts.path, nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, domain.Preferences{Locale: "ru"}, seen["preferences"])
+			_, ok := seen["member"]
+			assert.Equal(t, ts.member, ok)
+		})
+	}
+}
@@ -0,0 +1,8 @@
+package searchHandlers
+
+// RequestSearch represents search transactions request
+type RequestSearch struct {
+	Query  string `form:"q" binding:"required" example:"стоматолог весна"`
+	Limit  int    `form:"limit" example:"20"`
+	Offset int    `form:"offset" example:"0"`
+}
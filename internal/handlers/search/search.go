@@ -0,0 +1,88 @@
+package searchHandlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/financial_tracer/internal/handlers/api"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type SearchTransactionsServic interface {
+	SearchTransactions(ctx context.Context, member domain.Member, query domain.SearchQuery) ([]domain.TransactionSearchResult, error)
+}
+
+type SearchHandlers struct {
+	s   SearchTransactionsServic
+	log *logrus.Logger
+	ctx context.Context
+}
+
+func CreateSearchHandlers(s SearchTransactionsServic, log *logrus.Logger, ctx context.Context) *SearchHandlers {
+	return &SearchHandlers{
+		s:   s,
+		log: log,
+		ctx: ctx,
+	}
+}
+
+// SearchTransactions godoc
+//
+//	@Summary		Поиск транзакций
+//	@Description	Полнотекстовый поиск по названию и описанию транзакций пользователя (русский и английский). Результаты отсортированы по релевантности, найденные слова выделены <b></b>
+//	@Tags			transaction
+//	@Produce		json
+//	@Param			q				query		string				true	"поисковый запрос"
+//	@Param			limit			query		int					false	"количество результатов"	default(20)
+//	@Param			offset			query		int					false	"смещение"					default(0)
+//	@Param			X-Household-ID	header		int					false	"id общего бюджета, по умолчанию личный"
+//	@Success		200				{object}	api.SuccessResponse	"Найденные транзакции"
+//
+//	@Failure		401				{object}	api.ErrorResponse	"Ошибка авторизации"
+//	@Failure		400				{object}	api.ErrorResponse	"Некорректные входные данные"
+//	@Failure		500				{object}	api.ErrorResponse	"Ошибка сервера"
+//	@Failure		403				{object}	api.ErrorResponse	"Нет доступа к бюджету"
+//
+//	@Router			/transaction/search [get]
+//
+//	@Security		jwtAuth
+func (h *SearchHandlers) SearchTransactions(c *gin.Context) {
+	const op = "handlers.SearchTransactions"
+
+	log := h.log.WithField("op", op)
+
+	log.Info("start search transactions")
+
+	var req RequestSearch
+	if err := c.ShouldBindQuery(&req); err != nil {
+		log.WithField("err", err).Error("error valid query")
+		api.ResponseError(c, http.StatusBadRequest, "error valid query")
+		return
+	}
+
+	member, ok := api.Member(c)
+	if !ok {
+		log.Error("error get member")
+		api.ResponseError(c, http.StatusInternalServerError, "error server")
+		return
+	}
+
+	query := domain.SearchQuery{
+		Query:  req.Query,
+		Limit:  req.Limit,
+		Offset: req.Offset,
+	}
+
+	result, err := h.s.SearchTransactions(c.Request.Context(), member, query)
+	if err != nil {
+		log.WithField("err", err).Error("error search transactions")
+		api.RegistrationError(c, err)
+		return
+	}
+
+	log.Info("success search transactions")
+
+	api.ResponseOK(c, result)
+}
@@ -0,0 +1,17 @@
+package searchHandlers
+
+import (
+	"context"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type searchServicMock struct {
+	mock.Mock
+}
+
+func (m *searchServicMock) SearchTransactions(ctx context.Context, member domain.Member, query domain.SearchQuery) ([]domain.TransactionSearchResult, error) {
+	args := m.Called(ctx, member, query)
+	return args.Get(0).([]domain.TransactionSearchResult), args.Error(1)
+}
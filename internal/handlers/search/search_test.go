@@ -0,0 +1,88 @@
+package searchHandlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/financial_tracer/internal/servic/search"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/assert/v2"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/mock"
+)
+
+var member = domain.Member{UserID: 1, HouseholdID: 3, Role: domain.HouseholdViewer}
+
+func TestSearchTransactions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name         string
+		rawQuery     string
+		query        domain.SearchQuery
+		result       []domain.TransactionSearchResult
+		mockErr      error
+		status       int
+		shouldCallDB bool
+	}{
+		{
+			name:         "success",
+			rawQuery:     url.Values{"q": {"стоматолог"}, "limit": {"5"}}.Encode(),
+			query:        domain.SearchQuery{Query: "стоматолог", Limit: 5},
+			result:       []domain.TransactionSearchResult{{NameHighlight: "<b>стоматолог</b>"}},
+			status:       http.StatusOK,
+			shouldCallDB: true,
+		},
+		{
+			name:         "missing query",
+			rawQuery:     "limit=5",
+			status:       http.StatusBadRequest,
+			shouldCallDB: false,
+		},
+		{
+			name:         "invalid limit",
+			rawQuery:     "q=dentist&limit=many",
+			status:       http.StatusBadRequest,
+			shouldCallDB: false,
+		},
+		{
+			name:         "error database",
+			rawQuery:     "q=dentist",
+			query:        domain.SearchQuery{Query: "dentist"},
+			result:       []domain.TransactionSearchResult{},
+			mockErr:      search.ErrDatabase,
+			status:       http.StatusInternalServerError,
+			shouldCallDB: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Set("member", member)
+
+			svc := new(searchServicMock)
+			ctx := context.Background()
+			svc.On("SearchTransactions", mock.Anything, member, tc.query).Return(tc.result, tc.mockErr)
+
+			h := CreateSearchHandlers(svc, logrus.New(), ctx)
+
+			req := http.Request{Header: make(http.Header), URL: &url.URL{RawQuery: tc.rawQuery}}
+			c.Request = req.WithContext(ctx)
+
+			h.SearchTransactions(c)
+
+			assert.Equal(t, tc.status, w.Code)
+			if tc.shouldCallDB {
+				svc.AssertCalled(t, "SearchTransactions", mock.Anything, member, tc.query)
+			} else {
+				svc.AssertNotCalled(t, "SearchTransactions", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
@@ -0,0 +1,19 @@
+package securityHandlers
+
+import "time"
+
+// RequestEvents represents security events request, to is inclusive
+type RequestEvents struct {
+	Type    string    `form:"type" example:"login"`
+	Success *bool     `form:"success" example:"false"`
+	From    time.Time `form:"from" time_format:"2006-01-02" time_utc:"1" example:"2025-07-01"`
+	To      time.Time `form:"to" time_format:"2006-01-02" time_utc:"1" example:"2025-07-31"`
+	Limit   int       `form:"limit" example:"20"`
+	Offset  int       `form:"offset" example:"0"`
+}
+
+// RequestAllEvents represents security events of all users request, user_id selects one user
+type RequestAllEvents struct {
+	UserID uint `form:"user_id" example:"3"`
+	RequestEvents
+}
@@ -0,0 +1,155 @@
+package securityHandlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/financial_tracer/internal/handlers/api"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type SecurityServic interface {
+	Events(ctx context.Context, userID uint, filter domain.SecurityEventFilter) (domain.SecurityEventList, error)
+	AllEvents(ctx context.Context, filter domain.SecurityEventFilter) (domain.SecurityEventList, error)
+}
+
+type SecurityHandlers struct {
+	s   SecurityServic
+	log *logrus.Logger
+	ctx context.Context
+}
+
+func CreateSecurityHandlers(s SecurityServic, log *logrus.Logger, ctx context.Context) *SecurityHandlers {
+	return &SecurityHandlers{
+		s:   s,
+		log: log,
+		ctx: ctx,
+	}
+}
+
+// ListEvents godoc
+//
+//	@Summary		История входов
+//	@Description	События безопасности пользователя от новых к старым: входы (успешные и нет), обновления токенов, смена и сброс пароля, включение и отключение 2FA с IP и User-Agent. new_device отмечает вход с нового устройства, о нем приходит письмо
+//
+//	@Tags			User
+//
+//	@Produce		json
+//	@Param			type	query		string				false	"тип: login, refresh, password_change, password_reset, two_factor_enable, two_factor_disable"
+//	@Param			success	query		bool				false	"успешно ли действие"
+//	@Param			from	query		string				false	"с даты (YYYY-MM-DD)"
+//	@Param			to		query		string				false	"по дату включительно (YYYY-MM-DD)"
+//	@Param			limit	query		int					false	"количество событий"	default(20)
+//	@Param			offset	query		int					false	"смещение"				default(0)
+//	@Success		200		{object}	api.SuccessResponse	"События"
+//
+//	@Failure		400		{object}	api.ErrorResponse	"Некорректные входные данные"
+//	@Failure		401		{object}	api.ErrorResponse	"Ошибка авторизации"
+//	@Failure		500		{object}	api.ErrorResponse	"Ошибка сервера"
+//
+//	@Router			/user/security-events [get]
+//
+//	@Security		jwtAuth
+func (h *SecurityHandlers) ListEvents(c *gin.Context) {
+	const op = "handlers.ListSecurityEvents"
+
+	log := h.log.WithField("op", op)
+
+	log.Info("start list security events")
+
+	idUser, ok := c.Get("userID")
+	if !ok {
+		log.Error("error get userID")
+		api.ResponseError(c, http.StatusInternalServerError, "error server")
+		return
+	}
+
+	var req RequestEvents
+	if err := c.ShouldBindQuery(&req); err != nil {
+		log.WithField("err", err).Error("error valid query")
+		api.ResponseError(c, http.StatusBadRequest, "error valid query")
+		return
+	}
+
+	list, err := h.s.Events(c.Request.Context(), idUser.(uint), filter(req))
+	if err != nil {
+		log.WithField("err", err).Error("error list security events")
+		api.RegistrationError(c, err)
+		return
+	}
+
+	log.Info("success list security events")
+
+	api.ResponseOK(c, list)
+}
+
+// ListAllEvents godoc
+//
+//	@Summary		События безопасности пользователей
+//	@Description	События безопасности всех пользователей или одного от новых к старым. Неудачные входы с неизвестным email имеют user_id 0 и email, с которым был вход
+//
+//	@Tags			Admin
+//
+//	@Produce		json
+//	@Param			user_id	query		int					false	"id пользователя"
+//	@Param			type	query		string				false	"тип: login, refresh, password_change, password_reset, two_factor_enable, two_factor_disable"
+//	@Param			success	query		bool				false	"успешно ли действие"
+//	@Param			from	query		string				false	"с даты (YYYY-MM-DD)"
+//	@Param			to		query		string				false	"по дату включительно (YYYY-MM-DD)"
+//	@Param			limit	query		int					false	"количество событий"	default(20)
+//	@Param			offset	query		int					false	"смещение"				default(0)
+//	@Success		200		{object}	api.SuccessResponse	"События"
+//
+//	@Failure		400		{object}	api.ErrorResponse	"Некорректные входные данные"
+//	@Failure		401		{object}	api.ErrorResponse	"Ошибка авторизации"
+//	@Failure		403		{object}	api.ErrorResponse	"Нужна роль admin"
+//	@Failure		500		{object}	api.ErrorResponse	"Ошибка сервера"
+//
+//	@Router			/admin/security-events [get]
+//
+//	@Security		jwtAuth
+func (h *SecurityHandlers) ListAllEvents(c *gin.Context) {
+	const op = "handlers.ListAllSecurityEvents"
+
+	log := h.log.WithField("op", op)
+
+	log.Info("start list all security events")
+
+	var req RequestAllEvents
+	if err := c.ShouldBindQuery(&req); err != nil {
+		log.WithField("err", err).Error("error valid query")
+		api.ResponseError(c, http.StatusBadRequest, "error valid query")
+		return
+	}
+
+	f := filter(req.RequestEvents)
+	f.UserID = req.UserID
+
+	list, err := h.s.AllEvents(c.Request.Context(), f)
+	if err != nil {
+		log.WithField("err", err).Error("error list all security events")
+		api.RegistrationError(c, err)
+		return
+	}
+
+	log.Info("success list all security events")
+
+	api.ResponseOK(c, list)
+}
+
+func filter(req RequestEvents) domain.SecurityEventFilter {
+	f := domain.SecurityEventFilter{
+		Type:    req.Type,
+		Success: req.Success,
+		From:    req.From,
+		Limit:   req.Limit,
+		Offset:  req.Offset,
+	}
+	if !req.To.IsZero() {
+		f.To = req.To.AddDate(0, 0, 1)
+	}
+
+	return f
+}
@@ -0,0 +1,22 @@
+package securityHandlers
+
+import (
+	"context"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type securityServicMock struct {
+	mock.Mock
+}
+
+func (m *securityServicMock) Events(ctx context.Context, userID uint, filter domain.SecurityEventFilter) (domain.SecurityEventList, error) {
+	args := m.Called(ctx, userID, filter)
+	return args.Get(0).(domain.SecurityEventList), args.Error(1)
+}
+
+func (m *securityServicMock) AllEvents(ctx context.Context, filter domain.SecurityEventFilter) (domain.SecurityEventList, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).(domain.SecurityEventList), args.Error(1)
+}
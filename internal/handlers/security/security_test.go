@@ -0,0 +1,143 @@
+package securityHandlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/financial_tracer/internal/servic/security"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/assert/v2"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestListEvents(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	failed := false
+
+	tests := []struct {
+		name         string
+		query        string
+		filter       domain.SecurityEventFilter
+		mockErr      error
+		status       int
+		shouldCallDB bool
+	}{
+		{
+			name:  "success",
+			query: "type=login&success=false&from=2025-07-01&to=2025-07-31&limit=10",
+			filter: domain.SecurityEventFilter{
+				Type:    domain.EventLogin,
+				Success: &failed,
+				From:    time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC),
+				To:      time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC),
+				Limit:   10,
+			},
+			status:       http.StatusOK,
+			shouldCallDB: true,
+		},
+		{
+			name:         "error database",
+			filter:       domain.SecurityEventFilter{},
+			mockErr:      security.ErrDatabase,
+			status:       http.StatusInternalServerError,
+			shouldCallDB: true,
+		},
+		{
+			name:   "error query",
+			query:  "from=yesterday",
+			status: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Set("userID", uint(1))
+
+			svc := new(securityServicMock)
+			ctx := context.Background()
+			svc.On("Events", mock.Anything, uint(1), tc.filter).Return(domain.SecurityEventList{}, tc.mockErr)
+
+			h := CreateSecurityHandlers(svc, logrus.New(), ctx)
+
+			req := http.Request{Header: make(http.Header), URL: &url.URL{RawQuery: tc.query}}
+			c.Request = req.WithContext(ctx)
+
+			h.ListEvents(c)
+
+			assert.Equal(t, tc.status, w.Code)
+			if tc.shouldCallDB {
+				svc.AssertExpectations(t)
+			} else {
+				svc.AssertNotCalled(t, "Events", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestListAllEvents(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name         string
+		query        string
+		filter       domain.SecurityEventFilter
+		mockErr      error
+		status       int
+		shouldCallDB bool
+	}{
+		{
+			name:         "success",
+			query:        "user_id=3&type=refresh",
+			filter:       domain.SecurityEventFilter{UserID: 3, Type: domain.EventRefresh},
+			status:       http.StatusOK,
+			shouldCallDB: true,
+		},
+		{
+			name:         "error database",
+			filter:       domain.SecurityEventFilter{},
+			mockErr:      security.ErrDatabase,
+			status:       http.StatusInternalServerError,
+			shouldCallDB: true,
+		},
+		{
+			name:   "error query",
+			query:  "user_id=jonn",
+			status: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Set("userID", uint(1))
+
+			svc := new(securityServicMock)
+			ctx := context.Background()
+			svc.On("AllEvents", mock.Anything, tc.filter).Return(domain.SecurityEventList{}, tc.mockErr)
+
+			h := CreateSecurityHandlers(svc, logrus.New(), ctx)
+
+			req := http.Request{Header: make(http.Header), URL: &url.URL{RawQuery: tc.query}}
+			c.Request = req.WithContext(ctx)
+
+			h.ListAllEvents(c)
+
+			assert.Equal(t, tc.status, w.Code)
+			if tc.shouldCallDB {
+				svc.AssertExpectations(t)
+			} else {
+				svc.AssertNotCalled(t, "AllEvents", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
@@ -0,0 +1,10 @@
+package ssoHandlers
+
+// RequestCallback represents the redirect back from the identity provider, error is set
+// when the login at the provider failed
+type RequestCallback struct {
+	Code        string `form:"code" example:"SplxlOBeZQQYbYS6WxSbIA"`
+	State       string `form:"state" example:"af0ifjsldkj"`
+	Error       string `form:"error" example:"access_denied"`
+	Description string `form:"error_description" example:"the user canceled the login"`
+}
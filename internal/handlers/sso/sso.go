@@ -0,0 +1,146 @@
+package ssoHandlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/financial_tracer/internal/handlers/api"
+	jwttoken "github.com/financial_tracer/internal/lib/jwtToken"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type SSOServic interface {
+	Providers() []string
+	Start(ctx context.Context, provider string) (domain.OIDCStart, error)
+	Callback(ctx context.Context, req domain.OIDCCallback) (jwttoken.ResponseJWTUser, error)
+}
+
+type SSOHandlers struct {
+	s   SSOServic
+	log *logrus.Logger
+	ctx context.Context
+}
+
+func CreateSSOHandlers(s SSOServic, log *logrus.Logger, ctx context.Context) *SSOHandlers {
+	return &SSOHandlers{
+		s:   s,
+		log: log,
+		ctx: ctx,
+	}
+}
+
+// ListProviders godoc
+//
+//	@Summary		Провайдеры SSO
+//	@Description	Имена настроенных провайдеров OpenID Connect для входа через корпоративный SSO
+//	@Tags			registration
+//
+//	@Produce		json
+//	@Success		200	{object}	api.SuccessResponse	"Провайдеры"
+//
+//	@Router			/registration/oidc [get]
+func (h *SSOHandlers) ListProviders(c *gin.Context) {
+	api.ResponseOK(c, h.s.Providers())
+}
+
+// Start godoc
+//
+//	@Summary		Вход через SSO
+//	@Description	Начало входа через провайдера OpenID Connect (authorization code + PKCE). Возвращает url, на который нужно перенаправить пользователя
+//	@Tags			registration
+//
+//	@Produce		json
+//	@Param			provider	path		string				true	"имя провайдера"
+//	@Success		200			{object}	api.SuccessResponse	"url провайдера"
+//
+//	@Failure		404			{object}	api.ErrorResponse	"Провайдер не найден"
+//	@Failure		502			{object}	api.ErrorResponse	"Провайдер недоступен"
+//	@Failure		500			{object}	api.ErrorResponse	"Ошибка сервера"
+//
+//	@Router			/registration/oidc/{provider} [get]
+func (h *SSOHandlers) Start(c *gin.Context) {
+	const op = "handlers.StartSSO"
+
+	log := h.log.WithFields(logrus.Fields{
+		"op":       op,
+		"provider": c.Param("provider"),
+	})
+
+	log.Info("start oidc login")
+
+	start, err := h.s.Start(c.Request.Context(), c.Param("provider"))
+	if err != nil {
+		log.WithField("err", err).Error("error start oidc login")
+		api.RegistrationError(c, err)
+		return
+	}
+
+	log.Info("success start oidc login")
+
+	api.ResponseOK(c, start)
+}
+
+// Callback godoc
+//
+//	@Summary		Завершение входа через SSO
+//	@Description	Обработка возврата от провайдера: обмен кода на ID token, проверка подписи по JWKS провайдера и привязка к пользователю по подтвержденному email. Возвращает пару токенов, при включенной 2FA challenge_token для /registration/2fa
+//	@Tags			registration
+//
+//	@Produce		json
+//	@Param			provider			path		string				true	"имя провайдера"
+//	@Param			code				query		string				false	"код авторизации"
+//	@Param			state				query		string				false	"state входа"
+//	@Param			error				query		string				false	"ошибка провайдера"
+//	@Param			error_description	query		string				false	"описание ошибки провайдера"
+//	@Param			X-Session-Mode		header		string				false	"cookie: токены выдаются в HttpOnly cookie, в ответе только csrf_token"
+//	@Success		200					{object}	api.SuccessResponse	"Авторизация пользователя"
+//
+//	@Failure		400					{object}	api.ErrorResponse	"Некорректный или просроченный вход"
+//	@Failure		401					{object}	api.ErrorResponse	"Провайдер отклонил вход"
+//	@Failure		403					{object}	api.ErrorResponse	"Email не подтвержден провайдером, пользователь заблокирован или регистрация новых пользователей запрещена"
+//	@Failure		404					{object}	api.ErrorResponse	"Провайдер не найден"
+//	@Failure		409					{object}	api.ErrorResponse	"Email существующего пользователя не подтвержден, нужно войти по паролю и подтвердить его"
+//	@Failure		500					{object}	api.ErrorResponse	"Ошибка сервера"
+//
+//	@Router			/registration/oidc/{provider}/callback [get]
+func (h *SSOHandlers) Callback(c *gin.Context) {
+	const op = "handlers.CallbackSSO"
+
+	log := h.log.WithFields(logrus.Fields{
+		"op":       op,
+		"provider": c.Param("provider"),
+	})
+
+	log.Info("start oidc callback")
+
+	var req RequestCallback
+	if err := c.ShouldBindQuery(&req); err != nil {
+		log.WithField("err", err).Error("error valid query")
+		api.ResponseError(c, http.StatusBadRequest, "error valid query")
+		return
+	}
+
+	if req.Error != "" {
+		log.WithFields(logrus.Fields{"error": req.Error, "description": req.Description}).Warn("login failed at the provider")
+		api.ResponseError(c, http.StatusUnauthorized, "identity provider rejected the login")
+		return
+	}
+
+	tokens, err := h.s.Callback(c.Request.Context(), domain.OIDCCallback{
+		Provider: c.Param("provider"),
+		Code:     req.Code,
+		State:    req.State,
+		Device:   api.Device(c),
+	})
+	if err != nil {
+		log.WithField("err", err).Error("error oidc callback")
+		api.RegistrationError(c, err)
+		return
+	}
+
+	log.Info("success oidc callback")
+
+	api.ResponseTokens(c, tokens)
+}
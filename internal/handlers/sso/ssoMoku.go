@@ -0,0 +1,28 @@
+package ssoHandlers
+
+import (
+	"context"
+
+	"github.com/financial_tracer/internal/domain"
+	jwttoken "github.com/financial_tracer/internal/lib/jwtToken"
+	"github.com/stretchr/testify/mock"
+)
+
+type ssoServicMock struct {
+	mock.Mock
+}
+
+func (m *ssoServicMock) Providers() []string {
+	args := m.Called()
+	return args.Get(0).([]string)
+}
+
+func (m *ssoServicMock) Start(ctx context.Context, provider string) (domain.OIDCStart, error) {
+	args := m.Called(ctx, provider)
+	return args.Get(0).(domain.OIDCStart), args.Error(1)
+}
+
+func (m *ssoServicMock) Callback(ctx context.Context, req domain.OIDCCallback) (jwttoken.ResponseJWTUser, error) {
+	args := m.Called(ctx, req)
+	return args.Get(0).(jwttoken.ResponseJWTUser), args.Error(1)
+}
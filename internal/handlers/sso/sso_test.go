@@ -0,0 +1,100 @@
+package ssoHandlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/financial_tracer/internal/domain"
+	jwttoken "github.com/financial_tracer/internal/lib/jwtToken"
+	"github.com/financial_tracer/internal/servic/sso"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/assert/v2"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestStart(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name    string
+		mockErr error
+		status  int
+	}{
+		{name: "success", status: http.StatusOK},
+		{name: "error provider", mockErr: sso.ErrProvider, status: http.StatusNotFound},
+		{name: "error unavailable", mockErr: sso.ErrUnavailable, status: http.StatusBadGateway},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Params = gin.Params{{Key: "provider", Value: "corp"}}
+
+			svc := new(ssoServicMock)
+			ctx := context.Background()
+			svc.On("Start", mock.Anything, "corp").Return(domain.OIDCStart{URL: "https://idp/authorize"}, tc.mockErr)
+
+			h := CreateSSOHandlers(svc, logrus.New(), ctx)
+
+			req := http.Request{Header: make(http.Header), URL: &url.URL{}}
+			c.Request = req.WithContext(ctx)
+
+			h.Start(c)
+
+			assert.Equal(t, tc.status, w.Code)
+			svc.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCallback(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name         string
+		query        string
+		mockErr      error
+		status       int
+		shouldCallDB bool
+	}{
+		{name: "success", query: "code=abc&state=xyz", status: http.StatusOK, shouldCallDB: true},
+		{name: "error state", query: "code=abc&state=xyz", mockErr: sso.ErrState, status: http.StatusBadRequest, shouldCallDB: true},
+		{name: "error login", query: "code=abc&state=xyz", mockErr: sso.ErrLogin, status: http.StatusUnauthorized, shouldCallDB: true},
+		{name: "error unverified email", query: "code=abc&state=xyz", mockErr: sso.ErrUnverified, status: http.StatusForbidden, shouldCallDB: true},
+		{name: "error unverified account", query: "code=abc&state=xyz", mockErr: sso.ErrUnlinked, status: http.StatusConflict, shouldCallDB: true},
+		{name: "error from provider", query: "error=access_denied&state=xyz", status: http.StatusUnauthorized},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Params = gin.Params{{Key: "provider", Value: "corp"}}
+
+			svc := new(ssoServicMock)
+			ctx := context.Background()
+			svc.On("Callback", mock.Anything, mock.MatchedBy(func(req domain.OIDCCallback) bool {
+				return req.Provider == "corp" && req.Code == "abc" && req.State == "xyz"
+			})).Return(jwttoken.ResponseJWTUser{AccessToken: "access"}, tc.mockErr)
+
+			h := CreateSSOHandlers(svc, logrus.New(), ctx)
+
+			req := http.Request{Header: make(http.Header), URL: &url.URL{RawQuery: tc.query}}
+			c.Request = req.WithContext(ctx)
+
+			h.Callback(c)
+
+			assert.Equal(t, tc.status, w.Code)
+			if tc.shouldCallDB {
+				svc.AssertExpectations(t)
+			} else {
+				svc.AssertNotCalled(t, "Callback", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
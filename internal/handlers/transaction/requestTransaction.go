@@ -15,3 +15,8 @@ type RequestUpdateTransaction struct {
 	Count         int    `json:"limit" binding:"required" example:"2000"`
 	Description   string `json:"description" example:"going to a restaurant"`
 }
+
+// RequestReviewAnomaly represents review of the flagged transaction
+type RequestReviewAnomaly struct {
+	Status string `json:"status" binding:"required" example:"dismissed"`
+}
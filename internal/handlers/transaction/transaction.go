@@ -12,19 +12,24 @@ import (
 )
 
 type CreateTransactionServic interface {
-	CreateTransaction(ctx context.Context, idUser uint, idCategory uint, tran domain.TransactionInput) (uint, error)
+	CreateTransaction(ctx context.Context, member domain.Member, idCategory uint, tran domain.TransactionInput) (uint, error)
 }
 
 type GetTransactionServic interface {
-	GetTransaction(ctx context.Context, idTransaction uint) (domain.TransactionOutput, error)
+	GetTransaction(ctx context.Context, member domain.Member, idTransaction uint) (domain.TransactionOutput, error)
 }
 
 type UpdateTransactionServic interface {
-	UpdateTransaction(ctx context.Context, idTransaction uint, newTransaction domain.TransactionInput) (domain.TransactionOutput, error)
+	UpdateTransaction(ctx context.Context, member domain.Member, idTransaction uint, newTransaction domain.TransactionInput) (domain.TransactionOutput, error)
 }
 
 type DeleteTransactionServic interface {
-	DeleteTransaction(ctx context.Context, idTransaction uint) error
+	DeleteTransaction(ctx context.Context, member domain.Member, idTransaction uint) error
+}
+
+type AnomalyServic interface {
+	ListAnomalies(ctx context.Context, member domain.Member, status string) ([]domain.TransactionAnomaly, error)
+	ReviewAnomaly(ctx context.Context, member domain.Member, idTransaction uint, status string) error
 }
 
 type TransactionHandlers struct {
@@ -32,6 +37,7 @@ type TransactionHandlers struct {
 	g   GetTransactionServic
 	u   UpdateTransactionServic
 	d   DeleteTransactionServic
+	a   AnomalyServic
 	log *logrus.Logger
 	ctx context.Context
 }
@@ -40,6 +46,7 @@ func CreateTransactionHandlers(c CreateTransactionServic,
 	g GetTransactionServic,
 	u UpdateTransactionServic,
 	d DeleteTransactionServic,
+	a AnomalyServic,
 	log *logrus.Logger,
 	ctx context.Context) *TransactionHandlers {
 	return &TransactionHandlers{
@@ -47,6 +54,7 @@ func CreateTransactionHandlers(c CreateTransactionServic,
 		d:   d,
 		g:   g,
 		u:   u,
+		a:   a,
 		log: log,
 		ctx: ctx,
 	}
@@ -59,14 +67,17 @@ func CreateTransactionHandlers(c CreateTransactionServic,
 //	@Tags			transaction
 //	@Accept			json
 //	@Produce		json
-//	@Param			req	body		RequestCreateTransaction	true	"данные для создание пользователя"
-//	@Success		200	{object}	api.SuccessResponse			"Транзакция создана успешно"
+//	@Param			req				body		RequestCreateTransaction	true	"данные для создание пользователя"
+//	@Param			X-Household-ID	header		int							false	"id общего бюджета, по умолчанию личный"
+//	@Success		200				{object}	api.SuccessResponse			"Транзакция создана успешно"
+//
+//	@Failure		401				{object}	api.ErrorResponse			"Ошибка авторизации"
 //
-//	@Failure		401	{object}	api.ErrorResponse			"Ошибка авторизации"
+//	@Failure		400				{object}	api.ErrorResponse			"Некорректные входные данные"
+//	@Failure		500				{object}	api.ErrorResponse			"Ошибка сервера"
+//	@Failure		400				{object}	api.ErrorResponse			"Некорректные данные"
+//	@Failure		403				{object}	api.ErrorResponse			"Нет доступа к бюджету"
 //
-//	@Failure		400	{object}	api.ErrorResponse			"Некорректные входные данные"
-//	@Failure		500	{object}	api.ErrorResponse			"Ошибка сервера"
-//	@Failure		400	{object}	api.ErrorResponse			"Некорректные данные"
 //	@Router			/transaction/ [post]
 //
 //	@Security		jwtAuth
@@ -84,9 +95,9 @@ func (th *TransactionHandlers) PostTransaction(c *gin.Context) {
 		api.ResponseError(c, http.StatusBadRequest, "error valid JSON")
 		return
 	}
-	idUser, ok := c.Get("userID")
+	member, ok := api.Member(c)
 	if !ok {
-		log.Error("error get userID")
+		log.Error("error get member")
 		api.ResponseError(c, http.StatusInternalServerError, "error server")
 		return
 	}
@@ -97,7 +108,7 @@ func (th *TransactionHandlers) PostTransaction(c *gin.Context) {
 		Description: transaction.Description,
 	}
 
-	id, err := th.c.CreateTransaction(th.ctx, idUser.(uint), transaction.IdCategory, newTransaction)
+	id, err := th.c.CreateTransaction(th.ctx, member, transaction.IdCategory, newTransaction)
 	if err != nil {
 		log.WithField("err", err).Error("error create transaction")
 		api.RegistrationError(c, err)
@@ -115,15 +126,18 @@ func (th *TransactionHandlers) PostTransaction(c *gin.Context) {
 //	@Description	Получение 1 транзакции для 1 пользователя
 //	@Tags			transaction
 //	@Produce		json
-//	@Param			id	path		int					true	"id транзакции"
-//	@Success		200	{object}	api.SuccessResponse	"good"
+//	@Param			id				path		int					true	"id транзакции"
+//	@Param			X-Household-ID	header		int					false	"id общего бюджета, по умолчанию личный"
+//	@Success		200				{object}	api.SuccessResponse	"good"
 //
-//	@Failure		401	{object}	api.ErrorResponse	"Ошибка авторизации"
+//	@Failure		401				{object}	api.ErrorResponse	"Ошибка авторизации"
+//
+//	@Failure		400				{object}	api.ErrorResponse	"Некорректные входные данные"
+//	@Failure		500				{object}	api.ErrorResponse	"Ошибка сервера"
+//	@Failure		400				{object}	api.ErrorResponse	"Некорректные данные"
+//	@Failure		404				{object}	api.ErrorResponse	"Транзакция не найдена"
+//	@Failure		403				{object}	api.ErrorResponse	"Нет доступа к бюджету"
 //
-//	@Failure		400	{object}	api.ErrorResponse	"Некорректные входные данные"
-//	@Failure		500	{object}	api.ErrorResponse	"Ошибка сервера"
-//	@Failure		400	{object}	api.ErrorResponse	"Некорректные данные"
-//	@Failure		404	{object}	api.ErrorResponse	"Транзакция не найдена"
 //	@Router			/transaction/{id} [get]
 //
 //	@Security		jwtAuth
@@ -143,7 +157,14 @@ func (th *TransactionHandlers) GetTransaction(c *gin.Context) {
 		return
 	}
 
-	transaction, err := th.g.GetTransaction(c.Request.Context(), uint(id))
+	member, ok := api.Member(c)
+	if !ok {
+		log.Error("error get member")
+		api.ResponseError(c, http.StatusInternalServerError, "error server")
+		return
+	}
+
+	transaction, err := th.g.GetTransaction(c.Request.Context(), member, uint(id))
 	if err != nil {
 		log.WithField("err", err).Error("error get transaction")
 		api.RegistrationError(c, err)
@@ -162,15 +183,18 @@ func (th *TransactionHandlers) GetTransaction(c *gin.Context) {
 //	@Tags			transaction
 //	@Accept			json
 //	@Produce		json
-//	@Param			req	body		RequestUpdateTransaction	true	"Данные для обновление пользователя"
-//	@Success		200	{object}	api.SuccessResponse			"Транзакция обновлена"
+//	@Param			req				body		RequestUpdateTransaction	true	"Данные для обновление пользователя"
+//	@Param			X-Household-ID	header		int							false	"id общего бюджета, по умолчанию личный"
+//	@Success		200				{object}	api.SuccessResponse			"Транзакция обновлена"
+//
+//	@Failure		401				{object}	api.ErrorResponse			"Ошибка авторизации"
 //
-//	@Failure		401	{object}	api.ErrorResponse			"Ошибка авторизации"
+//	@Failure		400				{object}	api.ErrorResponse			"Некорректные входные данные"
+//	@Failure		500				{object}	api.ErrorResponse			"Ошибка сервера"
+//	@Failure		400				{object}	api.ErrorResponse			"Некорректные данные"
+//	@Failure		404				{object}	api.ErrorResponse			"Транзакция не найдена"
+//	@Failure		403				{object}	api.ErrorResponse			"Нет доступа к бюджету"
 //
-//	@Failure		400	{object}	api.ErrorResponse			"Некорректные входные данные"
-//	@Failure		500	{object}	api.ErrorResponse			"Ошибка сервера"
-//	@Failure		400	{object}	api.ErrorResponse			"Некорректные данные"
-//	@Failure		404	{object}	api.ErrorResponse			"Транзакция не найдена"
 //	@Router			/transaction/ [put]
 //
 //	@Security		jwtAuth
@@ -193,7 +217,14 @@ func (th *TransactionHandlers) UpdateTransaction(c *gin.Context) {
 		Description: transaction.Description,
 	}
 
-	newTransaction, err := th.u.UpdateTransaction(c.Request.Context(), transaction.IdTransaction, tran)
+	member, ok := api.Member(c)
+	if !ok {
+		log.Error("error get member")
+		api.ResponseError(c, http.StatusInternalServerError, "error server")
+		return
+	}
+
+	newTransaction, err := th.u.UpdateTransaction(c.Request.Context(), member, transaction.IdTransaction, tran)
 	if err != nil {
 		log.WithField("err", err).Error("error update transaction")
 		api.RegistrationError(c, err)
@@ -211,15 +242,18 @@ func (th *TransactionHandlers) UpdateTransaction(c *gin.Context) {
 //	@Description	Удаление 1 транзакции для 1 пользователя
 //	@Tags			transaction
 //	@Produce		json
-//	@Param			id	path		int					true	"id транзакции"
-//	@Success		200	{object}	api.SuccessResponse	"Транзакция удалена"
+//	@Param			id				path		int					true	"id транзакции"
+//	@Param			X-Household-ID	header		int					false	"id общего бюджета, по умолчанию личный"
+//	@Success		200				{object}	api.SuccessResponse	"Транзакция удалена"
 //
-//	@Failure		401	{object}	api.ErrorResponse	"Ошибка авторизации"
+//	@Failure		401				{object}	api.ErrorResponse	"Ошибка авторизации"
+//
+//	@Failure		400				{object}	api.ErrorResponse	"Некорректные входные данные"
+//	@Failure		500				{object}	api.ErrorResponse	"Ошибка сервера"
+//	@Failure		400				{object}	api.ErrorResponse	"Некорректные данные"
+//	@Failure		404				{object}	api.ErrorResponse	"Транзакция не найдена"
+//	@Failure		403				{object}	api.ErrorResponse	"Нет доступа к бюджету"
 //
-//	@Failure		400	{object}	api.ErrorResponse	"Некорректные входные данные"
-//	@Failure		500	{object}	api.ErrorResponse	"Ошибка сервера"
-//	@Failure		400	{object}	api.ErrorResponse	"Некорректные данные"
-//	@Failure		404	{object}	api.ErrorResponse	"Транзакция не найдена"
 //	@Router			/transaction/{id} [delete]
 //
 //	@Security		jwtAuth
@@ -239,7 +273,14 @@ func (th *TransactionHandlers) DeleteTransaction(c *gin.Context) {
 		return
 	}
 
-	err = th.d.DeleteTransaction(c.Request.Context(), uint(id))
+	member, ok := api.Member(c)
+	if !ok {
+		log.Error("error get member")
+		api.ResponseError(c, http.StatusInternalServerError, "error server")
+		return
+	}
+
+	err = th.d.DeleteTransaction(c.Request.Context(), member, uint(id))
 
 	if err != nil {
 		log.WithField("err", err).Error("error delete transaction")
@@ -251,3 +292,107 @@ func (th *TransactionHandlers) DeleteTransaction(c *gin.Context) {
 
 	api.ResponseOK(c, "transaction delete")
 }
+
+// ListAnomalies godoc
+//
+//	@Summary		Подозрительные транзакции
+//	@Description	Список транзакций, сумма которых сильно отличается от истории категории (медиана / MAD)
+//	@Tags			transaction
+//	@Produce		json
+//	@Param			status			query		string				false	"статус: flagged, confirmed, dismissed"	default(flagged)
+//	@Param			X-Household-ID	header		int					false	"id общего бюджета, по умолчанию личный"
+//	@Success		200				{object}	api.SuccessResponse	"Список транзакций"
+//
+//	@Failure		401				{object}	api.ErrorResponse	"Ошибка авторизации"
+//	@Failure		400				{object}	api.ErrorResponse	"Некорректный статус"
+//	@Failure		500				{object}	api.ErrorResponse	"Ошибка сервера"
+//	@Failure		403				{object}	api.ErrorResponse	"Нет доступа к бюджету"
+//
+//	@Router			/transaction/anomalies [get]
+//
+//	@Security		jwtAuth
+func (th *TransactionHandlers) ListAnomalies(c *gin.Context) {
+	const op = "handlers.ListAnomalies"
+
+	log := th.log.WithField("op", op)
+
+	log.Info("start list anomalies")
+
+	member, ok := api.Member(c)
+	if !ok {
+		log.Error("error get member")
+		api.ResponseError(c, http.StatusInternalServerError, "error server")
+		return
+	}
+
+	anomalies, err := th.a.ListAnomalies(c.Request.Context(), member, c.Query("status"))
+	if err != nil {
+		log.WithField("err", err).Error("error list anomalies")
+		api.RegistrationError(c, err)
+		return
+	}
+
+	log.Info("success list anomalies")
+
+	api.ResponseOK(c, anomalies)
+}
+
+// ReviewAnomaly godoc
+//
+//	@Summary		Проверка подозрительной транзакции
+//	@Description	Подтверждение (confirmed) или снятие (dismissed) отметки о подозрительной транзакции
+//	@Tags			transaction
+//	@Accept			json
+//	@Produce		json
+//	@Param			id				path		int						true	"id транзакции"
+//	@Param			req				body		RequestReviewAnomaly	true	"решение пользователя"
+//	@Param			X-Household-ID	header		int						false	"id общего бюджета, по умолчанию личный"
+//	@Success		200				{object}	api.SuccessResponse		"Решение сохранено"
+//
+//	@Failure		401				{object}	api.ErrorResponse		"Ошибка авторизации"
+//	@Failure		400				{object}	api.ErrorResponse		"Некорректные входные данные"
+//	@Failure		404				{object}	api.ErrorResponse		"Транзакция не найдена"
+//	@Failure		500				{object}	api.ErrorResponse		"Ошибка сервера"
+//	@Failure		403				{object}	api.ErrorResponse		"Нет доступа к бюджету"
+//
+//	@Router			/transaction/anomalies/{id} [put]
+//
+//	@Security		jwtAuth
+func (th *TransactionHandlers) ReviewAnomaly(c *gin.Context) {
+	const op = "handlers.ReviewAnomaly"
+
+	log := th.log.WithField("op", op)
+
+	log.Info("start review anomaly")
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		log.WithField("err", err).Error("invalid convert string in int")
+		api.ResponseError(c, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	var req RequestReviewAnomaly
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.WithField("err", err).Error("error valid JSON")
+		api.ResponseError(c, http.StatusBadRequest, "error valid JSON")
+		return
+	}
+
+	member, ok := api.Member(c)
+	if !ok {
+		log.Error("error get member")
+		api.ResponseError(c, http.StatusInternalServerError, "error server")
+		return
+	}
+
+	if err := th.a.ReviewAnomaly(c.Request.Context(), member, uint(id), req.Status); err != nil {
+		log.WithField("err", err).Error("error review anomaly")
+		api.RegistrationError(c, err)
+		return
+	}
+
+	log.Info("success review anomaly")
+
+	api.ResponseOK(c, req.Status)
+}
@@ -11,19 +11,27 @@ type tranasctionServicMock struct {
 	mock.Mock
 }
 
-func (d *tranasctionServicMock) CreateTransaction(ctx context.Context, idUser uint, idCategory uint, tran domain.TransactionInput) (uint, error) {
-	args := d.Called(ctx, idUser, idCategory, tran)
+func (d *tranasctionServicMock) CreateTransaction(ctx context.Context, member domain.Member, idCategory uint, tran domain.TransactionInput) (uint, error) {
+	args := d.Called(ctx, member, idCategory, tran)
 	return args.Get(0).(uint), args.Error(1)
 }
-func (d *tranasctionServicMock) GetTransaction(ctx context.Context, idTransaction uint) (domain.TransactionOutput, error) {
-	args := d.Called(ctx, idTransaction)
+func (d *tranasctionServicMock) GetTransaction(ctx context.Context, member domain.Member, idTransaction uint) (domain.TransactionOutput, error) {
+	args := d.Called(ctx, member, idTransaction)
 	return args.Get(0).(domain.TransactionOutput), args.Error(1)
 }
-func (d *tranasctionServicMock) UpdateTransaction(ctx context.Context, idTransaction uint, newTransaction domain.TransactionInput) (domain.TransactionOutput, error) {
-	args := d.Called(ctx, idTransaction, newTransaction)
+func (d *tranasctionServicMock) UpdateTransaction(ctx context.Context, member domain.Member, idTransaction uint, newTransaction domain.TransactionInput) (domain.TransactionOutput, error) {
+	args := d.Called(ctx, member, idTransaction, newTransaction)
 	return args.Get(0).(domain.TransactionOutput), args.Error(1)
 }
-func (d *tranasctionServicMock) DeleteTransaction(ctx context.Context, idTransaction uint) error {
-	args := d.Called(ctx, idTransaction)
+func (d *tranasctionServicMock) DeleteTransaction(ctx context.Context, member domain.Member, idTransaction uint) error {
+	args := d.Called(ctx, member, idTransaction)
+	return args.Error(0)
+}
+func (d *tranasctionServicMock) ListAnomalies(ctx context.Context, member domain.Member, status string) ([]domain.TransactionAnomaly, error) {
+	args := d.Called(ctx, member, status)
+	return args.Get(0).([]domain.TransactionAnomaly), args.Error(1)
+}
+func (d *tranasctionServicMock) ReviewAnomaly(ctx context.Context, member domain.Member, idTransaction uint, status string) error {
+	args := d.Called(ctx, member, idTransaction, status)
 	return args.Error(0)
 }
@@ -17,8 +17,11 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/assert/v2"
 	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/mock"
 )
 
+var member = domain.Member{UserID: 1, HouseholdID: 3, Role: domain.HouseholdOwner}
+
 func TestCreateTransactionServic(t *testing.T) {
 	type test struct {
 		name          string
@@ -114,7 +117,8 @@ func TestCreateTransactionServic(t *testing.T) {
 			gin.SetMode(gin.TestMode)
 			w := httptest.NewRecorder()
 			c, _ := gin.CreateTestContext(w)
-			c.Set("userID", ts.idUser)
+			m := domain.Member{UserID: ts.idUser, HouseholdID: member.HouseholdID, Role: member.Role}
+			c.Set("member", m)
 
 			repoMock := new(tranasctionServicMock)
 			log := logrus.New()
@@ -124,10 +128,10 @@ func TestCreateTransactionServic(t *testing.T) {
 				Description: ts.tran.Description,
 			}
 			if ts.shouldCallDB {
-				repoMock.On("CreateTransaction", ctx, ts.idUser, ts.idCategory, tranInput).Return(ts.idTransaction, ts.mockErr)
+				repoMock.On("CreateTransaction", ctx, m, ts.idCategory, tranInput).Return(ts.idTransaction, ts.mockErr)
 			}
 
-			handler := CreateTransactionHandlers(repoMock, repoMock, repoMock, repoMock, log, ctx)
+			handler := CreateTransactionHandlers(repoMock, repoMock, repoMock, repoMock, repoMock, log, ctx)
 
 			req := http.Request{
 				Header: make(http.Header),
@@ -148,7 +152,7 @@ func TestCreateTransactionServic(t *testing.T) {
 			assert.Equal(t, ts.status, w.Code)
 
 			if ts.shouldCallDB {
-				repoMock.AssertCalled(t, "CreateTransaction", ctx, ts.idUser, ts.idCategory, tranInput)
+				repoMock.AssertCalled(t, "CreateTransaction", ctx, m, ts.idCategory, tranInput)
 			}
 		})
 	}
@@ -192,16 +196,17 @@ func TestGetTransaction(t *testing.T) {
 			gin.SetMode(gin.TestMode)
 			w := httptest.NewRecorder()
 			c, _ := gin.CreateTestContext(w)
+			c.Set("member", member)
 
 			repoMock := new(tranasctionServicMock)
 			log := logrus.New()
 			ctx := context.Background()
 
 			if !tc.invalid {
-				repoMock.On("GetTransaction", ctx, tc.req).Return(tc.output, tc.mockErr)
+				repoMock.On("GetTransaction", ctx, member, tc.req).Return(tc.output, tc.mockErr)
 			}
 
-			handler := CreateTransactionHandlers(repoMock, repoMock, repoMock, repoMock, log, ctx)
+			handler := CreateTransactionHandlers(repoMock, repoMock, repoMock, repoMock, repoMock, log, ctx)
 
 			req := http.Request{Header: make(http.Header), URL: &url.URL{}}
 
@@ -227,7 +232,7 @@ func TestGetTransaction(t *testing.T) {
 			assert.Equal(t, tc.status, w.Code)
 
 			if !tc.invalid {
-				repoMock.AssertCalled(t, "GetTransaction", ctx, tc.req)
+				repoMock.AssertCalled(t, "GetTransaction", ctx, member, tc.req)
 			}
 		})
 	}
@@ -270,6 +275,7 @@ func TestUpdateTransaction(t *testing.T) {
 			gin.SetMode(gin.TestMode)
 			w := httptest.NewRecorder()
 			c, _ := gin.CreateTestContext(w)
+			c.Set("member", member)
 
 			repoMock := new(tranasctionServicMock)
 			log := logrus.New()
@@ -277,10 +283,10 @@ func TestUpdateTransaction(t *testing.T) {
 
 			if !tc.invalid {
 				input := domain.TransactionInput{Name: tc.req.Name, Count: tc.req.Count, Description: tc.req.Description}
-				repoMock.On("UpdateTransaction", ctx, tc.req.IdTransaction, input).Return(tc.output, tc.mockErr)
+				repoMock.On("UpdateTransaction", ctx, member, tc.req.IdTransaction, input).Return(tc.output, tc.mockErr)
 			}
 
-			handler := CreateTransactionHandlers(repoMock, repoMock, repoMock, repoMock, log, ctx)
+			handler := CreateTransactionHandlers(repoMock, repoMock, repoMock, repoMock, repoMock, log, ctx)
 			req := http.Request{Header: make(http.Header), URL: &url.URL{}}
 			if tc.invalid {
 				req.Body = ioutil.NopCloser(bytes.NewBufferString("{"))
@@ -296,7 +302,7 @@ func TestUpdateTransaction(t *testing.T) {
 
 			if !tc.invalid {
 				input := domain.TransactionInput{Name: tc.req.Name, Count: tc.req.Count, Description: tc.req.Description}
-				repoMock.AssertCalled(t, "UpdateTransaction", ctx, tc.req.IdTransaction, input)
+				repoMock.AssertCalled(t, "UpdateTransaction", ctx, member, tc.req.IdTransaction, input)
 			}
 		})
 	}
@@ -339,16 +345,17 @@ func TestDeleteTransaction(t *testing.T) {
 			gin.SetMode(gin.TestMode)
 			w := httptest.NewRecorder()
 			c, _ := gin.CreateTestContext(w)
+			c.Set("member", member)
 
 			repoMock := new(tranasctionServicMock)
 			log := logrus.New()
 			ctx := context.Background()
 
 			if !tc.invalid {
-				repoMock.On("DeleteTransaction", ctx, tc.req).Return(tc.mockErr)
+				repoMock.On("DeleteTransaction", ctx, member, tc.req).Return(tc.mockErr)
 			}
 
-			handler := CreateTransactionHandlers(repoMock, repoMock, repoMock, repoMock, log, ctx)
+			handler := CreateTransactionHandlers(repoMock, repoMock, repoMock, repoMock, repoMock, log, ctx)
 			req := http.Request{Header: make(http.Header), URL: &url.URL{}}
 
 			req.Header.Set("content-type", "application/json")
@@ -370,7 +377,133 @@ func TestDeleteTransaction(t *testing.T) {
 			assert.Equal(t, tc.status, w.Code)
 
 			if !tc.invalid {
-				repoMock.AssertCalled(t, "DeleteTransaction", ctx, tc.req)
+				repoMock.AssertCalled(t, "DeleteTransaction", ctx, member, tc.req)
+			}
+		})
+	}
+}
+
+func TestListAnomalies(t *testing.T) {
+	type test struct {
+		name      string
+		status    string
+		anomalies []domain.TransactionAnomaly
+		mockErr   error
+		code      int
+	}
+
+	cases := []test{
+		{
+			name:   "success",
+			status: "",
+			anomalies: []domain.TransactionAnomaly{
+				{Reason: "count 50000 is far above the category median 300", Status: domain.AnomalyFlagged},
+			},
+			code: http.StatusOK,
+		},
+		{
+			name:      "invalid status",
+			status:    "deleted",
+			anomalies: []domain.TransactionAnomaly{},
+			mockErr:   transaction.ErrAnomalyStatus,
+			code:      http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Set("member", member)
+
+			repoMock := new(tranasctionServicMock)
+			ctx := context.Background()
+			repoMock.On("ListAnomalies", ctx, member, tc.status).Return(tc.anomalies, tc.mockErr)
+
+			handler := CreateTransactionHandlers(repoMock, repoMock, repoMock, repoMock, repoMock, logrus.New(), ctx)
+			req := http.Request{Header: make(http.Header), URL: &url.URL{RawQuery: "status=" + tc.status}}
+			c.Request = req.WithContext(ctx)
+
+			handler.ListAnomalies(c)
+
+			assert.Equal(t, tc.code, w.Code)
+			repoMock.AssertCalled(t, "ListAnomalies", ctx, member, tc.status)
+		})
+	}
+}
+
+func TestReviewAnomaly(t *testing.T) {
+	type test struct {
+		name         string
+		id           string
+		body         string
+		status       string
+		mockErr      error
+		code         int
+		shouldCallDB bool
+	}
+
+	cases := []test{
+		{
+			name:         "success",
+			id:           "4",
+			body:         `{"status":"dismissed"}`,
+			status:       domain.AnomalyDismissed,
+			code:         http.StatusOK,
+			shouldCallDB: true,
+		},
+		{
+			name:         "not found",
+			id:           "5",
+			body:         `{"status":"confirmed"}`,
+			status:       domain.AnomalyConfirmed,
+			mockErr:      transaction.ErrNoFound,
+			code:         http.StatusNotFound,
+			shouldCallDB: true,
+		},
+		{
+			name:         "invalid id",
+			id:           "abc",
+			body:         `{"status":"confirmed"}`,
+			code:         http.StatusBadRequest,
+			shouldCallDB: false,
+		},
+		{
+			name:         "invalid json",
+			id:           "4",
+			body:         `{`,
+			code:         http.StatusBadRequest,
+			shouldCallDB: false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Set("member", member)
+
+			repoMock := new(tranasctionServicMock)
+			ctx := context.Background()
+			id, _ := strconv.Atoi(tc.id)
+			repoMock.On("ReviewAnomaly", ctx, member, uint(id), tc.status).Return(tc.mockErr)
+
+			handler := CreateTransactionHandlers(repoMock, repoMock, repoMock, repoMock, repoMock, logrus.New(), ctx)
+			req := http.Request{Header: make(http.Header), URL: &url.URL{}}
+			req.Body = ioutil.NopCloser(bytes.NewBufferString(tc.body))
+			req.Header.Set("content-type", "application/json")
+			c.Request = req.WithContext(ctx)
+			c.Params = gin.Params{{Key: "id", Value: tc.id}}
+
+			handler.ReviewAnomaly(c)
+
+			assert.Equal(t, tc.code, w.Code)
+			if tc.shouldCallDB {
+				repoMock.AssertCalled(t, "ReviewAnomaly", ctx, member, uint(id), tc.status)
+			} else {
+				repoMock.AssertNotCalled(t, "ReviewAnomaly", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
 			}
 		})
 	}
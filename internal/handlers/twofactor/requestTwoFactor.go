@@ -0,0 +1,18 @@
+package twofactorHandlers
+
+// RequestCode represents two-factor code request
+type RequestCode struct {
+	Code string `json:"code" binding:"required" example:"287082"`
+}
+
+// RequestTwoFactorLogin represents two-factor login request
+type RequestTwoFactorLogin struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code" binding:"required" example:"287082"`
+}
+
+// RequestRecover represents two-factor recovery request
+type RequestRecover struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	RecoveryCode   string `json:"recovery_code" binding:"required" example:"k3j9d-x82mf"`
+}
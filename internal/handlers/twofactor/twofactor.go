@@ -0,0 +1,296 @@
+package twofactorHandlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/financial_tracer/internal/handlers/api"
+	jwttoken "github.com/financial_tracer/internal/lib/jwtToken"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type EnrollServic interface {
+	Enroll(ctx context.Context, userID uint) (domain.TwoFactorEnrollment, error)
+	Confirm(ctx context.Context, userID uint, req domain.TwoFactorCode) (domain.RecoveryCodes, error)
+}
+
+type DisableServic interface {
+	Disable(ctx context.Context, userID uint, req domain.TwoFactorCode) error
+}
+
+type LoginServic interface {
+	TwoFactorLogin(ctx context.Context, req domain.TwoFactorLogin) (jwttoken.ResponseJWTUser, error)
+	RecoverTwoFactor(ctx context.Context, req domain.TwoFactorLogin) (jwttoken.ResponseJWTUser, error)
+}
+
+type TwoFactorHandlers struct {
+	e   EnrollServic
+	d   DisableServic
+	l   LoginServic
+	log *logrus.Logger
+	ctx context.Context
+}
+
+func CreateTwoFactorHandlers(e EnrollServic,
+	d DisableServic,
+	l LoginServic,
+	log *logrus.Logger,
+	ctx context.Context) *TwoFactorHandlers {
+	return &TwoFactorHandlers{
+		e:   e,
+		d:   d,
+		l:   l,
+		log: log,
+		ctx: ctx,
+	}
+}
+
+// Enroll godoc
+//
+//	@Summary		Подключение 2FA
+//	@Description	Создание секрета TOTP и otpauth URI для приложения-аутентификатора. Двухфакторная аутентификация включается после подтверждения кодом
+//
+//	@Tags			2FA
+//
+//	@Produce		json
+//	@Success		200	{object}	api.SuccessResponse	"Секрет и otpauth URI"
+//
+//	@Failure		400	{object}	api.ErrorResponse	"2FA уже включена"
+//	@Failure		401	{object}	api.ErrorResponse	"Ошибка авторизации"
+//	@Failure		404	{object}	api.ErrorResponse	"Пользователь не найден"
+//	@Failure		500	{object}	api.ErrorResponse	"Ошибка сервера"
+//
+//	@Router			/user/2fa/enroll [post]
+//
+//	@Security		jwtAuth
+func (h *TwoFactorHandlers) Enroll(c *gin.Context) {
+	const op = "handlers.Enroll"
+
+	log := h.log.WithField("op", op)
+
+	log.Info("start enroll two-factor")
+
+	idUser, ok := c.Get("userID")
+	if !ok {
+		log.Error("error get userID")
+		api.ResponseError(c, http.StatusInternalServerError, "error server")
+		return
+	}
+
+	res, err := h.e.Enroll(c.Request.Context(), idUser.(uint))
+	if err != nil {
+		log.WithField("err", err).Error("error enroll two-factor")
+		api.RegistrationError(c, err)
+		return
+	}
+
+	log.Info("success enroll two-factor")
+
+	api.ResponseOK(c, res)
+}
+
+// Confirm godoc
+//
+//	@Summary		Подтверждение 2FA
+//	@Description	Включение двухфакторной аутентификации кодом из приложения. Возвращает коды восстановления, они показываются один раз
+//
+//	@Tags			2FA
+//
+//	@Accept			json
+//	@Produce		json
+//	@Param			req	body		RequestCode			true	"код из приложения"
+//	@Success		200	{object}	api.SuccessResponse	"Коды восстановления"
+//
+//	@Failure		400	{object}	api.ErrorResponse	"Некорректные входные данные"
+//	@Failure		401	{object}	api.ErrorResponse	"Неверный код"
+//	@Failure		500	{object}	api.ErrorResponse	"Ошибка сервера"
+//
+//	@Router			/user/2fa/confirm [post]
+//
+//	@Security		jwtAuth
+func (h *TwoFactorHandlers) Confirm(c *gin.Context) {
+	const op = "handlers.Confirm"
+
+	log := h.log.WithField("op", op)
+
+	log.Info("start confirm two-factor")
+
+	var req RequestCode
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.WithField("err", err).Error("error valid JSON")
+		api.ResponseError(c, http.StatusBadRequest, "error valid JSON")
+		return
+	}
+
+	idUser, ok := c.Get("userID")
+	if !ok {
+		log.Error("error get userID")
+		api.ResponseError(c, http.StatusInternalServerError, "error server")
+		return
+	}
+
+	res, err := h.e.Confirm(c.Request.Context(), idUser.(uint), domain.TwoFactorCode{Code: req.Code, Device: api.Device(c)})
+	if err != nil {
+		log.WithField("err", err).Error("error confirm two-factor")
+		api.RegistrationError(c, err)
+		return
+	}
+
+	log.Info("success confirm two-factor")
+
+	api.ResponseOK(c, res)
+}
+
+// Disable godoc
+//
+//	@Summary		Отключение 2FA
+//	@Description	Отключение двухфакторной аутентификации кодом из приложения или кодом восстановления
+//
+//	@Tags			2FA
+//
+//	@Accept			json
+//	@Produce		json
+//	@Param			req	body		RequestCode			true	"код из приложения или код восстановления"
+//	@Success		200	{object}	api.SuccessResponse	"2FA отключена"
+//
+//	@Failure		400	{object}	api.ErrorResponse	"2FA не включена"
+//	@Failure		401	{object}	api.ErrorResponse	"Неверный код"
+//	@Failure		500	{object}	api.ErrorResponse	"Ошибка сервера"
+//
+//	@Router			/user/2fa [delete]
+//
+//	@Security		jwtAuth
+func (h *TwoFactorHandlers) Disable(c *gin.Context) {
+	const op = "handlers.Disable"
+
+	log := h.log.WithField("op", op)
+
+	log.Info("start disable two-factor")
+
+	var req RequestCode
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.WithField("err", err).Error("error valid JSON")
+		api.ResponseError(c, http.StatusBadRequest, "error valid JSON")
+		return
+	}
+
+	idUser, ok := c.Get("userID")
+	if !ok {
+		log.Error("error get userID")
+		api.ResponseError(c, http.StatusInternalServerError, "error server")
+		return
+	}
+
+	err := h.d.Disable(c.Request.Context(), idUser.(uint), domain.TwoFactorCode{Code: req.Code, Device: api.Device(c)})
+	if err != nil {
+		log.WithField("err", err).Error("error disable two-factor")
+		api.RegistrationError(c, err)
+		return
+	}
+
+	log.Info("success disable two-factor")
+
+	api.ResponseOK(c, "two-factor authentication disabled")
+}
+
+// Login godoc
+//
+//	@Summary		Вход с 2FA
+//	@Description	Обмен challenge токена, полученного при входе, и кода из приложения на пару токенов. Challenge токен одноразовый: после неверного кода нужно войти заново
+//
+//	@Tags			registration
+//
+//	@Accept			json
+//	@Produce		json
+//	@Param			req				body		RequestTwoFactorLogin	true	"challenge токен и код"
+//	@Param			X-Session-Mode	header		string					false	"cookie: токены выдаются в HttpOnly cookie, в ответе только csrf_token"
+//	@Success		200				{object}	api.SuccessResponse		"Пара токенов"
+//
+//	@Failure		400				{object}	api.ErrorResponse		"Некорректные входные данные"
+//	@Failure		401				{object}	api.ErrorResponse		"Неверный код или challenge токен"
+//	@Failure		429				{object}	api.ErrorResponse		"Слишком много неудачных попыток, см. Retry-After"
+//	@Header			429				{string}	Retry-After				"Через сколько секунд можно повторить"
+//	@Failure		500				{object}	api.ErrorResponse		"Ошибка сервера"
+//
+//	@Router			/registration/2fa [post]
+func (h *TwoFactorHandlers) Login(c *gin.Context) {
+	const op = "handlers.TwoFactorLogin"
+
+	log := h.log.WithField("op", op)
+
+	log.Info("start two-factor login")
+
+	var req RequestTwoFactorLogin
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.WithField("err", err).Error("error valid JSON")
+		api.ResponseError(c, http.StatusBadRequest, "error valid JSON")
+		return
+	}
+
+	tokens, err := h.l.TwoFactorLogin(c.Request.Context(), domain.TwoFactorLogin{
+		ChallengeToken: req.ChallengeToken,
+		Code:           req.Code,
+		Device:         api.Device(c),
+	})
+	if err != nil {
+		log.WithField("err", err).Error("error two-factor login")
+		api.RegistrationError(c, err)
+		return
+	}
+
+	log.Info("success two-factor login")
+
+	api.ResponseTokens(c, tokens)
+}
+
+// Recover godoc
+//
+//	@Summary		Вход по коду восстановления
+//	@Description	Обмен challenge токена и кода восстановления на пару токенов. Двухфакторная аутентификация при этом отключается, ее можно подключить заново
+//
+//	@Tags			registration
+//
+//	@Accept			json
+//	@Produce		json
+//	@Param			req				body		RequestRecover		true	"challenge токен и код восстановления"
+//	@Param			X-Session-Mode	header		string				false	"cookie: токены выдаются в HttpOnly cookie, в ответе только csrf_token"
+//	@Success		200				{object}	api.SuccessResponse	"Пара токенов"
+//
+//	@Failure		400				{object}	api.ErrorResponse	"Некорректные входные данные"
+//	@Failure		401				{object}	api.ErrorResponse	"Неверный код или challenge токен"
+//	@Failure		429				{object}	api.ErrorResponse	"Слишком много неудачных попыток, см. Retry-After"
+//	@Header			429				{string}	Retry-After			"Через сколько секунд можно повторить"
+//	@Failure		500				{object}	api.ErrorResponse	"Ошибка сервера"
+//
+//	@Router			/registration/2fa/recover [post]
+func (h *TwoFactorHandlers) Recover(c *gin.Context) {
+	const op = "handlers.RecoverTwoFactor"
+
+	log := h.log.WithField("op", op)
+
+	log.Info("start recover two-factor")
+
+	var req RequestRecover
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.WithField("err", err).Error("error valid JSON")
+		api.ResponseError(c, http.StatusBadRequest, "error valid JSON")
+		return
+	}
+
+	tokens, err := h.l.RecoverTwoFactor(c.Request.Context(), domain.TwoFactorLogin{
+		ChallengeToken: req.ChallengeToken,
+		Code:           req.RecoveryCode,
+		Device:         api.Device(c),
+	})
+	if err != nil {
+		log.WithField("err", err).Error("error recover two-factor")
+		api.RegistrationError(c, err)
+		return
+	}
+
+	log.Info("success recover two-factor")
+
+	api.ResponseTokens(c, tokens)
+}
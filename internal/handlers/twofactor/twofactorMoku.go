@@ -0,0 +1,38 @@
+package twofactorHandlers
+
+import (
+	"context"
+
+	"github.com/financial_tracer/internal/domain"
+	jwttoken "github.com/financial_tracer/internal/lib/jwtToken"
+	"github.com/stretchr/testify/mock"
+)
+
+type twofactorServicMock struct {
+	mock.Mock
+}
+
+func (m *twofactorServicMock) Enroll(ctx context.Context, userID uint) (domain.TwoFactorEnrollment, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(domain.TwoFactorEnrollment), args.Error(1)
+}
+
+func (m *twofactorServicMock) Confirm(ctx context.Context, userID uint, req domain.TwoFactorCode) (domain.RecoveryCodes, error) {
+	args := m.Called(ctx, userID, req)
+	return args.Get(0).(domain.RecoveryCodes), args.Error(1)
+}
+
+func (m *twofactorServicMock) Disable(ctx context.Context, userID uint, req domain.TwoFactorCode) error {
+	args := m.Called(ctx, userID, req)
+	return args.Error(0)
+}
+
+func (m *twofactorServicMock) TwoFactorLogin(ctx context.Context, req domain.TwoFactorLogin) (jwttoken.ResponseJWTUser, error) {
+	args := m.Called(ctx, req)
+	return args.Get(0).(jwttoken.ResponseJWTUser), args.Error(1)
+}
+
+func (m *twofactorServicMock) RecoverTwoFactor(ctx context.Context, req domain.TwoFactorLogin) (jwttoken.ResponseJWTUser, error) {
+	args := m.Called(ctx, req)
+	return args.Get(0).(jwttoken.ResponseJWTUser), args.Error(1)
+}
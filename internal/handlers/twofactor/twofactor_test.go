@@ -0,0 +1,169 @@
+package twofactorHandlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/financial_tracer/internal/domain"
+	jwttoken "github.com/financial_tracer/internal/lib/jwtToken"
+	"github.com/financial_tracer/internal/servic/twofactor"
+	"github.com/financial_tracer/internal/servic/user"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/assert/v2"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestConfirm(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name         string
+		body         any
+		mockErr      error
+		status       int
+		shouldCallDB bool
+	}{
+		{name: "success", body: RequestCode{Code: "287082"}, status: http.StatusOK, shouldCallDB: true},
+		{name: "wrong code", body: RequestCode{Code: "287082"}, mockErr: twofactor.ErrCode, status: http.StatusUnauthorized, shouldCallDB: true},
+		{name: "missing code", body: RequestCode{}, status: http.StatusBadRequest, shouldCallDB: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Set("userID", uint(1))
+
+			svc := new(twofactorServicMock)
+			ctx := context.Background()
+			svc.On("Confirm", mock.Anything, uint(1), domain.TwoFactorCode{Code: "287082"}).Return(domain.RecoveryCodes{Codes: []string{"k3j9d-x82mf"}}, tc.mockErr)
+
+			h := CreateTwoFactorHandlers(svc, svc, svc, logrus.New(), ctx)
+
+			req := http.Request{Header: make(http.Header), URL: &url.URL{}}
+			b, _ := json.Marshal(tc.body)
+			req.Body = io.NopCloser(bytes.NewBuffer(b))
+			req.Header.Set("content-type", "application/json")
+			c.Request = req.WithContext(ctx)
+
+			h.Confirm(c)
+
+			assert.Equal(t, tc.status, w.Code)
+			if tc.shouldCallDB {
+				svc.AssertCalled(t, "Confirm", mock.Anything, uint(1), domain.TwoFactorCode{Code: "287082"})
+			} else {
+				svc.AssertNotCalled(t, "Confirm", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestLogin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name         string
+		body         any
+		req          domain.TwoFactorLogin
+		mockErr      error
+		status       int
+		shouldCallDB bool
+	}{
+		{
+			name:         "success",
+			body:         RequestTwoFactorLogin{ChallengeToken: "challenge", Code: "287082"},
+			req:          domain.TwoFactorLogin{ChallengeToken: "challenge", Code: "287082"},
+			status:       http.StatusOK,
+			shouldCallDB: true,
+		},
+		{
+			name:         "used challenge",
+			body:         RequestTwoFactorLogin{ChallengeToken: "challenge", Code: "287082"},
+			req:          domain.TwoFactorLogin{ChallengeToken: "challenge", Code: "287082"},
+			mockErr:      user.ErrChallenge,
+			status:       http.StatusUnauthorized,
+			shouldCallDB: true,
+		},
+		{
+			name:         "missing challenge",
+			body:         RequestTwoFactorLogin{Code: "287082"},
+			status:       http.StatusBadRequest,
+			shouldCallDB: false,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+
+			svc := new(twofactorServicMock)
+			ctx := context.Background()
+			svc.On("TwoFactorLogin", mock.Anything, mock.Anything).Return(jwttoken.ResponseJWTUser{AccessToken: "access"}, tc.mockErr)
+
+			h := CreateTwoFactorHandlers(svc, svc, svc, logrus.New(), ctx)
+
+			req := http.Request{Header: make(http.Header), URL: &url.URL{}}
+			b, _ := json.Marshal(tc.body)
+			req.Body = io.NopCloser(bytes.NewBuffer(b))
+			req.Header.Set("content-type", "application/json")
+			c.Request = req.WithContext(ctx)
+
+			h.Login(c)
+
+			assert.Equal(t, tc.status, w.Code)
+			if tc.shouldCallDB {
+				got := svc.Calls[0].Arguments.Get(1).(domain.TwoFactorLogin)
+				assert.Equal(t, tc.req.ChallengeToken, got.ChallengeToken)
+				assert.Equal(t, tc.req.Code, got.Code)
+			} else {
+				svc.AssertNotCalled(t, "TwoFactorLogin", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestRecover(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name    string
+		mockErr error
+		status  int
+	}{
+		{name: "success", status: http.StatusOK},
+		{name: "used recovery code", mockErr: twofactor.ErrRecoveryCode, status: http.StatusUnauthorized},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+
+			svc := new(twofactorServicMock)
+			ctx := context.Background()
+			svc.On("RecoverTwoFactor", mock.Anything, mock.Anything).Return(jwttoken.ResponseJWTUser{AccessToken: "access"}, tc.mockErr)
+
+			h := CreateTwoFactorHandlers(svc, svc, svc, logrus.New(), ctx)
+
+			req := http.Request{Header: make(http.Header), URL: &url.URL{}}
+			b, _ := json.Marshal(RequestRecover{ChallengeToken: "challenge", RecoveryCode: "k3j9d-x82mf"})
+			req.Body = io.NopCloser(bytes.NewBuffer(b))
+			req.Header.Set("content-type", "application/json")
+			c.Request = req.WithContext(ctx)
+
+			h.Recover(c)
+
+			assert.Equal(t, tc.status, w.Code)
+			got := svc.Calls[0].Arguments.Get(1).(domain.TwoFactorLogin)
+			assert.Equal(t, "k3j9d-x82mf", got.Code)
+		})
+	}
+}
@@ -6,9 +6,11 @@ type UserRequest struct {
 	Password string `json:"password" binding:"required" example:"securitycod123"`
 }
 
-// UserRegistration represents registration user request
+// UserRegistration represents registration user request, invite_code is required in the
+// invite registration mode
 type UserRegistration struct {
-	Name string `json:"name" binding:"required" example:"jonn"`
+	Name       string `json:"name" binding:"required" example:"jonn"`
+	InviteCode string `json:"invite_code" example:"q3Zt8LkP..."`
 	UserRequest
 }
 
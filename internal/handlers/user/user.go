@@ -3,6 +3,7 @@ package userHandlers
 import (
 	"context"
 	"net/http"
+	"strconv"
 
 	"github.com/financial_tracer/internal/domain"
 	"github.com/financial_tracer/internal/handlers/api"
@@ -20,46 +21,68 @@ type AuthenticationUserServic interface {
 }
 
 type DeleteUserServic interface {
-	DeleteUser(ctx context.Context, us domain.DeleteUser) error
+	DeleteUser(ctx context.Context, us domain.DeleteUser) (domain.AccountDeletion, error)
+}
+
+type RefreshTokensServic interface {
+	RefreshTokens(ctx context.Context, refreshToken string, device domain.Device) (jwttoken.ResponseJWTUser, error)
+}
+
+type LogoutServic interface {
+	Logout(ctx context.Context, userID uint, sessionID uint) error
+}
+
+type SessionsServic interface {
+	ListSessions(ctx context.Context, userID uint, currentSessionID uint) ([]domain.Session, error)
+	RevokeSession(ctx context.Context, userID uint, sessionID uint) error
 }
 
 type HandlersUser struct {
-	SecretKey string
-	r         RegistrationUserServic
-	a         AuthenticationUserServic
-	d         DeleteUserServic
-	log       *logrus.Logger
-	ctx       context.Context
+	r   RegistrationUserServic
+	a   AuthenticationUserServic
+	d   DeleteUserServic
+	f   RefreshTokensServic
+	o   LogoutServic
+	s   SessionsServic
+	log *logrus.Logger
+	ctx context.Context
 }
 
-func CreateHandlersUser(secretKey string, r RegistrationUserServic,
+func CreateHandlersUser(r RegistrationUserServic,
 	a AuthenticationUserServic,
 	d DeleteUserServic,
+	f RefreshTokensServic,
+	o LogoutServic,
+	s SessionsServic,
 	log *logrus.Logger,
 	ctx context.Context) *HandlersUser {
 	return &HandlersUser{
-		d:         d,
-		a:         a,
-		r:         r,
-		log:       log,
-		SecretKey: secretKey,
-		ctx:       ctx,
+		d:   d,
+		a:   a,
+		r:   r,
+		f:   f,
+		o:   o,
+		s:   s,
+		log: log,
+		ctx: ctx,
 	}
 }
 
 // RegistrationUser godoc
 //
 //	@Summary		Регистрация пользователя
-//	@Description	Создание нового пользователя
+//	@Description	Создание нового пользователя. В режиме invite нужен код приглашения, в режиме closed регистрация закрыта
 //	@Tags			registration
 //	@Accept			json
 //	@Produce		json
-//	@Param			user	body		UserRegistration	true	"Данные для регистрации пользователя"
-//	@Success		200		{object}	api.SuccessResponse	"Регистрация пользователя"
+//	@Param			user			body		UserRegistration	true	"Данные для регистрации пользователя"
+//	@Param			X-Session-Mode	header		string				false	"cookie: токены выдаются в HttpOnly cookie, в ответе только csrf_token"
+//	@Success		200				{object}	api.SuccessResponse	"Регистрация пользователя"
 //
-//	@Failure		400		{object}	api.ErrorResponse	"Некорректные входные данные"
-//	@Failure		500		{object}	api.ErrorResponse	"Ошибка сервера"
-//	@Failure		400		{object}	api.ErrorResponse	"Некорректные данные"
+//	@Failure		400				{object}	api.ErrorResponse	"Некорректные входные данные"
+//	@Failure		403				{object}	api.ErrorResponse	"Регистрация закрыта или домен email не разрешен"
+//	@Failure		500				{object}	api.ErrorResponse	"Ошибка сервера"
+//	@Failure		400				{object}	api.ErrorResponse	"Некорректные данные"
 //
 //	@Router			/registration/register [post]
 func (h *HandlersUser) Registration(c *gin.Context) {
@@ -78,9 +101,11 @@ func (h *HandlersUser) Registration(c *gin.Context) {
 	}
 
 	user := domain.RegisterUser{
-		Name:     req.Name,
-		Email:    req.Email,
-		Password: req.Password,
+		Name:       req.Name,
+		Email:      req.Email,
+		Password:   req.Password,
+		InviteCode: req.InviteCode,
+		Device:     api.Device(c),
 	}
 
 	tokens, err := h.r.RegistrationUser(h.ctx, user)
@@ -92,25 +117,29 @@ func (h *HandlersUser) Registration(c *gin.Context) {
 
 	log.Info("success registration user")
 
-	api.ResponseOK(c, tokens)
+	api.ResponseTokens(c, tokens)
 
 }
 
 // AuthenticationUser godoc
 //
 //	@Summary		Аутентификация пользователя
-//	@Description	Вход пользователя в систему
+//	@Description	Вход пользователя в систему. При включенной 2FA вместо пары токенов возвращается challenge_token для /registration/2fa
 //	@Tags			registration
 //
 //	@Accept			json
 //	@Produce		json
-//	@Param			credentials	body		UserRequest			true	"Данные для авторизации пользователя"
-//	@Success		200			{object}	api.SuccessResponse	"Авторизация пользователя"
+//	@Param			credentials		body		UserRequest			true	"Данные для авторизации пользователя"
+//	@Param			X-Session-Mode	header		string				false	"cookie: токены выдаются в HttpOnly cookie, в ответе только csrf_token"
+//	@Success		200				{object}	api.SuccessResponse	"Авторизация пользователя"
 //
-//	@Failure		400			{object}	api.ErrorResponse	"Некорректные входные данные"
-//	@Failure		500			{object}	api.ErrorResponse	"Ошибка сервера"
-//	@Failure		400			{object}	api.ErrorResponse	"Некорректные данные"
-//	@Failure		404			{object}	api.ErrorResponse	"Пользователь не найден"
+//	@Failure		400				{object}	api.ErrorResponse	"Некорректные входные данные"
+//	@Failure		500				{object}	api.ErrorResponse	"Ошибка сервера"
+//	@Failure		400				{object}	api.ErrorResponse	"Некорректные данные"
+//	@Failure		401				{object}	api.ErrorResponse	"Неверный email или пароль"
+//	@Failure		404				{object}	api.ErrorResponse	"Пользователь не найден"
+//	@Failure		429				{object}	api.ErrorResponse	"Слишком много неудачных попыток, см. Retry-After"
+//	@Header			429				{string}	Retry-After			"Через сколько секунд можно повторить"
 //
 //	@Router			/registration/login [post]
 func (h *HandlersUser) Authentication(c *gin.Context) {
@@ -131,6 +160,7 @@ func (h *HandlersUser) Authentication(c *gin.Context) {
 	user := domain.AuthenticationUser{
 		Email:    req.Email,
 		Password: req.Password,
+		Device:   api.Device(c),
 	}
 
 	tokens, err := h.a.AuthenticationUser(h.ctx, user)
@@ -143,24 +173,25 @@ func (h *HandlersUser) Authentication(c *gin.Context) {
 
 	log.Info("success authentication user")
 
-	api.ResponseOK(c, tokens)
+	api.ResponseTokens(c, tokens)
 }
 
 // DeleteUser godoc
 //
 //	@Summary		Удаление пользователя
-//	@Description	Удаление пользователя
+//	@Description	Планирование удаления пользователя: аккаунт блокируется, на email приходит письмо, после льготного периода аккаунт удаляется вместе с данными. Вход в аккаунт до этого отменяет удаление
 //
 //	@Tags			User
 //
 //	@Accept			json
 //	@Produce		json
 //	@Param			req	body		UserRequest			true	"данные для удаление пользователя"
-//	@Success		200	{object}	api.SuccessResponse	"Удаление пользователя"
+//	@Success		200	{object}	api.SuccessResponse	"Время удаления пользователя"
 //
 //	@Failure		400	{object}	api.ErrorResponse	"Некорректные входные данные"
 //	@Failure		500	{object}	api.ErrorResponse	"Ошибка сервера"
 //	@Failure		400	{object}	api.ErrorResponse	"Некорректные данные"
+//	@Failure		403	{object}	api.ErrorResponse	"Пользователь заблокирован"
 //	@Failure		404	{object}	api.ErrorResponse	"Пользователь не найден"
 //
 //	@Router			/user/ [delete]
@@ -186,7 +217,7 @@ func (h *HandlersUser) DeleteUser(c *gin.Context) {
 		Password: req.Password,
 	}
 
-	err := h.d.DeleteUser(h.ctx, users)
+	deletion, err := h.d.DeleteUser(h.ctx, users)
 	if err != nil {
 		log.WithField("err", err).Error("error delete user")
 		api.RegistrationError(c, err)
@@ -196,26 +227,29 @@ func (h *HandlersUser) DeleteUser(c *gin.Context) {
 
 	log.Info("success delete user")
 
-	api.ResponseOK(c, "user delete")
+	api.ResponseOK(c, deletion)
 }
 
 // GetAccessToken godoc
 //
-//	@Summary		Получение токена
-//	@Description	Получение токена
+//	@Summary		Обновление токенов
+//	@Description	Обмен refresh токена на новую пару токенов. Refresh токен одноразовый: повторное использование уже обменянного токена завершает сессию. В cookie режиме токен читается из cookie ft_refresh
 //
 //	@Tags			registration
 //
 //	@Accept			json
 //	@Produce		json
-//	@Param			req	body		RefreshToken		true	"для получение access токена"
-//	@Success		200	{object}	api.SuccessResponse	"Получение access токена"
-//
-//	@Failure		400	{object}	api.ErrorResponse	"Некорректные входные данные"
+//	@Param			req				body		RefreshToken		false	"refresh токен, без cookie режима"
+//	@Param			X-Session-Mode	header		string				false	"cookie: refresh токен берется из cookie, токены выдаются в cookie"
+//	@Param			X-CSRF-Token	header		string				false	"CSRF токен, обязателен в cookie режиме"
+//	@Success		200				{object}	api.SuccessResponse	"Новая пара токенов"
 //
-//	@Failure		500	{object}	api.ErrorResponse	"Ошибка сервера"
+//	@Failure		400				{object}	api.ErrorResponse	"Некорректные входные данные"
+//	@Failure		401				{object}	api.ErrorResponse	"Недействительный refresh токен"
+//	@Failure		403				{object}	api.ErrorResponse	"Неверный CSRF токен"
+//	@Failure		500				{object}	api.ErrorResponse	"Ошибка сервера"
 //
-//	@Router			/registration/get_access_token [post]
+//	@Router			/registration/access_token [post]
 func (h *HandlersUser) GetAccessToken(c *gin.Context) {
 	const op = "handlers.GetAccsessToken"
 
@@ -225,26 +259,180 @@ func (h *HandlersUser) GetAccessToken(c *gin.Context) {
 
 	var req RefreshToken
 
-	if err := c.ShouldBindJSON(&req); err != nil {
+	if api.CookieMode(c) {
+		if !api.CheckCSRF(c) {
+			log.Error("invalid CSRF token")
+			api.ResponseError(c, http.StatusForbidden, "invalid CSRF token")
+			return
+		}
+		req.RefreshToken, _ = c.Cookie(api.RefreshCookie)
+		if req.RefreshToken == "" {
+			log.Error("error get refresh cookie")
+			api.ResponseError(c, http.StatusUnauthorized, "invalid refresh token")
+			return
+		}
+	} else if err := c.ShouldBindJSON(&req); err != nil {
 		log.WithField("err", err).Error("error valid JSON")
 		api.ResponseError(c, http.StatusBadRequest, "error valid JSON")
 		return
 	}
 
-	i, name, err := jwttoken.CheckAccess(req.RefreshToken, h.SecretKey, h.log)
+	tokens, err := h.f.RefreshTokens(c.Request.Context(), req.RefreshToken, api.Device(c))
 	if err != nil {
-		log.WithField("err", err).Error("error check token")
-		api.ResponseError(c, http.StatusBadRequest, "error valid token")
+		log.WithField("err", err).Error("error refresh tokens")
+		api.RegistrationError(c, err)
 		return
 	}
 
-	tokens, err := jwttoken.JWTAccessToken(h.SecretKey, i, name)
+	log.Info("create access token")
+	api.ResponseTokens(c, tokens)
+}
+
+// Logout godoc
+//
+//	@Summary		Выход
+//	@Description	Завершение текущей сессии: refresh токен сессии перестает действовать
+//
+//	@Tags			User
+//
+//	@Produce		json
+//	@Success		200	{object}	api.SuccessResponse	"Сессия завершена"
+//
+//	@Failure		401	{object}	api.ErrorResponse	"Ошибка авторизации"
+//	@Failure		404	{object}	api.ErrorResponse	"Сессия не найдена"
+//	@Failure		500	{object}	api.ErrorResponse	"Ошибка сервера"
+//
+//	@Router			/user/logout [post]
+//
+//	@Security		jwtAuth
+func (h *HandlersUser) Logout(c *gin.Context) {
+	const op = "handlers.Logout"
+
+	log := h.log.WithField("op", op)
+
+	log.Info("start logout")
+
+	idUser, ok := c.Get("userID")
+	if !ok {
+		log.Error("error get userID")
+		api.ResponseError(c, http.StatusInternalServerError, "error server")
+		return
+	}
+
+	idSession, ok := c.Get("sessionID")
+	if !ok {
+		log.Error("error get sessionID")
+		api.ResponseError(c, http.StatusUnauthorized, "token without session")
+		return
+	}
+
+	err := h.o.Logout(c.Request.Context(), idUser.(uint), idSession.(uint))
 	if err != nil {
-		log.WithField("err", err).Error("error create tokens")
+		log.WithField("err", err).Error("error logout")
+		api.RegistrationError(c, err)
+		return
+	}
+
+	log.Info("success logout")
+
+	api.ClearSession(c)
+	api.ResponseOK(c, "logout")
+}
+
+// ListSessions godoc
+//
+//	@Summary		Активные сессии
+//	@Description	Список активных сессий пользователя: устройство (user agent), IP и время последнего использования. Текущая сессия отмечена current
+//
+//	@Tags			User
+//
+//	@Produce		json
+//	@Success		200	{object}	api.SuccessResponse	"Сессии"
+//
+//	@Failure		401	{object}	api.ErrorResponse	"Ошибка авторизации"
+//	@Failure		500	{object}	api.ErrorResponse	"Ошибка сервера"
+//
+//	@Router			/user/sessions [get]
+//
+//	@Security		jwtAuth
+func (h *HandlersUser) ListSessions(c *gin.Context) {
+	const op = "handlers.ListSessions"
+
+	log := h.log.WithField("op", op)
+
+	log.Info("start list sessions")
+
+	idUser, ok := c.Get("userID")
+	if !ok {
+		log.Error("error get userID")
 		api.ResponseError(c, http.StatusInternalServerError, "error server")
 		return
 	}
 
-	log.Info("create access token")
-	api.ResponseOK(c, tokens)
+	// tokens issued before sessions have no session id, then no session is current
+	idSession, _ := c.Get("sessionID")
+	current, _ := idSession.(uint)
+
+	sessions, err := h.s.ListSessions(c.Request.Context(), idUser.(uint), current)
+	if err != nil {
+		log.WithField("err", err).Error("error list sessions")
+		api.RegistrationError(c, err)
+		return
+	}
+
+	log.Info("success list sessions")
+
+	api.ResponseOK(c, sessions)
+}
+
+// DeleteSession godoc
+//
+//	@Summary		Завершение сессии
+//	@Description	Завершение сессии пользователя, например на потерянном устройстве. Токены сессии перестают действовать
+//
+//	@Tags			User
+//
+//	@Produce		json
+//	@Param			id	path		int					true	"id сессии"
+//	@Success		200	{object}	api.SuccessResponse	"Сессия завершена"
+//
+//	@Failure		400	{object}	api.ErrorResponse	"Некорректные входные данные"
+//	@Failure		401	{object}	api.ErrorResponse	"Ошибка авторизации"
+//	@Failure		404	{object}	api.ErrorResponse	"Сессия не найдена"
+//	@Failure		500	{object}	api.ErrorResponse	"Ошибка сервера"
+//
+//	@Router			/user/sessions/{id} [delete]
+//
+//	@Security		jwtAuth
+func (h *HandlersUser) DeleteSession(c *gin.Context) {
+	const op = "handlers.DeleteSession"
+
+	log := h.log.WithField("op", op)
+
+	log.Info("start delete session")
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		log.WithField("err", err).Error("error get id")
+		api.ResponseError(c, http.StatusBadRequest, "invalid session id")
+		return
+	}
+
+	idUser, ok := c.Get("userID")
+	if !ok {
+		log.Error("error get userID")
+		api.ResponseError(c, http.StatusInternalServerError, "error server")
+		return
+	}
+
+	err = h.s.RevokeSession(c.Request.Context(), idUser.(uint), uint(id))
+	if err != nil {
+		log.WithField("err", err).Error("error delete session")
+		api.RegistrationError(c, err)
+		return
+	}
+
+	log.Info("success delete session")
+
+	api.ResponseOK(c, "session delete")
 }
@@ -8,7 +8,7 @@ import (
 	"github.com/stretchr/testify/mock"
 )
 
-const secretKey = "secret"
+var keys, _ = jwttoken.NewHMACKeySet("", "", "test", "secret", nil)
 
 type userServiceMock struct {
 	mock.Mock
@@ -16,7 +16,7 @@ type userServiceMock struct {
 
 func (m *userServiceMock) RegistrationUser(ctx context.Context, us domain.RegisterUser) (jwttoken.ResponseJWTUser, error) {
 	args := m.Called(ctx, us)
-	response, err := jwttoken.PostJWT(secretKey, args.Get(0).(uint), us.Name)
+	response, err := keys.PostJWT(args.Get(0).(uint), us.Name, 1, "jti")
 	if err != nil {
 		return jwttoken.ResponseJWTUser{}, args.Error(1)
 	}
@@ -24,14 +24,34 @@ func (m *userServiceMock) RegistrationUser(ctx context.Context, us domain.Regist
 }
 func (m *userServiceMock) AuthenticationUser(ctx context.Context, us domain.AuthenticationUser) (jwttoken.ResponseJWTUser, error) {
 	args := m.Called(ctx, us)
-	response, err := jwttoken.PostJWT(secretKey, args.Get(0).(uint), args.Get(1).(string))
+	response, err := keys.PostJWT(args.Get(0).(uint), args.Get(1).(string), 1, "jti")
 	if err != nil {
 		return jwttoken.ResponseJWTUser{}, args.Error(2)
 	}
 	return response, args.Error(2)
 }
 
-func (m *userServiceMock) DeleteUser(ctx context.Context, us domain.DeleteUser) error {
+func (m *userServiceMock) DeleteUser(ctx context.Context, us domain.DeleteUser) (domain.AccountDeletion, error) {
 	args := m.Called(ctx, us)
+	return domain.AccountDeletion{}, args.Error(0)
+}
+
+func (m *userServiceMock) RefreshTokens(ctx context.Context, refreshToken string, device domain.Device) (jwttoken.ResponseJWTUser, error) {
+	args := m.Called(ctx, refreshToken, device)
+	return args.Get(0).(jwttoken.ResponseJWTUser), args.Error(1)
+}
+
+func (m *userServiceMock) Logout(ctx context.Context, userID uint, sessionID uint) error {
+	args := m.Called(ctx, userID, sessionID)
+	return args.Error(0)
+}
+
+func (m *userServiceMock) ListSessions(ctx context.Context, userID uint, currentSessionID uint) ([]domain.Session, error) {
+	args := m.Called(ctx, userID, currentSessionID)
+	return args.Get(0).([]domain.Session), args.Error(1)
+}
+
+func (m *userServiceMock) RevokeSession(ctx context.Context, userID uint, sessionID uint) error {
+	args := m.Called(ctx, userID, sessionID)
 	return args.Error(0)
 }
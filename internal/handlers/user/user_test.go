@@ -9,12 +9,18 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"testing"
+	"time"
 
 	"github.com/financial_tracer/internal/domain"
+	"github.com/financial_tracer/internal/handlers/api"
+	jwttoken "github.com/financial_tracer/internal/lib/jwtToken"
+	"github.com/financial_tracer/internal/servic/deletion"
+	"github.com/financial_tracer/internal/servic/lockout"
 	"github.com/financial_tracer/internal/servic/user"
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/assert/v2"
 	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestRegistration(t *testing.T) {
@@ -77,6 +83,30 @@ func TestRegistration(t *testing.T) {
 			mockErr:      user.ErrServic,
 			shouldCallDB: true,
 		},
+		{
+			name: "invite code",
+			body: UserRegistration{
+				Name:        "Bob",
+				InviteCode:  "invite",
+				UserRequest: UserRequest{Email: "bob@example.com", Password: "qwerty"},
+			},
+			user:         domain.RegisterUser{Name: "Bob", Email: "bob@example.com", Password: "qwerty", InviteCode: "invite"},
+			userID:       2,
+			userName:     "Bob",
+			status:       http.StatusOK,
+			shouldCallDB: true,
+		},
+		{
+			name: "registration closed",
+			body: UserRegistration{
+				Name:        "Bob",
+				UserRequest: UserRequest{Email: "bob@example.com", Password: "qwerty"},
+			},
+			user:         domain.RegisterUser{Name: "Bob", Email: "bob@example.com", Password: "qwerty"},
+			status:       http.StatusForbidden,
+			mockErr:      user.ErrRegistrationClosed,
+			shouldCallDB: true,
+		},
 	}
 
 	for _, tc := range tests {
@@ -90,7 +120,7 @@ func TestRegistration(t *testing.T) {
 
 			svc.On("RegistrationUser", ctx, tc.user).Return(tc.userID, tc.mockErr)
 
-			h := CreateHandlersUser("secret", svc, svc, svc, log, ctx)
+			h := CreateHandlersUser(svc, svc, svc, svc, svc, svc, log, ctx)
 
 			req := http.Request{Header: make(http.Header), URL: &url.URL{}}
 			if tc.invalidJSON {
@@ -124,6 +154,7 @@ func TestAuthentication(t *testing.T) {
 		status       int
 		invalidJSON  bool
 		mockErr      error
+		retryAfter   string
 		shouldCallDB bool
 	}{
 		{
@@ -145,6 +176,23 @@ func TestAuthentication(t *testing.T) {
 			mockErr:      user.ErrNoFound,
 			shouldCallDB: true,
 		},
+		{
+			name:         "wrong password",
+			body:         UserRequest{Email: "alice@example.com", Password: "wrong"},
+			user:         domain.AuthenticationUser{Email: "alice@example.com", Password: "wrong"},
+			status:       http.StatusUnauthorized,
+			mockErr:      user.ErrPassword,
+			shouldCallDB: true,
+		},
+		{
+			name:         "locked",
+			body:         UserRequest{Email: "alice@example.com", Password: "secret"},
+			user:         domain.AuthenticationUser{Email: "alice@example.com", Password: "secret"},
+			status:       http.StatusTooManyRequests,
+			mockErr:      &lockout.LockedError{RetryAfter: 90500 * time.Millisecond},
+			retryAfter:   "91",
+			shouldCallDB: true,
+		},
 		{
 			name:         "invalid json",
 			invalidJSON:  true,
@@ -164,7 +212,7 @@ func TestAuthentication(t *testing.T) {
 
 			svc.On("AuthenticationUser", ctx, tc.user).Return(tc.userID, tc.userName, tc.mockErr)
 
-			h := CreateHandlersUser("secret", svc, svc, svc, log, ctx)
+			h := CreateHandlersUser(svc, svc, svc, svc, svc, svc, log, ctx)
 
 			req := http.Request{Header: make(http.Header), URL: &url.URL{}}
 			if tc.invalidJSON {
@@ -178,6 +226,7 @@ func TestAuthentication(t *testing.T) {
 
 			h.Authentication(c)
 			assert.Equal(t, tc.status, w.Code)
+			assert.Equal(t, tc.retryAfter, w.Header().Get("Retry-After"))
 			if tc.shouldCallDB {
 				svc.AssertCalled(t, "AuthenticationUser", ctx, tc.user)
 			}
@@ -209,7 +258,7 @@ func TestDeleteUser(t *testing.T) {
 			name:         "not found",
 			body:         UserRequest{Email: "missing@example.com", Password: "x"},
 			user:         domain.DeleteUser{Email: "missing@example.com", Password: "x"},
-			mockErr:      user.ErrNoFound,
+			mockErr:      deletion.ErrNoFound,
 			status:       http.StatusNotFound,
 			shouldCallDB: true,
 		},
@@ -232,7 +281,7 @@ func TestDeleteUser(t *testing.T) {
 
 			svc.On("DeleteUser", ctx, tc.user).Return(tc.mockErr)
 
-			h := CreateHandlersUser("secret", svc, svc, svc, log, ctx)
+			h := CreateHandlersUser(svc, svc, svc, svc, svc, svc, log, ctx)
 
 			req := http.Request{Header: make(http.Header), URL: &url.URL{}}
 			if tc.invalidJSON {
@@ -260,7 +309,7 @@ func TestGetAccessToken(t *testing.T) {
 	ctx := context.Background()
 
 	log := logrus.New()
-	h := CreateHandlersUser("secret", nil, nil, nil, log, ctx)
+	h := CreateHandlersUser(nil, nil, nil, nil, nil, nil, log, ctx)
 
 	// invalid json
 	req := http.Request{Header: make(http.Header), URL: &url.URL{}}
@@ -271,3 +320,237 @@ func TestGetAccessToken(t *testing.T) {
 	h.GetAccessToken(c)
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
+
+func TestRefreshTokens(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name    string
+		mockErr error
+		status  int
+	}{
+		{name: "success", status: http.StatusOK},
+		{name: "invalid token", mockErr: user.ErrToken, status: http.StatusUnauthorized},
+		{name: "reused token", mockErr: user.ErrTokenReused, status: http.StatusUnauthorized},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			ctx := context.Background()
+
+			svc := new(userServiceMock)
+			svc.On("RefreshTokens", mock.Anything, "refresh", domain.Device{UserAgent: "okhttp/4.12", IP: "10.0.0.7"}).Return(jwttoken.ResponseJWTUser{AccessToken: "a", RefreshToken: "r"}, tc.mockErr)
+
+			h := CreateHandlersUser(svc, svc, svc, svc, svc, svc, logrus.New(), ctx)
+
+			b, _ := json.Marshal(RefreshToken{RefreshToken: "refresh"})
+			req := http.Request{Header: make(http.Header), URL: &url.URL{}}
+			req.Body = ioutil.NopCloser(bytes.NewBuffer(b))
+			req.Header.Set("content-type", "application/json")
+			req.Header.Set("User-Agent", "okhttp/4.12")
+			req.RemoteAddr = "10.0.0.7:51234"
+			c.Request = req.WithContext(ctx)
+
+			h.GetAccessToken(c)
+
+			assert.Equal(t, tc.status, w.Code)
+			svc.AssertCalled(t, "RefreshTokens", mock.Anything, "refresh", domain.Device{UserAgent: "okhttp/4.12", IP: "10.0.0.7"})
+		})
+	}
+}
+
+func TestRefreshTokensCookie(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name    string
+		refresh string
+		csrf    string
+		header  string
+		status  int
+	}{
+		{name: "success", refresh: "refresh", csrf: "csrf", header: "csrf", status: http.StatusOK},
+		{name: "no csrf header", refresh: "refresh", csrf: "csrf", status: http.StatusForbidden},
+		{name: "wrong csrf header", refresh: "refresh", csrf: "csrf", header: "other", status: http.StatusForbidden},
+		{name: "no refresh cookie", csrf: "csrf", header: "csrf", status: http.StatusUnauthorized},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			ctx := context.Background()
+
+			svc := new(userServiceMock)
+			svc.On("RefreshTokens", mock.Anything, "refresh", mock.Anything).Return(jwttoken.ResponseJWTUser{AccessToken: "a", RefreshToken: "r"}, nil)
+
+			h := CreateHandlersUser(svc, svc, svc, svc, svc, svc, logrus.New(), ctx)
+
+			req := httptest.NewRequest(http.MethodPost, "/financial_tracker/registration/access_token", nil)
+			req.Header.Set(api.SessionModeHeader, api.SessionModeCookie)
+			req.Header.Set(api.CSRFHeader, tc.header)
+			if tc.refresh != "" {
+				req.AddCookie(&http.Cookie{Name: api.RefreshCookie, Value: tc.refresh})
+			}
+			req.AddCookie(&http.Cookie{Name: api.CSRFCookie, Value: tc.csrf})
+			c.Request = req
+			c.Set("cookies", api.Cookies{Path: "/financial_tracker", RefreshPath: "/financial_tracker/registration", Secure: true, SameSite: http.SameSiteStrictMode})
+
+			h.GetAccessToken(c)
+
+			assert.Equal(t, tc.status, w.Code)
+			if tc.status != http.StatusOK {
+				svc.AssertNotCalled(t, "RefreshTokens", mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+
+			cookies := map[string]*http.Cookie{}
+			for _, cookie := range w.Result().Cookies() {
+				cookies[cookie.Name] = cookie
+			}
+			assert.Equal(t, "a", cookies[api.AccessCookie].Value)
+			assert.Equal(t, true, cookies[api.AccessCookie].HttpOnly)
+			assert.Equal(t, true, cookies[api.AccessCookie].Secure)
+			assert.Equal(t, "r", cookies[api.RefreshCookie].Value)
+			assert.Equal(t, "/financial_tracker/registration", cookies[api.RefreshCookie].Path)
+			assert.Equal(t, false, cookies[api.CSRFCookie].HttpOnly)
+
+			var resp struct {
+				Value api.CookieSession `json:"value"`
+			}
+			assert.Equal(t, nil, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Equal(t, cookies[api.CSRFCookie].Value, resp.Value.CSRFToken)
+			assert.Equal(t, false, bytes.Contains(w.Body.Bytes(), []byte(`"r"`)))
+		})
+	}
+}
+
+func TestLogout(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name         string
+		noSession    bool
+		mockErr      error
+		status       int
+		shouldCallDB bool
+	}{
+		{name: "success", status: http.StatusOK, shouldCallDB: true},
+		{name: "not found", mockErr: user.ErrSessionNotFound, status: http.StatusNotFound, shouldCallDB: true},
+		{name: "token without session", noSession: true, status: http.StatusUnauthorized, shouldCallDB: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Set("userID", uint(3))
+			if !tc.noSession {
+				c.Set("sessionID", uint(7))
+			}
+			ctx := context.Background()
+
+			svc := new(userServiceMock)
+			svc.On("Logout", mock.Anything, uint(3), uint(7)).Return(tc.mockErr)
+
+			h := CreateHandlersUser(svc, svc, svc, svc, svc, svc, logrus.New(), ctx)
+
+			req := http.Request{Header: make(http.Header), URL: &url.URL{}}
+			c.Request = req.WithContext(ctx)
+
+			h.Logout(c)
+
+			assert.Equal(t, tc.status, w.Code)
+			if tc.shouldCallDB {
+				svc.AssertCalled(t, "Logout", mock.Anything, uint(3), uint(7))
+			} else {
+				svc.AssertNotCalled(t, "Logout", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestListSessions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name    string
+		current uint
+		mockErr error
+		status  int
+	}{
+		{name: "success", current: 7, status: http.StatusOK},
+		{name: "token without session", status: http.StatusOK},
+		{name: "error database", current: 7, mockErr: user.ErrDatabase, status: http.StatusInternalServerError},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Set("userID", uint(3))
+			if tc.current != 0 {
+				c.Set("sessionID", tc.current)
+			}
+			ctx := context.Background()
+
+			svc := new(userServiceMock)
+			svc.On("ListSessions", mock.Anything, uint(3), tc.current).Return([]domain.Session{{ID: 7, Current: tc.current == 7}}, tc.mockErr)
+
+			h := CreateHandlersUser(svc, svc, svc, svc, svc, svc, logrus.New(), ctx)
+
+			req := http.Request{Header: make(http.Header), URL: &url.URL{}}
+			c.Request = req.WithContext(ctx)
+
+			h.ListSessions(c)
+
+			assert.Equal(t, tc.status, w.Code)
+			svc.AssertCalled(t, "ListSessions", mock.Anything, uint(3), tc.current)
+		})
+	}
+}
+
+func TestDeleteSession(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name         string
+		id           string
+		mockErr      error
+		status       int
+		shouldCallDB bool
+	}{
+		{name: "success", id: "9", status: http.StatusOK, shouldCallDB: true},
+		{name: "not found", id: "9", mockErr: user.ErrSessionNotFound, status: http.StatusNotFound, shouldCallDB: true},
+		{name: "invalid id", id: "phone", status: http.StatusBadRequest, shouldCallDB: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Set("userID", uint(3))
+			c.Params = gin.Params{{Key: "id", Value: tc.id}}
+			ctx := context.Background()
+
+			svc := new(userServiceMock)
+			svc.On("RevokeSession", mock.Anything, uint(3), uint(9)).Return(tc.mockErr)
+
+			h := CreateHandlersUser(svc, svc, svc, svc, svc, svc, logrus.New(), ctx)
+
+			req := http.Request{Header: make(http.Header), URL: &url.URL{}}
+			c.Request = req.WithContext(ctx)
+
+			h.DeleteSession(c)
+
+			assert.Equal(t, tc.status, w.Code)
+			if tc.shouldCallDB {
+				svc.AssertCalled(t, "RevokeSession", mock.Anything, uint(3), uint(9))
+			} else {
+				svc.AssertNotCalled(t, "RevokeSession", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
@@ -0,0 +1,6 @@
+package verificationHandlers
+
+// RequestVerifyEmail represents verify email request
+type RequestVerifyEmail struct {
+	Token string `json:"token" binding:"required" example:"Zx9kQ2LmVbT0yA4sWc1NrE7uHj3PfG6dKo8iB5qXtYw"`
+}
@@ -0,0 +1,124 @@
+package verificationHandlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/financial_tracer/internal/handlers/api"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type SendVerificationServic interface {
+	SendVerification(ctx context.Context, userID uint) error
+}
+
+type VerifyEmailServic interface {
+	VerifyEmail(ctx context.Context, req domain.VerifyEmail) error
+}
+
+type VerificationHandlers struct {
+	s   SendVerificationServic
+	v   VerifyEmailServic
+	log *logrus.Logger
+	ctx context.Context
+}
+
+func CreateVerificationHandlers(s SendVerificationServic,
+	v VerifyEmailServic,
+	log *logrus.Logger,
+	ctx context.Context) *VerificationHandlers {
+	return &VerificationHandlers{
+		s:   s,
+		v:   v,
+		log: log,
+		ctx: ctx,
+	}
+}
+
+// VerifyEmail godoc
+//
+//	@Summary		Подтверждение email
+//	@Description	Подтверждение email по токену из письма, отправленного при регистрации. Токен ограничен по времени
+//
+//	@Tags			registration
+//
+//	@Accept			json
+//	@Produce		json
+//	@Param			req	body		RequestVerifyEmail	true	"токен из письма"
+//	@Success		200	{object}	api.SuccessResponse	"Email подтвержден"
+//
+//	@Failure		400	{object}	api.ErrorResponse	"Некорректный или просроченный токен"
+//	@Failure		500	{object}	api.ErrorResponse	"Ошибка сервера"
+//
+//	@Router			/registration/verify [post]
+func (h *VerificationHandlers) VerifyEmail(c *gin.Context) {
+	const op = "handlers.VerifyEmail"
+
+	log := h.log.WithField("op", op)
+
+	log.Info("start verify email")
+
+	var req RequestVerifyEmail
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.WithField("err", err).Error("error valid JSON")
+		api.ResponseError(c, http.StatusBadRequest, "error valid JSON")
+		return
+	}
+
+	err := h.v.VerifyEmail(c.Request.Context(), domain.VerifyEmail{Token: req.Token})
+	if err != nil {
+		log.WithField("err", err).Error("error verify email")
+		api.RegistrationError(c, err)
+		return
+	}
+
+	log.Info("success verify email")
+
+	api.ResponseOK(c, "email verified")
+}
+
+// ResendVerification godoc
+//
+//	@Summary		Повторное письмо подтверждения
+//	@Description	Отправка нового письма для подтверждения email. Доступно пользователю с неподтвержденным email
+//
+//	@Tags			User
+//
+//	@Produce		json
+//	@Success		200	{object}	api.SuccessResponse	"Письмо отправлено"
+//
+//	@Failure		400	{object}	api.ErrorResponse	"Email уже подтвержден"
+//	@Failure		401	{object}	api.ErrorResponse	"Ошибка авторизации"
+//	@Failure		404	{object}	api.ErrorResponse	"Пользователь не найден"
+//	@Failure		500	{object}	api.ErrorResponse	"Ошибка сервера"
+//
+//	@Router			/user/verify/resend [post]
+//
+//	@Security		jwtAuth
+func (h *VerificationHandlers) ResendVerification(c *gin.Context) {
+	const op = "handlers.ResendVerification"
+
+	log := h.log.WithField("op", op)
+
+	log.Info("start resend verification")
+
+	idUser, ok := c.Get("userID")
+	if !ok {
+		log.Error("error get userID")
+		api.ResponseError(c, http.StatusInternalServerError, "error server")
+		return
+	}
+
+	err := h.s.SendVerification(c.Request.Context(), idUser.(uint))
+	if err != nil {
+		log.WithField("err", err).Error("error resend verification")
+		api.RegistrationError(c, err)
+		return
+	}
+
+	log.Info("success resend verification")
+
+	api.ResponseOK(c, "verification mail sent")
+}
@@ -0,0 +1,22 @@
+package verificationHandlers
+
+import (
+	"context"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type verificationServicMock struct {
+	mock.Mock
+}
+
+func (m *verificationServicMock) SendVerification(ctx context.Context, userID uint) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *verificationServicMock) VerifyEmail(ctx context.Context, req domain.VerifyEmail) error {
+	args := m.Called(ctx, req)
+	return args.Error(0)
+}
@@ -0,0 +1,117 @@
+package verificationHandlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/financial_tracer/internal/servic/verification"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/assert/v2"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestVerifyEmail(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name         string
+		body         any
+		req          domain.VerifyEmail
+		mockErr      error
+		status       int
+		shouldCallDB bool
+	}{
+		{
+			name:         "success",
+			body:         RequestVerifyEmail{Token: "token"},
+			req:          domain.VerifyEmail{Token: "token"},
+			status:       http.StatusOK,
+			shouldCallDB: true,
+		},
+		{
+			name:         "expired token",
+			body:         RequestVerifyEmail{Token: "token"},
+			req:          domain.VerifyEmail{Token: "token"},
+			mockErr:      verification.ErrVerifyToken,
+			status:       http.StatusBadRequest,
+			shouldCallDB: true,
+		},
+		{
+			name:         "missing token",
+			body:         RequestVerifyEmail{},
+			status:       http.StatusBadRequest,
+			shouldCallDB: false,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+
+			svc := new(verificationServicMock)
+			ctx := context.Background()
+			svc.On("VerifyEmail", mock.Anything, tc.req).Return(tc.mockErr)
+
+			h := CreateVerificationHandlers(svc, svc, logrus.New(), ctx)
+
+			b, _ := json.Marshal(tc.body)
+			req := http.Request{Header: make(http.Header), URL: &url.URL{}, Body: io.NopCloser(bytes.NewBuffer(b))}
+			req.Header.Set("content-type", "application/json")
+			c.Request = req.WithContext(ctx)
+
+			h.VerifyEmail(c)
+
+			assert.Equal(t, tc.status, w.Code)
+			if tc.shouldCallDB {
+				svc.AssertCalled(t, "VerifyEmail", mock.Anything, tc.req)
+			} else {
+				svc.AssertNotCalled(t, "VerifyEmail", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestResendVerification(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name    string
+		mockErr error
+		status  int
+	}{
+		{name: "success", status: http.StatusOK},
+		{name: "already verified", mockErr: verification.ErrVerified, status: http.StatusBadRequest},
+		{name: "error mail", mockErr: verification.ErrMail, status: http.StatusInternalServerError},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Set("userID", uint(1))
+
+			svc := new(verificationServicMock)
+			ctx := context.Background()
+			svc.On("SendVerification", mock.Anything, uint(1)).Return(tc.mockErr)
+
+			h := CreateVerificationHandlers(svc, svc, logrus.New(), ctx)
+
+			req := http.Request{Header: make(http.Header), URL: &url.URL{}}
+			c.Request = req.WithContext(ctx)
+
+			h.ResendVerification(c)
+
+			assert.Equal(t, tc.status, w.Code)
+			svc.AssertCalled(t, "SendVerification", mock.Anything, uint(1))
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package cash
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Failed login attempts are counted in "attempts:<key>", the lock lives in "lock:<key>";
+// both expire by themselves.
+
+func (r *RealRedis) Fail(ctx context.Context, key string, window time.Duration) (int64, error) {
+	pipe := r.r.TxPipeline()
+	incr := pipe.Incr(ctx, "attempts:"+key)
+	pipe.Expire(ctx, "attempts:"+key, window)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+
+	return incr.Val(), nil
+}
+
+func (r *RealRedis) Lock(ctx context.Context, key string, ttl time.Duration) error {
+	return r.r.Set(ctx, "lock:"+key, 1, ttl).Err()
+}
+
+func (r *RealRedis) Locked(ctx context.Context, key string) (time.Duration, error) {
+	ttl, err := r.r.PTTL(ctx, "lock:"+key).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	// negative values mean no key or no expiry
+	if ttl < 0 {
+		return 0, nil
+	}
+
+	return ttl, nil
+}
+
+func (r *RealRedis) Reset(ctx context.Context, key string) error {
+	return r.r.Del(ctx, "attempts:"+key, "lock:"+key).Err()
+}
@@ -29,6 +29,7 @@ func (r *RealRedis) HsetCategory(ctx context.Context, id uint, category domain.C
 	strId := strconv.FormatUint(uint64(id), 10)
 	return r.r.HSet(ctx, "category:"+strId,
 		"userID", category.UserID,
+		"householdID", category.HouseholdID,
 		"name", category.Name,
 		"type", category.Type,
 		"description", category.Description,
@@ -57,6 +58,7 @@ func (r *RealRedis) HsetTransaction(ctx context.Context, id uint, transaction do
 		"description", transaction.Description,
 		"count", transaction.Count,
 		"userID", transaction.UserID,
+		"householdID", transaction.HouseholdID,
 		"categoryID", transaction.CategoryID).Err()
 }
 
@@ -74,3 +76,19 @@ func (r *RealRedis) HdelTransaction(ctx context.Context, id uint) error {
 	strId := strconv.FormatUint(uint64(id), 10)
 	return r.r.Del(ctx, strId).Err()
 }
+
+// DelUser removes the cached categories and transactions of a purged user.
+func (r *RealRedis) DelUser(ctx context.Context, categoryIDs []uint, transactionIDs []uint) error {
+	keys := make([]string, 0, len(categoryIDs)+len(transactionIDs))
+	for _, id := range categoryIDs {
+		keys = append(keys, "category:"+strconv.FormatUint(uint64(id), 10))
+	}
+	for _, id := range transactionIDs {
+		keys = append(keys, "transaction:"+strconv.FormatUint(uint64(id), 10))
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+
+	return r.r.Del(ctx, keys...).Err()
+}
@@ -0,0 +1,181 @@
+package cash
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// sweepInterval is how often MemoryAttempts drops expired entries.
+const sweepInterval = time.Minute
+
+type attempt struct {
+	count   int64
+	expires time.Time
+}
+
+// MemoryAttempts keeps failed login attempts in the process memory, it is the fallback
+// when Redis is unavailable and counts the attempts of this instance only.
+type MemoryAttempts struct {
+	mu        sync.Mutex
+	attempts  map[string]attempt
+	locks     map[string]time.Time
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+func CreateMemoryAttempts(now func() time.Time) *MemoryAttempts {
+	return &MemoryAttempts{
+		attempts: map[string]attempt{},
+		locks:    map[string]time.Time{},
+		now:      now,
+	}
+}
+
+func (m *MemoryAttempts) Fail(ctx context.Context, key string, window time.Duration) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	m.sweep(now)
+
+	a := m.attempts[key]
+	if !now.Before(a.expires) {
+		a = attempt{}
+	}
+	a.count++
+	a.expires = now.Add(window)
+	m.attempts[key] = a
+
+	return a.count, nil
+}
+
+func (m *MemoryAttempts) Lock(ctx context.Context, key string, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.locks[key] = m.now().Add(ttl)
+	return nil
+}
+
+func (m *MemoryAttempts) Locked(ctx context.Context, key string) (time.Duration, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	until, ok := m.locks[key]
+	if !ok {
+		return 0, nil
+	}
+
+	ttl := until.Sub(m.now())
+	if ttl <= 0 {
+		delete(m.locks, key)
+		return 0, nil
+	}
+
+	return ttl, nil
+}
+
+func (m *MemoryAttempts) Reset(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.attempts, key)
+	delete(m.locks, key)
+	return nil
+}
+
+func (m *MemoryAttempts) sweep(now time.Time) {
+	if now.Sub(m.lastSweep) < sweepInterval {
+		return
+	}
+	m.lastSweep = now
+
+	for key, a := range m.attempts {
+		if !now.Before(a.expires) {
+			delete(m.attempts, key)
+		}
+	}
+	for key, until := range m.locks {
+		if !now.Before(until) {
+			delete(m.locks, key)
+		}
+	}
+}
+
+type attemptStore interface {
+	Fail(ctx context.Context, key string, window time.Duration) (int64, error)
+	Lock(ctx context.Context, key string, ttl time.Duration) error
+	Locked(ctx context.Context, key string) (time.Duration, error)
+	Reset(ctx context.Context, key string) error
+}
+
+// FallbackAttempts uses primary (Redis) and switches to fallback (memory) for the calls
+// primary fails, so the login stays protected while Redis is down.
+type FallbackAttempts struct {
+	primary  attemptStore
+	fallback attemptStore
+	log      *logrus.Logger
+}
+
+func CreateFallbackAttempts(primary attemptStore, fallback attemptStore, log *logrus.Logger) *FallbackAttempts {
+	return &FallbackAttempts{
+		primary:  primary,
+		fallback: fallback,
+		log:      log,
+	}
+}
+
+func (f *FallbackAttempts) Fail(ctx context.Context, key string, window time.Duration) (int64, error) {
+	n, err := f.primary.Fail(ctx, key, window)
+	if err != nil {
+		f.warn("Fail", err)
+		return f.fallback.Fail(ctx, key, window)
+	}
+	return n, nil
+}
+
+func (f *FallbackAttempts) Lock(ctx context.Context, key string, ttl time.Duration) error {
+	if err := f.primary.Lock(ctx, key, ttl); err != nil {
+		f.warn("Lock", err)
+		return f.fallback.Lock(ctx, key, ttl)
+	}
+	return nil
+}
+
+// Locked reports the longer lock of the two stores, a lock set while Redis was down
+// stays in force after it is back.
+func (f *FallbackAttempts) Locked(ctx context.Context, key string) (time.Duration, error) {
+	local, err := f.fallback.Locked(ctx, key)
+	if err != nil {
+		return 0, err
+	}
+
+	ttl, err := f.primary.Locked(ctx, key)
+	if err != nil {
+		f.warn("Locked", err)
+		return local, nil
+	}
+
+	return max(ttl, local), nil
+}
+
+func (f *FallbackAttempts) Reset(ctx context.Context, key string) error {
+	if err := f.fallback.Reset(ctx, key); err != nil {
+		return err
+	}
+
+	if err := f.primary.Reset(ctx, key); err != nil {
+		f.warn("Reset", err)
+	}
+	return nil
+}
+
+func (f *FallbackAttempts) warn(method string, err error) {
+	f.log.WithFields(logrus.Fields{
+		"op":  "cash.FallbackAttempts." + method,
+		"err": err,
+	}).Warn("redis unavailable, using memory")
+}
@@ -0,0 +1,99 @@
+package cash
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+type clock struct {
+	t time.Time
+}
+
+func (c *clock) now() time.Time {
+	return c.t
+}
+
+func TestMemoryAttempts(t *testing.T) {
+	ctx := context.Background()
+	c := &clock{t: time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)}
+	m := CreateMemoryAttempts(c.now)
+
+	n, _ := m.Fail(ctx, "email:jonn", time.Hour)
+	assert.Equal(t, int64(1), n)
+	n, _ = m.Fail(ctx, "email:jonn", time.Hour)
+	assert.Equal(t, int64(2), n)
+
+	c.t = c.t.Add(2 * time.Hour)
+	n, _ = m.Fail(ctx, "email:jonn", time.Hour)
+	assert.Equal(t, int64(1), n, "failures expire after the window")
+
+	assert.NoError(t, m.Lock(ctx, "email:jonn", time.Minute))
+	ttl, _ := m.Locked(ctx, "email:jonn")
+	assert.Equal(t, time.Minute, ttl)
+
+	c.t = c.t.Add(30 * time.Second)
+	ttl, _ = m.Locked(ctx, "email:jonn")
+	assert.Equal(t, 30*time.Second, ttl)
+
+	c.t = c.t.Add(time.Minute)
+	ttl, _ = m.Locked(ctx, "email:jonn")
+	assert.Zero(t, ttl)
+
+	assert.NoError(t, m.Lock(ctx, "email:jonn", time.Minute))
+	assert.NoError(t, m.Reset(ctx, "email:jonn"))
+	ttl, _ = m.Locked(ctx, "email:jonn")
+	assert.Zero(t, ttl)
+	n, _ = m.Fail(ctx, "email:jonn", time.Hour)
+	assert.Equal(t, int64(1), n)
+}
+
+type downStore struct{}
+
+var errDown = errors.New("dial tcp: connection refused")
+
+func (downStore) Fail(ctx context.Context, key string, window time.Duration) (int64, error) {
+	return 0, errDown
+}
+
+func (downStore) Lock(ctx context.Context, key string, ttl time.Duration) error {
+	return errDown
+}
+
+func (downStore) Locked(ctx context.Context, key string) (time.Duration, error) {
+	return 0, errDown
+}
+
+func (downStore) Reset(ctx context.Context, key string) error {
+	return errDown
+}
+
+func TestFallbackAttempts(t *testing.T) {
+	ctx := context.Background()
+	c := &clock{t: time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)}
+	memory := CreateMemoryAttempts(c.now)
+	f := CreateFallbackAttempts(downStore{}, memory, logrus.New())
+
+	n, err := f.Fail(ctx, "ip:10.0.0.1", time.Hour)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+
+	assert.NoError(t, f.Lock(ctx, "ip:10.0.0.1", time.Minute))
+	ttl, err := f.Locked(ctx, "ip:10.0.0.1")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Minute, ttl)
+
+	// a lock set while redis was down stays when it is back
+	up := CreateFallbackAttempts(CreateMemoryAttempts(c.now), memory, logrus.New())
+	ttl, err = up.Locked(ctx, "ip:10.0.0.1")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Minute, ttl)
+
+	assert.NoError(t, f.Reset(ctx, "ip:10.0.0.1"))
+	ttl, _ = f.Locked(ctx, "ip:10.0.0.1")
+	assert.Zero(t, ttl)
+}
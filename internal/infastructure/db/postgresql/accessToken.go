@@ -0,0 +1,110 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/financial_tracer/internal/domain"
+	"gorm.io/gorm"
+)
+
+// lastUsedStep throttles the writes of the last use of a token.
+const lastUsedStep = time.Minute
+
+func (d *Db) CreateAccessToken(ctx context.Context, token domain.AccessToken, tokenHash string) (domain.AccessToken, error) {
+	value := AccessToken{
+		UserID:    token.UserID,
+		Name:      token.Name,
+		Prefix:    token.Prefix,
+		TokenHash: tokenHash,
+		Scopes:    strings.Join(token.Scopes, ","),
+		ExpiresAt: token.ExpiresAt,
+	}
+
+	result := d.DB.WithContext(ctx).Create(&value)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrDuplicatedKey) {
+			return domain.AccessToken{}, ErrorDuplicated
+		}
+		if errors.Is(result.Error, gorm.ErrForeignKeyViolated) {
+			return domain.AccessToken{}, ErrorNotFound
+		}
+		return domain.AccessToken{}, result.Error
+	}
+
+	return accessToken(value), nil
+}
+
+func (d *Db) AccessTokens(ctx context.Context, userID uint) ([]domain.AccessToken, error) {
+	var tokens []AccessToken
+
+	result := d.DB.WithContext(ctx).Where("user_id = ?", userID).Order("id DESC").Find(&tokens)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	arr := make([]domain.AccessToken, 0, len(tokens))
+	for _, value := range tokens {
+		arr = append(arr, accessToken(value))
+	}
+
+	return arr, nil
+}
+
+func (d *Db) RevokeAccessToken(ctx context.Context, userID uint, id uint) error {
+	result := d.DB.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).Delete(&AccessToken{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrorNotFound
+	}
+
+	return nil
+}
+
+// AuthenticateAccessToken rejects the tokens of disabled users and users waiting for deletion.
+func (d *Db) AuthenticateAccessToken(ctx context.Context, tokenHash string, now time.Time) (domain.AccessToken, error) {
+	var token AccessToken
+
+	result := d.DB.WithContext(ctx).
+		Joins("JOIN users ON users.id = access_tokens.user_id AND users.deleted_at IS NULL AND users.disabled_at IS NULL AND users.deletion_at IS NULL").
+		Where("access_tokens.token_hash = ? AND (access_tokens.expires_at IS NULL OR access_tokens.expires_at > ?)", tokenHash, now).
+		First(&token)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return domain.AccessToken{}, ErrorNotFound
+		}
+		return domain.AccessToken{}, result.Error
+	}
+
+	if token.LastUsedAt == nil || token.LastUsedAt.Before(now.Add(-lastUsedStep)) {
+		result = d.DB.WithContext(ctx).Model(&AccessToken{}).Where("id = ?", token.ID).Update("last_used_at", now)
+		if result.Error != nil {
+			return domain.AccessToken{}, result.Error
+		}
+		token.LastUsedAt = &now
+	}
+
+	return accessToken(token), nil
+}
+
+func accessToken(value AccessToken) domain.AccessToken {
+	var scopes []string
+	if value.Scopes != "" {
+		scopes = strings.Split(value.Scopes, ",")
+	}
+
+	return domain.AccessToken{
+		ID:         value.ID,
+		Name:       value.Name,
+		Prefix:     value.Prefix,
+		Scopes:     scopes,
+		UserID:     value.UserID,
+		CreatedAt:  value.CreatedAt,
+		ExpiresAt:  value.ExpiresAt,
+		LastUsedAt: value.LastUsedAt,
+	}
+}
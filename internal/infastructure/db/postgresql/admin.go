@@ -0,0 +1,196 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/financial_tracer/internal/domain"
+	"gorm.io/gorm"
+)
+
+func (d *Db) AdminUsers(ctx context.Context, filter domain.AdminUserFilter) (domain.AdminUserList, error) {
+	query := d.DB.WithContext(ctx).Model(&User{})
+
+	if filter.Query != "" {
+		like := "%" + escapeLike(strings.ToLower(filter.Query)) + "%"
+		query = query.Where("LOWER(name) LIKE ? OR LOWER(email) LIKE ?", like, like)
+	}
+	if filter.Role != "" {
+		query = query.Where("role = ?", filter.Role)
+	}
+	if filter.Disabled != nil {
+		if *filter.Disabled {
+			query = query.Where("disabled_at IS NOT NULL")
+		} else {
+			query = query.Where("disabled_at IS NULL")
+		}
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return domain.AdminUserList{}, err
+	}
+
+	var users []User
+	result := query.Order("id DESC").Limit(filter.Limit).Offset(filter.Offset).Find(&users)
+	if result.Error != nil {
+		return domain.AdminUserList{}, result.Error
+	}
+
+	list := domain.AdminUserList{
+		Users: make([]domain.AdminUser, 0, len(users)),
+		Total: total,
+	}
+	for _, value := range users {
+		list.Users = append(list.Users, adminUser(value))
+	}
+
+	return list, nil
+}
+
+func (d *Db) AdminUser(ctx context.Context, userID uint) (domain.AdminUser, error) {
+	var user User
+
+	result := d.DB.WithContext(ctx).Where("id = ?", userID).First(&user)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return domain.AdminUser{}, ErrorNotFound
+		}
+		return domain.AdminUser{}, result.Error
+	}
+
+	var counts domain.UserCounts
+	db := d.DB.WithContext(ctx)
+	for _, count := range []struct {
+		model any
+		where string
+		args  []any
+		value *int64
+	}{
+		{&Category{}, "user_id = ?", []any{userID}, &counts.Categories},
+		{&Transaction{}, "user_id = ?", []any{userID}, &counts.Transactions},
+		{&Session{}, "user_id = ? AND revoked_at IS NULL AND expires_at > ?", []any{userID, time.Now()}, &counts.Sessions},
+		{&AccessToken{}, "user_id = ?", []any{userID}, &counts.AccessTokens},
+		{&DataExport{}, "user_id = ?", []any{userID}, &counts.DataExports},
+	} {
+		if err := db.Model(count.model).Where(count.where, count.args...).Count(count.value).Error; err != nil {
+			return domain.AdminUser{}, err
+		}
+	}
+
+	value := adminUser(user)
+	value.Counts = &counts
+
+	return value, nil
+}
+
+// SetUserDisabled also revokes the sessions of a disabled user.
+func (d *Db) SetUserDisabled(ctx context.Context, userID uint, disabled bool) error {
+	return d.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+
+		var disabledAt *time.Time
+		if disabled {
+			disabledAt = &now
+		}
+
+		result := tx.Model(&User{}).Where("id = ?", userID).Update("disabled_at", disabledAt)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrorNotFound
+		}
+
+		if !disabled {
+			return nil
+		}
+
+		return tx.Model(&Session{}).
+			Where("user_id = ? AND revoked_at IS NULL", userID).
+			Update("revoked_at", now).Error
+	})
+}
+
+func (d *Db) RevokeUserSessions(ctx context.Context, userID uint) (int64, error) {
+	var user User
+	result := d.DB.WithContext(ctx).Select("id").Where("id = ?", userID).First(&user)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return 0, ErrorNotFound
+		}
+		return 0, result.Error
+	}
+
+	result = d.DB.WithContext(ctx).Model(&Session{}).
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return result.RowsAffected, nil
+}
+
+func (d *Db) SetUserRole(ctx context.Context, userID uint, role string) error {
+	result := d.DB.WithContext(ctx).Model(&User{}).Where("id = ?", userID).Update("role", role)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrorNotFound
+	}
+
+	return nil
+}
+
+func (d *Db) UserRole(ctx context.Context, userID uint) (string, error) {
+	var user User
+
+	result := d.DB.WithContext(ctx).Select("id", "role").Where("id = ?", userID).First(&user)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return "", ErrorNotFound
+		}
+		return "", result.Error
+	}
+
+	return user.Role, nil
+}
+
+func (d *Db) PromoteAdmins(ctx context.Context, emails []string) (int64, error) {
+	if len(emails) == 0 {
+		return 0, nil
+	}
+
+	result := d.DB.WithContext(ctx).Model(&User{}).
+		Where("email IN ? AND role <> ?", emails, domain.RoleAdmin).
+		Update("role", domain.RoleAdmin)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return result.RowsAffected, nil
+}
+
+func adminUser(value User) domain.AdminUser {
+	return domain.AdminUser{
+		ID:         value.ID,
+		Name:       value.Name,
+		Email:      value.Email,
+		Role:       value.Role,
+		Verified:   value.VerifiedAt != nil,
+		TwoFactor:  value.TOTPEnabledAt != nil,
+		CreatedAt:  value.CreatedAt,
+		DisabledAt: value.DisabledAt,
+	}
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// escapeLike makes the wildcards of s match themselves in a LIKE pattern.
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
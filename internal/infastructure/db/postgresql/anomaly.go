@@ -0,0 +1,92 @@
+package postgresql
+
+import (
+	"context"
+
+	"github.com/financial_tracer/internal/domain"
+)
+
+// CategoryCounts leaves the anomalies out, so they do not shift the baseline.
+func (d *Db) CategoryCounts(ctx context.Context, m domain.Member, idCategory uint, limit int) ([]int, error) {
+	var counts []int
+
+	result := d.DB.WithContext(ctx).Model(&Transaction{}).
+		Scopes(memberOf("transactions", m, false)).
+		Where("category_id = ? AND COALESCE(anomaly_status, '') NOT IN ?", idCategory,
+			[]string{domain.AnomalyFlagged, domain.AnomalyConfirmed}).
+		Order("id desc").
+		Limit(limit).
+		Pluck("count", &counts)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return counts, nil
+}
+
+func (d *Db) CreateFlaggedTransaction(ctx context.Context, m domain.Member, idCategory uint, tran domain.TransactionInput, reason string) (uint, error) {
+	return d.createTransaction(ctx, m, Transaction{
+		UserID:        m.UserID,
+		HouseholdID:   m.HouseholdID,
+		CategoryID:    idCategory,
+		Name:          tran.Name,
+		Count:         tran.Count,
+		Description:   tran.Description,
+		AnomalyStatus: domain.AnomalyFlagged,
+		AnomalyReason: reason,
+	})
+}
+
+func (d *Db) TransactionAnomalies(ctx context.Context, m domain.Member, status string) ([]domain.TransactionAnomaly, error) {
+	if err := checkMember(d.DB.WithContext(ctx), m, false); err != nil {
+		return nil, err
+	}
+
+	var transactions []Transaction
+
+	result := d.DB.WithContext(ctx).
+		Scopes(memberOf("transactions", m, false)).
+		Where("anomaly_status = ?", status).
+		Order("created_at desc, id desc").
+		Find(&transactions)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	arr := make([]domain.TransactionAnomaly, 0, len(transactions))
+	for _, value := range transactions {
+		arr = append(arr, domain.TransactionAnomaly{
+			TransactionRecord: domain.TransactionRecord{
+				ID:   value.ID,
+				Date: value.CreatedAt,
+				TransactionOutput: domain.TransactionOutput{
+					UserID:      value.UserID,
+					HouseholdID: value.HouseholdID,
+					CategoryID:  value.CategoryID,
+					Name:        value.Name,
+					Count:       value.Count,
+					Description: value.Description,
+				},
+			},
+			Reason: value.AnomalyReason,
+			Status: value.AnomalyStatus,
+		})
+	}
+
+	return arr, nil
+}
+
+func (d *Db) ReviewAnomaly(ctx context.Context, m domain.Member, transactionId uint, status string) error {
+	result := d.DB.WithContext(ctx).Model(&Transaction{}).
+		Scopes(memberOf("transactions", m, true)).
+		Where("id = ? AND anomaly_status <> ''", transactionId).
+		Update("anomaly_status", status)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return notFound(d.DB.WithContext(ctx), m, true)
+	}
+
+	return nil
+}
@@ -8,45 +8,44 @@ import (
 	"gorm.io/gorm"
 )
 
-func (d *Db) CreateCategory(ctx context.Context, userID uint, category domain.CategoryInput) (uint, error) {
-	var user User
-	result := d.DB.WithContext(ctx).First(&user, userID)
-	if result.Error != nil {
-		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			return 0, ErrorNotFound
-		}
-		return 0, result.Error
+func (d *Db) CreateCategory(ctx context.Context, m domain.Member, category domain.CategoryInput) (uint, error) {
+	if err := checkMember(d.DB.WithContext(ctx), m, true); err != nil {
+		return 0, err
 	}
 
 	newCategory := Category{
+		UserID:      m.UserID,
+		HouseholdID: m.HouseholdID,
 		Name:        category.Name,
 		Limit:       category.Limit,
 		Type:        category.Type,
 		Description: category.Description,
 	}
 
-	err := d.DB.WithContext(ctx).Model(&user).Association("Categories").Append(&newCategory)
-	if err != nil {
-		if errors.Is(err, gorm.ErrDuplicatedKey) {
+	result := d.DB.WithContext(ctx).Create(&newCategory)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrDuplicatedKey) {
 			return 0, ErrorDuplicated
 		}
-		return 0, err
+		return 0, result.Error
 	}
 	return newCategory.ID, nil
 }
 
-func (d *Db) GetCategory(ctx context.Context, idCategory uint) (domain.CategoryOutput, error) {
+func (d *Db) GetCategory(ctx context.Context, m domain.Member, idCategory uint) (domain.CategoryOutput, error) {
 
 	var category Category
-	result := d.DB.WithContext(ctx).First(&category, idCategory)
+	result := d.DB.WithContext(ctx).Scopes(memberOf("categories", m, false)).First(&category, idCategory)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			return domain.CategoryOutput{}, ErrorNotFound
+			return domain.CategoryOutput{}, notFound(d.DB.WithContext(ctx), m, false)
 		}
 		return domain.CategoryOutput{}, result.Error
 	}
 
 	modelCategory := domain.CategoryOutput{
+		UserID:      category.UserID,
+		HouseholdID: category.HouseholdID,
 		Name:        category.Name,
 		Description: category.Description,
 		Type:        category.Type,
@@ -55,13 +54,13 @@ func (d *Db) GetCategory(ctx context.Context, idCategory uint) (domain.CategoryO
 	return modelCategory, nil
 }
 
-func (d *Db) UpdateCategory(ctx context.Context, idCategory uint, newCategory domain.CategoryInput) (domain.CategoryOutput, error) {
+func (d *Db) UpdateCategory(ctx context.Context, m domain.Member, idCategory uint, newCategory domain.CategoryInput) (domain.CategoryOutput, error) {
 
 	var categor Category
-	result := d.DB.WithContext(ctx).First(&categor, idCategory)
+	result := d.DB.WithContext(ctx).Scopes(memberOf("categories", m, true)).First(&categor, idCategory)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			return domain.CategoryOutput{}, ErrorNotFound
+			return domain.CategoryOutput{}, notFound(d.DB.WithContext(ctx), m, true)
 		}
 		return domain.CategoryOutput{}, result.Error
 	}
@@ -79,6 +78,8 @@ func (d *Db) UpdateCategory(ctx context.Context, idCategory uint, newCategory do
 	}
 
 	ResponseCategory := domain.CategoryOutput{
+		UserID:      categor.UserID,
+		HouseholdID: categor.HouseholdID,
 		Name:        categor.Name,
 		Description: categor.Description,
 		Type:        categor.Type,
@@ -87,21 +88,27 @@ func (d *Db) UpdateCategory(ctx context.Context, idCategory uint, newCategory do
 	return ResponseCategory, nil
 }
 
-func (d *Db) DeleteCategory(ctx context.Context, idCategory uint) error {
+func (d *Db) DeleteCategory(ctx context.Context, m domain.Member, idCategory uint) error {
 
-	result := d.DB.WithContext(ctx).Unscoped().Delete(&Category{}, idCategory)
+	result := d.DB.WithContext(ctx).Unscoped().Scopes(memberOf("categories", m, true)).Delete(&Category{}, idCategory)
 	if result.Error != nil {
 		return result.Error
 	}
+	if result.RowsAffected == 0 {
+		return notFound(d.DB.WithContext(ctx), m, true)
+	}
 
 	return nil
 }
 
-func (d *Db) CategoriesType(ctx context.Context, typeFound string) ([]domain.CategoryOutput, error) {
+func (d *Db) CategoriesType(ctx context.Context, m domain.Member, typeFound string) ([]domain.CategoryOutput, error) {
+	if err := checkMember(d.DB.WithContext(ctx), m, false); err != nil {
+		return []domain.CategoryOutput{}, err
+	}
 
 	var category []Category
 
-	result := d.DB.WithContext(ctx).Where("type = ?", typeFound).Find(&category, category)
+	result := d.DB.WithContext(ctx).Scopes(memberOf("categories", m, false)).Where("type = ?", typeFound).Find(&category)
 
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
@@ -115,7 +122,8 @@ func (d *Db) CategoriesType(ctx context.Context, typeFound string) ([]domain.Cat
 
 	for _, value := range category {
 		categor := domain.CategoryOutput{
-			UserID:      0,
+			UserID:      value.UserID,
+			HouseholdID: value.HouseholdID,
 			Name:        value.Name,
 			Limit:       value.Limit,
 			Type:        value.Type,
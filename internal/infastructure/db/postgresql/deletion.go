@@ -0,0 +1,217 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/financial_tracer/internal/lib/hashPassword"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// DeleteUser schedules the deletion of the user, a new login cancels it.
+func (d *Db) DeleteUser(ctx context.Context, email string, password string, deleteAt time.Time) (domain.User, error) {
+	var user User
+
+	err := d.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("email = ?", email).First(&user)
+		if result.Error != nil {
+			if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+				return ErrorNotFound
+			}
+			return result.Error
+		}
+
+		if err := hashPassword.Compare(user.PasswordHash, password); err != nil {
+			if errors.Is(err, hashPassword.ErrMismatched) {
+				return ErrorPassword
+			}
+			return err
+		}
+
+		if user.DisabledAt != nil {
+			return ErrorDisabled
+		}
+
+		result = tx.Model(&user).Update("deletion_at", deleteAt)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		return tx.Model(&Session{}).
+			Where("user_id = ? AND revoked_at IS NULL", user.ID).
+			Update("revoked_at", time.Now()).Error
+	})
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	return domain.User{Name: user.Name, Email: user.Email}, nil
+}
+
+func cancelDeletion(tx *gorm.DB, userID uint) error {
+	return tx.Model(&User{}).
+		Where("id = ? AND deletion_at IS NOT NULL", userID).
+		Update("deletion_at", nil).Error
+}
+
+func (d *Db) DueDeletions(ctx context.Context, now time.Time) ([]uint, error) {
+	var ids []uint
+
+	result := d.DB.WithContext(ctx).Model(&User{}).
+		Where("deletion_at IS NOT NULL AND deletion_at <= ?", now).
+		Order("deletion_at").
+		Pluck("id", &ids)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return ids, nil
+}
+
+// PurgeUser hands the rows of the user in the households that stay over to their owners.
+func (d *Db) PurgeUser(ctx context.Context, userID uint, now time.Time) (domain.PurgedUser, error) {
+	purged := domain.PurgedUser{UserID: userID}
+
+	err := d.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var user User
+		result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND deletion_at IS NOT NULL AND deletion_at <= ?", userID, now).
+			First(&user)
+		if result.Error != nil {
+			if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+				return ErrorNotFound
+			}
+			return result.Error
+		}
+
+		// a new session for every query, so the conditions don't pile up
+		tx = tx.Unscoped().Session(&gorm.Session{})
+
+		removed, err := transferHouseholds(tx, userID)
+		if err != nil {
+			return err
+		}
+
+		if len(removed) != 0 {
+			if err := tx.Model(&Category{}).Where("household_id IN ?", removed).Pluck("id", &purged.CategoryIDs).Error; err != nil {
+				return err
+			}
+			if err := tx.Model(&Transaction{}).Where("household_id IN ?", removed).Pluck("id", &purged.TransactionIDs).Error; err != nil {
+				return err
+			}
+		}
+
+		// the rows stay with the household, the owner answers for them from now on
+		var handedTransactions, handedCategories []uint
+		transactions := tx.Model(&Transaction{}).Where("user_id = ?", userID)
+		categories := tx.Model(&Category{}).Where("user_id = ?", userID)
+		if len(removed) != 0 {
+			transactions = transactions.Where("household_id NOT IN ?", removed)
+			categories = categories.Where("household_id NOT IN ?", removed)
+		}
+		transactions = transactions.Session(&gorm.Session{})
+		categories = categories.Session(&gorm.Session{})
+		if err := transactions.Pluck("id", &handedTransactions).Error; err != nil {
+			return err
+		}
+		if err := categories.Pluck("id", &handedCategories).Error; err != nil {
+			return err
+		}
+		purged.TransactionIDs = append(purged.TransactionIDs, handedTransactions...)
+		purged.CategoryIDs = append(purged.CategoryIDs, handedCategories...)
+		if err := transactions.Update("user_id", householdOwner("transactions", userID)).Error; err != nil {
+			return err
+		}
+		if err := categories.Update("user_id", householdOwner("categories", userID)).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&InviteCode{}).Where("created_by = ?", userID).Update("created_by", nil).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&DataExport{}).Where("user_id = ? AND file_name <> ''", userID).Pluck("file_name", &purged.Files).Error; err != nil {
+			return err
+		}
+
+		deletes := []struct {
+			value any
+			query string
+			args  []any
+		}{
+			{&Transaction{}, "household_id IN ?", []any{removed}},
+			{&Category{}, "household_id IN ?", []any{removed}},
+			{&Session{}, "user_id = ?", []any{userID}},
+			{&PasswordReset{}, "user_id = ?", []any{userID}},
+			{&EmailVerification{}, "user_id = ?", []any{userID}},
+			{&RecoveryCode{}, "user_id = ?", []any{userID}},
+			{&DataExport{}, "user_id = ?", []any{userID}},
+			{&AccessToken{}, "user_id = ?", []any{userID}},
+			{&OidcIdentity{}, "user_id = ?", []any{userID}},
+			{&SecurityEvent{}, "user_id = ?", []any{userID}},
+			{&HouseholdMember{}, "user_id = ? OR household_id IN ?", []any{userID, removed}},
+			{&HouseholdInvitation{}, "household_id IN ? OR invited_by = ?", []any{removed, userID}},
+			{&Household{}, "id IN ?", []any{removed}},
+			{&User{}, "id = ?", []any{userID}},
+		}
+		for _, value := range deletes {
+			if err := tx.Where(value.query, value.args...).Delete(value.value).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return domain.PurgedUser{}, err
+	}
+
+	return purged, nil
+}
+
+// transferHouseholds returns the households nobody else is a member of.
+func transferHouseholds(tx *gorm.DB, userID uint) ([]uint, error) {
+	var removed []uint
+	if err := tx.Model(&Household{}).Where("personal_user_id = ?", userID).Pluck("id", &removed).Error; err != nil {
+		return nil, err
+	}
+
+	var owned []uint
+	result := tx.Model(&HouseholdMember{}).
+		Joins("JOIN households ON households.id = household_members.household_id").
+		Where("household_members.user_id = ? AND household_members.role = ? AND households.personal_user_id IS NULL",
+			userID, domain.HouseholdOwner).
+		Pluck("household_members.household_id", &owned)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	for _, householdID := range owned {
+		var next HouseholdMember
+		result := tx.Where("household_id = ? AND user_id <> ?", householdID, userID).
+			Order("created_at, id").
+			First(&next)
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			removed = append(removed, householdID)
+			continue
+		}
+		if result.Error != nil {
+			return nil, result.Error
+		}
+
+		if err := tx.Model(&next).Update("role", domain.HouseholdOwner).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	return removed, nil
+}
+
+// householdOwner selects the owner of the household of the row other than the user.
+func householdOwner(table string, userID uint) clause.Expr {
+	return gorm.Expr("(SELECT hm.user_id FROM household_members hm WHERE hm.household_id = "+table+
+		".household_id AND hm.role = ? AND hm.user_id <> ? ORDER BY hm.id LIMIT 1)", domain.HouseholdOwner, userID)
+}
@@ -0,0 +1,135 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// testDb opens the database of TEST_DATABASE_DSN, the test is skipped without it.
+func testDb(t *testing.T) *Db {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_DSN is not set")
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{TranslateError: true})
+	require.NoError(t, err)
+	require.NoError(t, migrate(db))
+
+	return &Db{DB: db}
+}
+
+func TestPurgeUserSharedHousehold(t *testing.T) {
+	d := testDb(t)
+	ctx := context.Background()
+	now := time.Now()
+	suffix := fmt.Sprint(now.UnixNano())
+
+	createUser := func(name string) User {
+		user := User{Name: name, Email: name + suffix + "@tracker.local", PasswordHash: []byte("hash")}
+		require.NoError(t, d.DB.Create(&user).Error)
+		require.NoError(t, createPersonalHousehold(d.DB, user.ID, user.Name))
+		return user
+	}
+	createHousehold := func(name string, members ...HouseholdMember) Household {
+		household := Household{Name: name}
+		require.NoError(t, d.DB.Create(&household).Error)
+		for _, member := range members {
+			member.HouseholdID = household.ID
+			require.NoError(t, d.DB.Create(&member).Error)
+		}
+		return household
+	}
+	createCategory := func(userID uint, householdID uint, name string) Category {
+		category := Category{Name: name + suffix, UserID: userID, HouseholdID: householdID, Limit: 1000}
+		require.NoError(t, d.DB.Create(&category).Error)
+		return category
+	}
+	createTransaction := func(userID uint, category Category, name string) Transaction {
+		transaction := Transaction{Name: name, UserID: userID, HouseholdID: category.HouseholdID, CategoryID: category.ID, Count: 100}
+		require.NoError(t, d.DB.Create(&transaction).Error)
+		return transaction
+	}
+
+	anna := createUser("anna")
+	boris := createUser("boris")
+	t.Cleanup(func() {
+		d.DB.Unscoped().Where("user_id = ?", boris.ID).Delete(&Transaction{})
+		d.DB.Unscoped().Where("user_id = ?", boris.ID).Delete(&Category{})
+		d.DB.Unscoped().Where("user_id = ?", boris.ID).Delete(&HouseholdMember{})
+		d.DB.Unscoped().Where("personal_user_id = ?", boris.ID).Delete(&Household{})
+		d.DB.Unscoped().Delete(&User{}, boris.ID)
+	})
+
+	shared := createHousehold("family",
+		HouseholdMember{UserID: anna.ID, Role: domain.HouseholdOwner},
+		HouseholdMember{UserID: boris.ID, Role: domain.HouseholdEditor},
+	)
+	t.Cleanup(func() { d.DB.Unscoped().Delete(&Household{}, shared.ID) })
+	alone := createHousehold("alone", HouseholdMember{UserID: anna.ID, Role: domain.HouseholdOwner})
+
+	invitation := HouseholdInvitation{HouseholdID: shared.ID, Email: "ivan" + suffix + "@tracker.local", Role: domain.HouseholdViewer,
+		TokenHash: "invitation" + suffix, InvitedBy: anna.ID, ExpiresAt: now.Add(time.Hour)}
+	require.NoError(t, d.DB.Create(&invitation).Error)
+	code := InviteCode{Prefix: "ft_inv_", CodeHash: "code" + suffix, MaxUses: 1, CreatedBy: &anna.ID}
+	require.NoError(t, d.DB.Create(&code).Error)
+	t.Cleanup(func() { d.DB.Unscoped().Delete(&InviteCode{}, code.ID) })
+
+	var personal Household
+	require.NoError(t, d.DB.Where("personal_user_id = ?", anna.ID).First(&personal).Error)
+
+	annaShared := createCategory(anna.ID, shared.ID, "anna shared")
+	borisShared := createCategory(boris.ID, shared.ID, "boris shared")
+	annaPersonal := createCategory(anna.ID, personal.ID, "anna personal")
+	annaAlone := createCategory(anna.ID, alone.ID, "anna alone")
+
+	annaInBoris := createTransaction(anna.ID, borisShared, "anna in boris category")
+	borisInAnna := createTransaction(boris.ID, annaShared, "boris in anna category")
+	borisOwn := createTransaction(boris.ID, borisShared, "boris")
+	annaOwn := createTransaction(anna.ID, annaPersonal, "anna personal")
+	annaAloneTran := createTransaction(anna.ID, annaAlone, "anna alone")
+
+	require.NoError(t, d.DB.Model(&anna).Update("deletion_at", now.Add(-time.Hour)).Error)
+
+	purged, err := d.PurgeUser(ctx, anna.ID, now)
+	require.NoError(t, err)
+
+	assert.ElementsMatch(t, []uint{annaPersonal.ID, annaAlone.ID, annaShared.ID}, purged.CategoryIDs)
+	assert.ElementsMatch(t, []uint{annaOwn.ID, annaAloneTran.ID, annaInBoris.ID}, purged.TransactionIDs)
+
+	count := func(model any, query string, args ...any) int64 {
+		var n int64
+		require.NoError(t, d.DB.Unscoped().Model(model).Where(query, args...).Count(&n).Error)
+		return n
+	}
+
+	// the user and the households nobody else uses are gone
+	assert.Zero(t, count(&User{}, "id = ?", anna.ID))
+	assert.Zero(t, count(&Household{}, "id IN ?", []uint{personal.ID, alone.ID}))
+	assert.Zero(t, count(&Category{}, "id IN ?", []uint{annaPersonal.ID, annaAlone.ID}))
+	assert.Zero(t, count(&Transaction{}, "id IN ?", []uint{annaOwn.ID, annaAloneTran.ID}))
+	assert.Zero(t, count(&HouseholdInvitation{}, "id = ?", invitation.ID))
+	assert.Equal(t, int64(1), count(&InviteCode{}, "id = ? AND created_by IS NULL", code.ID))
+
+	// the shared household keeps the data of every member and gets a new owner
+	var owner HouseholdMember
+	require.NoError(t, d.DB.Where("household_id = ?", shared.ID).First(&owner).Error)
+	assert.Equal(t, boris.ID, owner.UserID)
+	assert.Equal(t, domain.HouseholdOwner, owner.Role)
+	assert.Equal(t, int64(1), count(&HouseholdMember{}, "household_id = ?", shared.ID))
+
+	assert.Equal(t, int64(2), count(&Category{}, "id IN ? AND user_id = ?", []uint{annaShared.ID, borisShared.ID}, boris.ID))
+	assert.Equal(t, int64(3), count(&Transaction{}, "id IN ? AND user_id = ?",
+		[]uint{annaInBoris.ID, borisInAnna.ID, borisOwn.ID}, boris.ID))
+}
@@ -0,0 +1,248 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/financial_tracer/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CreateDataExport returns the pending export started after staleBefore with created false.
+func (d *Db) CreateDataExport(ctx context.Context, userID uint, staleBefore time.Time) (domain.DataExport, bool, error) {
+	var export DataExport
+	created := false
+
+	err := d.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var user User
+		result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").Where("id = ?", userID).First(&user)
+		if result.Error != nil {
+			if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+				return ErrorNotFound
+			}
+			return result.Error
+		}
+
+		result = tx.Where("user_id = ? AND status = ? AND created_at > ?", userID, domain.ExportPending, staleBefore).
+			Order("id DESC").Limit(1).Find(&export)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected != 0 {
+			return nil
+		}
+
+		export = DataExport{
+			UserID: userID,
+			Status: domain.ExportPending,
+		}
+		created = true
+
+		return tx.Create(&export).Error
+	})
+	if err != nil {
+		return domain.DataExport{}, false, err
+	}
+
+	return dataExport(export), created, nil
+}
+
+func (d *Db) CompleteDataExport(ctx context.Context, id uint, fileName string, size int64, expiresAt time.Time) error {
+	now := time.Now()
+
+	return d.DB.WithContext(ctx).Model(&DataExport{}).Where("id = ?", id).Updates(map[string]any{
+		"status":       domain.ExportReady,
+		"file_name":    fileName,
+		"size":         size,
+		"completed_at": now,
+		"expires_at":   expiresAt,
+	}).Error
+}
+
+func (d *Db) FailDataExport(ctx context.Context, id uint, reason string) error {
+	now := time.Now()
+
+	return d.DB.WithContext(ctx).Model(&DataExport{}).Where("id = ?", id).Updates(map[string]any{
+		"status":       domain.ExportFailed,
+		"error":        reason,
+		"completed_at": now,
+	}).Error
+}
+
+func (d *Db) UserDataExports(ctx context.Context, userID uint) ([]domain.DataExport, error) {
+	var exports []DataExport
+
+	result := d.DB.WithContext(ctx).Where("user_id = ?", userID).Order("id DESC").Find(&exports)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	arr := make([]domain.DataExport, 0, len(exports))
+	for _, value := range exports {
+		arr = append(arr, dataExport(value))
+	}
+
+	return arr, nil
+}
+
+// DataExport with userID 0 returns the export of any user.
+func (d *Db) DataExport(ctx context.Context, userID uint, id uint) (domain.DataExport, error) {
+	var export DataExport
+
+	query := d.DB.WithContext(ctx).Where("id = ?", id)
+	if userID != 0 {
+		query = query.Where("user_id = ?", userID)
+	}
+
+	result := query.First(&export)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return domain.DataExport{}, ErrorNotFound
+		}
+		return domain.DataExport{}, result.Error
+	}
+
+	return dataExport(export), nil
+}
+
+func (d *Db) DeleteExpiredDataExports(ctx context.Context, now time.Time) ([]string, error) {
+	var exports []DataExport
+
+	result := d.DB.WithContext(ctx).Unscoped().Clauses(clause.Returning{}).
+		Where("expires_at < ?", now).
+		Delete(&exports)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	names := make([]string, 0, len(exports))
+	for _, value := range exports {
+		if value.FileName != "" {
+			names = append(names, value.FileName)
+		}
+	}
+
+	return names, nil
+}
+
+func (d *Db) UserArchive(ctx context.Context, userID uint) (domain.Archive, error) {
+	profile, err := d.UserProfile(ctx, userID)
+	if err != nil {
+		return domain.Archive{}, err
+	}
+
+	var categories []Category
+	if err := d.DB.WithContext(ctx).Where("user_id = ?", userID).Order("id").Find(&categories).Error; err != nil {
+		return domain.Archive{}, err
+	}
+
+	var transactions []Transaction
+	if err := d.DB.WithContext(ctx).Where("user_id = ?", userID).Order("id").Find(&transactions).Error; err != nil {
+		return domain.Archive{}, err
+	}
+
+	var sessions []Session
+	if err := d.DB.WithContext(ctx).Where("user_id = ?", userID).Order("id").Find(&sessions).Error; err != nil {
+		return domain.Archive{}, err
+	}
+
+	archive := domain.Archive{
+		Profile:      profile,
+		Categories:   make([]domain.ArchiveCategory, 0, len(categories)),
+		Transactions: make([]domain.ArchiveTransaction, 0, len(transactions)),
+		Sessions:     make([]domain.Session, 0, len(sessions)),
+	}
+
+	names := make(map[uint]string, len(categories))
+	for _, value := range categories {
+		names[value.ID] = value.Name
+		archive.Categories = append(archive.Categories, domain.ArchiveCategory{
+			ID:          value.ID,
+			Name:        value.Name,
+			Limit:       value.Limit,
+			Type:        value.Type,
+			Description: value.Description,
+			CreatedAt:   value.CreatedAt,
+			UpdatedAt:   value.UpdatedAt,
+		})
+	}
+
+	for _, value := range transactions {
+		archive.Transactions = append(archive.Transactions, domain.ArchiveTransaction{
+			ID:            value.ID,
+			CategoryID:    value.CategoryID,
+			Category:      names[value.CategoryID],
+			Name:          value.Name,
+			Count:         value.Count,
+			Description:   value.Description,
+			AnomalyStatus: value.AnomalyStatus,
+			AnomalyReason: value.AnomalyReason,
+			CreatedAt:     value.CreatedAt,
+			UpdatedAt:     value.UpdatedAt,
+		})
+	}
+
+	for _, value := range sessions {
+		archive.Sessions = append(archive.Sessions, domain.Session{
+			ID: value.ID,
+			Device: domain.Device{
+				UserAgent: value.UserAgent,
+				IP:        value.IP,
+			},
+			CreatedAt:  value.CreatedAt,
+			LastUsedAt: value.LastUsedAt,
+			ExpiresAt:  value.ExpiresAt,
+		})
+	}
+
+	archive.AccessTokens, err = d.AccessTokens(ctx, userID)
+	if err != nil {
+		return domain.Archive{}, err
+	}
+
+	var events []SecurityEvent
+	if err := d.DB.WithContext(ctx).Where("user_id = ?", userID).Order("id").Find(&events).Error; err != nil {
+		return domain.Archive{}, err
+	}
+	archive.SecurityEvents = make([]domain.SecurityEvent, 0, len(events))
+	for _, value := range events {
+		archive.SecurityEvents = append(archive.SecurityEvents, securityEvent(value))
+	}
+
+	archive.Households, err = d.Households(ctx, userID)
+	if err != nil {
+		return domain.Archive{}, err
+	}
+
+	var identities []OidcIdentity
+	if err := d.DB.WithContext(ctx).Where("user_id = ?", userID).Order("id").Find(&identities).Error; err != nil {
+		return domain.Archive{}, err
+	}
+	archive.Identities = make([]domain.ArchiveIdentity, 0, len(identities))
+	for _, value := range identities {
+		archive.Identities = append(archive.Identities, domain.ArchiveIdentity{
+			Provider: value.Provider,
+			Subject:  value.Subject,
+			Email:    value.Email,
+			LinkedAt: value.CreatedAt,
+		})
+	}
+
+	return archive, nil
+}
+
+func dataExport(value DataExport) domain.DataExport {
+	return domain.DataExport{
+		ID:          value.ID,
+		Status:      value.Status,
+		Size:        value.Size,
+		Error:       value.Error,
+		FileName:    value.FileName,
+		UserID:      value.UserID,
+		CreatedAt:   value.CreatedAt,
+		CompletedAt: value.CompletedAt,
+		ExpiresAt:   value.ExpiresAt,
+	}
+}
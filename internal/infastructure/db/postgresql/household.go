@@ -0,0 +1,419 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/financial_tracer/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// householdMigration gives the users registered before households a personal household.
+const householdMigration = `
+INSERT INTO households (created_at, updated_at, name, personal_user_id)
+SELECT now(), now(), u.name, u.id FROM users u
+WHERE NOT EXISTS (SELECT 1 FROM households h WHERE h.personal_user_id = u.id);
+INSERT INTO household_members (created_at, household_id, user_id, role)
+SELECT now(), h.id, h.personal_user_id, 'owner' FROM households h
+WHERE h.personal_user_id IS NOT NULL AND NOT EXISTS (
+	SELECT 1 FROM household_members m WHERE m.household_id = h.id AND m.user_id = h.personal_user_id
+);
+UPDATE categories c SET household_id = h.id FROM households h
+WHERE (c.household_id IS NULL OR c.household_id = 0) AND h.personal_user_id = c.user_id;
+UPDATE transactions t SET household_id = h.id FROM households h
+WHERE (t.household_id IS NULL OR t.household_id = 0) AND h.personal_user_id = t.user_id;
+`
+
+func migrateHouseholds(db *gorm.DB) error {
+	return db.Exec(householdMigration).Error
+}
+
+func roles(write bool) []string {
+	if write {
+		return []string{domain.HouseholdOwner, domain.HouseholdEditor}
+	}
+	return []string{domain.HouseholdOwner, domain.HouseholdEditor, domain.HouseholdViewer}
+}
+
+// memberOf limits a query on table to the household of m, with write only for the owner and editors.
+func memberOf(table string, m domain.Member, write bool) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where(table+".household_id = ? AND EXISTS (SELECT 1 FROM household_members hm WHERE hm.household_id = ? AND hm.user_id = ? AND hm.role IN ?)",
+			m.HouseholdID, m.HouseholdID, m.UserID, roles(write))
+	}
+}
+
+func checkMember(db *gorm.DB, m domain.Member, write bool) error {
+	var count int64
+
+	result := db.Model(&HouseholdMember{}).
+		Where("household_id = ? AND user_id = ? AND role IN ?", m.HouseholdID, m.UserID, roles(write)).
+		Count(&count)
+	if result.Error != nil {
+		return result.Error
+	}
+	if count == 0 {
+		return ErrorForbidden
+	}
+
+	return nil
+}
+
+// notFound tells a household the user may not use from a missing row.
+func notFound(db *gorm.DB, m domain.Member, write bool) error {
+	if err := checkMember(db, m, write); err != nil {
+		return err
+	}
+	return ErrorNotFound
+}
+
+func createPersonalHousehold(tx *gorm.DB, userID uint, name string) error {
+	household := Household{
+		Name:           name,
+		PersonalUserID: &userID,
+	}
+	if err := tx.Create(&household).Error; err != nil {
+		return err
+	}
+
+	return tx.Create(&HouseholdMember{
+		HouseholdID: household.ID,
+		UserID:      userID,
+		Role:        domain.HouseholdOwner,
+	}).Error
+}
+
+func (d *Db) PersonalHousehold(ctx context.Context, userID uint) (domain.Member, error) {
+	var household Household
+
+	result := d.DB.WithContext(ctx).Select("id").Where("personal_user_id = ?", userID).First(&household)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return domain.Member{}, ErrorNotFound
+		}
+		return domain.Member{}, result.Error
+	}
+
+	return domain.Member{
+		UserID:      userID,
+		HouseholdID: household.ID,
+		Role:        domain.HouseholdOwner,
+	}, nil
+}
+
+func (d *Db) HouseholdMember(ctx context.Context, userID uint, householdID uint) (domain.Member, error) {
+	var member HouseholdMember
+
+	result := d.DB.WithContext(ctx).
+		Joins("JOIN households ON households.id = household_members.household_id AND households.deleted_at IS NULL").
+		Where("household_members.household_id = ? AND household_members.user_id = ?", householdID, userID).
+		First(&member)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return domain.Member{}, ErrorNotFound
+		}
+		return domain.Member{}, result.Error
+	}
+
+	return domain.Member{
+		UserID:      userID,
+		HouseholdID: householdID,
+		Role:        member.Role,
+	}, nil
+}
+
+type householdRow struct {
+	ID             uint
+	Name           string
+	PersonalUserID *uint
+	Role           string
+	CreatedAt      time.Time
+}
+
+func (d *Db) Households(ctx context.Context, userID uint) ([]domain.Household, error) {
+	var rows []householdRow
+
+	result := d.DB.WithContext(ctx).Model(&Household{}).
+		Select("households.id, households.name, households.personal_user_id, households.created_at, household_members.role").
+		Joins("JOIN household_members ON household_members.household_id = households.id").
+		Where("household_members.user_id = ?", userID).
+		Order("households.personal_user_id IS NULL, households.id").
+		Scan(&rows)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	arr := make([]domain.Household, 0, len(rows))
+	for _, row := range rows {
+		arr = append(arr, household(row))
+	}
+
+	return arr, nil
+}
+
+func (d *Db) Household(ctx context.Context, m domain.Member) (domain.Household, error) {
+	var row householdRow
+
+	result := d.DB.WithContext(ctx).Model(&Household{}).
+		Select("households.id, households.name, households.personal_user_id, households.created_at, household_members.role").
+		Joins("JOIN household_members ON household_members.household_id = households.id").
+		Where("households.id = ? AND household_members.user_id = ?", m.HouseholdID, m.UserID).
+		Take(&row)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return domain.Household{}, ErrorNotFound
+		}
+		return domain.Household{}, result.Error
+	}
+
+	value := household(row)
+
+	result = d.DB.WithContext(ctx).Model(&HouseholdMember{}).
+		Select("household_members.user_id, users.name, users.email, household_members.role, household_members.created_at AS joined_at").
+		Joins("JOIN users ON users.id = household_members.user_id AND users.deleted_at IS NULL").
+		Where("household_members.household_id = ?", m.HouseholdID).
+		Order("household_members.id").
+		Scan(&value.Members)
+	if result.Error != nil {
+		return domain.Household{}, result.Error
+	}
+
+	return value, nil
+}
+
+func (d *Db) CreateHousehold(ctx context.Context, userID uint, name string) (domain.Household, error) {
+	value := Household{Name: name}
+
+	err := d.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&value).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(&HouseholdMember{
+			HouseholdID: value.ID,
+			UserID:      userID,
+			Role:        domain.HouseholdOwner,
+		}).Error
+	})
+	if err != nil {
+		return domain.Household{}, err
+	}
+
+	return domain.Household{
+		ID:        value.ID,
+		Name:      value.Name,
+		Role:      domain.HouseholdOwner,
+		CreatedAt: value.CreatedAt,
+	}, nil
+}
+
+func (d *Db) CreateHouseholdInvitation(ctx context.Context, m domain.Member, invite domain.InviteMember, tokenHash string, expiresAt time.Time) (domain.HouseholdInvitation, error) {
+	var res domain.HouseholdInvitation
+
+	err := d.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var inviter User
+		if err := tx.Select("id", "name").First(&inviter, m.UserID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrorNotFound
+			}
+			return err
+		}
+
+		var household Household
+		result := tx.Joins("JOIN household_members hm ON hm.household_id = households.id").
+			Where("households.id = ? AND hm.user_id = ? AND hm.role = ?", m.HouseholdID, m.UserID, domain.HouseholdOwner).
+			First(&household)
+		if result.Error != nil {
+			if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+				return notFound(tx, m, false)
+			}
+			return result.Error
+		}
+		if household.PersonalUserID != nil {
+			return ErrorPersonal
+		}
+
+		var count int64
+		result = tx.Model(&HouseholdMember{}).
+			Joins("JOIN users ON users.id = household_members.user_id").
+			Where("household_members.household_id = ? AND LOWER(users.email) = LOWER(?)", m.HouseholdID, invite.Email).
+			Count(&count)
+		if result.Error != nil {
+			return result.Error
+		}
+		if count != 0 {
+			return ErrorDuplicated
+		}
+
+		invitation := HouseholdInvitation{
+			HouseholdID: m.HouseholdID,
+			Email:       invite.Email,
+			Role:        invite.Role,
+			TokenHash:   tokenHash,
+			InvitedBy:   m.UserID,
+			ExpiresAt:   expiresAt,
+		}
+		if err := tx.Create(&invitation).Error; err != nil {
+			return err
+		}
+
+		res = domain.HouseholdInvitation{
+			ID:          invitation.ID,
+			HouseholdID: household.ID,
+			Household:   household.Name,
+			Email:       invitation.Email,
+			Role:        invitation.Role,
+			InvitedBy:   inviter.Name,
+			ExpiresAt:   invitation.ExpiresAt,
+		}
+
+		return nil
+	})
+	if err != nil {
+		return domain.HouseholdInvitation{}, err
+	}
+
+	return res, nil
+}
+
+// AcceptHouseholdInvitation requires the invitation to be sent to the email of the user.
+func (d *Db) AcceptHouseholdInvitation(ctx context.Context, userID uint, tokenHash string, now time.Time) (domain.Household, error) {
+	var res domain.Household
+
+	err := d.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var user User
+		if err := tx.Select("id", "email").First(&user, userID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrorNotFound
+			}
+			return err
+		}
+
+		var invitation HouseholdInvitation
+		result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("token_hash = ? AND accepted_at IS NULL AND expires_at > ?", tokenHash, now).
+			First(&invitation)
+		if result.Error != nil {
+			if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+				return ErrorNotFound
+			}
+			return result.Error
+		}
+		if !strings.EqualFold(invitation.Email, user.Email) {
+			return ErrorNotFound
+		}
+
+		var household Household
+		if err := tx.First(&household, invitation.HouseholdID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrorNotFound
+			}
+			return err
+		}
+
+		member := HouseholdMember{
+			HouseholdID: household.ID,
+			UserID:      userID,
+			Role:        invitation.Role,
+		}
+		if err := tx.Create(&member).Error; err != nil {
+			if errors.Is(err, gorm.ErrDuplicatedKey) {
+				return ErrorDuplicated
+			}
+			return err
+		}
+
+		if err := tx.Model(&invitation).Update("accepted_at", now).Error; err != nil {
+			return err
+		}
+
+		res = domain.Household{
+			ID:        household.ID,
+			Name:      household.Name,
+			Role:      member.Role,
+			CreatedAt: household.CreatedAt,
+		}
+
+		return nil
+	})
+	if err != nil {
+		return domain.Household{}, err
+	}
+
+	return res, nil
+}
+
+func (d *Db) UpdateHouseholdMember(ctx context.Context, m domain.Member, userID uint, role string) error {
+	return d.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := checkOwner(tx, m); err != nil {
+			return err
+		}
+
+		result := tx.Model(&HouseholdMember{}).
+			Where("household_id = ? AND user_id = ? AND role <> ?", m.HouseholdID, userID, domain.HouseholdOwner).
+			Update("role", role)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrorNotFound
+		}
+
+		return nil
+	})
+}
+
+// RemoveHouseholdMember lets any member but the owner leave by removing itself.
+func (d *Db) RemoveHouseholdMember(ctx context.Context, m domain.Member, userID uint) error {
+	return d.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if userID != m.UserID {
+			if err := checkOwner(tx, m); err != nil {
+				return err
+			}
+		}
+
+		result := tx.Where("household_id = ? AND user_id = ? AND role <> ?", m.HouseholdID, userID, domain.HouseholdOwner).
+			Delete(&HouseholdMember{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			if userID == m.UserID {
+				return ErrorForbidden
+			}
+			return ErrorNotFound
+		}
+
+		return nil
+	})
+}
+
+func checkOwner(tx *gorm.DB, m domain.Member) error {
+	var member HouseholdMember
+
+	result := tx.Where("household_id = ? AND user_id = ?", m.HouseholdID, m.UserID).First(&member)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return ErrorNotFound
+		}
+		return result.Error
+	}
+	if member.Role != domain.HouseholdOwner {
+		return ErrorForbidden
+	}
+
+	return nil
+}
+
+func household(row householdRow) domain.Household {
+	return domain.Household{
+		ID:        row.ID,
+		Name:      row.Name,
+		Personal:  row.PersonalUserID != nil,
+		Role:      row.Role,
+		CreatedAt: row.CreatedAt,
+	}
+}
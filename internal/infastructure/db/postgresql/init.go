@@ -2,25 +2,42 @@ package postgresql
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/financial_tracer/internal/config"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
+// TOTPChallenge of a User is the jti of the only login challenge that may be exchanged.
 type User struct {
 	gorm.Model
-	Name         string        `gorm:"size:50;not null"`
-	Email        string        `gorm:"not null;unique"`
-	PasswordHash []byte        `gorm:"not null"`
-	Categories   []Category    `gorm:"foreignKey:UserID"`
-	Transactions []Transaction `gorm:"foreignKey:UserID"`
+	Name              string `gorm:"size:50;not null"`
+	Email             string `gorm:"not null;unique"`
+	PasswordHash      []byte `gorm:"not null"`
+	VerifiedAt        *time.Time
+	TOTPSecret        string `gorm:"size:64"`
+	TOTPPendingSecret string `gorm:"size:64"`
+	TOTPEnabledAt     *time.Time
+	TOTPLastStep      int64
+	TOTPChallenge     string `gorm:"size:64"`
+	Currency          string `gorm:"size:3;not null;default:RUB"`
+	Timezone          string `gorm:"size:64;not null;default:UTC"`
+	Locale            string `gorm:"size:8;not null;default:en"`
+	WeekStart         int    `gorm:"not null;default:1"`
+	MonthStart        int    `gorm:"not null;default:1"`
+	Role              string `gorm:"size:16;not null;default:user"`
+	DisabledAt        *time.Time
+	DeletionAt        *time.Time    `gorm:"index"`
+	Categories        []Category    `gorm:"foreignKey:UserID"`
+	Transactions      []Transaction `gorm:"foreignKey:UserID"`
 }
 
 type Category struct {
 	gorm.Model
 	Name         string `gorm:"size:60;not null;unique"`
 	UserID       uint
+	HouseholdID  uint          `gorm:"index"`
 	Limit        int           `gorm:"not null"`
 	Type         string        `gorm:"size:100"`
 	Description  string        `gorm:"size:100"`
@@ -29,11 +46,140 @@ type Category struct {
 
 type Transaction struct {
 	gorm.Model
-	Name        string `gorm:"not null;size:60"`
-	UserID      uint
-	CategoryID  uint
-	Count       int    `gorm:"not null"`
-	Description string `gorm:"size:100"`
+	Name          string `gorm:"not null;size:60"`
+	UserID        uint
+	HouseholdID   uint `gorm:"index"`
+	CategoryID    uint
+	Count         int    `gorm:"not null"`
+	Description   string `gorm:"size:100"`
+	AnomalyStatus string `gorm:"size:20;index"`
+	AnomalyReason string `gorm:"size:200"`
+}
+
+// Session is a refresh-token family, RefreshJti is the only token that may be exchanged.
+type Session struct {
+	gorm.Model
+	UserID     uint   `gorm:"not null;index"`
+	RefreshJti string `gorm:"size:64;not null;uniqueIndex"`
+	UserAgent  string `gorm:"size:255"`
+	IP         string `gorm:"size:45"`
+	LastUsedAt time.Time
+	ExpiresAt  time.Time
+	RevokedAt  *time.Time
+}
+
+type PasswordReset struct {
+	gorm.Model
+	UserID    uint   `gorm:"not null;index"`
+	TokenHash string `gorm:"size:64;not null;uniqueIndex"`
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+}
+
+type EmailVerification struct {
+	gorm.Model
+	UserID    uint   `gorm:"not null;index"`
+	TokenHash string `gorm:"size:64;not null;uniqueIndex"`
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+}
+
+type RecoveryCode struct {
+	gorm.Model
+	UserID   uint   `gorm:"not null;index"`
+	CodeHash string `gorm:"size:64;not null;index"`
+	UsedAt   *time.Time
+}
+
+type DataExport struct {
+	gorm.Model
+	UserID      uint   `gorm:"not null;index"`
+	Status      string `gorm:"size:16;not null"`
+	FileName    string `gorm:"size:64"`
+	Size        int64
+	Error       string `gorm:"size:255"`
+	CompletedAt *time.Time
+	ExpiresAt   *time.Time `gorm:"index"`
+}
+
+type AccessToken struct {
+	gorm.Model
+	UserID     uint   `gorm:"not null;index"`
+	Name       string `gorm:"size:60;not null"`
+	Prefix     string `gorm:"size:16;not null"`
+	TokenHash  string `gorm:"size:64;not null;uniqueIndex"`
+	Scopes     string `gorm:"size:255;not null"`
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+}
+
+// PersonalUserID is set only for the personal household of the user.
+type Household struct {
+	gorm.Model
+	Name           string `gorm:"size:60;not null"`
+	PersonalUserID *uint  `gorm:"uniqueIndex"`
+}
+
+type HouseholdMember struct {
+	ID          uint   `gorm:"primarykey"`
+	HouseholdID uint   `gorm:"not null;uniqueIndex:idx_household_member"`
+	UserID      uint   `gorm:"not null;uniqueIndex:idx_household_member;index"`
+	Role        string `gorm:"size:16;not null"`
+	CreatedAt   time.Time
+}
+
+type HouseholdInvitation struct {
+	gorm.Model
+	HouseholdID uint   `gorm:"not null;index"`
+	Email       string `gorm:"not null"`
+	Role        string `gorm:"size:16;not null"`
+	TokenHash   string `gorm:"size:64;not null;uniqueIndex"`
+	InvitedBy   uint   `gorm:"not null"`
+	ExpiresAt   time.Time
+	AcceptedAt  *time.Time
+}
+
+type OidcState struct {
+	ID        uint   `gorm:"primarykey"`
+	StateHash string `gorm:"size:64;not null;uniqueIndex"`
+	Provider  string `gorm:"size:64;not null"`
+	Nonce     string `gorm:"size:64;not null"`
+	Verifier  string `gorm:"size:64;not null"`
+	ExpiresAt time.Time
+	CreatedAt time.Time
+}
+
+type OidcIdentity struct {
+	gorm.Model
+	UserID   uint   `gorm:"not null;index"`
+	Provider string `gorm:"size:64;not null;uniqueIndex:idx_oidc_subject"`
+	Subject  string `gorm:"size:255;not null;uniqueIndex:idx_oidc_subject"`
+	Email    string `gorm:"not null"`
+}
+
+// CreatedBy of an InviteCode is cleared when the admin is deleted.
+type InviteCode struct {
+	gorm.Model
+	Prefix    string `gorm:"size:16;not null"`
+	CodeHash  string `gorm:"size:64;not null;uniqueIndex"`
+	Note      string `gorm:"size:100"`
+	MaxUses   int    `gorm:"not null"`
+	Uses      int    `gorm:"not null;default:0"`
+	ExpiresAt *time.Time
+	CreatedBy *uint
+}
+
+type SecurityEvent struct {
+	ID        uint      `gorm:"primarykey"`
+	UserID    uint      `gorm:"not null;index:idx_security_event_user"`
+	Email     string    `gorm:"size:255;index"`
+	Type      string    `gorm:"size:32;not null;index"`
+	Success   bool      `gorm:"not null"`
+	Reason    string    `gorm:"size:100"`
+	NewDevice bool      `gorm:"not null"`
+	UserAgent string    `gorm:"size:255"`
+	IP        string    `gorm:"size:45"`
+	CreatedAt time.Time `gorm:"index:idx_security_event_user"`
 }
 
 type Db struct {
@@ -51,16 +197,53 @@ func Init(cfg *config.Config) (*Db, error) {
 		return nil, fmt.Errorf("error conn database: %w", err)
 	}
 
-	err = db.AutoMigrate(
+	if err := migrate(db); err != nil {
+		return nil, err
+	}
+
+	return &Db{
+		DB: db,
+	}, nil
+}
+
+func migrate(db *gorm.DB) error {
+	backfill := db.Migrator().HasTable(&User{}) && !db.Migrator().HasColumn(&User{}, "VerifiedAt")
+
+	err := db.AutoMigrate(
 		&User{},
 		&Category{},
 		&Transaction{},
+		&Session{},
+		&PasswordReset{},
+		&EmailVerification{},
+		&RecoveryCode{},
+		&DataExport{},
+		&AccessToken{},
+		&Household{},
+		&HouseholdMember{},
+		&HouseholdInvitation{},
+		&OidcState{},
+		&OidcIdentity{},
+		&SecurityEvent{},
+		&InviteCode{},
 	)
 	if err != nil {
-		return nil, fmt.Errorf("error migrate database: %w", err)
+		return fmt.Errorf("error migrate database: %w", err)
 	}
 
-	return &Db{
-		DB: db,
-	}, nil
+	if backfill {
+		if err := migrateVerification(db); err != nil {
+			return fmt.Errorf("error migrate verification: %w", err)
+		}
+	}
+
+	if err := migrateSearch(db); err != nil {
+		return fmt.Errorf("error migrate search: %w", err)
+	}
+
+	if err := migrateHouseholds(db); err != nil {
+		return fmt.Errorf("error migrate households: %w", err)
+	}
+
+	return nil
 }
@@ -0,0 +1,92 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/financial_tracer/internal/domain"
+	"gorm.io/gorm"
+)
+
+func (d *Db) CreateInviteCode(ctx context.Context, code domain.InviteCode, codeHash string) (domain.InviteCode, error) {
+	value := InviteCode{
+		Prefix:    code.Prefix,
+		CodeHash:  codeHash,
+		Note:      code.Note,
+		MaxUses:   code.MaxUses,
+		ExpiresAt: code.ExpiresAt,
+		CreatedBy: &code.CreatedBy,
+	}
+
+	result := d.DB.WithContext(ctx).Create(&value)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrDuplicatedKey) {
+			return domain.InviteCode{}, ErrorDuplicated
+		}
+		return domain.InviteCode{}, result.Error
+	}
+
+	return inviteCode(value), nil
+}
+
+func (d *Db) InviteCodes(ctx context.Context) ([]domain.InviteCode, error) {
+	var codes []InviteCode
+
+	result := d.DB.WithContext(ctx).Order("id DESC").Find(&codes)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	arr := make([]domain.InviteCode, 0, len(codes))
+	for _, value := range codes {
+		arr = append(arr, inviteCode(value))
+	}
+
+	return arr, nil
+}
+
+func (d *Db) RevokeInviteCode(ctx context.Context, id uint) error {
+	result := d.DB.WithContext(ctx).Where("id = ?", id).Delete(&InviteCode{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrorNotFound
+	}
+
+	return nil
+}
+
+// useInviteCode checks and counts the use in one statement, so concurrent sign-ups respect MaxUses.
+func useInviteCode(tx *gorm.DB, codeHash string, now time.Time) error {
+	result := tx.Model(&InviteCode{}).
+		Where("code_hash = ? AND uses < max_uses AND (expires_at IS NULL OR expires_at > ?)", codeHash, now).
+		Update("uses", gorm.Expr("uses + 1"))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrorInvite
+	}
+
+	return nil
+}
+
+func inviteCode(value InviteCode) domain.InviteCode {
+	code := domain.InviteCode{
+		ID:        value.ID,
+		Prefix:    value.Prefix,
+		Note:      value.Note,
+		MaxUses:   value.MaxUses,
+		Uses:      value.Uses,
+		ExpiresAt: value.ExpiresAt,
+		CreatedAt: value.CreatedAt,
+	}
+	// the admin who created the code may have been deleted
+	if value.CreatedBy != nil {
+		code.CreatedBy = *value.CreatedBy
+	}
+
+	return code
+}
@@ -0,0 +1,179 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+
+	"github.com/financial_tracer/internal/domain"
+	"gorm.io/gorm"
+)
+
+func (d *Db) UserJournal(ctx context.Context, userID uint) (domain.Journal, error) {
+	var user User
+	result := d.DB.WithContext(ctx).Preload("Categories", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id")
+	}).Preload("Transactions", func(db *gorm.DB) *gorm.DB {
+		return db.Order("created_at, id")
+	}).First(&user, userID)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return domain.Journal{}, ErrorNotFound
+		}
+		return domain.Journal{}, result.Error
+	}
+
+	return journal(user, user.Categories, user.Transactions), nil
+}
+
+func (d *Db) Journal(ctx context.Context, m domain.Member) (domain.Journal, error) {
+	var user User
+	result := d.DB.WithContext(ctx).First(&user, m.UserID)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return domain.Journal{}, ErrorNotFound
+		}
+		return domain.Journal{}, result.Error
+	}
+
+	if err := checkMember(d.DB.WithContext(ctx), m, false); err != nil {
+		return domain.Journal{}, err
+	}
+
+	var categories []Category
+	result = d.DB.WithContext(ctx).Scopes(memberOf("categories", m, false)).Order("id").Find(&categories)
+	if result.Error != nil {
+		return domain.Journal{}, result.Error
+	}
+
+	var transactions []Transaction
+	result = d.DB.WithContext(ctx).Scopes(memberOf("transactions", m, false)).Order("created_at, id").Find(&transactions)
+	if result.Error != nil {
+		return domain.Journal{}, result.Error
+	}
+
+	return journal(user, categories, transactions), nil
+}
+
+func journal(user User, categories []Category, transactions []Transaction) domain.Journal {
+	journal := domain.Journal{
+		UserName: user.Name,
+		Email:    user.Email,
+		Currency: user.Currency,
+	}
+
+	names := make(map[uint]string, len(categories))
+	for _, value := range categories {
+		names[value.ID] = value.Name
+		journal.Categories = append(journal.Categories, domain.CategoryInput{
+			Name:        value.Name,
+			Limit:       value.Limit,
+			Type:        value.Type,
+			Description: value.Description,
+		})
+	}
+
+	for _, value := range transactions {
+		journal.Transactions = append(journal.Transactions, domain.JournalTransaction{
+			Date:        value.CreatedAt,
+			Category:    names[value.CategoryID],
+			Name:        value.Name,
+			Count:       value.Count,
+			Description: value.Description,
+		})
+	}
+
+	return journal
+}
+
+// ImportJournal skips the transactions that already exist on the same day, so a journal is imported once.
+func (d *Db) ImportJournal(ctx context.Context, m domain.Member, journal domain.Journal) (domain.JournalImport, error) {
+	var res domain.JournalImport
+
+	err := d.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var user User
+		if err := tx.First(&user, m.UserID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrorNotFound
+			}
+			return err
+		}
+
+		if err := checkMember(tx, m, true); err != nil {
+			return err
+		}
+
+		ids := make(map[string]uint, len(journal.Categories))
+		for _, value := range journal.Categories {
+			var categor Category
+			err := tx.Where("name = ?", value.Name).First(&categor).Error
+			if err == nil {
+				if categor.HouseholdID != m.HouseholdID {
+					return ErrorDuplicated
+				}
+				ids[value.Name] = categor.ID
+				continue
+			}
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				return err
+			}
+
+			categor = Category{
+				Name:        value.Name,
+				UserID:      m.UserID,
+				HouseholdID: m.HouseholdID,
+				Limit:       value.Limit,
+				Type:        value.Type,
+				Description: value.Description,
+			}
+			if err := tx.Create(&categor).Error; err != nil {
+				if errors.Is(err, gorm.ErrDuplicatedKey) {
+					return ErrorDuplicated
+				}
+				return err
+			}
+			ids[value.Name] = categor.ID
+			res.Categories++
+		}
+
+		for _, value := range journal.Transactions {
+			categoryID, ok := ids[value.Category]
+			if !ok {
+				return ErrorNotFound
+			}
+
+			var count int64
+			err := tx.Model(&Transaction{}).
+				Where("household_id = ? AND category_id = ? AND name = ? AND count = ? AND created_at::date = ?",
+					m.HouseholdID, categoryID, value.Name, value.Count, value.Date.Format("2006-01-02")).
+				Count(&count).Error
+			if err != nil {
+				return err
+			}
+			if count != 0 {
+				res.Skipped++
+				continue
+			}
+
+			tran := Transaction{
+				Name:        value.Name,
+				UserID:      m.UserID,
+				HouseholdID: m.HouseholdID,
+				CategoryID:  categoryID,
+				Count:       value.Count,
+				Description: value.Description,
+			}
+			tran.CreatedAt = value.Date
+			if err := tx.Create(&tran).Error; err != nil {
+				return err
+			}
+			res.Transactions++
+		}
+
+		return nil
+	})
+	if err != nil {
+		return domain.JournalImport{}, err
+	}
+
+	return res, nil
+}
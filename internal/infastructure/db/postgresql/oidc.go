@@ -0,0 +1,140 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/financial_tracer/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (d *Db) CreateOIDCState(ctx context.Context, stateHash string, state domain.OIDCState, expiresAt time.Time) error {
+	return d.DB.WithContext(ctx).Create(&OidcState{
+		StateHash: stateHash,
+		Provider:  state.Provider,
+		Nonce:     state.Nonce,
+		Verifier:  state.Verifier,
+		ExpiresAt: expiresAt,
+	}).Error
+}
+
+// ConsumeOIDCState deletes the state, so it is used once.
+func (d *Db) ConsumeOIDCState(ctx context.Context, stateHash string, provider string, now time.Time) (domain.OIDCState, error) {
+	var state OidcState
+
+	err := d.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("state_hash = ? AND provider = ? AND expires_at > ?", stateHash, provider, now).
+			First(&state)
+		if result.Error != nil {
+			if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+				return ErrorNotFound
+			}
+			return result.Error
+		}
+
+		return tx.Where("id = ? OR expires_at <= ?", state.ID, now).Delete(&OidcState{}).Error
+	})
+	if err != nil {
+		return domain.OIDCState{}, err
+	}
+
+	return domain.OIDCState{
+		Provider: state.Provider,
+		Nonce:    state.Nonce,
+		Verifier: state.Verifier,
+	}, nil
+}
+
+func (d *Db) OIDCUser(ctx context.Context, provider string, subject string) (uint, string, error) {
+	var user User
+
+	result := d.DB.WithContext(ctx).
+		Joins("JOIN oidc_identities oi ON oi.user_id = users.id AND oi.deleted_at IS NULL").
+		Where("oi.provider = ? AND oi.subject = ?", provider, subject).
+		First(&user)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return 0, "", ErrorNotFound
+		}
+		return 0, "", result.Error
+	}
+
+	if user.DisabledAt != nil {
+		return 0, "", ErrorDisabled
+	}
+
+	return user.ID, user.Name, nil
+}
+
+// LinkOIDCUser links a local user only when its email is verified by both sides.
+func (d *Db) LinkOIDCUser(ctx context.Context, identity domain.OIDCIdentity, passwordHash []byte, register bool) (uint, string, error) {
+	if !identity.EmailVerified || identity.Email == "" {
+		return 0, "", ErrorUnverified
+	}
+
+	var user User
+
+	err := d.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+
+		result := tx.Where("lower(email) = ?", strings.ToLower(identity.Email)).First(&user)
+		switch {
+		case errors.Is(result.Error, gorm.ErrRecordNotFound):
+			if !register {
+				return ErrorNotFound
+			}
+			user = User{
+				Name:         oidcName(identity),
+				Email:        identity.Email,
+				PasswordHash: passwordHash,
+				VerifiedAt:   &now,
+			}
+			if err := tx.Create(&user).Error; err != nil {
+				return err
+			}
+			if err := createPersonalHousehold(tx, user.ID, user.Name); err != nil {
+				return err
+			}
+		case result.Error != nil:
+			return result.Error
+		case user.DisabledAt != nil:
+			return ErrorDisabled
+		case user.VerifiedAt == nil:
+			// anyone could have registered the email, the owner has to verify it first
+			return ErrorUnlinked
+		}
+
+		return tx.Create(&OidcIdentity{
+			UserID:   user.ID,
+			Provider: identity.Provider,
+			Subject:  identity.Subject,
+			Email:    identity.Email,
+		}).Error
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return 0, "", ErrorDuplicated
+		}
+		return 0, "", err
+	}
+
+	return user.ID, user.Name, nil
+}
+
+// oidcName falls back to the part of the email before @.
+func oidcName(identity domain.OIDCIdentity) string {
+	name := identity.Name
+	if name == "" {
+		name, _, _ = strings.Cut(identity.Email, "@")
+	}
+
+	if r := []rune(name); len(r) > 50 {
+		name = string(r[:50])
+	}
+
+	return name
+}
@@ -0,0 +1,142 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/financial_tracer/internal/lib/hashPassword"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ChangePassword keeps only the current session and revokes the access tokens.
+func (d *Db) ChangePassword(ctx context.Context, userID uint, sessionID uint, oldPassword string, newHash []byte) error {
+	return d.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var user User
+		result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", userID).First(&user)
+		if result.Error != nil {
+			if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+				return ErrorNotFound
+			}
+			return result.Error
+		}
+
+		if err := hashPassword.Compare(user.PasswordHash, oldPassword); err != nil {
+			return ErrorPassword
+		}
+
+		result = tx.Model(&user).Update("password_hash", newHash)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if err := tx.Where("user_id = ?", userID).Delete(&AccessToken{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&Session{}).
+			Where("user_id = ? AND id <> ? AND revoked_at IS NULL", userID, sessionID).
+			Update("revoked_at", time.Now()).Error
+	})
+}
+
+func (d *Db) CreatePasswordReset(ctx context.Context, email string, tokenHash string, expiresAt time.Time) (domain.User, error) {
+	var user User
+
+	result := d.DB.WithContext(ctx).Where("email = ?", email).First(&user)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return domain.User{}, ErrorNotFound
+		}
+		return domain.User{}, result.Error
+	}
+
+	reset := PasswordReset{
+		UserID:    user.ID,
+		TokenHash: tokenHash,
+		ExpiresAt: expiresAt,
+	}
+
+	result = d.DB.WithContext(ctx).Create(&reset)
+	if result.Error != nil {
+		return domain.User{}, result.Error
+	}
+
+	return domain.User{
+		Name:  user.Name,
+		Email: user.Email,
+	}, nil
+}
+
+// PasswordResetUser looks the user of the reset token up without using it.
+func (d *Db) PasswordResetUser(ctx context.Context, tokenHash string) (domain.User, error) {
+	var user User
+
+	result := d.DB.WithContext(ctx).
+		Joins("JOIN password_resets ON password_resets.user_id = users.id").
+		Where("password_resets.token_hash = ? AND password_resets.used_at IS NULL AND password_resets.expires_at > ?",
+			tokenHash, time.Now()).
+		First(&user)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return domain.User{}, ErrorNotFound
+		}
+		return domain.User{}, result.Error
+	}
+
+	return domain.User{
+		Name:  user.Name,
+		Email: user.Email,
+	}, nil
+}
+
+// ResetPassword revokes every session and access token of the user.
+func (d *Db) ResetPassword(ctx context.Context, tokenHash string, newHash []byte) (uint, error) {
+	var userID uint
+
+	err := d.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+
+		var reset PasswordReset
+		result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("token_hash = ? AND used_at IS NULL AND expires_at > ?", tokenHash, now).
+			First(&reset)
+		if result.Error != nil {
+			if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+				return ErrorNotFound
+			}
+			return result.Error
+		}
+		userID = reset.UserID
+
+		result = tx.Model(&PasswordReset{}).
+			Where("user_id = ? AND used_at IS NULL", reset.UserID).
+			Update("used_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		result = tx.Model(&User{}).Where("id = ?", reset.UserID).Update("password_hash", newHash)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrorNotFound
+		}
+
+		if err := tx.Where("user_id = ?", reset.UserID).Delete(&AccessToken{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&Session{}).
+			Where("user_id = ? AND revoked_at IS NULL", reset.UserID).
+			Update("revoked_at", now).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return userID, nil
+}
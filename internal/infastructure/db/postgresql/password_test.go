@@ -0,0 +1,49 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/financial_tracer/internal/lib/hashPassword"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPasswordRevokesAccessTokens(t *testing.T) {
+	d := testDb(t)
+	ctx := context.Background()
+	suffix := fmt.Sprint(time.Now().UnixNano())
+
+	hash, err := hashPassword.Hash("admin12241532")
+	require.NoError(t, err)
+	user := User{Name: "jonn", Email: "jonn" + suffix + "@tracker.local", PasswordHash: hash}
+	require.NoError(t, d.DB.Create(&user).Error)
+	t.Cleanup(func() {
+		d.DB.Unscoped().Where("user_id = ?", user.ID).Delete(&AccessToken{})
+		d.DB.Unscoped().Where("user_id = ?", user.ID).Delete(&PasswordReset{})
+		d.DB.Unscoped().Delete(&User{}, user.ID)
+	})
+
+	createToken := func() {
+		token := AccessToken{UserID: user.ID, Name: "ci", Prefix: "ft_pat_", TokenHash: "hash" + fmt.Sprint(time.Now().UnixNano()), Scopes: "reports:read"}
+		require.NoError(t, d.DB.Create(&token).Error)
+	}
+	tokens := func() int64 {
+		var n int64
+		require.NoError(t, d.DB.Model(&AccessToken{}).Where("user_id = ?", user.ID).Count(&n).Error)
+		return n
+	}
+
+	createToken()
+	require.NoError(t, d.ChangePassword(ctx, user.ID, 0, "admin12241532", []byte("new hash")))
+	assert.Zero(t, tokens())
+
+	createToken()
+	_, err = d.CreatePasswordReset(ctx, user.Email, "reset"+suffix, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	_, err = d.ResetPassword(ctx, "reset"+suffix, []byte("newer hash"))
+	require.NoError(t, err)
+	assert.Zero(t, tokens())
+}
@@ -0,0 +1,124 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/financial_tracer/internal/lib/hashPassword"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (d *Db) UserProfile(ctx context.Context, userID uint) (domain.Profile, error) {
+	var user User
+
+	result := d.DB.WithContext(ctx).Where("id = ?", userID).First(&user)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return domain.Profile{}, ErrorNotFound
+		}
+		return domain.Profile{}, result.Error
+	}
+
+	return domain.Profile{
+		ID:          user.ID,
+		Name:        user.Name,
+		Email:       user.Email,
+		Role:        user.Role,
+		Verified:    user.VerifiedAt != nil,
+		TwoFactor:   user.TOTPEnabledAt != nil,
+		CreatedAt:   user.CreatedAt,
+		Preferences: preferences(user),
+	}, nil
+}
+
+func (d *Db) UserPreferences(ctx context.Context, userID uint) (domain.Preferences, error) {
+	var user User
+
+	result := d.DB.WithContext(ctx).
+		Select("id", "currency", "timezone", "locale", "week_start", "month_start").
+		Where("id = ?", userID).First(&user)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return domain.Preferences{}, ErrorNotFound
+		}
+		return domain.Preferences{}, result.Error
+	}
+
+	return preferences(user), nil
+}
+
+func (d *Db) UpdatePreferences(ctx context.Context, userID uint, pref domain.Preferences) error {
+	result := d.DB.WithContext(ctx).Model(&User{}).Where("id = ?", userID).Updates(map[string]any{
+		"currency":    pref.Currency,
+		"timezone":    pref.Timezone,
+		"locale":      pref.Locale,
+		"week_start":  pref.WeekStart,
+		"month_start": pref.MonthStart,
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrorNotFound
+	}
+
+	return nil
+}
+
+func (d *Db) UpdateName(ctx context.Context, userID uint, name string) error {
+	result := d.DB.WithContext(ctx).Model(&User{}).Where("id = ?", userID).Update("name", name)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrorNotFound
+	}
+
+	return nil
+}
+
+// ChangeEmail invalidates the verification tokens mailed to the old email.
+func (d *Db) ChangeEmail(ctx context.Context, userID uint, password string, email string) error {
+	return d.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var user User
+		result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", userID).First(&user)
+		if result.Error != nil {
+			if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+				return ErrorNotFound
+			}
+			return result.Error
+		}
+
+		if err := hashPassword.Compare(user.PasswordHash, password); err != nil {
+			return ErrorPassword
+		}
+
+		result = tx.Model(&user).Updates(map[string]any{
+			"email":       email,
+			"verified_at": nil,
+		})
+		if result.Error != nil {
+			if errors.Is(result.Error, gorm.ErrDuplicatedKey) {
+				return ErrorDuplicated
+			}
+			return result.Error
+		}
+
+		return tx.Model(&EmailVerification{}).
+			Where("user_id = ? AND used_at IS NULL", userID).
+			Update("used_at", time.Now()).Error
+	})
+}
+
+func preferences(user User) domain.Preferences {
+	return domain.Preferences{
+		Currency:   user.Currency,
+		Timezone:   user.Timezone,
+		Locale:     user.Locale,
+		WeekStart:  user.WeekStart,
+		MonthStart: user.MonthStart,
+	}
+}
@@ -0,0 +1,98 @@
+package postgresql
+
+import (
+	"context"
+	"time"
+
+	"github.com/financial_tracer/internal/domain"
+)
+
+func (d *Db) HouseholdCategories(ctx context.Context, m domain.Member) ([]domain.CategoryRecord, error) {
+	if err := checkMember(d.DB.WithContext(ctx), m, false); err != nil {
+		return nil, err
+	}
+
+	var categories []Category
+
+	result := d.DB.WithContext(ctx).Scopes(memberOf("categories", m, false)).Order("id").Find(&categories)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	arr := make([]domain.CategoryRecord, 0, len(categories))
+	for _, value := range categories {
+		arr = append(arr, domain.CategoryRecord{
+			ID: value.ID,
+			CategoryOutput: domain.CategoryOutput{
+				UserID:      value.UserID,
+				HouseholdID: value.HouseholdID,
+				Name:        value.Name,
+				Limit:       value.Limit,
+				Type:        value.Type,
+				Description: value.Description,
+			},
+		})
+	}
+
+	return arr, nil
+}
+
+// HouseholdTransactions returns transactions of the household created in [from, to).
+func (d *Db) HouseholdTransactions(ctx context.Context, m domain.Member, from time.Time, to time.Time) ([]domain.TransactionRecord, error) {
+	if err := checkMember(d.DB.WithContext(ctx), m, false); err != nil {
+		return nil, err
+	}
+
+	var transactions []Transaction
+
+	result := d.DB.WithContext(ctx).
+		Scopes(memberOf("transactions", m, false)).
+		Where("created_at >= ? AND created_at < ?", from, to).
+		Order("created_at, id").
+		Find(&transactions)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	arr := make([]domain.TransactionRecord, 0, len(transactions))
+	for _, value := range transactions {
+		arr = append(arr, domain.TransactionRecord{
+			ID:   value.ID,
+			Date: value.CreatedAt,
+			TransactionOutput: domain.TransactionOutput{
+				UserID:      value.UserID,
+				HouseholdID: value.HouseholdID,
+				CategoryID:  value.CategoryID,
+				Name:        value.Name,
+				Count:       value.Count,
+				Description: value.Description,
+			},
+		})
+	}
+
+	return arr, nil
+}
+
+// CategoryTotals returns the spending of the household in [from, to) summed per category.
+func (d *Db) CategoryTotals(ctx context.Context, m domain.Member, from time.Time, to time.Time) ([]domain.CategoryTotal, error) {
+	if err := checkMember(d.DB.WithContext(ctx), m, false); err != nil {
+		return nil, err
+	}
+
+	var totals []domain.CategoryTotal
+
+	result := d.DB.WithContext(ctx).
+		Model(&Transaction{}).
+		Select("transactions.category_id, categories.name, SUM(transactions.count) AS total, COUNT(*) AS transactions").
+		Joins("JOIN categories ON categories.id = transactions.category_id").
+		Scopes(memberOf("transactions", m, false)).
+		Where("transactions.created_at >= ? AND transactions.created_at < ?", from, to).
+		Group("transactions.category_id, categories.name").
+		Order("transactions.category_id").
+		Scan(&totals)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return totals, nil
+}
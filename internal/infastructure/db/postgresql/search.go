@@ -0,0 +1,145 @@
+package postgresql
+
+import (
+	"context"
+	"html"
+	"strings"
+	"time"
+
+	"github.com/financial_tracer/internal/domain"
+	"gorm.io/gorm"
+)
+
+// searchMigration adds the search column generated by Postgres, gorm never writes it.
+const searchMigration = `
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS search tsvector GENERATED ALWAYS AS (
+	setweight(to_tsvector('russian', coalesce(name, '')), 'A') ||
+	setweight(to_tsvector('english', coalesce(name, '')), 'A') ||
+	setweight(to_tsvector('russian', coalesce(description, '')), 'B') ||
+	setweight(to_tsvector('english', coalesce(description, '')), 'B')
+) STORED;
+CREATE INDEX IF NOT EXISTS idx_transactions_search ON transactions USING GIN (search);
+`
+
+// searchQuery highlights in two passes, one per configuration of the match.
+const searchQuery = `
+SELECT t.id, t.created_at, t.user_id, t.household_id, t.category_id, t.name, t.count, t.description,
+	ts_rank(t.search, q.ru || q.en) AS rank,
+	ts_headline('english', ts_headline('russian', translate(t.name, @marks, ''), q.ru, @all), q.en, @all)
+		AS name_highlight,
+	CASE WHEN to_tsvector('russian', coalesce(t.description, '')) @@ q.ru
+		THEN ts_headline('english', ts_headline('russian', translate(t.description, @marks, ''), q.ru, @fragment), q.en, @all)
+		ELSE ts_headline('russian', ts_headline('english', translate(t.description, @marks, ''), q.en, @fragment), q.ru, @all)
+	END AS description_highlight
+FROM transactions t,
+	(SELECT websearch_to_tsquery('russian', @query) AS ru, websearch_to_tsquery('english', @query) AS en) q
+WHERE t.household_id = @household AND t.deleted_at IS NULL AND t.search @@ (q.ru || q.en)
+	AND EXISTS (SELECT 1 FROM household_members hm WHERE hm.household_id = @household AND hm.user_id = @user)
+ORDER BY rank DESC, t.created_at DESC, t.id DESC
+LIMIT @limit OFFSET @offset
+`
+
+const (
+	highlightStart = "\x02"
+	highlightStop  = "\x03"
+
+	highlightOptions = "StartSel=" + highlightStart + ", StopSel=" + highlightStop
+)
+
+func migrateSearch(db *gorm.DB) error {
+	return db.Exec(searchMigration).Error
+}
+
+type searchRow struct {
+	ID                   uint
+	CreatedAt            time.Time
+	UserID               uint
+	HouseholdID          uint
+	CategoryID           uint
+	Name                 string
+	Count                int
+	Description          string
+	Rank                 float64
+	NameHighlight        string
+	DescriptionHighlight string
+}
+
+func (d *Db) SearchTransactions(ctx context.Context, m domain.Member, query domain.SearchQuery) ([]domain.TransactionSearchResult, error) {
+	if err := checkMember(d.DB.WithContext(ctx), m, false); err != nil {
+		return nil, err
+	}
+
+	var rows []searchRow
+
+	result := d.DB.WithContext(ctx).Raw(searchQuery, map[string]any{
+		"query":     query.Query,
+		"marks":     highlightStart + highlightStop,
+		"all":       "HighlightAll=true, " + highlightOptions,
+		"fragment":  highlightOptions,
+		"user":      m.UserID,
+		"household": m.HouseholdID,
+		"limit":     query.Limit,
+		"offset":    query.Offset,
+	}).Scan(&rows)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	arr := make([]domain.TransactionSearchResult, 0, len(rows))
+	for _, row := range rows {
+		arr = append(arr, domain.TransactionSearchResult{
+			TransactionRecord: domain.TransactionRecord{
+				ID:   row.ID,
+				Date: row.CreatedAt,
+				TransactionOutput: domain.TransactionOutput{
+					UserID:      row.UserID,
+					HouseholdID: row.HouseholdID,
+					CategoryID:  row.CategoryID,
+					Name:        row.Name,
+					Count:       row.Count,
+					Description: row.Description,
+				},
+			},
+			Rank:                 row.Rank,
+			NameHighlight:        highlight(row.NameHighlight),
+			DescriptionHighlight: highlight(row.DescriptionHighlight),
+		})
+	}
+
+	return arr, nil
+}
+
+// highlight escapes the headline and turns its markers into <b></b>.
+func highlight(s string) string {
+	var b strings.Builder
+	depth := 0
+	for s != "" {
+		i := strings.IndexAny(s, highlightStart+highlightStop)
+		if i < 0 {
+			b.WriteString(html.EscapeString(s))
+			break
+		}
+		b.WriteString(html.EscapeString(s[:i]))
+
+		switch s[i : i+1] {
+		case highlightStart:
+			if depth == 0 {
+				b.WriteString("<b>")
+			}
+			depth++
+		case highlightStop:
+			if depth == 1 {
+				b.WriteString("</b>")
+			}
+			if depth > 0 {
+				depth--
+			}
+		}
+		s = s[i+1:]
+	}
+	if depth > 0 {
+		b.WriteString("</b>")
+	}
+
+	return b.String()
+}
@@ -0,0 +1,42 @@
+package postgresql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHighlight(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{
+			name: "plain",
+			src:  "стоматолог",
+			want: "стоматолог",
+		},
+		{
+			name: "match",
+			src:  "визит к \x02стоматологу\x03",
+			want: "визит к <b>стоматологу</b>",
+		},
+		{
+			name: "escape",
+			src:  "<script>\x02dentist\x03</script> & co",
+			want: "&lt;script&gt;<b>dentist</b>&lt;/script&gt; &amp; co",
+		},
+		{
+			name: "both configurations",
+			src:  "\x02\x02dentist\x03\x03 visit",
+			want: "<b>dentist</b> visit",
+		},
+	}
+
+	for _, ts := range tests {
+		t.Run(ts.name, func(t *testing.T) {
+			assert.Equal(t, ts.want, highlight(ts.src))
+		})
+	}
+}
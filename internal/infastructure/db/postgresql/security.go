@@ -0,0 +1,123 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/financial_tracer/internal/domain"
+	"gorm.io/gorm"
+)
+
+// CreateSecurityEvent marks a login with an unknown user agent and IP as from a new device.
+func (d *Db) CreateSecurityEvent(ctx context.Context, event domain.SecurityEvent) (domain.SecurityEvent, error) {
+	value := SecurityEvent{
+		UserID:    event.UserID,
+		Email:     strings.ToLower(strings.TrimSpace(event.Email)),
+		Type:      event.Type,
+		Success:   event.Success,
+		Reason:    event.Reason,
+		UserAgent: event.UserAgent,
+		IP:        event.IP,
+		CreatedAt: event.CreatedAt,
+	}
+	if value.CreatedAt.IsZero() {
+		value.CreatedAt = time.Now()
+	}
+
+	db := d.DB.WithContext(ctx)
+
+	if value.UserID == 0 && value.Email != "" {
+		var user User
+		result := db.Select("id").Where("LOWER(email) = ?", value.Email).First(&user)
+		if result.Error != nil && !errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return domain.SecurityEvent{}, result.Error
+		}
+		value.UserID = user.ID
+	}
+
+	if value.UserID != 0 && value.Type == domain.EventLogin && value.Success {
+		logins, err := countLogins(db.Where("user_id = ?", value.UserID))
+		if err != nil {
+			return domain.SecurityEvent{}, err
+		}
+		known, err := countLogins(db.Where("user_id = ? AND user_agent = ? AND ip = ?", value.UserID, value.UserAgent, value.IP))
+		if err != nil {
+			return domain.SecurityEvent{}, err
+		}
+		value.NewDevice = logins > 0 && known == 0
+	}
+
+	if err := db.Create(&value).Error; err != nil {
+		return domain.SecurityEvent{}, err
+	}
+
+	return securityEvent(value), nil
+}
+
+func countLogins(query *gorm.DB) (int64, error) {
+	var count int64
+	err := query.Model(&SecurityEvent{}).
+		Where("type = ? AND success", domain.EventLogin).
+		Count(&count).Error
+	return count, err
+}
+
+func (d *Db) SecurityEvents(ctx context.Context, filter domain.SecurityEventFilter) (domain.SecurityEventList, error) {
+	query := d.DB.WithContext(ctx).Model(&SecurityEvent{})
+
+	if filter.UserID != 0 {
+		query = query.Where("user_id = ?", filter.UserID)
+	}
+	if filter.Type != "" {
+		query = query.Where("type = ?", filter.Type)
+	}
+	if filter.Success != nil {
+		query = query.Where("success = ?", *filter.Success)
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("created_at >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("created_at < ?", filter.To)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return domain.SecurityEventList{}, err
+	}
+
+	var events []SecurityEvent
+	result := query.Order("created_at DESC, id DESC").Limit(filter.Limit).Offset(filter.Offset).Find(&events)
+	if result.Error != nil {
+		return domain.SecurityEventList{}, result.Error
+	}
+
+	list := domain.SecurityEventList{
+		Events: make([]domain.SecurityEvent, 0, len(events)),
+		Total:  total,
+	}
+	for _, value := range events {
+		list.Events = append(list.Events, securityEvent(value))
+	}
+
+	return list, nil
+}
+
+func securityEvent(value SecurityEvent) domain.SecurityEvent {
+	return domain.SecurityEvent{
+		ID:        value.ID,
+		UserID:    value.UserID,
+		Email:     value.Email,
+		Type:      value.Type,
+		Success:   value.Success,
+		Reason:    value.Reason,
+		NewDevice: value.NewDevice,
+		Device: domain.Device{
+			UserAgent: value.UserAgent,
+			IP:        value.IP,
+		},
+		CreatedAt: value.CreatedAt,
+	}
+}
@@ -0,0 +1,144 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/financial_tracer/internal/domain"
+	"gorm.io/gorm"
+)
+
+// CreateSession also cancels the scheduled deletion of the user.
+func (d *Db) CreateSession(ctx context.Context, userID uint, jti string, expiresAt time.Time, device domain.Device) (uint, error) {
+	session := Session{
+		UserID:     userID,
+		RefreshJti: jti,
+		UserAgent:  device.UserAgent,
+		IP:         device.IP,
+		LastUsedAt: time.Now(),
+		ExpiresAt:  expiresAt,
+	}
+
+	err := d.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&session).Error; err != nil {
+			return err
+		}
+
+		return cancelDeletion(tx, userID)
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return 0, ErrorDuplicated
+		}
+		return 0, err
+	}
+
+	return session.ID, nil
+}
+
+// RotateSession revokes the whole session when an already rotated jti is reused.
+func (d *Db) RotateSession(ctx context.Context, userID uint, sessionID uint, jti string, newJti string, expiresAt time.Time, device domain.Device) error {
+	reused := false
+
+	err := d.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+
+		result := tx.Model(&Session{}).
+			Where("id = ? AND user_id = ? AND refresh_jti = ? AND revoked_at IS NULL AND expires_at > ?", sessionID, userID, jti, now).
+			Updates(map[string]any{
+				"refresh_jti":  newJti,
+				"expires_at":   expiresAt,
+				"user_agent":   device.UserAgent,
+				"ip":           device.IP,
+				"last_used_at": now,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 1 {
+			return nil
+		}
+
+		var session Session
+		result = tx.Where("id = ? AND user_id = ?", sessionID, userID).First(&session)
+		if result.Error != nil {
+			if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+				return ErrorNotFound
+			}
+			return result.Error
+		}
+
+		if session.RevokedAt != nil || !session.ExpiresAt.After(now) {
+			return ErrorRevoked
+		}
+
+		reused = true
+
+		return tx.Model(&session).Update("revoked_at", now).Error
+	})
+	if err != nil {
+		return err
+	}
+	if reused {
+		return ErrorReused
+	}
+
+	return nil
+}
+
+func (d *Db) RevokeSession(ctx context.Context, userID uint, sessionID uint) error {
+	result := d.DB.WithContext(ctx).Model(&Session{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", sessionID, userID).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrorNotFound
+	}
+
+	return nil
+}
+
+func (d *Db) UserSessions(ctx context.Context, userID uint) ([]domain.Session, error) {
+	var sessions []Session
+
+	result := d.DB.WithContext(ctx).
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Order("last_used_at DESC").
+		Find(&sessions)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	arr := make([]domain.Session, 0, len(sessions))
+	for _, value := range sessions {
+		arr = append(arr, domain.Session{
+			ID: value.ID,
+			Device: domain.Device{
+				UserAgent: value.UserAgent,
+				IP:        value.IP,
+			},
+			CreatedAt:  value.CreatedAt,
+			LastUsedAt: value.LastUsedAt,
+			ExpiresAt:  value.ExpiresAt,
+		})
+	}
+
+	return arr, nil
+}
+
+func (d *Db) SessionActive(ctx context.Context, userID uint, sessionID uint) (bool, error) {
+	var count int64
+
+	result := d.DB.WithContext(ctx).Model(&Session{}).
+		Joins("JOIN users ON users.id = sessions.user_id AND users.deleted_at IS NULL AND users.disabled_at IS NULL AND users.deletion_at IS NULL").
+		Where("sessions.id = ? AND sessions.user_id = ? AND sessions.revoked_at IS NULL AND sessions.expires_at > ?", sessionID, userID, time.Now()).
+		Count(&count)
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return count == 1, nil
+}
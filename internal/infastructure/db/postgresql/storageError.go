@@ -3,7 +3,19 @@ package postgresql
 import "errors"
 
 var (
-	ErrorNotFound   = errors.New("not found")
-	ErrorDuplicated = errors.New("duplicated unique")
-	ErrorLimit      = errors.New("error limit transaction")
+	ErrorNotFound     = errors.New("not found")
+	ErrorDuplicated   = errors.New("duplicated unique")
+	ErrorLimit        = errors.New("error limit transaction")
+	ErrorRevoked      = errors.New("session revoked or expired")
+	ErrorReused       = errors.New("token reused")
+	ErrorPassword     = errors.New("wrong password")
+	ErrorVerified     = errors.New("email already verified")
+	ErrorTwoFactorOn  = errors.New("two-factor authentication already enabled")
+	ErrorTwoFactorOff = errors.New("two-factor authentication is not enabled")
+	ErrorDisabled     = errors.New("user disabled")
+	ErrorForbidden    = errors.New("not allowed in the household")
+	ErrorPersonal     = errors.New("personal household can't be shared")
+	ErrorUnverified   = errors.New("email is not verified by the identity provider")
+	ErrorInvite       = errors.New("invite code is invalid, expired or used up")
+	ErrorUnlinked     = errors.New("email of the local user is not verified")
 )
@@ -8,52 +8,58 @@ import (
 	"gorm.io/gorm"
 )
 
-func (d *Db) CreateTransaction(ctx context.Context, idUser uint, idCategory uint, tran domain.TransactionInput) (uint, error) {
-
-	tx := d.DB.WithContext(ctx).Begin()
+func (d *Db) CreateTransaction(ctx context.Context, m domain.Member, idCategory uint, tran domain.TransactionInput) (uint, error) {
 	newTransaction := Transaction{
-		UserID:      idUser,
+		UserID:      m.UserID,
+		HouseholdID: m.HouseholdID,
 		CategoryID:  idCategory,
 		Name:        tran.Name,
 		Count:       tran.Count,
 		Description: tran.Description,
 	}
-	var categor Category
-	result := tx.Select("limit").First(&categor, idCategory)
-	if result.Error != nil {
-		tx.Rollback()
-		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			return 0, ErrorNotFound
+
+	return d.createTransaction(ctx, m, newTransaction)
+}
+
+func (d *Db) createTransaction(ctx context.Context, m domain.Member, newTransaction Transaction) (uint, error) {
+	idCategory := newTransaction.CategoryID
+
+	err := d.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var categor Category
+		result := tx.Select("limit").Scopes(memberOf("categories", m, true)).First(&categor, idCategory)
+		if result.Error != nil {
+			if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+				return notFound(tx, m, true)
+			}
+			return result.Error
+		}
+		if categor.Limit < newTransaction.Count {
+			return ErrorLimit
 		}
-		return 0, result.Error
-	}
-	if categor.Limit < newTransaction.Count {
-		return 0, ErrorLimit
-	}
 
-	result = tx.Create(&newTransaction)
-	if result.Error != nil {
-		tx.Rollback()
-		return 0, result.Error
+		return tx.Create(&newTransaction).Error
+	})
+	if err != nil {
+		return 0, err
 	}
 
-	return newTransaction.ID, tx.Commit().Error
-
+	return newTransaction.ID, nil
 }
 
-func (d *Db) GetTransaction(ctx context.Context, TransactionId uint) (domain.TransactionOutput, error) {
+func (d *Db) GetTransaction(ctx context.Context, m domain.Member, TransactionId uint) (domain.TransactionOutput, error) {
 	var tran Transaction
 
-	result := d.DB.WithContext(ctx).First(&tran, TransactionId)
+	result := d.DB.WithContext(ctx).Scopes(memberOf("transactions", m, false)).First(&tran, TransactionId)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			return domain.TransactionOutput{}, ErrorNotFound
+			return domain.TransactionOutput{}, notFound(d.DB.WithContext(ctx), m, false)
 		}
 		return domain.TransactionOutput{}, result.Error
 	}
 
 	transaction := domain.TransactionOutput{
 		UserID:      tran.UserID,
+		HouseholdID: tran.HouseholdID,
 		CategoryID:  tran.CategoryID,
 		Name:        tran.Name,
 		Count:       tran.Count,
@@ -62,22 +68,28 @@ func (d *Db) GetTransaction(ctx context.Context, TransactionId uint) (domain.Tra
 	return transaction, nil
 }
 
-func (d *Db) UpdateTransaction(ctx context.Context, transactionId uint, newTransaction domain.TransactionInput) (domain.TransactionOutput, error) {
-	var transaction = Transaction{
-		Name:        newTransaction.Name,
-		Count:       newTransaction.Count,
-		Description: newTransaction.Description,
-	}
+func (d *Db) UpdateTransaction(ctx context.Context, m domain.Member, transactionId uint, newTransaction domain.TransactionInput) (domain.TransactionOutput, error) {
+	var transaction Transaction
 
-	result := d.DB.WithContext(ctx).Where("id = ?", transactionId).Updates(&transaction)
+	result := d.DB.WithContext(ctx).Scopes(memberOf("transactions", m, true)).First(&transaction, transactionId)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			return domain.TransactionOutput{}, ErrorNotFound
+			return domain.TransactionOutput{}, notFound(d.DB.WithContext(ctx), m, true)
 		}
 		return domain.TransactionOutput{}, result.Error
 	}
+
+	result = d.DB.WithContext(ctx).Model(&transaction).Updates(Transaction{
+		Name:        newTransaction.Name,
+		Count:       newTransaction.Count,
+		Description: newTransaction.Description,
+	})
+	if result.Error != nil {
+		return domain.TransactionOutput{}, result.Error
+	}
 	res := domain.TransactionOutput{
 		UserID:      transaction.UserID,
+		HouseholdID: transaction.HouseholdID,
 		CategoryID:  transaction.CategoryID,
 		Name:        transaction.Name,
 		Count:       transaction.Count,
@@ -87,15 +99,15 @@ func (d *Db) UpdateTransaction(ctx context.Context, transactionId uint, newTrans
 	return res, nil
 }
 
-func (d *Db) DeleteTransaction(ctx context.Context, transactionId uint) error {
+func (d *Db) DeleteTransaction(ctx context.Context, m domain.Member, transactionId uint) error {
 
-	result := d.DB.WithContext(ctx).Delete(&Transaction{}, transactionId)
+	result := d.DB.WithContext(ctx).Scopes(memberOf("transactions", m, true)).Delete(&Transaction{}, transactionId)
 	if result.Error != nil {
-		if result.RowsAffected == 0 {
-			return ErrorNotFound
-		}
 		return result.Error
 	}
+	if result.RowsAffected == 0 {
+		return notFound(d.DB.WithContext(ctx), m, true)
+	}
 
 	return nil
 }
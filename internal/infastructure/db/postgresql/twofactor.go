@@ -0,0 +1,195 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/financial_tracer/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (d *Db) EnrollTwoFactor(ctx context.Context, userID uint, secret string) (domain.User, error) {
+	var user User
+
+	result := d.DB.WithContext(ctx).Where("id = ?", userID).First(&user)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return domain.User{}, ErrorNotFound
+		}
+		return domain.User{}, result.Error
+	}
+
+	if user.TOTPEnabledAt != nil {
+		return domain.User{}, ErrorTwoFactorOn
+	}
+
+	result = d.DB.WithContext(ctx).Model(&user).Update("totp_pending_secret", secret)
+	if result.Error != nil {
+		return domain.User{}, result.Error
+	}
+
+	return domain.User{
+		Name:  user.Name,
+		Email: user.Email,
+	}, nil
+}
+
+func (d *Db) PendingTwoFactor(ctx context.Context, userID uint) (string, error) {
+	var user User
+
+	result := d.DB.WithContext(ctx).Where("id = ?", userID).First(&user)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return "", ErrorNotFound
+		}
+		return "", result.Error
+	}
+
+	if user.TOTPEnabledAt != nil {
+		return "", ErrorTwoFactorOn
+	}
+	if user.TOTPPendingSecret == "" {
+		return "", ErrorTwoFactorOff
+	}
+
+	return user.TOTPPendingSecret, nil
+}
+
+// ConfirmTwoFactor replaces the recovery codes, step is the time step of the confirming code.
+func (d *Db) ConfirmTwoFactor(ctx context.Context, userID uint, secret string, step int64, codeHashes []string) error {
+	return d.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&User{}).
+			Where("id = ? AND totp_pending_secret = ? AND totp_enabled_at IS NULL", userID, secret).
+			Updates(map[string]any{
+				"totp_secret":         secret,
+				"totp_pending_secret": "",
+				"totp_enabled_at":     time.Now(),
+				"totp_last_step":      step,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrorTwoFactorOff
+		}
+
+		result = tx.Unscoped().Where("user_id = ?", userID).Delete(&RecoveryCode{})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		codes := make([]RecoveryCode, 0, len(codeHashes))
+		for _, hash := range codeHashes {
+			codes = append(codes, RecoveryCode{UserID: userID, CodeHash: hash})
+		}
+
+		return tx.Create(&codes).Error
+	})
+}
+
+func (d *Db) TwoFactor(ctx context.Context, userID uint) (domain.TwoFactor, error) {
+	var user User
+
+	result := d.DB.WithContext(ctx).Where("id = ?", userID).First(&user)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return domain.TwoFactor{}, ErrorNotFound
+		}
+		return domain.TwoFactor{}, result.Error
+	}
+
+	if user.TOTPEnabledAt == nil {
+		return domain.TwoFactor{}, ErrorTwoFactorOff
+	}
+
+	return domain.TwoFactor{
+		Secret:   user.TOTPSecret,
+		LastStep: user.TOTPLastStep,
+	}, nil
+}
+
+// UseTOTPStep returns ErrorReused for a step at or before the last accepted one.
+func (d *Db) UseTOTPStep(ctx context.Context, userID uint, step int64) error {
+	result := d.DB.WithContext(ctx).Model(&User{}).
+		Where("id = ? AND totp_last_step < ?", userID, step).
+		Update("totp_last_step", step)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrorReused
+	}
+
+	return nil
+}
+
+func (d *Db) UseRecoveryCode(ctx context.Context, userID uint, codeHash string) error {
+	result := d.DB.WithContext(ctx).Model(&RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrorNotFound
+	}
+
+	return nil
+}
+
+func (d *Db) DisableTwoFactor(ctx context.Context, userID uint) error {
+	return d.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&User{}).
+			Where("id = ? AND totp_enabled_at IS NOT NULL", userID).
+			Updates(map[string]any{
+				"totp_secret":     "",
+				"totp_enabled_at": nil,
+				"totp_last_step":  0,
+				"totp_challenge":  "",
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrorTwoFactorOff
+		}
+
+		return tx.Unscoped().Where("user_id = ?", userID).Delete(&RecoveryCode{}).Error
+	})
+}
+
+// StartTwoFactor stores jti as the login challenge when two-factor authentication is on.
+func (d *Db) StartTwoFactor(ctx context.Context, userID uint, jti string) (bool, error) {
+	result := d.DB.WithContext(ctx).Model(&User{}).
+		Where("id = ? AND totp_enabled_at IS NOT NULL", userID).
+		Update("totp_challenge", jti)
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected != 0, nil
+}
+
+// ConsumeTwoFactorChallenge spends the challenge whatever the code sent with it.
+func (d *Db) ConsumeTwoFactorChallenge(ctx context.Context, userID uint, jti string) error {
+	if jti == "" {
+		return ErrorNotFound
+	}
+
+	return d.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var user User
+		result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND totp_challenge = ? AND totp_enabled_at IS NOT NULL", userID, jti).
+			First(&user)
+		if result.Error != nil {
+			if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+				return ErrorNotFound
+			}
+			return result.Error
+		}
+
+		return tx.Model(&user).Update("totp_challenge", "").Error
+	})
+}
@@ -3,13 +3,15 @@ package postgresql
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/financial_tracer/internal/domain"
-	"golang.org/x/crypto/bcrypt"
+	"github.com/financial_tracer/internal/lib/hashPassword"
 	"gorm.io/gorm"
 )
 
-func (d *Db) RegistrationUser(ctx context.Context, user domain.User) (uint, string, error) {
+// RegistrationUser uses the invite code in the same transaction.
+func (d *Db) RegistrationUser(ctx context.Context, user domain.User, inviteHash string) (uint, string, error) {
 
 	userDb := User{
 		Name:         user.Name,
@@ -17,16 +19,29 @@ func (d *Db) RegistrationUser(ctx context.Context, user domain.User) (uint, stri
 		PasswordHash: user.PasswordHash[:],
 	}
 
-	result := d.DB.WithContext(ctx).Create(&userDb)
-	if result.Error != nil {
-		if errors.Is(result.Error, gorm.ErrDuplicatedKey) {
+	err := d.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if inviteHash != "" {
+			if err := useInviteCode(tx, inviteHash, time.Now()); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Create(&userDb).Error; err != nil {
+			return err
+		}
+
+		return createPersonalHousehold(tx, userDb.ID, userDb.Name)
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
 			return 0, "", ErrorDuplicated
 		}
-		return 0, "", result.Error
+		return 0, "", err
 	}
 	return userDb.ID, user.Name, nil
 }
 
+// AuthenticationUser rehashes a bcrypt or outdated argon2id hash after the check.
 func (d *Db) AuthenticationUser(ctx context.Context, email string, password string) (uint, string, error) {
 
 	var user User
@@ -39,35 +54,33 @@ func (d *Db) AuthenticationUser(ctx context.Context, email string, password stri
 		return 0, "", result.Error
 	}
 
-	err := bcrypt.CompareHashAndPassword(user.PasswordHash, []byte(password))
+	err := hashPassword.Compare(user.PasswordHash, password)
 	if err != nil {
+		if errors.Is(err, hashPassword.ErrMismatched) {
+			return 0, "", ErrorPassword
+		}
 		return 0, "", err
 	}
 
+	if user.DisabledAt != nil {
+		return 0, "", ErrorDisabled
+	}
+
+	if hashPassword.NeedsRehash(user.PasswordHash) {
+		d.rehashPassword(ctx, user, password)
+	}
+
 	return user.ID, user.Name, nil
 }
 
-func (d *Db) DeleteUser(ctx context.Context, email string, passwordHash string) error {
-	var user User
-	result := d.DB.WithContext(ctx).Where("email = ?", email).First(&user)
-	if result.Error != nil {
-		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			return ErrorNotFound
-		}
-		return result.Error
-	}
-	err := bcrypt.CompareHashAndPassword(user.PasswordHash, []byte(passwordHash))
+// rehashPassword skips the update when the hash was changed meanwhile.
+func (d *Db) rehashPassword(ctx context.Context, user User, password string) {
+	hash, err := hashPassword.Hash(password)
 	if err != nil {
-		return err
-	}
-
-	result = d.DB.WithContext(ctx).Select("Transactions", "Categories").Where("id = ?", user.ID).Delete(&user)
-	if result.Error != nil {
-		return result.Error
-	}
-	if result.RowsAffected == 0 {
-		return ErrorNotFound
+		return
 	}
 
-	return nil
+	d.DB.WithContext(ctx).Model(&User{}).
+		Where("id = ? AND password_hash = ?", user.ID, user.PasswordHash).
+		Update("password_hash", hash)
 }
@@ -0,0 +1,103 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/financial_tracer/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// migrateVerification keeps the users registered before email verification verified.
+func migrateVerification(db *gorm.DB) error {
+	return db.Model(&User{}).Where("verified_at IS NULL").Update("verified_at", gorm.Expr("created_at")).Error
+}
+
+func (d *Db) CreateEmailVerification(ctx context.Context, userID uint, tokenHash string, expiresAt time.Time) (domain.User, error) {
+	var user User
+
+	result := d.DB.WithContext(ctx).Where("id = ?", userID).First(&user)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return domain.User{}, ErrorNotFound
+		}
+		return domain.User{}, result.Error
+	}
+
+	if user.VerifiedAt != nil {
+		return domain.User{}, ErrorVerified
+	}
+
+	verification := EmailVerification{
+		UserID:    user.ID,
+		TokenHash: tokenHash,
+		ExpiresAt: expiresAt,
+	}
+
+	result = d.DB.WithContext(ctx).Create(&verification)
+	if result.Error != nil {
+		return domain.User{}, result.Error
+	}
+
+	return domain.User{
+		Name:  user.Name,
+		Email: user.Email,
+	}, nil
+}
+
+func (d *Db) VerifyEmail(ctx context.Context, tokenHash string) (uint, error) {
+	var userID uint
+
+	err := d.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+
+		var verification EmailVerification
+		result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("token_hash = ? AND used_at IS NULL AND expires_at > ?", tokenHash, now).
+			First(&verification)
+		if result.Error != nil {
+			if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+				return ErrorNotFound
+			}
+			return result.Error
+		}
+		userID = verification.UserID
+
+		result = tx.Model(&EmailVerification{}).
+			Where("user_id = ? AND used_at IS NULL", verification.UserID).
+			Update("used_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		result = tx.Model(&User{}).
+			Where("id = ? AND verified_at IS NULL", verification.UserID).
+			Update("verified_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return userID, nil
+}
+
+func (d *Db) UserVerified(ctx context.Context, userID uint) (bool, error) {
+	var user User
+
+	result := d.DB.WithContext(ctx).Select("id", "verified_at").Where("id = ?", userID).First(&user)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return false, ErrorNotFound
+		}
+		return false, result.Error
+	}
+
+	return user.VerifiedAt != nil, nil
+}
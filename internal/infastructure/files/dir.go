@@ -0,0 +1,86 @@
+package files
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+var ErrName = errors.New("invalid file name")
+
+// Dir keeps files in a directory of the local disk.
+type Dir struct {
+	path string
+}
+
+func CreateDir(path string) (*Dir, error) {
+	if err := os.MkdirAll(path, 0o700); err != nil {
+		return nil, err
+	}
+
+	return &Dir{path: path}, nil
+}
+
+// Create returns a writer of the file, the file appears under its name only after Close,
+// so a half written file is never read.
+func (d *Dir) Create(name string) (io.WriteCloser, error) {
+	path, err := d.file(name)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.CreateTemp(d.path, "."+name+".*")
+	if err != nil {
+		return nil, err
+	}
+
+	return &file{File: f, path: path}, nil
+}
+
+func (d *Dir) Open(name string) (io.ReadCloser, error) {
+	path, err := d.file(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return os.Open(path)
+}
+
+// Remove removes the file, a missing file is not an error.
+func (d *Dir) Remove(name string) error {
+	path, err := d.file(name)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (d *Dir) file(name string) (string, error) {
+	if name == "" || name != filepath.Base(name) || name[0] == '.' {
+		return "", ErrName
+	}
+	return filepath.Join(d.path, name), nil
+}
+
+type file struct {
+	*os.File
+	path string
+}
+
+func (f *file) Close() error {
+	if err := f.File.Close(); err != nil {
+		os.Remove(f.File.Name())
+		return err
+	}
+
+	if err := os.Rename(f.File.Name(), f.path); err != nil {
+		os.Remove(f.File.Name())
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package files
+
+import (
+	"io"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDir(t *testing.T) {
+	d, err := CreateDir(t.TempDir())
+	require.NoError(t, err)
+
+	w, err := d.Create("export-1.zip")
+	require.NoError(t, err)
+	_, err = w.Write([]byte("data"))
+	require.NoError(t, err)
+
+	// not visible before Close
+	_, err = d.Open("export-1.zip")
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	require.NoError(t, w.Close())
+
+	r, err := d.Open("export-1.zip")
+	require.NoError(t, err)
+	data, err := io.ReadAll(r)
+	require.NoError(t, err)
+	r.Close()
+	assert.Equal(t, "data", string(data))
+
+	require.NoError(t, d.Remove("export-1.zip"))
+	require.NoError(t, d.Remove("export-1.zip"))
+	_, err = d.Open("export-1.zip")
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	for _, name := range []string{"", "../secret", "a/b.zip", ".hidden"} {
+		_, err := d.Create(name)
+		assert.ErrorIs(t, err, ErrName, name)
+	}
+}
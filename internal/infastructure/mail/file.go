@@ -0,0 +1,53 @@
+package mail
+
+import (
+	"context"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/financial_tracer/internal/config"
+	"github.com/financial_tracer/internal/domain"
+)
+
+// FileMailer writes mails to a file or stdout instead of sending them, for development
+// and tests.
+type FileMailer struct {
+	mu   sync.Mutex
+	w    io.Writer
+	from string
+}
+
+func CreateFileMailer(cfg config.Config) (*FileMailer, error) {
+	if cfg.Mail.File == "" {
+		return NewFileMailer(os.Stdout, cfg.Mail.From), nil
+	}
+
+	file, err := os.OpenFile(cfg.Mail.File, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewFileMailer(file, cfg.Mail.From), nil
+}
+
+func NewFileMailer(w io.Writer, from string) *FileMailer {
+	return &FileMailer{
+		w:    w,
+		from: from,
+	}
+}
+
+func (m *FileMailer) Send(ctx context.Context, msg domain.Mail) error {
+	data, err := message(m.from, msg, time.Now())
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	_, err = m.w.Write(append(data, '\n'))
+	return err
+}
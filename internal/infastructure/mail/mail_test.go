@@ -0,0 +1,127 @@
+package mail
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"net"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/financial_tracer/internal/config"
+	"github.com/financial_tracer/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var resetMail = domain.Mail{
+	To:      "jonn@gmail.com",
+	Subject: "Сброс пароля",
+	Body:    "Ссылка:\nhttps://tracker.local/reset?token=abc=\n",
+}
+
+func TestMessage(t *testing.T) {
+	date := time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	data, err := message("noreply@tracker.local", resetMail, date)
+	require.NoError(t, err)
+
+	text := string(data)
+	assert.Contains(t, text, "To: jonn@gmail.com\r\n")
+	assert.Contains(t, text, "Subject: =?utf-8?q?")
+	assert.Contains(t, text, "Date: Thu, 01 May 2025 12:00:00 +0000\r\n")
+	assert.Contains(t, text, "\r\n\r\nСсылка:\r\nhttps://tracker.local/reset?token=abc=\r\n")
+
+	_, err = message("noreply@tracker.local", domain.Mail{To: "not an address"}, date)
+	assert.Error(t, err)
+
+	_, err = message("noreply@tracker.local", domain.Mail{To: "jonn@gmail.com", Subject: "x\r\nBcc: all@gmail.com"}, date)
+	assert.Error(t, err)
+}
+
+func TestFileMailer(t *testing.T) {
+	var buf bytes.Buffer
+	m := NewFileMailer(&buf, "noreply@tracker.local")
+
+	require.NoError(t, m.Send(context.Background(), resetMail))
+	assert.Contains(t, buf.String(), "https://tracker.local/reset?token=abc=")
+}
+
+// fakeSMTP accepts one mail without extensions and returns the DATA it received.
+func fakeSMTP(ln net.Listener, received chan<- string) {
+	conn, err := ln.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	tp := textproto.NewConn(conn)
+	tp.PrintfLine("220 fake ESMTP")
+
+	var from, to string
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		cmd := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+		switch {
+		case cmd == "EHLO" || cmd == "HELO":
+			tp.PrintfLine("250 fake")
+		case strings.HasPrefix(strings.ToUpper(line), "MAIL FROM:"):
+			from = line
+			tp.PrintfLine("250 ok")
+		case strings.HasPrefix(strings.ToUpper(line), "RCPT TO:"):
+			to = line
+			tp.PrintfLine("250 ok")
+		case cmd == "DATA":
+			tp.PrintfLine("354 go ahead")
+			data, err := tp.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			received <- from + "\n" + to + "\n" + string(data)
+			tp.PrintfLine("250 queued")
+		case cmd == "QUIT":
+			tp.PrintfLine("221 bye")
+			return
+		default:
+			tp.PrintfLine("502 not implemented")
+		}
+	}
+}
+
+func TestSMTPMailer(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+
+	received := make(chan string, 1)
+	go fakeSMTP(ln, received)
+
+	host, port, err := net.SplitHostPort(ln.Addr().String())
+	require.NoError(t, err)
+
+	var cfg config.Config
+	cfg.Mail = config.MailConfig{Driver: "smtp", Host: host, Port: port, From: "noreply@tracker.local"}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	m := CreateSMTPMailer(cfg)
+	require.NoError(t, m.Send(ctx, resetMail))
+
+	select {
+	case got := <-received:
+		r := bufio.NewReader(strings.NewReader(got))
+		from, _ := r.ReadString('\n')
+		to, _ := r.ReadString('\n')
+		assert.Equal(t, "MAIL FROM:<noreply@tracker.local>\n", from)
+		assert.Equal(t, "RCPT TO:<jonn@gmail.com>\n", to)
+		assert.Contains(t, got, "https://tracker.local/reset?token=abc=")
+	case <-ctx.Done():
+		t.Fatal("mail not received")
+	}
+}
@@ -0,0 +1,91 @@
+package mail
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/smtp"
+	"time"
+
+	"github.com/financial_tracer/internal/config"
+	"github.com/financial_tracer/internal/domain"
+)
+
+type SMTPMailer struct {
+	host     string
+	addr     string
+	username string
+	password string
+	from     string
+}
+
+func CreateSMTPMailer(cfg config.Config) *SMTPMailer {
+	return &SMTPMailer{
+		host:     cfg.Mail.Host,
+		addr:     net.JoinHostPort(cfg.Mail.Host, cfg.Mail.Port),
+		username: cfg.Mail.Username,
+		password: cfg.Mail.Password,
+		from:     cfg.Mail.From,
+	}
+}
+
+// Send delivers msg through the SMTP server, STARTTLS is used when the server offers it.
+// Credentials are only sent over TLS or to localhost (see smtp.PlainAuth).
+func (m *SMTPMailer) Send(ctx context.Context, msg domain.Mail) error {
+	data, err := message(m.from, msg, time.Now())
+	if err != nil {
+		return err
+	}
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", m.addr)
+	if err != nil {
+		return fmt.Errorf("error dial smtp: %w", err)
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return err
+		}
+	}
+
+	c, err := smtp.NewClient(conn, m.host)
+	if err != nil {
+		return fmt.Errorf("error smtp client: %w", err)
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: m.host}); err != nil {
+			return fmt.Errorf("error starttls: %w", err)
+		}
+	}
+
+	if m.username != "" {
+		if err := c.Auth(smtp.PlainAuth("", m.username, m.password, m.host)); err != nil {
+			return fmt.Errorf("error smtp auth: %w", err)
+		}
+	}
+
+	if err := c.Mail(m.from); err != nil {
+		return fmt.Errorf("error smtp from: %w", err)
+	}
+	if err := c.Rcpt(msg.To); err != nil {
+		return fmt.Errorf("error smtp rcpt: %w", err)
+	}
+
+	w, err := c.Data()
+	if err != nil {
+		return fmt.Errorf("error smtp data: %w", err)
+	}
+	if _, err := w.Write(data); err != nil {
+		return fmt.Errorf("error smtp write: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("error smtp data: %w", err)
+	}
+
+	return c.Quit()
+}
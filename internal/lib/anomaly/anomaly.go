@@ -0,0 +1,73 @@
+package anomaly
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+const (
+	// MinHistory is the number of earlier transactions needed to judge a new one.
+	MinHistory = 5
+	// Threshold of the modified z-score (Iglewicz and Hoaglin) above which a value is an outlier.
+	Threshold = 3.5
+)
+
+// Detect compares the value with the history using the median and the median absolute
+// deviation (MAD). It returns whether the value is an outlier above the usual counts and the
+// reason in human words, small counts are never flagged.
+func Detect(history []int, value int) (bool, string) {
+	if len(history) < MinHistory {
+		return false, ""
+	}
+
+	values := make([]float64, len(history))
+	for i, v := range history {
+		values[i] = float64(v)
+	}
+
+	med := median(values)
+	deviations := make([]float64, len(values))
+	meanDeviation := 0.0
+	for i, v := range values {
+		deviations[i] = math.Abs(v - med)
+		meanDeviation += deviations[i]
+	}
+	meanDeviation /= float64(len(values))
+	mad := median(deviations)
+
+	diff := float64(value) - med
+	var score float64
+	switch {
+	case mad != 0:
+		score = 0.6745 * diff / mad
+	case meanDeviation != 0:
+		score = diff / (1.253314 * meanDeviation)
+	case diff <= 0:
+		return false, ""
+	default:
+		// every earlier value is the same, only a rise by more than the value itself counts
+		if diff <= math.Abs(med) {
+			return false, ""
+		}
+		score = math.Inf(1)
+	}
+
+	if score <= Threshold {
+		return false, ""
+	}
+
+	return true, fmt.Sprintf("count %d is far above the category median %.0f (MAD %.0f, %d earlier transactions)",
+		value, med, mad, len(history))
+}
+
+func median(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	n := len(sorted)
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
@@ -0,0 +1,36 @@
+package anomaly
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name    string
+		history []int
+		value   int
+		anomaly bool
+		reason  string
+	}{
+		{name: "short history", history: []int{300, 250, 350}, value: 50000, anomaly: false},
+		{name: "usual value", history: []int{300, 250, 350, 280, 320, 310}, value: 330, anomaly: false},
+		{name: "typo", history: []int{300, 250, 350, 280, 320, 310}, value: 50000, anomaly: true, reason: "above"},
+		{name: "too small", history: []int{5000, 5200, 4900, 5100, 5050}, value: 50, anomaly: false},
+		{name: "same values small change", history: []int{500, 500, 500, 500, 500}, value: 700, anomaly: false},
+		{name: "same values big change", history: []int{500, 500, 500, 500, 500}, value: 5000, anomaly: true, reason: "above"},
+		{name: "mad is zero", history: []int{100, 100, 100, 100, 100, 900}, value: 10000, anomaly: true, reason: "above"},
+	}
+
+	for _, ts := range tests {
+		t.Run(ts.name, func(t *testing.T) {
+			anomaly, reason := Detect(ts.history, ts.value)
+			if anomaly != ts.anomaly {
+				t.Errorf("anomaly = %v, want %v (%s)", anomaly, ts.anomaly, reason)
+			}
+			if !strings.Contains(reason, ts.reason) {
+				t.Errorf("reason = %q, want to contain %q", reason, ts.reason)
+			}
+		})
+	}
+}
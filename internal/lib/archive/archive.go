@@ -0,0 +1,138 @@
+// Package archive writes the data export of a user: a ZIP with the data as JSON, the
+// transactions as CSV and a ledger journal that can be imported back as a backup.
+package archive
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/financial_tracer/internal/lib/journal"
+)
+
+// Version is the version of the archive layout, it changes when files are renamed or
+// their fields change meaning.
+const Version = 1
+
+const (
+	ManifestFile        = "manifest.json"
+	ProfileFile         = "profile.json"
+	CategoriesFile      = "categories.json"
+	TransactionsFile    = "transactions.json"
+	TransactionsCSVFile = "transactions.csv"
+	SessionsFile        = "sessions.json"
+	AccessTokensFile    = "access_tokens.json"
+	SecurityEventsFile  = "security_events.json"
+	HouseholdsFile      = "households.json"
+	IdentitiesFile      = "identities.json"
+	JournalFile         = "journal.ledger"
+)
+
+type Manifest struct {
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	UserID    uint      `json:"user_id"`
+	Currency  string    `json:"currency"`
+	Files     []string  `json:"files"`
+	Restore   string    `json:"restore"`
+}
+
+// Write writes the archive of a to w, j is the journal of the same user.
+func Write(w io.Writer, a domain.Archive, j domain.Journal, createdAt time.Time) error {
+	zw := zip.NewWriter(w)
+
+	manifest := Manifest{
+		Version:   Version,
+		CreatedAt: createdAt,
+		UserID:    a.Profile.ID,
+		Currency:  a.Profile.Preferences.Currency,
+		Files: []string{
+			ProfileFile, CategoriesFile, TransactionsFile, TransactionsCSVFile, SessionsFile, AccessTokensFile,
+			SecurityEventsFile, HouseholdsFile, IdentitiesFile, JournalFile,
+		},
+		Restore: "import " + JournalFile + " with POST /journal/import?format=ledger",
+	}
+
+	files := []struct {
+		name  string
+		write func(io.Writer) error
+	}{
+		{ManifestFile, writeJSON(manifest)},
+		{ProfileFile, writeJSON(a.Profile)},
+		{CategoriesFile, writeJSON(nonNil(a.Categories))},
+		{TransactionsFile, writeJSON(nonNil(a.Transactions))},
+		{TransactionsCSVFile, func(w io.Writer) error { return writeCSV(w, a) }},
+		{SessionsFile, writeJSON(nonNil(a.Sessions))},
+		{AccessTokensFile, writeJSON(nonNil(a.AccessTokens))},
+		{SecurityEventsFile, writeJSON(nonNil(a.SecurityEvents))},
+		{HouseholdsFile, writeJSON(nonNil(a.Households))},
+		{IdentitiesFile, writeJSON(nonNil(a.Identities))},
+		{JournalFile, func(w io.Writer) error { return journal.Encode(w, journal.Ledger, j) }},
+	}
+
+	for _, f := range files {
+		fw, err := zw.CreateHeader(&zip.FileHeader{
+			Name:     f.name,
+			Method:   zip.Deflate,
+			Modified: createdAt,
+		})
+		if err != nil {
+			return err
+		}
+		if err := f.write(fw); err != nil {
+			return err
+		}
+	}
+
+	return zw.Close()
+}
+
+func writeJSON(v any) func(io.Writer) error {
+	return func(w io.Writer) error {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	}
+}
+
+func writeCSV(w io.Writer, a domain.Archive) error {
+	cw := csv.NewWriter(w)
+
+	err := cw.Write([]string{"id", "date", "category_id", "category", "name", "amount", "currency", "description", "anomaly_status"})
+	if err != nil {
+		return err
+	}
+
+	currency := a.Profile.Preferences.Currency
+	for _, t := range a.Transactions {
+		err := cw.Write([]string{
+			strconv.FormatUint(uint64(t.ID), 10),
+			t.CreatedAt.Format(time.RFC3339),
+			strconv.FormatUint(uint64(t.CategoryID), 10),
+			t.Category,
+			t.Name,
+			strconv.Itoa(t.Count),
+			currency,
+			t.Description,
+			t.AnomalyStatus,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// nonNil keeps empty lists as [] in the JSON.
+func nonNil[T any](s []T) []T {
+	if s == nil {
+		return []T{}
+	}
+	return s
+}
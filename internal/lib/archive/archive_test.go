@@ -0,0 +1,97 @@
+package archive
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/financial_tracer/internal/lib/journal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrite(t *testing.T) {
+	created := time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+	a := domain.Archive{
+		Profile: domain.Profile{ID: 3, Name: "jonn", Email: "jonn@gmail.com", Preferences: domain.Preferences{Currency: "EUR"}},
+		Categories: []domain.ArchiveCategory{
+			{ID: 1, Name: "food", Limit: 1000},
+		},
+		Transactions: []domain.ArchiveTransaction{
+			{ID: 5, CategoryID: 1, Category: "food", Name: "market, central", Count: 250, CreatedAt: created},
+		},
+		SecurityEvents: []domain.SecurityEvent{
+			{ID: 7, UserID: 3, Type: domain.EventLogin, Success: true, Device: domain.Device{UserAgent: "curl/8.0", IP: "10.0.0.1"}, CreatedAt: created},
+		},
+		Households: []domain.Household{
+			{ID: 2, Name: "family", Role: domain.HouseholdEditor, CreatedAt: created},
+		},
+		Identities: []domain.ArchiveIdentity{
+			{Provider: "corp", Subject: "42", Email: "jonn@corp.com", LinkedAt: created},
+		},
+	}
+	j := domain.Journal{
+		UserName:     "jonn",
+		Email:        "jonn@gmail.com",
+		Currency:     "EUR",
+		Categories:   []domain.CategoryInput{{Name: "food", Limit: 1000}},
+		Transactions: []domain.JournalTransaction{{Date: created, Category: "food", Name: "market, central", Count: 250}},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, a, j, created))
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+
+	files := map[string][]byte{}
+	for _, f := range zr.File {
+		r, err := f.Open()
+		require.NoError(t, err)
+		files[f.Name], err = io.ReadAll(r)
+		require.NoError(t, err)
+	}
+
+	var manifest Manifest
+	require.NoError(t, json.Unmarshal(files[ManifestFile], &manifest))
+	assert.Equal(t, Version, manifest.Version)
+	assert.Equal(t, uint(3), manifest.UserID)
+	for _, name := range manifest.Files {
+		assert.Contains(t, files, name)
+	}
+
+	var sessions []domain.Session
+	require.NoError(t, json.Unmarshal(files[SessionsFile], &sessions))
+	assert.NotNil(t, sessions)
+
+	var events []domain.SecurityEvent
+	require.NoError(t, json.Unmarshal(files[SecurityEventsFile], &events))
+	assert.Equal(t, a.SecurityEvents, events)
+
+	var households []domain.Household
+	require.NoError(t, json.Unmarshal(files[HouseholdsFile], &households))
+	assert.Equal(t, a.Households, households)
+
+	var identities []domain.ArchiveIdentity
+	require.NoError(t, json.Unmarshal(files[IdentitiesFile], &identities))
+	assert.Equal(t, a.Identities, identities)
+
+	rows, err := csv.NewReader(bytes.NewReader(files[TransactionsCSVFile])).ReadAll()
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"id", "date", "category_id", "category", "name", "amount", "currency", "description", "anomaly_status"},
+		{"5", "2025-05-01T12:00:00Z", "1", "food", "market, central", "250", "EUR", "", ""},
+	}, rows)
+
+	// the journal restores the data
+	restored, err := journal.Decode(bytes.NewReader(files[JournalFile]), journal.Ledger)
+	require.NoError(t, err)
+	assert.Equal(t, j.Categories, restored.Categories)
+	assert.Equal(t, 1, len(restored.Transactions))
+	assert.Equal(t, 250, restored.Transactions[0].Count)
+}
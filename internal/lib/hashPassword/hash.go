@@ -1,15 +1,145 @@
 package hashPassword
 
 import (
+	"bytes"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
 	"fmt"
+	"strings"
 
+	"golang.org/x/crypto/argon2"
 	"golang.org/x/crypto/bcrypt"
 )
 
+var (
+	ErrMismatched = errors.New("hash is not the hash of the password")
+	ErrFormat     = errors.New("unknown password hash format")
+)
+
+const (
+	saltLen = 16
+	keyLen  = 32
+)
+
+// Params is the cost of argon2id: Memory in KiB, Iterations passes over the memory and
+// Parallelism threads.
+type Params struct {
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+}
+
+// DefaultParams follow the OWASP recommendation for argon2id.
+var DefaultParams = Params{Memory: 64 * 1024, Iterations: 3, Parallelism: 2}
+
+var params = DefaultParams
+
+// SetParams changes the cost of new hashes, zero fields keep the default. It is called
+// once at start, before any hash is made.
+func SetParams(p Params) error {
+	if p.Memory == 0 {
+		p.Memory = DefaultParams.Memory
+	}
+	if p.Iterations == 0 {
+		p.Iterations = DefaultParams.Iterations
+	}
+	if p.Parallelism == 0 {
+		p.Parallelism = DefaultParams.Parallelism
+	}
+	if p.Memory < 8*uint32(p.Parallelism) {
+		return fmt.Errorf("argon2 memory must be at least 8 KiB per thread")
+	}
+
+	params = p
+	return nil
+}
+
+// Hash returns the argon2id hash of the password in the PHC string format
+// $argon2id$v=19$m=65536,t=3,p=2$<salt>$<hash>, so the parameters are stored with it.
 func Hash(password string) ([]byte, error) {
-	bytes, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
-	if err != nil {
+	salt := make([]byte, saltLen)
+	if _, err := rand.Read(salt); err != nil {
 		return []byte(""), fmt.Errorf("error hash password")
 	}
-	return bytes, nil
+
+	key := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, keyLen)
+
+	return []byte(fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, params.Memory, params.Iterations, params.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key))), nil
+}
+
+// Compare checks the password against an argon2id hash or a legacy bcrypt one, it returns
+// ErrMismatched when the password is wrong.
+func Compare(hash []byte, password string) error {
+	if bcryptHash(hash) {
+		err := bcrypt.CompareHashAndPassword(hash, []byte(password))
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return ErrMismatched
+		}
+		return err
+	}
+
+	p, salt, key, err := decode(hash)
+	if err != nil {
+		return err
+	}
+
+	other := argon2.IDKey([]byte(password), salt, p.Iterations, p.Memory, p.Parallelism, uint32(len(key)))
+	if subtle.ConstantTimeCompare(key, other) != 1 {
+		return ErrMismatched
+	}
+
+	return nil
+}
+
+// NeedsRehash reports whether the hash was made with bcrypt or other argon2id parameters
+// than the current ones, the password should then be hashed again.
+func NeedsRehash(hash []byte) bool {
+	if bcryptHash(hash) {
+		return true
+	}
+
+	p, _, _, err := decode(hash)
+	if err != nil {
+		return true
+	}
+
+	return p != params
+}
+
+func bcryptHash(hash []byte) bool {
+	return bytes.HasPrefix(hash, []byte("$2"))
+}
+
+func decode(hash []byte) (Params, []byte, []byte, error) {
+	parts := strings.Split(string(hash), "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return Params{}, nil, nil, ErrFormat
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return Params{}, nil, nil, ErrFormat
+	}
+
+	var p Params
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &p.Memory, &p.Iterations, &p.Parallelism); err != nil {
+		return Params{}, nil, nil, ErrFormat
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return Params{}, nil, nil, ErrFormat
+	}
+
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return Params{}, nil, nil, ErrFormat
+	}
+
+	return p, salt, key, nil
 }
@@ -1,8 +1,10 @@
 package hashPassword
 
 import (
+	"strings"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -14,10 +16,58 @@ func TestHash(t *testing.T) {
 			if err != nil {
 				t.Error("error create hash")
 			}
-			err = bcrypt.CompareHashAndPassword(hash, []byte(password))
+			if !strings.HasPrefix(string(hash), "$argon2id$v=19$m=65536,t=3,p=2$") {
+				t.Errorf("error: unexpected hash format %s", hash)
+			}
+			err = Compare(hash, password)
 			if err != nil {
 				t.Error("error: hash is not equal is password")
 			}
+			if NeedsRehash(hash) {
+				t.Error("error: new hash needs rehash")
+			}
+		})
+	}
+}
+
+func TestCompare(t *testing.T) {
+	argonHash, _ := Hash("secret")
+	bcryptHash, _ := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+
+	tests := []struct {
+		name     string
+		hash     []byte
+		password string
+		wantErr  error
+		rehash   bool
+	}{
+		{name: "success argon2id", hash: argonHash, password: "secret"},
+		{name: "success bcrypt", hash: bcryptHash, password: "secret", rehash: true},
+		{name: "error argon2id password", hash: argonHash, password: "other", wantErr: ErrMismatched},
+		{name: "error bcrypt password", hash: bcryptHash, password: "other", wantErr: ErrMismatched, rehash: true},
+		{name: "error format", hash: []byte("$argon2i$v=19$m=8,t=1,p=1$c2FsdA$a2V5"), password: "secret", wantErr: ErrFormat, rehash: true},
+	}
+
+	for _, ts := range tests {
+		t.Run(ts.name, func(t *testing.T) {
+			assert.ErrorIs(t, Compare(ts.hash, ts.password), ts.wantErr)
+			assert.Equal(t, ts.rehash, NeedsRehash(ts.hash))
 		})
 	}
 }
+
+func TestSetParams(t *testing.T) {
+	defer SetParams(DefaultParams)
+
+	old, _ := Hash("secret")
+
+	assert.Error(t, SetParams(Params{Memory: 8, Parallelism: 4}))
+	assert.NoError(t, SetParams(Params{Memory: 32 * 1024, Iterations: 2}))
+
+	hash, err := Hash("secret")
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(hash), "$argon2id$v=19$m=32768,t=2,p=2$"))
+	assert.NoError(t, Compare(old, "secret"))
+	assert.True(t, NeedsRehash(old))
+	assert.False(t, NeedsRehash(hash))
+}
@@ -0,0 +1,447 @@
+package journal
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/financial_tracer/internal/domain"
+)
+
+type posting struct {
+	account   string
+	count     int
+	hasAmount bool
+}
+
+type entry struct {
+	line        int
+	date        time.Time
+	name        string
+	description string
+	postings    []posting
+}
+
+type decoder struct {
+	format     Format
+	journal    domain.Journal
+	categories map[string]int
+	account    int
+	entry      *entry
+}
+
+// decode reads ledger, hledger and beancount journals. Only the parts that matter
+// for the tracker are read: expense accounts become categories and every posting
+// to an expense account becomes a transaction, everything else is skipped.
+func decode(r io.Reader, f Format) (domain.Journal, error) {
+	d := &decoder{
+		format:     f,
+		categories: map[string]int{},
+		account:    -1,
+	}
+
+	sc := bufio.NewScanner(r)
+	n := 0
+	for sc.Scan() {
+		n++
+		if err := d.line(n, strings.TrimRight(sc.Text(), " \t\r")); err != nil {
+			return domain.Journal{}, err
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return domain.Journal{}, err
+	}
+	if err := d.closeEntry(); err != nil {
+		return domain.Journal{}, err
+	}
+
+	return d.journal, nil
+}
+
+func (d *decoder) line(n int, line string) error {
+	if strings.TrimSpace(line) == "" {
+		d.account = -1
+		return d.closeEntry()
+	}
+
+	if line[0] == ' ' || line[0] == '\t' {
+		return d.indented(n, strings.TrimSpace(line))
+	}
+
+	d.account = -1
+	if err := d.closeEntry(); err != nil {
+		return err
+	}
+
+	switch line[0] {
+	case ';', '#', '*', '%', '|':
+		return nil
+	}
+
+	if d.format == Beancount {
+		return d.beancountDirective(n, line)
+	}
+	return d.ledgerDirective(n, line)
+}
+
+func (d *decoder) indented(n int, line string) error {
+	if d.account >= 0 {
+		key, value, ok := d.metadata(line)
+		if ok {
+			d.setCategory(d.account, key, value)
+		}
+		return nil
+	}
+
+	if d.entry == nil {
+		return nil
+	}
+
+	if line[0] == ';' || line[0] == '#' {
+		d.entryComment(strings.TrimSpace(line[1:]))
+		return nil
+	}
+
+	if d.format == Beancount {
+		if key, value, ok := d.metadata(line); ok {
+			if key == "description" {
+				d.entry.description = value
+			}
+			return nil
+		}
+	}
+
+	p, err := d.posting(line)
+	if err != nil {
+		return fmt.Errorf("%w: line %d: %s", ErrSyntax, n, err)
+	}
+	d.entry.postings = append(d.entry.postings, p)
+
+	return nil
+}
+
+func (d *decoder) ledgerDirective(n int, line string) error {
+	if rest, ok := strings.CutPrefix(line, "account "); ok {
+		account, _, _ := strings.Cut(rest, ";")
+		d.openAccount(strings.TrimSpace(account))
+		return nil
+	}
+
+	if !unicode.IsDigit(rune(line[0])) {
+		return nil
+	}
+
+	head, comment, _ := strings.Cut(line, ";")
+	fields := strings.Fields(head)
+	dateField, _, _ := strings.Cut(fields[0], "=")
+	date, err := parseDate(dateField)
+	if err != nil {
+		return fmt.Errorf("%w: line %d: %s", ErrSyntax, n, err)
+	}
+
+	rest := fields[1:]
+	if len(rest) > 0 && (rest[0] == "*" || rest[0] == "!") {
+		rest = rest[1:]
+	}
+	if len(rest) > 0 && strings.HasPrefix(rest[0], "(") && strings.HasSuffix(rest[0], ")") {
+		rest = rest[1:]
+	}
+
+	d.entry = &entry{line: n, date: date, name: strings.Join(rest, " ")}
+	if comment != "" {
+		d.entryComment(strings.TrimSpace(comment))
+	}
+
+	return nil
+}
+
+func (d *decoder) beancountDirective(n int, line string) error {
+	fields := strings.Fields(line)
+	if len(fields) < 2 || !unicode.IsDigit(rune(line[0])) {
+		return nil
+	}
+
+	date, err := parseDate(fields[0])
+	if err != nil {
+		return fmt.Errorf("%w: line %d: %s", ErrSyntax, n, err)
+	}
+
+	switch fields[1] {
+	case "open":
+		if len(fields) > 2 {
+			d.openAccount(fields[2])
+		}
+		return nil
+	case "*", "!", "txn":
+	default:
+		return nil
+	}
+
+	strs, err := quoted(strings.TrimSpace(strings.TrimPrefix(line, fields[0]+" "+fields[1])))
+	if err != nil {
+		return fmt.Errorf("%w: line %d: %s", ErrSyntax, n, err)
+	}
+
+	e := &entry{line: n, date: date}
+	switch len(strs) {
+	case 0:
+	case 1:
+		e.name = strs[0]
+	default:
+		e.name, e.description = strs[0], strs[1]
+		if e.name == "" {
+			e.name, e.description = e.description, ""
+		}
+	}
+	d.entry = e
+
+	return nil
+}
+
+func (d *decoder) entryComment(comment string) {
+	if key, value, ok := tag(comment); ok {
+		if key == "description" {
+			d.entry.description = value
+		}
+		return
+	}
+	if d.entry.description == "" {
+		d.entry.description = comment
+	}
+}
+
+// metadata reads "key: value" of an account or a transaction. In ledger journals it is
+// written as a comment, in beancount journals as a metadata line.
+func (d *decoder) metadata(line string) (string, string, bool) {
+	if d.format != Beancount {
+		if line[0] != ';' {
+			return "", "", false
+		}
+		return tag(strings.TrimSpace(line[1:]))
+	}
+
+	key, value, ok := tag(line)
+	if !ok || !unicode.IsLower(rune(key[0])) {
+		return "", "", false
+	}
+	if s, err := strconv.Unquote(value); err == nil {
+		value = s
+	}
+	return key, value, true
+}
+
+func (d *decoder) posting(line string) (posting, error) {
+	line, _, _ = strings.Cut(line, ";")
+	line = strings.TrimSpace(line)
+	if len(line) > 1 && (line[0] == '*' || line[0] == '!') && (line[1] == ' ' || line[1] == '\t') {
+		line = strings.TrimSpace(line[1:])
+	}
+
+	var account, amount string
+	if d.format == Beancount {
+		account, amount, _ = strings.Cut(line, " ")
+	} else {
+		idx := strings.Index(line, "  ")
+		if tab := strings.Index(line, "\t"); tab >= 0 && (idx < 0 || tab < idx) {
+			idx = tab
+		}
+		account = line
+		if idx >= 0 {
+			account, amount = line[:idx], line[idx:]
+		}
+		account = strings.Trim(account, "()[]")
+	}
+
+	p := posting{account: strings.TrimSpace(account)}
+	amount = strings.TrimSpace(amount)
+	if cut := strings.IndexAny(amount, "@={"); cut >= 0 {
+		amount = strings.TrimSpace(amount[:cut])
+	}
+	if amount == "" {
+		return p, nil
+	}
+
+	count, err := parseAmount(amount)
+	if err != nil {
+		return posting{}, err
+	}
+	p.count = count
+	p.hasAmount = true
+
+	return p, nil
+}
+
+func (d *decoder) closeEntry() error {
+	e := d.entry
+	if e == nil {
+		return nil
+	}
+	d.entry = nil
+
+	missing := -1
+	sum := 0
+	for i, p := range e.postings {
+		if p.hasAmount {
+			sum += p.count
+			continue
+		}
+		if missing >= 0 {
+			return fmt.Errorf("%w: line %d: more than one posting without amount", ErrSyntax, e.line)
+		}
+		missing = i
+	}
+	if missing >= 0 {
+		e.postings[missing].count = -sum
+		e.postings[missing].hasAmount = true
+	}
+
+	for _, p := range e.postings {
+		name, ok := d.category(p.account)
+		if !ok {
+			continue
+		}
+
+		tr := domain.JournalTransaction{
+			Date:        e.date,
+			Category:    name,
+			Name:        e.name,
+			Count:       p.count,
+			Description: e.description,
+		}
+		if tr.Name == "" {
+			tr.Name = name
+		}
+		d.journal.Transactions = append(d.journal.Transactions, tr)
+	}
+
+	return nil
+}
+
+func (d *decoder) openAccount(account string) {
+	if _, ok := d.category(account); !ok {
+		return
+	}
+	d.account = d.categories[account]
+}
+
+// category returns the category name of the expense account, the category is
+// registered on the first use.
+func (d *decoder) category(account string) (string, bool) {
+	root, rest, ok := strings.Cut(account, ":")
+	if !ok || !strings.EqualFold(root, ExpensesRoot) || rest == "" {
+		return "", false
+	}
+
+	idx, ok := d.categories[account]
+	if !ok {
+		idx = len(d.journal.Categories)
+		d.categories[account] = idx
+		d.journal.Categories = append(d.journal.Categories, domain.CategoryInput{Name: rest})
+	}
+
+	return d.journal.Categories[idx].Name, true
+}
+
+func (d *decoder) setCategory(idx int, key, value string) {
+	c := &d.journal.Categories[idx]
+	switch key {
+	case "name":
+		old := c.Name
+		c.Name = value
+		for i := range d.journal.Transactions {
+			if d.journal.Transactions[i].Category == old {
+				d.journal.Transactions[i].Category = value
+			}
+		}
+	case "limit":
+		if limit, err := parseAmount(value); err == nil {
+			c.Limit = limit
+		}
+	case "type":
+		c.Type = value
+	case "description":
+		c.Description = value
+	}
+}
+
+func tag(s string) (string, string, bool) {
+	key, value, ok := strings.Cut(s, ":")
+	if !ok || key == "" || strings.ContainsAny(key, " \t") {
+		return "", "", false
+	}
+	return key, strings.TrimSpace(value), true
+}
+
+func parseDate(s string) (time.Time, error) {
+	s = strings.NewReplacer("/", "-", ".", "-").Replace(s)
+	return time.Parse(dateLayout, s)
+}
+
+// parseAmount reads the number of an amount like "1000 RUB", "$-12.50", "1,000.00 EUR"
+// or "3 500,50 RUB" and rounds it to the whole units used by the tracker.
+func parseAmount(s string) (int, error) {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case unicode.IsDigit(r), r == '.', r == ',', r == '-', r == '+':
+			b.WriteRune(r)
+		case r == '_', unicode.IsSpace(r), unicode.IsLetter(r), r == '"', r == '$', r == '€', r == '£', r == '₽':
+		default:
+			return 0, fmt.Errorf("invalid amount %q", s)
+		}
+	}
+
+	num := b.String()
+	neg := strings.Count(num, "-")%2 == 1
+	num = strings.NewReplacer("-", "", "+", "").Replace(num)
+
+	// a comma is the decimal mark when there is no dot and at most two digits follow it
+	if idx := strings.LastIndexByte(num, ','); idx >= 0 && !strings.Contains(num, ".") && len(num)-idx-1 <= 2 {
+		num = num[:idx] + "." + num[idx+1:]
+	}
+	num = strings.ReplaceAll(num, ",", "")
+
+	f, err := strconv.ParseFloat(num, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount %q", s)
+	}
+	if neg {
+		f = -f
+	}
+
+	return int(math.Round(f)), nil
+}
+
+// quoted returns the double-quoted strings of the beancount transaction header.
+func quoted(s string) ([]string, error) {
+	var res []string
+	for {
+		start := strings.IndexByte(s, '"')
+		if start < 0 {
+			return res, nil
+		}
+
+		end := start + 1
+		for end < len(s) && s[end] != '"' {
+			if s[end] == '\\' {
+				end++
+			}
+			end++
+		}
+		if end >= len(s) {
+			return nil, fmt.Errorf("unterminated string")
+		}
+
+		str, err := strconv.Unquote(s[start : end+1])
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, str)
+		s = s[end+1:]
+	}
+}
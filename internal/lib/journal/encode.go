@@ -0,0 +1,95 @@
+package journal
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/financial_tracer/internal/domain"
+)
+
+func encodeLedger(w io.Writer, j domain.Journal) error {
+	bw := bufio.NewWriter(w)
+	user := UserAccount(j.UserName)
+
+	fmt.Fprintf(bw, "; user: %s <%s>\n\n", oneLine(j.UserName), oneLine(j.Email))
+	commodity := currency(j)
+
+	fmt.Fprintf(bw, "commodity %s\n\n", commodity)
+	fmt.Fprintf(bw, "account %s\n", user)
+	for _, c := range j.Categories {
+		fmt.Fprintf(bw, "account %s\n", CategoryAccount(c.Name))
+		fmt.Fprintf(bw, "    ; name: %s\n", oneLine(c.Name))
+		fmt.Fprintf(bw, "    ; limit: %d\n", c.Limit)
+		if c.Type != "" {
+			fmt.Fprintf(bw, "    ; type: %s\n", oneLine(c.Type))
+		}
+		if c.Description != "" {
+			fmt.Fprintf(bw, "    ; description: %s\n", oneLine(c.Description))
+		}
+	}
+
+	for _, t := range j.Transactions {
+		fmt.Fprintf(bw, "\n%s %s\n", t.Date.Format(dateLayout), payee(t.Name))
+		if t.Description != "" {
+			fmt.Fprintf(bw, "    ; description: %s\n", oneLine(t.Description))
+		}
+		fmt.Fprintf(bw, "    %s    %d %s\n", CategoryAccount(t.Category), t.Count, commodity)
+		fmt.Fprintf(bw, "    %s\n", user)
+	}
+
+	return bw.Flush()
+}
+
+func encodeBeancount(w io.Writer, j domain.Journal) error {
+	bw := bufio.NewWriter(w)
+	user := UserAccount(j.UserName)
+	open := openDate(j).Format(dateLayout)
+	commodity := currency(j)
+
+	fmt.Fprintf(bw, "option \"title\" %s\n", strconv.Quote(j.UserName))
+	fmt.Fprintf(bw, "option \"operating_currency\" \"%s\"\n\n", commodity)
+	fmt.Fprintf(bw, "%s open %s %s\n", open, user, commodity)
+	fmt.Fprintf(bw, "  email: %s\n", strconv.Quote(j.Email))
+	for _, c := range j.Categories {
+		fmt.Fprintf(bw, "%s open %s %s\n", open, CategoryAccount(c.Name), commodity)
+		fmt.Fprintf(bw, "  name: %s\n", strconv.Quote(c.Name))
+		fmt.Fprintf(bw, "  limit: %d\n", c.Limit)
+		if c.Type != "" {
+			fmt.Fprintf(bw, "  type: %s\n", strconv.Quote(c.Type))
+		}
+		if c.Description != "" {
+			fmt.Fprintf(bw, "  description: %s\n", strconv.Quote(c.Description))
+		}
+	}
+
+	for _, t := range j.Transactions {
+		fmt.Fprintf(bw, "\n%s * %s %s\n", t.Date.Format(dateLayout), strconv.Quote(t.Name), strconv.Quote(t.Description))
+		fmt.Fprintf(bw, "  %s  %d %s\n", CategoryAccount(t.Category), t.Count, commodity)
+		fmt.Fprintf(bw, "  %s\n", user)
+	}
+
+	return bw.Flush()
+}
+
+var lineBreaks = strings.NewReplacer("\r\n", " ", "\r", " ", "\n", " ")
+
+// oneLine keeps a ledger value on its line, a line break would end the entry.
+func oneLine(s string) string {
+	return lineBreaks.Replace(s)
+}
+
+// payee is the ledger transaction name, a ';' in it would start a comment.
+func payee(s string) string {
+	return strings.ReplaceAll(oneLine(s), ";", ",")
+}
+
+// currency returns the commodity of the journal amounts, Commodity when the journal has none.
+func currency(j domain.Journal) string {
+	if j.Currency == "" {
+		return Commodity
+	}
+	return j.Currency
+}
@@ -0,0 +1,129 @@
+package journal
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/financial_tracer/internal/domain"
+)
+
+type Format string
+
+const (
+	Ledger    Format = "ledger"
+	Hledger   Format = "hledger"
+	Beancount Format = "beancount"
+)
+
+const (
+	ExpensesRoot = "Expenses"
+	AssetsRoot   = "Assets"
+	Commodity    = "RUB"
+	dateLayout   = "2006-01-02"
+)
+
+var (
+	ErrFormat = errors.New("unknown journal format")
+	ErrSyntax = errors.New("invalid journal syntax")
+)
+
+func ParseFormat(value string) (Format, error) {
+	switch f := Format(strings.ToLower(strings.TrimSpace(value))); f {
+	case Ledger, Hledger, Beancount:
+		return f, nil
+	case "":
+		return Ledger, nil
+	default:
+		return "", fmt.Errorf("%w: %s", ErrFormat, value)
+	}
+}
+
+// Extension returns file extension usual for the format.
+func (f Format) Extension() string {
+	switch f {
+	case Beancount:
+		return ".beancount"
+	case Hledger:
+		return ".journal"
+	default:
+		return ".ledger"
+	}
+}
+
+func Encode(w io.Writer, f Format, j domain.Journal) error {
+	switch f {
+	case Ledger, Hledger:
+		return encodeLedger(w, j)
+	case Beancount:
+		return encodeBeancount(w, j)
+	default:
+		return fmt.Errorf("%w: %s", ErrFormat, f)
+	}
+}
+
+func Decode(r io.Reader, f Format) (domain.Journal, error) {
+	switch f {
+	case Ledger, Hledger, Beancount:
+		return decode(r, f)
+	default:
+		return domain.Journal{}, fmt.Errorf("%w: %s", ErrFormat, f)
+	}
+}
+
+// CategoryAccount maps the category name to the expense account.
+func CategoryAccount(name string) string {
+	return ExpensesRoot + ":" + accountComponent(name)
+}
+
+// UserAccount maps the user name to the asset account from which expenses are paid.
+func UserAccount(name string) string {
+	if strings.TrimSpace(name) == "" {
+		return AssetsRoot + ":Cash"
+	}
+	return AssetsRoot + ":" + accountComponent(name)
+}
+
+// accountComponent makes a single account component valid for ledger and beancount:
+// it starts with an upper case letter or digit and contains only letters, digits and '-'.
+func accountComponent(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.TrimSpace(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if b.Len() == 0 {
+				r = unicode.ToUpper(r)
+			}
+			b.WriteRune(r)
+			dash = false
+			continue
+		}
+		if b.Len() != 0 && !dash {
+			b.WriteRune('-')
+			dash = true
+		}
+	}
+
+	res := strings.TrimSuffix(b.String(), "-")
+	if res == "" {
+		return "Unknown"
+	}
+	return res
+}
+
+// openDate returns the date of the earliest transaction, it is used for account declarations.
+func openDate(j domain.Journal) time.Time {
+	var first time.Time
+	for _, t := range j.Transactions {
+		if first.IsZero() || t.Date.Before(first) {
+			first = t.Date
+		}
+	}
+	if first.IsZero() {
+		return time.Unix(0, 0).UTC()
+	}
+	return first
+}
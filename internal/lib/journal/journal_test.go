@@ -0,0 +1,194 @@
+package journal
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func testJournal() domain.Journal {
+	return domain.Journal{
+		UserName: "jonn",
+		Email:    "jonn@gmail.com",
+		Categories: []domain.CategoryInput{
+			{Name: "продукты", Limit: 5000, Type: "store", Description: "покупки в магазине"},
+			{Name: "car service", Limit: 20000},
+		},
+		Transactions: []domain.JournalTransaction{
+			{
+				Date:        time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+				Category:    "продукты",
+				Name:        "Пятерочка",
+				Count:       1200,
+				Description: "продукты на неделю",
+			},
+			{
+				Date:     time.Date(2025, 3, 4, 0, 0, 0, 0, time.UTC),
+				Category: "car service",
+				Name:     "oil change",
+				Count:    -300,
+			},
+		},
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, f := range []Format{Ledger, Hledger, Beancount} {
+		t.Run(string(f), func(t *testing.T) {
+			var buf bytes.Buffer
+			err := Encode(&buf, f, testJournal())
+			assert.NoError(t, err)
+
+			res, err := Decode(&buf, f)
+			assert.NoError(t, err)
+
+			want := testJournal()
+			assert.Equal(t, want.Categories, res.Categories)
+			assert.Equal(t, want.Transactions, res.Transactions)
+		})
+	}
+}
+
+func TestRoundTripEscape(t *testing.T) {
+	date := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	j := domain.Journal{
+		UserName: "jonn; admin",
+		Email:    "jonn@gmail.com",
+		Categories: []domain.CategoryInput{
+			{Name: "food; drinks", Limit: 5000, Type: "store\nmarket", Description: "line one\r\nline; \"two\""},
+		},
+		Transactions: []domain.JournalTransaction{
+			{Date: date, Category: "food; drinks", Name: "market; \"Пятерочка\"", Count: 1200, Description: "first\nsecond; third"},
+		},
+	}
+	ledger := domain.Journal{
+		Categories: []domain.CategoryInput{
+			{Name: "food; drinks", Limit: 5000, Type: "store market", Description: "line one line; \"two\""},
+		},
+		Transactions: []domain.JournalTransaction{
+			{Date: date, Category: "food; drinks", Name: "market, \"Пятерочка\"", Count: 1200, Description: "first second; third"},
+		},
+	}
+
+	tests := []struct {
+		format Format
+		want   domain.Journal
+	}{
+		{format: Ledger, want: ledger},
+		{format: Hledger, want: ledger},
+		{format: Beancount, want: j},
+	}
+
+	for _, ts := range tests {
+		t.Run(string(ts.format), func(t *testing.T) {
+			var buf bytes.Buffer
+			err := Encode(&buf, ts.format, j)
+			assert.NoError(t, err)
+
+			res, err := Decode(&buf, ts.format)
+			assert.NoError(t, err)
+			assert.Equal(t, ts.want.Categories, res.Categories)
+			assert.Equal(t, ts.want.Transactions, res.Transactions)
+		})
+	}
+}
+
+func TestDecodeHledger(t *testing.T) {
+	src := `; imported from the bank
+2025/01/15 * (42) Dentist  ; spring check-up
+    expenses:health:dentist      3 500,00 RUB
+    assets:bank
+
+2025-01-16 Salary
+    assets:bank           100000 RUB
+    income:salary
+
+2025-01-17 Lunch
+    ; description: with colleagues
+    Expenses:Food                450 RUB
+    Expenses:Coffee              150 RUB
+    Assets:Cash
+`
+	res, err := Decode(strings.NewReader(src), Hledger)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []domain.CategoryInput{
+		{Name: "health:dentist"},
+		{Name: "Food"},
+		{Name: "Coffee"},
+	}, res.Categories)
+
+	assert.Equal(t, []domain.JournalTransaction{
+		{Date: time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC), Category: "health:dentist", Name: "Dentist", Count: 3500, Description: "spring check-up"},
+		{Date: time.Date(2025, 1, 17, 0, 0, 0, 0, time.UTC), Category: "Food", Name: "Lunch", Count: 450, Description: "with colleagues"},
+		{Date: time.Date(2025, 1, 17, 0, 0, 0, 0, time.UTC), Category: "Coffee", Name: "Lunch", Count: 150, Description: "with colleagues"},
+	}, res.Transactions)
+}
+
+func TestDecodeBeancount(t *testing.T) {
+	src := `option "title" "jonn"
+
+2025-01-01 open Expenses:Food RUB
+  limit: 3000
+2025-01-01 open Assets:Cash
+
+2025-01-02 balance Assets:Cash 0 RUB
+
+2025-01-03 * "Market"
+  trip: "weekend"
+  Expenses:Food 250 RUB
+  Assets:Cash -250 RUB
+
+2025-01-04 txn "" "Bakery \"Bread\""
+  Expenses:Food 90 RUB @ 1 RUB
+  Assets:Cash
+`
+	res, err := Decode(strings.NewReader(src), Beancount)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []domain.CategoryInput{{Name: "Food", Limit: 3000}}, res.Categories)
+	assert.Equal(t, []domain.JournalTransaction{
+		{Date: time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC), Category: "Food", Name: "Market", Count: 250},
+		{Date: time.Date(2025, 1, 4, 0, 0, 0, 0, time.UTC), Category: "Food", Name: `Bakery "Bread"`, Count: 90},
+	}, res.Transactions)
+}
+
+func TestDecodeErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		src    string
+		format Format
+	}{
+		{name: "invalid date", src: "2025-13-01 shop\n  Expenses:Food  10\n  Assets:Cash\n", format: Ledger},
+		{name: "invalid amount", src: "2025-01-01 shop\n  Expenses:Food  ten?\n  Assets:Cash\n", format: Ledger},
+		{name: "two elided amounts", src: "2025-01-01 shop\n  Expenses:Food\n  Assets:Cash\n", format: Ledger},
+		{name: "unterminated string", src: "2025-01-01 * \"shop\n  Expenses:Food 10 RUB\n", format: Beancount},
+	}
+
+	for _, ts := range tests {
+		t.Run(ts.name, func(t *testing.T) {
+			_, err := Decode(strings.NewReader(ts.src), ts.format)
+			if !errors.Is(err, ErrSyntax) {
+				t.Errorf("err = %v, want ErrSyntax", err)
+			}
+		})
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	f, err := ParseFormat("Beancount")
+	assert.NoError(t, err)
+	assert.Equal(t, Beancount, f)
+
+	f, err = ParseFormat("")
+	assert.NoError(t, err)
+	assert.Equal(t, Ledger, f)
+
+	_, err = ParseFormat("qif")
+	assert.ErrorIs(t, err, ErrFormat)
+}
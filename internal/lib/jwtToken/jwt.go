@@ -1,33 +1,133 @@
 package jwttoken
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/sirupsen/logrus"
 )
 
-// ResponseJWTUser represents a jwt model
+// ResponseJWTUser represents a jwt model. With two-factor authentication on, login
+// returns only ChallengeToken, it is exchanged for the pair together with a TOTP code.
 type ResponseJWTUser struct {
-	RefreshToken string `json:"refresh_token,omitempty"`
-	AccessToken  string `json:"access_token"`
+	RefreshToken   string `json:"refresh_token,omitempty"`
+	AccessToken    string `json:"access_token,omitempty"`
+	ChallengeToken string `json:"challenge_token,omitempty"`
 }
 
+const (
+	AccessTTL    = time.Hour * 48
+	RefreshTTL   = time.Hour * 148
+	ChallengeTTL = time.Minute * 5
+
+	DefaultIssuer   = "financial_tracer"
+	DefaultAudience = "financial_tracer"
+)
+
+// Token types, stored in the typ claim so one can not be used in place of the other.
+const (
+	TypeAccess    = "access"
+	TypeRefresh   = "refresh"
+	TypeChallenge = "challenge"
+)
+
+var (
+	ErrTokenType = errors.New("wrong token type")
+	ErrKeyID     = errors.New("unknown signing key")
+)
+
+// Claims are the claims of all token types. ID (jti) is set on refresh tokens, where it
+// identifies the token inside its session, and on challenge tokens. SessionID is the
+// session (refresh-token family), challenge tokens have none.
 type Claims struct {
-	Id   uint   `json:"id"`
-	Name string `json:"name"`
+	Id        uint   `json:"id"`
+	Name      string `json:"name,omitempty"`
+	SessionID uint   `json:"sid,omitempty"`
+	Type      string `json:"typ"`
 	jwt.RegisteredClaims
 }
 
-func PostJWT(secretKey string, id uint, name string) (ResponseJWTUser, error) {
-	accessToken, err := JWTAccessToken(secretKey, id, name)
+// KeySet signs tokens with the current key and verifies them with any known key. Keys
+// are identified by the kid header, so the signing key can be rotated by adding a new
+// current key and keeping the old one for verification until its tokens expire.
+type KeySet struct {
+	issuer   string
+	audience string
+	signing  Key
+	keys     map[string]Key
+	methods  []string
+}
+
+// NewKeySet creates a key set signing with signing and verifying with it and previous.
+func NewKeySet(issuer string, audience string, signing Key, previous ...Key) (*KeySet, error) {
+	if issuer == "" {
+		issuer = DefaultIssuer
+	}
+	if audience == "" {
+		audience = DefaultAudience
+	}
+	if signing.private == nil {
+		return nil, fmt.Errorf("key %q can not sign", signing.ID)
+	}
+
+	k := &KeySet{
+		issuer:   issuer,
+		audience: audience,
+		signing:  signing,
+		keys:     map[string]Key{},
+	}
+
+	for _, key := range append([]Key{signing}, previous...) {
+		if _, ok := k.keys[key.ID]; ok {
+			return nil, fmt.Errorf("duplicated kid %q", key.ID)
+		}
+		k.keys[key.ID] = key
+
+		if !slices.Contains(k.methods, key.method.Alg()) {
+			k.methods = append(k.methods, key.method.Alg())
+		}
+	}
+
+	return k, nil
+}
+
+// NewHMACKeySet creates a HS256 key set signing with secret under kid. previous maps the
+// kids of retired secrets to the secrets, they are only used to verify tokens.
+func NewHMACKeySet(issuer string, audience string, kid string, secret string, previous map[string]string) (*KeySet, error) {
+	signing, err := HMACKey(kid, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := HMACKeys(previous)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewKeySet(issuer, audience, signing, keys...)
+}
+
+// NewJTI returns a random token id.
+func NewJTI() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func (k *KeySet) PostJWT(id uint, name string, sessionID uint, jti string) (ResponseJWTUser, error) {
+	accessToken, err := k.JWTAccessToken(id, name, sessionID)
 	if err != nil {
 		return ResponseJWTUser{}, fmt.Errorf("error create access token: %s", err)
 	}
 
-	refreshToken, err := JWTRefreshToken(secretKey, id, name)
+	refreshToken, err := k.JWTRefreshToken(id, name, sessionID, jti)
 	if err != nil {
 		return ResponseJWTUser{}, fmt.Errorf("error create refresh token: %s", err)
 	}
@@ -38,16 +138,16 @@ func PostJWT(secretKey string, id uint, name string) (ResponseJWTUser, error) {
 	}, nil
 }
 
-func JWTAccessToken(secretKey string, id uint, name string) (string, error) {
+func (k *KeySet) JWTAccessToken(id uint, name string, sessionID uint) (string, error) {
 	const op = "handlers.JWTAccessToken"
 
-	payload := jwt.MapClaims{
-		"id":  id,
-		"exp": time.Now().Add(time.Hour * 48).Unix(),
-	}
-
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, payload)
-	t, err := token.SignedString([]byte(secretKey))
+	t, err := k.sign(Claims{
+		Id:               id,
+		Name:             name,
+		SessionID:        sessionID,
+		Type:             TypeAccess,
+		RegisteredClaims: k.registered("", AccessTTL),
+	})
 	if err != nil {
 		return "", fmt.Errorf("%s: %w", op, err)
 	}
@@ -55,16 +155,16 @@ func JWTAccessToken(secretKey string, id uint, name string) (string, error) {
 	return t, nil
 }
 
-func JWTRefreshToken(secretKey string, id uint, name string) (string, error) {
+func (k *KeySet) JWTRefreshToken(id uint, name string, sessionID uint, jti string) (string, error) {
 	const op = "handlers.JWTRefreshToken"
 
-	payload := jwt.MapClaims{
-		"id":  id,
-		"exp": time.Now().Add(time.Hour * 148).Unix(),
-	}
-
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, payload)
-	t, err := token.SignedString([]byte(secretKey))
+	t, err := k.sign(Claims{
+		Id:               id,
+		Name:             name,
+		SessionID:        sessionID,
+		Type:             TypeRefresh,
+		RegisteredClaims: k.registered(jti, RefreshTTL),
+	})
 	if err != nil {
 		return "", fmt.Errorf("%s: %w", op, err)
 	}
@@ -72,56 +172,101 @@ func JWTRefreshToken(secretKey string, id uint, name string) (string, error) {
 	return t, nil
 }
 
-func CheckAccess(refreshToken string, secretKey string, log *logrus.Logger) (uint, string, error) {
-	const op = "handlers.CheckAccess"
-	token, err := jwt.Parse(refreshToken, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("%s: %w", op, errors.New("invalid method"))
-		}
+// JWTChallengeToken returns the token proving the password of a user with two-factor
+// authentication was checked, jti makes it single-use.
+func (k *KeySet) JWTChallengeToken(id uint, name string, jti string) (string, error) {
+	const op = "handlers.JWTChallengeToken"
 
-		return []byte(secretKey), nil
+	t, err := k.sign(Claims{
+		Id:               id,
+		Name:             name,
+		Type:             TypeChallenge,
+		RegisteredClaims: k.registered(jti, ChallengeTTL),
 	})
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
 
+	return t, nil
+}
+
+// Parse verifies the signature, issuer, audience, expiry and type of the token.
+func (k *KeySet) Parse(tokenStr string, tokenType string) (*Claims, error) {
+	token, err := jwt.ParseWithClaims(tokenStr, &Claims{}, k.keyFunc,
+		jwt.WithValidMethods(k.methods),
+		jwt.WithIssuer(k.issuer),
+		jwt.WithAudience(k.audience),
+		jwt.WithExpirationRequired(),
+	)
 	if err != nil {
-		log.WithFields(logrus.Fields{
-			"op":  op,
-			"err": "invalid parse token",
-		}).Error("error parse token")
-		return 0, "", fmt.Errorf("%s, not valid method: %w", op, err)
+		return nil, err
 	}
 
-	if !token.Valid {
-		log.WithFields(logrus.Fields{
-			"op":  op,
-			"err": "error valid token",
-		}).Error("error valid token")
-		return 0, "", fmt.Errorf("%s not valid token", op)
+	claims, ok := token.Claims.(*Claims)
+	if !ok || !token.Valid {
+		return nil, jwt.ErrTokenInvalidClaims
 	}
 
-	tokenClaims, ok := token.Claims.(*Claims)
-	if !ok {
-		log.WithFields(logrus.Fields{
-			"op":  op,
-			"err": "error claims",
-		}).Error("error claims")
-		return 0, "", fmt.Errorf("%s error convert token in claim", op)
+	if claims.Type != tokenType {
+		return nil, fmt.Errorf("%w: %q", ErrTokenType, claims.Type)
 	}
 
-	if tokenClaims.ExpiresAt.Unix() < time.Now().Unix() {
+	return claims, nil
+}
+
+func (k *KeySet) CheckAccess(refreshToken string, log *logrus.Logger) (*Claims, error) {
+	const op = "handlers.CheckAccess"
+
+	tokenClaims, err := k.Parse(refreshToken, TypeRefresh)
+	if err != nil {
 		log.WithFields(logrus.Fields{
 			"op":  op,
-			"err": "error expired token",
-		}).Error("error expired token")
-		return 0, "", fmt.Errorf("%s the deadline has ended", op)
+			"err": err,
+		}).Error("error parse token")
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	if tokenClaims.ID == "" {
+	if tokenClaims.ID == "" || tokenClaims.SessionID == 0 {
 		log.WithFields(logrus.Fields{
 			"op":  op,
 			"err": "error id",
 		}).Error("error id")
-		return 0, "", fmt.Errorf("%s error id token", op)
+		return nil, fmt.Errorf("%s error id token", op)
+	}
+
+	return tokenClaims, nil
+}
+
+func (k *KeySet) registered(jti string, ttl time.Duration) jwt.RegisteredClaims {
+	now := time.Now()
+
+	return jwt.RegisteredClaims{
+		ID:        jti,
+		Issuer:    k.issuer,
+		Audience:  jwt.ClaimStrings{k.audience},
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+	}
+}
+
+func (k *KeySet) sign(claims Claims) (string, error) {
+	token := jwt.NewWithClaims(k.signing.method, claims)
+	token.Header["kid"] = k.signing.ID
+
+	return token.SignedString(k.signing.private)
+}
+
+func (k *KeySet) keyFunc(token *jwt.Token) (any, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, ok := k.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrKeyID, kid)
+	}
+
+	// the algorithm is bound to the key, a token can not pick another one
+	if token.Method.Alg() != key.method.Alg() {
+		return nil, fmt.Errorf("invalid signing method %v for key %q", token.Header["alg"], kid)
 	}
 
-	return tokenClaims.Id, tokenClaims.Name, nil
+	return key.public, nil
 }
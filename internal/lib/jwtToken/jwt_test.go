@@ -0,0 +1,125 @@
+package jwttoken
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTokenType(t *testing.T) {
+	keys, err := NewHMACKeySet("", "", "k1", "secret", nil)
+	require.NoError(t, err)
+
+	tokens, err := keys.PostJWT(3, "jonn", 7, "jti")
+	require.NoError(t, err)
+
+	claims, err := keys.Parse(tokens.AccessToken, TypeAccess)
+	require.NoError(t, err)
+	assert.Equal(t, uint(3), claims.Id)
+	assert.Equal(t, uint(7), claims.SessionID)
+	assert.Equal(t, DefaultIssuer, claims.Issuer)
+
+	claims, err = keys.Parse(tokens.RefreshToken, TypeRefresh)
+	require.NoError(t, err)
+	assert.Equal(t, "jti", claims.ID)
+
+	_, err = keys.Parse(tokens.RefreshToken, TypeAccess)
+	assert.ErrorIs(t, err, ErrTokenType)
+
+	_, err = keys.Parse(tokens.AccessToken, TypeRefresh)
+	assert.ErrorIs(t, err, ErrTokenType)
+
+	challenge, err := keys.JWTChallengeToken(3, "jonn", "challenge")
+	require.NoError(t, err)
+
+	claims, err = keys.Parse(challenge, TypeChallenge)
+	require.NoError(t, err)
+	assert.Equal(t, "challenge", claims.ID)
+	assert.Zero(t, claims.SessionID)
+	assert.WithinDuration(t, time.Now().Add(ChallengeTTL), claims.ExpiresAt.Time, time.Second)
+
+	_, err = keys.Parse(challenge, TypeAccess)
+	assert.ErrorIs(t, err, ErrTokenType)
+}
+
+func TestKeyRotation(t *testing.T) {
+	old, err := NewHMACKeySet("", "", "k1", "old secret", nil)
+	require.NoError(t, err)
+
+	rotated, err := NewHMACKeySet("", "", "k2", "new secret", map[string]string{"k1": "old secret"})
+	require.NoError(t, err)
+
+	retired, err := NewHMACKeySet("", "", "k2", "new secret", nil)
+	require.NoError(t, err)
+
+	token, err := old.JWTAccessToken(3, "jonn", 7)
+	require.NoError(t, err)
+
+	_, err = rotated.Parse(token, TypeAccess)
+	assert.NoError(t, err, "tokens of the previous key stay valid")
+
+	_, err = retired.Parse(token, TypeAccess)
+	assert.ErrorIs(t, err, ErrKeyID)
+
+	token, err = rotated.JWTAccessToken(3, "jonn", 7)
+	require.NoError(t, err)
+
+	parsed, _, err := jwt.NewParser().ParseUnverified(token, &Claims{})
+	require.NoError(t, err)
+	assert.Equal(t, "k2", parsed.Header["kid"])
+
+	_, err = old.Parse(token, TypeAccess)
+	assert.ErrorIs(t, err, ErrKeyID)
+}
+
+func TestParseRejects(t *testing.T) {
+	keys, err := NewHMACKeySet("financial_tracer", "financial_tracer", "k1", "secret", nil)
+	require.NoError(t, err)
+
+	other, err := NewHMACKeySet("other", "financial_tracer", "k1", "secret", nil)
+	require.NoError(t, err)
+
+	token, err := other.JWTAccessToken(3, "jonn", 7)
+	require.NoError(t, err)
+	_, err = keys.Parse(token, TypeAccess)
+	assert.ErrorIs(t, err, jwt.ErrTokenInvalidIssuer)
+
+	other, err = NewHMACKeySet("financial_tracer", "reports", "k1", "secret", nil)
+	require.NoError(t, err)
+
+	token, err = other.JWTAccessToken(3, "jonn", 7)
+	require.NoError(t, err)
+	_, err = keys.Parse(token, TypeAccess)
+	assert.ErrorIs(t, err, jwt.ErrTokenInvalidAudience)
+
+	legacy := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"id":  3,
+		"exp": time.Now().Add(time.Hour).Unix(),
+	})
+	token, err = legacy.SignedString([]byte("secret"))
+	require.NoError(t, err)
+	_, err = keys.Parse(token, TypeAccess)
+	assert.True(t, errors.Is(err, ErrKeyID), "tokens without kid are rejected")
+
+	none := jwt.NewWithClaims(jwt.SigningMethodNone, Claims{Id: 3, Type: TypeAccess})
+	none.Header["kid"] = "k1"
+	token, err = none.SignedString(jwt.UnsafeAllowNoneSignatureType)
+	require.NoError(t, err)
+	_, err = keys.Parse(token, TypeAccess)
+	assert.Error(t, err)
+}
+
+func TestNewHMACKeySet(t *testing.T) {
+	_, err := NewHMACKeySet("", "", "k1", "", nil)
+	assert.Error(t, err)
+
+	_, err = NewHMACKeySet("", "", "k1", "secret", map[string]string{"k1": "old"})
+	assert.Error(t, err)
+
+	_, err = NewHMACKeySet("", "", "k2", "secret", map[string]string{"k1": ""})
+	assert.Error(t, err)
+}
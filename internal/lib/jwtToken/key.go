@@ -0,0 +1,184 @@
+package jwttoken
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Signing algorithms.
+const (
+	AlgHS256 = "HS256"
+	AlgRS256 = "RS256"
+	AlgEdDSA = "EdDSA"
+)
+
+const (
+	DefaultKeyID = "default"
+
+	minRSABits = 2048
+)
+
+// Key is a signing or verification key identified by kid. Keys loaded from a public key
+// only verify tokens.
+type Key struct {
+	ID      string
+	method  jwt.SigningMethod
+	private any
+	public  any
+}
+
+// HMACKey returns a HS256 key, the secret both signs and verifies.
+func HMACKey(kid string, secret string) (Key, error) {
+	if kid == "" {
+		kid = DefaultKeyID
+	}
+	if secret == "" {
+		return Key{}, fmt.Errorf("empty secret of kid %q", kid)
+	}
+
+	return Key{
+		ID:      kid,
+		method:  jwt.SigningMethodHS256,
+		private: []byte(secret),
+		public:  []byte(secret),
+	}, nil
+}
+
+// HMACKeys returns the HS256 keys of the kid: secret pairs ordered by kid.
+func HMACKeys(secrets map[string]string) ([]Key, error) {
+	kids := make([]string, 0, len(secrets))
+	for kid := range secrets {
+		kids = append(kids, kid)
+	}
+	sort.Strings(kids)
+
+	keys := make([]Key, 0, len(kids))
+	for _, kid := range kids {
+		key, err := HMACKey(kid, secrets[kid])
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+// PrivateKeyFromPEM parses a PKCS#1/PKCS#8 RSA key for RS256 or a PKCS#8 Ed25519 key
+// for EdDSA.
+func PrivateKeyFromPEM(kid string, alg string, data []byte) (Key, error) {
+	if kid == "" {
+		kid = DefaultKeyID
+	}
+
+	switch alg {
+	case AlgRS256:
+		private, err := jwt.ParseRSAPrivateKeyFromPEM(data)
+		if err != nil {
+			return Key{}, fmt.Errorf("kid %q: %w", kid, err)
+		}
+		if private.N.BitLen() < minRSABits {
+			return Key{}, fmt.Errorf("kid %q: rsa key shorter than %d bits", kid, minRSABits)
+		}
+		return Key{ID: kid, method: jwt.SigningMethodRS256, private: private, public: &private.PublicKey}, nil
+
+	case AlgEdDSA:
+		parsed, err := jwt.ParseEdPrivateKeyFromPEM(data)
+		if err != nil {
+			return Key{}, fmt.Errorf("kid %q: %w", kid, err)
+		}
+		private, ok := parsed.(ed25519.PrivateKey)
+		if !ok {
+			return Key{}, fmt.Errorf("kid %q: not an Ed25519 private key", kid)
+		}
+		return Key{ID: kid, method: jwt.SigningMethodEdDSA, private: private, public: private.Public()}, nil
+	}
+
+	return Key{}, fmt.Errorf("unsupported signing algorithm %q", alg)
+}
+
+// PublicKeyFromPEM parses a PKIX RSA or Ed25519 public key used to verify tokens signed
+// by a retired key.
+func PublicKeyFromPEM(kid string, data []byte) (Key, error) {
+	if public, err := jwt.ParseRSAPublicKeyFromPEM(data); err == nil {
+		return Key{ID: kid, method: jwt.SigningMethodRS256, public: public}, nil
+	}
+
+	if public, err := jwt.ParseEdPublicKeyFromPEM(data); err == nil {
+		return Key{ID: kid, method: jwt.SigningMethodEdDSA, public: public}, nil
+	}
+
+	return Key{}, fmt.Errorf("kid %q: %w", kid, errors.New("not a RSA or Ed25519 public key"))
+}
+
+// JWK is a public key in the JSON Web Key format (RFC 7517, RFC 8037).
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS returns the public keys of the set ordered by kid. HS256 keys are secret and
+// never published.
+func (k *KeySet) JWKS() JWKS {
+	kids := make([]string, 0, len(k.keys))
+	for kid := range k.keys {
+		kids = append(kids, kid)
+	}
+	sort.Strings(kids)
+
+	res := JWKS{Keys: []JWK{}}
+	for _, kid := range kids {
+		key := k.keys[kid]
+		switch public := key.public.(type) {
+		case *rsa.PublicKey:
+			res.Keys = append(res.Keys, JWK{
+				Kty: "RSA",
+				Kid: kid,
+				Use: "sig",
+				Alg: AlgRS256,
+				N:   base64url(public.N.Bytes()),
+				E:   base64url(bigEndian(public.E)),
+			})
+		case ed25519.PublicKey:
+			res.Keys = append(res.Keys, JWK{
+				Kty: "OKP",
+				Kid: kid,
+				Use: "sig",
+				Alg: AlgEdDSA,
+				Crv: "Ed25519",
+				X:   base64url(public),
+			})
+		}
+	}
+
+	return res
+}
+
+func base64url(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// bigEndian returns the minimal big-endian bytes of a positive exponent.
+func bigEndian(v int) []byte {
+	var b []byte
+	for ; v > 0; v >>= 8 {
+		b = append([]byte{byte(v)}, b...)
+	}
+	return b
+}
@@ -0,0 +1,175 @@
+package jwttoken
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func pemBlock(t *testing.T, typ string, der []byte) []byte {
+	t.Helper()
+	return pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: der})
+}
+
+func rsaPEM(t *testing.T, bits int) ([]byte, []byte) {
+	t.Helper()
+	private, err := rsa.GenerateKey(rand.Reader, bits)
+	require.NoError(t, err)
+
+	public, err := x509.MarshalPKIXPublicKey(&private.PublicKey)
+	require.NoError(t, err)
+
+	return pemBlock(t, "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(private)), pemBlock(t, "PUBLIC KEY", public)
+}
+
+func edPEM(t *testing.T) ([]byte, []byte) {
+	t.Helper()
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	privateDER, err := x509.MarshalPKCS8PrivateKey(private)
+	require.NoError(t, err)
+	publicDER, err := x509.MarshalPKIXPublicKey(public)
+	require.NoError(t, err)
+
+	return pemBlock(t, "PRIVATE KEY", privateDER), pemBlock(t, "PUBLIC KEY", publicDER)
+}
+
+func TestAsymmetricKeySet(t *testing.T) {
+	rsaPrivate, rsaPublic := rsaPEM(t, 2048)
+	edPrivate, edPublic := edPEM(t)
+
+	tests := []struct {
+		name    string
+		alg     string
+		private []byte
+		public  []byte
+	}{
+		{name: "RS256", alg: AlgRS256, private: rsaPrivate, public: rsaPublic},
+		{name: "EdDSA", alg: AlgEdDSA, private: edPrivate, public: edPublic},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			signing, err := PrivateKeyFromPEM("k1", tc.alg, tc.private)
+			require.NoError(t, err)
+
+			keys, err := NewKeySet("", "", signing)
+			require.NoError(t, err)
+
+			token, err := keys.JWTAccessToken(3, "jonn", 7)
+			require.NoError(t, err)
+
+			parsed, _, err := jwt.NewParser().ParseUnverified(token, &Claims{})
+			require.NoError(t, err)
+			assert.Equal(t, tc.alg, parsed.Method.Alg())
+
+			claims, err := keys.Parse(token, TypeAccess)
+			require.NoError(t, err)
+			assert.Equal(t, uint(3), claims.Id)
+
+			// another service verifies with the public key only
+			public, err := PublicKeyFromPEM("k1", tc.public)
+			require.NoError(t, err)
+
+			other, err := NewKeySet("", "", mustHMAC(t, "own", "secret"), public)
+			require.NoError(t, err)
+
+			_, err = other.Parse(token, TypeAccess)
+			assert.NoError(t, err)
+
+			jwks := keys.JWKS()
+			require.Len(t, jwks.Keys, 1)
+			assert.Equal(t, "k1", jwks.Keys[0].Kid)
+			assert.Equal(t, tc.alg, jwks.Keys[0].Alg)
+			assert.Equal(t, "sig", jwks.Keys[0].Use)
+		})
+	}
+}
+
+func mustHMAC(t *testing.T, kid string, secret string) Key {
+	t.Helper()
+	key, err := HMACKey(kid, secret)
+	require.NoError(t, err)
+	return key
+}
+
+func TestAlgorithmBoundToKey(t *testing.T) {
+	rsaPrivate, rsaPublic := rsaPEM(t, 2048)
+
+	signing, err := PrivateKeyFromPEM("k1", AlgRS256, rsaPrivate)
+	require.NoError(t, err)
+
+	keys, err := NewKeySet("", "", signing, mustHMAC(t, "legacy", "secret"))
+	require.NoError(t, err)
+
+	// HS256 token keyed with the published RSA public key
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, Claims{Id: 1, SessionID: 1, Type: TypeAccess})
+	forged.Header["kid"] = "k1"
+	token, err := forged.SignedString(rsaPublic)
+	require.NoError(t, err)
+
+	_, err = keys.Parse(token, TypeAccess)
+	assert.Error(t, err)
+
+	// HS256 tokens issued before switching to RS256 stay valid
+	legacy, err := NewHMACKeySet("", "", "legacy", "secret", nil)
+	require.NoError(t, err)
+
+	token, err = legacy.JWTAccessToken(3, "jonn", 7)
+	require.NoError(t, err)
+
+	_, err = keys.Parse(token, TypeAccess)
+	assert.NoError(t, err)
+
+	assert.Len(t, keys.JWKS().Keys, 1, "hmac secrets are not published")
+}
+
+func TestRSAJWK(t *testing.T) {
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	signing, err := PrivateKeyFromPEM("k1", AlgRS256, pemBlock(t, "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(private)))
+	require.NoError(t, err)
+
+	keys, err := NewKeySet("", "", signing)
+	require.NoError(t, err)
+
+	jwk := keys.JWKS().Keys[0]
+	assert.Equal(t, "RSA", jwk.Kty)
+	assert.Equal(t, "AQAB", jwk.E)
+
+	n, err := base64.RawURLEncoding.DecodeString(jwk.N)
+	require.NoError(t, err)
+	assert.Equal(t, 0, new(big.Int).SetBytes(n).Cmp(private.N))
+}
+
+func TestPrivateKeyFromPEMErrors(t *testing.T) {
+	small, _ := rsaPEM(t, 1024)
+	_, err := PrivateKeyFromPEM("k1", AlgRS256, small)
+	assert.Error(t, err)
+
+	edPrivate, _ := edPEM(t)
+	_, err = PrivateKeyFromPEM("k1", AlgRS256, edPrivate)
+	assert.Error(t, err)
+
+	_, err = PrivateKeyFromPEM("k1", "ES256", edPrivate)
+	assert.Error(t, err)
+
+	_, err = PublicKeyFromPEM("k1", []byte("not a key"))
+	assert.Error(t, err)
+
+	public, err := PublicKeyFromPEM("k1", func() []byte { _, p := edPEM(t); return p }())
+	require.NoError(t, err)
+	_, err = NewKeySet("", "", public)
+	assert.Error(t, err, "public keys can not sign")
+}
@@ -0,0 +1,45 @@
+package mailToken
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"net/url"
+)
+
+// New returns a random single-use token sent by mail and the hash of it stored in the
+// database, so a leaked table does not reveal usable tokens.
+func New() (string, string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(b)
+	return token, Hash(token), nil
+}
+
+func Hash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// Link adds the token to base as the token query parameter. Without a valid base the
+// token itself is returned.
+func Link(base string, token string) string {
+	if base == "" {
+		return token
+	}
+
+	u, err := url.Parse(base)
+	if err != nil {
+		return token
+	}
+
+	q := u.Query()
+	q.Set("token", token)
+	u.RawQuery = q.Encode()
+
+	return u.String()
+}
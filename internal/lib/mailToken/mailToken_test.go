@@ -0,0 +1,36 @@
+package mailToken
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	token, hash, err := New()
+	assert.NoError(t, err)
+	assert.Len(t, token, 43)
+	assert.Equal(t, Hash(token), hash)
+
+	other, _, err := New()
+	assert.NoError(t, err)
+	assert.NotEqual(t, token, other)
+}
+
+func TestLink(t *testing.T) {
+	tests := []struct {
+		name string
+		base string
+		want string
+	}{
+		{name: "url", base: "https://tracker.local/reset", want: "https://tracker.local/reset?token=abc"},
+		{name: "url with query", base: "https://tracker.local/app?page=reset", want: "https://tracker.local/app?page=reset&token=abc"},
+		{name: "empty", base: "", want: "abc"},
+	}
+
+	for _, ts := range tests {
+		t.Run(ts.name, func(t *testing.T) {
+			assert.Equal(t, ts.want, Link(ts.base, "abc"))
+		})
+	}
+}
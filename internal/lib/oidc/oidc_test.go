@@ -0,0 +1,137 @@
+package oidc_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/financial_tracer/internal/lib/oidc"
+	"github.com/financial_tracer/internal/lib/oidc/oidctest"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const redirect = "http://localhost:8080/financial_tracker/registration/oidc/corp/callback"
+
+func newIdP(t *testing.T) *oidctest.IdP {
+	idp, err := oidctest.New("tracker", "secret", oidctest.User{Subject: "42", Email: "Anna@Corp.com", EmailVerified: true, Name: "Anna"})
+	require.NoError(t, err)
+	t.Cleanup(idp.Close)
+	return idp
+}
+
+func TestFlow(t *testing.T) {
+	idp := newIdP(t)
+	ctx := context.Background()
+	p := oidc.NewProvider(idp.Config(redirect), nil, time.Now)
+
+	verifier, err := oidc.NewVerifier()
+	require.NoError(t, err)
+
+	authURL, err := p.AuthURL(ctx, "state", "nonce", oidc.Challenge(verifier))
+	require.NoError(t, err)
+
+	code, state, err := idp.Authorize(authURL)
+	require.NoError(t, err)
+	assert.Equal(t, "state", state)
+
+	t.Run("wrong verifier", func(t *testing.T) {
+		code, _, err := idp.Authorize(authURL)
+		require.NoError(t, err)
+
+		_, err = p.Exchange(ctx, code, "other")
+		assert.ErrorIs(t, err, oidc.ErrExchange)
+	})
+
+	raw, err := p.Exchange(ctx, code, verifier)
+	require.NoError(t, err)
+
+	identity, err := p.Verify(ctx, raw, "nonce")
+	require.NoError(t, err)
+	assert.Equal(t, oidc.Identity{Subject: "42", Email: "anna@corp.com", EmailVerified: true, Name: "Anna"}, identity)
+
+	_, err = p.Verify(ctx, raw, "other nonce")
+	assert.ErrorIs(t, err, oidc.ErrToken)
+
+	_, err = p.Exchange(ctx, code, verifier)
+	assert.ErrorIs(t, err, oidc.ErrExchange, "a code is used once")
+}
+
+func TestVerifyRejects(t *testing.T) {
+	idp := newIdP(t)
+	ctx := context.Background()
+	now := time.Now()
+	p := oidc.NewProvider(idp.Config(redirect), nil, func() time.Time { return now })
+
+	valid := func() jwt.MapClaims {
+		return jwt.MapClaims{
+			"iss":   idp.Issuer(),
+			"sub":   "42",
+			"aud":   "tracker",
+			"exp":   now.Add(time.Minute).Unix(),
+			"nonce": "nonce",
+		}
+	}
+
+	tests := []struct {
+		name   string
+		change func(jwt.MapClaims)
+	}{
+		{name: "other issuer", change: func(c jwt.MapClaims) { c["iss"] = "https://evil.example" }},
+		{name: "other audience", change: func(c jwt.MapClaims) { c["aud"] = "other" }},
+		{name: "expired", change: func(c jwt.MapClaims) { c["exp"] = now.Add(-time.Minute).Unix() }},
+		{name: "no expiry", change: func(c jwt.MapClaims) { delete(c, "exp") }},
+		{name: "no nonce", change: func(c jwt.MapClaims) { delete(c, "nonce") }},
+		{name: "no subject", change: func(c jwt.MapClaims) { delete(c, "sub") }},
+		{name: "azp of other client", change: func(c jwt.MapClaims) { c["aud"] = []string{"tracker", "other"}; c["azp"] = "other" }},
+	}
+
+	raw, err := idp.Sign(valid())
+	require.NoError(t, err)
+	_, err = p.Verify(ctx, raw, "nonce")
+	require.NoError(t, err)
+
+	for _, ts := range tests {
+		t.Run(ts.name, func(t *testing.T) {
+			claims := valid()
+			ts.change(claims)
+			raw, err := idp.Sign(claims)
+			require.NoError(t, err)
+
+			_, err = p.Verify(ctx, raw, "nonce")
+			assert.ErrorIs(t, err, oidc.ErrToken)
+		})
+	}
+
+	t.Run("unsigned", func(t *testing.T) {
+		raw, err := jwt.NewWithClaims(jwt.SigningMethodNone, valid()).SignedString(jwt.UnsafeAllowNoneSignatureType)
+		require.NoError(t, err)
+
+		_, err = p.Verify(ctx, raw, "nonce")
+		assert.ErrorIs(t, err, oidc.ErrToken)
+	})
+
+	t.Run("other key", func(t *testing.T) {
+		other := newIdP(t)
+		raw, err := other.Sign(valid())
+		require.NoError(t, err)
+
+		_, err = p.Verify(ctx, raw, "nonce")
+		assert.ErrorIs(t, err, oidc.ErrToken)
+	})
+}
+
+func TestDiscoveryIssuerMismatch(t *testing.T) {
+	idp := newIdP(t)
+	cfg := idp.Config(redirect)
+	cfg.Issuer += "/"
+
+	_, err := oidc.NewProvider(cfg, nil, time.Now).AuthURL(context.Background(), "state", "nonce", "challenge")
+	assert.ErrorIs(t, err, oidc.ErrDiscovery)
+}
+
+func TestChallenge(t *testing.T) {
+	// RFC 7636, appendix B
+	assert.Equal(t, "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM", oidc.Challenge("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"))
+}
@@ -0,0 +1,281 @@
+// Package oidctest runs an in-process OpenID Connect provider for tests.
+package oidctest
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/financial_tracer/internal/lib/oidc"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const KeyID = "oidctest"
+
+// User is the user the provider logs in, the authorization endpoint consents for it at once.
+type User struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+type grant struct {
+	redirectURI string
+	challenge   string
+	nonce       string
+	user        User
+}
+
+// IdP serves discovery, the JWKS, the authorization endpoint and the token endpoint,
+// which checks the client credentials, the redirect URI and the PKCE verifier. ID tokens
+// are signed with a RS256 key.
+type IdP struct {
+	Server       *httptest.Server
+	ClientID     string
+	ClientSecret string
+
+	mu    sync.Mutex
+	user  User
+	key   *rsa.PrivateKey
+	codes map[string]grant
+}
+
+func New(clientID string, clientSecret string, user User) (*IdP, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, err
+	}
+
+	idp := &IdP{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		user:         user,
+		key:          key,
+		codes:        map[string]grant{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /.well-known/openid-configuration", idp.discovery)
+	mux.HandleFunc("GET /jwks", idp.jwks)
+	mux.HandleFunc("GET /authorize", idp.authorize)
+	mux.HandleFunc("POST /token", idp.token)
+	idp.Server = httptest.NewServer(mux)
+
+	return idp, nil
+}
+
+func (i *IdP) Issuer() string {
+	return i.Server.URL
+}
+
+func (i *IdP) Close() {
+	i.Server.Close()
+}
+
+// SetUser changes the user logged in by the next authorizations.
+func (i *IdP) SetUser(user User) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.user = user
+}
+
+// Config returns the provider config of the registered client.
+func (i *IdP) Config(redirectURL string) oidc.Config {
+	return oidc.Config{
+		Issuer:       i.Issuer(),
+		ClientID:     i.ClientID,
+		ClientSecret: i.ClientSecret,
+		RedirectURL:  redirectURL,
+	}
+}
+
+// Sign signs the claims with the key of the provider.
+func (i *IdP) Sign(claims jwt.MapClaims) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = KeyID
+	return token.SignedString(i.key)
+}
+
+// Authorize opens the authorization URL like a browser and returns the code and the state
+// the provider redirects back with.
+func (i *IdP) Authorize(authURL string) (string, string, error) {
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+
+	resp, err := client.Get(authURL)
+	if err != nil {
+		return "", "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusFound {
+		return "", "", fmt.Errorf("authorize: status %d", resp.StatusCode)
+	}
+
+	location, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil {
+		return "", "", err
+	}
+
+	return location.Query().Get("code"), location.Query().Get("state"), nil
+}
+
+func (i *IdP) discovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"issuer":                                i.Issuer(),
+		"authorization_endpoint":                i.Issuer() + "/authorize",
+		"token_endpoint":                        i.Issuer() + "/token",
+		"jwks_uri":                              i.Issuer() + "/jwks",
+		"response_types_supported":              []string{"code"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+		"code_challenge_methods_supported":      []string{"S256"},
+	})
+}
+
+func (i *IdP) jwks(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": KeyID,
+			"use": "sig",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(i.key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(i.key.E)).Bytes()),
+		}},
+	})
+}
+
+func (i *IdP) authorize(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	switch {
+	case q.Get("response_type") != "code":
+		http.Error(w, "unsupported response_type", http.StatusBadRequest)
+		return
+	case q.Get("client_id") != i.ClientID:
+		http.Error(w, "unknown client", http.StatusBadRequest)
+		return
+	case q.Get("redirect_uri") == "":
+		http.Error(w, "no redirect_uri", http.StatusBadRequest)
+		return
+	case q.Get("code_challenge_method") != "S256" || q.Get("code_challenge") == "":
+		http.Error(w, "PKCE S256 required", http.StatusBadRequest)
+		return
+	}
+
+	code, err := oidc.NewNonce()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	i.mu.Lock()
+	i.codes[code] = grant{
+		redirectURI: q.Get("redirect_uri"),
+		challenge:   q.Get("code_challenge"),
+		nonce:       q.Get("nonce"),
+		user:        i.user,
+	}
+	i.mu.Unlock()
+
+	redirect, err := url.Parse(q.Get("redirect_uri"))
+	if err != nil {
+		http.Error(w, "invalid redirect_uri", http.StatusBadRequest)
+		return
+	}
+	rq := redirect.Query()
+	rq.Set("code", code)
+	rq.Set("state", q.Get("state"))
+	redirect.RawQuery = rq.Encode()
+
+	http.Redirect(w, r, redirect.String(), http.StatusFound)
+}
+
+func (i *IdP) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		tokenError(w, "invalid_request", err)
+		return
+	}
+
+	clientID, secret, ok := r.BasicAuth()
+	if !ok {
+		clientID, secret = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+	}
+	if clientID != i.ClientID || subtle.ConstantTimeCompare([]byte(secret), []byte(i.ClientSecret)) != 1 {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+		return
+	}
+	if r.PostForm.Get("grant_type") != "authorization_code" {
+		tokenError(w, "unsupported_grant_type", nil)
+		return
+	}
+
+	i.mu.Lock()
+	g, ok := i.codes[r.PostForm.Get("code")]
+	delete(i.codes, r.PostForm.Get("code"))
+	i.mu.Unlock()
+
+	switch {
+	case !ok:
+		tokenError(w, "invalid_grant", errors.New("unknown or used code"))
+		return
+	case g.redirectURI != r.PostForm.Get("redirect_uri"):
+		tokenError(w, "invalid_grant", errors.New("redirect_uri mismatch"))
+		return
+	case oidc.Challenge(r.PostForm.Get("code_verifier")) != g.challenge:
+		tokenError(w, "invalid_grant", errors.New("PKCE verification failed"))
+		return
+	}
+
+	now := time.Now()
+	idToken, err := i.Sign(jwt.MapClaims{
+		"iss":            i.Issuer(),
+		"sub":            g.user.Subject,
+		"aud":            i.ClientID,
+		"iat":            now.Unix(),
+		"exp":            now.Add(5 * time.Minute).Unix(),
+		"nonce":          g.nonce,
+		"email":          g.user.Email,
+		"email_verified": g.user.EmailVerified,
+		"name":           g.user.Name,
+	})
+	if err != nil {
+		tokenError(w, "server_error", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"access_token": "oidctest",
+		"token_type":   "Bearer",
+		"expires_in":   300,
+		"id_token":     idToken,
+	})
+}
+
+func tokenError(w http.ResponseWriter, code string, err error) {
+	res := map[string]string{"error": code}
+	if err != nil {
+		res["error_description"] = err.Error()
+	}
+	writeJSON(w, http.StatusBadRequest, res)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,34 @@
+package oidc
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+)
+
+// NewVerifier returns a random PKCE code verifier (RFC 7636), it is kept on the server
+// and sent only with the code exchange.
+func NewVerifier() (string, error) {
+	return random()
+}
+
+// NewNonce returns a random nonce bound to the ID token of the login.
+func NewNonce() (string, error) {
+	return random()
+}
+
+// Challenge returns the S256 code challenge of the verifier sent with the authorization
+// request.
+func Challenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func random() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,61 @@
+package passwordPolicy
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const prefixLen = 5
+
+// BreachedList is a local copy of a breached password list split by k-anonymity ranges:
+// the file PREFIX.txt in the directory holds the lines SUFFIX:COUNT of the SHA-1 hashes
+// starting with the 5 hex characters PREFIX, as served by the Pwned Passwords range API.
+type BreachedList struct {
+	dir string
+}
+
+func OpenBreachedList(dir string) (*BreachedList, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("open breached password list: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("breached password list %s is not a directory", dir)
+	}
+
+	return &BreachedList{dir: dir}, nil
+}
+
+// Contains reports whether the password is in the list. A missing range file means no
+// password with the prefix was breached.
+func (b *BreachedList) Contains(password string) (bool, error) {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+
+	file, err := os.Open(filepath.Join(b.dir, hash[:prefixLen]+".txt"))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return false, nil
+		}
+		return false, err
+	}
+	defer file.Close()
+
+	suffix := hash[prefixLen:]
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		value, count, _ := strings.Cut(strings.TrimSpace(scanner.Text()), ":")
+		if strings.EqualFold(value, suffix) && count != "0" {
+			return true, nil
+		}
+	}
+
+	return false, scanner.Err()
+}
@@ -0,0 +1,130 @@
+package passwordPolicy
+
+import (
+	"reflect"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// Tags of the password rules, a failed rule is reported as a validation error with its tag.
+const (
+	TagLength   = "password_length"
+	TagClasses  = "password_classes"
+	TagPersonal = "password_personal"
+	TagBreached = "password_breached"
+)
+
+// minPersonal is the shortest name or email part that is looked for in the password.
+const minPersonal = 3
+
+// Policy describes the new passwords: the length in characters, how many of the classes
+// lower case, upper case, digits and other characters are required, whether the password
+// may contain the name or the email of the user and the list of breached passwords.
+type Policy struct {
+	MinLength     int
+	MaxLength     int
+	MinClasses    int
+	AllowPersonal bool
+	Breached      *BreachedList
+}
+
+// DefaultPolicy is used for the zero length limits, the maximum bounds the work of hashing.
+var DefaultPolicy = Policy{MinLength: 8, MaxLength: 128}
+
+// Register adds the password tags to the validator.
+func Register(v *validator.Validate, p Policy) {
+	if p.MinLength == 0 {
+		p.MinLength = DefaultPolicy.MinLength
+	}
+	if p.MaxLength == 0 {
+		p.MaxLength = DefaultPolicy.MaxLength
+	}
+
+	v.RegisterValidation(TagLength, func(fl validator.FieldLevel) bool {
+		n := utf8.RuneCountInString(fl.Field().String())
+		return n >= p.MinLength && n <= p.MaxLength
+	})
+	v.RegisterValidation(TagClasses, func(fl validator.FieldLevel) bool {
+		return classes(fl.Field().String()) >= p.MinClasses
+	})
+	v.RegisterValidation(TagPersonal, func(fl validator.FieldLevel) bool {
+		return p.AllowPersonal || !personal(fl.Field().String(), sibling(fl, "Name"), sibling(fl, "Email"))
+	})
+	v.RegisterValidation(TagBreached, func(fl validator.FieldLevel) bool {
+		if p.Breached == nil {
+			return true
+		}
+		// an unreadable list must not block every sign-up, the password is let through
+		found, err := p.Breached.Contains(fl.Field().String())
+		return err != nil || !found
+	})
+}
+
+// personalPassword is a new password with the name and the email of the user it is set for.
+type personalPassword struct {
+	Name        string
+	Email       string
+	NewPassword string `validate:"password_personal"`
+}
+
+// CheckPersonal checks the new password against the stored name and email of the user, for
+// the requests that carry neither. A failure is a validation error of the NewPassword field.
+func CheckPersonal(v *validator.Validate, password string, name string, email string) error {
+	return v.Struct(personalPassword{Name: name, Email: email, NewPassword: password})
+}
+
+func classes(password string) int {
+	var lower, upper, digit, other int
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = 1
+		case unicode.IsUpper(r):
+			upper = 1
+		case unicode.IsDigit(r):
+			digit = 1
+		default:
+			other = 1
+		}
+	}
+
+	return lower + upper + digit + other
+}
+
+// personal reports whether the password contains the name, the email or the part of the
+// email before @, case is ignored.
+func personal(password string, name string, email string) bool {
+	password = strings.ToLower(password)
+
+	local, _, _ := strings.Cut(email, "@")
+	for _, value := range []string{name, email, local} {
+		value = strings.ToLower(strings.TrimSpace(value))
+		if utf8.RuneCountInString(value) >= minPersonal && strings.Contains(password, value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// sibling returns the string field of the struct holding the password, an empty string
+// when the struct has no such field.
+func sibling(fl validator.FieldLevel, name string) string {
+	parent := fl.Parent()
+	if parent.Kind() == reflect.Pointer {
+		parent = parent.Elem()
+	}
+	if parent.Kind() != reflect.Struct {
+		return ""
+	}
+
+	field := parent.FieldByName(name)
+	if !field.IsValid() || field.Kind() != reflect.String {
+		return ""
+	}
+
+	return field.String()
+}
@@ -0,0 +1,109 @@
+package passwordPolicy
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+)
+
+type register struct {
+	Name     string
+	Email    string
+	Password string `validate:"password_length,password_classes,password_personal,password_breached"`
+}
+
+func breachedDir(t *testing.T, passwords ...string) string {
+	dir := t.TempDir()
+	for _, password := range passwords {
+		sum := sha1.Sum([]byte(password))
+		hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+		line := "0018A45C4D1DEF81644B54AB7F969B88D65:1\r\n" + hash[prefixLen:] + ":52579\r\n"
+		if err := os.WriteFile(filepath.Join(dir, hash[:prefixLen]+".txt"), []byte(line), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestRegister(t *testing.T) {
+	list, err := OpenBreachedList(breachedDir(t, "P@ssw0rd!"))
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		policy   Policy
+		password string
+		tag      string
+	}{
+		{name: "success", password: "gpDIJGP:OGhiHG"},
+		{name: "error short", password: "Ab1!", tag: TagLength},
+		{name: "error long", policy: Policy{MaxLength: 10}, password: "gpDIJGP:OGhiHG", tag: TagLength},
+		{name: "success classes", policy: Policy{MinClasses: 3}, password: "gpDIJGPOGhiHG1"},
+		{name: "error classes", policy: Policy{MinClasses: 3}, password: "gpDIJGPOGhiHG", tag: TagClasses},
+		{name: "error name", password: "xxJonnDoe2024", tag: TagPersonal},
+		{name: "error email", password: "jonn.doe-2024", tag: TagPersonal},
+		{name: "success personal allowed", policy: Policy{AllowPersonal: true}, password: "jonn.doe-2024"},
+		{name: "error breached", policy: Policy{Breached: list}, password: "P@ssw0rd!", tag: TagBreached},
+		{name: "success not breached", policy: Policy{Breached: list}, password: "gpDIJGP:OGhiHG"},
+	}
+
+	for _, ts := range tests {
+		t.Run(ts.name, func(t *testing.T) {
+			v := validator.New()
+			Register(v, ts.policy)
+
+			err := v.Struct(register{Name: "JonnDoe", Email: "jonn.doe@tracker.local", Password: ts.password})
+
+			if ts.tag == "" {
+				assert.NoError(t, err)
+				return
+			}
+			var validErr validator.ValidationErrors
+			if assert.True(t, errors.As(err, &validErr)) {
+				assert.Equal(t, "Password", validErr[0].Field())
+				assert.Equal(t, ts.tag, validErr[0].Tag())
+			}
+		})
+	}
+}
+
+func TestCheckPersonal(t *testing.T) {
+	v := validator.New()
+	Register(v, Policy{})
+
+	assert.NoError(t, CheckPersonal(v, "gpDIJGP:OGhiHG", "JonnDoe", "jonn.doe@tracker.local"))
+
+	err := CheckPersonal(v, "xxJonnDoe2024", "JonnDoe", "jonn.doe@tracker.local")
+	var validErr validator.ValidationErrors
+	if assert.True(t, errors.As(err, &validErr)) {
+		assert.Equal(t, "NewPassword", validErr[0].Field())
+		assert.Equal(t, TagPersonal, validErr[0].Tag())
+	}
+
+	allowed := validator.New()
+	Register(allowed, Policy{AllowPersonal: true})
+	assert.NoError(t, CheckPersonal(allowed, "xxJonnDoe2024", "JonnDoe", "jonn.doe@tracker.local"))
+}
+
+func TestBreachedList(t *testing.T) {
+	list, err := OpenBreachedList(breachedDir(t, "qwerty"))
+	assert.NoError(t, err)
+
+	found, err := list.Contains("qwerty")
+	assert.NoError(t, err)
+	assert.True(t, found)
+
+	found, err = list.Contains("gpDIJGP:OGhiHG")
+	assert.NoError(t, err)
+	assert.False(t, found)
+
+	_, err = OpenBreachedList(filepath.Join(t.TempDir(), "missing"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,79 @@
+// Package period builds the reporting periods of a user from the preferences: the
+// timezone, the first day of the week and the day the month starts on.
+package period
+
+import (
+	"time"
+
+	"github.com/financial_tracer/internal/domain"
+)
+
+// Location returns the timezone of the preferences, UTC when it is unknown.
+func Location(pref domain.Preferences) *time.Location {
+	loc, err := time.LoadLocation(pref.Timezone)
+	if err != nil || pref.Timezone == "" {
+		return time.UTC
+	}
+	return loc
+}
+
+// Date returns the midnight of the calendar date of d in loc, so "2025-07-01" parsed as
+// UTC becomes the start of that day for the user.
+func Date(d time.Time, loc *time.Location) time.Time {
+	return time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, loc)
+}
+
+// Week returns the week containing t, it starts on the start weekday at midnight in the
+// location of t.
+func Week(t time.Time, start time.Weekday) domain.Period {
+	shift := (int(t.Weekday()) - int(start) + 7) % 7
+	from := Date(t, t.Location()).AddDate(0, 0, -shift)
+
+	return domain.Period{From: from, To: from.AddDate(0, 0, 7)}
+}
+
+// Month returns the reporting month containing t, it starts on the start day (1-28) at
+// midnight in the location of t.
+func Month(t time.Time, start int) domain.Period {
+	start = monthStart(start)
+
+	from := time.Date(t.Year(), t.Month(), start, 0, 0, 0, 0, t.Location())
+	if t.Day() < start {
+		from = from.AddDate(0, -1, 0)
+	}
+
+	return domain.Period{From: from, To: from.AddDate(0, 1, 0)}
+}
+
+// Previous returns the period of the same kind right before p.
+func Previous(p domain.Period, week bool) domain.Period {
+	if week {
+		return domain.Period{From: p.From.AddDate(0, 0, -7), To: p.From}
+	}
+	return domain.Period{From: p.From.AddDate(0, -1, 0), To: p.From}
+}
+
+// MonthsBetween returns how many reporting months t is before now, both in the same location.
+func MonthsBetween(t time.Time, now time.Time, start int) int {
+	shift := monthStart(start) - 1
+	t = t.AddDate(0, 0, -shift)
+	now = now.AddDate(0, 0, -shift)
+
+	return (now.Year()-t.Year())*12 + int(now.Month()) - int(t.Month())
+}
+
+// Days returns the number of calendar days in p.
+func Days(p domain.Period) int {
+	days := 0
+	for d := p.From; d.Before(p.To); d = d.AddDate(0, 0, 1) {
+		days++
+	}
+	return days
+}
+
+func monthStart(start int) int {
+	if start < 1 || start > 28 {
+		return 1
+	}
+	return start
+}
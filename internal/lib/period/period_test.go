@@ -0,0 +1,113 @@
+package period
+
+import (
+	"testing"
+	"time"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWeek(t *testing.T) {
+	// 2025-05-07 is Wednesday
+	now := time.Date(2025, 5, 7, 15, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		start time.Weekday
+		from  time.Time
+	}{
+		{name: "monday", start: time.Monday, from: time.Date(2025, 5, 5, 0, 0, 0, 0, time.UTC)},
+		{name: "sunday", start: time.Sunday, from: time.Date(2025, 5, 4, 0, 0, 0, 0, time.UTC)},
+		{name: "same day", start: time.Wednesday, from: time.Date(2025, 5, 7, 0, 0, 0, 0, time.UTC)},
+		{name: "thursday", start: time.Thursday, from: time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, ts := range tests {
+		t.Run(ts.name, func(t *testing.T) {
+			p := Week(now, ts.start)
+			assert.Equal(t, ts.from, p.From)
+			assert.Equal(t, ts.from.AddDate(0, 0, 7), p.To)
+		})
+	}
+}
+
+func TestMonth(t *testing.T) {
+	tests := []struct {
+		name  string
+		now   time.Time
+		start int
+		from  time.Time
+		to    time.Time
+	}{
+		{
+			name:  "calendar month",
+			now:   time.Date(2025, 2, 10, 12, 0, 0, 0, time.UTC),
+			start: 1,
+			from:  time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC),
+			to:    time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "before the start day",
+			now:   time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC),
+			start: 25,
+			from:  time.Date(2025, 2, 25, 0, 0, 0, 0, time.UTC),
+			to:    time.Date(2025, 3, 25, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "on the start day",
+			now:   time.Date(2025, 3, 25, 0, 0, 0, 0, time.UTC),
+			start: 25,
+			from:  time.Date(2025, 3, 25, 0, 0, 0, 0, time.UTC),
+			to:    time.Date(2025, 4, 25, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "january before the start day",
+			now:   time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC),
+			start: 10,
+			from:  time.Date(2024, 12, 10, 0, 0, 0, 0, time.UTC),
+			to:    time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "invalid start day",
+			now:   time.Date(2025, 2, 10, 12, 0, 0, 0, time.UTC),
+			start: 31,
+			from:  time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC),
+			to:    time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, ts := range tests {
+		t.Run(ts.name, func(t *testing.T) {
+			p := Month(ts.now, ts.start)
+			assert.Equal(t, ts.from, p.From)
+			assert.Equal(t, ts.to, p.To)
+		})
+	}
+}
+
+func TestMonthsBetween(t *testing.T) {
+	now := time.Date(2025, 3, 20, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, 0, MonthsBetween(time.Date(2025, 3, 15, 0, 0, 0, 0, time.UTC), now, 15))
+	assert.Equal(t, 1, MonthsBetween(time.Date(2025, 3, 14, 0, 0, 0, 0, time.UTC), now, 15))
+	assert.Equal(t, 1, MonthsBetween(time.Date(2025, 2, 15, 0, 0, 0, 0, time.UTC), now, 15))
+	assert.Equal(t, 2, MonthsBetween(time.Date(2025, 2, 14, 0, 0, 0, 0, time.UTC), now, 15))
+	assert.Equal(t, 1, MonthsBetween(time.Date(2025, 2, 28, 0, 0, 0, 0, time.UTC), now, 1))
+}
+
+func TestLocation(t *testing.T) {
+	assert.Equal(t, "Europe/Moscow", Location(domain.Preferences{Timezone: "Europe/Moscow"}).String())
+	assert.Equal(t, time.UTC, Location(domain.Preferences{Timezone: "Mars/Olympus"}))
+	assert.Equal(t, time.UTC, Location(domain.Preferences{}))
+}
+
+func TestDays(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Berlin")
+	assert.NoError(t, err)
+
+	// the DST switch makes the month an hour short
+	p := Month(time.Date(2025, 3, 10, 0, 0, 0, 0, loc), 1)
+	assert.Equal(t, 31, Days(p))
+	assert.Equal(t, 28, Days(Month(time.Date(2025, 2, 10, 0, 0, 0, 0, time.UTC), 1)))
+}
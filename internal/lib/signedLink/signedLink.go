@@ -0,0 +1,59 @@
+// Package signedLink signs links with HMAC-SHA256, so a link can be handed out without
+// authentication and still can't be forged or used after it expires.
+package signedLink
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+var (
+	ErrSignature = errors.New("invalid link signature")
+	ErrExpired   = errors.New("link expired")
+)
+
+// Sign returns the signature of the payload valid until expires.
+func Sign(key []byte, payload string, expires time.Time) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(payload))
+	mac.Write([]byte{0})
+	mac.Write([]byte(strconv.FormatInt(expires.Unix(), 10)))
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks the signature of the payload, expires is the unix time from the link.
+func Verify(key []byte, payload string, expires int64, signature string, now time.Time) error {
+	got, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil {
+		return ErrSignature
+	}
+
+	want, _ := base64.RawURLEncoding.DecodeString(Sign(key, payload, time.Unix(expires, 0)))
+	if !hmac.Equal(got, want) {
+		return ErrSignature
+	}
+
+	if !now.Before(time.Unix(expires, 0)) {
+		return ErrExpired
+	}
+
+	return nil
+}
+
+// Link adds the expires and signature query parameters to base with the other values.
+func Link(base string, values url.Values, expires time.Time, signature string) string {
+	q := url.Values{}
+	for k, v := range values {
+		q[k] = v
+	}
+	q.Set("expires", strconv.FormatInt(expires.Unix(), 10))
+	q.Set("signature", signature)
+
+	return base + "?" + q.Encode()
+}
@@ -0,0 +1,30 @@
+package signedLink
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerify(t *testing.T) {
+	key := []byte("secret")
+	now := time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+	expires := now.Add(time.Hour)
+	signature := Sign(key, "export:7", expires)
+
+	assert.NoError(t, Verify(key, "export:7", expires.Unix(), signature, now))
+	assert.ErrorIs(t, Verify(key, "export:8", expires.Unix(), signature, now), ErrSignature)
+	assert.ErrorIs(t, Verify(key, "export:7", expires.Add(time.Hour).Unix(), signature, now), ErrSignature)
+	assert.ErrorIs(t, Verify([]byte("other"), "export:7", expires.Unix(), signature, now), ErrSignature)
+	assert.ErrorIs(t, Verify(key, "export:7", expires.Unix(), "not base64!", now), ErrSignature)
+	assert.ErrorIs(t, Verify(key, "export:7", expires.Unix(), signature, expires), ErrExpired)
+}
+
+func TestLink(t *testing.T) {
+	expires := time.Unix(1746104400, 0)
+	link := Link("http://localhost:8080/export/download", url.Values{"id": {"7"}}, expires, "c2ln")
+
+	assert.Equal(t, "http://localhost:8080/export/download?expires=1746104400&id=7&signature=c2ln", link)
+}
@@ -0,0 +1,154 @@
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Codes are RFC 6238 TOTP codes: HMAC-SHA1, 6 digits, 30 second steps, the parameters
+// every authenticator app supports.
+const (
+	Digits = 6
+	Period = 30 * time.Second
+
+	// Skew is the number of steps before and after the current one a code is accepted
+	// for, it covers clock drift and the time the user needs to type the code.
+	Skew = 1
+
+	RecoveryCodes = 10
+)
+
+var ErrSecret = errors.New("invalid totp secret")
+
+var encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// NewSecret returns a random 160 bit secret in base32, the form authenticator apps take.
+func NewSecret() (string, error) {
+	b := make([]byte, 20)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return encoding.EncodeToString(b), nil
+}
+
+// URI returns the otpauth URI of the secret, shown to the user as a QR code.
+func URI(issuer string, account string, secret string) string {
+	q := url.Values{}
+	q.Set("secret", secret)
+	q.Set("issuer", issuer)
+	q.Set("algorithm", "SHA1")
+	q.Set("digits", fmt.Sprint(Digits))
+	q.Set("period", fmt.Sprint(int(Period.Seconds())))
+
+	u := url.URL{
+		Scheme:   "otpauth",
+		Host:     "totp",
+		Path:     "/" + issuer + ":" + account,
+		RawQuery: q.Encode(),
+	}
+	return u.String()
+}
+
+// Step returns the time step of t.
+func Step(t time.Time) int64 {
+	return t.Unix() / int64(Period.Seconds())
+}
+
+// Code returns the code of the secret for the time step.
+func Code(secret string, step int64) (string, error) {
+	key, err := encoding.DecodeString(strings.ToUpper(secret))
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", ErrSecret, err)
+	}
+
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], uint64(step))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint32(1)
+	for range Digits {
+		mod *= 10
+	}
+
+	return fmt.Sprintf("%0*d", Digits, value%mod), nil
+}
+
+// Validate checks the code against the steps around t and returns the matched step. The
+// caller stores the step and passes it as after next time, so a code is accepted once.
+func Validate(secret string, code string, t time.Time, after int64) (int64, bool) {
+	code = strings.TrimSpace(code)
+	if len(code) != Digits {
+		return 0, false
+	}
+
+	now := Step(t)
+	for step := now - Skew; step <= now+Skew; step++ {
+		if step <= after {
+			continue
+		}
+
+		want, err := Code(secret, step)
+		if err != nil {
+			return 0, false
+		}
+		if hmac.Equal([]byte(want), []byte(code)) {
+			return step, true
+		}
+	}
+
+	return 0, false
+}
+
+// NewRecoveryCodes returns n single-use recovery codes like "k3j9d-x82mf".
+func NewRecoveryCodes(n int) ([]string, error) {
+	const alphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+
+	// bytes over the largest multiple of the alphabet size are skipped, so every
+	// character is equally likely
+	const limit = 256 / len(alphabet) * len(alphabet)
+
+	codes := make([]string, 0, n)
+	buf := make([]byte, 32)
+	for range n {
+		code := make([]byte, 0, 10)
+		for len(code) < cap(code) {
+			if _, err := rand.Read(buf); err != nil {
+				return nil, err
+			}
+			for _, c := range buf {
+				if int(c) < limit && len(code) < cap(code) {
+					code = append(code, alphabet[int(c)%len(alphabet)])
+				}
+			}
+		}
+		codes = append(codes, string(code[:5])+"-"+string(code[5:]))
+	}
+
+	return codes, nil
+}
+
+// HashRecoveryCode returns the hash of the code stored in the database. Case, spaces and
+// dashes are ignored, so the code can be typed as the user likes.
+func HashRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	code = strings.NewReplacer("-", "", " ", "").Replace(code)
+
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,100 @@
+package totp
+
+import (
+	"encoding/base32"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rfcSecret is the SHA1 secret of the RFC 6238 test vectors.
+var rfcSecret = base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+func TestCode(t *testing.T) {
+	// RFC 6238 appendix B, the last 6 of the 8 digits
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{unix: 59, want: "287082"},
+		{unix: 1111111109, want: "081804"},
+		{unix: 1111111111, want: "050471"},
+		{unix: 1234567890, want: "005924"},
+		{unix: 2000000000, want: "279037"},
+	}
+
+	for _, ts := range tests {
+		code, err := Code(rfcSecret, Step(time.Unix(ts.unix, 0)))
+		assert.NoError(t, err)
+		assert.Equal(t, ts.want, code)
+	}
+
+	_, err := Code("not base32!", 1)
+	assert.ErrorIs(t, err, ErrSecret)
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	step := Step(now)
+
+	code, err := Code(rfcSecret, step)
+	require.NoError(t, err)
+	prev, err := Code(rfcSecret, step-1)
+	require.NoError(t, err)
+	old, err := Code(rfcSecret, step-2)
+	require.NoError(t, err)
+
+	got, ok := Validate(rfcSecret, code, now, 0)
+	assert.True(t, ok)
+	assert.Equal(t, step, got)
+
+	got, ok = Validate(rfcSecret, " "+prev+" ", now, 0)
+	assert.True(t, ok)
+	assert.Equal(t, step-1, got)
+
+	_, ok = Validate(rfcSecret, old, now, 0)
+	assert.False(t, ok, "code out of the skew window")
+
+	_, ok = Validate(rfcSecret, code, now, step)
+	assert.False(t, ok, "code of an already used step")
+
+	_, ok = Validate(rfcSecret, "12345", now, 0)
+	assert.False(t, ok)
+}
+
+func TestSecretAndURI(t *testing.T) {
+	secret, err := NewSecret()
+	require.NoError(t, err)
+	assert.Len(t, secret, 32)
+
+	_, err = Code(secret, 1)
+	assert.NoError(t, err)
+
+	u, err := url.Parse(URI("financial_tracer", "jonn@gmail.com", secret))
+	require.NoError(t, err)
+	assert.Equal(t, "otpauth", u.Scheme)
+	assert.Equal(t, "totp", u.Host)
+	assert.Equal(t, "/financial_tracer:jonn@gmail.com", u.Path)
+	assert.Equal(t, secret, u.Query().Get("secret"))
+	assert.Equal(t, "financial_tracer", u.Query().Get("issuer"))
+}
+
+func TestRecoveryCodes(t *testing.T) {
+	codes, err := NewRecoveryCodes(RecoveryCodes)
+	require.NoError(t, err)
+	assert.Len(t, codes, RecoveryCodes)
+
+	seen := map[string]bool{}
+	for _, code := range codes {
+		assert.Len(t, code, 11)
+		assert.False(t, seen[code])
+		seen[code] = true
+	}
+
+	assert.Equal(t, HashRecoveryCode(codes[0]), HashRecoveryCode(strings.ToUpper(strings.ReplaceAll(codes[0], "-", " "))))
+	assert.NotEqual(t, HashRecoveryCode(codes[0]), HashRecoveryCode(codes[1]))
+}
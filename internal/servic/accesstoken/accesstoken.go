@@ -0,0 +1,193 @@
+package accesstoken
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/financial_tracer/internal/infastructure/db/postgresql"
+	"github.com/financial_tracer/internal/lib/mailToken"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// MaxTokens is how many access tokens a user may have.
+	MaxTokens = 50
+
+	// prefixSize is how much of the secret part is kept to identify a token.
+	prefixSize = 6
+)
+
+type AccessTokenRepository interface {
+	CreateAccessToken(ctx context.Context, token domain.AccessToken, tokenHash string) (domain.AccessToken, error)
+	AccessTokens(ctx context.Context, userID uint) ([]domain.AccessToken, error)
+	RevokeAccessToken(ctx context.Context, userID uint, id uint) error
+	AuthenticateAccessToken(ctx context.Context, tokenHash string, now time.Time) (domain.AccessToken, error)
+}
+
+type AccessTokenServer struct {
+	r        AccessTokenRepository
+	log      *logrus.Logger
+	now      func() time.Time
+	validate validator.Validate
+}
+
+func CreateAccessTokenServer(r AccessTokenRepository, log *logrus.Logger, now func() time.Time) *AccessTokenServer {
+	return &AccessTokenServer{
+		r:        r,
+		log:      log,
+		now:      now,
+		validate: *validator.New(),
+	}
+}
+
+// CreateAccessToken returns the token only once, afterwards just its hash is known.
+func (as *AccessTokenServer) CreateAccessToken(ctx context.Context, userID uint, req domain.CreateAccessToken) (domain.NewAccessToken, error) {
+	const op = "accesstoken.CreateAccessToken"
+
+	log := as.log.WithFields(logrus.Fields{
+		"op":      op,
+		"user_id": userID,
+	})
+
+	log.Info("start create access token")
+
+	if err := as.validate.Struct(req); err != nil {
+		log.WithField("err", err).Error("invalid validate")
+		return domain.NewAccessToken{}, err
+	}
+
+	tokens, err := as.r.AccessTokens(ctx, userID)
+	if err != nil {
+		log.Error("error get access tokens: ", err)
+		return domain.NewAccessToken{}, ErrDatabase
+	}
+	if len(tokens) >= MaxTokens {
+		log.WithField("tokens", len(tokens)).Error("too many access tokens")
+		return domain.NewAccessToken{}, ErrTooMany
+	}
+
+	secret, hash, err := mailToken.New()
+	if err != nil {
+		log.WithField("err", err).Error("field create access token")
+		return domain.NewAccessToken{}, ErrServic
+	}
+	token := domain.AccessTokenPrefix + secret
+
+	scopes := slices.Clone(req.Scopes)
+	slices.Sort(scopes)
+	scopes = slices.Compact(scopes)
+
+	value := domain.AccessToken{
+		Name:   strings.TrimSpace(req.Name),
+		Prefix: Prefix(token),
+		Scopes: scopes,
+		UserID: userID,
+	}
+	if req.ExpiresIn > 0 {
+		expiresAt := as.now().AddDate(0, 0, req.ExpiresIn)
+		value.ExpiresAt = &expiresAt
+	}
+
+	value, err = as.r.CreateAccessToken(ctx, value, hash)
+	if err != nil {
+		log.Error("error create access token: ", err)
+		if errors.Is(err, postgresql.ErrorNotFound) {
+			return domain.NewAccessToken{}, ErrNoFound
+		}
+		return domain.NewAccessToken{}, ErrDatabase
+	}
+
+	log.WithField("token_id", value.ID).Info("success create access token")
+
+	return domain.NewAccessToken{
+		AccessToken: value,
+		Token:       token,
+	}, nil
+}
+
+func (as *AccessTokenServer) AccessTokens(ctx context.Context, userID uint) ([]domain.AccessToken, error) {
+	const op = "accesstoken.AccessTokens"
+
+	log := as.log.WithFields(logrus.Fields{
+		"op":      op,
+		"user_id": userID,
+	})
+
+	log.Info("start get access tokens")
+
+	tokens, err := as.r.AccessTokens(ctx, userID)
+	if err != nil {
+		log.Error("error get access tokens: ", err)
+		return nil, ErrDatabase
+	}
+
+	log.Info("success get access tokens")
+
+	return tokens, nil
+}
+
+func (as *AccessTokenServer) RevokeAccessToken(ctx context.Context, userID uint, id uint) error {
+	const op = "accesstoken.RevokeAccessToken"
+
+	log := as.log.WithFields(logrus.Fields{
+		"op":       op,
+		"user_id":  userID,
+		"token_id": id,
+	})
+
+	log.Info("start revoke access token")
+
+	if err := as.r.RevokeAccessToken(ctx, userID, id); err != nil {
+		log.Error("error revoke access token: ", err)
+		if errors.Is(err, postgresql.ErrorNotFound) {
+			return ErrNoFound
+		}
+		return ErrDatabase
+	}
+
+	log.Info("success revoke access token")
+
+	return nil
+}
+
+func (as *AccessTokenServer) Authenticate(ctx context.Context, token string) (domain.AccessToken, error) {
+	const op = "accesstoken.Authenticate"
+
+	log := as.log.WithField("op", op)
+
+	if !strings.HasPrefix(token, domain.AccessTokenPrefix) {
+		log.Error("invalid access token prefix")
+		return domain.AccessToken{}, ErrToken
+	}
+
+	value, err := as.r.AuthenticateAccessToken(ctx, mailToken.Hash(strings.TrimPrefix(token, domain.AccessTokenPrefix)), as.now())
+	if err != nil {
+		if errors.Is(err, postgresql.ErrorNotFound) {
+			log.WithField("prefix", Prefix(token)).Error("unknown access token")
+			return domain.AccessToken{}, ErrToken
+		}
+		log.Error("error authenticate access token: ", err)
+		return domain.AccessToken{}, ErrDatabase
+	}
+
+	log.WithFields(logrus.Fields{
+		"user_id":  value.UserID,
+		"token_id": value.ID,
+	}).Debug("success authenticate access token")
+
+	return value, nil
+}
+
+// Prefix is the part of the token that may be logged and shown to identify it.
+func Prefix(token string) string {
+	size := len(domain.AccessTokenPrefix) + prefixSize
+	if len(token) < size {
+		return token
+	}
+	return token[:size]
+}
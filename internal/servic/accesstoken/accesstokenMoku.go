@@ -0,0 +1,33 @@
+package accesstoken
+
+import (
+	"context"
+	"time"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type DbMock struct {
+	mock.Mock
+}
+
+func (d *DbMock) CreateAccessToken(ctx context.Context, token domain.AccessToken, tokenHash string) (domain.AccessToken, error) {
+	args := d.Called(ctx, token, tokenHash)
+	return args.Get(0).(domain.AccessToken), args.Error(1)
+}
+
+func (d *DbMock) AccessTokens(ctx context.Context, userID uint) ([]domain.AccessToken, error) {
+	args := d.Called(ctx, userID)
+	return args.Get(0).([]domain.AccessToken), args.Error(1)
+}
+
+func (d *DbMock) RevokeAccessToken(ctx context.Context, userID uint, id uint) error {
+	args := d.Called(ctx, userID, id)
+	return args.Error(0)
+}
+
+func (d *DbMock) AuthenticateAccessToken(ctx context.Context, tokenHash string, now time.Time) (domain.AccessToken, error) {
+	args := d.Called(ctx, tokenHash, now)
+	return args.Get(0).(domain.AccessToken), args.Error(1)
+}
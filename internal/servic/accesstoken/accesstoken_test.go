@@ -0,0 +1,188 @@
+package accesstoken
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/financial_tracer/internal/infastructure/db/postgresql"
+	"github.com/financial_tracer/internal/lib/mailToken"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var now = time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+func TestCreateAccessToken(t *testing.T) {
+	expires := now.AddDate(0, 0, 30)
+
+	tests := []struct {
+		name        string
+		req         domain.CreateAccessToken
+		tokens      int
+		createErr   error
+		wantErr     error
+		wantScopes  []string
+		wantExpires *time.Time
+		validateErr bool
+	}{
+		{
+			name:        "success",
+			req:         domain.CreateAccessToken{Name: " bank scraper ", Scopes: []string{domain.ScopeTransactionsWrite, domain.ScopeCategoriesRead, domain.ScopeTransactionsWrite}, ExpiresIn: 30},
+			wantScopes:  []string{domain.ScopeCategoriesRead, domain.ScopeTransactionsWrite},
+			wantExpires: &expires,
+		},
+		{
+			name:       "success never expires",
+			req:        domain.CreateAccessToken{Name: "spreadsheet", Scopes: []string{domain.ScopeReportsRead}},
+			wantScopes: []string{domain.ScopeReportsRead},
+		},
+		{
+			name:        "error unknown scope",
+			req:         domain.CreateAccessToken{Name: "spreadsheet", Scopes: []string{"user:write"}},
+			validateErr: true,
+		},
+		{
+			name:        "error no scopes",
+			req:         domain.CreateAccessToken{Name: "spreadsheet"},
+			validateErr: true,
+		},
+		{
+			name:    "error too many",
+			req:     domain.CreateAccessToken{Name: "spreadsheet", Scopes: []string{domain.ScopeReportsRead}},
+			tokens:  MaxTokens,
+			wantErr: ErrTooMany,
+		},
+		{
+			name:      "error database",
+			req:       domain.CreateAccessToken{Name: "spreadsheet", Scopes: []string{domain.ScopeReportsRead}},
+			createErr: errors.New("database down"),
+			wantErr:   ErrDatabase,
+		},
+	}
+
+	for _, ts := range tests {
+		t.Run(ts.name, func(t *testing.T) {
+			repoMock := new(DbMock)
+			repoMock.On("AccessTokens", mock.Anything, uint(3)).Return(make([]domain.AccessToken, ts.tokens), nil)
+
+			var stored domain.AccessToken
+			var hash string
+			repoMock.On("CreateAccessToken", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+				stored = args.Get(1).(domain.AccessToken)
+				hash = args.String(2)
+			}).Return(domain.AccessToken{ID: 9}, ts.createErr)
+
+			s := CreateAccessTokenServer(repoMock, logrus.New(), func() time.Time { return now })
+
+			token, err := s.CreateAccessToken(context.Background(), 3, ts.req)
+
+			if ts.validateErr {
+				var validErr validator.ValidationErrors
+				assert.ErrorAs(t, err, &validErr)
+				repoMock.AssertNotCalled(t, "CreateAccessToken", mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			assert.ErrorIs(t, err, ts.wantErr)
+			if ts.wantErr != nil {
+				return
+			}
+
+			assert.Equal(t, uint(9), token.ID)
+			assert.True(t, strings.HasPrefix(token.Token, domain.AccessTokenPrefix))
+			assert.Equal(t, mailToken.Hash(strings.TrimPrefix(token.Token, domain.AccessTokenPrefix)), hash)
+			assert.Equal(t, Prefix(token.Token), stored.Prefix)
+			assert.Equal(t, strings.TrimSpace(ts.req.Name), stored.Name)
+			assert.Equal(t, uint(3), stored.UserID)
+			assert.Equal(t, ts.wantScopes, stored.Scopes)
+			assert.Equal(t, ts.wantExpires, stored.ExpiresAt)
+		})
+	}
+}
+
+func TestRevokeAccessToken(t *testing.T) {
+	tests := []struct {
+		name      string
+		revokeErr error
+		wantErr   error
+	}{
+		{name: "success"},
+		{name: "error not found", revokeErr: postgresql.ErrorNotFound, wantErr: ErrNoFound},
+		{name: "error database", revokeErr: errors.New("database down"), wantErr: ErrDatabase},
+	}
+
+	for _, ts := range tests {
+		t.Run(ts.name, func(t *testing.T) {
+			repoMock := new(DbMock)
+			repoMock.On("RevokeAccessToken", mock.Anything, uint(3), uint(9)).Return(ts.revokeErr)
+
+			err := CreateAccessTokenServer(repoMock, logrus.New(), time.Now).RevokeAccessToken(context.Background(), 3, 9)
+
+			assert.ErrorIs(t, err, ts.wantErr)
+		})
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	secret, hash, err := mailToken.New()
+	require.NoError(t, err)
+	found := domain.AccessToken{ID: 9, UserID: 3, Scopes: []string{domain.ScopeReportsRead}}
+
+	tests := []struct {
+		name     string
+		token    string
+		authErr  error
+		wantErr  error
+		callRepo bool
+	}{
+		{
+			name:     "success",
+			token:    domain.AccessTokenPrefix + secret,
+			callRepo: true,
+		},
+		{
+			name:    "error jwt",
+			token:   "eyJhbGciOiJIUzI1NiJ9.e30.sig",
+			wantErr: ErrToken,
+		},
+		{
+			name:     "error revoked",
+			token:    domain.AccessTokenPrefix + secret,
+			authErr:  postgresql.ErrorNotFound,
+			wantErr:  ErrToken,
+			callRepo: true,
+		},
+		{
+			name:     "error database",
+			token:    domain.AccessTokenPrefix + secret,
+			authErr:  errors.New("database down"),
+			wantErr:  ErrDatabase,
+			callRepo: true,
+		},
+	}
+
+	for _, ts := range tests {
+		t.Run(ts.name, func(t *testing.T) {
+			repoMock := new(DbMock)
+			repoMock.On("AuthenticateAccessToken", mock.Anything, hash, now).Return(found, ts.authErr)
+
+			token, err := CreateAccessTokenServer(repoMock, logrus.New(), func() time.Time { return now }).Authenticate(context.Background(), ts.token)
+
+			assert.ErrorIs(t, err, ts.wantErr)
+			if ts.wantErr == nil {
+				assert.Equal(t, found, token)
+			}
+			if ts.callRepo {
+				repoMock.AssertExpectations(t)
+			} else {
+				repoMock.AssertNotCalled(t, "AuthenticateAccessToken", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
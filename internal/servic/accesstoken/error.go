@@ -0,0 +1,11 @@
+package accesstoken
+
+import "errors"
+
+var (
+	ErrDatabase = errors.New("error database")
+	ErrServic   = errors.New("servic error")
+	ErrNoFound  = errors.New("access token is not found")
+	ErrToken    = errors.New("invalid or expired access token")
+	ErrTooMany  = errors.New("too many access tokens")
+)
@@ -0,0 +1,162 @@
+package admin
+
+import (
+	"context"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+)
+
+const DefaultLimit = 20
+
+type AdminRepository interface {
+	AdminUsers(ctx context.Context, filter domain.AdminUserFilter) (domain.AdminUserList, error)
+	AdminUser(ctx context.Context, userID uint) (domain.AdminUser, error)
+	SetUserDisabled(ctx context.Context, userID uint, disabled bool) error
+	RevokeUserSessions(ctx context.Context, userID uint) (int64, error)
+	SetUserRole(ctx context.Context, userID uint, role string) error
+}
+
+type AdminServer struct {
+	r        AdminRepository
+	log      *logrus.Logger
+	validate validator.Validate
+}
+
+func CreateAdminServer(r AdminRepository, log *logrus.Logger) *AdminServer {
+	return &AdminServer{
+		r:        r,
+		log:      log,
+		validate: *validator.New(),
+	}
+}
+
+func (as *AdminServer) Users(ctx context.Context, filter domain.AdminUserFilter) (domain.AdminUserList, error) {
+	const op = "admin.Users"
+
+	log := as.log.WithField("op", op)
+
+	log.Info("start get users")
+
+	if filter.Limit == 0 {
+		filter.Limit = DefaultLimit
+	}
+
+	if err := as.validate.Struct(filter); err != nil {
+		log.WithField("err", err).Error("invalid validate")
+		return domain.AdminUserList{}, err
+	}
+
+	list, err := as.r.AdminUsers(ctx, filter)
+	if err != nil {
+		log.Error("error get users: ", err)
+		return domain.AdminUserList{}, ErrDatabase
+	}
+
+	log.WithField("total", list.Total).Info("success get users")
+
+	return list, nil
+}
+
+func (as *AdminServer) User(ctx context.Context, userID uint) (domain.AdminUser, error) {
+	const op = "admin.User"
+
+	log := as.log.WithFields(logrus.Fields{
+		"op":      op,
+		"user_id": userID,
+	})
+
+	log.Info("start get user")
+
+	user, err := as.r.AdminUser(ctx, userID)
+	if err != nil {
+		log.Error("error get user: ", err)
+		return domain.AdminUser{}, RegisterErrDatabase(err)
+	}
+
+	log.Info("success get user")
+
+	return user, nil
+}
+
+func (as *AdminServer) SetDisabled(ctx context.Context, adminID uint, userID uint, disabled bool) error {
+	const op = "admin.SetDisabled"
+
+	log := as.log.WithFields(logrus.Fields{
+		"op":       op,
+		"admin_id": adminID,
+		"user_id":  userID,
+		"disabled": disabled,
+	})
+
+	log.Info("start set disabled")
+
+	if adminID == userID && disabled {
+		log.Error("admin disables itself")
+		return ErrSelf
+	}
+
+	if err := as.r.SetUserDisabled(ctx, userID, disabled); err != nil {
+		log.Error("error set disabled: ", err)
+		return RegisterErrDatabase(err)
+	}
+
+	log.Info("success set disabled")
+
+	return nil
+}
+
+func (as *AdminServer) Logout(ctx context.Context, adminID uint, userID uint) (int64, error) {
+	const op = "admin.Logout"
+
+	log := as.log.WithFields(logrus.Fields{
+		"op":       op,
+		"admin_id": adminID,
+		"user_id":  userID,
+	})
+
+	log.Info("start logout user")
+
+	revoked, err := as.r.RevokeUserSessions(ctx, userID)
+	if err != nil {
+		log.Error("error revoke sessions: ", err)
+		return 0, RegisterErrDatabase(err)
+	}
+
+	log.WithField("revoked", revoked).Info("success logout user")
+
+	return revoked, nil
+}
+
+func (as *AdminServer) SetRole(ctx context.Context, adminID uint, userID uint, req domain.UpdateRole) error {
+	const op = "admin.SetRole"
+
+	log := as.log.WithFields(logrus.Fields{
+		"op":       op,
+		"admin_id": adminID,
+		"user_id":  userID,
+		"role":     req.Role,
+	})
+
+	log.Info("start set role")
+
+	if err := as.validate.Struct(req); err != nil {
+		log.WithField("err", err).Error("invalid validate")
+		return err
+	}
+
+	if adminID == userID && req.Role != domain.RoleAdmin {
+		log.Error("admin demotes itself")
+		return ErrSelf
+	}
+
+	if err := as.r.SetUserRole(ctx, userID, req.Role); err != nil {
+		log.Error("error set role: ", err)
+		return RegisterErrDatabase(err)
+	}
+
+	log.Info("success set role")
+
+	return nil
+}
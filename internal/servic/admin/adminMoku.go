@@ -0,0 +1,37 @@
+package admin
+
+import (
+	"context"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type DbMock struct {
+	mock.Mock
+}
+
+func (d *DbMock) AdminUsers(ctx context.Context, filter domain.AdminUserFilter) (domain.AdminUserList, error) {
+	args := d.Called(ctx, filter)
+	return args.Get(0).(domain.AdminUserList), args.Error(1)
+}
+
+func (d *DbMock) AdminUser(ctx context.Context, userID uint) (domain.AdminUser, error) {
+	args := d.Called(ctx, userID)
+	return args.Get(0).(domain.AdminUser), args.Error(1)
+}
+
+func (d *DbMock) SetUserDisabled(ctx context.Context, userID uint, disabled bool) error {
+	args := d.Called(ctx, userID, disabled)
+	return args.Error(0)
+}
+
+func (d *DbMock) RevokeUserSessions(ctx context.Context, userID uint) (int64, error) {
+	args := d.Called(ctx, userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (d *DbMock) SetUserRole(ctx context.Context, userID uint, role string) error {
+	args := d.Called(ctx, userID, role)
+	return args.Error(0)
+}
@@ -0,0 +1,137 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/financial_tracer/internal/infastructure/db/postgresql"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestUsers(t *testing.T) {
+	disabled := true
+
+	tests := []struct {
+		name        string
+		filter      domain.AdminUserFilter
+		want        domain.AdminUserFilter
+		repoErr     error
+		wantErr     error
+		validateErr bool
+	}{
+		{
+			name:   "success default limit",
+			filter: domain.AdminUserFilter{Query: "jonn", Disabled: &disabled},
+			want:   domain.AdminUserFilter{Query: "jonn", Disabled: &disabled, Limit: DefaultLimit},
+		},
+		{
+			name:        "error role",
+			filter:      domain.AdminUserFilter{Role: "root"},
+			validateErr: true,
+		},
+		{
+			name:        "error limit",
+			filter:      domain.AdminUserFilter{Limit: 1000},
+			validateErr: true,
+		},
+		{
+			name:    "error database",
+			filter:  domain.AdminUserFilter{},
+			want:    domain.AdminUserFilter{Limit: DefaultLimit},
+			repoErr: errors.New("database down"),
+			wantErr: ErrDatabase,
+		},
+	}
+
+	for _, ts := range tests {
+		t.Run(ts.name, func(t *testing.T) {
+			repoMock := new(DbMock)
+			repoMock.On("AdminUsers", mock.Anything, ts.want).Return(domain.AdminUserList{Total: 1}, ts.repoErr)
+
+			_, err := CreateAdminServer(repoMock, logrus.New()).Users(context.Background(), ts.filter)
+
+			if ts.validateErr {
+				var validErr validator.ValidationErrors
+				assert.ErrorAs(t, err, &validErr)
+				repoMock.AssertNotCalled(t, "AdminUsers", mock.Anything, mock.Anything)
+				return
+			}
+			assert.ErrorIs(t, err, ts.wantErr)
+			repoMock.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSetDisabled(t *testing.T) {
+	tests := []struct {
+		name         string
+		userID       uint
+		disabled     bool
+		repoErr      error
+		wantErr      error
+		shouldCallDB bool
+	}{
+		{name: "success disable", userID: 3, disabled: true, shouldCallDB: true},
+		{name: "success enable", userID: 3, shouldCallDB: true},
+		{name: "error self", userID: 1, disabled: true, wantErr: ErrSelf},
+		{name: "error not found", userID: 3, disabled: true, repoErr: postgresql.ErrorNotFound, wantErr: ErrNoFound, shouldCallDB: true},
+	}
+
+	for _, ts := range tests {
+		t.Run(ts.name, func(t *testing.T) {
+			repoMock := new(DbMock)
+			repoMock.On("SetUserDisabled", mock.Anything, ts.userID, ts.disabled).Return(ts.repoErr)
+
+			err := CreateAdminServer(repoMock, logrus.New()).SetDisabled(context.Background(), 1, ts.userID, ts.disabled)
+
+			assert.ErrorIs(t, err, ts.wantErr)
+			if ts.shouldCallDB {
+				repoMock.AssertExpectations(t)
+			} else {
+				repoMock.AssertNotCalled(t, "SetUserDisabled", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestSetRole(t *testing.T) {
+	tests := []struct {
+		name         string
+		userID       uint
+		role         string
+		wantErr      error
+		validateErr  bool
+		shouldCallDB bool
+	}{
+		{name: "success promote", userID: 3, role: domain.RoleAdmin, shouldCallDB: true},
+		{name: "success demote", userID: 3, role: domain.RoleUser, shouldCallDB: true},
+		{name: "error demote self", userID: 1, role: domain.RoleUser, wantErr: ErrSelf},
+		{name: "error role", userID: 3, role: "root", validateErr: true},
+	}
+
+	for _, ts := range tests {
+		t.Run(ts.name, func(t *testing.T) {
+			repoMock := new(DbMock)
+			repoMock.On("SetUserRole", mock.Anything, ts.userID, ts.role).Return(nil)
+
+			err := CreateAdminServer(repoMock, logrus.New()).SetRole(context.Background(), 1, ts.userID, domain.UpdateRole{Role: ts.role})
+
+			if ts.validateErr {
+				var validErr validator.ValidationErrors
+				assert.ErrorAs(t, err, &validErr)
+			} else {
+				assert.ErrorIs(t, err, ts.wantErr)
+			}
+			if ts.shouldCallDB {
+				repoMock.AssertExpectations(t)
+			} else {
+				repoMock.AssertNotCalled(t, "SetUserRole", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
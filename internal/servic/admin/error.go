@@ -0,0 +1,26 @@
+package admin
+
+import (
+	"errors"
+
+	"github.com/financial_tracer/internal/infastructure/db/postgresql"
+)
+
+var (
+	ErrDatabase = errors.New("error database")
+	ErrNoFound  = errors.New("user is not found")
+	ErrSelf     = errors.New("admins can't disable or demote themselves")
+)
+
+func RegisterErrDatabase(err error) error {
+	arr := map[error]error{
+		postgresql.ErrorNotFound: ErrNoFound,
+	}
+
+	value, ok := arr[err]
+	if !ok {
+		return ErrDatabase
+	}
+
+	return value
+}
@@ -10,23 +10,23 @@ import (
 )
 
 type CreateCategoryRepository interface {
-	CreateCategory(ctx context.Context, id uint, category domain.CategoryInput) (uint, error)
+	CreateCategory(ctx context.Context, m domain.Member, category domain.CategoryInput) (uint, error)
 }
 
 type GetCategoryRepository interface {
-	GetCategory(ctx context.Context, idCategory uint) (domain.CategoryOutput, error)
+	GetCategory(ctx context.Context, m domain.Member, idCategory uint) (domain.CategoryOutput, error)
 }
 
 type UpdateCategoryRepository interface {
-	UpdateCategory(ctx context.Context, idCategory uint, newCategory domain.CategoryInput) (domain.CategoryOutput, error)
+	UpdateCategory(ctx context.Context, m domain.Member, idCategory uint, newCategory domain.CategoryInput) (domain.CategoryOutput, error)
 }
 
 type DeleteCategoryRepository interface {
-	DeleteCategory(ctx context.Context, idCategory uint) error
+	DeleteCategory(ctx context.Context, m domain.Member, idCategory uint) error
 }
 
 type CategoryTypeRepository interface {
-	CategoriesType(ctx context.Context, typeFound string) ([]domain.CategoryOutput, error)
+	CategoriesType(ctx context.Context, m domain.Member, typeFound string) ([]domain.CategoryOutput, error)
 }
 
 type Redis interface {
@@ -65,12 +65,13 @@ func CreateCategoryServer(d DeleteCategoryRepository,
 	}
 }
 
-func (cs *CategoryServer) CreateCategory(ctx context.Context, userID uint, category domain.CategoryInput) (uint, error) {
+func (cs *CategoryServer) CreateCategory(ctx context.Context, m domain.Member, category domain.CategoryInput) (uint, error) {
 	const op = "category.CreateCategory"
 
 	log := cs.log.WithFields(logrus.Fields{
-		"op":      op,
-		"user_id": userID,
+		"op":           op,
+		"user_id":      m.UserID,
+		"household_id": m.HouseholdID,
 	})
 
 	log.Info("start create category")
@@ -81,7 +82,7 @@ func (cs *CategoryServer) CreateCategory(ctx context.Context, userID uint, categ
 		return 0, err
 	}
 
-	id, err := cs.c.CreateCategory(ctx, userID, category)
+	id, err := cs.c.CreateCategory(ctx, m, category)
 	if err != nil {
 		log.Error("error create category: ", err)
 		return 0, RegsiterErrorDatabase(err)
@@ -95,7 +96,8 @@ func (cs *CategoryServer) CreateCategory(ctx context.Context, userID uint, categ
 			Description: category.Description,
 			Type:        category.Type,
 			Limit:       category.Limit,
-			UserID:      userID,
+			UserID:      m.UserID,
+			HouseholdID: m.HouseholdID,
 		}
 		err := cs.rbd.HsetCategory(ctx, id, category)
 		canal <- err
@@ -110,23 +112,25 @@ func (cs *CategoryServer) CreateCategory(ctx context.Context, userID uint, categ
 	return id, nil
 }
 
-func (cs *CategoryServer) GetCategory(ctx context.Context, idCategory uint) (domain.CategoryOutput, error) {
+func (cs *CategoryServer) GetCategory(ctx context.Context, m domain.Member, idCategory uint) (domain.CategoryOutput, error) {
 	const op = "category.GetCategory"
 
 	log := cs.log.WithFields(logrus.Fields{
-		"op":          op,
-		"category_id": idCategory,
+		"op":           op,
+		"category_id":  idCategory,
+		"household_id": m.HouseholdID,
 	})
 
 	log.Info("start get category")
 
 	result, err := cs.rbd.HgetCategory(ctx, idCategory)
-	if err == nil {
+	if err == nil && result["householdID"] == strconv.FormatUint(uint64(m.HouseholdID), 10) {
 		log.Info("get cateogry is cash: ", result)
 		limit, _ := strconv.Atoi(result["limit"])
 		userID, _ := strconv.Atoi(result["userID"])
 		return domain.CategoryOutput{
 			UserID:      uint(userID),
+			HouseholdID: m.HouseholdID,
 			Name:        result["name"],
 			Limit:       limit,
 			Description: result["description"],
@@ -136,7 +140,7 @@ func (cs *CategoryServer) GetCategory(ctx context.Context, idCategory uint) (dom
 		log.Info("error cash", err)
 	}
 
-	category, err := cs.g.GetCategory(ctx, idCategory)
+	category, err := cs.g.GetCategory(ctx, m, idCategory)
 	if err != nil {
 		log.Error("error get category: ", err)
 		return domain.CategoryOutput{}, RegsiterErrorDatabase(err)
@@ -146,12 +150,13 @@ func (cs *CategoryServer) GetCategory(ctx context.Context, idCategory uint) (dom
 	return category, nil
 }
 
-func (cs *CategoryServer) UpdateCategory(ctx context.Context, idCategory uint, newCategory domain.CategoryInput) (domain.CategoryOutput, error) {
+func (cs *CategoryServer) UpdateCategory(ctx context.Context, m domain.Member, idCategory uint, newCategory domain.CategoryInput) (domain.CategoryOutput, error) {
 	const op = "category.UpdateCategory"
 
 	log := cs.log.WithFields(logrus.Fields{
-		"op":          op,
-		"category_id": idCategory,
+		"op":           op,
+		"category_id":  idCategory,
+		"household_id": m.HouseholdID,
 	})
 
 	log.Info("start update category")
@@ -162,7 +167,7 @@ func (cs *CategoryServer) UpdateCategory(ctx context.Context, idCategory uint, n
 		return domain.CategoryOutput{}, err
 	}
 
-	category, err := cs.u.UpdateCategory(ctx, idCategory, newCategory)
+	category, err := cs.u.UpdateCategory(ctx, m, idCategory, newCategory)
 	if err != nil {
 		log.Error("error update category: ", err)
 		return domain.CategoryOutput{}, RegsiterErrorDatabase(err)
@@ -184,17 +189,18 @@ func (cs *CategoryServer) UpdateCategory(ctx context.Context, idCategory uint, n
 	return category, nil
 }
 
-func (cs *CategoryServer) DeleteCategory(ctx context.Context, idCategory uint) error {
+func (cs *CategoryServer) DeleteCategory(ctx context.Context, m domain.Member, idCategory uint) error {
 	const op = "category.DeleteCategory"
 
 	log := cs.log.WithFields(logrus.Fields{
-		"op":          op,
-		"category_id": idCategory,
+		"op":           op,
+		"category_id":  idCategory,
+		"household_id": m.HouseholdID,
 	})
 
 	log.Info("start delete category")
 
-	err := cs.d.DeleteCategory(ctx, idCategory)
+	err := cs.d.DeleteCategory(ctx, m, idCategory)
 	if err != nil {
 		log.Error("error delete category: ", err)
 		return RegsiterErrorDatabase(err)
@@ -217,12 +223,13 @@ func (cs *CategoryServer) DeleteCategory(ctx context.Context, idCategory uint) e
 	
 }
 
-func (cs *CategoryServer) CategoryType(ctx context.Context, typeFound string) ([]domain.CategoryOutput, error) {
+func (cs *CategoryServer) CategoryType(ctx context.Context, m domain.Member, typeFound string) ([]domain.CategoryOutput, error) {
 	const op = "category.CategoryType"
 
 	log := cs.log.WithFields(logrus.Fields{
-		"op":   op,
-		"type": typeFound,
+		"op":           op,
+		"type":         typeFound,
+		"household_id": m.HouseholdID,
 	})
 
 	log.Info("start gets categories type")
@@ -232,7 +239,7 @@ func (cs *CategoryServer) CategoryType(ctx context.Context, typeFound string) ([
 		return []domain.CategoryOutput{}, ErrValidateType
 	}
 
-	result, err := cs.t.CategoriesType(ctx, typeFound)
+	result, err := cs.t.CategoriesType(ctx, m, typeFound)
 	if err != nil {
 		log.Error("error find category type: ", err)
 		return []domain.CategoryOutput{}, RegsiterErrorDatabase(err)
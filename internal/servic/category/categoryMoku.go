@@ -11,27 +11,27 @@ type DbMock struct {
 	mock.Mock
 }
 
-func (d *DbMock) CreateCategory(ctx context.Context, id uint, category domain.CategoryInput) (uint, error) {
-	args := d.Called(ctx, id, category)
+func (d *DbMock) CreateCategory(ctx context.Context, m domain.Member, category domain.CategoryInput) (uint, error) {
+	args := d.Called(ctx, m, category)
 	return args.Get(0).(uint), args.Error(1)
 }
 
-func (d *DbMock) GetCategory(ctx context.Context, idCategory uint) (domain.CategoryOutput, error) {
-	args := d.Called(ctx, idCategory)
+func (d *DbMock) GetCategory(ctx context.Context, m domain.Member, idCategory uint) (domain.CategoryOutput, error) {
+	args := d.Called(ctx, m, idCategory)
 	return args.Get(0).(domain.CategoryOutput), args.Error(1)
 }
 
-func (d *DbMock) UpdateCategory(ctx context.Context, idCategory uint, newCategory domain.CategoryInput) (domain.CategoryOutput, error) {
-	args := d.Called(ctx, idCategory, newCategory)
+func (d *DbMock) UpdateCategory(ctx context.Context, m domain.Member, idCategory uint, newCategory domain.CategoryInput) (domain.CategoryOutput, error) {
+	args := d.Called(ctx, m, idCategory, newCategory)
 	return args.Get(0).(domain.CategoryOutput), args.Error(1)
 }
 
-func (d *DbMock) DeleteCategory(ctx context.Context, idCategory uint) error {
-	args := d.Called(ctx, idCategory)
+func (d *DbMock) DeleteCategory(ctx context.Context, m domain.Member, idCategory uint) error {
+	args := d.Called(ctx, m, idCategory)
 	return args.Error(0)
 }
 
-func (d *DbMock) CategoriesType(ctx context.Context, typeFound string) ([]domain.CategoryOutput, error) {
-	args := d.Called(ctx, typeFound)
+func (d *DbMock) CategoriesType(ctx context.Context, m domain.Member, typeFound string) ([]domain.CategoryOutput, error) {
+	args := d.Called(ctx, m, typeFound)
 	return args.Get(0).([]domain.CategoryOutput), args.Error(1)
 }
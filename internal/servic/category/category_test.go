@@ -15,6 +15,8 @@ import (
 	"github.com/stretchr/testify/mock"
 )
 
+var member = domain.Member{UserID: 1, HouseholdID: 3, Role: domain.HouseholdOwner}
+
 func TestCreateCategory(t *testing.T) {
 
 	type tests struct {
@@ -138,10 +140,12 @@ func TestCreateCategory(t *testing.T) {
 				Type:        test.category.Type,
 				Limit:       test.category.Limit,
 				UserID:      test.userID,
+				HouseholdID: member.HouseholdID,
 			}
+			m := domain.Member{UserID: test.userID, HouseholdID: member.HouseholdID, Role: domain.HouseholdOwner}
 
 			if test.shouldCallDB {
-				repoMock.On("CreateCategory", mock.Anything, test.userID, test.category).Return(test.categoryID, test.mockErr)
+				repoMock.On("CreateCategory", mock.Anything, m, test.category).Return(test.categoryID, test.mockErr)
 			}
 
 			if test.shouldCallRedis {
@@ -151,7 +155,7 @@ func TestCreateCategory(t *testing.T) {
 			log := logrus.New()
 
 			servic := CreateCategoryServer(repoMock, repoMock, repoMock, repoMock, repoMock, log, r)
-			resultID, err := servic.CreateCategory(context.Background(), test.userID, test.category)
+			resultID, err := servic.CreateCategory(context.Background(), m, test.category)
 
 			if test.mockErr != nil || test.categoryErr != nil {
 				assert.Error(t, err)
@@ -169,9 +173,9 @@ func TestCreateCategory(t *testing.T) {
 			}
 
 			if test.shouldCallDB {
-				repoMock.AssertCalled(t, "CreateCategory", mock.Anything, test.userID, test.category)
+				repoMock.AssertCalled(t, "CreateCategory", mock.Anything, m, test.category)
 			} else {
-				repoMock.AssertNotCalled(t, "CreateCategory", mock.Anything, test.userID, test.category)
+				repoMock.AssertNotCalled(t, "CreateCategory", mock.Anything, m, test.category)
 			}
 
 			if test.shouldCallRedis {
@@ -213,6 +217,7 @@ func TestGetCategory(t *testing.T) {
 			categoryErr: nil,
 			redisData: map[string]string{
 				"userID":      "2",
+				"householdID": "3",
 				"name":        "food",
 				"limit":       "10000",
 				"description": "траты на еду",
@@ -222,6 +227,38 @@ func TestGetCategory(t *testing.T) {
 			shouldCallRedis: true,
 			shouldCallDB:    false,
 		},
+		{
+			Name: "cached for another household",
+			category: domain.CategoryOutput{
+				UserID:      2,
+				HouseholdID: 3,
+				Name:        "food",
+				Limit:       10000,
+			},
+			categoryID:  2,
+			mockErr:     nil,
+			categoryErr: nil,
+			redisData: map[string]string{
+				"userID":      "2",
+				"householdID": "4",
+				"name":        "rent",
+				"limit":       "500",
+			},
+			redisErr:        nil,
+			shouldCallRedis: true,
+			shouldCallDB:    true,
+		},
+		{
+			Name:            "forbidden",
+			category:        domain.CategoryOutput{},
+			categoryID:      5,
+			mockErr:         postgresql.ErrorForbidden,
+			categoryErr:     ErrForbidden,
+			redisData:       nil,
+			redisErr:        errors.New("cache miss"),
+			shouldCallRedis: true,
+			shouldCallDB:    true,
+		},
 		{
 			Name: "success from database (cache miss)",
 			category: domain.CategoryOutput{
@@ -277,7 +314,7 @@ func TestGetCategory(t *testing.T) {
 			repoMock.Calls = nil
 
 			if test.shouldCallDB {
-				repoMock.On("GetCategory", mock.Anything, test.categoryID).Return(test.category, test.mockErr)
+				repoMock.On("GetCategory", mock.Anything, member, test.categoryID).Return(test.category, test.mockErr)
 			}
 
 			log := logrus.New()
@@ -287,7 +324,7 @@ func TestGetCategory(t *testing.T) {
 			}
 
 			servic := CreateCategoryServer(repoMock, repoMock, repoMock, repoMock, repoMock, log, r)
-			resultCategory, err := servic.GetCategory(context.Background(), test.categoryID)
+			resultCategory, err := servic.GetCategory(context.Background(), member, test.categoryID)
 
 			if test.categoryErr != nil {
 				assert.Error(t, err)
@@ -297,7 +334,7 @@ func TestGetCategory(t *testing.T) {
 
 			} else {
 				assert.NoError(t, err)
-				if test.shouldCallRedis && test.redisErr == nil {
+				if test.shouldCallRedis && test.redisErr == nil && !test.shouldCallDB {
 					userID, _ := strconv.Atoi(test.redisData["userID"])
 					assert.Equal(t, uint(userID), resultCategory.UserID)
 					assert.Equal(t, test.redisData["name"], resultCategory.Name)
@@ -315,9 +352,9 @@ func TestGetCategory(t *testing.T) {
 			}
 
 			if test.shouldCallDB {
-				repoMock.AssertCalled(t, "GetCategory", mock.Anything, test.categoryID)
+				repoMock.AssertCalled(t, "GetCategory", mock.Anything, member, test.categoryID)
 			} else {
-				repoMock.AssertNotCalled(t, "GetCategory", mock.Anything, test.categoryID)
+				repoMock.AssertNotCalled(t, "GetCategory", mock.Anything, member, test.categoryID)
 			}
 		})
 	}
@@ -437,7 +474,7 @@ func TestUpdateCategory(t *testing.T) {
 			repoMock.Calls = nil
 
 			if test.shouldCallDB {
-				repoMock.On("UpdateCategory", mock.Anything, test.categoryID, test.newCategory).Return(test.category, test.mockErr)
+				repoMock.On("UpdateCategory", mock.Anything, member, test.categoryID, test.newCategory).Return(test.category, test.mockErr)
 			}
 
 			log := logrus.New()
@@ -447,7 +484,7 @@ func TestUpdateCategory(t *testing.T) {
 			}
 
 			servic := CreateCategoryServer(repoMock, repoMock, repoMock, repoMock, repoMock, log, r)
-			_, err := servic.UpdateCategory(context.Background(), test.categoryID, test.newCategory)
+			_, err := servic.UpdateCategory(context.Background(), member, test.categoryID, test.newCategory)
 
 			if test.mockErr != nil || test.categoryErr != nil {
 				assert.Error(t, err)
@@ -464,7 +501,7 @@ func TestUpdateCategory(t *testing.T) {
 			}
 
 			if test.shouldCallDB {
-				repoMock.AssertCalled(t, "UpdateCategory", mock.Anything, test.categoryID, test.newCategory)
+				repoMock.AssertCalled(t, "UpdateCategory", mock.Anything, member, test.categoryID, test.newCategory)
 			}
 
 			if test.shouldCallRedis {
@@ -519,7 +556,7 @@ func TestDeleteCategoryDatabase(t *testing.T) {
 			repoMock.ExpectedCalls = nil
 			repoMock.Calls = nil
 
-			repoMock.On("DeleteCategory", mock.Anything, test.categoryID).Return(test.mockErr)
+			repoMock.On("DeleteCategory", mock.Anything, member, test.categoryID).Return(test.mockErr)
 			log := logrus.New()
 
 			if test.shouldCallRedis {
@@ -527,7 +564,7 @@ func TestDeleteCategoryDatabase(t *testing.T) {
 			}
 
 			servic := CreateCategoryServer(repoMock, repoMock, repoMock, repoMock, repoMock, log, r)
-			err := servic.DeleteCategory(context.Background(), test.categoryID)
+			err := servic.DeleteCategory(context.Background(), member, test.categoryID)
 
 			if test.mockErr != nil || test.categoryErr != nil {
 				assert.Error(t, err)
@@ -537,7 +574,7 @@ func TestDeleteCategoryDatabase(t *testing.T) {
 			} else {
 				assert.NoError(t, err)
 			}
-			repoMock.AssertCalled(t, "DeleteCategory", mock.Anything, test.categoryID)
+			repoMock.AssertCalled(t, "DeleteCategory", mock.Anything, member, test.categoryID)
 
 			if test.shouldCallRedis {
 				r.AssertCalled(t, "HdelCategory", context.Background(), test.categoryID)
@@ -612,13 +649,13 @@ func TestCategoryType(t *testing.T) {
 			r := new(cash.RedisMock)
 
 			if test.shouldCallDB {
-				repoMock.On("CategoriesType", mock.Anything, test.typeFound).Return(test.categories, test.mockErr)
+				repoMock.On("CategoriesType", mock.Anything, member, test.typeFound).Return(test.categories, test.mockErr)
 			}
 
 			log := logrus.New()
 
 			servic := CreateCategoryServer(repoMock, repoMock, repoMock, repoMock, repoMock, log, r)
-			resultCategories, err := servic.CategoryType(context.Background(), test.typeFound)
+			resultCategories, err := servic.CategoryType(context.Background(), member, test.typeFound)
 
 			if test.categoryErr != nil {
 				assert.Error(t, err)
@@ -632,7 +669,7 @@ func TestCategoryType(t *testing.T) {
 			}
 
 			if test.shouldCallDB {
-				repoMock.AssertCalled(t, "CategoriesType", mock.Anything, test.typeFound)
+				repoMock.AssertCalled(t, "CategoriesType", mock.Anything, member, test.typeFound)
 			}
 			repoMock.AssertExpectations(t)
 		})
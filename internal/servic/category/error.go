@@ -11,6 +11,7 @@ var (
 	ErrNoFound      = errors.New("category is not found")
 	ErrDuplicated   = errors.New("category is duplicated")
 	ErrValidateType = errors.New("invalid type category")
+	ErrForbidden    = errors.New("no access to the household")
 )
 
 func RegsiterErrorDatabase(err error) error {
@@ -18,6 +19,7 @@ func RegsiterErrorDatabase(err error) error {
 		postgresql.ErrorDuplicated: ErrDuplicated,
 		postgresql.ErrorNotFound:   ErrNoFound,
 		postgresql.ErrorLimit:      ErrValidateType,
+		postgresql.ErrorForbidden:  ErrForbidden,
 	}
 
 	value, ok := arr[err]
@@ -0,0 +1,146 @@
+package comparison
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+)
+
+type CategoryTotalsRepository interface {
+	CategoryTotals(ctx context.Context, m domain.Member, from time.Time, to time.Time) ([]domain.CategoryTotal, error)
+}
+
+type ComparisonServer struct {
+	t        CategoryTotalsRepository
+	log      *logrus.Logger
+	validate validator.Validate
+}
+
+func CreateComparisonServer(t CategoryTotalsRepository, log *logrus.Logger) *ComparisonServer {
+	return &ComparisonServer{
+		t:        t,
+		log:      log,
+		validate: *validator.New(),
+	}
+}
+
+// Compare falls back to the same period one year earlier when the previous one is empty.
+func (cs *ComparisonServer) Compare(ctx context.Context, m domain.Member, current domain.Period, previous domain.Period, pref domain.Preferences) (domain.PeriodComparison, error) {
+	const op = "comparison.Compare"
+
+	if previous == (domain.Period{}) {
+		previous = domain.Period{
+			From: current.From.AddDate(-1, 0, 0),
+			To:   current.To.AddDate(-1, 0, 0),
+		}
+	}
+
+	log := cs.log.WithFields(logrus.Fields{
+		"op":            op,
+		"user_id":       m.UserID,
+		"household_id":  m.HouseholdID,
+		"current_from":  current.From,
+		"current_to":    current.To,
+		"previous_from": previous.From,
+		"previous_to":   previous.To,
+	})
+
+	log.Info("start compare periods")
+
+	if err := cs.validate.Struct(current); err != nil {
+		log.WithField("err", err).Error("invalid validate")
+		return domain.PeriodComparison{}, err
+	}
+
+	if err := cs.validate.Struct(previous); err != nil {
+		log.WithField("err", err).Error("invalid validate")
+		return domain.PeriodComparison{}, err
+	}
+
+	currentTotals, err := cs.t.CategoryTotals(ctx, m, current.From, current.To)
+	if err != nil {
+		log.Error("error get current totals: ", err)
+		return domain.PeriodComparison{}, RegisterErrDatabase(err)
+	}
+
+	previousTotals, err := cs.t.CategoryTotals(ctx, m, previous.From, previous.To)
+	if err != nil {
+		log.Error("error get previous totals: ", err)
+		return domain.PeriodComparison{}, RegisterErrDatabase(err)
+	}
+
+	res := domain.PeriodComparison{
+		Current:    current,
+		Previous:   previous,
+		Currency:   pref.Currency,
+		Categories: merge(currentTotals, previousTotals),
+	}
+
+	var cur, prev int
+	for _, c := range res.Categories {
+		cur += c.Current
+		prev += c.Previous
+	}
+	res.Total = compare(cur, prev)
+
+	log.Info("success compare periods")
+
+	return res, nil
+}
+
+func merge(current []domain.CategoryTotal, previous []domain.CategoryTotal) []domain.CategoryComparison {
+	res := make([]domain.CategoryComparison, 0, len(current)+len(previous))
+
+	i, j := 0, 0
+	for i < len(current) || j < len(previous) {
+		var c domain.CategoryComparison
+		switch {
+		case j == len(previous) || i < len(current) && current[i].CategoryID < previous[j].CategoryID:
+			c = domain.CategoryComparison{
+				CategoryID: current[i].CategoryID,
+				Name:       current[i].Name,
+				Comparison: compare(current[i].Total, 0),
+				Status:     domain.ComparisonNew,
+			}
+			i++
+		case i == len(current) || previous[j].CategoryID < current[i].CategoryID:
+			c = domain.CategoryComparison{
+				CategoryID: previous[j].CategoryID,
+				Name:       previous[j].Name,
+				Comparison: compare(0, previous[j].Total),
+				Status:     domain.ComparisonDisappeared,
+			}
+			j++
+		default:
+			c = domain.CategoryComparison{
+				CategoryID: current[i].CategoryID,
+				Name:       current[i].Name,
+				Comparison: compare(current[i].Total, previous[j].Total),
+			}
+			i++
+			j++
+		}
+		res = append(res, c)
+	}
+
+	return res
+}
+
+func compare(current int, previous int) domain.Comparison {
+	c := domain.Comparison{
+		Current:    current,
+		Previous:   previous,
+		Difference: current - previous,
+	}
+
+	if previous != 0 {
+		percent := math.Round(float64(c.Difference)/float64(previous)*10000) / 100
+		c.Percent = &percent
+	}
+
+	return c
+}
@@ -0,0 +1,18 @@
+package comparison
+
+import (
+	"context"
+	"time"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type DbMock struct {
+	mock.Mock
+}
+
+func (d *DbMock) CategoryTotals(ctx context.Context, m domain.Member, from time.Time, to time.Time) ([]domain.CategoryTotal, error) {
+	args := d.Called(ctx, m, from, to)
+	return args.Get(0).([]domain.CategoryTotal), args.Error(1)
+}
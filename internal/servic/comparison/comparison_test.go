@@ -0,0 +1,129 @@
+package comparison
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func percent(v float64) *float64 {
+	return &v
+}
+
+var member = domain.Member{UserID: 1, HouseholdID: 3, Role: domain.HouseholdViewer}
+
+func TestCompare(t *testing.T) {
+	q3 := domain.Period{From: date(2025, time.July, 1), To: date(2025, time.October, 1)}
+	q2 := domain.Period{From: date(2025, time.April, 1), To: date(2025, time.July, 1)}
+	lastYear := domain.Period{From: date(2024, time.July, 1), To: date(2024, time.October, 1)}
+
+	type test struct {
+		name           string
+		current        domain.Period
+		previous       domain.Period
+		wantPrevious   domain.Period
+		currentTotals  []domain.CategoryTotal
+		previousTotals []domain.CategoryTotal
+		mockErr        error
+		wantErr        error
+		want           domain.PeriodComparison
+		shouldCallDB   bool
+	}
+
+	tests := []test{
+		{
+			name:         "quarter over quarter",
+			current:      q3,
+			previous:     q2,
+			wantPrevious: q2,
+			currentTotals: []domain.CategoryTotal{
+				{CategoryID: 1, Name: "food", Total: 1500, Transactions: 10},
+				{CategoryID: 3, Name: "travel", Total: 800, Transactions: 1},
+			},
+			previousTotals: []domain.CategoryTotal{
+				{CategoryID: 1, Name: "food", Total: 1200, Transactions: 9},
+				{CategoryID: 2, Name: "car", Total: 300, Transactions: 2},
+			},
+			want: domain.PeriodComparison{
+				Current:  q3,
+				Previous: q2,
+				Currency: "RUB",
+				Total:    domain.Comparison{Current: 2300, Previous: 1500, Difference: 800, Percent: percent(53.33)},
+				Categories: []domain.CategoryComparison{
+					{CategoryID: 1, Name: "food", Comparison: domain.Comparison{Current: 1500, Previous: 1200, Difference: 300, Percent: percent(25)}},
+					{CategoryID: 2, Name: "car", Comparison: domain.Comparison{Previous: 300, Difference: -300, Percent: percent(-100)}, Status: domain.ComparisonDisappeared},
+					{CategoryID: 3, Name: "travel", Comparison: domain.Comparison{Current: 800, Difference: 800}, Status: domain.ComparisonNew},
+				},
+			},
+			shouldCallDB: true,
+		},
+		{
+			name:         "year over year by default",
+			current:      q3,
+			wantPrevious: lastYear,
+			want: domain.PeriodComparison{
+				Current:    q3,
+				Previous:   lastYear,
+				Currency:   "RUB",
+				Categories: []domain.CategoryComparison{},
+			},
+			shouldCallDB: true,
+		},
+		{
+			name:         "error period",
+			current:      domain.Period{From: q3.To, To: q3.From},
+			previous:     q2,
+			wantErr:      validator.ValidationErrors{},
+			shouldCallDB: false,
+		},
+		{
+			name:         "error database",
+			current:      q3,
+			previous:     q2,
+			wantPrevious: q2,
+			mockErr:      errors.New("some db error"),
+			wantErr:      ErrDatabase,
+			shouldCallDB: true,
+		},
+	}
+
+	for _, ts := range tests {
+		t.Run(ts.name, func(t *testing.T) {
+			repoMock := new(DbMock)
+			repoMock.On("CategoryTotals", mock.Anything, member, ts.current.From, ts.current.To).Return(ts.currentTotals, ts.mockErr)
+			repoMock.On("CategoryTotals", mock.Anything, member, ts.wantPrevious.From, ts.wantPrevious.To).Return(ts.previousTotals, nil)
+
+			server := CreateComparisonServer(repoMock, logrus.New())
+			res, err := server.Compare(context.Background(), member, ts.current, ts.previous, domain.Preferences{Currency: "RUB"})
+
+			if ts.wantErr != nil {
+				var validErr validator.ValidationErrors
+				if errors.As(ts.wantErr, &validErr) {
+					assert.True(t, errors.As(err, &validErr))
+				} else {
+					assert.ErrorIs(t, err, ts.wantErr)
+				}
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, ts.want, res)
+			}
+
+			if ts.shouldCallDB {
+				repoMock.AssertCalled(t, "CategoryTotals", mock.Anything, member, ts.current.From, ts.current.To)
+			} else {
+				repoMock.AssertNotCalled(t, "CategoryTotals", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
@@ -0,0 +1,168 @@
+package deletion
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/financial_tracer/internal/infastructure/db/postgresql"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	DefaultGrace    = 30 * 24 * time.Hour
+	DefaultInterval = time.Hour
+)
+
+type DeletionRepository interface {
+	DeleteUser(ctx context.Context, email string, password string, deleteAt time.Time) (domain.User, error)
+	DueDeletions(ctx context.Context, now time.Time) ([]uint, error)
+	PurgeUser(ctx context.Context, userID uint, now time.Time) (domain.PurgedUser, error)
+}
+
+type Mailer interface {
+	Send(ctx context.Context, msg domain.Mail) error
+}
+
+type Cache interface {
+	DelUser(ctx context.Context, categoryIDs []uint, transactionIDs []uint) error
+}
+
+type Storage interface {
+	Remove(name string) error
+}
+
+// Grace is how long a login still restores the account, Interval how often the due accounts are purged.
+type Options struct {
+	Grace    time.Duration
+	Interval time.Duration
+}
+
+type DeletionServer struct {
+	r        DeletionRepository
+	m        Mailer
+	c        Cache
+	st       Storage
+	opt      Options
+	log      *logrus.Logger
+	validate validator.Validate
+	now      func() time.Time
+}
+
+func CreateDeletionServer(r DeletionRepository, m Mailer, c Cache, st Storage, opt Options, log *logrus.Logger, now func() time.Time) *DeletionServer {
+	if opt.Grace == 0 {
+		opt.Grace = DefaultGrace
+	}
+	if opt.Interval == 0 {
+		opt.Interval = DefaultInterval
+	}
+
+	return &DeletionServer{
+		r:        r,
+		m:        m,
+		c:        c,
+		st:       st,
+		opt:      opt,
+		log:      log,
+		validate: *validator.New(),
+		now:      now,
+	}
+}
+
+// DeleteUser does not fail when the mail can't be sent.
+func (ds *DeletionServer) DeleteUser(ctx context.Context, us domain.DeleteUser) (domain.AccountDeletion, error) {
+	const op = "deletion.DeleteUser"
+
+	log := ds.log.WithField("op", op)
+
+	log.Info("start delete user")
+
+	if err := ds.validate.Struct(us); err != nil {
+		log.WithField("err", err).Error("invalid validate")
+		return domain.AccountDeletion{}, err
+	}
+
+	deleteAt := ds.now().Add(ds.opt.Grace)
+
+	user, err := ds.r.DeleteUser(ctx, us.Email, us.Password, deleteAt)
+	if err != nil {
+		log.Error("error delete user: ", err)
+		return domain.AccountDeletion{}, RegisterErrDatabase(err)
+	}
+
+	if err := ds.m.Send(ctx, ds.deletionMail(user, deleteAt)); err != nil {
+		log.WithField("err", err).Error("error send deletion mail")
+	}
+
+	log.WithField("delete_at", deleteAt).Info("success delete user")
+
+	return domain.AccountDeletion{DeleteAt: deleteAt}, nil
+}
+
+func (ds *DeletionServer) Purge(ctx context.Context) (int, error) {
+	const op = "deletion.Purge"
+
+	log := ds.log.WithField("op", op)
+
+	now := ds.now()
+
+	ids, err := ds.r.DueDeletions(ctx, now)
+	if err != nil {
+		log.Error("error get due deletions: ", err)
+		return 0, ErrDatabase
+	}
+
+	count := 0
+	for _, id := range ids {
+		purged, err := ds.r.PurgeUser(ctx, id, now)
+		if err != nil {
+			if !errors.Is(err, postgresql.ErrorNotFound) {
+				log.WithFields(logrus.Fields{"user_id": id, "err": err}).Error("error purge user")
+			}
+			continue
+		}
+		count++
+
+		if err := ds.c.DelUser(ctx, purged.CategoryIDs, purged.TransactionIDs); err != nil {
+			log.WithFields(logrus.Fields{"user_id": id, "err": err}).Error("error delete cache")
+		}
+		for _, name := range purged.Files {
+			if err := ds.st.Remove(name); err != nil {
+				log.WithFields(logrus.Fields{"file": name, "err": err}).Error("error remove archive")
+			}
+		}
+
+		log.WithField("user_id", id).Info("user purged")
+	}
+
+	return count, nil
+}
+
+func (ds *DeletionServer) Run(ctx context.Context) {
+	ticker := time.NewTicker(ds.opt.Interval)
+	defer ticker.Stop()
+
+	for {
+		ds.Purge(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (ds *DeletionServer) deletionMail(user domain.User, deleteAt time.Time) domain.Mail {
+	return domain.Mail{
+		To:      user.Email,
+		Subject: "Удаление аккаунта financial_tracer",
+		Body: fmt.Sprintf("Здравствуйте, %s!\n\n"+
+			"Ваш аккаунт будет удален %s вместе со всеми категориями и транзакциями.\n"+
+			"Чтобы отменить удаление, просто войдите в аккаунт до этого времени.\n",
+			user.Name, deleteAt.UTC().Format("02.01.2006 15:04 UTC")),
+	}
+}
@@ -0,0 +1,55 @@
+package deletion
+
+import (
+	"context"
+	"time"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type DbMock struct {
+	mock.Mock
+}
+
+func (d *DbMock) DeleteUser(ctx context.Context, email string, password string, deleteAt time.Time) (domain.User, error) {
+	args := d.Called(ctx, email, password, deleteAt)
+	return args.Get(0).(domain.User), args.Error(1)
+}
+
+func (d *DbMock) DueDeletions(ctx context.Context, now time.Time) ([]uint, error) {
+	args := d.Called(ctx, now)
+	return args.Get(0).([]uint), args.Error(1)
+}
+
+func (d *DbMock) PurgeUser(ctx context.Context, userID uint, now time.Time) (domain.PurgedUser, error) {
+	args := d.Called(ctx, userID, now)
+	return args.Get(0).(domain.PurgedUser), args.Error(1)
+}
+
+type MailerMock struct {
+	mock.Mock
+}
+
+func (m *MailerMock) Send(ctx context.Context, msg domain.Mail) error {
+	args := m.Called(ctx, msg)
+	return args.Error(0)
+}
+
+type CacheMock struct {
+	mock.Mock
+}
+
+func (c *CacheMock) DelUser(ctx context.Context, categoryIDs []uint, transactionIDs []uint) error {
+	args := c.Called(ctx, categoryIDs, transactionIDs)
+	return args.Error(0)
+}
+
+type StorageMock struct {
+	mock.Mock
+}
+
+func (s *StorageMock) Remove(name string) error {
+	args := s.Called(name)
+	return args.Error(0)
+}
@@ -0,0 +1,126 @@
+package deletion
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/financial_tracer/internal/infastructure/db/postgresql"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestDeleteUser(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	deleteAt := now.Add(7 * 24 * time.Hour)
+	user := domain.User{Name: "jonn", Email: "jonn@gmail.com"}
+
+	tests := []struct {
+		name         string
+		req          domain.DeleteUser
+		mockErr      error
+		mailErr      error
+		wantErr      error
+		validateErr  bool
+		shouldCallDB bool
+	}{
+		{name: "success", req: domain.DeleteUser{Email: "jonn@gmail.com", Password: "admin12241532"}, shouldCallDB: true},
+		{name: "success mail error", req: domain.DeleteUser{Email: "jonn@gmail.com", Password: "admin12241532"}, mailErr: errors.New("connection refused"), shouldCallDB: true},
+		{name: "error password", req: domain.DeleteUser{Email: "jonn@gmail.com", Password: "admin15412"}, mockErr: postgresql.ErrorPassword, wantErr: ErrPassword, shouldCallDB: true},
+		{name: "error not found", req: domain.DeleteUser{Email: "jonn11@gmail.com", Password: "adminov"}, mockErr: postgresql.ErrorNotFound, wantErr: ErrNoFound, shouldCallDB: true},
+		{name: "error database", req: domain.DeleteUser{Email: "jonn@gmail.com", Password: "admin12241532"}, mockErr: errors.New("database down"), wantErr: ErrDatabase, shouldCallDB: true},
+		{name: "error validate", req: domain.DeleteUser{Email: "jonn@gmail.com"}, validateErr: true},
+	}
+
+	for _, ts := range tests {
+		t.Run(ts.name, func(t *testing.T) {
+			repoMock := new(DbMock)
+			repoMock.On("DeleteUser", mock.Anything, ts.req.Email, ts.req.Password, deleteAt).Return(user, ts.mockErr)
+			mailer := new(MailerMock)
+			mailer.On("Send", mock.Anything, mock.Anything).Return(ts.mailErr)
+
+			server := CreateDeletionServer(repoMock, mailer, new(CacheMock), new(StorageMock), Options{Grace: 7 * 24 * time.Hour}, logrus.New(), func() time.Time { return now })
+			deletion, err := server.DeleteUser(context.Background(), ts.req)
+
+			if ts.validateErr {
+				var validErr validator.ValidationErrors
+				assert.ErrorAs(t, err, &validErr)
+			} else {
+				assert.ErrorIs(t, err, ts.wantErr)
+			}
+
+			if ts.shouldCallDB {
+				repoMock.AssertExpectations(t)
+			} else {
+				repoMock.AssertNotCalled(t, "DeleteUser", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+
+			if ts.wantErr != nil || ts.validateErr {
+				mailer.AssertNotCalled(t, "Send", mock.Anything, mock.Anything)
+				return
+			}
+			assert.Equal(t, deleteAt, deletion.DeleteAt)
+			msg := mailer.Calls[0].Arguments.Get(1).(domain.Mail)
+			assert.Equal(t, user.Email, msg.To)
+			assert.True(t, strings.Contains(msg.Body, "08.03.2025 12:00 UTC"))
+		})
+	}
+}
+
+func TestPurge(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	repoMock := new(DbMock)
+	repoMock.On("DueDeletions", mock.Anything, now).Return([]uint{3, 4, 5}, nil)
+	repoMock.On("PurgeUser", mock.Anything, uint(3), now).Return(domain.PurgedUser{UserID: 3, CategoryIDs: []uint{7}, TransactionIDs: []uint{9, 10}, Files: []string{"export-3.zip"}}, nil)
+	repoMock.On("PurgeUser", mock.Anything, uint(4), now).Return(domain.PurgedUser{}, postgresql.ErrorNotFound)
+	repoMock.On("PurgeUser", mock.Anything, uint(5), now).Return(domain.PurgedUser{UserID: 5}, nil)
+	cache := new(CacheMock)
+	cache.On("DelUser", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	storage := new(StorageMock)
+	storage.On("Remove", "export-3.zip").Return(errors.New("no such file"))
+
+	server := CreateDeletionServer(repoMock, new(MailerMock), cache, storage, Options{}, logrus.New(), func() time.Time { return now })
+	count, err := server.Purge(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+	cache.AssertCalled(t, "DelUser", mock.Anything, []uint{7}, []uint{9, 10})
+	cache.AssertNumberOfCalls(t, "DelUser", 2)
+	storage.AssertExpectations(t)
+
+	t.Run("shared household", func(t *testing.T) {
+		purged := domain.PurgedUser{UserID: 3, CategoryIDs: []uint{7, 8}, TransactionIDs: []uint{9, 11}, Files: []string{"export-3.zip", "report-3.pdf"}}
+		repoMock := new(DbMock)
+		repoMock.On("DueDeletions", mock.Anything, now).Return([]uint{3}, nil)
+		repoMock.On("PurgeUser", mock.Anything, uint(3), now).Return(purged, nil)
+		cache := new(CacheMock)
+		cache.On("DelUser", mock.Anything, purged.CategoryIDs, purged.TransactionIDs).Return(errors.New("redis down"))
+		storage := new(StorageMock)
+		storage.On("Remove", "export-3.zip").Return(nil)
+		storage.On("Remove", "report-3.pdf").Return(nil)
+
+		server := CreateDeletionServer(repoMock, new(MailerMock), cache, storage, Options{}, logrus.New(), func() time.Time { return now })
+		count, err := server.Purge(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, count)
+		cache.AssertExpectations(t)
+		storage.AssertExpectations(t)
+	})
+
+	t.Run("error database", func(t *testing.T) {
+		repoMock := new(DbMock)
+		repoMock.On("DueDeletions", mock.Anything, now).Return([]uint(nil), errors.New("database down"))
+
+		server := CreateDeletionServer(repoMock, new(MailerMock), new(CacheMock), new(StorageMock), Options{}, logrus.New(), func() time.Time { return now })
+		_, err := server.Purge(context.Background())
+
+		assert.ErrorIs(t, err, ErrDatabase)
+	})
+}
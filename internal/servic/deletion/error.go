@@ -0,0 +1,29 @@
+package deletion
+
+import (
+	"errors"
+
+	"github.com/financial_tracer/internal/infastructure/db/postgresql"
+)
+
+var (
+	ErrDatabase = errors.New("error database")
+	ErrNoFound  = errors.New("user is not found")
+	ErrPassword = errors.New("wrong email or password")
+	ErrDisabled = errors.New("user is disabled")
+)
+
+func RegisterErrDatabase(err error) error {
+	arr := map[error]error{
+		postgresql.ErrorNotFound: ErrNoFound,
+		postgresql.ErrorPassword: ErrPassword,
+		postgresql.ErrorDisabled: ErrDisabled,
+	}
+
+	value, ok := arr[err]
+	if !ok {
+		return ErrDatabase
+	}
+
+	return value
+}
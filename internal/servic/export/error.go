@@ -0,0 +1,27 @@
+package export
+
+import (
+	"errors"
+
+	"github.com/financial_tracer/internal/infastructure/db/postgresql"
+)
+
+var (
+	ErrDatabase = errors.New("error database")
+	ErrServic   = errors.New("servic error")
+	ErrNoFound  = errors.New("export is not found")
+	ErrLink     = errors.New("invalid or expired download link")
+)
+
+func RegisterErrDatabase(err error) error {
+	arr := map[error]error{
+		postgresql.ErrorNotFound: ErrNoFound,
+	}
+
+	value, ok := arr[err]
+	if !ok {
+		return ErrDatabase
+	}
+
+	return value
+}
@@ -0,0 +1,332 @@
+package export
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/financial_tracer/internal/lib/archive"
+	"github.com/financial_tracer/internal/lib/signedLink"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	DefaultTTL     = 24 * time.Hour
+	DefaultWorkers = 2
+	DefaultURL     = "/financial_tracker/export/download"
+
+	// PendingTimeout is how long a pending export, lost with a restart, blocks a new one.
+	PendingTimeout = time.Hour
+)
+
+type ExportRepository interface {
+	CreateDataExport(ctx context.Context, userID uint, staleBefore time.Time) (domain.DataExport, bool, error)
+	CompleteDataExport(ctx context.Context, id uint, fileName string, size int64, expiresAt time.Time) error
+	FailDataExport(ctx context.Context, id uint, reason string) error
+	UserDataExports(ctx context.Context, userID uint) ([]domain.DataExport, error)
+	DataExport(ctx context.Context, userID uint, id uint) (domain.DataExport, error)
+	DeleteExpiredDataExports(ctx context.Context, now time.Time) ([]string, error)
+}
+
+type ArchiveRepository interface {
+	UserArchive(ctx context.Context, userID uint) (domain.Archive, error)
+	UserJournal(ctx context.Context, userID uint) (domain.Journal, error)
+}
+
+type Storage interface {
+	Create(name string) (io.WriteCloser, error)
+	Open(name string) (io.ReadCloser, error)
+	Remove(name string) error
+}
+
+// A random Secret makes the download links invalid after a restart.
+type Options struct {
+	TTL     time.Duration
+	URL     string
+	Secret  string
+	Workers int
+}
+
+type ExportServer struct {
+	e   ExportRepository
+	a   ArchiveRepository
+	st  Storage
+	opt Options
+	key []byte
+	log *logrus.Logger
+	now func() time.Time
+	sem chan struct{}
+	run func(func())
+}
+
+func CreateExportServer(e ExportRepository, a ArchiveRepository, st Storage, opt Options, log *logrus.Logger, now func() time.Time) (*ExportServer, error) {
+	if opt.TTL == 0 {
+		opt.TTL = DefaultTTL
+	}
+	if opt.URL == "" {
+		opt.URL = DefaultURL
+	}
+	if opt.Workers == 0 {
+		opt.Workers = DefaultWorkers
+	}
+
+	key := []byte(opt.Secret)
+	if len(key) == 0 {
+		key = make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return nil, fmt.Errorf("error generate export key: %w", err)
+		}
+	}
+
+	es := &ExportServer{
+		e:   e,
+		a:   a,
+		st:  st,
+		opt: opt,
+		key: key,
+		log: log,
+		now: now,
+		sem: make(chan struct{}, opt.Workers),
+	}
+	es.run = es.background
+
+	return es, nil
+}
+
+// RequestExport returns the pending export instead of starting another one.
+func (es *ExportServer) RequestExport(ctx context.Context, userID uint) (domain.DataExport, error) {
+	const op = "export.RequestExport"
+
+	log := es.log.WithFields(logrus.Fields{
+		"op":      op,
+		"user_id": userID,
+	})
+
+	log.Info("start request export")
+
+	es.cleanup(ctx)
+
+	export, created, err := es.e.CreateDataExport(ctx, userID, es.now().Add(-PendingTimeout))
+	if err != nil {
+		log.Error("error create export: ", err)
+		return domain.DataExport{}, RegisterErrDatabase(err)
+	}
+
+	if created {
+		es.run(func() {
+			es.build(context.WithoutCancel(ctx), export)
+		})
+	}
+
+	log.WithFields(logrus.Fields{
+		"export_id": export.ID,
+		"created":   created,
+	}).Info("success request export")
+
+	return export, nil
+}
+
+func (es *ExportServer) Exports(ctx context.Context, userID uint) ([]domain.DataExport, error) {
+	const op = "export.Exports"
+
+	log := es.log.WithFields(logrus.Fields{
+		"op":      op,
+		"user_id": userID,
+	})
+
+	log.Info("start get exports")
+
+	exports, err := es.e.UserDataExports(ctx, userID)
+	if err != nil {
+		log.Error("error get exports: ", err)
+		return nil, ErrDatabase
+	}
+
+	for i := range exports {
+		exports[i] = es.withLink(exports[i])
+	}
+
+	log.Info("success get exports")
+
+	return exports, nil
+}
+
+func (es *ExportServer) Export(ctx context.Context, userID uint, id uint) (domain.DataExport, error) {
+	const op = "export.Export"
+
+	log := es.log.WithFields(logrus.Fields{
+		"op":        op,
+		"user_id":   userID,
+		"export_id": id,
+	})
+
+	log.Info("start get export")
+
+	export, err := es.e.DataExport(ctx, userID, id)
+	if err != nil {
+		log.Error("error get export: ", err)
+		return domain.DataExport{}, RegisterErrDatabase(err)
+	}
+
+	log.Info("success get export")
+
+	return es.withLink(export), nil
+}
+
+// Download opens the archive, the caller closes it.
+func (es *ExportServer) Download(ctx context.Context, id uint, expires int64, signature string) (domain.DataExport, io.ReadCloser, error) {
+	const op = "export.Download"
+
+	log := es.log.WithFields(logrus.Fields{
+		"op":        op,
+		"export_id": id,
+	})
+
+	log.Info("start download export")
+
+	if err := signedLink.Verify(es.key, payload(id), expires, signature, es.now()); err != nil {
+		log.WithField("err", err).Warn("invalid download link")
+		return domain.DataExport{}, nil, ErrLink
+	}
+
+	export, err := es.e.DataExport(ctx, 0, id)
+	if err != nil {
+		log.Error("error get export: ", err)
+		return domain.DataExport{}, nil, RegisterErrDatabase(err)
+	}
+
+	if export.Status != domain.ExportReady || export.ExpiresAt == nil || !es.now().Before(*export.ExpiresAt) {
+		log.WithField("status", export.Status).Error("export is not available")
+		return domain.DataExport{}, nil, ErrLink
+	}
+
+	file, err := es.st.Open(export.FileName)
+	if err != nil {
+		log.WithField("err", err).Error("error open archive")
+		return domain.DataExport{}, nil, ErrServic
+	}
+
+	log.WithField("user_id", export.UserID).Info("success download export")
+
+	return export, file, nil
+}
+
+func (es *ExportServer) build(ctx context.Context, export domain.DataExport) {
+	const op = "export.build"
+
+	log := es.log.WithFields(logrus.Fields{
+		"op":        op,
+		"user_id":   export.UserID,
+		"export_id": export.ID,
+	})
+
+	log.Info("start build export")
+
+	name := fmt.Sprintf("export-%d-%d.zip", export.UserID, export.ID)
+	size, err := es.write(ctx, export.UserID, name)
+	if err != nil {
+		log.WithField("err", err).Error("error build export")
+		if err := es.e.FailDataExport(ctx, export.ID, "error build archive"); err != nil {
+			log.Error("error fail export: ", err)
+		}
+		return
+	}
+
+	if err := es.e.CompleteDataExport(ctx, export.ID, name, size, es.now().Add(es.opt.TTL)); err != nil {
+		log.Error("error complete export: ", err)
+		if err := es.st.Remove(name); err != nil {
+			log.WithField("err", err).Error("error remove archive")
+		}
+		return
+	}
+
+	log.WithField("size", size).Info("success build export")
+}
+
+func (es *ExportServer) write(ctx context.Context, userID uint, name string) (int64, error) {
+	data, err := es.a.UserArchive(ctx, userID)
+	if err != nil {
+		return 0, fmt.Errorf("get archive: %w", err)
+	}
+
+	journal, err := es.a.UserJournal(ctx, userID)
+	if err != nil {
+		return 0, fmt.Errorf("get journal: %w", err)
+	}
+
+	file, err := es.st.Create(name)
+	if err != nil {
+		return 0, fmt.Errorf("create file: %w", err)
+	}
+
+	w := &countWriter{w: file}
+	if err := archive.Write(w, data, journal, es.now()); err != nil {
+		file.Close()
+		es.st.Remove(name)
+		return 0, fmt.Errorf("write archive: %w", err)
+	}
+
+	if err := file.Close(); err != nil {
+		return 0, fmt.Errorf("close file: %w", err)
+	}
+
+	return w.n, nil
+}
+
+func (es *ExportServer) cleanup(ctx context.Context) {
+	const op = "export.cleanup"
+
+	log := es.log.WithField("op", op)
+
+	names, err := es.e.DeleteExpiredDataExports(ctx, es.now())
+	if err != nil {
+		log.Error("error delete expired exports: ", err)
+		return
+	}
+
+	for _, name := range names {
+		if err := es.st.Remove(name); err != nil {
+			log.WithFields(logrus.Fields{"file": name, "err": err}).Error("error remove archive")
+		}
+	}
+}
+
+func (es *ExportServer) withLink(export domain.DataExport) domain.DataExport {
+	if export.Status != domain.ExportReady || export.ExpiresAt == nil || !es.now().Before(*export.ExpiresAt) {
+		return export
+	}
+
+	signature := signedLink.Sign(es.key, payload(export.ID), *export.ExpiresAt)
+	export.DownloadURL = signedLink.Link(es.opt.URL, url.Values{"id": {strconv.FormatUint(uint64(export.ID), 10)}}, *export.ExpiresAt, signature)
+
+	return export
+}
+
+// background runs f in a goroutine, at most Workers of them at once.
+func (es *ExportServer) background(f func()) {
+	go func() {
+		es.sem <- struct{}{}
+		defer func() { <-es.sem }()
+		f()
+	}()
+}
+
+func payload(id uint) string {
+	return "export:" + strconv.FormatUint(uint64(id), 10)
+}
+
+type countWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,93 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type DbMock struct {
+	mock.Mock
+}
+
+func (d *DbMock) CreateDataExport(ctx context.Context, userID uint, staleBefore time.Time) (domain.DataExport, bool, error) {
+	args := d.Called(ctx, userID, staleBefore)
+	return args.Get(0).(domain.DataExport), args.Bool(1), args.Error(2)
+}
+
+func (d *DbMock) CompleteDataExport(ctx context.Context, id uint, fileName string, size int64, expiresAt time.Time) error {
+	args := d.Called(ctx, id, fileName, size, expiresAt)
+	return args.Error(0)
+}
+
+func (d *DbMock) FailDataExport(ctx context.Context, id uint, reason string) error {
+	args := d.Called(ctx, id, reason)
+	return args.Error(0)
+}
+
+func (d *DbMock) UserDataExports(ctx context.Context, userID uint) ([]domain.DataExport, error) {
+	args := d.Called(ctx, userID)
+	return args.Get(0).([]domain.DataExport), args.Error(1)
+}
+
+func (d *DbMock) DataExport(ctx context.Context, userID uint, id uint) (domain.DataExport, error) {
+	args := d.Called(ctx, userID, id)
+	return args.Get(0).(domain.DataExport), args.Error(1)
+}
+
+func (d *DbMock) DeleteExpiredDataExports(ctx context.Context, now time.Time) ([]string, error) {
+	args := d.Called(ctx, now)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (d *DbMock) UserArchive(ctx context.Context, userID uint) (domain.Archive, error) {
+	args := d.Called(ctx, userID)
+	return args.Get(0).(domain.Archive), args.Error(1)
+}
+
+func (d *DbMock) UserJournal(ctx context.Context, userID uint) (domain.Journal, error) {
+	args := d.Called(ctx, userID)
+	return args.Get(0).(domain.Journal), args.Error(1)
+}
+
+// StorageMock keeps the files in memory.
+type StorageMock struct {
+	files map[string][]byte
+}
+
+func newStorageMock() *StorageMock {
+	return &StorageMock{files: map[string][]byte{}}
+}
+
+func (s *StorageMock) Create(name string) (io.WriteCloser, error) {
+	return &memoryFile{name: name, s: s}, nil
+}
+
+func (s *StorageMock) Open(name string) (io.ReadCloser, error) {
+	data, ok := s.files[name]
+	if !ok {
+		return nil, errors.New("file not found")
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (s *StorageMock) Remove(name string) error {
+	delete(s.files, name)
+	return nil
+}
+
+type memoryFile struct {
+	bytes.Buffer
+	name string
+	s    *StorageMock
+}
+
+func (f *memoryFile) Close() error {
+	f.s.files[f.name] = f.Bytes()
+	return nil
+}
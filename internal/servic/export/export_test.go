@@ -0,0 +1,207 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/financial_tracer/internal/infastructure/db/postgresql"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var now = time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+func server(t *testing.T, repo *DbMock, st *StorageMock) *ExportServer {
+	t.Helper()
+
+	es, err := CreateExportServer(repo, repo, st, Options{Secret: "secret"}, logrus.New(), func() time.Time { return now })
+	require.NoError(t, err)
+	es.run = func(f func()) { f() }
+
+	return es
+}
+
+func TestRequestExport(t *testing.T) {
+	pending := domain.DataExport{ID: 5, UserID: 3, Status: domain.ExportPending, CreatedAt: now}
+	name := "export-3-5.zip"
+
+	tests := []struct {
+		name       string
+		created    bool
+		createErr  error
+		archiveErr error
+		wantErr    error
+		complete   bool
+		fail       bool
+	}{
+		{
+			name:     "success",
+			created:  true,
+			complete: true,
+		},
+		{
+			name: "pending export is reused",
+		},
+		{
+			name:       "error build",
+			created:    true,
+			archiveErr: errors.New("database down"),
+			fail:       true,
+		},
+		{
+			name:      "error database",
+			createErr: errors.New("database down"),
+			wantErr:   ErrDatabase,
+		},
+	}
+
+	for _, ts := range tests {
+		t.Run(ts.name, func(t *testing.T) {
+			repo := new(DbMock)
+			st := newStorageMock()
+			st.files["export-3-1.zip"] = []byte("old")
+
+			repo.On("DeleteExpiredDataExports", mock.Anything, now).Return([]string{"export-3-1.zip"}, nil)
+			repo.On("CreateDataExport", mock.Anything, uint(3), now.Add(-PendingTimeout)).Return(pending, ts.created, ts.createErr)
+			repo.On("UserArchive", mock.Anything, uint(3)).Return(domain.Archive{Profile: domain.Profile{ID: 3}}, ts.archiveErr)
+			repo.On("UserJournal", mock.Anything, uint(3)).Return(domain.Journal{}, nil)
+			repo.On("CompleteDataExport", mock.Anything, uint(5), name, mock.Anything, now.Add(DefaultTTL)).Return(nil)
+			repo.On("FailDataExport", mock.Anything, uint(5), mock.Anything).Return(nil)
+
+			export, err := server(t, repo, st).RequestExport(context.Background(), 3)
+
+			assert.ErrorIs(t, err, ts.wantErr)
+			assert.NotContains(t, st.files, "export-3-1.zip")
+			if ts.wantErr == nil {
+				assert.Equal(t, pending, export)
+			}
+
+			if ts.complete {
+				repo.AssertCalled(t, "CompleteDataExport", mock.Anything, uint(5), name, int64(len(st.files[name])), now.Add(DefaultTTL))
+				reader, err := zip.NewReader(bytes.NewReader(st.files[name]), int64(len(st.files[name])))
+				require.NoError(t, err)
+				assert.NotEmpty(t, reader.File)
+			} else {
+				repo.AssertNotCalled(t, "CompleteDataExport", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+
+			if ts.fail {
+				repo.AssertCalled(t, "FailDataExport", mock.Anything, uint(5), mock.Anything)
+				assert.NotContains(t, st.files, name)
+			} else {
+				repo.AssertNotCalled(t, "FailDataExport", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestExportsLink(t *testing.T) {
+	expires := now.Add(time.Hour)
+	expired := now.Add(-time.Hour)
+
+	repo := new(DbMock)
+	repo.On("UserDataExports", mock.Anything, uint(3)).Return([]domain.DataExport{
+		{ID: 7, Status: domain.ExportReady, ExpiresAt: &expires},
+		{ID: 6, Status: domain.ExportReady, ExpiresAt: &expired},
+		{ID: 5, Status: domain.ExportPending},
+	}, nil)
+
+	exports, err := server(t, repo, newStorageMock()).Exports(context.Background(), 3)
+	require.NoError(t, err)
+
+	link, err := url.Parse(exports[0].DownloadURL)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultURL, link.Path)
+	assert.Equal(t, "7", link.Query().Get("id"))
+	assert.Equal(t, strconv.FormatInt(expires.Unix(), 10), link.Query().Get("expires"))
+	assert.Empty(t, exports[1].DownloadURL)
+	assert.Empty(t, exports[2].DownloadURL)
+}
+
+func TestExportNoFound(t *testing.T) {
+	repo := new(DbMock)
+	repo.On("DataExport", mock.Anything, uint(3), uint(9)).Return(domain.DataExport{}, postgresql.ErrorNotFound)
+
+	_, err := server(t, repo, newStorageMock()).Export(context.Background(), 3, 9)
+
+	assert.ErrorIs(t, err, ErrNoFound)
+}
+
+func TestDownload(t *testing.T) {
+	expires := now.Add(time.Hour)
+	ready := domain.DataExport{ID: 7, UserID: 3, Status: domain.ExportReady, FileName: "export-3-7.zip", ExpiresAt: &expires}
+	failed := domain.DataExport{ID: 7, UserID: 3, Status: domain.ExportFailed}
+
+	tests := []struct {
+		name      string
+		export    domain.DataExport
+		tamper    func(url.Values)
+		wantErr   error
+		wantBytes string
+	}{
+		{
+			name:      "success",
+			export:    ready,
+			wantBytes: "archive",
+		},
+		{
+			name:    "error other export",
+			export:  ready,
+			tamper:  func(v url.Values) { v.Set("id", "8") },
+			wantErr: ErrLink,
+		},
+		{
+			name:    "error extended link",
+			export:  ready,
+			tamper:  func(v url.Values) { v.Set("expires", strconv.FormatInt(expires.Add(time.Hour).Unix(), 10)) },
+			wantErr: ErrLink,
+		},
+		{
+			name:    "error export not ready",
+			export:  failed,
+			wantErr: ErrLink,
+		},
+	}
+
+	for _, ts := range tests {
+		t.Run(ts.name, func(t *testing.T) {
+			repo := new(DbMock)
+			st := newStorageMock()
+			st.files["export-3-7.zip"] = []byte("archive")
+			es := server(t, repo, st)
+
+			repo.On("UserDataExports", mock.Anything, uint(3)).Return([]domain.DataExport{ready}, nil)
+			repo.On("DataExport", mock.Anything, uint(0), mock.Anything).Return(ts.export, nil)
+
+			exports, err := es.Exports(context.Background(), 3)
+			require.NoError(t, err)
+			link, err := url.Parse(exports[0].DownloadURL)
+			require.NoError(t, err)
+			values := link.Query()
+			if ts.tamper != nil {
+				ts.tamper(values)
+			}
+
+			id, _ := strconv.ParseUint(values.Get("id"), 10, 64)
+			exp, _ := strconv.ParseInt(values.Get("expires"), 10, 64)
+			_, file, err := es.Download(context.Background(), uint(id), exp, values.Get("signature"))
+
+			assert.ErrorIs(t, err, ts.wantErr)
+			if ts.wantErr == nil {
+				data, err := io.ReadAll(file)
+				require.NoError(t, err)
+				assert.Equal(t, ts.wantBytes, string(data))
+			}
+		})
+	}
+}
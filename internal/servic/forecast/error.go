@@ -0,0 +1,25 @@
+package forecast
+
+import (
+	"errors"
+
+	"github.com/financial_tracer/internal/infastructure/db/postgresql"
+)
+
+var (
+	ErrDatabase  = errors.New("error database")
+	ErrForbidden = errors.New("no access to the household")
+)
+
+func RegisterErrDatabase(err error) error {
+	arr := map[error]error{
+		postgresql.ErrorForbidden: ErrForbidden,
+	}
+
+	value, ok := arr[err]
+	if !ok {
+		return ErrDatabase
+	}
+
+	return value
+}
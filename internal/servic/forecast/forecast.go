@@ -0,0 +1,179 @@
+package forecast
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/financial_tracer/internal/lib/period"
+	"github.com/sirupsen/logrus"
+)
+
+// TrailingMonths is how many full months before the current one the averages look at.
+const TrailingMonths = 3
+
+type CategoriesRepository interface {
+	HouseholdCategories(ctx context.Context, m domain.Member) ([]domain.CategoryRecord, error)
+}
+
+type TransactionsRepository interface {
+	HouseholdTransactions(ctx context.Context, m domain.Member, from time.Time, to time.Time) ([]domain.TransactionRecord, error)
+}
+
+type ForecastServer struct {
+	c   CategoriesRepository
+	t   TransactionsRepository
+	log *logrus.Logger
+	now func() time.Time
+}
+
+func CreateForecastServer(c CategoriesRepository, t TransactionsRepository, log *logrus.Logger, now func() time.Time) *ForecastServer {
+	return &ForecastServer{
+		c:   c,
+		t:   t,
+		log: log,
+		now: now,
+	}
+}
+
+type recurringKey struct {
+	categoryID uint
+	name       string
+}
+
+// charge groups the transactions with one name in a category, months[i] counts them i months back.
+type charge struct {
+	months  [TrailingMonths + 1]int
+	last    int
+	current int
+	history int
+}
+
+// Forecast blends the run-rate with the trailing average and adds the recurring charges still due.
+func (fs *ForecastServer) Forecast(ctx context.Context, m domain.Member, pref domain.Preferences) (domain.Forecast, error) {
+	const op = "forecast.Forecast"
+
+	log := fs.log.WithFields(logrus.Fields{
+		"op":           op,
+		"user_id":      m.UserID,
+		"household_id": m.HouseholdID,
+	})
+
+	log.Info("start forecast")
+
+	now := fs.now().In(period.Location(pref))
+	month := period.Month(now, pref.MonthStart)
+	from, to := month.From, month.To
+	historyFrom := from.AddDate(0, -TrailingMonths, 0)
+
+	categories, err := fs.c.HouseholdCategories(ctx, m)
+	if err != nil {
+		log.Error("error get categories: ", err)
+		return domain.Forecast{}, RegisterErrDatabase(err)
+	}
+
+	transactions, err := fs.t.HouseholdTransactions(ctx, m, historyFrom, now)
+	if err != nil {
+		log.Error("error get transactions: ", err)
+		return domain.Forecast{}, RegisterErrDatabase(err)
+	}
+
+	days := period.Days(month)
+	elapsed := period.Days(domain.Period{From: from, To: period.Date(now, now.Location()).AddDate(0, 0, 1)})
+
+	charges := map[recurringKey]*charge{}
+	spent := map[uint]int{}
+	history := map[uint]int{}
+	historyMonths := map[int]bool{}
+
+	for _, tr := range transactions {
+		month := period.MonthsBetween(tr.Date.In(now.Location()), now, pref.MonthStart)
+		if month < 0 || month > TrailingMonths {
+			continue
+		}
+
+		key := recurringKey{categoryID: tr.CategoryID, name: strings.ToLower(strings.TrimSpace(tr.Name))}
+		ch, ok := charges[key]
+		if !ok {
+			ch = &charge{}
+			charges[key] = ch
+		}
+		ch.months[month]++
+
+		if month == 0 {
+			ch.current += tr.Count
+			spent[tr.CategoryID] += tr.Count
+		} else {
+			ch.last = tr.Count
+			ch.history += tr.Count
+			history[tr.CategoryID] += tr.Count
+			historyMonths[month] = true
+		}
+	}
+
+	recurringSpent := map[uint]int{}
+	recurringPending := map[uint]int{}
+	recurringHistory := map[uint]int{}
+	for key, ch := range charges {
+		if !ch.recurring() {
+			continue
+		}
+		recurringSpent[key.categoryID] += ch.current
+		recurringHistory[key.categoryID] += ch.history
+		if ch.months[0] == 0 {
+			recurringPending[key.categoryID] += ch.last
+		}
+	}
+
+	res := domain.Forecast{
+		From:         from,
+		To:           to,
+		Currency:     pref.Currency,
+		DaysElapsed:  elapsed,
+		DaysInPeriod: days,
+		Categories:   make([]domain.CategoryForecast, 0, len(categories)),
+	}
+
+	for _, category := range categories {
+		id := category.ID
+		variable := spent[id] - recurringSpent[id]
+		runRate := variable * days / elapsed
+
+		trailing := 0
+		projected := runRate
+		if len(historyMonths) != 0 {
+			trailing = (history[id] - recurringHistory[id]) / len(historyMonths)
+			projected = (runRate*elapsed + trailing*(days-elapsed)) / days
+		}
+		if projected < variable {
+			projected = variable
+		}
+		projected += recurringSpent[id] + recurringPending[id]
+
+		res.Categories = append(res.Categories, domain.CategoryForecast{
+			CategoryID:       id,
+			Name:             category.Name,
+			Limit:            category.Limit,
+			Spent:            spent[id],
+			RunRate:          runRate,
+			TrailingAverage:  trailing,
+			RecurringPending: recurringPending[id],
+			Projected:        projected,
+			OverLimit:        projected > category.Limit,
+		})
+	}
+
+	log.Info("success forecast")
+
+	return res, nil
+}
+
+func (ch *charge) recurring() bool {
+	for _, n := range ch.months[1:] {
+		if n != 1 {
+			return false
+		}
+	}
+	return true
+}
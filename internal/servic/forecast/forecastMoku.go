@@ -0,0 +1,23 @@
+package forecast
+
+import (
+	"context"
+	"time"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type DbMock struct {
+	mock.Mock
+}
+
+func (d *DbMock) HouseholdCategories(ctx context.Context, m domain.Member) ([]domain.CategoryRecord, error) {
+	args := d.Called(ctx, m)
+	return args.Get(0).([]domain.CategoryRecord), args.Error(1)
+}
+
+func (d *DbMock) HouseholdTransactions(ctx context.Context, m domain.Member, from time.Time, to time.Time) ([]domain.TransactionRecord, error) {
+	args := d.Called(ctx, m, from, to)
+	return args.Get(0).([]domain.TransactionRecord), args.Error(1)
+}
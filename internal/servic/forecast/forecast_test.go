@@ -0,0 +1,165 @@
+package forecast
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func category(id uint, name string, limit int) domain.CategoryRecord {
+	return domain.CategoryRecord{ID: id, CategoryOutput: domain.CategoryOutput{Name: name, Limit: limit}}
+}
+
+func tran(categoryID uint, name string, count int, month time.Month, day int) domain.TransactionRecord {
+	return domain.TransactionRecord{
+		Date:              time.Date(2025, month, day, 12, 0, 0, 0, time.UTC),
+		TransactionOutput: domain.TransactionOutput{CategoryID: categoryID, Name: name, Count: count},
+	}
+}
+
+var member = domain.Member{UserID: 1, HouseholdID: 3, Role: domain.HouseholdViewer}
+
+func TestForecast(t *testing.T) {
+	now := time.Date(2025, time.June, 10, 18, 0, 0, 0, time.UTC)
+	from := time.Date(2025, time.June, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, time.July, 1, 0, 0, 0, 0, time.UTC)
+	historyFrom := time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)
+
+	categories := []domain.CategoryRecord{
+		category(1, "food", 10000),
+		category(2, "subscriptions", 600),
+		category(3, "car", 1500),
+	}
+
+	type test struct {
+		name         string
+		transactions []domain.TransactionRecord
+		want         []domain.CategoryForecast
+	}
+
+	tests := []test{
+		{
+			name: "run-rate with trailing average and recurring charges",
+			transactions: []domain.TransactionRecord{
+				tran(1, "market", 3000, time.March, 5), tran(1, "market", 3000, time.March, 20),
+				tran(2, "Netflix", 500, time.March, 3), tran(2, "Music", 200, time.March, 7),
+				tran(1, "market", 2000, time.April, 5), tran(1, "market", 4000, time.April, 20),
+				tran(2, "Netflix", 500, time.April, 3), tran(2, "Music", 200, time.April, 7),
+				tran(1, "market", 3500, time.May, 5), tran(1, "market", 2500, time.May, 20),
+				tran(2, "netflix", 500, time.May, 3), tran(2, "Music", 200, time.May, 7),
+				tran(1, "market", 1500, time.June, 2), tran(1, "market", 1500, time.June, 8),
+				tran(2, "Music", 200, time.June, 7),
+				tran(3, "repair", 2000, time.June, 9),
+			},
+			want: []domain.CategoryForecast{
+				{CategoryID: 1, Name: "food", Limit: 10000, Spent: 3000, RunRate: 9000, TrailingAverage: 6000, Projected: 7000},
+				{CategoryID: 2, Name: "subscriptions", Limit: 600, Spent: 200, RecurringPending: 500, Projected: 700, OverLimit: true},
+				{CategoryID: 3, Name: "car", Limit: 1500, Spent: 2000, RunRate: 6000, Projected: 2000, OverLimit: true},
+			},
+		},
+		{
+			name: "run-rate without history",
+			transactions: []domain.TransactionRecord{
+				tran(1, "market", 4000, time.June, 2),
+			},
+			want: []domain.CategoryForecast{
+				{CategoryID: 1, Name: "food", Limit: 10000, Spent: 4000, RunRate: 12000, Projected: 12000, OverLimit: true},
+				{CategoryID: 2, Name: "subscriptions", Limit: 600},
+				{CategoryID: 3, Name: "car", Limit: 1500},
+			},
+		},
+	}
+
+	for _, ts := range tests {
+		t.Run(ts.name, func(t *testing.T) {
+			repoMock := new(DbMock)
+			repoMock.On("HouseholdCategories", mock.Anything, member).Return(categories, nil)
+			repoMock.On("HouseholdTransactions", mock.Anything, member, historyFrom, now).Return(ts.transactions, nil)
+
+			server := CreateForecastServer(repoMock, repoMock, logrus.New(), func() time.Time { return now })
+			res, err := server.Forecast(context.Background(), member, preferences())
+
+			assert.NoError(t, err)
+			assert.Equal(t, from, res.From)
+			assert.Equal(t, to, res.To)
+			assert.Equal(t, "RUB", res.Currency)
+			assert.Equal(t, 10, res.DaysElapsed)
+			assert.Equal(t, 30, res.DaysInPeriod)
+			assert.Equal(t, ts.want, res.Categories)
+		})
+	}
+}
+
+func TestForecastPreferences(t *testing.T) {
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	assert.NoError(t, err)
+
+	// 22:00 UTC on May 24 is already May 25 in Moscow, the first day of the reporting month
+	now := time.Date(2025, time.May, 24, 22, 0, 0, 0, time.UTC)
+	from := time.Date(2025, time.May, 25, 0, 0, 0, 0, moscow)
+	to := time.Date(2025, time.June, 25, 0, 0, 0, 0, moscow)
+
+	pref := domain.Preferences{Currency: "EUR", Timezone: "Europe/Moscow", Locale: "en", MonthStart: 25}
+
+	repoMock := new(DbMock)
+	repoMock.On("HouseholdCategories", mock.Anything, member).Return([]domain.CategoryRecord{category(1, "rent", 900)}, nil)
+	repoMock.On("HouseholdTransactions", mock.Anything, member, from.AddDate(0, -TrailingMonths, 0), now.In(moscow)).Return([]domain.TransactionRecord{
+		// one charge in each reporting month, though two of them are in March
+		tran(1, "rent", 800, time.March, 1), tran(1, "rent", 800, time.March, 26), tran(1, "rent", 800, time.April, 26),
+	}, nil)
+
+	server := CreateForecastServer(repoMock, repoMock, logrus.New(), func() time.Time { return now })
+	res, err := server.Forecast(context.Background(), member, pref)
+
+	assert.NoError(t, err)
+	assert.True(t, from.Equal(res.From))
+	assert.True(t, to.Equal(res.To))
+	assert.Equal(t, "EUR", res.Currency)
+	assert.Equal(t, 1, res.DaysElapsed)
+	assert.Equal(t, 31, res.DaysInPeriod)
+	assert.Equal(t, []domain.CategoryForecast{
+		{CategoryID: 1, Name: "rent", Limit: 900, RecurringPending: 800, Projected: 800},
+	}, res.Categories)
+}
+
+func preferences() domain.Preferences {
+	return domain.Preferences{
+		Currency:   domain.DefaultCurrency,
+		Timezone:   domain.DefaultTimezone,
+		Locale:     domain.DefaultLocale,
+		WeekStart:  domain.DefaultWeekStart,
+		MonthStart: domain.DefaultMonthStart,
+	}
+}
+
+func TestForecastDatabase(t *testing.T) {
+	now := time.Date(2025, time.February, 28, 9, 0, 0, 0, time.UTC)
+
+	t.Run("error categories", func(t *testing.T) {
+		repoMock := new(DbMock)
+		repoMock.On("HouseholdCategories", mock.Anything, member).Return([]domain.CategoryRecord{}, errors.New("some db error"))
+
+		server := CreateForecastServer(repoMock, repoMock, logrus.New(), func() time.Time { return now })
+		_, err := server.Forecast(context.Background(), member, preferences())
+
+		assert.ErrorIs(t, err, ErrDatabase)
+		repoMock.AssertNotCalled(t, "HouseholdTransactions", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("error transactions", func(t *testing.T) {
+		repoMock := new(DbMock)
+		repoMock.On("HouseholdCategories", mock.Anything, member).Return([]domain.CategoryRecord{}, nil)
+		repoMock.On("HouseholdTransactions", mock.Anything, member, mock.Anything, now).Return([]domain.TransactionRecord{}, errors.New("some db error"))
+
+		server := CreateForecastServer(repoMock, repoMock, logrus.New(), func() time.Time { return now })
+		_, err := server.Forecast(context.Background(), member, preferences())
+
+		assert.ErrorIs(t, err, ErrDatabase)
+	})
+}
@@ -0,0 +1,34 @@
+package household
+
+import (
+	"errors"
+
+	"github.com/financial_tracer/internal/infastructure/db/postgresql"
+)
+
+var (
+	ErrDatabase   = errors.New("error database")
+	ErrServic     = errors.New("servic error")
+	ErrNoFound    = errors.New("household is not found")
+	ErrForbidden  = errors.New("no access to the household")
+	ErrPersonal   = errors.New("personal household can't be shared")
+	ErrDuplicated = errors.New("user is already a member of the household")
+	ErrInvitation = errors.New("invalid or expired invitation")
+	ErrMail       = errors.New("error send mail")
+)
+
+func RegisterErrDatabase(err error) error {
+	arr := map[error]error{
+		postgresql.ErrorNotFound:   ErrNoFound,
+		postgresql.ErrorForbidden:  ErrForbidden,
+		postgresql.ErrorPersonal:   ErrPersonal,
+		postgresql.ErrorDuplicated: ErrDuplicated,
+	}
+
+	value, ok := arr[err]
+	if !ok {
+		return ErrDatabase
+	}
+
+	return value
+}
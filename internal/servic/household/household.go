@@ -0,0 +1,287 @@
+package household
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/financial_tracer/internal/infastructure/db/postgresql"
+	"github.com/financial_tracer/internal/lib/mailToken"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+)
+
+const DefaultInviteTTL = 7 * 24 * time.Hour
+
+type HouseholdRepository interface {
+	PersonalHousehold(ctx context.Context, userID uint) (domain.Member, error)
+	HouseholdMember(ctx context.Context, userID uint, householdID uint) (domain.Member, error)
+	Households(ctx context.Context, userID uint) ([]domain.Household, error)
+	Household(ctx context.Context, m domain.Member) (domain.Household, error)
+	CreateHousehold(ctx context.Context, userID uint, name string) (domain.Household, error)
+	UpdateHouseholdMember(ctx context.Context, m domain.Member, userID uint, role string) error
+	RemoveHouseholdMember(ctx context.Context, m domain.Member, userID uint) error
+}
+
+type InvitationRepository interface {
+	CreateHouseholdInvitation(ctx context.Context, m domain.Member, invite domain.InviteMember, tokenHash string, expiresAt time.Time) (domain.HouseholdInvitation, error)
+	AcceptHouseholdInvitation(ctx context.Context, userID uint, tokenHash string, now time.Time) (domain.Household, error)
+}
+
+type Mailer interface {
+	Send(ctx context.Context, msg domain.Mail) error
+}
+
+type Options struct {
+	TTL time.Duration
+	URL string
+}
+
+type HouseholdServer struct {
+	r        HouseholdRepository
+	i        InvitationRepository
+	m        Mailer
+	opt      Options
+	log      *logrus.Logger
+	now      func() time.Time
+	validate validator.Validate
+}
+
+func CreateHouseholdServer(r HouseholdRepository, i InvitationRepository, m Mailer, opt Options, log *logrus.Logger, now func() time.Time) *HouseholdServer {
+	if opt.TTL == 0 {
+		opt.TTL = DefaultInviteTTL
+	}
+
+	return &HouseholdServer{
+		r:        r,
+		i:        i,
+		m:        m,
+		opt:      opt,
+		log:      log,
+		now:      now,
+		validate: *validator.New(),
+	}
+}
+
+// Member falls back to the personal household when householdID is 0.
+func (hs *HouseholdServer) Member(ctx context.Context, userID uint, householdID uint) (domain.Member, error) {
+	if householdID == 0 {
+		m, err := hs.r.PersonalHousehold(ctx, userID)
+		if err != nil {
+			return domain.Member{}, RegisterErrDatabase(err)
+		}
+		return m, nil
+	}
+
+	m, err := hs.r.HouseholdMember(ctx, userID, householdID)
+	if err != nil {
+		if errors.Is(err, postgresql.ErrorNotFound) {
+			return domain.Member{}, ErrForbidden
+		}
+		return domain.Member{}, ErrDatabase
+	}
+
+	return m, nil
+}
+
+func (hs *HouseholdServer) Households(ctx context.Context, userID uint) ([]domain.Household, error) {
+	const op = "household.Households"
+
+	log := hs.log.WithFields(logrus.Fields{
+		"op":      op,
+		"user_id": userID,
+	})
+
+	log.Info("start get households")
+
+	households, err := hs.r.Households(ctx, userID)
+	if err != nil {
+		log.Error("error get households: ", err)
+		return nil, ErrDatabase
+	}
+
+	log.Info("success get households")
+
+	return households, nil
+}
+
+func (hs *HouseholdServer) Household(ctx context.Context, m domain.Member) (domain.Household, error) {
+	const op = "household.Household"
+
+	log := hs.log.WithFields(logrus.Fields{
+		"op":           op,
+		"user_id":      m.UserID,
+		"household_id": m.HouseholdID,
+	})
+
+	log.Info("start get household")
+
+	household, err := hs.r.Household(ctx, m)
+	if err != nil {
+		log.Error("error get household: ", err)
+		return domain.Household{}, RegisterErrDatabase(err)
+	}
+
+	log.Info("success get household")
+
+	return household, nil
+}
+
+func (hs *HouseholdServer) CreateHousehold(ctx context.Context, userID uint, req domain.CreateHousehold) (domain.Household, error) {
+	const op = "household.CreateHousehold"
+
+	log := hs.log.WithFields(logrus.Fields{
+		"op":      op,
+		"user_id": userID,
+	})
+
+	log.Info("start create household")
+
+	if err := hs.validate.Struct(req); err != nil {
+		log.WithField("err", err).Error("invalid validate")
+		return domain.Household{}, err
+	}
+
+	household, err := hs.r.CreateHousehold(ctx, userID, req.Name)
+	if err != nil {
+		log.Error("error create household: ", err)
+		return domain.Household{}, ErrDatabase
+	}
+
+	log.WithField("household_id", household.ID).Info("success create household")
+
+	return household, nil
+}
+
+func (hs *HouseholdServer) Invite(ctx context.Context, m domain.Member, req domain.InviteMember) (domain.HouseholdInvitation, error) {
+	const op = "household.Invite"
+
+	log := hs.log.WithFields(logrus.Fields{
+		"op":           op,
+		"user_id":      m.UserID,
+		"household_id": m.HouseholdID,
+		"role":         req.Role,
+	})
+
+	log.Info("start invite member")
+
+	if err := hs.validate.Struct(req); err != nil {
+		log.WithField("err", err).Error("invalid validate")
+		return domain.HouseholdInvitation{}, err
+	}
+
+	token, hash, err := mailToken.New()
+	if err != nil {
+		log.WithField("err", err).Error("field create invitation token")
+		return domain.HouseholdInvitation{}, ErrServic
+	}
+
+	invitation, err := hs.i.CreateHouseholdInvitation(ctx, m, req, hash, hs.now().Add(hs.opt.TTL))
+	if err != nil {
+		log.Error("error create invitation: ", err)
+		return domain.HouseholdInvitation{}, RegisterErrDatabase(err)
+	}
+
+	if err := hs.m.Send(ctx, hs.invitationMail(invitation, token)); err != nil {
+		log.WithField("err", err).Error("error send invitation mail")
+		return domain.HouseholdInvitation{}, ErrMail
+	}
+
+	log.WithField("invitation_id", invitation.ID).Info("success invite member")
+
+	return invitation, nil
+}
+
+func (hs *HouseholdServer) Accept(ctx context.Context, userID uint, req domain.AcceptInvitation) (domain.Household, error) {
+	const op = "household.Accept"
+
+	log := hs.log.WithFields(logrus.Fields{
+		"op":      op,
+		"user_id": userID,
+	})
+
+	log.Info("start accept invitation")
+
+	if err := hs.validate.Struct(req); err != nil {
+		log.WithField("err", err).Error("invalid validate")
+		return domain.Household{}, err
+	}
+
+	household, err := hs.i.AcceptHouseholdInvitation(ctx, userID, mailToken.Hash(req.Token), hs.now())
+	if err != nil {
+		log.Error("error accept invitation: ", err)
+		if errors.Is(err, postgresql.ErrorNotFound) {
+			return domain.Household{}, ErrInvitation
+		}
+		return domain.Household{}, RegisterErrDatabase(err)
+	}
+
+	log.WithField("household_id", household.ID).Info("success accept invitation")
+
+	return household, nil
+}
+
+func (hs *HouseholdServer) UpdateMember(ctx context.Context, m domain.Member, userID uint, req domain.UpdateMember) error {
+	const op = "household.UpdateMember"
+
+	log := hs.log.WithFields(logrus.Fields{
+		"op":           op,
+		"user_id":      m.UserID,
+		"household_id": m.HouseholdID,
+		"member_id":    userID,
+		"role":         req.Role,
+	})
+
+	log.Info("start update member")
+
+	if err := hs.validate.Struct(req); err != nil {
+		log.WithField("err", err).Error("invalid validate")
+		return err
+	}
+
+	if err := hs.r.UpdateHouseholdMember(ctx, m, userID, req.Role); err != nil {
+		log.Error("error update member: ", err)
+		return RegisterErrDatabase(err)
+	}
+
+	log.Info("success update member")
+
+	return nil
+}
+
+func (hs *HouseholdServer) RemoveMember(ctx context.Context, m domain.Member, userID uint) error {
+	const op = "household.RemoveMember"
+
+	log := hs.log.WithFields(logrus.Fields{
+		"op":           op,
+		"user_id":      m.UserID,
+		"household_id": m.HouseholdID,
+		"member_id":    userID,
+	})
+
+	log.Info("start remove member")
+
+	if err := hs.r.RemoveHouseholdMember(ctx, m, userID); err != nil {
+		log.Error("error remove member: ", err)
+		return RegisterErrDatabase(err)
+	}
+
+	log.Info("success remove member")
+
+	return nil
+}
+
+func (hs *HouseholdServer) invitationMail(invitation domain.HouseholdInvitation, token string) domain.Mail {
+	return domain.Mail{
+		To:      invitation.Email,
+		Subject: "Приглашение в бюджет financial_tracer",
+		Body: fmt.Sprintf("Здравствуйте!\n\n"+
+			"%s приглашает вас в общий бюджет «%s» с ролью %s.\n"+
+			"Чтобы принять приглашение, войдите в financial_tracer с этим email и перейдите по ссылке:\n%s\n\n"+
+			"Ссылка действует %s.\n"+
+			"Если вы не ждали приглашения, просто проигнорируйте это письмо.\n",
+			invitation.InvitedBy, invitation.Household, invitation.Role, mailToken.Link(hs.opt.URL, token), hs.opt.TTL),
+	}
+}
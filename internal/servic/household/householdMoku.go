@@ -0,0 +1,67 @@
+package household
+
+import (
+	"context"
+	"time"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type DbMock struct {
+	mock.Mock
+}
+
+func (d *DbMock) PersonalHousehold(ctx context.Context, userID uint) (domain.Member, error) {
+	args := d.Called(ctx, userID)
+	return args.Get(0).(domain.Member), args.Error(1)
+}
+
+func (d *DbMock) HouseholdMember(ctx context.Context, userID uint, householdID uint) (domain.Member, error) {
+	args := d.Called(ctx, userID, householdID)
+	return args.Get(0).(domain.Member), args.Error(1)
+}
+
+func (d *DbMock) Households(ctx context.Context, userID uint) ([]domain.Household, error) {
+	args := d.Called(ctx, userID)
+	return args.Get(0).([]domain.Household), args.Error(1)
+}
+
+func (d *DbMock) Household(ctx context.Context, m domain.Member) (domain.Household, error) {
+	args := d.Called(ctx, m)
+	return args.Get(0).(domain.Household), args.Error(1)
+}
+
+func (d *DbMock) CreateHousehold(ctx context.Context, userID uint, name string) (domain.Household, error) {
+	args := d.Called(ctx, userID, name)
+	return args.Get(0).(domain.Household), args.Error(1)
+}
+
+func (d *DbMock) UpdateHouseholdMember(ctx context.Context, m domain.Member, userID uint, role string) error {
+	args := d.Called(ctx, m, userID, role)
+	return args.Error(0)
+}
+
+func (d *DbMock) RemoveHouseholdMember(ctx context.Context, m domain.Member, userID uint) error {
+	args := d.Called(ctx, m, userID)
+	return args.Error(0)
+}
+
+func (d *DbMock) CreateHouseholdInvitation(ctx context.Context, m domain.Member, invite domain.InviteMember, tokenHash string, expiresAt time.Time) (domain.HouseholdInvitation, error) {
+	args := d.Called(ctx, m, invite, tokenHash, expiresAt)
+	return args.Get(0).(domain.HouseholdInvitation), args.Error(1)
+}
+
+func (d *DbMock) AcceptHouseholdInvitation(ctx context.Context, userID uint, tokenHash string, now time.Time) (domain.Household, error) {
+	args := d.Called(ctx, userID, tokenHash, now)
+	return args.Get(0).(domain.Household), args.Error(1)
+}
+
+type MailerMock struct {
+	mock.Mock
+}
+
+func (m *MailerMock) Send(ctx context.Context, msg domain.Mail) error {
+	args := m.Called(ctx, msg)
+	return args.Error(0)
+}
@@ -0,0 +1,265 @@
+package household
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/financial_tracer/internal/infastructure/db/postgresql"
+	"github.com/financial_tracer/internal/infastructure/mail"
+	"github.com/financial_tracer/internal/lib/mailToken"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var (
+	now    = time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	member = domain.Member{UserID: 1, HouseholdID: 7}
+)
+
+func clock() time.Time {
+	return now
+}
+
+func TestMember(t *testing.T) {
+	personal := domain.Member{UserID: 1, HouseholdID: 2, Role: domain.HouseholdOwner}
+	shared := domain.Member{UserID: 1, HouseholdID: 7, Role: domain.HouseholdViewer}
+
+	tests := []struct {
+		name        string
+		householdID uint
+		mockErr     error
+		want        domain.Member
+		wantErr     error
+	}{
+		{name: "personal household", householdID: 0, want: personal},
+		{name: "shared household", householdID: 7, want: shared},
+		{name: "error not a member", householdID: 9, mockErr: postgresql.ErrorNotFound, wantErr: ErrForbidden},
+		{name: "error database", householdID: 7, mockErr: errors.New("some db error"), wantErr: ErrDatabase},
+	}
+
+	for _, ts := range tests {
+		t.Run(ts.name, func(t *testing.T) {
+			repoMock := new(DbMock)
+			repoMock.On("PersonalHousehold", mock.Anything, uint(1)).Return(personal, nil)
+			repoMock.On("HouseholdMember", mock.Anything, uint(1), ts.householdID).Return(shared, ts.mockErr)
+
+			server := CreateHouseholdServer(repoMock, repoMock, new(MailerMock), Options{}, logrus.New(), clock)
+			res, err := server.Member(context.Background(), 1, ts.householdID)
+
+			if ts.wantErr != nil {
+				assert.ErrorIs(t, err, ts.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, ts.want, res)
+		})
+	}
+}
+
+func TestCreateHousehold(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     domain.CreateHousehold
+		mockErr error
+		wantErr error
+		callDB  bool
+	}{
+		{name: "success", req: domain.CreateHousehold{Name: "семья"}, callDB: true},
+		{name: "error validate", req: domain.CreateHousehold{Name: "a"}},
+		{name: "error database", req: domain.CreateHousehold{Name: "семья"}, mockErr: errors.New("some db error"), wantErr: ErrDatabase, callDB: true},
+	}
+
+	for _, ts := range tests {
+		t.Run(ts.name, func(t *testing.T) {
+			repoMock := new(DbMock)
+			household := domain.Household{ID: 7, Name: ts.req.Name, Role: domain.HouseholdOwner}
+			repoMock.On("CreateHousehold", mock.Anything, uint(1), ts.req.Name).Return(household, ts.mockErr)
+
+			server := CreateHouseholdServer(repoMock, repoMock, new(MailerMock), Options{}, logrus.New(), clock)
+			res, err := server.CreateHousehold(context.Background(), 1, ts.req)
+
+			switch {
+			case !ts.callDB:
+				var verr validator.ValidationErrors
+				assert.ErrorAs(t, err, &verr)
+				repoMock.AssertNotCalled(t, "CreateHousehold", mock.Anything, mock.Anything, mock.Anything)
+			case ts.wantErr != nil:
+				assert.ErrorIs(t, err, ts.wantErr)
+			default:
+				assert.NoError(t, err)
+				assert.Equal(t, household, res)
+			}
+		})
+	}
+}
+
+func TestInvite(t *testing.T) {
+	req := domain.InviteMember{Email: "anna@gmail.com", Role: domain.HouseholdEditor}
+	invitation := domain.HouseholdInvitation{ID: 3, HouseholdID: 7, Household: "семья", Email: req.Email, Role: req.Role, InvitedBy: "jonn"}
+
+	t.Run("success", func(t *testing.T) {
+		var out bytes.Buffer
+		repoMock := new(DbMock)
+		repoMock.On("CreateHouseholdInvitation", mock.Anything, member, req, mock.Anything, now.Add(DefaultInviteTTL)).Return(invitation, nil)
+
+		opt := Options{URL: "https://tracker.local/invite"}
+		server := CreateHouseholdServer(repoMock, repoMock, mail.NewFileMailer(&out, "noreply@tracker.local"), opt, logrus.New(), clock)
+
+		res, err := server.Invite(context.Background(), member, req)
+		assert.NoError(t, err)
+		assert.Equal(t, invitation, res)
+
+		assert.Contains(t, out.String(), "To: anna@gmail.com")
+		token := regexp.MustCompile(`invite\?token=([A-Za-z0-9_-]+)`).FindStringSubmatch(out.String())
+		if assert.Len(t, token, 2) {
+			assert.Equal(t, mailToken.Hash(token[1]), repoMock.Calls[0].Arguments.Get(3))
+		}
+	})
+
+	tests := []struct {
+		name    string
+		req     domain.InviteMember
+		mockErr error
+		mailErr error
+		wantErr error
+	}{
+		{name: "error forbidden", req: req, mockErr: postgresql.ErrorForbidden, wantErr: ErrForbidden},
+		{name: "error personal", req: req, mockErr: postgresql.ErrorPersonal, wantErr: ErrPersonal},
+		{name: "error already member", req: req, mockErr: postgresql.ErrorDuplicated, wantErr: ErrDuplicated},
+		{name: "error not found", req: req, mockErr: postgresql.ErrorNotFound, wantErr: ErrNoFound},
+		{name: "error database", req: req, mockErr: errors.New("some db error"), wantErr: ErrDatabase},
+		{name: "error mail", req: req, mailErr: errors.New("connection refused"), wantErr: ErrMail},
+	}
+
+	for _, ts := range tests {
+		t.Run(ts.name, func(t *testing.T) {
+			repoMock := new(DbMock)
+			repoMock.On("CreateHouseholdInvitation", mock.Anything, member, ts.req, mock.Anything, mock.Anything).Return(invitation, ts.mockErr)
+			mailer := new(MailerMock)
+			mailer.On("Send", mock.Anything, mock.Anything).Return(ts.mailErr)
+
+			server := CreateHouseholdServer(repoMock, repoMock, mailer, Options{}, logrus.New(), clock)
+			_, err := server.Invite(context.Background(), member, ts.req)
+
+			assert.ErrorIs(t, err, ts.wantErr)
+			if ts.mockErr != nil {
+				mailer.AssertNotCalled(t, "Send", mock.Anything, mock.Anything)
+			}
+		})
+	}
+
+	t.Run("error owner role", func(t *testing.T) {
+		repoMock := new(DbMock)
+		server := CreateHouseholdServer(repoMock, repoMock, new(MailerMock), Options{}, logrus.New(), clock)
+
+		_, err := server.Invite(context.Background(), member, domain.InviteMember{Email: "anna@gmail.com", Role: domain.HouseholdOwner})
+
+		var verr validator.ValidationErrors
+		assert.ErrorAs(t, err, &verr)
+		repoMock.AssertNotCalled(t, "CreateHouseholdInvitation", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestAccept(t *testing.T) {
+	household := domain.Household{ID: 7, Name: "семья", Role: domain.HouseholdViewer}
+
+	tests := []struct {
+		name    string
+		mockErr error
+		wantErr error
+	}{
+		{name: "success"},
+		{name: "error invitation", mockErr: postgresql.ErrorNotFound, wantErr: ErrInvitation},
+		{name: "error already member", mockErr: postgresql.ErrorDuplicated, wantErr: ErrDuplicated},
+		{name: "error database", mockErr: errors.New("some db error"), wantErr: ErrDatabase},
+	}
+
+	for _, ts := range tests {
+		t.Run(ts.name, func(t *testing.T) {
+			repoMock := new(DbMock)
+			repoMock.On("AcceptHouseholdInvitation", mock.Anything, uint(2), mailToken.Hash("token"), now).Return(household, ts.mockErr)
+
+			server := CreateHouseholdServer(repoMock, repoMock, new(MailerMock), Options{}, logrus.New(), clock)
+			res, err := server.Accept(context.Background(), 2, domain.AcceptInvitation{Token: "token"})
+
+			if ts.wantErr != nil {
+				assert.ErrorIs(t, err, ts.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, household, res)
+		})
+	}
+}
+
+func TestUpdateMember(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     domain.UpdateMember
+		mockErr error
+		wantErr error
+		callDB  bool
+	}{
+		{name: "success", req: domain.UpdateMember{Role: domain.HouseholdViewer}, callDB: true},
+		{name: "error validate", req: domain.UpdateMember{Role: domain.HouseholdOwner}},
+		{name: "error forbidden", req: domain.UpdateMember{Role: domain.HouseholdViewer}, mockErr: postgresql.ErrorForbidden, wantErr: ErrForbidden, callDB: true},
+		{name: "error not found", req: domain.UpdateMember{Role: domain.HouseholdEditor}, mockErr: postgresql.ErrorNotFound, wantErr: ErrNoFound, callDB: true},
+	}
+
+	for _, ts := range tests {
+		t.Run(ts.name, func(t *testing.T) {
+			repoMock := new(DbMock)
+			repoMock.On("UpdateHouseholdMember", mock.Anything, member, uint(2), ts.req.Role).Return(ts.mockErr)
+
+			server := CreateHouseholdServer(repoMock, repoMock, new(MailerMock), Options{}, logrus.New(), clock)
+			err := server.UpdateMember(context.Background(), member, 2, ts.req)
+
+			switch {
+			case !ts.callDB:
+				var verr validator.ValidationErrors
+				assert.ErrorAs(t, err, &verr)
+				repoMock.AssertNotCalled(t, "UpdateHouseholdMember", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			case ts.wantErr != nil:
+				assert.ErrorIs(t, err, ts.wantErr)
+			default:
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestRemoveMember(t *testing.T) {
+	tests := []struct {
+		name    string
+		mockErr error
+		wantErr error
+	}{
+		{name: "success"},
+		{name: "error forbidden", mockErr: postgresql.ErrorForbidden, wantErr: ErrForbidden},
+		{name: "error not found", mockErr: postgresql.ErrorNotFound, wantErr: ErrNoFound},
+		{name: "error database", mockErr: errors.New("some db error"), wantErr: ErrDatabase},
+	}
+
+	for _, ts := range tests {
+		t.Run(ts.name, func(t *testing.T) {
+			repoMock := new(DbMock)
+			repoMock.On("RemoveHouseholdMember", mock.Anything, member, uint(2)).Return(ts.mockErr)
+
+			server := CreateHouseholdServer(repoMock, repoMock, new(MailerMock), Options{}, logrus.New(), clock)
+			err := server.RemoveMember(context.Background(), member, 2)
+
+			if ts.wantErr != nil {
+				assert.ErrorIs(t, err, ts.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
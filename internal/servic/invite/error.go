@@ -0,0 +1,26 @@
+package invite
+
+import (
+	"errors"
+
+	"github.com/financial_tracer/internal/infastructure/db/postgresql"
+)
+
+var (
+	ErrDatabase = errors.New("error database")
+	ErrServic   = errors.New("error servic")
+	ErrNoFound  = errors.New("invite code is not found")
+)
+
+func RegisterErrDatabase(err error) error {
+	arr := map[error]error{
+		postgresql.ErrorNotFound: ErrNoFound,
+	}
+
+	value, ok := arr[err]
+	if !ok {
+		return ErrDatabase
+	}
+
+	return value
+}
@@ -0,0 +1,118 @@
+package invite
+
+import (
+	"context"
+	"time"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/financial_tracer/internal/lib/mailToken"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+)
+
+// PrefixLen characters of the code stay in plain text, so admins can tell the codes apart.
+const PrefixLen = 6
+
+type InviteRepository interface {
+	CreateInviteCode(ctx context.Context, code domain.InviteCode, codeHash string) (domain.InviteCode, error)
+	InviteCodes(ctx context.Context) ([]domain.InviteCode, error)
+	RevokeInviteCode(ctx context.Context, id uint) error
+}
+
+type InviteServer struct {
+	r        InviteRepository
+	log      *logrus.Logger
+	validate validator.Validate
+	now      func() time.Time
+}
+
+func CreateInviteServer(r InviteRepository, log *logrus.Logger, now func() time.Time) *InviteServer {
+	return &InviteServer{
+		r:        r,
+		log:      log,
+		validate: *validator.New(),
+		now:      now,
+	}
+}
+
+func (is *InviteServer) Create(ctx context.Context, adminID uint, req domain.CreateInviteCode) (domain.NewInviteCode, error) {
+	const op = "invite.Create"
+
+	log := is.log.WithFields(logrus.Fields{
+		"op":       op,
+		"admin_id": adminID,
+	})
+
+	log.Info("start create invite code")
+
+	if err := is.validate.Struct(req); err != nil {
+		log.WithField("err", err).Error("invalid validate")
+		return domain.NewInviteCode{}, err
+	}
+
+	code, codeHash, err := mailToken.New()
+	if err != nil {
+		log.Error("error generate invite code: ", err)
+		return domain.NewInviteCode{}, ErrServic
+	}
+
+	value := domain.InviteCode{
+		Prefix:    code[:PrefixLen],
+		Note:      req.Note,
+		MaxUses:   req.MaxUses,
+		CreatedBy: adminID,
+	}
+	if req.ExpiresIn > 0 {
+		expiresAt := is.now().AddDate(0, 0, req.ExpiresIn)
+		value.ExpiresAt = &expiresAt
+	}
+
+	value, err = is.r.CreateInviteCode(ctx, value, codeHash)
+	if err != nil {
+		log.Error("error create invite code: ", err)
+		return domain.NewInviteCode{}, ErrDatabase
+	}
+
+	log.WithField("invite_id", value.ID).Info("success create invite code")
+
+	return domain.NewInviteCode{InviteCode: value, Code: code}, nil
+}
+
+func (is *InviteServer) List(ctx context.Context) ([]domain.InviteCode, error) {
+	const op = "invite.List"
+
+	log := is.log.WithField("op", op)
+
+	log.Info("start get invite codes")
+
+	codes, err := is.r.InviteCodes(ctx)
+	if err != nil {
+		log.Error("error get invite codes: ", err)
+		return nil, ErrDatabase
+	}
+
+	log.WithField("count", len(codes)).Info("success get invite codes")
+
+	return codes, nil
+}
+
+func (is *InviteServer) Revoke(ctx context.Context, adminID uint, id uint) error {
+	const op = "invite.Revoke"
+
+	log := is.log.WithFields(logrus.Fields{
+		"op":        op,
+		"admin_id":  adminID,
+		"invite_id": id,
+	})
+
+	log.Info("start revoke invite code")
+
+	if err := is.r.RevokeInviteCode(ctx, id); err != nil {
+		log.Error("error revoke invite code: ", err)
+		return RegisterErrDatabase(err)
+	}
+
+	log.Info("success revoke invite code")
+
+	return nil
+}
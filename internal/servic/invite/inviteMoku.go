@@ -0,0 +1,27 @@
+package invite
+
+import (
+	"context"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type DbMock struct {
+	mock.Mock
+}
+
+func (d *DbMock) CreateInviteCode(ctx context.Context, code domain.InviteCode, codeHash string) (domain.InviteCode, error) {
+	args := d.Called(ctx, code, codeHash)
+	return args.Get(0).(domain.InviteCode), args.Error(1)
+}
+
+func (d *DbMock) InviteCodes(ctx context.Context) ([]domain.InviteCode, error) {
+	args := d.Called(ctx)
+	return args.Get(0).([]domain.InviteCode), args.Error(1)
+}
+
+func (d *DbMock) RevokeInviteCode(ctx context.Context, id uint) error {
+	args := d.Called(ctx, id)
+	return args.Error(0)
+}
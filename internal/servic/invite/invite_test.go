@@ -0,0 +1,91 @@
+package invite
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/financial_tracer/internal/infastructure/db/postgresql"
+	"github.com/financial_tracer/internal/lib/mailToken"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreate(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	expiresAt := now.AddDate(0, 0, 7)
+
+	tests := []struct {
+		name        string
+		req         domain.CreateInviteCode
+		expiresAt   *time.Time
+		repoErr     error
+		wantErr     error
+		validateErr bool
+	}{
+		{name: "success", req: domain.CreateInviteCode{Note: "team", MaxUses: 5, ExpiresIn: 7}, expiresAt: &expiresAt},
+		{name: "success without expiry", req: domain.CreateInviteCode{MaxUses: 1}},
+		{name: "error max uses", req: domain.CreateInviteCode{}, validateErr: true},
+		{name: "error expires in", req: domain.CreateInviteCode{MaxUses: 1, ExpiresIn: 1000}, validateErr: true},
+		{name: "error database", req: domain.CreateInviteCode{MaxUses: 1}, repoErr: errors.New("database down"), wantErr: ErrDatabase},
+	}
+
+	for _, ts := range tests {
+		t.Run(ts.name, func(t *testing.T) {
+			repoMock := new(DbMock)
+			repoMock.On("CreateInviteCode", mock.Anything, mock.Anything, mock.Anything).Return(domain.InviteCode{ID: 4}, ts.repoErr)
+
+			server := CreateInviteServer(repoMock, logrus.New(), func() time.Time { return now })
+			code, err := server.Create(context.Background(), 1, ts.req)
+
+			if ts.validateErr {
+				var validErr validator.ValidationErrors
+				assert.ErrorAs(t, err, &validErr)
+				repoMock.AssertNotCalled(t, "CreateInviteCode", mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			assert.ErrorIs(t, err, ts.wantErr)
+			if ts.wantErr != nil {
+				assert.Empty(t, code.Code)
+				return
+			}
+
+			call := repoMock.Calls[0]
+			stored := call.Arguments.Get(1).(domain.InviteCode)
+			assert.Equal(t, mailToken.Hash(code.Code), call.Arguments.String(2))
+			assert.Equal(t, code.Code[:PrefixLen], stored.Prefix)
+			assert.Equal(t, ts.req.MaxUses, stored.MaxUses)
+			assert.Equal(t, uint(1), stored.CreatedBy)
+			assert.Equal(t, ts.expiresAt, stored.ExpiresAt)
+			assert.Equal(t, uint(4), code.ID)
+		})
+	}
+}
+
+func TestRevoke(t *testing.T) {
+	tests := []struct {
+		name    string
+		repoErr error
+		wantErr error
+	}{
+		{name: "success"},
+		{name: "error not found", repoErr: postgresql.ErrorNotFound, wantErr: ErrNoFound},
+		{name: "error database", repoErr: errors.New("database down"), wantErr: ErrDatabase},
+	}
+
+	for _, ts := range tests {
+		t.Run(ts.name, func(t *testing.T) {
+			repoMock := new(DbMock)
+			repoMock.On("RevokeInviteCode", mock.Anything, uint(4)).Return(ts.repoErr)
+
+			err := CreateInviteServer(repoMock, logrus.New(), time.Now).Revoke(context.Background(), 1, 4)
+
+			assert.ErrorIs(t, err, ts.wantErr)
+			repoMock.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package ledger
+
+import (
+	"errors"
+
+	"github.com/financial_tracer/internal/infastructure/db/postgresql"
+)
+
+var (
+	ErrDatabase   = errors.New("error database")
+	ErrNoFound    = errors.New("user is not found")
+	ErrDuplicated = errors.New("category belongs to another household")
+	ErrForbidden  = errors.New("no access to the household")
+	ErrFormat     = errors.New("unknown journal format")
+	ErrParse      = errors.New("invalid journal")
+)
+
+func RegisterErrDatabase(err error) error {
+	arr := map[error]error{
+		postgresql.ErrorNotFound:   ErrNoFound,
+		postgresql.ErrorDuplicated: ErrDuplicated,
+		postgresql.ErrorForbidden:  ErrForbidden,
+	}
+
+	value, ok := arr[err]
+	if !ok {
+		return ErrDatabase
+	}
+
+	return value
+}
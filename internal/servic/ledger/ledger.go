@@ -0,0 +1,136 @@
+package ledger
+
+import (
+	"bytes"
+	"context"
+	"io"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/financial_tracer/internal/lib/journal"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+)
+
+type JournalRepository interface {
+	Journal(ctx context.Context, m domain.Member) (domain.Journal, error)
+}
+
+type ImportJournalRepository interface {
+	ImportJournal(ctx context.Context, m domain.Member, journal domain.Journal) (domain.JournalImport, error)
+}
+
+type LedgerServer struct {
+	j        JournalRepository
+	i        ImportJournalRepository
+	log      *logrus.Logger
+	validate validator.Validate
+}
+
+func CreateLedgerServer(j JournalRepository, i ImportJournalRepository, log *logrus.Logger) *LedgerServer {
+	return &LedgerServer{
+		j:        j,
+		i:        i,
+		log:      log,
+		validate: *validator.New(),
+	}
+}
+
+func (ls *LedgerServer) ExportJournal(ctx context.Context, m domain.Member, format string) ([]byte, journal.Format, error) {
+	const op = "ledger.ExportJournal"
+
+	log := ls.log.WithFields(logrus.Fields{
+		"op":           op,
+		"user_id":      m.UserID,
+		"household_id": m.HouseholdID,
+		"format":       format,
+	})
+
+	log.Info("start export journal")
+
+	f, err := journal.ParseFormat(format)
+	if err != nil {
+		log.WithField("err", err).Error("invalid format")
+		return nil, "", ErrFormat
+	}
+
+	data, err := ls.j.Journal(ctx, m)
+	if err != nil {
+		log.Error("error get journal: ", err)
+		return nil, "", RegisterErrDatabase(err)
+	}
+
+	var buf bytes.Buffer
+	if err := journal.Encode(&buf, f, data); err != nil {
+		log.WithField("err", err).Error("error encode journal")
+		return nil, "", err
+	}
+
+	log.Info("success export journal")
+
+	return buf.Bytes(), f, nil
+}
+
+func (ls *LedgerServer) ImportJournal(ctx context.Context, m domain.Member, format string, r io.Reader) (domain.JournalImport, error) {
+	const op = "ledger.ImportJournal"
+
+	log := ls.log.WithFields(logrus.Fields{
+		"op":           op,
+		"user_id":      m.UserID,
+		"household_id": m.HouseholdID,
+		"format":       format,
+	})
+
+	log.Info("start import journal")
+
+	f, err := journal.ParseFormat(format)
+	if err != nil {
+		log.WithField("err", err).Error("invalid format")
+		return domain.JournalImport{}, ErrFormat
+	}
+
+	data, err := journal.Decode(r, f)
+	if err != nil {
+		// a syntax error or an unreadable body, such as one over the size limit
+		log.WithField("err", err).Error("error decode journal")
+		return domain.JournalImport{}, ErrParse
+	}
+
+	defaultLimits(&data)
+
+	if err := ls.validate.Struct(data); err != nil {
+		log.WithField("err", err).Error("invalid validate")
+		return domain.JournalImport{}, err
+	}
+
+	res, err := ls.i.ImportJournal(ctx, m, data)
+	if err != nil {
+		log.Error("error import journal: ", err)
+		return domain.JournalImport{}, RegisterErrDatabase(err)
+	}
+
+	log.WithFields(logrus.Fields{
+		"categories":   res.Categories,
+		"transactions": res.Transactions,
+		"skipped":      res.Skipped,
+	}).Info("success import journal")
+
+	return res, nil
+}
+
+// defaultLimits keeps the imported history within the limit of the categories that came without one.
+func defaultLimits(data *domain.Journal) {
+	for i := range data.Categories {
+		c := &data.Categories[i]
+		if c.Limit != 0 {
+			continue
+		}
+		for _, t := range data.Transactions {
+			if t.Category == c.Name && t.Count > c.Limit {
+				c.Limit = t.Count
+			}
+		}
+		if c.Limit == 0 {
+			c.Limit = 1
+		}
+	}
+}
@@ -0,0 +1,22 @@
+package ledger
+
+import (
+	"context"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type DbMock struct {
+	mock.Mock
+}
+
+func (d *DbMock) Journal(ctx context.Context, m domain.Member) (domain.Journal, error) {
+	args := d.Called(ctx, m)
+	return args.Get(0).(domain.Journal), args.Error(1)
+}
+
+func (d *DbMock) ImportJournal(ctx context.Context, m domain.Member, journal domain.Journal) (domain.JournalImport, error) {
+	args := d.Called(ctx, m, journal)
+	return args.Get(0).(domain.JournalImport), args.Error(1)
+}
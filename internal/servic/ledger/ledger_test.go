@@ -0,0 +1,205 @@
+package ledger
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/financial_tracer/internal/infastructure/db/postgresql"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var member = domain.Member{UserID: 1, HouseholdID: 3, Role: domain.HouseholdOwner}
+
+func TestExportJournal(t *testing.T) {
+	type test struct {
+		name         string
+		format       string
+		journal      domain.Journal
+		mockErr      error
+		ledgerErr    error
+		contains     string
+		shouldCallDB bool
+	}
+
+	data := domain.Journal{
+		UserName:   "jonn",
+		Email:      "jonn@gmail.com",
+		Categories: []domain.CategoryInput{{Name: "food", Limit: 1000}},
+		Transactions: []domain.JournalTransaction{
+			{Date: time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC), Category: "food", Name: "market", Count: 200},
+		},
+	}
+
+	arrTests := []test{
+		{
+			name:         "success ledger",
+			format:       "ledger",
+			journal:      data,
+			contains:     "2025-05-01 market",
+			shouldCallDB: true,
+		},
+		{
+			name:         "success beancount",
+			format:       "beancount",
+			journal:      data,
+			contains:     `2025-05-01 * "market" ""`,
+			shouldCallDB: true,
+		},
+		{
+			name:         "error format",
+			format:       "qif",
+			ledgerErr:    ErrFormat,
+			shouldCallDB: false,
+		},
+		{
+			name:         "error not found",
+			format:       "hledger",
+			mockErr:      postgresql.ErrorNotFound,
+			ledgerErr:    ErrNoFound,
+			shouldCallDB: true,
+		},
+		{
+			name:         "error database",
+			format:       "hledger",
+			mockErr:      errors.New("some db error"),
+			ledgerErr:    ErrDatabase,
+			shouldCallDB: true,
+		},
+	}
+
+	for _, ts := range arrTests {
+		t.Run(ts.name, func(t *testing.T) {
+			repoMock := new(DbMock)
+			repoMock.On("Journal", mock.Anything, member).Return(ts.journal, ts.mockErr)
+
+			server := CreateLedgerServer(repoMock, repoMock, logrus.New())
+			res, _, err := server.ExportJournal(context.Background(), member, ts.format)
+
+			if ts.ledgerErr != nil {
+				assert.ErrorIs(t, err, ts.ledgerErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Contains(t, string(res), ts.contains)
+			}
+
+			if ts.shouldCallDB {
+				repoMock.AssertCalled(t, "Journal", mock.Anything, member)
+			} else {
+				repoMock.AssertNotCalled(t, "Journal", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestImportJournal(t *testing.T) {
+	type test struct {
+		name         string
+		format       string
+		src          string
+		readErr      error
+		journal      domain.Journal
+		result       domain.JournalImport
+		mockErr      error
+		ledgerErr    error
+		shouldCallDB bool
+	}
+
+	arrTests := []test{
+		{
+			name:   "success",
+			format: "ledger",
+			src:    "2025-05-01 market\n    Expenses:Food    200 RUB\n    Assets:Cash\n",
+			journal: domain.Journal{
+				Categories: []domain.CategoryInput{{Name: "Food", Limit: 200}},
+				Transactions: []domain.JournalTransaction{
+					{Date: time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC), Category: "Food", Name: "market", Count: 200},
+				},
+			},
+			result:       domain.JournalImport{Categories: 1, Transactions: 1},
+			shouldCallDB: true,
+		},
+		{
+			name:         "error format",
+			format:       "qif",
+			ledgerErr:    ErrFormat,
+			shouldCallDB: false,
+		},
+		{
+			name:         "error parse",
+			format:       "ledger",
+			src:          "2025-05-01 market\n    Expenses:Food    much\n",
+			ledgerErr:    ErrParse,
+			shouldCallDB: false,
+		},
+		{
+			name:         "error read",
+			format:       "ledger",
+			readErr:      errors.New("http: request body too large"),
+			ledgerErr:    ErrParse,
+			shouldCallDB: false,
+		},
+		{
+			name:         "error validate",
+			format:       "ledger",
+			src:          "2025-05-01 x\n    Expenses:TV    200 RUB\n    Assets:Cash\n",
+			ledgerErr:    validator.ValidationErrors{},
+			shouldCallDB: false,
+		},
+		{
+			name:   "error duplicated",
+			format: "beancount",
+			src:    "2025-05-01 * \"market\"\n  Expenses:Food 200 RUB\n  Assets:Cash\n",
+			journal: domain.Journal{
+				Categories: []domain.CategoryInput{{Name: "Food", Limit: 200}},
+				Transactions: []domain.JournalTransaction{
+					{Date: time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC), Category: "Food", Name: "market", Count: 200},
+				},
+			},
+			mockErr:      postgresql.ErrorDuplicated,
+			ledgerErr:    ErrDuplicated,
+			shouldCallDB: true,
+		},
+	}
+
+	for _, ts := range arrTests {
+		t.Run(ts.name, func(t *testing.T) {
+			repoMock := new(DbMock)
+			repoMock.On("ImportJournal", mock.Anything, member, ts.journal).Return(ts.result, ts.mockErr)
+
+			server := CreateLedgerServer(repoMock, repoMock, logrus.New())
+			var r io.Reader = strings.NewReader(ts.src)
+			if ts.readErr != nil {
+				r = iotest.ErrReader(ts.readErr)
+			}
+			res, err := server.ImportJournal(context.Background(), member, ts.format, r)
+
+			if ts.ledgerErr != nil {
+				assert.Error(t, err)
+				var validErr validator.ValidationErrors
+				if errors.As(ts.ledgerErr, &validErr) {
+					assert.True(t, errors.As(err, &validErr))
+				} else {
+					assert.ErrorIs(t, err, ts.ledgerErr)
+				}
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, ts.result, res)
+			}
+
+			if ts.shouldCallDB {
+				repoMock.AssertCalled(t, "ImportJournal", mock.Anything, member, ts.journal)
+			} else {
+				repoMock.AssertNotCalled(t, "ImportJournal", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package lockout
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+var ErrLocked = errors.New("too many failed attempts, try again later")
+
+type LockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LockedError) Error() string {
+	return fmt.Sprintf("%s: retry after %s", ErrLocked, e.RetryAfter)
+}
+
+func (e *LockedError) Unwrap() error {
+	return ErrLocked
+}
+
+// Seconds rounds RetryAfter up, as the Retry-After header takes it.
+func (e *LockedError) Seconds() int {
+	return int(math.Ceil(e.RetryAfter.Seconds()))
+}
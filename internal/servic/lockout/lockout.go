@@ -0,0 +1,189 @@
+package lockout
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// after MaxAttempts failures a subject is locked for BaseLock, doubling up to MaxLock
+const (
+	DefaultMaxAttempts   = 5
+	DefaultMaxAttemptsIP = 50
+	DefaultBaseLock      = time.Minute
+	DefaultMaxLock       = time.Hour
+	DefaultWindow        = 24 * time.Hour
+)
+
+type AttemptStore interface {
+	Fail(ctx context.Context, key string, window time.Duration) (int64, error)
+	Lock(ctx context.Context, key string, ttl time.Duration) error
+	Locked(ctx context.Context, key string) (time.Duration, error)
+	Reset(ctx context.Context, key string) error
+}
+
+type Options struct {
+	MaxAttempts   int
+	MaxAttemptsIP int
+	BaseLock      time.Duration
+	MaxLock       time.Duration
+	Window        time.Duration
+}
+
+// Subject is an email, a user or an IP address the failed attempts are counted for.
+type Subject struct {
+	kind string
+	id   string
+}
+
+func Email(email string) Subject {
+	return Subject{kind: "email", id: strings.ToLower(strings.TrimSpace(email))}
+}
+
+func User(id uint) Subject {
+	return Subject{kind: "user", id: strconv.FormatUint(uint64(id), 10)}
+}
+
+func IP(ip string) Subject {
+	return Subject{kind: "ip", id: ip}
+}
+
+func (s Subject) key() string {
+	return s.kind + ":" + s.id
+}
+
+type Lockout struct {
+	store AttemptStore
+	opt   Options
+	log   *logrus.Logger
+}
+
+func CreateLockout(store AttemptStore, opt Options, log *logrus.Logger) *Lockout {
+	if opt.MaxAttempts == 0 {
+		opt.MaxAttempts = DefaultMaxAttempts
+	}
+	if opt.MaxAttemptsIP == 0 {
+		opt.MaxAttemptsIP = DefaultMaxAttemptsIP
+	}
+	if opt.BaseLock == 0 {
+		opt.BaseLock = DefaultBaseLock
+	}
+	if opt.MaxLock == 0 {
+		opt.MaxLock = DefaultMaxLock
+	}
+	if opt.Window == 0 {
+		opt.Window = DefaultWindow
+	}
+
+	return &Lockout{
+		store: store,
+		opt:   opt,
+		log:   log,
+	}
+}
+
+// Check lets the attempt through when the store fails, the lockout must not take the login down.
+func (l *Lockout) Check(ctx context.Context, subjects ...Subject) error {
+	const op = "lockout.Check"
+
+	var retry time.Duration
+	for _, s := range subjects {
+		if s.id == "" {
+			continue
+		}
+
+		ttl, err := l.store.Locked(ctx, s.key())
+		if err != nil {
+			l.log.WithFields(logrus.Fields{"op": op, "err": err}).Error("error check lock")
+			continue
+		}
+		retry = max(retry, ttl)
+	}
+
+	if retry > 0 {
+		return &LockedError{RetryAfter: retry}
+	}
+
+	return nil
+}
+
+func (l *Lockout) Fail(ctx context.Context, subjects ...Subject) error {
+	const op = "lockout.Fail"
+
+	var retry time.Duration
+	for _, s := range subjects {
+		if s.id == "" {
+			continue
+		}
+
+		log := l.log.WithFields(logrus.Fields{
+			"op":      op,
+			"subject": s.kind,
+			"id":      s.id,
+		})
+
+		failures, err := l.store.Fail(ctx, s.key(), l.opt.Window)
+		if err != nil {
+			log.WithField("err", err).Error("error record failure")
+			continue
+		}
+
+		lock := l.lockFor(s, failures)
+		if lock == 0 {
+			continue
+		}
+
+		if err := l.store.Lock(ctx, s.key(), lock); err != nil {
+			log.WithField("err", err).Error("error lock")
+			continue
+		}
+
+		log.WithFields(logrus.Fields{
+			"failures": failures,
+			"lock":     lock.String(),
+		}).Warn("locked after failed attempts")
+
+		retry = max(retry, lock)
+	}
+
+	if retry > 0 {
+		return &LockedError{RetryAfter: retry}
+	}
+
+	return nil
+}
+
+func (l *Lockout) Reset(ctx context.Context, subjects ...Subject) {
+	const op = "lockout.Reset"
+
+	for _, s := range subjects {
+		if s.id == "" {
+			continue
+		}
+
+		if err := l.store.Reset(ctx, s.key()); err != nil {
+			l.log.WithFields(logrus.Fields{"op": op, "err": err}).Error("error reset")
+		}
+	}
+}
+
+func (l *Lockout) lockFor(s Subject, failures int64) time.Duration {
+	limit := int64(l.opt.MaxAttempts)
+	if s.kind == "ip" {
+		limit = int64(l.opt.MaxAttemptsIP)
+	}
+
+	if failures < limit {
+		return 0
+	}
+
+	lock := l.opt.BaseLock
+	for i := limit; i < failures && lock < l.opt.MaxLock; i++ {
+		lock *= 2
+	}
+
+	return min(lock, l.opt.MaxLock)
+}
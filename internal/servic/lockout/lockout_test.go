@@ -0,0 +1,101 @@
+package lockout
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/financial_tracer/internal/infastructure/cash"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type clock struct {
+	t time.Time
+}
+
+func (c *clock) now() time.Time {
+	return c.t
+}
+
+func TestLockoutBackoff(t *testing.T) {
+	ctx := context.Background()
+	c := &clock{t: time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)}
+	l := CreateLockout(cash.CreateMemoryAttempts(c.now), Options{MaxAttempts: 3, MaxLock: 3 * time.Minute}, logrus.New())
+	email := Email("Jonn@Gmail.com ")
+
+	for i := 0; i < 2; i++ {
+		assert.NoError(t, l.Fail(ctx, email))
+	}
+	assert.NoError(t, l.Check(ctx, email))
+
+	tests := []time.Duration{time.Minute, 2 * time.Minute, 3 * time.Minute, 3 * time.Minute}
+	for _, want := range tests {
+		var locked *LockedError
+		require.ErrorAs(t, l.Fail(ctx, email), &locked)
+		assert.Equal(t, want, locked.RetryAfter)
+
+		require.ErrorAs(t, l.Check(ctx, Email("jonn@gmail.com")), &locked)
+		assert.Equal(t, want, locked.RetryAfter)
+		assert.ErrorIs(t, locked, ErrLocked)
+	}
+
+	c.t = c.t.Add(3 * time.Minute)
+	assert.NoError(t, l.Check(ctx, email))
+
+	l.Reset(ctx, email)
+	assert.NoError(t, l.Fail(ctx, email))
+}
+
+func TestLockoutSubjects(t *testing.T) {
+	ctx := context.Background()
+	c := &clock{t: time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)}
+	l := CreateLockout(cash.CreateMemoryAttempts(c.now), Options{MaxAttempts: 1, MaxAttemptsIP: 2}, logrus.New())
+
+	// the email is locked, the IP is still under its limit
+	err := l.Fail(ctx, Email("jonn@gmail.com"), IP("10.0.0.1"))
+	assert.ErrorIs(t, err, ErrLocked)
+	assert.NoError(t, l.Check(ctx, Email("sasha@gmail.com"), IP("10.0.0.1")))
+
+	err = l.Fail(ctx, Email("sasha@gmail.com"), IP("10.0.0.1"))
+	assert.ErrorIs(t, err, ErrLocked)
+	assert.ErrorIs(t, l.Check(ctx, Email("ivan@gmail.com"), IP("10.0.0.1")), ErrLocked)
+
+	// subjects without an id are not counted
+	assert.NoError(t, l.Fail(ctx, IP("")))
+	assert.NoError(t, l.Check(ctx, IP("")))
+}
+
+type downStore struct{}
+
+var errDown = errors.New("connection refused")
+
+func (downStore) Fail(ctx context.Context, key string, window time.Duration) (int64, error) {
+	return 0, errDown
+}
+
+func (downStore) Lock(ctx context.Context, key string, ttl time.Duration) error {
+	return errDown
+}
+
+func (downStore) Locked(ctx context.Context, key string) (time.Duration, error) {
+	return 0, errDown
+}
+
+func (downStore) Reset(ctx context.Context, key string) error {
+	return errDown
+}
+
+func TestLockoutStoreError(t *testing.T) {
+	l := CreateLockout(downStore{}, Options{MaxAttempts: 1}, logrus.New())
+
+	assert.NoError(t, l.Fail(context.Background(), Email("jonn@gmail.com")))
+	assert.NoError(t, l.Check(context.Background(), Email("jonn@gmail.com")))
+}
+
+func TestLockedErrorSeconds(t *testing.T) {
+	assert.Equal(t, 60, (&LockedError{RetryAfter: time.Minute}).Seconds())
+	assert.Equal(t, 2, (&LockedError{RetryAfter: 1500 * time.Millisecond}).Seconds())
+}
@@ -0,0 +1,29 @@
+package password
+
+import (
+	"errors"
+
+	"github.com/financial_tracer/internal/infastructure/db/postgresql"
+)
+
+var (
+	ErrDatabase   = errors.New("error database")
+	ErrServic     = errors.New("servic error")
+	ErrNoFound    = errors.New("user is not found")
+	ErrPassword   = errors.New("wrong password")
+	ErrResetToken = errors.New("invalid or expired reset token")
+)
+
+func RegisterErrDatabase(err error) error {
+	arr := map[error]error{
+		postgresql.ErrorNotFound: ErrNoFound,
+		postgresql.ErrorPassword: ErrPassword,
+	}
+
+	value, ok := arr[err]
+	if !ok {
+		return ErrDatabase
+	}
+
+	return value
+}
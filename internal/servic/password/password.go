@@ -0,0 +1,227 @@
+package password
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/financial_tracer/internal/infastructure/db/postgresql"
+	"github.com/financial_tracer/internal/lib/hashPassword"
+	"github.com/financial_tracer/internal/lib/mailToken"
+	"github.com/financial_tracer/internal/lib/passwordPolicy"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+)
+
+const DefaultResetTTL = time.Hour
+
+type ChangePasswordRepository interface {
+	UserProfile(ctx context.Context, userID uint) (domain.Profile, error)
+	ChangePassword(ctx context.Context, userID uint, sessionID uint, oldPassword string, newHash []byte) error
+}
+
+type ResetPasswordRepository interface {
+	CreatePasswordReset(ctx context.Context, email string, tokenHash string, expiresAt time.Time) (domain.User, error)
+	PasswordResetUser(ctx context.Context, tokenHash string) (domain.User, error)
+	ResetPassword(ctx context.Context, tokenHash string, newHash []byte) (uint, error)
+}
+
+type Mailer interface {
+	Send(ctx context.Context, msg domain.Mail) error
+}
+
+type EventRecorder interface {
+	Record(ctx context.Context, event domain.SecurityEvent)
+}
+
+type Options struct {
+	ResetTTL time.Duration
+	ResetURL string
+	Policy   passwordPolicy.Policy
+}
+
+type PasswordServer struct {
+	c        ChangePasswordRepository
+	r        ResetPasswordRepository
+	m        Mailer
+	e        EventRecorder
+	opt      Options
+	log      *logrus.Logger
+	validate validator.Validate
+}
+
+func CreatePasswordServer(c ChangePasswordRepository, r ResetPasswordRepository, m Mailer, e EventRecorder, opt Options, log *logrus.Logger) *PasswordServer {
+	if opt.ResetTTL == 0 {
+		opt.ResetTTL = DefaultResetTTL
+	}
+
+	validate := validator.New()
+	passwordPolicy.Register(validate, opt.Policy)
+
+	return &PasswordServer{
+		c:        c,
+		r:        r,
+		m:        m,
+		e:        e,
+		opt:      opt,
+		log:      log,
+		validate: *validate,
+	}
+}
+
+// ChangePassword checks the new password against the stored name and email.
+func (ps *PasswordServer) ChangePassword(ctx context.Context, userID uint, sessionID uint, req domain.ChangePassword) error {
+	const op = "password.ChangePassword"
+
+	log := ps.log.WithFields(logrus.Fields{
+		"op":      op,
+		"user_id": userID,
+	})
+
+	log.Info("start change password")
+
+	if err := ps.validate.Struct(req); err != nil {
+		log.WithField("err", err).Error("invalid validate")
+		return err
+	}
+
+	profile, err := ps.c.UserProfile(ctx, userID)
+	if err != nil {
+		log.Error("error get user: ", err)
+		return RegisterErrDatabase(err)
+	}
+
+	if err := passwordPolicy.CheckPersonal(&ps.validate, req.NewPassword, profile.Name, profile.Email); err != nil {
+		log.WithField("err", err).Error("invalid validate")
+		return err
+	}
+
+	hash, err := hashPassword.Hash(req.NewPassword)
+	if err != nil {
+		log.WithField("err", err).Error("field hash password")
+		return ErrServic
+	}
+
+	if err := ps.c.ChangePassword(ctx, userID, sessionID, req.OldPassword, hash); err != nil {
+		log.Error("error change password: ", err)
+		return RegisterErrDatabase(err)
+	}
+
+	ps.e.Record(ctx, domain.SecurityEvent{
+		UserID:  userID,
+		Type:    domain.EventPasswordChange,
+		Success: true,
+		Device:  req.Device,
+	})
+
+	log.Info("success change password")
+
+	return nil
+}
+
+// ForgotPassword reports neither an unknown email nor a failed mail, so it can't find registered emails.
+func (ps *PasswordServer) ForgotPassword(ctx context.Context, req domain.ForgotPassword) error {
+	const op = "password.ForgotPassword"
+
+	log := ps.log.WithField("op", op)
+
+	log.Info("start forgot password")
+
+	if err := ps.validate.Struct(req); err != nil {
+		log.WithField("err", err).Error("invalid validate")
+		return err
+	}
+
+	token, hash, err := mailToken.New()
+	if err != nil {
+		log.WithField("err", err).Error("field create reset token")
+		return ErrServic
+	}
+
+	user, err := ps.r.CreatePasswordReset(ctx, req.Email, hash, time.Now().Add(ps.opt.ResetTTL))
+	if err != nil {
+		if errors.Is(err, postgresql.ErrorNotFound) {
+			log.Warn("password reset for unknown email")
+			return nil
+		}
+		log.Error("error create password reset: ", err)
+		return ErrDatabase
+	}
+
+	if err := ps.m.Send(ctx, ps.resetMail(user, token)); err != nil {
+		log.WithField("err", err).Error("error send reset mail")
+		return nil
+	}
+
+	log.Info("success forgot password")
+
+	return nil
+}
+
+// ResetPassword checks the new password against the name and email of the token owner.
+func (ps *PasswordServer) ResetPassword(ctx context.Context, req domain.ResetPassword) error {
+	const op = "password.ResetPassword"
+
+	log := ps.log.WithField("op", op)
+
+	log.Info("start reset password")
+
+	if err := ps.validate.Struct(req); err != nil {
+		log.WithField("err", err).Error("invalid validate")
+		return err
+	}
+
+	user, err := ps.r.PasswordResetUser(ctx, mailToken.Hash(req.Token))
+	if err != nil {
+		log.Error("error get reset user: ", err)
+		if errors.Is(err, postgresql.ErrorNotFound) {
+			return ErrResetToken
+		}
+		return ErrDatabase
+	}
+
+	if err := passwordPolicy.CheckPersonal(&ps.validate, req.NewPassword, user.Name, user.Email); err != nil {
+		log.WithField("err", err).Error("invalid validate")
+		return err
+	}
+
+	hash, err := hashPassword.Hash(req.NewPassword)
+	if err != nil {
+		log.WithField("err", err).Error("field hash password")
+		return ErrServic
+	}
+
+	userID, err := ps.r.ResetPassword(ctx, mailToken.Hash(req.Token), hash)
+	if err != nil {
+		log.Error("error reset password: ", err)
+		if errors.Is(err, postgresql.ErrorNotFound) {
+			return ErrResetToken
+		}
+		return ErrDatabase
+	}
+
+	ps.e.Record(ctx, domain.SecurityEvent{
+		UserID:  userID,
+		Type:    domain.EventPasswordReset,
+		Success: true,
+		Device:  req.Device,
+	})
+
+	log.WithField("user_id", userID).Info("success reset password")
+
+	return nil
+}
+
+func (ps *PasswordServer) resetMail(user domain.User, token string) domain.Mail {
+	return domain.Mail{
+		To:      user.Email,
+		Subject: "Сброс пароля financial_tracer",
+		Body: fmt.Sprintf("Здравствуйте, %s!\n\n"+
+			"Для сброса пароля перейдите по ссылке:\n%s\n\n"+
+			"Ссылка действует %s и может быть использована один раз.\n"+
+			"Если вы не запрашивали сброс пароля, просто проигнорируйте это письмо.\n",
+			user.Name, mailToken.Link(ps.opt.ResetURL, token), ps.opt.ResetTTL),
+	}
+}
@@ -0,0 +1,47 @@
+package password
+
+import (
+	"context"
+	"time"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type DbMock struct {
+	mock.Mock
+}
+
+func (d *DbMock) UserProfile(ctx context.Context, userID uint) (domain.Profile, error) {
+	args := d.Called(ctx, userID)
+	return args.Get(0).(domain.Profile), args.Error(1)
+}
+
+func (d *DbMock) PasswordResetUser(ctx context.Context, tokenHash string) (domain.User, error) {
+	args := d.Called(ctx, tokenHash)
+	return args.Get(0).(domain.User), args.Error(1)
+}
+
+func (d *DbMock) ChangePassword(ctx context.Context, userID uint, sessionID uint, oldPassword string, newHash []byte) error {
+	args := d.Called(ctx, userID, sessionID, oldPassword, newHash)
+	return args.Error(0)
+}
+
+func (d *DbMock) CreatePasswordReset(ctx context.Context, email string, tokenHash string, expiresAt time.Time) (domain.User, error) {
+	args := d.Called(ctx, email, tokenHash, expiresAt)
+	return args.Get(0).(domain.User), args.Error(1)
+}
+
+func (d *DbMock) ResetPassword(ctx context.Context, tokenHash string, newHash []byte) (uint, error) {
+	args := d.Called(ctx, tokenHash, newHash)
+	return args.Get(0).(uint), args.Error(1)
+}
+
+type MailerMock struct {
+	mock.Mock
+}
+
+func (m *MailerMock) Send(ctx context.Context, msg domain.Mail) error {
+	args := m.Called(ctx, msg)
+	return args.Error(0)
+}
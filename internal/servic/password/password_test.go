@@ -0,0 +1,264 @@
+package password
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/financial_tracer/internal/infastructure/db/postgresql"
+	"github.com/financial_tracer/internal/infastructure/mail"
+	"github.com/financial_tracer/internal/lib/hashPassword"
+	"github.com/financial_tracer/internal/lib/mailToken"
+	"github.com/financial_tracer/internal/servic/security"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func assertErr(t *testing.T, want error, err error) {
+	t.Helper()
+
+	var validErr validator.ValidationErrors
+	if errors.As(want, &validErr) {
+		assert.True(t, errors.As(err, &validErr))
+		return
+	}
+	assert.ErrorIs(t, err, want)
+}
+
+func TestChangePassword(t *testing.T) {
+	type test struct {
+		name         string
+		req          domain.ChangePassword
+		profileErr   error
+		mockErr      error
+		wantErr      error
+		shouldCallDB bool
+	}
+
+	tests := []test{
+		{
+			name:         "success",
+			req:          domain.ChangePassword{OldPassword: "admin12241532", NewPassword: "gpDIJGP:OGhiHG"},
+			shouldCallDB: true,
+		},
+		{
+			name:         "error wrong password",
+			req:          domain.ChangePassword{OldPassword: "admin", NewPassword: "gpDIJGP:OGhiHG"},
+			mockErr:      postgresql.ErrorPassword,
+			wantErr:      ErrPassword,
+			shouldCallDB: true,
+		},
+		{
+			name:         "error same password",
+			req:          domain.ChangePassword{OldPassword: "admin12241532", NewPassword: "admin12241532"},
+			wantErr:      validator.ValidationErrors{},
+			shouldCallDB: false,
+		},
+		{
+			name:         "error password with name",
+			req:          domain.ChangePassword{OldPassword: "admin12241532", NewPassword: "xxJonnDoe2024"},
+			wantErr:      validator.ValidationErrors{},
+			shouldCallDB: false,
+		},
+		{
+			name:         "error password with email",
+			req:          domain.ChangePassword{OldPassword: "admin12241532", NewPassword: "jonn.doe-2024"},
+			wantErr:      validator.ValidationErrors{},
+			shouldCallDB: false,
+		},
+		{
+			name:         "error user not found",
+			req:          domain.ChangePassword{OldPassword: "admin12241532", NewPassword: "gpDIJGP:OGhiHG"},
+			profileErr:   postgresql.ErrorNotFound,
+			wantErr:      ErrNoFound,
+			shouldCallDB: false,
+		},
+		{
+			name:         "error database",
+			req:          domain.ChangePassword{OldPassword: "admin12241532", NewPassword: "gpDIJGP:OGhiHG"},
+			mockErr:      errors.New("some db error"),
+			wantErr:      ErrDatabase,
+			shouldCallDB: true,
+		},
+	}
+
+	for _, ts := range tests {
+		t.Run(ts.name, func(t *testing.T) {
+			repoMock := new(DbMock)
+			repoMock.On("UserProfile", mock.Anything, uint(3)).
+				Return(domain.Profile{ID: 3, Name: "JonnDoe", Email: "jonn.doe@tracker.local"}, ts.profileErr)
+			repoMock.On("ChangePassword", mock.Anything, uint(3), uint(7), ts.req.OldPassword, mock.Anything).Return(ts.mockErr)
+
+			events := new(security.EventMock)
+			server := CreatePasswordServer(repoMock, repoMock, new(MailerMock), events, Options{}, logrus.New())
+			err := server.ChangePassword(context.Background(), 3, 7, ts.req)
+
+			if ts.wantErr != nil {
+				assertErr(t, ts.wantErr, err)
+				assert.Empty(t, events.Events)
+			} else {
+				assert.NoError(t, err)
+				hash := repoMock.Calls[1].Arguments.Get(4).([]byte)
+				assert.NoError(t, hashPassword.Compare(hash, ts.req.NewPassword))
+				assert.Equal(t, []domain.SecurityEvent{{UserID: 3, Type: domain.EventPasswordChange, Success: true, Device: ts.req.Device}}, events.Events)
+			}
+
+			if ts.shouldCallDB {
+				repoMock.AssertCalled(t, "ChangePassword", mock.Anything, uint(3), uint(7), ts.req.OldPassword, mock.Anything)
+			} else {
+				repoMock.AssertNotCalled(t, "ChangePassword", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestForgotPassword(t *testing.T) {
+	user := domain.User{Name: "jonn", Email: "jonn@gmail.com"}
+
+	t.Run("success", func(t *testing.T) {
+		var out bytes.Buffer
+		repoMock := new(DbMock)
+		repoMock.On("CreatePasswordReset", mock.Anything, user.Email, mock.Anything, mock.Anything).Return(user, nil)
+
+		opt := Options{ResetTTL: 30 * time.Minute, ResetURL: "https://tracker.local/reset"}
+		server := CreatePasswordServer(repoMock, repoMock, mail.NewFileMailer(&out, "noreply@tracker.local"), new(security.EventMock), opt, logrus.New())
+
+		start := time.Now()
+		err := server.ForgotPassword(context.Background(), domain.ForgotPassword{Email: user.Email})
+		assert.NoError(t, err)
+
+		token := regexp.MustCompile(`reset\?token=([A-Za-z0-9_-]+)`).FindStringSubmatch(out.String())
+		if assert.Len(t, token, 2) {
+			assert.Equal(t, mailToken.Hash(token[1]), repoMock.Calls[0].Arguments.Get(2))
+		}
+
+		expiresAt := repoMock.Calls[0].Arguments.Get(3).(time.Time)
+		assert.WithinDuration(t, start.Add(30*time.Minute), expiresAt, time.Second)
+	})
+
+	t.Run("unknown email", func(t *testing.T) {
+		repoMock := new(DbMock)
+		repoMock.On("CreatePasswordReset", mock.Anything, "nobody@gmail.com", mock.Anything, mock.Anything).Return(domain.User{}, postgresql.ErrorNotFound)
+		mailer := new(MailerMock)
+
+		server := CreatePasswordServer(repoMock, repoMock, mailer, new(security.EventMock), Options{}, logrus.New())
+		err := server.ForgotPassword(context.Background(), domain.ForgotPassword{Email: "nobody@gmail.com"})
+
+		assert.NoError(t, err)
+		mailer.AssertNotCalled(t, "Send", mock.Anything, mock.Anything)
+	})
+
+	t.Run("error mail not reported", func(t *testing.T) {
+		repoMock := new(DbMock)
+		repoMock.On("CreatePasswordReset", mock.Anything, user.Email, mock.Anything, mock.Anything).Return(user, nil)
+		mailer := new(MailerMock)
+		mailer.On("Send", mock.Anything, mock.Anything).Return(errors.New("connection refused"))
+
+		server := CreatePasswordServer(repoMock, repoMock, mailer, new(security.EventMock), Options{}, logrus.New())
+		err := server.ForgotPassword(context.Background(), domain.ForgotPassword{Email: user.Email})
+
+		assert.NoError(t, err)
+		mailer.AssertExpectations(t)
+	})
+
+	t.Run("error validate", func(t *testing.T) {
+		repoMock := new(DbMock)
+
+		server := CreatePasswordServer(repoMock, repoMock, new(MailerMock), new(security.EventMock), Options{}, logrus.New())
+		err := server.ForgotPassword(context.Background(), domain.ForgotPassword{Email: "jonn"})
+
+		assertErr(t, validator.ValidationErrors{}, err)
+		repoMock.AssertNotCalled(t, "CreatePasswordReset", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestResetPassword(t *testing.T) {
+	type test struct {
+		name         string
+		req          domain.ResetPassword
+		userErr      error
+		mockErr      error
+		wantErr      error
+		shouldCallDB bool
+	}
+
+	tests := []test{
+		{
+			name:         "success",
+			req:          domain.ResetPassword{Token: "token", NewPassword: "gpDIJGP:OGhiHG"},
+			shouldCallDB: true,
+		},
+		{
+			name:         "error used or expired token",
+			req:          domain.ResetPassword{Token: "token", NewPassword: "gpDIJGP:OGhiHG"},
+			mockErr:      postgresql.ErrorNotFound,
+			wantErr:      ErrResetToken,
+			shouldCallDB: true,
+		},
+		{
+			name:         "error validate",
+			req:          domain.ResetPassword{Token: "token", NewPassword: "123"},
+			wantErr:      validator.ValidationErrors{},
+			shouldCallDB: false,
+		},
+		{
+			name:         "error password with name",
+			req:          domain.ResetPassword{Token: "token", NewPassword: "xxJonnDoe2024"},
+			wantErr:      validator.ValidationErrors{},
+			shouldCallDB: false,
+		},
+		{
+			name:         "error password with email",
+			req:          domain.ResetPassword{Token: "token", NewPassword: "jonn.doe-2024"},
+			wantErr:      validator.ValidationErrors{},
+			shouldCallDB: false,
+		},
+		{
+			name:         "error unknown token",
+			req:          domain.ResetPassword{Token: "token", NewPassword: "gpDIJGP:OGhiHG"},
+			userErr:      postgresql.ErrorNotFound,
+			wantErr:      ErrResetToken,
+			shouldCallDB: false,
+		},
+		{
+			name:         "error database",
+			req:          domain.ResetPassword{Token: "token", NewPassword: "gpDIJGP:OGhiHG"},
+			mockErr:      errors.New("some db error"),
+			wantErr:      ErrDatabase,
+			shouldCallDB: true,
+		},
+	}
+
+	for _, ts := range tests {
+		t.Run(ts.name, func(t *testing.T) {
+			repoMock := new(DbMock)
+			repoMock.On("PasswordResetUser", mock.Anything, mailToken.Hash("token")).
+				Return(domain.User{Name: "JonnDoe", Email: "jonn.doe@tracker.local"}, ts.userErr)
+			repoMock.On("ResetPassword", mock.Anything, mailToken.Hash("token"), mock.Anything).Return(uint(3), ts.mockErr)
+
+			events := new(security.EventMock)
+			server := CreatePasswordServer(repoMock, repoMock, new(MailerMock), events, Options{}, logrus.New())
+			err := server.ResetPassword(context.Background(), ts.req)
+
+			if ts.wantErr != nil {
+				assertErr(t, ts.wantErr, err)
+				assert.Empty(t, events.Events)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, []domain.SecurityEvent{{UserID: 3, Type: domain.EventPasswordReset, Success: true, Device: ts.req.Device}}, events.Events)
+			}
+
+			if ts.shouldCallDB {
+				repoMock.AssertCalled(t, "ResetPassword", mock.Anything, mailToken.Hash("token"), mock.Anything)
+			} else {
+				repoMock.AssertNotCalled(t, "ResetPassword", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
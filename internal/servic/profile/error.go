@@ -0,0 +1,31 @@
+package profile
+
+import (
+	"errors"
+
+	"github.com/financial_tracer/internal/infastructure/db/postgresql"
+)
+
+var (
+	ErrDatabase         = errors.New("error database")
+	ErrNoFound          = errors.New("user is not found")
+	ErrPassword         = errors.New("wrong password")
+	ErrDuplicated       = errors.New("the email has already been registered")
+	ErrPasswordRequired = errors.New("password is required to change the email")
+	ErrNothingToUpdate  = errors.New("nothing to update")
+)
+
+func RegisterErrDatabase(err error) error {
+	arr := map[error]error{
+		postgresql.ErrorNotFound:   ErrNoFound,
+		postgresql.ErrorPassword:   ErrPassword,
+		postgresql.ErrorDuplicated: ErrDuplicated,
+	}
+
+	value, ok := arr[err]
+	if !ok {
+		return ErrDatabase
+	}
+
+	return value
+}
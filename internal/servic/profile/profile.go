@@ -0,0 +1,177 @@
+package profile
+
+import (
+	"context"
+	"strings"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+)
+
+type ProfileRepository interface {
+	UserProfile(ctx context.Context, userID uint) (domain.Profile, error)
+	UpdateName(ctx context.Context, userID uint, name string) error
+	ChangeEmail(ctx context.Context, userID uint, password string, email string) error
+}
+
+type PreferencesRepository interface {
+	UserPreferences(ctx context.Context, userID uint) (domain.Preferences, error)
+	UpdatePreferences(ctx context.Context, userID uint, pref domain.Preferences) error
+}
+
+type VerificationSender interface {
+	SendVerification(ctx context.Context, userID uint) error
+}
+
+type ProfileServer struct {
+	p        ProfileRepository
+	pr       PreferencesRepository
+	v        VerificationSender
+	log      *logrus.Logger
+	validate validator.Validate
+}
+
+func CreateProfileServer(p ProfileRepository, pr PreferencesRepository, v VerificationSender, log *logrus.Logger) *ProfileServer {
+	return &ProfileServer{
+		p:        p,
+		pr:       pr,
+		v:        v,
+		log:      log,
+		validate: *validator.New(),
+	}
+}
+
+func (ps *ProfileServer) Profile(ctx context.Context, userID uint) (domain.Profile, error) {
+	const op = "profile.Profile"
+
+	log := ps.log.WithFields(logrus.Fields{
+		"op":      op,
+		"user_id": userID,
+	})
+
+	log.Info("start get profile")
+
+	profile, err := ps.p.UserProfile(ctx, userID)
+	if err != nil {
+		log.Error("error get profile: ", err)
+		return domain.Profile{}, RegisterErrDatabase(err)
+	}
+
+	log.Info("success get profile")
+
+	return profile, nil
+}
+
+// UpdateProfile changes the email only with the right password, the new email is unverified.
+func (ps *ProfileServer) UpdateProfile(ctx context.Context, userID uint, req domain.UpdateProfile) (domain.Profile, error) {
+	const op = "profile.UpdateProfile"
+
+	log := ps.log.WithFields(logrus.Fields{
+		"op":      op,
+		"user_id": userID,
+	})
+
+	log.Info("start update profile")
+
+	if err := ps.validate.Struct(req); err != nil {
+		log.WithField("err", err).Error("invalid validate")
+		return domain.Profile{}, err
+	}
+
+	if req.Name == "" && req.Email == "" {
+		log.Error("nothing to update")
+		return domain.Profile{}, ErrNothingToUpdate
+	}
+
+	profile, err := ps.p.UserProfile(ctx, userID)
+	if err != nil {
+		log.Error("error get profile: ", err)
+		return domain.Profile{}, RegisterErrDatabase(err)
+	}
+
+	// the email goes first, a wrong password must not leave the name changed
+	if req.Email != "" && !strings.EqualFold(req.Email, profile.Email) {
+		if req.Password == "" {
+			log.Error("password is required")
+			return domain.Profile{}, ErrPasswordRequired
+		}
+
+		if err := ps.p.ChangeEmail(ctx, userID, req.Password, req.Email); err != nil {
+			log.Error("error change email: ", err)
+			return domain.Profile{}, RegisterErrDatabase(err)
+		}
+
+		log.Info("email changed")
+
+		if err := ps.v.SendVerification(ctx, userID); err != nil {
+			log.WithField("err", err).Warn("field send verification")
+		}
+	}
+
+	if req.Name != "" && req.Name != profile.Name {
+		if err := ps.p.UpdateName(ctx, userID, req.Name); err != nil {
+			log.Error("error update name: ", err)
+			return domain.Profile{}, RegisterErrDatabase(err)
+		}
+	}
+
+	profile, err = ps.p.UserProfile(ctx, userID)
+	if err != nil {
+		log.Error("error get profile: ", err)
+		return domain.Profile{}, RegisterErrDatabase(err)
+	}
+
+	log.Info("success update profile")
+
+	return profile, nil
+}
+
+func (ps *ProfileServer) Preferences(ctx context.Context, userID uint) (domain.Preferences, error) {
+	const op = "profile.Preferences"
+
+	log := ps.log.WithFields(logrus.Fields{
+		"op":      op,
+		"user_id": userID,
+	})
+
+	log.Info("start get preferences")
+
+	pref, err := ps.pr.UserPreferences(ctx, userID)
+	if err != nil {
+		log.Error("error get preferences: ", err)
+		return domain.Preferences{}, RegisterErrDatabase(err)
+	}
+
+	log.Info("success get preferences")
+
+	return pref, nil
+}
+
+func (ps *ProfileServer) UpdatePreferences(ctx context.Context, userID uint, pref domain.Preferences) (domain.Preferences, error) {
+	const op = "profile.UpdatePreferences"
+
+	log := ps.log.WithFields(logrus.Fields{
+		"op":      op,
+		"user_id": userID,
+	})
+
+	log.Info("start update preferences")
+
+	pref.Currency = strings.ToUpper(pref.Currency)
+	pref.Locale = strings.ToLower(pref.Locale)
+
+	if err := ps.validate.Struct(pref); err != nil {
+		log.WithField("err", err).Error("invalid validate")
+		return domain.Preferences{}, err
+	}
+
+	if err := ps.pr.UpdatePreferences(ctx, userID, pref); err != nil {
+		log.Error("error update preferences: ", err)
+		return domain.Preferences{}, RegisterErrDatabase(err)
+	}
+
+	log.Info("success update preferences")
+
+	return pref, nil
+}
@@ -0,0 +1,46 @@
+package profile
+
+import (
+	"context"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type DbMock struct {
+	mock.Mock
+}
+
+func (d *DbMock) UserProfile(ctx context.Context, userID uint) (domain.Profile, error) {
+	args := d.Called(ctx, userID)
+	return args.Get(0).(domain.Profile), args.Error(1)
+}
+
+func (d *DbMock) UpdateName(ctx context.Context, userID uint, name string) error {
+	args := d.Called(ctx, userID, name)
+	return args.Error(0)
+}
+
+func (d *DbMock) ChangeEmail(ctx context.Context, userID uint, password string, email string) error {
+	args := d.Called(ctx, userID, password, email)
+	return args.Error(0)
+}
+
+func (d *DbMock) UserPreferences(ctx context.Context, userID uint) (domain.Preferences, error) {
+	args := d.Called(ctx, userID)
+	return args.Get(0).(domain.Preferences), args.Error(1)
+}
+
+func (d *DbMock) UpdatePreferences(ctx context.Context, userID uint, pref domain.Preferences) error {
+	args := d.Called(ctx, userID, pref)
+	return args.Error(0)
+}
+
+type VerificationMock struct {
+	mock.Mock
+}
+
+func (v *VerificationMock) SendVerification(ctx context.Context, userID uint) error {
+	args := v.Called(ctx, userID)
+	return args.Error(0)
+}
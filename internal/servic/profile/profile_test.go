@@ -0,0 +1,195 @@
+package profile
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/financial_tracer/internal/infastructure/db/postgresql"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestUpdateProfile(t *testing.T) {
+	current := domain.Profile{ID: 3, Name: "jonn", Email: "jonn@gmail.com", Verified: true}
+
+	tests := []struct {
+		name        string
+		req         domain.UpdateProfile
+		changeErr   error
+		wantErr     error
+		validateErr bool
+		changeEmail bool
+		updateName  bool
+	}{
+		{
+			name:       "success name",
+			req:        domain.UpdateProfile{Name: "jonny"},
+			updateName: true,
+		},
+		{
+			name:        "success email and name",
+			req:         domain.UpdateProfile{Name: "jonny", Email: "jonny@gmail.com", Password: "admin12241532"},
+			changeEmail: true,
+			updateName:  true,
+		},
+		{
+			name: "same email needs no password",
+			req:  domain.UpdateProfile{Email: "Jonn@gmail.com"},
+		},
+		{
+			name:    "error email without password",
+			req:     domain.UpdateProfile{Email: "jonny@gmail.com"},
+			wantErr: ErrPasswordRequired,
+		},
+		{
+			name:        "error wrong password keeps the name",
+			req:         domain.UpdateProfile{Name: "jonny", Email: "jonny@gmail.com", Password: "wrong"},
+			changeErr:   postgresql.ErrorPassword,
+			wantErr:     ErrPassword,
+			changeEmail: true,
+		},
+		{
+			name:        "error email taken",
+			req:         domain.UpdateProfile{Email: "sasha@gmail.com", Password: "admin12241532"},
+			changeErr:   postgresql.ErrorDuplicated,
+			wantErr:     ErrDuplicated,
+			changeEmail: true,
+		},
+		{
+			name:    "error nothing to update",
+			req:     domain.UpdateProfile{},
+			wantErr: ErrNothingToUpdate,
+		},
+		{
+			name:        "error validate",
+			req:         domain.UpdateProfile{Name: "jo", Email: "not email"},
+			validateErr: true,
+		},
+	}
+
+	for _, ts := range tests {
+		t.Run(ts.name, func(t *testing.T) {
+			repoMock := new(DbMock)
+			repoMock.On("UserProfile", mock.Anything, uint(3)).Return(current, nil)
+			repoMock.On("ChangeEmail", mock.Anything, uint(3), ts.req.Password, ts.req.Email).Return(ts.changeErr)
+			repoMock.On("UpdateName", mock.Anything, uint(3), ts.req.Name).Return(nil)
+			verify := new(VerificationMock)
+			verify.On("SendVerification", mock.Anything, uint(3)).Return(errors.New("smtp down"))
+
+			server := CreateProfileServer(repoMock, repoMock, verify, logrus.New())
+			_, err := server.UpdateProfile(context.Background(), 3, ts.req)
+
+			switch {
+			case ts.validateErr:
+				var validErr validator.ValidationErrors
+				assert.ErrorAs(t, err, &validErr)
+			case ts.wantErr != nil:
+				assert.ErrorIs(t, err, ts.wantErr)
+			default:
+				assert.NoError(t, err)
+			}
+
+			if ts.changeEmail {
+				repoMock.AssertCalled(t, "ChangeEmail", mock.Anything, uint(3), ts.req.Password, ts.req.Email)
+			} else {
+				repoMock.AssertNotCalled(t, "ChangeEmail", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+			if ts.changeEmail && ts.changeErr == nil {
+				verify.AssertCalled(t, "SendVerification", mock.Anything, uint(3))
+			} else {
+				verify.AssertNotCalled(t, "SendVerification", mock.Anything, mock.Anything)
+			}
+			if ts.updateName {
+				repoMock.AssertCalled(t, "UpdateName", mock.Anything, uint(3), ts.req.Name)
+			} else {
+				repoMock.AssertNotCalled(t, "UpdateName", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestUpdatePreferences(t *testing.T) {
+	tests := []struct {
+		name         string
+		pref         domain.Preferences
+		want         domain.Preferences
+		mokuErr      error
+		wantErr      error
+		shouldCallDB bool
+	}{
+		{
+			name:         "success",
+			pref:         domain.Preferences{Currency: "eur", Timezone: "Europe/Berlin", Locale: "RU", WeekStart: 0, MonthStart: 25},
+			want:         domain.Preferences{Currency: "EUR", Timezone: "Europe/Berlin", Locale: "ru", WeekStart: 0, MonthStart: 25},
+			shouldCallDB: true,
+		},
+		{
+			name: "error currency",
+			pref: domain.Preferences{Currency: "XXY", Timezone: "UTC", Locale: "en", MonthStart: 1},
+		},
+		{
+			name: "error timezone",
+			pref: domain.Preferences{Currency: "RUB", Timezone: "Mars/Olympus", Locale: "en", MonthStart: 1},
+		},
+		{
+			name: "error locale",
+			pref: domain.Preferences{Currency: "RUB", Timezone: "UTC", Locale: "de", MonthStart: 1},
+		},
+		{
+			name: "error week start",
+			pref: domain.Preferences{Currency: "RUB", Timezone: "UTC", Locale: "en", WeekStart: 7, MonthStart: 1},
+		},
+		{
+			name: "error month start",
+			pref: domain.Preferences{Currency: "RUB", Timezone: "UTC", Locale: "en", MonthStart: 31},
+		},
+		{
+			name:         "error database",
+			pref:         domain.Preferences{Currency: "RUB", Timezone: "UTC", Locale: "en", MonthStart: 1},
+			want:         domain.Preferences{Currency: "RUB", Timezone: "UTC", Locale: "en", MonthStart: 1},
+			mokuErr:      errors.New("some db error"),
+			wantErr:      ErrDatabase,
+			shouldCallDB: true,
+		},
+	}
+
+	for _, ts := range tests {
+		t.Run(ts.name, func(t *testing.T) {
+			repoMock := new(DbMock)
+			repoMock.On("UpdatePreferences", mock.Anything, uint(3), ts.want).Return(ts.mokuErr)
+
+			server := CreateProfileServer(repoMock, repoMock, new(VerificationMock), logrus.New())
+			pref, err := server.UpdatePreferences(context.Background(), 3, ts.pref)
+
+			switch {
+			case !ts.shouldCallDB:
+				var validErr validator.ValidationErrors
+				assert.ErrorAs(t, err, &validErr)
+				repoMock.AssertNotCalled(t, "UpdatePreferences", mock.Anything, mock.Anything, mock.Anything)
+			case ts.wantErr != nil:
+				assert.ErrorIs(t, err, ts.wantErr)
+			default:
+				assert.NoError(t, err)
+				assert.Equal(t, ts.want, pref)
+			}
+		})
+	}
+}
+
+func TestProfile(t *testing.T) {
+	repoMock := new(DbMock)
+	repoMock.On("UserProfile", mock.Anything, uint(3)).Return(domain.Profile{}, postgresql.ErrorNotFound)
+	repoMock.On("UserPreferences", mock.Anything, uint(3)).Return(domain.Preferences{}, errors.New("some db error"))
+
+	server := CreateProfileServer(repoMock, repoMock, new(VerificationMock), logrus.New())
+
+	_, err := server.Profile(context.Background(), 3)
+	assert.ErrorIs(t, err, ErrNoFound)
+
+	_, err = server.Preferences(context.Background(), 3)
+	assert.ErrorIs(t, err, ErrDatabase)
+}
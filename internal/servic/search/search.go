@@ -0,0 +1,60 @@
+package search
+
+import (
+	"context"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+)
+
+const DefaultLimit = 20
+
+type SearchTransactionsRepository interface {
+	SearchTransactions(ctx context.Context, m domain.Member, query domain.SearchQuery) ([]domain.TransactionSearchResult, error)
+}
+
+type SearchServer struct {
+	s        SearchTransactionsRepository
+	log      *logrus.Logger
+	validate validator.Validate
+}
+
+func CreateSearchServer(s SearchTransactionsRepository, log *logrus.Logger) *SearchServer {
+	return &SearchServer{
+		s:        s,
+		log:      log,
+		validate: *validator.New(),
+	}
+}
+
+func (ss *SearchServer) SearchTransactions(ctx context.Context, m domain.Member, query domain.SearchQuery) ([]domain.TransactionSearchResult, error) {
+	const op = "search.SearchTransactions"
+
+	log := ss.log.WithFields(logrus.Fields{
+		"op":           op,
+		"user_id":      m.UserID,
+		"household_id": m.HouseholdID,
+	})
+
+	log.Info("start search transactions")
+
+	if query.Limit == 0 {
+		query.Limit = DefaultLimit
+	}
+
+	if err := ss.validate.Struct(query); err != nil {
+		log.WithField("err", err).Error("invalid validate")
+		return nil, err
+	}
+
+	result, err := ss.s.SearchTransactions(ctx, m, query)
+	if err != nil {
+		log.Error("error search transactions: ", err)
+		return nil, RegisterErrDatabase(err)
+	}
+
+	log.WithField("found", len(result)).Info("success search transactions")
+
+	return result, nil
+}
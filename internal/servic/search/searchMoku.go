@@ -0,0 +1,17 @@
+package search
+
+import (
+	"context"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type DbMock struct {
+	mock.Mock
+}
+
+func (d *DbMock) SearchTransactions(ctx context.Context, m domain.Member, query domain.SearchQuery) ([]domain.TransactionSearchResult, error) {
+	args := d.Called(ctx, m, query)
+	return args.Get(0).([]domain.TransactionSearchResult), args.Error(1)
+}
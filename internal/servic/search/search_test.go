@@ -0,0 +1,102 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var member = domain.Member{UserID: 1, HouseholdID: 3, Role: domain.HouseholdViewer}
+
+func TestSearchTransactions(t *testing.T) {
+	type test struct {
+		name         string
+		query        domain.SearchQuery
+		repoQuery    domain.SearchQuery
+		result       []domain.TransactionSearchResult
+		repoErr      error
+		searchErr    error
+		shouldCallDB bool
+	}
+
+	found := []domain.TransactionSearchResult{
+		{
+			TransactionRecord: domain.TransactionRecord{ID: 3, TransactionOutput: domain.TransactionOutput{Name: "стоматолог", Count: 5000}},
+			Rank:              0.6,
+			NameHighlight:     "<b>стоматолог</b>",
+		},
+	}
+
+	arrTests := []test{
+		{
+			name:         "success default limit",
+			query:        domain.SearchQuery{Query: "стоматолог"},
+			repoQuery:    domain.SearchQuery{Query: "стоматолог", Limit: DefaultLimit},
+			result:       found,
+			shouldCallDB: true,
+		},
+		{
+			name:         "success page",
+			query:        domain.SearchQuery{Query: "dentist", Limit: 5, Offset: 10},
+			repoQuery:    domain.SearchQuery{Query: "dentist", Limit: 5, Offset: 10},
+			result:       []domain.TransactionSearchResult{},
+			shouldCallDB: true,
+		},
+		{
+			name:         "error validate empty query",
+			query:        domain.SearchQuery{},
+			searchErr:    validator.ValidationErrors{},
+			shouldCallDB: false,
+		},
+		{
+			name:         "error validate limit",
+			query:        domain.SearchQuery{Query: "dentist", Limit: 1000},
+			searchErr:    validator.ValidationErrors{},
+			shouldCallDB: false,
+		},
+		{
+			name:         "error database",
+			query:        domain.SearchQuery{Query: "dentist", Limit: 5},
+			repoQuery:    domain.SearchQuery{Query: "dentist", Limit: 5},
+			result:       []domain.TransactionSearchResult{},
+			repoErr:      errors.New("some db error"),
+			searchErr:    ErrDatabase,
+			shouldCallDB: true,
+		},
+	}
+
+	for _, ts := range arrTests {
+		t.Run(ts.name, func(t *testing.T) {
+			repoMock := new(DbMock)
+			repoMock.On("SearchTransactions", mock.Anything, member, ts.repoQuery).Return(ts.result, ts.repoErr)
+
+			server := CreateSearchServer(repoMock, logrus.New())
+			res, err := server.SearchTransactions(context.Background(), member, ts.query)
+
+			if ts.searchErr != nil {
+				assert.Error(t, err)
+				var validErr validator.ValidationErrors
+				if errors.As(ts.searchErr, &validErr) {
+					assert.True(t, errors.As(err, &validErr))
+				} else {
+					assert.ErrorIs(t, err, ts.searchErr)
+				}
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, ts.result, res)
+			}
+
+			if ts.shouldCallDB {
+				repoMock.AssertExpectations(t)
+			} else {
+				repoMock.AssertNotCalled(t, "SearchTransactions", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
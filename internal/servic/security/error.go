@@ -0,0 +1,7 @@
+package security
+
+import "errors"
+
+var (
+	ErrDatabase = errors.New("error database")
+)
@@ -0,0 +1,157 @@
+package security
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+)
+
+const DefaultLimit = 20
+
+type EventRepository interface {
+	CreateSecurityEvent(ctx context.Context, event domain.SecurityEvent) (domain.SecurityEvent, error)
+	SecurityEvents(ctx context.Context, filter domain.SecurityEventFilter) (domain.SecurityEventList, error)
+}
+
+type ProfileRepository interface {
+	UserProfile(ctx context.Context, userID uint) (domain.Profile, error)
+}
+
+type Mailer interface {
+	Send(ctx context.Context, msg domain.Mail) error
+}
+
+type SecurityServer struct {
+	r        EventRepository
+	p        ProfileRepository
+	m        Mailer
+	log      *logrus.Logger
+	validate validator.Validate
+	now      func() time.Time
+}
+
+func CreateSecurityServer(r EventRepository, p ProfileRepository, m Mailer, log *logrus.Logger, now func() time.Time) *SecurityServer {
+	return &SecurityServer{
+		r:        r,
+		p:        p,
+		m:        m,
+		log:      log,
+		validate: *validator.New(),
+		now:      now,
+	}
+}
+
+// Record only logs the errors, the action the event is about is already done.
+func (ss *SecurityServer) Record(ctx context.Context, event domain.SecurityEvent) {
+	const op = "security.Record"
+
+	log := ss.log.WithFields(logrus.Fields{
+		"op":      op,
+		"user_id": event.UserID,
+		"type":    event.Type,
+		"success": event.Success,
+	})
+
+	event.CreatedAt = ss.now()
+
+	stored, err := ss.r.CreateSecurityEvent(ctx, event)
+	if err != nil {
+		log.WithField("err", err).Warn("error record security event")
+		return
+	}
+
+	if !stored.NewDevice {
+		return
+	}
+
+	profile, err := ss.p.UserProfile(ctx, stored.UserID)
+	if err != nil {
+		log.WithField("err", err).Warn("error get user for new login mail")
+		return
+	}
+
+	if err := ss.m.Send(ctx, newLoginMail(profile, stored)); err != nil {
+		log.WithField("err", err).Warn("error send new login mail")
+		return
+	}
+
+	log.Info("new login mail sent")
+}
+
+func (ss *SecurityServer) Events(ctx context.Context, userID uint, filter domain.SecurityEventFilter) (domain.SecurityEventList, error) {
+	const op = "security.Events"
+
+	log := ss.log.WithFields(logrus.Fields{
+		"op":      op,
+		"user_id": userID,
+	})
+
+	log.Info("start get security events")
+
+	filter.UserID = userID
+
+	list, err := ss.events(ctx, filter)
+	if err != nil {
+		log.WithField("err", err).Error("error get security events")
+		return domain.SecurityEventList{}, err
+	}
+
+	log.WithField("total", list.Total).Info("success get security events")
+
+	return list, nil
+}
+
+func (ss *SecurityServer) AllEvents(ctx context.Context, filter domain.SecurityEventFilter) (domain.SecurityEventList, error) {
+	const op = "security.AllEvents"
+
+	log := ss.log.WithFields(logrus.Fields{
+		"op":      op,
+		"user_id": filter.UserID,
+	})
+
+	log.Info("start get all security events")
+
+	list, err := ss.events(ctx, filter)
+	if err != nil {
+		log.WithField("err", err).Error("error get security events")
+		return domain.SecurityEventList{}, err
+	}
+
+	log.WithField("total", list.Total).Info("success get all security events")
+
+	return list, nil
+}
+
+func (ss *SecurityServer) events(ctx context.Context, filter domain.SecurityEventFilter) (domain.SecurityEventList, error) {
+	if filter.Limit == 0 {
+		filter.Limit = DefaultLimit
+	}
+
+	if err := ss.validate.Struct(filter); err != nil {
+		return domain.SecurityEventList{}, err
+	}
+
+	list, err := ss.r.SecurityEvents(ctx, filter)
+	if err != nil {
+		ss.log.WithField("err", err).Error("error database")
+		return domain.SecurityEventList{}, ErrDatabase
+	}
+
+	return list, nil
+}
+
+func newLoginMail(profile domain.Profile, event domain.SecurityEvent) domain.Mail {
+	return domain.Mail{
+		To:      profile.Email,
+		Subject: "Новый вход в financial_tracer",
+		Body: fmt.Sprintf("Здравствуйте, %s!\n\n"+
+			"В ваш аккаунт выполнен вход с нового устройства:\n"+
+			"%s, IP %s, %s.\n\n"+
+			"Если это были не вы, смените пароль и завершите чужие сессии в настройках аккаунта.\n",
+			profile.Name, event.UserAgent, event.IP, event.CreatedAt.UTC().Format("02.01.2006 15:04 UTC")),
+	}
+}
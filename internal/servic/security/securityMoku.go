@@ -0,0 +1,44 @@
+package security
+
+import (
+	"context"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type DbMock struct {
+	mock.Mock
+}
+
+func (d *DbMock) CreateSecurityEvent(ctx context.Context, event domain.SecurityEvent) (domain.SecurityEvent, error) {
+	args := d.Called(ctx, event)
+	return args.Get(0).(domain.SecurityEvent), args.Error(1)
+}
+
+func (d *DbMock) SecurityEvents(ctx context.Context, filter domain.SecurityEventFilter) (domain.SecurityEventList, error) {
+	args := d.Called(ctx, filter)
+	return args.Get(0).(domain.SecurityEventList), args.Error(1)
+}
+
+func (d *DbMock) UserProfile(ctx context.Context, userID uint) (domain.Profile, error) {
+	args := d.Called(ctx, userID)
+	return args.Get(0).(domain.Profile), args.Error(1)
+}
+
+type MailerMock struct {
+	mock.Mock
+}
+
+func (m *MailerMock) Send(ctx context.Context, msg domain.Mail) error {
+	args := m.Called(ctx, msg)
+	return args.Error(0)
+}
+
+type EventMock struct {
+	Events []domain.SecurityEvent
+}
+
+func (e *EventMock) Record(ctx context.Context, event domain.SecurityEvent) {
+	e.Events = append(e.Events, event)
+}
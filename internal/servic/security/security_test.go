@@ -0,0 +1,162 @@
+package security
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var now = time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+
+func server(repo *DbMock, mailer *MailerMock) *SecurityServer {
+	return CreateSecurityServer(repo, repo, mailer, logrus.New(), func() time.Time { return now })
+}
+
+func TestRecord(t *testing.T) {
+	device := domain.Device{UserAgent: "okhttp/4.12", IP: "10.0.0.7"}
+
+	tests := []struct {
+		name       string
+		stored     domain.SecurityEvent
+		repoErr    error
+		profileErr error
+		mailErr    error
+		shouldMail bool
+	}{
+		{name: "known device", stored: domain.SecurityEvent{UserID: 1, Type: domain.EventLogin, Success: true}},
+		{name: "new device", stored: domain.SecurityEvent{UserID: 1, Type: domain.EventLogin, Success: true, NewDevice: true, Device: device, CreatedAt: now}, shouldMail: true},
+		{name: "error mail", stored: domain.SecurityEvent{UserID: 1, NewDevice: true}, mailErr: errors.New("smtp down"), shouldMail: true},
+		{name: "error profile", stored: domain.SecurityEvent{UserID: 1, NewDevice: true}, profileErr: errors.New("database down")},
+		{name: "error database", repoErr: errors.New("database down")},
+	}
+
+	for _, ts := range tests {
+		t.Run(ts.name, func(t *testing.T) {
+			event := domain.SecurityEvent{UserID: 1, Type: domain.EventLogin, Success: true, Device: device}
+			want := event
+			want.CreatedAt = now
+
+			repoMock := new(DbMock)
+			repoMock.On("CreateSecurityEvent", mock.Anything, want).Return(ts.stored, ts.repoErr)
+			repoMock.On("UserProfile", mock.Anything, uint(1)).Return(domain.Profile{Name: "jonn", Email: "jonn@gmail.com"}, ts.profileErr)
+			mailerMock := new(MailerMock)
+			mailerMock.On("Send", mock.Anything, mock.Anything).Return(ts.mailErr)
+
+			server(repoMock, mailerMock).Record(context.Background(), event)
+
+			repoMock.AssertCalled(t, "CreateSecurityEvent", mock.Anything, want)
+			if !ts.shouldMail {
+				mailerMock.AssertNotCalled(t, "Send", mock.Anything, mock.Anything)
+				return
+			}
+			msg := mailerMock.Calls[0].Arguments.Get(1).(domain.Mail)
+			assert.Equal(t, "jonn@gmail.com", msg.To)
+			assert.Contains(t, msg.Body, ts.stored.UserAgent)
+			assert.Contains(t, msg.Body, ts.stored.IP)
+		})
+	}
+}
+
+func TestEvents(t *testing.T) {
+	success := false
+
+	tests := []struct {
+		name        string
+		filter      domain.SecurityEventFilter
+		want        domain.SecurityEventFilter
+		repoErr     error
+		wantErr     error
+		validateErr bool
+	}{
+		{
+			name:   "success default limit",
+			filter: domain.SecurityEventFilter{Type: domain.EventLogin, Success: &success},
+			want:   domain.SecurityEventFilter{UserID: 1, Type: domain.EventLogin, Success: &success, Limit: DefaultLimit},
+		},
+		{
+			name:   "only own events",
+			filter: domain.SecurityEventFilter{UserID: 2, Limit: 5},
+			want:   domain.SecurityEventFilter{UserID: 1, Limit: 5},
+		},
+		{
+			name:        "error type",
+			filter:      domain.SecurityEventFilter{Type: "logout"},
+			validateErr: true,
+		},
+		{
+			name:        "error limit",
+			filter:      domain.SecurityEventFilter{Limit: 1000},
+			validateErr: true,
+		},
+		{
+			name:    "error database",
+			want:    domain.SecurityEventFilter{UserID: 1, Limit: DefaultLimit},
+			repoErr: errors.New("database down"),
+			wantErr: ErrDatabase,
+		},
+	}
+
+	for _, ts := range tests {
+		t.Run(ts.name, func(t *testing.T) {
+			repoMock := new(DbMock)
+			repoMock.On("SecurityEvents", mock.Anything, ts.want).Return(domain.SecurityEventList{Total: 1}, ts.repoErr)
+
+			_, err := server(repoMock, new(MailerMock)).Events(context.Background(), 1, ts.filter)
+
+			if ts.validateErr {
+				var validErr validator.ValidationErrors
+				assert.ErrorAs(t, err, &validErr)
+				repoMock.AssertNotCalled(t, "SecurityEvents", mock.Anything, mock.Anything)
+				return
+			}
+			assert.ErrorIs(t, err, ts.wantErr)
+			repoMock.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAllEvents(t *testing.T) {
+	tests := []struct {
+		name    string
+		filter  domain.SecurityEventFilter
+		want    domain.SecurityEventFilter
+		repoErr error
+		wantErr error
+	}{
+		{
+			name:   "success all users",
+			filter: domain.SecurityEventFilter{Type: domain.EventRefresh},
+			want:   domain.SecurityEventFilter{Type: domain.EventRefresh, Limit: DefaultLimit},
+		},
+		{
+			name:   "success one user",
+			filter: domain.SecurityEventFilter{UserID: 7, Limit: 50},
+			want:   domain.SecurityEventFilter{UserID: 7, Limit: 50},
+		},
+		{
+			name:    "error database",
+			want:    domain.SecurityEventFilter{Limit: DefaultLimit},
+			repoErr: errors.New("database down"),
+			wantErr: ErrDatabase,
+		},
+	}
+
+	for _, ts := range tests {
+		t.Run(ts.name, func(t *testing.T) {
+			repoMock := new(DbMock)
+			repoMock.On("SecurityEvents", mock.Anything, ts.want).Return(domain.SecurityEventList{}, ts.repoErr)
+
+			_, err := server(repoMock, new(MailerMock)).AllEvents(context.Background(), ts.filter)
+
+			assert.ErrorIs(t, err, ts.wantErr)
+			repoMock.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package sso
+
+import (
+	"errors"
+
+	"github.com/financial_tracer/internal/infastructure/db/postgresql"
+)
+
+var (
+	ErrDatabase     = errors.New("error database")
+	ErrServic       = errors.New("servic error")
+	ErrProvider     = errors.New("unknown identity provider")
+	ErrState        = errors.New("invalid or expired login state")
+	ErrLogin        = errors.New("identity provider rejected the login")
+	ErrUnavailable  = errors.New("identity provider is unavailable")
+	ErrUnverified   = errors.New("email is not verified by the identity provider")
+	ErrDisabled     = errors.New("user is disabled")
+	ErrRegistration = errors.New("registration of new users is not allowed")
+	ErrUnlinked     = errors.New("email of the account is not verified")
+)
+
+func RegisterErrDatabase(err error) error {
+	arr := map[error]error{
+		postgresql.ErrorUnverified: ErrUnverified,
+		postgresql.ErrorDisabled:   ErrDisabled,
+		postgresql.ErrorNotFound:   ErrRegistration,
+		postgresql.ErrorUnlinked:   ErrUnlinked,
+	}
+
+	value, ok := arr[err]
+	if !ok {
+		return ErrDatabase
+	}
+
+	return value
+}
@@ -0,0 +1,218 @@
+package sso
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/financial_tracer/internal/infastructure/db/postgresql"
+	"github.com/financial_tracer/internal/lib/hashPassword"
+	jwttoken "github.com/financial_tracer/internal/lib/jwtToken"
+	"github.com/financial_tracer/internal/lib/mailToken"
+	"github.com/financial_tracer/internal/lib/oidc"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+)
+
+// StateTTL is how long the user has to log in at the identity provider.
+const StateTTL = 10 * time.Minute
+
+// Provider runs the authorization code flow with PKCE at an identity provider.
+type Provider interface {
+	AuthURL(ctx context.Context, state string, nonce string, challenge string) (string, error)
+	Exchange(ctx context.Context, code string, verifier string) (string, error)
+	Verify(ctx context.Context, raw string, nonce string) (oidc.Identity, error)
+}
+
+type StateRepository interface {
+	CreateOIDCState(ctx context.Context, stateHash string, state domain.OIDCState, expiresAt time.Time) error
+	ConsumeOIDCState(ctx context.Context, stateHash string, provider string, now time.Time) (domain.OIDCState, error)
+}
+
+type IdentityRepository interface {
+	OIDCUser(ctx context.Context, provider string, subject string) (uint, string, error)
+	LinkOIDCUser(ctx context.Context, identity domain.OIDCIdentity, passwordHash []byte, register bool) (uint, string, error)
+}
+
+// Login issues the tokens the same way as a password login.
+type Login interface {
+	Login(ctx context.Context, id uint, name string, device domain.Device) (jwttoken.ResponseJWTUser, error)
+}
+
+type SSOServer struct {
+	providers map[string]Provider
+	s         StateRepository
+	i         IdentityRepository
+	l         Login
+	policy    domain.RegistrationPolicy
+	log       *logrus.Logger
+	now       func() time.Time
+	validate  validator.Validate
+}
+
+func CreateSSOServer(providers map[string]Provider, s StateRepository, i IdentityRepository, l Login, policy domain.RegistrationPolicy, log *logrus.Logger, now func() time.Time) *SSOServer {
+	return &SSOServer{
+		providers: providers,
+		s:         s,
+		i:         i,
+		l:         l,
+		policy:    policy,
+		log:       log,
+		now:       now,
+		validate:  *validator.New(),
+	}
+}
+
+func (s *SSOServer) Providers() []string {
+	names := make([]string, 0, len(s.providers))
+	for name := range s.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Start keeps the state, the nonce and the PKCE verifier on the server.
+func (s *SSOServer) Start(ctx context.Context, provider string) (domain.OIDCStart, error) {
+	const op = "sso.Start"
+
+	log := s.log.WithFields(logrus.Fields{
+		"op":       op,
+		"provider": provider,
+	})
+
+	log.Info("start oidc login")
+
+	p, ok := s.providers[provider]
+	if !ok {
+		log.Error("unknown provider")
+		return domain.OIDCStart{}, ErrProvider
+	}
+
+	state, stateHash, err := mailToken.New()
+	if err != nil {
+		log.WithField("err", err).Error("field create state")
+		return domain.OIDCStart{}, ErrServic
+	}
+	nonce, err := oidc.NewNonce()
+	if err != nil {
+		log.WithField("err", err).Error("field create nonce")
+		return domain.OIDCStart{}, ErrServic
+	}
+	verifier, err := oidc.NewVerifier()
+	if err != nil {
+		log.WithField("err", err).Error("field create verifier")
+		return domain.OIDCStart{}, ErrServic
+	}
+
+	authURL, err := p.AuthURL(ctx, state, nonce, oidc.Challenge(verifier))
+	if err != nil {
+		log.WithField("err", err).Error("error provider discovery")
+		return domain.OIDCStart{}, ErrUnavailable
+	}
+
+	err = s.s.CreateOIDCState(ctx, stateHash, domain.OIDCState{Provider: provider, Nonce: nonce, Verifier: verifier}, s.now().Add(StateTTL))
+	if err != nil {
+		log.Error("error create state: ", err)
+		return domain.OIDCStart{}, ErrDatabase
+	}
+
+	log.Info("success start oidc login")
+
+	return domain.OIDCStart{URL: authURL}, nil
+}
+
+func (s *SSOServer) Callback(ctx context.Context, req domain.OIDCCallback) (jwttoken.ResponseJWTUser, error) {
+	const op = "sso.Callback"
+
+	log := s.log.WithFields(logrus.Fields{
+		"op":       op,
+		"provider": req.Provider,
+	})
+
+	log.Info("start oidc callback")
+
+	if err := s.validate.Struct(req); err != nil {
+		log.WithField("err", err).Error("invalid validate")
+		return jwttoken.ResponseJWTUser{}, err
+	}
+
+	p, ok := s.providers[req.Provider]
+	if !ok {
+		log.Error("unknown provider")
+		return jwttoken.ResponseJWTUser{}, ErrProvider
+	}
+
+	state, err := s.s.ConsumeOIDCState(ctx, mailToken.Hash(req.State), req.Provider, s.now())
+	if err != nil {
+		log.Error("error consume state: ", err)
+		if errors.Is(err, postgresql.ErrorNotFound) {
+			return jwttoken.ResponseJWTUser{}, ErrState
+		}
+		return jwttoken.ResponseJWTUser{}, ErrDatabase
+	}
+
+	raw, err := p.Exchange(ctx, req.Code, state.Verifier)
+	if err != nil {
+		log.WithField("err", err).Error("error exchange code")
+		return jwttoken.ResponseJWTUser{}, ErrLogin
+	}
+
+	identity, err := p.Verify(ctx, raw, state.Nonce)
+	if err != nil {
+		log.WithField("err", err).Error("error verify id token")
+		return jwttoken.ResponseJWTUser{}, ErrLogin
+	}
+
+	id, name, err := s.user(ctx, domain.OIDCIdentity{
+		Provider:      req.Provider,
+		Subject:       identity.Subject,
+		Email:         identity.Email,
+		EmailVerified: identity.EmailVerified,
+		Name:          identity.Name,
+	})
+	if err != nil {
+		log.WithField("err", err).Error("error oidc user")
+		return jwttoken.ResponseJWTUser{}, err
+	}
+
+	tokens, err := s.l.Login(ctx, id, name, req.Device)
+	if err != nil {
+		log.WithField("err", err).Error("error login")
+		return jwttoken.ResponseJWTUser{}, err
+	}
+
+	log.WithField("user_id", id).Info("success oidc login")
+
+	return tokens, nil
+}
+
+// user registers a new user only when the registration policy lets anyone sign up.
+func (s *SSOServer) user(ctx context.Context, identity domain.OIDCIdentity) (uint, string, error) {
+	id, name, err := s.i.OIDCUser(ctx, identity.Provider, identity.Subject)
+	if err == nil {
+		return id, name, nil
+	}
+	if !errors.Is(err, postgresql.ErrorNotFound) {
+		return 0, "", RegisterErrDatabase(err)
+	}
+
+	password, _, err := mailToken.New()
+	if err != nil {
+		return 0, "", ErrServic
+	}
+	passwordHash, err := hashPassword.Hash(password)
+	if err != nil {
+		return 0, "", ErrServic
+	}
+
+	id, name, err = s.i.LinkOIDCUser(ctx, identity, passwordHash, s.policy.Open(identity.Email))
+	if err != nil {
+		return 0, "", RegisterErrDatabase(err)
+	}
+
+	return id, name, nil
+}
@@ -0,0 +1,43 @@
+package sso
+
+import (
+	"context"
+	"time"
+
+	"github.com/financial_tracer/internal/domain"
+	jwttoken "github.com/financial_tracer/internal/lib/jwtToken"
+	"github.com/stretchr/testify/mock"
+)
+
+type DbMock struct {
+	mock.Mock
+}
+
+func (d *DbMock) CreateOIDCState(ctx context.Context, stateHash string, state domain.OIDCState, expiresAt time.Time) error {
+	args := d.Called(ctx, stateHash, state, expiresAt)
+	return args.Error(0)
+}
+
+func (d *DbMock) ConsumeOIDCState(ctx context.Context, stateHash string, provider string, now time.Time) (domain.OIDCState, error) {
+	args := d.Called(ctx, stateHash, provider, now)
+	return args.Get(0).(domain.OIDCState), args.Error(1)
+}
+
+func (d *DbMock) OIDCUser(ctx context.Context, provider string, subject string) (uint, string, error) {
+	args := d.Called(ctx, provider, subject)
+	return args.Get(0).(uint), args.String(1), args.Error(2)
+}
+
+func (d *DbMock) LinkOIDCUser(ctx context.Context, identity domain.OIDCIdentity, passwordHash []byte, register bool) (uint, string, error) {
+	args := d.Called(ctx, identity, passwordHash, register)
+	return args.Get(0).(uint), args.String(1), args.Error(2)
+}
+
+type LoginMock struct {
+	mock.Mock
+}
+
+func (l *LoginMock) Login(ctx context.Context, id uint, name string, device domain.Device) (jwttoken.ResponseJWTUser, error) {
+	args := l.Called(ctx, id, name, device)
+	return args.Get(0).(jwttoken.ResponseJWTUser), args.Error(1)
+}
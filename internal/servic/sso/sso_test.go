@@ -0,0 +1,142 @@
+package sso
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/financial_tracer/internal/infastructure/db/postgresql"
+	jwttoken "github.com/financial_tracer/internal/lib/jwtToken"
+	"github.com/financial_tracer/internal/lib/mailToken"
+	"github.com/financial_tracer/internal/lib/oidc"
+	"github.com/financial_tracer/internal/lib/oidc/oidctest"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	now    = time.Now()
+	device = domain.Device{UserAgent: "firefox", IP: "10.0.0.1"}
+	tokens = jwttoken.ResponseJWTUser{AccessToken: "access", RefreshToken: "refresh"}
+	anna   = oidctest.User{Subject: "42", Email: "anna@corp.com", EmailVerified: true, Name: "Anna"}
+)
+
+func clock() time.Time {
+	return now
+}
+
+func newIdP(t *testing.T) (*oidctest.IdP, map[string]Provider) {
+	idp, err := oidctest.New("tracker", "secret", anna)
+	require.NoError(t, err)
+	t.Cleanup(idp.Close)
+
+	return idp, map[string]Provider{"corp": oidc.NewProvider(idp.Config("http://localhost/callback"), nil, time.Now)}
+}
+
+func TestStart(t *testing.T) {
+	_, providers := newIdP(t)
+
+	repoMock := new(DbMock)
+	repoMock.On("CreateOIDCState", mock.Anything, mock.Anything, mock.Anything, now.Add(StateTTL)).Return(nil)
+
+	server := CreateSSOServer(providers, repoMock, repoMock, new(LoginMock), domain.RegistrationPolicy{}, logrus.New(), clock)
+
+	res, err := server.Start(context.Background(), "corp")
+	require.NoError(t, err)
+
+	u, err := url.Parse(res.URL)
+	require.NoError(t, err)
+	q := u.Query()
+
+	stored := repoMock.Calls[0].Arguments.Get(2).(domain.OIDCState)
+	assert.Equal(t, "corp", stored.Provider)
+	assert.Equal(t, mailToken.Hash(q.Get("state")), repoMock.Calls[0].Arguments.Get(1))
+	assert.Equal(t, stored.Nonce, q.Get("nonce"))
+	assert.Equal(t, oidc.Challenge(stored.Verifier), q.Get("code_challenge"))
+	assert.Equal(t, "S256", q.Get("code_challenge_method"))
+	assert.Empty(t, q.Get("code_verifier"))
+
+	_, err = server.Start(context.Background(), "other")
+	assert.ErrorIs(t, err, ErrProvider)
+}
+
+func TestCallback(t *testing.T) {
+	identity := domain.OIDCIdentity{Provider: "corp", Subject: "42", Email: "anna@corp.com", EmailVerified: true, Name: "Anna"}
+
+	tests := []struct {
+		name     string
+		provider string
+		stateErr error
+		nonce    string
+		userErr  error
+		linkErr  error
+		wantErr  error
+		linked   bool
+		policy   domain.RegistrationPolicy
+		register bool
+	}{
+		{name: "success linked user", provider: "corp", linked: true},
+		{name: "success link by email", provider: "corp", userErr: postgresql.ErrorNotFound, register: true},
+		{name: "success link by email invite mode", provider: "corp", userErr: postgresql.ErrorNotFound, policy: domain.RegistrationPolicy{Mode: domain.RegistrationInvite}},
+		{name: "error registration closed", provider: "corp", userErr: postgresql.ErrorNotFound, linkErr: postgresql.ErrorNotFound, policy: domain.RegistrationPolicy{Mode: domain.RegistrationClosed}, wantErr: ErrRegistration},
+		{name: "error email domain", provider: "corp", userErr: postgresql.ErrorNotFound, linkErr: postgresql.ErrorNotFound, policy: domain.RegistrationPolicy{Domains: []string{"tracker.local"}}, wantErr: ErrRegistration},
+		{name: "error unverified email", provider: "corp", userErr: postgresql.ErrorNotFound, linkErr: postgresql.ErrorUnverified, wantErr: ErrUnverified, register: true},
+		{name: "error disabled", provider: "corp", userErr: postgresql.ErrorDisabled, wantErr: ErrDisabled},
+		{name: "error link disabled", provider: "corp", userErr: postgresql.ErrorNotFound, linkErr: postgresql.ErrorDisabled, wantErr: ErrDisabled, register: true},
+		{name: "error link unverified account", provider: "corp", userErr: postgresql.ErrorNotFound, linkErr: postgresql.ErrorUnlinked, wantErr: ErrUnlinked, register: true},
+		{name: "error state", provider: "corp", stateErr: postgresql.ErrorNotFound, wantErr: ErrState},
+		{name: "error nonce", provider: "corp", nonce: "other", wantErr: ErrLogin},
+		{name: "error database", provider: "corp", userErr: errors.New("some db error"), wantErr: ErrDatabase},
+		{name: "error provider", provider: "other", wantErr: ErrProvider},
+	}
+
+	for _, ts := range tests {
+		t.Run(ts.name, func(t *testing.T) {
+			idp, providers := newIdP(t)
+			ctx := context.Background()
+
+			repoMock := new(DbMock)
+			repoMock.On("CreateOIDCState", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+			loginMock := new(LoginMock)
+			loginMock.On("Login", mock.Anything, uint(5), "Anna", device).Return(tokens, nil)
+
+			server := CreateSSOServer(providers, repoMock, repoMock, loginMock, ts.policy, logrus.New(), clock)
+
+			start, err := server.Start(ctx, "corp")
+			require.NoError(t, err)
+			code, state, err := idp.Authorize(start.URL)
+			require.NoError(t, err)
+
+			stored := repoMock.Calls[0].Arguments.Get(2).(domain.OIDCState)
+			if ts.nonce != "" {
+				stored.Nonce = ts.nonce
+			}
+			repoMock.On("ConsumeOIDCState", mock.Anything, mailToken.Hash(state), "corp", now).Return(stored, ts.stateErr)
+			repoMock.On("OIDCUser", mock.Anything, "corp", "42").Return(uint(5), "Anna", ts.userErr)
+			repoMock.On("LinkOIDCUser", mock.Anything, identity, mock.Anything, ts.register).Return(uint(5), "Anna", ts.linkErr)
+
+			res, err := server.Callback(ctx, domain.OIDCCallback{Provider: ts.provider, Code: code, State: state, Device: device})
+
+			if ts.wantErr != nil {
+				assert.ErrorIs(t, err, ts.wantErr)
+				loginMock.AssertNotCalled(t, "Login", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+				if ts.linkErr != nil {
+					repoMock.AssertCalled(t, "LinkOIDCUser", mock.Anything, identity, mock.Anything, ts.register)
+				}
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tokens, res)
+			if ts.linked {
+				repoMock.AssertNotCalled(t, "LinkOIDCUser", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			} else {
+				repoMock.AssertCalled(t, "LinkOIDCUser", mock.Anything, identity, mock.Anything, ts.register)
+			}
+		})
+	}
+}
@@ -7,15 +7,18 @@ import (
 )
 
 var (
-	ErrNoFound  = errors.New("transaction is not found")
-	ErrLimit    = errors.New("exceeded the limit")
-	ErrDatabase = errors.New("error database")
+	ErrNoFound       = errors.New("transaction is not found")
+	ErrLimit         = errors.New("exceeded the limit")
+	ErrDatabase      = errors.New("error database")
+	ErrAnomalyStatus = errors.New("invalid anomaly status")
+	ErrForbidden     = errors.New("no access to the household")
 )
 
 func RegisterErrDatabase(err error) error {
 	arr := map[error]error{
-		postgresql.ErrorNotFound: ErrNoFound,
-		postgresql.ErrorLimit:    ErrLimit,
+		postgresql.ErrorNotFound:  ErrNoFound,
+		postgresql.ErrorLimit:     ErrLimit,
+		postgresql.ErrorForbidden: ErrForbidden,
 	}
 
 	value, ok := arr[err]
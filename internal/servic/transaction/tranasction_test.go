@@ -15,6 +15,8 @@ import (
 	"github.com/stretchr/testify/mock"
 )
 
+var member = domain.Member{UserID: 1, HouseholdID: 3, Role: domain.HouseholdEditor}
+
 func TestCreateTransactionServic(t *testing.T) {
 	type test struct {
 		name          string
@@ -27,6 +29,8 @@ func TestCreateTransactionServic(t *testing.T) {
 		shouldCallDB  bool
 		shouldCache   bool
 		cacheErr      error
+		history       []int
+		shouldFlag    bool
 	}
 
 	arrTest := []test{
@@ -45,6 +49,22 @@ func TestCreateTransactionServic(t *testing.T) {
 			shouldCallDB:  true,
 			shouldCache:   true,
 			cacheErr:      nil,
+			history:       []int{900, 1200, 1000, 1100, 950},
+		},
+		{
+			name: "anomaly",
+			tran: domain.TransactionInput{
+				Name:        "кофе",
+				Count:       50000,
+				Description: "опечатка в сумме",
+			},
+			idUser:        123,
+			idCategory:    15312,
+			idTransaction: 2,
+			shouldCallDB:  true,
+			shouldCache:   true,
+			history:       []int{300, 250, 350, 280, 320, 310},
+			shouldFlag:    true,
 		},
 		{
 			name: "error not found",
@@ -113,14 +133,22 @@ func TestCreateTransactionServic(t *testing.T) {
 			repoMock := new(DbMock)
 			redisMock := new(cash.RedisMock)
 
+			m := domain.Member{UserID: test.idUser, HouseholdID: member.HouseholdID, Role: member.Role}
 			if test.shouldCallDB {
-				repoMock.On("CreateTransaction", mock.Anything, test.idUser, test.idCategory, test.tran).
-					Return(test.idTransaction, test.repoErr)
+				repoMock.On("CategoryCounts", mock.Anything, m, test.idCategory, anomalyHistory).Return(test.history, nil)
+				if test.shouldFlag {
+					repoMock.On("CreateFlaggedTransaction", mock.Anything, m, test.idCategory, test.tran, mock.AnythingOfType("string")).
+						Return(test.idTransaction, test.repoErr)
+				} else {
+					repoMock.On("CreateTransaction", mock.Anything, m, test.idCategory, test.tran).
+						Return(test.idTransaction, test.repoErr)
+				}
 			}
 			if test.shouldCache {
 				expectedTransaction := domain.TransactionOutput{
 					Name:        test.tran.Name,
 					UserID:      test.idUser,
+					HouseholdID: member.HouseholdID,
 					CategoryID:  test.idCategory,
 					Description: test.tran.Description,
 					Count:       test.tran.Count,
@@ -130,8 +158,8 @@ func TestCreateTransactionServic(t *testing.T) {
 			}
 			log := logrus.New()
 
-			server := CreateTransactionServer(repoMock, repoMock, repoMock, repoMock, log, redisMock)
-			id, err := server.CreateTransaction(context.Background(), test.idUser, test.idCategory, test.tran)
+			server := CreateTransactionServer(repoMock, repoMock, repoMock, repoMock, repoMock, log, redisMock)
+			id, err := server.CreateTransaction(context.Background(), m, test.idCategory, test.tran)
 
 			if test.repoErr != nil || test.tranErr != nil {
 				assert.Error(t, err)
@@ -159,6 +187,12 @@ func TestCreateTransactionServic(t *testing.T) {
 			} else {
 				redisMock.AssertNotCalled(t, "HsetTransaction", mock.Anything, mock.Anything, mock.Anything)
 			}
+
+			if test.shouldFlag {
+				repoMock.AssertNotCalled(t, "CreateTransaction", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			} else {
+				repoMock.AssertNotCalled(t, "CreateFlaggedTransaction", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
 		})
 	}
 }
@@ -180,6 +214,7 @@ func TestReadTransactionServer(t *testing.T) {
 			name: "success",
 			tran: domain.TransactionOutput{
 				UserID:      1,
+				HouseholdID: 3,
 				CategoryID:  2,
 				Name:        "траты на магазин",
 				Count:       10000,
@@ -192,12 +227,43 @@ func TestReadTransactionServer(t *testing.T) {
 				"name":        "траты на магазин",
 				"description": "походил с девушкой по магазинам",
 				"userID":      strconv.FormatUint(uint64(1), 10),
+				"householdID": strconv.FormatUint(uint64(3), 10),
 				"categoryID":  strconv.FormatUint(uint64(2), 10),
 				"count":       strconv.Itoa(10000),
 			},
 			redisErr:     nil,
 			shouldCallDB: false,
 		},
+		{
+			name: "cached for another household",
+			tran: domain.TransactionOutput{
+				UserID:      1,
+				HouseholdID: 3,
+				CategoryID:  2,
+				Name:        "траты на магазин",
+				Count:       10000,
+			},
+			idTransaction: 8,
+			redisPayload: map[string]string{
+				"name":        "аренда",
+				"userID":      strconv.FormatUint(uint64(2), 10),
+				"householdID": strconv.FormatUint(uint64(9), 10),
+				"categoryID":  strconv.FormatUint(uint64(5), 10),
+				"count":       strconv.Itoa(500),
+			},
+			redisErr:     nil,
+			shouldCallDB: true,
+		},
+		{
+			name:          "forbidden",
+			tran:          domain.TransactionOutput{},
+			idTransaction: 9,
+			tranErr:       postgresql.ErrorForbidden,
+			svcErr:        ErrForbidden,
+			redisPayload:  map[string]string{},
+			redisErr:      errors.New("cache miss"),
+			shouldCallDB:  true,
+		},
 		{
 			name: "error database",
 			tran: domain.TransactionOutput{
@@ -239,14 +305,14 @@ func TestReadTransactionServer(t *testing.T) {
 			redisMock := new(cash.RedisMock)
 
 			if ts.shouldCallDB {
-				repoMock.On("GetTransaction", mock.Anything, ts.idTransaction).Return(ts.tran, ts.tranErr)
+				repoMock.On("GetTransaction", mock.Anything, member, ts.idTransaction).Return(ts.tran, ts.tranErr)
 			}
 			redisMock.On("HgetTransaction", mock.Anything, ts.idTransaction).
 				Return(ts.redisPayload, ts.redisErr)
 			log := logrus.New()
 
-			server := CreateTransactionServer(repoMock, repoMock, repoMock, repoMock, log, redisMock)
-			tran, err := server.GetTransaction(context.Background(), ts.idTransaction)
+			server := CreateTransactionServer(repoMock, repoMock, repoMock, repoMock, repoMock, log, redisMock)
+			tran, err := server.GetTransaction(context.Background(), member, ts.idTransaction)
 			if ts.tranErr != nil || ts.svcErr != nil {
 				assert.Error(t, err)
 				if !errors.Is(err, ts.svcErr) {
@@ -262,7 +328,7 @@ func TestReadTransactionServer(t *testing.T) {
 			if ts.shouldCallDB {
 				repoMock.AssertExpectations(t)
 			} else {
-				repoMock.AssertNotCalled(t, "GetTransaction", mock.Anything, mock.AnythingOfType("uint"))
+				repoMock.AssertNotCalled(t, "GetTransaction", mock.Anything, member, mock.AnythingOfType("uint"))
 			}
 		})
 	}
@@ -340,15 +406,15 @@ func TestUpdateTransactionServer(t *testing.T) {
 			redisMock := new(cash.RedisMock)
 
 			if test.shouldCallDB {
-				repoMock.On("UpdateTransaction", mock.Anything, test.idTransaction, test.tranInput).Return(test.tranOutput, test.tranErr)
+				repoMock.On("UpdateTransaction", mock.Anything, member, test.idTransaction, test.tranInput).Return(test.tranOutput, test.tranErr)
 			}
 			if test.shouldCache {
 				redisMock.On("HsetTransaction", mock.Anything, test.idTransaction, test.tranOutput).Return(test.cacheErr)
 			}
 			log := logrus.New()
 
-			server := CreateTransactionServer(repoMock, repoMock, repoMock, repoMock, log, redisMock)
-			tranOutput, err := server.UpdateTransaction(context.Background(), test.idTransaction, test.tranInput)
+			server := CreateTransactionServer(repoMock, repoMock, repoMock, repoMock, repoMock, log, redisMock)
+			tranOutput, err := server.UpdateTransaction(context.Background(), member, test.idTransaction, test.tranInput)
 
 			if test.tranErr != nil || test.svcErr != nil {
 				assert.Error(t, err)
@@ -368,7 +434,7 @@ func TestUpdateTransactionServer(t *testing.T) {
 			if test.shouldCallDB {
 				repoMock.AssertExpectations(t)
 			} else {
-				repoMock.AssertNotCalled(t, "UpdateTransaction", mock.Anything, mock.Anything, mock.Anything)
+				repoMock.AssertNotCalled(t, "UpdateTransaction", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
 			}
 
 			if test.shouldCache {
@@ -421,14 +487,14 @@ func TestDeleteTransactionServer(t *testing.T) {
 			repoMock := new(DbMock)
 			redisMock := new(cash.RedisMock)
 
-			repoMock.On("DeleteTransaction", mock.Anything, ts.idTransaction).Return(ts.tranErr)
+			repoMock.On("DeleteTransaction", mock.Anything, member, ts.idTransaction).Return(ts.tranErr)
 			if ts.shouldCache {
 				redisMock.On("HdelTransaction", mock.Anything, ts.idTransaction).Return(ts.cacheErr)
 			}
 			log := logrus.New()
 
-			server := CreateTransactionServer(repoMock, repoMock, repoMock, repoMock, log, redisMock)
-			err := server.DeleteTransaction(context.Background(), ts.idTransaction)
+			server := CreateTransactionServer(repoMock, repoMock, repoMock, repoMock, repoMock, log, redisMock)
+			err := server.DeleteTransaction(context.Background(), member, ts.idTransaction)
 			if ts.tranErr != nil || ts.svcErr != nil {
 				assert.Error(t, err)
 				if !errors.Is(err, ts.svcErr) {
@@ -446,3 +512,117 @@ func TestDeleteTransactionServer(t *testing.T) {
 		})
 	}
 }
+
+func TestListAnomalies(t *testing.T) {
+	type test struct {
+		name         string
+		status       string
+		repoStatus   string
+		anomalies    []domain.TransactionAnomaly
+		repoErr      error
+		tranErr      error
+		shouldCallDB bool
+	}
+
+	anomalies := []domain.TransactionAnomaly{
+		{
+			TransactionRecord: domain.TransactionRecord{ID: 4, TransactionOutput: domain.TransactionOutput{Name: "кофе", Count: 50000}},
+			Reason:            "count 50000 is far above the category median 300",
+			Status:            domain.AnomalyFlagged,
+		},
+	}
+
+	arrTest := []test{
+		{
+			name:         "success default status",
+			status:       "",
+			repoStatus:   domain.AnomalyFlagged,
+			anomalies:    anomalies,
+			shouldCallDB: true,
+		},
+		{
+			name:         "success dismissed",
+			status:       domain.AnomalyDismissed,
+			repoStatus:   domain.AnomalyDismissed,
+			anomalies:    []domain.TransactionAnomaly{},
+			shouldCallDB: true,
+		},
+		{
+			name:         "error status",
+			status:       "deleted",
+			tranErr:      ErrAnomalyStatus,
+			shouldCallDB: false,
+		},
+		{
+			name:         "error database",
+			status:       domain.AnomalyFlagged,
+			repoStatus:   domain.AnomalyFlagged,
+			anomalies:    []domain.TransactionAnomaly{},
+			repoErr:      errors.New("some db error"),
+			tranErr:      ErrDatabase,
+			shouldCallDB: true,
+		},
+	}
+
+	for _, test := range arrTest {
+		t.Run(test.name, func(t *testing.T) {
+			repoMock := new(DbMock)
+			repoMock.On("TransactionAnomalies", mock.Anything, member, test.repoStatus).Return(test.anomalies, test.repoErr)
+
+			server := CreateTransactionServer(repoMock, repoMock, repoMock, repoMock, repoMock, logrus.New(), new(cash.RedisMock))
+			res, err := server.ListAnomalies(context.Background(), member, test.status)
+
+			if test.tranErr != nil {
+				assert.ErrorIs(t, err, test.tranErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.anomalies, res)
+			}
+
+			if test.shouldCallDB {
+				repoMock.AssertExpectations(t)
+			} else {
+				repoMock.AssertNotCalled(t, "TransactionAnomalies", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestReviewAnomaly(t *testing.T) {
+	type test struct {
+		name         string
+		status       string
+		repoErr      error
+		tranErr      error
+		shouldCallDB bool
+	}
+
+	arrTest := []test{
+		{name: "confirm", status: domain.AnomalyConfirmed, shouldCallDB: true},
+		{name: "dismiss", status: domain.AnomalyDismissed, shouldCallDB: true},
+		{name: "error status", status: domain.AnomalyFlagged, tranErr: ErrAnomalyStatus, shouldCallDB: false},
+		{name: "error not found", status: domain.AnomalyDismissed, repoErr: postgresql.ErrorNotFound, tranErr: ErrNoFound, shouldCallDB: true},
+	}
+
+	for _, test := range arrTest {
+		t.Run(test.name, func(t *testing.T) {
+			repoMock := new(DbMock)
+			repoMock.On("ReviewAnomaly", mock.Anything, member, uint(4), test.status).Return(test.repoErr)
+
+			server := CreateTransactionServer(repoMock, repoMock, repoMock, repoMock, repoMock, logrus.New(), new(cash.RedisMock))
+			err := server.ReviewAnomaly(context.Background(), member, 4, test.status)
+
+			if test.tranErr != nil {
+				assert.ErrorIs(t, err, test.tranErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			if test.shouldCallDB {
+				repoMock.AssertExpectations(t)
+			} else {
+				repoMock.AssertNotCalled(t, "ReviewAnomaly", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
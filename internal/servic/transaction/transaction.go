@@ -5,20 +5,21 @@ import (
 	"strconv"
 
 	"github.com/financial_tracer/internal/domain"
+	"github.com/financial_tracer/internal/lib/anomaly"
 	"github.com/go-playground/validator/v10"
 	"github.com/sirupsen/logrus"
 )
 
 type CreateTransactionRepository interface {
-	CreateTransaction(ctx context.Context, idUser uint, idCategory uint, tran domain.TransactionInput) (uint, error)
+	CreateTransaction(ctx context.Context, m domain.Member, idCategory uint, tran domain.TransactionInput) (uint, error)
 }
 
 type GetTransactionRepository interface {
-	GetTransaction(ctx context.Context, TransactionId uint) (domain.TransactionOutput, error)
+	GetTransaction(ctx context.Context, m domain.Member, TransactionId uint) (domain.TransactionOutput, error)
 }
 
 type UpdateTransactionRepository interface {
-	UpdateTransaction(ctx context.Context, transactionId uint, newTransaction domain.TransactionInput) (domain.TransactionOutput, error)
+	UpdateTransaction(ctx context.Context, m domain.Member, transactionId uint, newTransaction domain.TransactionInput) (domain.TransactionOutput, error)
 }
 
 type Redis interface {
@@ -28,7 +29,17 @@ type Redis interface {
 }
 
 type DeleteTransactionRepository interface {
-	DeleteTransaction(ctx context.Context, transactionId uint) error
+	DeleteTransaction(ctx context.Context, m domain.Member, transactionId uint) error
+}
+
+// anomalyHistory is the number of latest category transactions a new one is compared with.
+const anomalyHistory = 100
+
+type AnomalyRepository interface {
+	CategoryCounts(ctx context.Context, m domain.Member, idCategory uint, limit int) ([]int, error)
+	CreateFlaggedTransaction(ctx context.Context, m domain.Member, idCategory uint, tran domain.TransactionInput, reason string) (uint, error)
+	TransactionAnomalies(ctx context.Context, m domain.Member, status string) ([]domain.TransactionAnomaly, error)
+	ReviewAnomaly(ctx context.Context, m domain.Member, transactionId uint, status string) error
 }
 
 type TransactionServer struct {
@@ -36,6 +47,7 @@ type TransactionServer struct {
 	c        CreateTransactionRepository
 	g        GetTransactionRepository
 	u        UpdateTransactionRepository
+	a        AnomalyRepository
 	log      *logrus.Logger
 	validate validator.Validate
 	rbd      Redis
@@ -45,6 +57,7 @@ func CreateTransactionServer(d DeleteTransactionRepository,
 	c CreateTransactionRepository,
 	g GetTransactionRepository,
 	u UpdateTransactionRepository,
+	a AnomalyRepository,
 	log *logrus.Logger,
 	r Redis) *TransactionServer {
 
@@ -53,19 +66,21 @@ func CreateTransactionServer(d DeleteTransactionRepository,
 		g:        g,
 		c:        c,
 		u:        u,
+		a:        a,
 		log:      log,
 		validate: *validator.New(),
 		rbd:      r,
 	}
 }
 
-func (ts *TransactionServer) CreateTransaction(ctx context.Context, idUser uint, idCategory uint, tran domain.TransactionInput) (uint, error) {
+func (ts *TransactionServer) CreateTransaction(ctx context.Context, m domain.Member, idCategory uint, tran domain.TransactionInput) (uint, error) {
 	const op = "transaction.CreateTransactionServic"
 
 	log := ts.log.WithFields(logrus.Fields{
-		"op":          op,
-		"user_id":     idUser,
-		"category_id": idCategory,
+		"op":           op,
+		"user_id":      m.UserID,
+		"household_id": m.HouseholdID,
+		"category_id":  idCategory,
 	})
 
 	log.Info("start create transaction")
@@ -76,7 +91,22 @@ func (ts *TransactionServer) CreateTransaction(ctx context.Context, idUser uint,
 		return 0, err
 	}
 
-	id, err := ts.c.CreateTransaction(ctx, idUser, idCategory, tran)
+	var isAnomaly bool
+	var reason string
+	counts, err := ts.a.CategoryCounts(ctx, m, idCategory, anomalyHistory)
+	if err != nil {
+		log.Error("error get category history: ", err)
+	} else {
+		isAnomaly, reason = anomaly.Detect(counts, tran.Count)
+	}
+
+	var id uint
+	if isAnomaly {
+		log.WithField("reason", reason).Warn("anomaly transaction")
+		id, err = ts.a.CreateFlaggedTransaction(ctx, m, idCategory, tran, reason)
+	} else {
+		id, err = ts.c.CreateTransaction(ctx, m, idCategory, tran)
+	}
 	if err != nil {
 		log.Error("error create transaction: ", err)
 		return 0, RegisterErrDatabase(err)
@@ -86,7 +116,8 @@ func (ts *TransactionServer) CreateTransaction(ctx context.Context, idUser uint,
 	go func(canal chan error) {
 		transaction := domain.TransactionOutput{
 			Name:        tran.Name,
-			UserID:      idUser,
+			UserID:      m.UserID,
+			HouseholdID: m.HouseholdID,
 			CategoryID:  idCategory,
 			Description: tran.Description,
 			Count:       tran.Count,
@@ -103,17 +134,18 @@ func (ts *TransactionServer) CreateTransaction(ctx context.Context, idUser uint,
 	return id, nil
 }
 
-func (ts *TransactionServer) GetTransaction(ctx context.Context, idTransaction uint) (domain.TransactionOutput, error) {
+func (ts *TransactionServer) GetTransaction(ctx context.Context, m domain.Member, idTransaction uint) (domain.TransactionOutput, error) {
 	const op = "transaction.GetTransactionServer"
 	log := ts.log.WithFields(logrus.Fields{
 		"op":             op,
 		"transaction_id": idTransaction,
+		"household_id":   m.HouseholdID,
 	})
 
 	log.Info("start read transaction")
 
 	result, err := ts.rbd.HgetTransaction(ctx, idTransaction)
-	if err == nil {
+	if err == nil && result["householdID"] == strconv.FormatUint(uint64(m.HouseholdID), 10) {
 		usID, _ := strconv.ParseUint(result["userID"], 10, 64)
 		categorID, _ := strconv.ParseUint(result["categoryID"], 10, 64)
 		count, _ := strconv.Atoi(result["count"])
@@ -122,6 +154,7 @@ func (ts *TransactionServer) GetTransaction(ctx context.Context, idTransaction u
 			Name:        result["name"],
 			Description: result["description"],
 			UserID:      uint(usID),
+			HouseholdID: m.HouseholdID,
 			CategoryID:  uint(categorID),
 			Count:       count,
 		}, nil
@@ -129,7 +162,7 @@ func (ts *TransactionServer) GetTransaction(ctx context.Context, idTransaction u
 		log.Info("err info: ", err)
 	}
 
-	transaction, err := ts.g.GetTransaction(ctx, idTransaction)
+	transaction, err := ts.g.GetTransaction(ctx, m, idTransaction)
 	if err != nil {
 		log.Error("error get transaction: ", err)
 		return domain.TransactionOutput{}, RegisterErrDatabase(err)
@@ -140,12 +173,13 @@ func (ts *TransactionServer) GetTransaction(ctx context.Context, idTransaction u
 	return transaction, nil
 }
 
-func (ts *TransactionServer) UpdateTransaction(ctx context.Context, idTransaction uint, newTransaction domain.TransactionInput) (domain.TransactionOutput, error) {
+func (ts *TransactionServer) UpdateTransaction(ctx context.Context, m domain.Member, idTransaction uint, newTransaction domain.TransactionInput) (domain.TransactionOutput, error) {
 	const op = "transaction.UpdateTransactionServer"
 
 	log := ts.log.WithFields(logrus.Fields{
 		"op":             op,
 		"transaction_id": idTransaction,
+		"household_id":   m.HouseholdID,
 	})
 
 	log.Info("start update transaction")
@@ -156,7 +190,7 @@ func (ts *TransactionServer) UpdateTransaction(ctx context.Context, idTransactio
 		return domain.TransactionOutput{}, err
 	}
 
-	transaction, err := ts.u.UpdateTransaction(ctx, idTransaction, newTransaction)
+	transaction, err := ts.u.UpdateTransaction(ctx, m, idTransaction, newTransaction)
 	if err != nil {
 		log.Error("error update transaction: ", err)
 		return domain.TransactionOutput{}, RegisterErrDatabase(err)
@@ -175,17 +209,18 @@ func (ts *TransactionServer) UpdateTransaction(ctx context.Context, idTransactio
 	return transaction, nil
 }
 
-func (ts *TransactionServer) DeleteTransaction(ctx context.Context, idTransaction uint) error {
+func (ts *TransactionServer) DeleteTransaction(ctx context.Context, m domain.Member, idTransaction uint) error {
 	const op = "transaction.DeleteTransactionServer"
 
 	log := ts.log.WithFields(logrus.Fields{
 		"op":             op,
 		"transaction_id": idTransaction,
+		"household_id":   m.HouseholdID,
 	})
 
 	log.Info("start delete transaction")
 
-	err := ts.d.DeleteTransaction(ctx, idTransaction)
+	err := ts.d.DeleteTransaction(ctx, m, idTransaction)
 	if err != nil {
 		log.Error("error delete transaction: ", err)
 		return RegisterErrDatabase(err)
@@ -203,3 +238,62 @@ func (ts *TransactionServer) DeleteTransaction(ctx context.Context, idTransactio
 	log.Info("success delete transaction")
 	return nil
 }
+
+func (ts *TransactionServer) ListAnomalies(ctx context.Context, m domain.Member, status string) ([]domain.TransactionAnomaly, error) {
+	const op = "transaction.ListAnomalies"
+
+	log := ts.log.WithFields(logrus.Fields{
+		"op":           op,
+		"user_id":      m.UserID,
+		"household_id": m.HouseholdID,
+		"status":       status,
+	})
+
+	log.Info("start list anomalies")
+
+	if status == "" {
+		status = domain.AnomalyFlagged
+	}
+	if status != domain.AnomalyFlagged && status != domain.AnomalyConfirmed && status != domain.AnomalyDismissed {
+		log.Error("invalid status")
+		return nil, ErrAnomalyStatus
+	}
+
+	anomalies, err := ts.a.TransactionAnomalies(ctx, m, status)
+	if err != nil {
+		log.Error("error list anomalies: ", err)
+		return nil, RegisterErrDatabase(err)
+	}
+
+	log.Info("success list anomalies")
+
+	return anomalies, nil
+}
+
+func (ts *TransactionServer) ReviewAnomaly(ctx context.Context, m domain.Member, idTransaction uint, status string) error {
+	const op = "transaction.ReviewAnomaly"
+
+	log := ts.log.WithFields(logrus.Fields{
+		"op":             op,
+		"user_id":        m.UserID,
+		"household_id":   m.HouseholdID,
+		"transaction_id": idTransaction,
+		"status":         status,
+	})
+
+	log.Info("start review anomaly")
+
+	if status != domain.AnomalyConfirmed && status != domain.AnomalyDismissed {
+		log.Error("invalid status")
+		return ErrAnomalyStatus
+	}
+
+	if err := ts.a.ReviewAnomaly(ctx, m, idTransaction, status); err != nil {
+		log.Error("error review anomaly: ", err)
+		return RegisterErrDatabase(err)
+	}
+
+	log.Info("success review anomaly")
+
+	return nil
+}
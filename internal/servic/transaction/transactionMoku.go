@@ -11,22 +11,42 @@ type DbMock struct {
 	mock.Mock
 }
 
-func (d *DbMock) CreateTransaction(ctx context.Context, idUser uint, idCategory uint, tran domain.TransactionInput) (uint, error) {
-	args := d.Called(ctx, idUser, idCategory, tran)
+func (d *DbMock) CreateTransaction(ctx context.Context, m domain.Member, idCategory uint, tran domain.TransactionInput) (uint, error) {
+	args := d.Called(ctx, m, idCategory, tran)
 	return args.Get(0).(uint), args.Error(1)
 }
 
-func (d *DbMock) GetTransaction(ctx context.Context, TransactionId uint) (domain.TransactionOutput, error) {
-	args := d.Called(ctx, TransactionId)
+func (d *DbMock) GetTransaction(ctx context.Context, m domain.Member, TransactionId uint) (domain.TransactionOutput, error) {
+	args := d.Called(ctx, m, TransactionId)
 	return args.Get(0).(domain.TransactionOutput), args.Error(1)
 }
 
-func (d *DbMock) UpdateTransaction(ctx context.Context, transactionId uint, newTransaction domain.TransactionInput) (domain.TransactionOutput, error) {
-	args := d.Called(ctx, transactionId, newTransaction)
+func (d *DbMock) UpdateTransaction(ctx context.Context, m domain.Member, transactionId uint, newTransaction domain.TransactionInput) (domain.TransactionOutput, error) {
+	args := d.Called(ctx, m, transactionId, newTransaction)
 	return args.Get(0).(domain.TransactionOutput), args.Error(1)
 }
 
-func (d *DbMock) DeleteTransaction(ctx context.Context, transactionId uint) error {
-	args := d.Called(ctx, transactionId)
+func (d *DbMock) DeleteTransaction(ctx context.Context, m domain.Member, transactionId uint) error {
+	args := d.Called(ctx, m, transactionId)
+	return args.Error(0)
+}
+
+func (d *DbMock) CategoryCounts(ctx context.Context, m domain.Member, idCategory uint, limit int) ([]int, error) {
+	args := d.Called(ctx, m, idCategory, limit)
+	return args.Get(0).([]int), args.Error(1)
+}
+
+func (d *DbMock) CreateFlaggedTransaction(ctx context.Context, m domain.Member, idCategory uint, tran domain.TransactionInput, reason string) (uint, error) {
+	args := d.Called(ctx, m, idCategory, tran, reason)
+	return args.Get(0).(uint), args.Error(1)
+}
+
+func (d *DbMock) TransactionAnomalies(ctx context.Context, m domain.Member, status string) ([]domain.TransactionAnomaly, error) {
+	args := d.Called(ctx, m, status)
+	return args.Get(0).([]domain.TransactionAnomaly), args.Error(1)
+}
+
+func (d *DbMock) ReviewAnomaly(ctx context.Context, m domain.Member, transactionId uint, status string) error {
+	args := d.Called(ctx, m, transactionId, status)
 	return args.Error(0)
 }
@@ -0,0 +1,33 @@
+package twofactor
+
+import (
+	"errors"
+
+	"github.com/financial_tracer/internal/infastructure/db/postgresql"
+)
+
+var (
+	ErrDatabase     = errors.New("error database")
+	ErrServic       = errors.New("servic error")
+	ErrNoFound      = errors.New("user is not found")
+	ErrEnabled      = errors.New("two-factor authentication already enabled")
+	ErrDisabled     = errors.New("two-factor authentication is not enabled")
+	ErrCode         = errors.New("invalid two-factor code")
+	ErrRecoveryCode = errors.New("invalid recovery code")
+)
+
+func RegisterErrDatabase(err error) error {
+	arr := map[error]error{
+		postgresql.ErrorNotFound:     ErrNoFound,
+		postgresql.ErrorTwoFactorOn:  ErrEnabled,
+		postgresql.ErrorTwoFactorOff: ErrDisabled,
+		postgresql.ErrorReused:       ErrCode,
+	}
+
+	value, ok := arr[err]
+	if !ok {
+		return ErrDatabase
+	}
+
+	return value
+}
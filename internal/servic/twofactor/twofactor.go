@@ -0,0 +1,256 @@
+package twofactor
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/financial_tracer/internal/infastructure/db/postgresql"
+	"github.com/financial_tracer/internal/lib/totp"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+)
+
+const DefaultIssuer = "financial_tracer"
+
+type EnrollRepository interface {
+	EnrollTwoFactor(ctx context.Context, userID uint, secret string) (domain.User, error)
+	PendingTwoFactor(ctx context.Context, userID uint) (string, error)
+	ConfirmTwoFactor(ctx context.Context, userID uint, secret string, step int64, codeHashes []string) error
+}
+
+type TwoFactorRepository interface {
+	TwoFactor(ctx context.Context, userID uint) (domain.TwoFactor, error)
+	UseTOTPStep(ctx context.Context, userID uint, step int64) error
+	UseRecoveryCode(ctx context.Context, userID uint, codeHash string) error
+	DisableTwoFactor(ctx context.Context, userID uint) error
+}
+
+type EventRecorder interface {
+	Record(ctx context.Context, event domain.SecurityEvent)
+}
+
+type TwoFactorServer struct {
+	e        EnrollRepository
+	t        TwoFactorRepository
+	r        EventRecorder
+	issuer   string
+	log      *logrus.Logger
+	validate validator.Validate
+	now      func() time.Time
+}
+
+func CreateTwoFactorServer(e EnrollRepository, t TwoFactorRepository, r EventRecorder, issuer string, log *logrus.Logger, now func() time.Time) *TwoFactorServer {
+	if issuer == "" {
+		issuer = DefaultIssuer
+	}
+
+	return &TwoFactorServer{
+		e:        e,
+		t:        t,
+		r:        r,
+		issuer:   issuer,
+		log:      log,
+		validate: *validator.New(),
+		now:      now,
+	}
+}
+
+// Enroll takes effect after Confirm, so an unfinished setup does not lock the user out.
+func (ts *TwoFactorServer) Enroll(ctx context.Context, userID uint) (domain.TwoFactorEnrollment, error) {
+	const op = "twofactor.Enroll"
+
+	log := ts.log.WithFields(logrus.Fields{
+		"op":      op,
+		"user_id": userID,
+	})
+
+	log.Info("start enroll two-factor")
+
+	secret, err := totp.NewSecret()
+	if err != nil {
+		log.WithField("err", err).Error("field create secret")
+		return domain.TwoFactorEnrollment{}, ErrServic
+	}
+
+	user, err := ts.e.EnrollTwoFactor(ctx, userID, secret)
+	if err != nil {
+		log.Error("error enroll two-factor: ", err)
+		return domain.TwoFactorEnrollment{}, RegisterErrDatabase(err)
+	}
+
+	log.Info("success enroll two-factor")
+
+	return domain.TwoFactorEnrollment{
+		Secret: secret,
+		URI:    totp.URI(ts.issuer, user.Email, secret),
+	}, nil
+}
+
+// Confirm returns the recovery codes once, only their hashes are stored.
+func (ts *TwoFactorServer) Confirm(ctx context.Context, userID uint, req domain.TwoFactorCode) (domain.RecoveryCodes, error) {
+	const op = "twofactor.Confirm"
+
+	log := ts.log.WithFields(logrus.Fields{
+		"op":      op,
+		"user_id": userID,
+	})
+
+	log.Info("start confirm two-factor")
+
+	if err := ts.validate.Struct(req); err != nil {
+		log.WithField("err", err).Error("invalid validate")
+		return domain.RecoveryCodes{}, err
+	}
+
+	secret, err := ts.e.PendingTwoFactor(ctx, userID)
+	if err != nil {
+		log.Error("error get pending two-factor: ", err)
+		return domain.RecoveryCodes{}, RegisterErrDatabase(err)
+	}
+
+	step, ok := totp.Validate(secret, req.Code, ts.now(), 0)
+	if !ok {
+		log.Error("invalid code")
+		return domain.RecoveryCodes{}, ErrCode
+	}
+
+	codes, err := totp.NewRecoveryCodes(totp.RecoveryCodes)
+	if err != nil {
+		log.WithField("err", err).Error("field create recovery codes")
+		return domain.RecoveryCodes{}, ErrServic
+	}
+
+	hashes := make([]string, 0, len(codes))
+	for _, code := range codes {
+		hashes = append(hashes, totp.HashRecoveryCode(code))
+	}
+
+	if err := ts.e.ConfirmTwoFactor(ctx, userID, secret, step, hashes); err != nil {
+		log.Error("error confirm two-factor: ", err)
+		return domain.RecoveryCodes{}, RegisterErrDatabase(err)
+	}
+
+	ts.r.Record(ctx, domain.SecurityEvent{
+		UserID:  userID,
+		Type:    domain.EventTwoFactorEnable,
+		Success: true,
+		Device:  req.Device,
+	})
+
+	log.Info("success confirm two-factor")
+
+	return domain.RecoveryCodes{Codes: codes}, nil
+}
+
+// Disable takes a current TOTP code or a recovery code.
+func (ts *TwoFactorServer) Disable(ctx context.Context, userID uint, req domain.TwoFactorCode) error {
+	const op = "twofactor.Disable"
+
+	log := ts.log.WithFields(logrus.Fields{
+		"op":      op,
+		"user_id": userID,
+	})
+
+	log.Info("start disable two-factor")
+
+	if err := ts.validate.Struct(req); err != nil {
+		log.WithField("err", err).Error("invalid validate")
+		return err
+	}
+
+	err := ts.Verify(ctx, userID, req.Code)
+	if errors.Is(err, ErrCode) {
+		err = ts.useRecoveryCode(ctx, userID, req.Code)
+		if errors.Is(err, ErrRecoveryCode) {
+			err = ErrCode
+		}
+	}
+	if err != nil {
+		log.WithField("err", err).Error("error check code")
+		return err
+	}
+
+	if err := ts.t.DisableTwoFactor(ctx, userID); err != nil {
+		log.Error("error disable two-factor: ", err)
+		return RegisterErrDatabase(err)
+	}
+
+	ts.r.Record(ctx, domain.SecurityEvent{
+		UserID:  userID,
+		Type:    domain.EventTwoFactorDisable,
+		Success: true,
+		Device:  req.Device,
+	})
+
+	log.Info("success disable two-factor")
+
+	return nil
+}
+
+// Verify accepts every code once.
+func (ts *TwoFactorServer) Verify(ctx context.Context, userID uint, code string) error {
+	const op = "twofactor.Verify"
+
+	log := ts.log.WithFields(logrus.Fields{
+		"op":      op,
+		"user_id": userID,
+	})
+
+	tf, err := ts.t.TwoFactor(ctx, userID)
+	if err != nil {
+		log.Error("error get two-factor: ", err)
+		return RegisterErrDatabase(err)
+	}
+
+	step, ok := totp.Validate(tf.Secret, code, ts.now(), tf.LastStep)
+	if !ok {
+		log.Warn("invalid code")
+		return ErrCode
+	}
+
+	if err := ts.t.UseTOTPStep(ctx, userID, step); err != nil {
+		log.Error("error use code: ", err)
+		return RegisterErrDatabase(err)
+	}
+
+	return nil
+}
+
+func (ts *TwoFactorServer) Recover(ctx context.Context, userID uint, code string) error {
+	const op = "twofactor.Recover"
+
+	log := ts.log.WithFields(logrus.Fields{
+		"op":      op,
+		"user_id": userID,
+	})
+
+	log.Info("start recover two-factor")
+
+	if err := ts.useRecoveryCode(ctx, userID, code); err != nil {
+		log.WithField("err", err).Error("error use recovery code")
+		return err
+	}
+
+	if err := ts.t.DisableTwoFactor(ctx, userID); err != nil {
+		log.Error("error disable two-factor: ", err)
+		return RegisterErrDatabase(err)
+	}
+
+	log.Info("success recover two-factor")
+
+	return nil
+}
+
+func (ts *TwoFactorServer) useRecoveryCode(ctx context.Context, userID uint, code string) error {
+	err := ts.t.UseRecoveryCode(ctx, userID, totp.HashRecoveryCode(code))
+	if err != nil {
+		if errors.Is(err, postgresql.ErrorNotFound) {
+			return ErrRecoveryCode
+		}
+		return ErrDatabase
+	}
+
+	return nil
+}
@@ -0,0 +1,47 @@
+package twofactor
+
+import (
+	"context"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type DbMock struct {
+	mock.Mock
+}
+
+func (d *DbMock) EnrollTwoFactor(ctx context.Context, userID uint, secret string) (domain.User, error) {
+	args := d.Called(ctx, userID, secret)
+	return args.Get(0).(domain.User), args.Error(1)
+}
+
+func (d *DbMock) PendingTwoFactor(ctx context.Context, userID uint) (string, error) {
+	args := d.Called(ctx, userID)
+	return args.String(0), args.Error(1)
+}
+
+func (d *DbMock) ConfirmTwoFactor(ctx context.Context, userID uint, secret string, step int64, codeHashes []string) error {
+	args := d.Called(ctx, userID, secret, step, codeHashes)
+	return args.Error(0)
+}
+
+func (d *DbMock) TwoFactor(ctx context.Context, userID uint) (domain.TwoFactor, error) {
+	args := d.Called(ctx, userID)
+	return args.Get(0).(domain.TwoFactor), args.Error(1)
+}
+
+func (d *DbMock) UseTOTPStep(ctx context.Context, userID uint, step int64) error {
+	args := d.Called(ctx, userID, step)
+	return args.Error(0)
+}
+
+func (d *DbMock) UseRecoveryCode(ctx context.Context, userID uint, codeHash string) error {
+	args := d.Called(ctx, userID, codeHash)
+	return args.Error(0)
+}
+
+func (d *DbMock) DisableTwoFactor(ctx context.Context, userID uint) error {
+	args := d.Called(ctx, userID)
+	return args.Error(0)
+}
@@ -0,0 +1,194 @@
+package twofactor
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/financial_tracer/internal/infastructure/db/postgresql"
+	"github.com/financial_tracer/internal/lib/totp"
+	"github.com/financial_tracer/internal/servic/security"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+var now = time.Unix(1111111111, 0)
+
+func code(t *testing.T, step int64) string {
+	t.Helper()
+	c, err := totp.Code(secret, step)
+	require.NoError(t, err)
+	return c
+}
+
+func newServer(repoMock *DbMock) *TwoFactorServer {
+	return CreateTwoFactorServer(repoMock, repoMock, new(security.EventMock), "", logrus.New(), func() time.Time { return now })
+}
+
+func TestEnroll(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		repoMock := new(DbMock)
+		repoMock.On("EnrollTwoFactor", mock.Anything, uint(1), mock.Anything).Return(domain.User{Email: "jonn@gmail.com"}, nil)
+
+		res, err := newServer(repoMock).Enroll(context.Background(), 1)
+		require.NoError(t, err)
+
+		assert.Equal(t, res.Secret, repoMock.Calls[0].Arguments.Get(2))
+		u, err := url.Parse(res.URI)
+		require.NoError(t, err)
+		assert.Equal(t, "/financial_tracer:jonn@gmail.com", u.Path)
+		assert.Equal(t, res.Secret, u.Query().Get("secret"))
+	})
+
+	t.Run("error already enabled", func(t *testing.T) {
+		repoMock := new(DbMock)
+		repoMock.On("EnrollTwoFactor", mock.Anything, uint(1), mock.Anything).Return(domain.User{}, postgresql.ErrorTwoFactorOn)
+
+		_, err := newServer(repoMock).Enroll(context.Background(), 1)
+		assert.ErrorIs(t, err, ErrEnabled)
+	})
+}
+
+func TestConfirm(t *testing.T) {
+	step := totp.Step(now)
+
+	tests := []struct {
+		name       string
+		code       string
+		pendingErr error
+		wantErr    error
+		confirm    bool
+	}{
+		{name: "success", code: code(t, step), confirm: true},
+		{name: "error wrong code", code: code(t, step-5), wantErr: ErrCode},
+		{name: "error not enrolled", code: code(t, step), pendingErr: postgresql.ErrorTwoFactorOff, wantErr: ErrDisabled},
+	}
+
+	for _, ts := range tests {
+		t.Run(ts.name, func(t *testing.T) {
+			repoMock := new(DbMock)
+			repoMock.On("PendingTwoFactor", mock.Anything, uint(1)).Return(secret, ts.pendingErr)
+			repoMock.On("ConfirmTwoFactor", mock.Anything, uint(1), secret, step, mock.Anything).Return(nil)
+
+			res, err := newServer(repoMock).Confirm(context.Background(), 1, domain.TwoFactorCode{Code: ts.code})
+
+			if ts.wantErr != nil {
+				assert.ErrorIs(t, err, ts.wantErr)
+			} else {
+				require.NoError(t, err)
+				require.Len(t, res.Codes, totp.RecoveryCodes)
+
+				hashes := repoMock.Calls[1].Arguments.Get(4).([]string)
+				assert.Equal(t, totp.HashRecoveryCode(res.Codes[0]), hashes[0])
+			}
+
+			if ts.confirm {
+				repoMock.AssertCalled(t, "ConfirmTwoFactor", mock.Anything, uint(1), secret, step, mock.Anything)
+			} else {
+				repoMock.AssertNotCalled(t, "ConfirmTwoFactor", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestVerify(t *testing.T) {
+	step := totp.Step(now)
+
+	tests := []struct {
+		name     string
+		code     string
+		lastStep int64
+		stepErr  error
+		wantErr  error
+	}{
+		{name: "success", code: code(t, step)},
+		{name: "error wrong code", code: "000000", wantErr: ErrCode},
+		{name: "error code already used", code: code(t, step), lastStep: step, wantErr: ErrCode},
+		{name: "error concurrent use", code: code(t, step), stepErr: postgresql.ErrorReused, wantErr: ErrCode},
+	}
+
+	for _, ts := range tests {
+		t.Run(ts.name, func(t *testing.T) {
+			repoMock := new(DbMock)
+			repoMock.On("TwoFactor", mock.Anything, uint(1)).Return(domain.TwoFactor{Secret: secret, LastStep: ts.lastStep}, nil)
+			repoMock.On("UseTOTPStep", mock.Anything, uint(1), step).Return(ts.stepErr)
+
+			err := newServer(repoMock).Verify(context.Background(), 1, ts.code)
+
+			if ts.wantErr != nil {
+				assert.ErrorIs(t, err, ts.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestDisable(t *testing.T) {
+	step := totp.Step(now)
+
+	tests := []struct {
+		name        string
+		code        string
+		recoveryErr error
+		wantErr     error
+		disable     bool
+	}{
+		{name: "success with code", code: code(t, step), disable: true},
+		{name: "success with recovery code", code: "abcde-fghjk", disable: true},
+		{name: "error wrong code", code: "abcde-fghjk", recoveryErr: postgresql.ErrorNotFound, wantErr: ErrCode},
+		{name: "error database", code: "abcde-fghjk", recoveryErr: errors.New("some db error"), wantErr: ErrDatabase},
+	}
+
+	for _, ts := range tests {
+		t.Run(ts.name, func(t *testing.T) {
+			repoMock := new(DbMock)
+			repoMock.On("TwoFactor", mock.Anything, uint(1)).Return(domain.TwoFactor{Secret: secret}, nil)
+			repoMock.On("UseTOTPStep", mock.Anything, uint(1), step).Return(nil)
+			repoMock.On("UseRecoveryCode", mock.Anything, uint(1), totp.HashRecoveryCode("abcde-fghjk")).Return(ts.recoveryErr)
+			repoMock.On("DisableTwoFactor", mock.Anything, uint(1)).Return(nil)
+
+			err := newServer(repoMock).Disable(context.Background(), 1, domain.TwoFactorCode{Code: ts.code})
+
+			if ts.wantErr != nil {
+				assert.ErrorIs(t, err, ts.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			if ts.disable {
+				repoMock.AssertCalled(t, "DisableTwoFactor", mock.Anything, uint(1))
+			} else {
+				repoMock.AssertNotCalled(t, "DisableTwoFactor", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestRecover(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		repoMock := new(DbMock)
+		repoMock.On("UseRecoveryCode", mock.Anything, uint(1), totp.HashRecoveryCode("ABCDE FGHJK")).Return(nil)
+		repoMock.On("DisableTwoFactor", mock.Anything, uint(1)).Return(nil)
+
+		err := newServer(repoMock).Recover(context.Background(), 1, "ABCDE FGHJK")
+		assert.NoError(t, err)
+		repoMock.AssertCalled(t, "DisableTwoFactor", mock.Anything, uint(1))
+	})
+
+	t.Run("error used code", func(t *testing.T) {
+		repoMock := new(DbMock)
+		repoMock.On("UseRecoveryCode", mock.Anything, uint(1), mock.Anything).Return(postgresql.ErrorNotFound)
+
+		err := newServer(repoMock).Recover(context.Background(), 1, "abcde-fghjk")
+		assert.ErrorIs(t, err, ErrRecoveryCode)
+		repoMock.AssertNotCalled(t, "DisableTwoFactor", mock.Anything, mock.Anything)
+	})
+}
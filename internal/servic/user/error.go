@@ -11,12 +11,26 @@ var (
 	ErrServic     = errors.New("servic error")
 	ErrDuplicated = errors.New("the email has already been registered")
 	ErrNoFound    = errors.New("user is not found")
+	ErrPassword   = errors.New("wrong email or password")
+	ErrDisabled   = errors.New("user is disabled")
+
+	ErrRegistrationClosed = errors.New("registration is closed")
+	ErrInvite             = errors.New("invalid or expired invite code")
+	ErrEmailDomain        = errors.New("registration is not allowed for the email domain")
+
+	ErrToken           = errors.New("invalid refresh token")
+	ErrTokenReused     = errors.New("refresh token already used, session revoked")
+	ErrSessionNotFound = errors.New("session is not found")
+	ErrChallenge       = errors.New("invalid or expired two-factor challenge")
 )
 
 func RegisterErrDatabase(err error) error {
 	arr := map[error]error{
 		postgresql.ErrorDuplicated: ErrDuplicated,
 		postgresql.ErrorNotFound:   ErrNoFound,
+		postgresql.ErrorPassword:   ErrPassword,
+		postgresql.ErrorDisabled:   ErrDisabled,
+		postgresql.ErrorInvite:     ErrInvite,
 	}
 
 	value, ok := arr[err]
@@ -2,48 +2,101 @@ package user
 
 import (
 	"context"
+	"errors"
+	"strings"
+	"time"
 
 	"github.com/financial_tracer/internal/domain"
+	"github.com/financial_tracer/internal/infastructure/db/postgresql"
 	"github.com/financial_tracer/internal/lib/hashPassword"
 	jwttoken "github.com/financial_tracer/internal/lib/jwtToken"
+	"github.com/financial_tracer/internal/lib/mailToken"
+	"github.com/financial_tracer/internal/lib/passwordPolicy"
+	"github.com/financial_tracer/internal/servic/lockout"
+	"github.com/financial_tracer/internal/servic/twofactor"
 	"github.com/go-playground/validator/v10"
 	"github.com/sirupsen/logrus"
 )
 
 type RegistrationuserRepository interface {
-	RegistrationUser(ctx context.Context, user domain.User) (uint, string, error)
-}
-
-type DeleteUserRepository interface {
-	DeleteUser(ctx context.Context, email string, password string) error
+	RegistrationUser(ctx context.Context, user domain.User, inviteHash string) (uint, string, error)
 }
 
 type AuthenticationUserRepository interface {
 	AuthenticationUser(ctx context.Context, email string, password string) (uint, string, error)
 }
 
+type SessionRepository interface {
+	CreateSession(ctx context.Context, userID uint, jti string, expiresAt time.Time, device domain.Device) (uint, error)
+	RotateSession(ctx context.Context, userID uint, sessionID uint, jti string, newJti string, expiresAt time.Time, device domain.Device) error
+	RevokeSession(ctx context.Context, userID uint, sessionID uint) error
+	UserSessions(ctx context.Context, userID uint) ([]domain.Session, error)
+}
+
+type VerificationSender interface {
+	SendVerification(ctx context.Context, userID uint) error
+}
+
+type TwoFactorRepository interface {
+	StartTwoFactor(ctx context.Context, userID uint, jti string) (bool, error)
+	ConsumeTwoFactorChallenge(ctx context.Context, userID uint, jti string) error
+}
+
+// TwoFactorChecker also turns two-factor authentication off on Recover.
+type TwoFactorChecker interface {
+	Verify(ctx context.Context, userID uint, code string) error
+	Recover(ctx context.Context, userID uint, code string) error
+}
+
+// LoginLimiter returns a *lockout.LockedError from Check and Fail while a subject is locked.
+type LoginLimiter interface {
+	Check(ctx context.Context, subjects ...lockout.Subject) error
+	Fail(ctx context.Context, subjects ...lockout.Subject) error
+	Reset(ctx context.Context, subjects ...lockout.Subject)
+}
+
+// EventRecorder records the logins and refreshes of the users.
+type EventRecorder interface {
+	Record(ctx context.Context, event domain.SecurityEvent)
+}
+
 type UserValid struct {
 	Valid func(error) []validator.ValidationErrors
 }
 
 type UserServer struct {
-	log       *logrus.Logger
-	r         RegistrationuserRepository
-	d         DeleteUserRepository
-	a         AuthenticationUserRepository
-	validate  validator.Validate
-	SecretKey string
+	log      *logrus.Logger
+	r        RegistrationuserRepository
+	a        AuthenticationUserRepository
+	s        SessionRepository
+	v        VerificationSender
+	t        TwoFactorRepository
+	f        TwoFactorChecker
+	l        LoginLimiter
+	e        EventRecorder
+	policy   domain.RegistrationPolicy
+	validate validator.Validate
+	keys     *jwttoken.KeySet
 }
 
-func CreateUserServer(r RegistrationuserRepository, d DeleteUserRepository, a AuthenticationUserRepository, sk string,
-	log *logrus.Logger) *UserServer {
+func CreateUserServer(r RegistrationuserRepository, a AuthenticationUserRepository, s SessionRepository, v VerificationSender,
+	t TwoFactorRepository, f TwoFactorChecker, l LoginLimiter, e EventRecorder, policy domain.RegistrationPolicy, passwords passwordPolicy.Policy, keys *jwttoken.KeySet, log *logrus.Logger) *UserServer {
+	validate := validator.New()
+	passwordPolicy.Register(validate, passwords)
+
 	return &UserServer{
-		log:       log,
-		d:         d,
-		r:         r,
-		a:         a,
-		validate:  *validator.New(),
-		SecretKey: sk,
+		log:      log,
+		r:        r,
+		a:        a,
+		s:        s,
+		v:        v,
+		t:        t,
+		f:        f,
+		l:        l,
+		e:        e,
+		policy:   policy,
+		validate: *validate,
+		keys:     keys,
 	}
 }
 
@@ -60,6 +113,12 @@ func (c *UserServer) RegistrationUser(ctx context.Context, us domain.RegisterUse
 		return jwttoken.ResponseJWTUser{}, err
 	}
 
+	inviteHash, err := c.allowRegistration(us)
+	if err != nil {
+		log.WithField("err", err).Error("registration not allowed")
+		return jwttoken.ResponseJWTUser{}, err
+	}
+
 	passwordHash, err := hashPassword.Hash(us.Password)
 	if err != nil {
 		log.WithFields(logrus.Fields{"err": err}).Error("field hash password")
@@ -73,16 +132,21 @@ func (c *UserServer) RegistrationUser(ctx context.Context, us domain.RegisterUse
 		PasswordHash: passwordHash,
 	}
 
-	id, name, err := c.r.RegistrationUser(ctx, user)
+	id, name, err := c.r.RegistrationUser(ctx, user, inviteHash)
 	if err != nil {
 		log.Error("error registration user: ", err)
 		return jwttoken.ResponseJWTUser{}, RegisterErrDatabase(err)
 	}
 
-	tokens, err := jwttoken.PostJWT(c.SecretKey, id, name)
+	// the user is registered anyway, the mail can be sent again from the account
+	if err := c.v.SendVerification(ctx, id); err != nil {
+		log.WithField("err", err).Warn("field send verification")
+	}
+
+	tokens, err := c.newSession(ctx, id, name, us.Device)
 	if err != nil {
-		log.WithField("err", err).Error("field create JWT token")
-		return jwttoken.ResponseJWTUser{}, ErrServic
+		log.WithField("err", err).Error("field create session")
+		return jwttoken.ResponseJWTUser{}, err
 	}
 
 	log.Info("success registration user")
@@ -90,6 +154,29 @@ func (c *UserServer) RegistrationUser(ctx context.Context, us domain.RegisterUse
 	return tokens, nil
 }
 
+// allowRegistration returns the hash of the invite code to use, empty when none is needed.
+func (c *UserServer) allowRegistration(us domain.RegisterUser) (string, error) {
+	if c.policy.Admin(us.Email) {
+		return "", nil
+	}
+
+	if !c.policy.DomainAllowed(us.Email) {
+		return "", ErrEmailDomain
+	}
+
+	switch c.policy.Mode {
+	case domain.RegistrationClosed:
+		return "", ErrRegistrationClosed
+	case domain.RegistrationInvite:
+		if us.InviteCode == "" {
+			return "", ErrInvite
+		}
+		return mailToken.Hash(strings.TrimSpace(us.InviteCode)), nil
+	}
+
+	return "", nil
+}
+
 func (c *UserServer) AuthenticationUser(ctx context.Context, us domain.AuthenticationUser) (jwttoken.ResponseJWTUser, error) {
 	const op = "user.ServerAuthenticationUser"
 
@@ -103,43 +190,374 @@ func (c *UserServer) AuthenticationUser(ctx context.Context, us domain.Authentic
 		return jwttoken.ResponseJWTUser{}, err
 	}
 
+	subjects := []lockout.Subject{lockout.Email(us.Email), lockout.IP(us.Device.IP)}
+	if err := c.l.Check(ctx, subjects...); err != nil {
+		log.WithField("err", err).Warn("login locked")
+		c.failLogin(ctx, domain.SecurityEvent{Email: us.Email, Device: us.Device}, err)
+		return jwttoken.ResponseJWTUser{}, err
+	}
+
 	id, name, err := c.a.AuthenticationUser(ctx, us.Email, us.Password)
 	if err != nil {
 		log.Error("error authentication user: ", err)
-		return jwttoken.ResponseJWTUser{}, RegisterErrDatabase(err)
+		err = RegisterErrDatabase(err)
+		if errors.Is(err, ErrNoFound) || errors.Is(err, ErrPassword) {
+			if locked := c.l.Fail(ctx, subjects...); locked != nil {
+				err = locked
+			}
+		}
+		c.failLogin(ctx, domain.SecurityEvent{Email: us.Email, Device: us.Device}, err)
+		return jwttoken.ResponseJWTUser{}, err
 	}
 
-	token, err := jwttoken.PostJWT(c.SecretKey, id, name)
+	c.l.Reset(ctx, lockout.Email(us.Email))
+
+	token, err := c.Login(ctx, id, name, us.Device)
 	if err != nil {
-		log.WithField("err", err).Error("field create JWT token")
+		return jwttoken.ResponseJWTUser{}, err
+	}
+
+	log.Info("success authentication user")
+
+	return token, nil
+}
+
+// Login returns the challenge token when two-factor authentication is on.
+func (c *UserServer) Login(ctx context.Context, id uint, name string, device domain.Device) (jwttoken.ResponseJWTUser, error) {
+	const op = "user.Login"
+
+	log := c.log.WithFields(logrus.Fields{
+		"op":      op,
+		"user_id": id,
+	})
 
+	jti, err := jwttoken.NewJTI()
+	if err != nil {
+		log.WithField("err", err).Error("field create jti")
 		return jwttoken.ResponseJWTUser{}, ErrServic
 	}
 
-	log.Info("success authentication user")
+	twoFactor, err := c.t.StartTwoFactor(ctx, id, jti)
+	if err != nil {
+		log.Error("error start two-factor: ", err)
+		return jwttoken.ResponseJWTUser{}, ErrDatabase
+	}
+
+	if twoFactor {
+		challenge, err := c.keys.JWTChallengeToken(id, name, jti)
+		if err != nil {
+			log.WithField("err", err).Error("field create challenge token")
+			return jwttoken.ResponseJWTUser{}, ErrServic
+		}
+
+		log.Info("two-factor challenge issued")
+
+		return jwttoken.ResponseJWTUser{ChallengeToken: challenge}, nil
+	}
+
+	token, err := c.newSession(ctx, id, name, device)
+	if err != nil {
+		log.WithField("err", err).Error("field create session")
+
+		return jwttoken.ResponseJWTUser{}, err
+	}
 
 	return token, nil
 }
 
-func (c *UserServer) DeleteUser(ctx context.Context, us domain.DeleteUser) error {
-	const op = "user.ServerDeleteUser"
+// TwoFactorLogin spends the challenge on the first attempt, a wrong code means logging in again.
+func (c *UserServer) TwoFactorLogin(ctx context.Context, req domain.TwoFactorLogin) (jwttoken.ResponseJWTUser, error) {
+	const op = "user.ServerTwoFactorLogin"
 
 	log := c.log.WithField("op", op)
 
-	log.Info("start delete user")
+	log.Info("start two-factor login")
 
-	if err := c.validate.Struct(us); err != nil {
+	if err := c.validate.Struct(req); err != nil {
+		log.WithField("err", err).Error("invalid validate")
+		return jwttoken.ResponseJWTUser{}, err
+	}
+
+	claims, err := c.consumeChallenge(ctx, req.ChallengeToken)
+	if err != nil {
+		log.WithField("err", err).Error("invalid challenge")
+		return jwttoken.ResponseJWTUser{}, err
+	}
+
+	log = log.WithField("user_id", claims.Id)
+
+	subjects := []lockout.Subject{lockout.User(claims.Id), lockout.IP(req.Device.IP)}
+	if err := c.l.Check(ctx, subjects...); err != nil {
+		log.WithField("err", err).Warn("login locked")
+		c.failLogin(ctx, domain.SecurityEvent{UserID: claims.Id, Device: req.Device}, err)
+		return jwttoken.ResponseJWTUser{}, err
+	}
+
+	if err := c.f.Verify(ctx, claims.Id, req.Code); err != nil {
+		log.WithField("err", err).Error("error verify code")
+		err = c.failTwoFactor(ctx, err, subjects)
+		c.failLogin(ctx, domain.SecurityEvent{UserID: claims.Id, Device: req.Device}, err)
+		return jwttoken.ResponseJWTUser{}, err
+	}
+
+	c.l.Reset(ctx, lockout.User(claims.Id))
+
+	tokens, err := c.newSession(ctx, claims.Id, claims.Name, req.Device)
+	if err != nil {
+		log.WithField("err", err).Error("field create session")
+		return jwttoken.ResponseJWTUser{}, err
+	}
+
+	log.Info("success two-factor login")
+
+	return tokens, nil
+}
+
+func (c *UserServer) RecoverTwoFactor(ctx context.Context, req domain.TwoFactorLogin) (jwttoken.ResponseJWTUser, error) {
+	const op = "user.ServerRecoverTwoFactor"
+
+	log := c.log.WithField("op", op)
+
+	log.Info("start recover two-factor")
+
+	if err := c.validate.Struct(req); err != nil {
 		log.WithField("err", err).Error("invalid validate")
+		return jwttoken.ResponseJWTUser{}, err
+	}
+
+	claims, err := c.consumeChallenge(ctx, req.ChallengeToken)
+	if err != nil {
+		log.WithField("err", err).Error("invalid challenge")
+		return jwttoken.ResponseJWTUser{}, err
+	}
 
+	log = log.WithField("user_id", claims.Id)
+
+	subjects := []lockout.Subject{lockout.User(claims.Id), lockout.IP(req.Device.IP)}
+	if err := c.l.Check(ctx, subjects...); err != nil {
+		log.WithField("err", err).Warn("login locked")
+		c.failLogin(ctx, domain.SecurityEvent{UserID: claims.Id, Device: req.Device}, err)
+		return jwttoken.ResponseJWTUser{}, err
+	}
+
+	if err := c.f.Recover(ctx, claims.Id, req.Code); err != nil {
+		log.WithField("err", err).Error("error recover two-factor")
+		err = c.failTwoFactor(ctx, err, subjects)
+		c.failLogin(ctx, domain.SecurityEvent{UserID: claims.Id, Device: req.Device}, err)
+		return jwttoken.ResponseJWTUser{}, err
+	}
+
+	c.l.Reset(ctx, lockout.User(claims.Id))
+
+	c.e.Record(ctx, domain.SecurityEvent{
+		UserID:  claims.Id,
+		Type:    domain.EventTwoFactorDisable,
+		Success: true,
+		Reason:  "recovery code",
+		Device:  req.Device,
+	})
+
+	tokens, err := c.newSession(ctx, claims.Id, claims.Name, req.Device)
+	if err != nil {
+		log.WithField("err", err).Error("field create session")
+		return jwttoken.ResponseJWTUser{}, err
+	}
+
+	log.Info("success recover two-factor")
+
+	return tokens, nil
+}
+
+// RefreshTokens revokes the session when an already rotated token is presented.
+func (c *UserServer) RefreshTokens(ctx context.Context, refreshToken string, device domain.Device) (jwttoken.ResponseJWTUser, error) {
+	const op = "user.ServerRefreshTokens"
+
+	log := c.log.WithField("op", op)
+
+	log.Info("start refresh tokens")
+
+	claims, err := c.keys.CheckAccess(refreshToken, c.log)
+	if err != nil {
+		log.WithField("err", err).Error("invalid refresh token")
+		return jwttoken.ResponseJWTUser{}, ErrToken
+	}
+
+	log = log.WithFields(logrus.Fields{
+		"user_id":    claims.Id,
+		"session_id": claims.SessionID,
+	})
+
+	jti, err := jwttoken.NewJTI()
+	if err != nil {
+		log.WithField("err", err).Error("field create jti")
+		return jwttoken.ResponseJWTUser{}, ErrServic
+	}
+
+	err = c.s.RotateSession(ctx, claims.Id, claims.SessionID, claims.ID, jti, time.Now().Add(jwttoken.RefreshTTL), device)
+	if err != nil {
+		log.Error("error rotate session: ", err)
+		switch {
+		case errors.Is(err, postgresql.ErrorReused):
+			log.Warn("refresh token reuse detected, session revoked")
+			c.e.Record(ctx, domain.SecurityEvent{
+				UserID: claims.Id,
+				Type:   domain.EventRefresh,
+				Reason: ErrTokenReused.Error(),
+				Device: device,
+			})
+			return jwttoken.ResponseJWTUser{}, ErrTokenReused
+		case errors.Is(err, postgresql.ErrorNotFound), errors.Is(err, postgresql.ErrorRevoked):
+			return jwttoken.ResponseJWTUser{}, ErrToken
+		}
+		return jwttoken.ResponseJWTUser{}, ErrDatabase
+	}
+
+	tokens, err := c.keys.PostJWT(claims.Id, claims.Name, claims.SessionID, jti)
+	if err != nil {
+		log.WithField("err", err).Error("field create JWT token")
+		return jwttoken.ResponseJWTUser{}, ErrServic
+	}
+
+	c.e.Record(ctx, domain.SecurityEvent{
+		UserID:  claims.Id,
+		Type:    domain.EventRefresh,
+		Success: true,
+		Device:  device,
+	})
+
+	log.Info("success refresh tokens")
+
+	return tokens, nil
+}
+
+func (c *UserServer) Logout(ctx context.Context, userID uint, sessionID uint) error {
+	const op = "user.ServerLogout"
+
+	log := c.log.WithFields(logrus.Fields{
+		"op":         op,
+		"user_id":    userID,
+		"session_id": sessionID,
+	})
+
+	log.Info("start logout")
+
+	if err := c.RevokeSession(ctx, userID, sessionID); err != nil {
 		return err
 	}
 
-	err := c.d.DeleteUser(ctx, us.Email, us.Password)
+	log.Info("success logout")
+
+	return nil
+}
+
+func (c *UserServer) RevokeSession(ctx context.Context, userID uint, sessionID uint) error {
+	const op = "user.ServerRevokeSession"
+
+	log := c.log.WithFields(logrus.Fields{
+		"op":         op,
+		"user_id":    userID,
+		"session_id": sessionID,
+	})
+
+	log.Info("start revoke session")
+
+	err := c.s.RevokeSession(ctx, userID, sessionID)
 	if err != nil {
-		log.Error("error delete user: ", err)
-		return RegisterErrDatabase(err)
+		log.Error("error revoke session: ", err)
+		if errors.Is(err, postgresql.ErrorNotFound) {
+			return ErrSessionNotFound
+		}
+		return ErrDatabase
 	}
 
-	log.Info("success delete user")
+	log.Info("success revoke session")
+
 	return nil
 }
+
+func (c *UserServer) ListSessions(ctx context.Context, userID uint, currentSessionID uint) ([]domain.Session, error) {
+	const op = "user.ServerListSessions"
+
+	log := c.log.WithFields(logrus.Fields{
+		"op":      op,
+		"user_id": userID,
+	})
+
+	log.Info("start list sessions")
+
+	sessions, err := c.s.UserSessions(ctx, userID)
+	if err != nil {
+		log.Error("error get sessions: ", err)
+		return nil, ErrDatabase
+	}
+
+	for i := range sessions {
+		sessions[i].Current = sessions[i].ID == currentSessionID
+	}
+
+	log.Info("success list sessions")
+
+	return sessions, nil
+}
+
+func (c *UserServer) failTwoFactor(ctx context.Context, err error, subjects []lockout.Subject) error {
+	if !errors.Is(err, twofactor.ErrCode) && !errors.Is(err, twofactor.ErrRecoveryCode) {
+		return err
+	}
+
+	if locked := c.l.Fail(ctx, subjects...); locked != nil {
+		return locked
+	}
+
+	return err
+}
+
+func (c *UserServer) failLogin(ctx context.Context, event domain.SecurityEvent, err error) {
+	event.Type = domain.EventLogin
+	event.Reason = err.Error()
+	c.e.Record(ctx, event)
+}
+
+func (c *UserServer) consumeChallenge(ctx context.Context, challenge string) (*jwttoken.Claims, error) {
+	claims, err := c.keys.Parse(challenge, jwttoken.TypeChallenge)
+	if err != nil {
+		return nil, ErrChallenge
+	}
+
+	err = c.t.ConsumeTwoFactorChallenge(ctx, claims.Id, claims.ID)
+	if err != nil {
+		if errors.Is(err, postgresql.ErrorNotFound) {
+			return nil, ErrChallenge
+		}
+		return nil, ErrDatabase
+	}
+
+	return claims, nil
+}
+
+// newSession is where every login succeeds.
+func (c *UserServer) newSession(ctx context.Context, id uint, name string, device domain.Device) (jwttoken.ResponseJWTUser, error) {
+	jti, err := jwttoken.NewJTI()
+	if err != nil {
+		return jwttoken.ResponseJWTUser{}, ErrServic
+	}
+
+	sessionID, err := c.s.CreateSession(ctx, id, jti, time.Now().Add(jwttoken.RefreshTTL), device)
+	if err != nil {
+		return jwttoken.ResponseJWTUser{}, ErrDatabase
+	}
+
+	tokens, err := c.keys.PostJWT(id, name, sessionID, jti)
+	if err != nil {
+		return jwttoken.ResponseJWTUser{}, ErrServic
+	}
+
+	c.e.Record(ctx, domain.SecurityEvent{
+		UserID:  id,
+		Type:    domain.EventLogin,
+		Success: true,
+		Device:  device,
+	})
+
+	return tokens, nil
+}
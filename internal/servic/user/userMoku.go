@@ -2,8 +2,10 @@ package user
 
 import (
 	"context"
+	"time"
 
 	"github.com/financial_tracer/internal/domain"
+	"github.com/financial_tracer/internal/servic/lockout"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -11,17 +13,83 @@ type DbMock struct {
 	mock.Mock
 }
 
-func (d *DbMock) RegistrationUser(ctx context.Context, user domain.User) (uint, string, error) {
-	args := d.Called(ctx, user)
+func (d *DbMock) RegistrationUser(ctx context.Context, user domain.User, inviteHash string) (uint, string, error) {
+	args := d.Called(ctx, user, inviteHash)
 	return args.Get(0).(uint), args.String(1), args.Error(2)
 }
 
-func (d *DbMock) DeleteUser(ctx context.Context, email string, password string) error {
+func (d *DbMock) AuthenticationUser(ctx context.Context, email string, password string) (uint, string, error) {
 	args := d.Called(ctx, email, password)
+	return args.Get(0).(uint), args.String(1), args.Error(2)
+}
+
+func (d *DbMock) CreateSession(ctx context.Context, userID uint, jti string, expiresAt time.Time, device domain.Device) (uint, error) {
+	args := d.Called(ctx, userID, jti, expiresAt, device)
+	return args.Get(0).(uint), args.Error(1)
+}
+
+func (d *DbMock) RotateSession(ctx context.Context, userID uint, sessionID uint, jti string, newJti string, expiresAt time.Time, device domain.Device) error {
+	args := d.Called(ctx, userID, sessionID, jti, newJti, expiresAt, device)
 	return args.Error(0)
 }
 
-func (d *DbMock) AuthenticationUser(ctx context.Context, email string, password string) (uint, string, error) {
-	args := d.Called(ctx, email, password)
-	return args.Get(0).(uint), args.String(1), args.Error(2)
+func (d *DbMock) RevokeSession(ctx context.Context, userID uint, sessionID uint) error {
+	args := d.Called(ctx, userID, sessionID)
+	return args.Error(0)
+}
+
+func (d *DbMock) UserSessions(ctx context.Context, userID uint) ([]domain.Session, error) {
+	args := d.Called(ctx, userID)
+	return args.Get(0).([]domain.Session), args.Error(1)
+}
+
+func (d *DbMock) StartTwoFactor(ctx context.Context, userID uint, jti string) (bool, error) {
+	args := d.Called(ctx, userID, jti)
+	return args.Bool(0), args.Error(1)
+}
+
+func (d *DbMock) ConsumeTwoFactorChallenge(ctx context.Context, userID uint, jti string) error {
+	args := d.Called(ctx, userID, jti)
+	return args.Error(0)
+}
+
+type VerificationMock struct {
+	mock.Mock
+}
+
+func (v *VerificationMock) SendVerification(ctx context.Context, userID uint) error {
+	args := v.Called(ctx, userID)
+	return args.Error(0)
+}
+
+type TwoFactorMock struct {
+	mock.Mock
+}
+
+func (t *TwoFactorMock) Verify(ctx context.Context, userID uint, code string) error {
+	args := t.Called(ctx, userID, code)
+	return args.Error(0)
+}
+
+func (t *TwoFactorMock) Recover(ctx context.Context, userID uint, code string) error {
+	args := t.Called(ctx, userID, code)
+	return args.Error(0)
+}
+
+type LimiterMock struct {
+	mock.Mock
+}
+
+func (l *LimiterMock) Check(ctx context.Context, subjects ...lockout.Subject) error {
+	args := l.Called(ctx, subjects)
+	return args.Error(0)
+}
+
+func (l *LimiterMock) Fail(ctx context.Context, subjects ...lockout.Subject) error {
+	args := l.Called(ctx, subjects)
+	return args.Error(0)
+}
+
+func (l *LimiterMock) Reset(ctx context.Context, subjects ...lockout.Subject) {
+	l.Called(ctx, subjects)
 }
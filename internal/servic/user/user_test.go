@@ -4,9 +4,16 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/financial_tracer/internal/domain"
 	"github.com/financial_tracer/internal/infastructure/db/postgresql"
+	jwttoken "github.com/financial_tracer/internal/lib/jwtToken"
+	"github.com/financial_tracer/internal/lib/mailToken"
+	"github.com/financial_tracer/internal/lib/passwordPolicy"
+	"github.com/financial_tracer/internal/servic/lockout"
+	"github.com/financial_tracer/internal/servic/security"
+	"github.com/financial_tracer/internal/servic/twofactor"
 	"github.com/go-playground/validator/v10"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
@@ -80,9 +87,11 @@ func TestServerRegistrationUser(t *testing.T) {
 			repoMock := new(DbMock)
 			log := logrus.New()
 
-			repoMock.On("RegistrationUser", mock.Anything, mock.AnythingOfType("domain.User")).Return(test.userID, test.user.Name, test.mokuErr)
+			repoMock.On("RegistrationUser", mock.Anything, mock.AnythingOfType("domain.User"), "").Return(test.userID, test.user.Name, test.mokuErr)
+			repoMock.On("CreateSession", mock.Anything, test.userID, mock.Anything, mock.Anything, test.user.Device).Return(uint(1), nil)
+			verify := verificationMock()
 
-			server := CreateUserServer(repoMock, repoMock, repoMock, "secret", log)
+			server := CreateUserServer(repoMock, repoMock, repoMock, verify, repoMock, new(TwoFactorMock), limiterMock(), new(security.EventMock), domain.RegistrationPolicy{}, passwordPolicy.Policy{}, testKeys(t), log)
 			tokens, err := server.RegistrationUser(context.Background(), test.user)
 
 			if test.mokuErr != nil || test.userErr != nil {
@@ -102,10 +111,100 @@ func TestServerRegistrationUser(t *testing.T) {
 				assert.NoError(t, err)
 				assert.NotEmpty(t, tokens.AccessToken)
 				assert.NotEmpty(t, tokens.RefreshToken)
+				verify.AssertCalled(t, "SendVerification", mock.Anything, test.userID)
 			}
 
 			if test.shouldCallDB {
-				repoMock.AssertCalled(t, "RegistrationUser", mock.Anything, mock.AnythingOfType("domain.User"))
+				repoMock.AssertCalled(t, "RegistrationUser", mock.Anything, mock.AnythingOfType("domain.User"), "")
+			}
+		})
+	}
+}
+
+func TestServerRegistrationPasswordPolicy(t *testing.T) {
+	tests := []struct {
+		name     string
+		password string
+		policy   passwordPolicy.Policy
+		tag      string
+	}{
+		{name: "error short", password: "Ab1:x", tag: passwordPolicy.TagLength},
+		{name: "error classes", password: "gpdijgpoghihg", policy: passwordPolicy.Policy{MinClasses: 2}, tag: passwordPolicy.TagClasses},
+		{name: "error name", password: "jonnsina2024!", tag: passwordPolicy.TagPersonal},
+		{name: "error email", password: "Jonn12-secret", tag: passwordPolicy.TagPersonal},
+	}
+
+	for _, ts := range tests {
+		t.Run(ts.name, func(t *testing.T) {
+			repoMock := new(DbMock)
+
+			server := CreateUserServer(repoMock, repoMock, repoMock, verificationMock(), repoMock, new(TwoFactorMock), limiterMock(), new(security.EventMock), domain.RegistrationPolicy{}, ts.policy, testKeys(t), logrus.New())
+			_, err := server.RegistrationUser(context.Background(), domain.RegisterUser{Name: "jonnsina", Email: "jonn12@gmail.com", Password: ts.password})
+
+			var validErr validator.ValidationErrors
+			if assert.ErrorAs(t, err, &validErr) {
+				assert.Equal(t, "Password", validErr[0].Field())
+				assert.Equal(t, ts.tag, validErr[0].Tag())
+			}
+			repoMock.AssertNotCalled(t, "RegistrationUser", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestServerRegistrationUserMailError(t *testing.T) {
+	user := domain.RegisterUser{Name: "jonnsina", Email: "jonn12@gmail.com", Password: "fgpDIJGP:OGhiHG"}
+
+	repoMock := new(DbMock)
+	repoMock.On("RegistrationUser", mock.Anything, mock.AnythingOfType("domain.User"), "").Return(uint(1), user.Name, nil)
+	repoMock.On("CreateSession", mock.Anything, uint(1), mock.Anything, mock.Anything, user.Device).Return(uint(1), nil)
+	verify := new(VerificationMock)
+	verify.On("SendVerification", mock.Anything, uint(1)).Return(errors.New("error send mail"))
+
+	server := CreateUserServer(repoMock, repoMock, repoMock, verify, repoMock, new(TwoFactorMock), limiterMock(), new(security.EventMock), domain.RegistrationPolicy{}, passwordPolicy.Policy{}, testKeys(t), logrus.New())
+	tokens, err := server.RegistrationUser(context.Background(), user)
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, tokens.AccessToken)
+}
+
+func TestServerRegistrationPolicy(t *testing.T) {
+	tests := []struct {
+		name       string
+		policy     domain.RegistrationPolicy
+		email      string
+		inviteCode string
+		inviteHash string
+		mockErr    error
+		userErr    error
+	}{
+		{name: "open", policy: domain.RegistrationPolicy{Mode: domain.RegistrationOpen}, email: "jonn@gmail.com", inviteCode: "ignored"},
+		{name: "default is open", email: "jonn@gmail.com"},
+		{name: "closed", policy: domain.RegistrationPolicy{Mode: domain.RegistrationClosed}, email: "jonn@gmail.com", userErr: ErrRegistrationClosed},
+		{name: "closed admin", policy: domain.RegistrationPolicy{Mode: domain.RegistrationClosed, Admins: []string{"Root@Tracker.local"}}, email: "root@tracker.local"},
+		{name: "invite", policy: domain.RegistrationPolicy{Mode: domain.RegistrationInvite}, email: "jonn@gmail.com", inviteCode: " code ", inviteHash: mailToken.Hash("code")},
+		{name: "invite without code", policy: domain.RegistrationPolicy{Mode: domain.RegistrationInvite}, email: "jonn@gmail.com", userErr: ErrInvite},
+		{name: "invite used up", policy: domain.RegistrationPolicy{Mode: domain.RegistrationInvite}, email: "jonn@gmail.com", inviteCode: "code", inviteHash: mailToken.Hash("code"), mockErr: postgresql.ErrorInvite, userErr: ErrInvite},
+		{name: "allowed domain", policy: domain.RegistrationPolicy{Domains: []string{"@Tracker.local"}}, email: "jonn@tracker.local"},
+		{name: "error domain", policy: domain.RegistrationPolicy{Domains: []string{"tracker.local"}}, email: "jonn@gmail.com", userErr: ErrEmailDomain},
+		{name: "error subdomain", policy: domain.RegistrationPolicy{Domains: []string{"tracker.local"}}, email: "jonn@evil.tracker.local", userErr: ErrEmailDomain},
+	}
+
+	for _, ts := range tests {
+		t.Run(ts.name, func(t *testing.T) {
+			user := domain.RegisterUser{Name: "jonnsina", Email: ts.email, Password: "fgpDIJGP:OGhiHG", InviteCode: ts.inviteCode}
+
+			repoMock := new(DbMock)
+			repoMock.On("RegistrationUser", mock.Anything, mock.AnythingOfType("domain.User"), ts.inviteHash).Return(uint(1), user.Name, ts.mockErr)
+			repoMock.On("CreateSession", mock.Anything, uint(1), mock.Anything, mock.Anything, user.Device).Return(uint(1), nil)
+
+			server := CreateUserServer(repoMock, repoMock, repoMock, verificationMock(), repoMock, new(TwoFactorMock), limiterMock(), new(security.EventMock), ts.policy, passwordPolicy.Policy{}, testKeys(t), logrus.New())
+			_, err := server.RegistrationUser(context.Background(), user)
+
+			assert.ErrorIs(t, err, ts.userErr)
+			if ts.userErr == nil || ts.mockErr != nil {
+				repoMock.AssertCalled(t, "RegistrationUser", mock.Anything, mock.AnythingOfType("domain.User"), ts.inviteHash)
+			} else {
+				repoMock.AssertNotCalled(t, "RegistrationUser", mock.Anything, mock.Anything, mock.Anything)
 			}
 		})
 	}
@@ -128,6 +227,7 @@ func TestServerAuthenticationUser(t *testing.T) {
 			inputUser: domain.AuthenticationUser{
 				Email:    "jonn1342@gmail.com",
 				Password: "admin12241532",
+				Device:   domain.Device{UserAgent: "curl/8.5.0", IP: "127.0.0.1"},
 			},
 			userID:       3,
 			nameUser:     "jonn",
@@ -180,8 +280,11 @@ func TestServerAuthenticationUser(t *testing.T) {
 
 			repoMock.On("AuthenticationUser", mock.Anything, ts.inputUser.Email, ts.inputUser.Password).
 				Return(ts.userID, ts.nameUser, ts.mokuErr)
+			repoMock.On("CreateSession", mock.Anything, ts.userID, mock.Anything, mock.Anything, ts.inputUser.Device).Return(uint(1), nil)
+			repoMock.On("StartTwoFactor", mock.Anything, ts.userID, mock.Anything).Return(false, nil)
 
-			server := CreateUserServer(repoMock, repoMock, repoMock, "secret", log)
+			events := new(security.EventMock)
+			server := CreateUserServer(repoMock, repoMock, repoMock, verificationMock(), repoMock, new(TwoFactorMock), limiterMock(), events, domain.RegistrationPolicy{}, passwordPolicy.Policy{}, testKeys(t), log)
 			tokens, err := server.AuthenticationUser(context.Background(), ts.inputUser)
 
 			if ts.mokuErr != nil || ts.userErr != nil {
@@ -205,93 +308,414 @@ func TestServerAuthenticationUser(t *testing.T) {
 
 			if ts.shouldCallDB {
 				repoMock.AssertCalled(t, "AuthenticationUser", mock.Anything, ts.inputUser.Email, ts.inputUser.Password)
+				assert.Len(t, events.Events, 1)
+				assert.Equal(t, domain.EventLogin, events.Events[0].Type)
+				assert.Equal(t, ts.userErr == nil, events.Events[0].Success)
+				if ts.userErr != nil {
+					assert.Equal(t, ts.inputUser.Email, events.Events[0].Email)
+					assert.Equal(t, ts.userErr.Error(), events.Events[0].Reason)
+				} else {
+					assert.Equal(t, ts.userID, events.Events[0].UserID)
+					assert.Equal(t, ts.inputUser.Device, events.Events[0].Device)
+				}
+			}
+		})
+	}
+}
+
+func TestServerAuthenticationLockout(t *testing.T) {
+	user := domain.AuthenticationUser{
+		Email:    "jonn@gmail.com",
+		Password: "admin12241532",
+		Device:   domain.Device{IP: "127.0.0.1"},
+	}
+	subjects := []lockout.Subject{lockout.Email(user.Email), lockout.IP(user.Device.IP)}
+	locked := &lockout.LockedError{RetryAfter: time.Minute}
+
+	tests := []struct {
+		name         string
+		checkErr     error
+		mokuErr      error
+		failErr      error
+		wantErr      error
+		shouldCallDB bool
+		shouldFail   bool
+	}{
+		{name: "success resets", shouldCallDB: true},
+		{name: "error wrong password", mokuErr: postgresql.ErrorPassword, wantErr: ErrPassword, shouldCallDB: true, shouldFail: true},
+		{name: "error not found", mokuErr: postgresql.ErrorNotFound, wantErr: ErrNoFound, shouldCallDB: true, shouldFail: true},
+		{name: "error disabled is not counted", mokuErr: postgresql.ErrorDisabled, wantErr: ErrDisabled, shouldCallDB: true},
+		{name: "error database is not counted", mokuErr: errors.New("error database"), wantErr: ErrDatabase, shouldCallDB: true},
+		{name: "error locked by this failure", mokuErr: postgresql.ErrorPassword, failErr: locked, wantErr: lockout.ErrLocked, shouldCallDB: true, shouldFail: true},
+		{name: "error locked", checkErr: locked, wantErr: lockout.ErrLocked},
+	}
+
+	for _, ts := range tests {
+		t.Run(ts.name, func(t *testing.T) {
+			repoMock := new(DbMock)
+			repoMock.On("AuthenticationUser", mock.Anything, user.Email, user.Password).Return(uint(3), "jonn", ts.mokuErr)
+			repoMock.On("StartTwoFactor", mock.Anything, uint(3), mock.Anything).Return(false, nil)
+			repoMock.On("CreateSession", mock.Anything, uint(3), mock.Anything, mock.Anything, user.Device).Return(uint(1), nil)
+			limiter := new(LimiterMock)
+			limiter.On("Check", mock.Anything, subjects).Return(ts.checkErr)
+			limiter.On("Fail", mock.Anything, subjects).Return(ts.failErr)
+			limiter.On("Reset", mock.Anything, mock.Anything)
+
+			server := CreateUserServer(repoMock, repoMock, repoMock, verificationMock(), repoMock, new(TwoFactorMock), limiter, new(security.EventMock), domain.RegistrationPolicy{}, passwordPolicy.Policy{}, testKeys(t), logrus.New())
+			_, err := server.AuthenticationUser(context.Background(), user)
+
+			if ts.wantErr != nil {
+				assert.ErrorIs(t, err, ts.wantErr)
+				limiter.AssertNotCalled(t, "Reset", mock.Anything, mock.Anything)
+			} else {
+				assert.NoError(t, err)
+				limiter.AssertCalled(t, "Reset", mock.Anything, []lockout.Subject{lockout.Email(user.Email)})
+			}
+
+			if ts.shouldCallDB {
+				repoMock.AssertCalled(t, "AuthenticationUser", mock.Anything, user.Email, user.Password)
+			} else {
+				repoMock.AssertNotCalled(t, "AuthenticationUser", mock.Anything, mock.Anything, mock.Anything)
+			}
+			if ts.shouldFail {
+				limiter.AssertCalled(t, "Fail", mock.Anything, subjects)
+			} else {
+				limiter.AssertNotCalled(t, "Fail", mock.Anything, mock.Anything)
 			}
 		})
 	}
 }
 
-func TestServerDeleteUser(t *testing.T) {
+func TestServerRefreshTokens(t *testing.T) {
+	refreshToken, err := testKeys(t).JWTRefreshToken(3, "jonn", 7, "old-jti")
+	assert.NoError(t, err)
+
+	foreign, err := jwttoken.NewHMACKeySet("", "", "test", "other secret", nil)
+	assert.NoError(t, err)
+	foreignToken, err := foreign.JWTRefreshToken(3, "jonn", 7, "old-jti")
+	assert.NoError(t, err)
+
+	device := domain.Device{UserAgent: "Mozilla/5.0 (Android 14)", IP: "10.0.0.7"}
+
 	type test struct {
 		name         string
-		user         domain.DeleteUser
+		token        string
 		mockErr      error
 		userErr      error
 		shouldCallDB bool
+		event        bool
+		reason       string
 	}
 
-	arrTests := []test{
+	tests := []test{
 		{
-			name: "success",
-			user: domain.DeleteUser{
-				Email:    "jonn@gmail.com",
-				Password: "admin",
-			},
-			mockErr:      nil,
-			userErr:      nil,
+			name:         "success",
+			token:        refreshToken,
 			shouldCallDB: true,
+			event:        true,
 		},
 		{
-			name: "error database",
-			user: domain.DeleteUser{
-				Email:    "sasha@gmail.com",
-				Password: "admin15412",
-			},
-			mockErr:      errors.New("error database"),
-			userErr:      ErrDatabase,
+			name:         "error signature",
+			token:        foreignToken,
+			userErr:      ErrToken,
+			shouldCallDB: false,
+		},
+		{
+			name:         "error access token",
+			token:        mustAccessToken(t),
+			userErr:      ErrToken,
+			shouldCallDB: false,
+		},
+		{
+			name:         "error reused",
+			token:        refreshToken,
+			mockErr:      postgresql.ErrorReused,
+			userErr:      ErrTokenReused,
 			shouldCallDB: true,
+			event:        true,
+			reason:       ErrTokenReused.Error(),
 		},
 		{
-			name: "error not found",
-			user: domain.DeleteUser{
-				Email:    "jonn11@gmail.com",
-				Password: "adminov",
-			},
-			mockErr:      postgresql.ErrorNotFound,
-			userErr:      ErrNoFound,
+			name:         "error revoked",
+			token:        refreshToken,
+			mockErr:      postgresql.ErrorRevoked,
+			userErr:      ErrToken,
 			shouldCallDB: true,
 		},
 		{
-			name: "error valdiate",
-			user: domain.DeleteUser{
-				Email:    "jonn@gmail.com",
-				Password: "",
-			},
-			mockErr:      nil,
-			userErr:      validator.ValidationErrors{},
-			shouldCallDB: false,
+			name:         "error database",
+			token:        refreshToken,
+			mockErr:      errors.New("error database"),
+			userErr:      ErrDatabase,
+			shouldCallDB: true,
 		},
 	}
 
-	for _, ts := range arrTests {
+	for _, ts := range tests {
 		t.Run(ts.name, func(t *testing.T) {
 			repoMock := new(DbMock)
-			log := logrus.New()
+			repoMock.On("RotateSession", mock.Anything, uint(3), uint(7), "old-jti", mock.Anything, mock.Anything, device).Return(ts.mockErr)
 
-			repoMock.On("DeleteUser", mock.Anything, ts.user.Email, ts.user.Password).Return(ts.mockErr)
+			events := new(security.EventMock)
+			server := CreateUserServer(repoMock, repoMock, repoMock, verificationMock(), repoMock, new(TwoFactorMock), limiterMock(), events, domain.RegistrationPolicy{}, passwordPolicy.Policy{}, testKeys(t), logrus.New())
+			tokens, err := server.RefreshTokens(context.Background(), ts.token, device)
 
-			server := CreateUserServer(repoMock, repoMock, repoMock, "secret", log)
-			err := server.DeleteUser(context.Background(), ts.user)
+			if ts.event {
+				assert.Equal(t, []domain.SecurityEvent{{
+					UserID:  3,
+					Type:    domain.EventRefresh,
+					Success: ts.userErr == nil,
+					Reason:  ts.reason,
+					Device:  device,
+				}}, events.Events)
+			} else {
+				assert.Empty(t, events.Events)
+			}
 
-			if ts.mockErr != nil || ts.userErr != nil {
-				assert.Error(t, err)
-				if ts.name == "error valdiate" {
-					var ValidationErrors validator.ValidationErrors
+			if ts.userErr != nil {
+				assert.ErrorIs(t, err, ts.userErr)
+			} else {
+				assert.NoError(t, err)
+				claims, err := testKeys(t).CheckAccess(tokens.RefreshToken, logrus.New())
+				assert.NoError(t, err)
+				assert.Equal(t, uint(7), claims.SessionID)
+				assert.NotEqual(t, "old-jti", claims.ID)
+				assert.NotEmpty(t, tokens.AccessToken)
+			}
 
-					if !errors.As(err, &ValidationErrors) {
-						t.Error("err != validator.ValidatiobErrors")
-					}
+			if ts.shouldCallDB {
+				repoMock.AssertCalled(t, "RotateSession", mock.Anything, uint(3), uint(7), "old-jti", mock.Anything, mock.Anything, device)
+			} else {
+				repoMock.AssertNotCalled(t, "RotateSession", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func mustAccessToken(t *testing.T) string {
+	token, err := testKeys(t).JWTAccessToken(3, "jonn", 7)
+	assert.NoError(t, err)
+	return token
+}
+
+func testKeys(t *testing.T) *jwttoken.KeySet {
+	keys, err := jwttoken.NewHMACKeySet("", "", "test", "secret", nil)
+	assert.NoError(t, err)
+	return keys
+}
+
+func TestServerAuthenticationTwoFactor(t *testing.T) {
+	user := domain.AuthenticationUser{Email: "jonn@gmail.com", Password: "admin12241532"}
+
+	repoMock := new(DbMock)
+	repoMock.On("AuthenticationUser", mock.Anything, user.Email, user.Password).Return(uint(3), "jonn", nil)
+	repoMock.On("StartTwoFactor", mock.Anything, uint(3), mock.Anything).Return(true, nil)
+
+	keys := testKeys(t)
+	server := CreateUserServer(repoMock, repoMock, repoMock, verificationMock(), repoMock, new(TwoFactorMock), limiterMock(), new(security.EventMock), domain.RegistrationPolicy{}, passwordPolicy.Policy{}, keys, logrus.New())
+	tokens, err := server.AuthenticationUser(context.Background(), user)
+
+	assert.NoError(t, err)
+	assert.Empty(t, tokens.AccessToken)
+	assert.Empty(t, tokens.RefreshToken)
+
+	claims, err := keys.Parse(tokens.ChallengeToken, jwttoken.TypeChallenge)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(3), claims.Id)
+	assert.Equal(t, repoMock.Calls[1].Arguments.Get(2), claims.ID)
+	repoMock.AssertNotCalled(t, "CreateSession", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestServerTwoFactorLogin(t *testing.T) {
+	keys := testKeys(t)
+	challenge, err := keys.JWTChallengeToken(3, "jonn", "challenge")
+	assert.NoError(t, err)
+	access, err := keys.JWTAccessToken(3, "jonn", 1)
+	assert.NoError(t, err)
+
+	device := domain.Device{UserAgent: "curl/8.5.0", IP: "127.0.0.1"}
+
+	tests := []struct {
+		name         string
+		token        string
+		consumeErr   error
+		checkErr     error
+		wantErr      error
+		shouldCallDB bool
+	}{
+		{name: "success", token: challenge, shouldCallDB: true},
+		{name: "error wrong code", token: challenge, checkErr: errors.New("invalid two-factor code"), shouldCallDB: true},
+		{name: "error challenge already used", token: challenge, consumeErr: postgresql.ErrorNotFound, wantErr: ErrChallenge, shouldCallDB: true},
+		{name: "error access token as challenge", token: access, wantErr: ErrChallenge},
+	}
+
+	for _, ts := range tests {
+		for _, recovery := range []bool{false, true} {
+			t.Run(ts.name, func(t *testing.T) {
+				repoMock := new(DbMock)
+				repoMock.On("ConsumeTwoFactorChallenge", mock.Anything, uint(3), "challenge").Return(ts.consumeErr)
+				repoMock.On("CreateSession", mock.Anything, uint(3), mock.Anything, mock.Anything, device).Return(uint(5), nil)
+				checker := new(TwoFactorMock)
+				checker.On("Verify", mock.Anything, uint(3), "123456").Return(ts.checkErr)
+				checker.On("Recover", mock.Anything, uint(3), "123456").Return(ts.checkErr)
+
+				server := CreateUserServer(repoMock, repoMock, repoMock, verificationMock(), repoMock, checker, limiterMock(), new(security.EventMock), domain.RegistrationPolicy{}, passwordPolicy.Policy{}, keys, logrus.New())
+				req := domain.TwoFactorLogin{ChallengeToken: ts.token, Code: "123456", Device: device}
+
+				var tokens jwttoken.ResponseJWTUser
+				if recovery {
+					tokens, err = server.RecoverTwoFactor(context.Background(), req)
 				} else {
-					if !errors.Is(err, ts.userErr) {
-						t.Error("err != test.userErr")
-					}
+					tokens, err = server.TwoFactorLogin(context.Background(), req)
+				}
+
+				switch {
+				case ts.checkErr != nil:
+					assert.ErrorIs(t, err, ts.checkErr)
+				case ts.wantErr != nil:
+					assert.ErrorIs(t, err, ts.wantErr)
+				default:
+					assert.NoError(t, err)
+					claims, err := keys.Parse(tokens.AccessToken, jwttoken.TypeAccess)
+					assert.NoError(t, err)
+					assert.Equal(t, uint(5), claims.SessionID)
 				}
+
+				if ts.shouldCallDB {
+					repoMock.AssertCalled(t, "ConsumeTwoFactorChallenge", mock.Anything, uint(3), "challenge")
+				} else {
+					repoMock.AssertNotCalled(t, "ConsumeTwoFactorChallenge", mock.Anything, mock.Anything, mock.Anything)
+				}
+				if ts.checkErr != nil || ts.wantErr != nil {
+					repoMock.AssertNotCalled(t, "CreateSession", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+				}
+				if ts.wantErr != nil {
+					checker.AssertNotCalled(t, "Verify", mock.Anything, mock.Anything, mock.Anything)
+					checker.AssertNotCalled(t, "Recover", mock.Anything, mock.Anything, mock.Anything)
+				}
+			})
+		}
+	}
+}
+
+func TestServerTwoFactorLoginLockout(t *testing.T) {
+	keys := testKeys(t)
+	challenge, err := keys.JWTChallengeToken(3, "jonn", "challenge")
+	assert.NoError(t, err)
+
+	device := domain.Device{IP: "127.0.0.1"}
+	subjects := []lockout.Subject{lockout.User(3), lockout.IP(device.IP)}
+	locked := &lockout.LockedError{RetryAfter: time.Minute}
+
+	tests := []struct {
+		name       string
+		checkErr   error
+		codeErr    error
+		failErr    error
+		wantErr    error
+		shouldFail bool
+	}{
+		{name: "error wrong code", codeErr: twofactor.ErrCode, wantErr: twofactor.ErrCode, shouldFail: true},
+		{name: "error locked by this failure", codeErr: twofactor.ErrCode, failErr: locked, wantErr: lockout.ErrLocked, shouldFail: true},
+		{name: "error database is not counted", codeErr: twofactor.ErrDatabase, wantErr: twofactor.ErrDatabase},
+		{name: "error locked", checkErr: locked, wantErr: lockout.ErrLocked},
+	}
+
+	for _, ts := range tests {
+		t.Run(ts.name, func(t *testing.T) {
+			repoMock := new(DbMock)
+			repoMock.On("ConsumeTwoFactorChallenge", mock.Anything, uint(3), "challenge").Return(nil)
+			checker := new(TwoFactorMock)
+			checker.On("Verify", mock.Anything, uint(3), "123456").Return(ts.codeErr)
+			limiter := new(LimiterMock)
+			limiter.On("Check", mock.Anything, subjects).Return(ts.checkErr)
+			limiter.On("Fail", mock.Anything, subjects).Return(ts.failErr)
+
+			server := CreateUserServer(repoMock, repoMock, repoMock, verificationMock(), repoMock, checker, limiter, new(security.EventMock), domain.RegistrationPolicy{}, passwordPolicy.Policy{}, keys, logrus.New())
+			_, err := server.TwoFactorLogin(context.Background(), domain.TwoFactorLogin{ChallengeToken: challenge, Code: "123456", Device: device})
+
+			assert.ErrorIs(t, err, ts.wantErr)
+			if ts.checkErr != nil {
+				checker.AssertNotCalled(t, "Verify", mock.Anything, mock.Anything, mock.Anything)
+			}
+			if ts.shouldFail {
+				limiter.AssertCalled(t, "Fail", mock.Anything, subjects)
 			} else {
-				assert.NoError(t, err)
+				limiter.AssertNotCalled(t, "Fail", mock.Anything, mock.Anything)
 			}
+		})
+	}
+}
 
-			if ts.shouldCallDB {
-				repoMock.AssertCalled(t, "DeleteUser", mock.Anything, ts.user.Email, ts.user.Password)
+func verificationMock() *VerificationMock {
+	verify := new(VerificationMock)
+	verify.On("SendVerification", mock.Anything, mock.Anything).Return(nil)
+	return verify
+}
+
+func TestServerLogout(t *testing.T) {
+	tests := []struct {
+		name    string
+		mockErr error
+		userErr error
+	}{
+		{name: "success"},
+		{name: "error not found", mockErr: postgresql.ErrorNotFound, userErr: ErrSessionNotFound},
+		{name: "error database", mockErr: errors.New("error database"), userErr: ErrDatabase},
+	}
+
+	for _, ts := range tests {
+		t.Run(ts.name, func(t *testing.T) {
+			repoMock := new(DbMock)
+			repoMock.On("RevokeSession", mock.Anything, uint(3), uint(7)).Return(ts.mockErr)
+
+			server := CreateUserServer(repoMock, repoMock, repoMock, verificationMock(), repoMock, new(TwoFactorMock), limiterMock(), new(security.EventMock), domain.RegistrationPolicy{}, passwordPolicy.Policy{}, testKeys(t), logrus.New())
+			err := server.Logout(context.Background(), 3, 7)
+
+			if ts.userErr != nil {
+				assert.ErrorIs(t, err, ts.userErr)
+			} else {
+				assert.NoError(t, err)
 			}
+			repoMock.AssertCalled(t, "RevokeSession", mock.Anything, uint(3), uint(7))
 		})
 	}
 }
+
+func TestServerListSessions(t *testing.T) {
+	sessions := []domain.Session{
+		{ID: 7, Device: domain.Device{UserAgent: "Firefox", IP: "10.0.0.7"}},
+		{ID: 9, Device: domain.Device{UserAgent: "Android", IP: "10.0.0.9"}},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		repoMock := new(DbMock)
+		repoMock.On("UserSessions", mock.Anything, uint(3)).Return(sessions, nil)
+
+		server := CreateUserServer(repoMock, repoMock, repoMock, verificationMock(), repoMock, new(TwoFactorMock), limiterMock(), new(security.EventMock), domain.RegistrationPolicy{}, passwordPolicy.Policy{}, testKeys(t), logrus.New())
+		res, err := server.ListSessions(context.Background(), 3, 9)
+
+		assert.NoError(t, err)
+		assert.Len(t, res, 2)
+		assert.False(t, res[0].Current)
+		assert.True(t, res[1].Current)
+	})
+
+	t.Run("error database", func(t *testing.T) {
+		repoMock := new(DbMock)
+		repoMock.On("UserSessions", mock.Anything, uint(3)).Return([]domain.Session{}, errors.New("error database"))
+
+		server := CreateUserServer(repoMock, repoMock, repoMock, verificationMock(), repoMock, new(TwoFactorMock), limiterMock(), new(security.EventMock), domain.RegistrationPolicy{}, passwordPolicy.Policy{}, testKeys(t), logrus.New())
+		_, err := server.ListSessions(context.Background(), 3, 9)
+
+		assert.ErrorIs(t, err, ErrDatabase)
+	})
+}
+
+func limiterMock() *LimiterMock {
+	limiter := new(LimiterMock)
+	limiter.On("Check", mock.Anything, mock.Anything).Return(nil)
+	limiter.On("Fail", mock.Anything, mock.Anything).Return(nil)
+	limiter.On("Reset", mock.Anything, mock.Anything)
+	return limiter
+}
@@ -0,0 +1,12 @@
+package verification
+
+import "errors"
+
+var (
+	ErrDatabase    = errors.New("error database")
+	ErrServic      = errors.New("servic error")
+	ErrNoFound     = errors.New("user is not found")
+	ErrVerified    = errors.New("email already verified")
+	ErrVerifyToken = errors.New("invalid or expired verification token")
+	ErrMail        = errors.New("error send mail")
+)
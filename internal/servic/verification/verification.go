@@ -0,0 +1,129 @@
+package verification
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/financial_tracer/internal/infastructure/db/postgresql"
+	"github.com/financial_tracer/internal/lib/mailToken"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+)
+
+const DefaultTTL = 24 * time.Hour
+
+type VerificationRepository interface {
+	CreateEmailVerification(ctx context.Context, userID uint, tokenHash string, expiresAt time.Time) (domain.User, error)
+	VerifyEmail(ctx context.Context, tokenHash string) (uint, error)
+}
+
+type Mailer interface {
+	Send(ctx context.Context, msg domain.Mail) error
+}
+
+type Options struct {
+	TTL time.Duration
+	URL string
+}
+
+type VerificationServer struct {
+	r        VerificationRepository
+	m        Mailer
+	opt      Options
+	log      *logrus.Logger
+	validate validator.Validate
+}
+
+func CreateVerificationServer(r VerificationRepository, m Mailer, opt Options, log *logrus.Logger) *VerificationServer {
+	if opt.TTL == 0 {
+		opt.TTL = DefaultTTL
+	}
+
+	return &VerificationServer{
+		r:        r,
+		m:        m,
+		opt:      opt,
+		log:      log,
+		validate: *validator.New(),
+	}
+}
+
+// SendVerification keeps the tokens sent before valid until they expire.
+func (vs *VerificationServer) SendVerification(ctx context.Context, userID uint) error {
+	const op = "verification.SendVerification"
+
+	log := vs.log.WithFields(logrus.Fields{
+		"op":      op,
+		"user_id": userID,
+	})
+
+	log.Info("start send verification")
+
+	token, hash, err := mailToken.New()
+	if err != nil {
+		log.WithField("err", err).Error("field create verification token")
+		return ErrServic
+	}
+
+	user, err := vs.r.CreateEmailVerification(ctx, userID, hash, time.Now().Add(vs.opt.TTL))
+	if err != nil {
+		log.Error("error create verification: ", err)
+		switch {
+		case errors.Is(err, postgresql.ErrorVerified):
+			return ErrVerified
+		case errors.Is(err, postgresql.ErrorNotFound):
+			return ErrNoFound
+		}
+		return ErrDatabase
+	}
+
+	if err := vs.m.Send(ctx, vs.verificationMail(user, token)); err != nil {
+		log.WithField("err", err).Error("error send verification mail")
+		return ErrMail
+	}
+
+	log.Info("success send verification")
+
+	return nil
+}
+
+func (vs *VerificationServer) VerifyEmail(ctx context.Context, req domain.VerifyEmail) error {
+	const op = "verification.VerifyEmail"
+
+	log := vs.log.WithField("op", op)
+
+	log.Info("start verify email")
+
+	if err := vs.validate.Struct(req); err != nil {
+		log.WithField("err", err).Error("invalid validate")
+		return err
+	}
+
+	userID, err := vs.r.VerifyEmail(ctx, mailToken.Hash(req.Token))
+	if err != nil {
+		log.Error("error verify email: ", err)
+		if errors.Is(err, postgresql.ErrorNotFound) {
+			return ErrVerifyToken
+		}
+		return ErrDatabase
+	}
+
+	log.WithField("user_id", userID).Info("success verify email")
+
+	return nil
+}
+
+func (vs *VerificationServer) verificationMail(user domain.User, token string) domain.Mail {
+	return domain.Mail{
+		To:      user.Email,
+		Subject: "Подтверждение email financial_tracer",
+		Body: fmt.Sprintf("Здравствуйте, %s!\n\n"+
+			"Для подтверждения email перейдите по ссылке:\n%s\n\n"+
+			"Ссылка действует %s.\n"+
+			"Если вы не регистрировались в financial_tracer, просто проигнорируйте это письмо.\n",
+			user.Name, mailToken.Link(vs.opt.URL, token), vs.opt.TTL),
+	}
+}
@@ -0,0 +1,32 @@
+package verification
+
+import (
+	"context"
+	"time"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type DbMock struct {
+	mock.Mock
+}
+
+func (d *DbMock) CreateEmailVerification(ctx context.Context, userID uint, tokenHash string, expiresAt time.Time) (domain.User, error) {
+	args := d.Called(ctx, userID, tokenHash, expiresAt)
+	return args.Get(0).(domain.User), args.Error(1)
+}
+
+func (d *DbMock) VerifyEmail(ctx context.Context, tokenHash string) (uint, error) {
+	args := d.Called(ctx, tokenHash)
+	return args.Get(0).(uint), args.Error(1)
+}
+
+type MailerMock struct {
+	mock.Mock
+}
+
+func (m *MailerMock) Send(ctx context.Context, msg domain.Mail) error {
+	args := m.Called(ctx, msg)
+	return args.Error(0)
+}
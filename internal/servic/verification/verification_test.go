@@ -0,0 +1,139 @@
+package verification
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/financial_tracer/internal/domain"
+	"github.com/financial_tracer/internal/infastructure/db/postgresql"
+	"github.com/financial_tracer/internal/infastructure/mail"
+	"github.com/financial_tracer/internal/lib/mailToken"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSendVerification(t *testing.T) {
+	user := domain.User{Name: "jonn", Email: "jonn@gmail.com"}
+
+	t.Run("success", func(t *testing.T) {
+		var out bytes.Buffer
+		repoMock := new(DbMock)
+		repoMock.On("CreateEmailVerification", mock.Anything, uint(1), mock.Anything, mock.Anything).Return(user, nil)
+
+		opt := Options{URL: "https://tracker.local/verify"}
+		server := CreateVerificationServer(repoMock, mail.NewFileMailer(&out, "noreply@tracker.local"), opt, logrus.New())
+
+		start := time.Now()
+		err := server.SendVerification(context.Background(), 1)
+		assert.NoError(t, err)
+
+		assert.Contains(t, out.String(), "To: jonn@gmail.com")
+		token := regexp.MustCompile(`verify\?token=([A-Za-z0-9_-]+)`).FindStringSubmatch(out.String())
+		if assert.Len(t, token, 2) {
+			assert.Equal(t, mailToken.Hash(token[1]), repoMock.Calls[0].Arguments.Get(2))
+		}
+
+		expiresAt := repoMock.Calls[0].Arguments.Get(3).(time.Time)
+		assert.WithinDuration(t, start.Add(DefaultTTL), expiresAt, time.Second)
+	})
+
+	tests := []struct {
+		name    string
+		mockErr error
+		mailErr error
+		wantErr error
+	}{
+		{name: "error already verified", mockErr: postgresql.ErrorVerified, wantErr: ErrVerified},
+		{name: "error not found", mockErr: postgresql.ErrorNotFound, wantErr: ErrNoFound},
+		{name: "error database", mockErr: errors.New("some db error"), wantErr: ErrDatabase},
+		{name: "error mail", mailErr: errors.New("connection refused"), wantErr: ErrMail},
+	}
+
+	for _, ts := range tests {
+		t.Run(ts.name, func(t *testing.T) {
+			repoMock := new(DbMock)
+			repoMock.On("CreateEmailVerification", mock.Anything, uint(1), mock.Anything, mock.Anything).Return(user, ts.mockErr)
+			mailer := new(MailerMock)
+			mailer.On("Send", mock.Anything, mock.Anything).Return(ts.mailErr)
+
+			server := CreateVerificationServer(repoMock, mailer, Options{}, logrus.New())
+			err := server.SendVerification(context.Background(), 1)
+
+			assert.ErrorIs(t, err, ts.wantErr)
+			if ts.mockErr != nil {
+				mailer.AssertNotCalled(t, "Send", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestVerifyEmail(t *testing.T) {
+	type test struct {
+		name         string
+		req          domain.VerifyEmail
+		mockErr      error
+		wantErr      error
+		shouldCallDB bool
+	}
+
+	tests := []test{
+		{
+			name:         "success",
+			req:          domain.VerifyEmail{Token: "token"},
+			shouldCallDB: true,
+		},
+		{
+			name:         "error used or expired token",
+			req:          domain.VerifyEmail{Token: "token"},
+			mockErr:      postgresql.ErrorNotFound,
+			wantErr:      ErrVerifyToken,
+			shouldCallDB: true,
+		},
+		{
+			name:         "error validate",
+			req:          domain.VerifyEmail{},
+			wantErr:      validator.ValidationErrors{},
+			shouldCallDB: false,
+		},
+		{
+			name:         "error database",
+			req:          domain.VerifyEmail{Token: "token"},
+			mockErr:      errors.New("some db error"),
+			wantErr:      ErrDatabase,
+			shouldCallDB: true,
+		},
+	}
+
+	for _, ts := range tests {
+		t.Run(ts.name, func(t *testing.T) {
+			repoMock := new(DbMock)
+			repoMock.On("VerifyEmail", mock.Anything, mailToken.Hash("token")).Return(uint(1), ts.mockErr)
+
+			server := CreateVerificationServer(repoMock, new(MailerMock), Options{}, logrus.New())
+			err := server.VerifyEmail(context.Background(), ts.req)
+
+			if ts.wantErr != nil {
+				var validErr validator.ValidationErrors
+				if errors.As(ts.wantErr, &validErr) {
+					assert.True(t, errors.As(err, &validErr))
+				} else {
+					assert.ErrorIs(t, err, ts.wantErr)
+				}
+			} else {
+				assert.NoError(t, err)
+			}
+
+			if ts.shouldCallDB {
+				repoMock.AssertCalled(t, "VerifyEmail", mock.Anything, mailToken.Hash("token"))
+			} else {
+				repoMock.AssertNotCalled(t, "VerifyEmail", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}